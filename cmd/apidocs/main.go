@@ -0,0 +1,185 @@
+// Command apidocs collects the doc comments, query parameters and JSON
+// bodies of the API handlers into internal/api/handler_docs_gen.go, from
+// which the OpenAPI document is built. Run it through go generate
+// ./internal/api after changing a handler.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const generatedFile = "handler_docs_gen.go"
+
+// docParam finds query parameters named in doc comments, e.g. "?season="
+var docParam = regexp.MustCompile(`\?([a-z_]+)=`)
+
+// handlerDoc is what the generated file records of a handler
+type handlerDoc struct {
+	doc   string
+	query []string
+	body  bool
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the api package")
+	flag.Parse()
+
+	docs, err := handlerDocs(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apidocs: %v\n", err)
+		os.Exit(1)
+	}
+
+	source, err := render(docs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apidocs: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, generatedFile), source, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "apidocs: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handlerDocs describes the exported methods of *Handler: their doc
+// comment, the query parameters they read (r.URL.Query().Get or a "query"
+// variable holding it, plus those named in the comment) and whether they
+// decode a JSON body
+func handlerDocs(dir string) (map[string]handlerDoc, error) {
+	fset := token.NewFileSet()
+	skip := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != generatedFile
+	}
+	pkgs, err := parser.ParseDir(fset, dir, skip, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]handlerDoc)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil || !fn.Name.IsExported() || !handlerMethod(fn) {
+					continue
+				}
+				docs[fn.Name.Name] = describe(fn)
+			}
+		}
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no handler methods found in %s", dir)
+	}
+	return docs, nil
+}
+
+func handlerMethod(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return false
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+	return ok && ident.Name == "Handler"
+}
+
+func describe(fn *ast.FuncDecl) handlerDoc {
+	doc := handlerDoc{doc: strings.TrimSpace(fn.Doc.Text())}
+	params := make(map[string]bool)
+	for _, m := range docParam.FindAllStringSubmatch(doc.doc, -1) {
+		params[m[1]] = true
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch {
+		case sel.Sel.Name == "Get" && len(call.Args) == 1 && queryValues(sel.X):
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if name, err := strconv.Unquote(lit.Value); err == nil {
+					params[name] = true
+				}
+			}
+		case sel.Sel.Name == "NewDecoder" && len(call.Args) == 1 && isIdent(sel.X, "json"):
+			if body, ok := call.Args[0].(*ast.SelectorExpr); ok && body.Sel.Name == "Body" {
+				doc.body = true
+			}
+		}
+		return true
+	})
+
+	for name := range params {
+		doc.query = append(doc.query, name)
+	}
+	sort.Strings(doc.query)
+	return doc
+}
+
+// queryValues reports whether expr is r.URL.Query() or a variable named
+// query holding it
+func queryValues(expr ast.Expr) bool {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "Query"
+	}
+	return isIdent(expr, "query")
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func render(docs map[string]handlerDoc) ([]byte, error) {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by cmd/apidocs; DO NOT EDIT.\n\npackage api\n\n")
+	buf.WriteString("import \"github.com/kmicac/smoothcomp-scraper/internal/openapi\"\n\n")
+	buf.WriteString("// handlerDocs describe the handlers, by method name\n")
+	buf.WriteString("var handlerDocs = map[string]openapi.HandlerDoc{\n")
+	for _, name := range names {
+		doc := docs[name]
+		fmt.Fprintf(&buf, "\t%q: {Doc: %q", name, doc.doc)
+		if len(doc.query) > 0 {
+			buf.WriteString(", Query: []string{")
+			for i, param := range doc.query {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(&buf, "%q", param)
+			}
+			buf.WriteString("}")
+		}
+		if doc.body {
+			buf.WriteString(", Body: true")
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// exportBatch is how many athletes are read and masked at a time
+const exportBatch = 1000
+
+// athleteColumns are the CSV columns of an athlete export
+var athleteColumns = []string{
+	"id", "external_id", "first_name", "last_name", "full_name", "academy_external_id",
+	"nationality", "country_code", "belt_rank", "gender", "age", "birth_year",
+	"total_wins", "total_losses", "profile_url",
+}
+
+func exportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "export",
+		Short:             "Export stored records",
+		PersistentPreRunE: setup(false),
+	}
+	cmd.AddCommand(exportAthletesCommand())
+	return cmd
+}
+
+func exportAthletesCommand() *cobra.Command {
+	var asCSV bool
+	var output, country string
+
+	cmd := &cobra.Command{
+		Use:   "athletes",
+		Short: "Export the stored athletes as CSV or JSON lines, minors masked as in the API",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			out := cmd.OutOrStdout()
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if closeErr := file.Close(); err == nil {
+						err = closeErr
+					}
+				}()
+				out = file
+			}
+
+			query := config.GetDB().Model(&models.Athlete{}).Order("id")
+			if country != "" {
+				query = query.Where("country_code = ?", strings.ToUpper(country))
+			}
+
+			write := writeAthletesJSON
+			if asCSV {
+				write = writeAthletesCSV
+			}
+			count, err := write(out, query, privacy.NewPolicy(cfg))
+			if err != nil {
+				return err
+			}
+			if output != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d athletes to %s\n", count, output)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asCSV, "csv", false, "write CSV instead of JSON lines")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write (default: stdout)")
+	cmd.Flags().StringVar(&country, "country", "", "only athletes of this country code")
+	return cmd
+}
+
+// eachAthlete calls fn with the athletes of query in batches, minors masked
+func eachAthlete(query *gorm.DB, policy *privacy.Policy, fn func([]models.Athlete) error) error {
+	var batch []models.Athlete
+	return query.FindInBatches(&batch, exportBatch, func(tx *gorm.DB, _ int) error {
+		policy.MaskAthletes(batch)
+		return fn(batch)
+	}).Error
+}
+
+func writeAthletesCSV(out io.Writer, query *gorm.DB, policy *privacy.Policy) (int, error) {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(athleteColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := eachAthlete(query, policy, func(athletes []models.Athlete) error {
+		for _, a := range athletes {
+			if err := writer.Write([]string{
+				strconv.FormatUint(uint64(a.ID), 10), a.ExternalID, a.FirstName, a.LastName, a.FullName,
+				a.AcademyExternalID, a.Nationality, a.CountryCode, a.BeltRank, string(a.Gender),
+				strconv.Itoa(a.Age), strconv.Itoa(a.BirthYear),
+				strconv.Itoa(a.TotalWins), strconv.Itoa(a.TotalLosses), a.ProfileURL,
+			}); err != nil {
+				return err
+			}
+			count++
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	return count, err
+}
+
+func writeAthletesJSON(out io.Writer, query *gorm.DB, policy *privacy.Policy) (int, error) {
+	encoder := json.NewEncoder(out)
+	count := 0
+	err := eachAthlete(query, policy, func(athletes []models.Athlete) error {
+		for i := range athletes {
+			if err := encoder.Encode(&athletes[i]); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,84 @@
+// Command scraper runs one-shot scrapes and exports against the configured
+// database without starting the HTTP server, for backfills and cron jobs.
+// It reads the same environment as the server.
+//
+//	scraper scrape event 12345
+//	scraper scrape profile 67890
+//	scraper scrape events --country BR --type past --depth participants
+//	scraper export athletes --csv --output athletes.csv
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// cfg is loaded before any subcommand runs
+var cfg *config.Config
+
+// dbOpen tells main to close the database on exit
+var dbOpen bool
+
+func main() {
+	root := &cobra.Command{
+		Use:           "scraper",
+		Short:         "One-shot Smoothcomp scrapes and exports",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(scrapeCommand(), exportCommand())
+
+	// Ctrl-C cancels the running scrape, which records its job as cancelled
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := root.ExecuteContext(ctx)
+	stop()
+	if dbOpen {
+		scraper.CloseWrites()
+		_ = config.CloseDatabase()
+		logger.Sync()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// setup loads the configuration and opens the database; it runs before the
+// scrape and export subcommands, not before help or completion. Commands
+// that stay offline skip the SMOOTHCOMP_BASE_URL probe.
+func setup(online bool) func(*cobra.Command, []string) error {
+	return func(*cobra.Command, []string) error {
+		return open(online)
+	}
+}
+
+func open(online bool) error {
+	var err error
+	if cfg, err = config.LoadConfig(); err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if !online {
+		cfg.Server.StartupReachabilityCheck = false
+	}
+	if err := logger.InitLogger(cfg.Logging.Level); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration check failed: %w", err)
+	}
+	if err := config.InitDatabase(cfg.Database); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	dbOpen = true
+	logger.Debug("Database initialized", zap.String("driver", cfg.Database.Driver))
+	return nil
+}
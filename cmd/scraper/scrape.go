@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/spf13/cobra"
+)
+
+// countryPattern matches the ISO 3166-1 alpha-2 codes Smoothcomp filters on
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+func scrapeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "scrape",
+		Short:             "Scrape Smoothcomp into the database",
+		PersistentPreRunE: setup(true),
+	}
+	cmd.AddCommand(scrapeEventCommand(), scrapeProfileCommand(), scrapeEventsCommand())
+	return cmd
+}
+
+func scrapeEventCommand() *cobra.Command {
+	var name, url string
+	var brackets, results bool
+
+	cmd := &cobra.Command{
+		Use:   "event <id>",
+		Short: "Scrape the participants, brackets and results of one event",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			eventID := args[0]
+			if scraper.IsBlocked(models.BlockedEvent, eventID) {
+				return fmt.Errorf("event %s is blocklisted", eventID)
+			}
+
+			// The stored event fills in what was not given
+			var event models.Event
+			config.GetDB().Where("external_id = ?", eventID).Limit(1).Find(&event)
+			if name == "" {
+				name = event.Name
+			}
+			if name == "" {
+				name = "Event " + eventID
+			}
+			if url == "" {
+				url = event.EventURL
+			}
+
+			ctx := cmd.Context()
+			s := scraper.NewScraper(cfg)
+			if err := s.ScrapeEventAthletes(ctx, eventID, name, url); err != nil {
+				return fmt.Errorf("participants: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Scraped participants of event %s\n", eventID)
+
+			if brackets {
+				count, err := s.ScrapeEventBrackets(ctx, eventID, url)
+				if err != nil {
+					return fmt.Errorf("brackets: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Scraped %d bracket matches\n", count)
+			}
+			if results {
+				count, err := s.ScrapeEventResults(ctx, eventID)
+				if err != nil {
+					return fmt.Errorf("results: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Scraped %d results\n", count)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "event name (default: the stored one)")
+	cmd.Flags().StringVar(&url, "url", "", "event URL, for events on their own subdomain (default: the stored one)")
+	cmd.Flags().BoolVar(&brackets, "brackets", true, "also scrape the brackets")
+	cmd.Flags().BoolVar(&results, "results", true, "also scrape the results")
+	return cmd
+}
+
+func scrapeProfileCommand() *cobra.Command {
+	var url string
+
+	cmd := &cobra.Command{
+		Use:   "profile <id>",
+		Short: "Scrape one athlete profile and its stats",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			athleteID := args[0]
+			if scraper.IsBlocked(models.BlockedAthlete, athleteID) {
+				return fmt.Errorf("athlete %s is blocklisted", athleteID)
+			}
+			if err := scraper.NewScraper(cfg).ScrapeAthleteProfile(cmd.Context(), athleteID, url); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Scraped profile of athlete %s\n", athleteID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&url, "url", "", "profile URL (default: smoothcomp.com/en/profile/<id>)")
+	return cmd
+}
+
+func scrapeEventsCommand() *cobra.Command {
+	var country, eventType, depthName string
+	var maxDuration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Scrape the past or upcoming events of a country",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			country = strings.ToUpper(strings.TrimSpace(country))
+			if !countryPattern.MatchString(country) {
+				return fmt.Errorf("invalid --country %q, expected a 2-letter code", country)
+			}
+			if eventType != "past" && eventType != "upcoming" {
+				return fmt.Errorf("invalid --type %q, expected past or upcoming", eventType)
+			}
+			depth, err := scraper.ParseDepth(depthName)
+			if err != nil {
+				return err
+			}
+
+			opts := scraper.RunOptions{MaxDuration: maxDuration}
+			if err := scraper.NewScraper(cfg).ScrapeEvents(cmd.Context(), eventType, country, depth, opts); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Scraped %s events of %s to depth %s\n", eventType, country, depth)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&country, "country", "AR", "country code")
+	cmd.Flags().StringVar(&eventType, "type", "past", "past or upcoming")
+	cmd.Flags().StringVar(&depthName, "depth", "listing", "listing, details, participants, profiles or brackets")
+	cmd.Flags().DurationVar(&maxDuration, "max-duration", 0, "stop between events after this long, e.g. 30m (default: no limit)")
+	return cmd
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +11,18 @@ import (
 	"time"
 
 	"github.com/kmicac/smoothcomp-scraper/internal/api"
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/fixtures"
+	"github.com/kmicac/smoothcomp-scraper/internal/grpcserver"
+	"github.com/kmicac/smoothcomp-scraper/internal/live"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
 	"github.com/kmicac/smoothcomp-scraper/internal/scheduler"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 const Version = "1.0.0"
@@ -38,25 +47,100 @@ func main() {
 		zap.String("environment", cfg.Server.Environment),
 	)
 
+	// Fail fast on configuration mistakes
+	if err := cfg.Validate(); err != nil {
+		logger.Fatal("Configuration check failed", zap.Error(err))
+	}
+
 	// Initialize database
-	if err := config.InitDatabase(cfg.Database.CachePath); err != nil {
+	if err := config.InitDatabase(cfg.Database); err != nil {
 		logger.Fatal("Failed to initialize database", zap.Error(err))
 	}
 	defer config.CloseDatabase()
 
 	logger.Info("Database initialized successfully")
 
+	// Latency tracking (route timings and slow query plans)
+	latencyTracker := metrics.Init(cfg)
+	if err := metrics.InstrumentDB(config.GetDB(), latencyTracker); err != nil {
+		logger.Error("Failed to instrument database", zap.Error(err))
+	}
+	stopMetrics := make(chan struct{})
+	metricsDone := make(chan struct{})
+	go func() {
+		latencyTracker.Run(5*time.Minute, stopMetrics)
+		close(metricsDone)
+	}()
+
+	if err := scraper.CanonicalizeStoredURLs(); err != nil {
+		logger.Error("Failed to canonicalize stored URLs", zap.Error(err))
+	}
+	if err := scraper.BackfillSlugs(); err != nil {
+		logger.Error("Failed to backfill slugs", zap.Error(err))
+	}
+	if err := scraper.MergeDuplicateAcademies(); err != nil {
+		logger.Error("Failed to merge duplicate academies", zap.Error(err))
+	}
+	if err := config.RunOnce("dedupe_registrations_by_division", scraper.DedupeRegistrations); err != nil {
+		logger.Error("Failed to dedupe registrations", zap.Error(err))
+	}
+	if err := scraper.NormalizeStoredGenders(); err != nil {
+		logger.Error("Failed to normalize stored genders", zap.Error(err))
+	}
+	if err := scraper.NormalizeStoredWeightClasses(); err != nil {
+		logger.Error("Failed to normalize stored weight classes", zap.Error(err))
+	}
+	if err := scraper.RederiveAthleteNames(); err != nil {
+		logger.Error("Failed to re-derive athlete names", zap.Error(err))
+	}
+	if err := scraper.RedactAcademyContacts(cfg); err != nil {
+		logger.Error("Failed to redact academy contacts", zap.Error(err))
+	}
+	if err := scraper.PruneBracketArchive(cfg.Scraper.BracketArchiveRetention); err != nil {
+		logger.Error("Failed to prune bracket archive", zap.Error(err))
+	}
+
+	// Start fixture server (simulation mode)
+	var fixtureServer *http.Server
+	if cfg.Fixtures.Enabled {
+		fixtureServer = &http.Server{
+			Addr:    "127.0.0.1:" + cfg.Fixtures.Port,
+			Handler: fixtures.NewServer(cfg.Fixtures.Dir),
+		}
+		go func() {
+			logger.Info("Fixture server listening",
+				zap.String("port", cfg.Fixtures.Port),
+				zap.String("dir", cfg.Fixtures.Dir))
+			if err := fixtureServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to start fixture server", zap.Error(err))
+			}
+		}()
+	}
+	if cfg.Scraper.TestBaseURL != "" {
+		logger.Warn("Outbound smoothcomp.com requests redirected",
+			zap.String("test_base_url", cfg.Scraper.TestBaseURL))
+	}
+
 	// Initialize scheduler
 	cronScheduler := scheduler.NewScheduler(cfg)
 	if cfg.Scheduler.Enabled {
 		if err := cronScheduler.Start(); err != nil {
 			logger.Fatal("Failed to start scheduler", zap.Error(err))
 		}
-		logger.Info("Scheduler started", zap.String("cron", cfg.Scheduler.CronExpression))
 	}
 
+	// Queue of scrapes requested through the API
+	jobQueue := queue.New(cfg)
+	if err := jobQueue.Start(); err != nil {
+		logger.Fatal("Failed to start job queue", zap.Error(err))
+	}
+
+	// Live scoreboard ingestion (optional, see LIVE_STREAM_URL)
+	liveIngestor := live.NewIngestor(cfg)
+	liveIngestor.StartConfigured()
+
 	// Initialize HTTP router
-	router := api.NewRouter(cfg, cronScheduler)
+	router := api.NewRouter(cfg, cronScheduler, jobQueue, liveIngestor)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -66,6 +150,8 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Job update streams stay open until the bus closes
+	server.RegisterOnShutdown(bus.Default().Close)
 
 	// Start server in a goroutine
 	go func() {
@@ -75,6 +161,22 @@ func main() {
 		}
 	}()
 
+	// gRPC server alongside HTTP (optional, see GRPC_PORT)
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+		grpcServer = grpcserver.New(cfg, jobQueue)
+		go func() {
+			logger.Info("gRPC server listening", zap.String("port", cfg.Server.GRPCPort))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -84,14 +186,39 @@ func main() {
 
 	// Stop scheduler
 	cronScheduler.Stop()
+	liveIngestor.StopAll()
+
+	close(stopMetrics)
+	<-metricsDone
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Put running queued jobs back on the queue, then stop the remaining
+	// scrape jobs so they record their status before exit
+	jobQueue.Stop(ctx)
+	scraper.CancelJobs(ctx)
+	scraper.CloseWrites()
+
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+	if fixtureServer != nil {
+		_ = fixtureServer.Shutdown(ctx)
+	}
 
 	logger.Info("Server stopped gracefully")
 }
@@ -0,0 +1,161 @@
+// Package academystats aggregates the statistics of academies from our own
+// data instead of the club page: wins and losses of their athletes and the
+// podium medals those athletes won at stored events.
+package academystats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recomputeMu keeps concurrent recomputes from writing stale totals
+var recomputeMu sync.Mutex
+
+// Summary describes a recompute
+type Summary struct {
+	Academies  int       `json:"academies"` // academies whose statistics were written
+	Athletes   int64     `json:"athletes"`
+	Medals     int64     `json:"medals"`
+	ComputedAt time.Time `json:"computed_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// stats are the aggregated statistics of one academy
+type stats struct {
+	wins, losses, athletes int
+	gold, silver, bronze   int
+}
+
+// Recompute aggregates the statistics of the academies with the given
+// external IDs, or of every academy when none are given, and stores them.
+// A medal counts for the academy listed on the results page, or for the
+// academy of the athlete when the page lists none.
+func Recompute(academyIDs ...string) (*Summary, error) {
+	recomputeMu.Lock()
+	defer recomputeMu.Unlock()
+
+	started := time.Now()
+	db := config.GetDB()
+	summary := &Summary{ComputedAt: started}
+
+	scope := func(query *gorm.DB, column string) *gorm.DB {
+		if len(academyIDs) > 0 {
+			return query.Where(column+" IN ?", academyIDs)
+		}
+		return query
+	}
+
+	var academies []models.Academy
+	if err := scope(db.Select("id", "external_id"), "external_id").Find(&academies).Error; err != nil {
+		return nil, fmt.Errorf("error loading academies: %w", err)
+	}
+	if len(academies) == 0 {
+		return summary, nil
+	}
+	byAcademy := make(map[string]*stats, len(academies))
+	for _, academy := range academies {
+		byAcademy[academy.ExternalID] = &stats{}
+	}
+
+	var athleteRows []struct {
+		AcademyExternalID string
+		Wins              int
+		Losses            int
+		Athletes          int
+	}
+	err := scope(db.Model(&models.Athlete{}).
+		Select("academy_external_id, SUM(total_wins) AS wins, SUM(total_losses) AS losses, COUNT(*) AS athletes").
+		Where("academy_external_id <> ''"), "academy_external_id").
+		Group("academy_external_id").
+		Scan(&athleteRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating athletes: %w", err)
+	}
+	for _, row := range athleteRows {
+		if s := byAcademy[row.AcademyExternalID]; s != nil {
+			s.wins, s.losses, s.athletes = row.Wins, row.Losses, row.Athletes
+			summary.Athletes += int64(row.Athletes)
+		}
+	}
+
+	const medalAcademy = "COALESCE(NULLIF(event_results.academy_external_id, ''), athletes.academy_external_id)"
+	var medalRows []struct {
+		Academy string
+		Medal   string
+		Medals  int
+	}
+	err = scope(db.Model(&models.EventResult{}).
+		Select(medalAcademy+" AS academy, event_results.medal, COUNT(*) AS medals").
+		Joins("LEFT JOIN athletes ON athletes.id = event_results.athlete_id").
+		Where("event_results.medal <> ''"), medalAcademy).
+		Group(medalAcademy + ", event_results.medal").
+		Scan(&medalRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating medals: %w", err)
+	}
+	for _, row := range medalRows {
+		s := byAcademy[row.Academy]
+		if s == nil {
+			continue
+		}
+		switch row.Medal {
+		case models.MedalGold:
+			s.gold = row.Medals
+		case models.MedalSilver:
+			s.silver = row.Medals
+		case models.MedalBronze:
+			s.bronze = row.Medals
+		}
+		summary.Medals += int64(row.Medals)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, academy := range academies {
+			s := byAcademy[academy.ExternalID]
+			err := tx.Model(&models.Academy{}).Where("id = ?", academy.ID).UpdateColumns(map[string]interface{}{
+				"total_wins":        s.wins,
+				"total_losses":      s.losses,
+				"athlete_count":     s.athletes,
+				"gold_medals":       s.gold,
+				"silver_medals":     s.silver,
+				"bronze_medals":     s.bronze,
+				"stats_computed_at": started,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("error saving statistics of academy %s: %w", academy.ExternalID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Academies = len(academies)
+	summary.DurationMs = time.Since(started).Milliseconds()
+	logger.Info("Academy statistics recomputed",
+		zap.Int("academies", summary.Academies),
+		zap.Int64("athletes", summary.Athletes),
+		zap.Int64("medals", summary.Medals))
+	return summary, nil
+}
+
+// EventAcademies returns the academies credited with the results of an
+// event, the ones a recompute after scraping its results has to refresh
+func EventAcademies(eventID string) ([]string, error) {
+	var academyIDs []string
+	err := config.GetDB().Model(&models.EventResult{}).
+		Joins("LEFT JOIN athletes ON athletes.id = event_results.athlete_id").
+		Where("event_results.event_id = ?", eventID).
+		Where("COALESCE(NULLIF(event_results.academy_external_id, ''), athletes.academy_external_id) <> ''").
+		Distinct().
+		Pluck("COALESCE(NULLIF(event_results.academy_external_id, ''), athletes.academy_external_id)", &academyIDs).Error
+	return academyIDs, err
+}
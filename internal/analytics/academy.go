@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// MethodBreakdown counts decided matches by how they ended
+type MethodBreakdown struct {
+	Submission int `json:"submission"`
+	Points     int `json:"points"`
+	Decision   int `json:"decision"`
+	DQ         int `json:"dq"`
+	Other      int `json:"other"`
+}
+
+// TechniqueCount is how often a finishing technique was used
+type TechniqueCount struct {
+	Technique string `json:"technique"`
+	Count     int    `json:"count"`
+}
+
+// AcademyMatchStats aggregates the stored matches of an academy's athletes
+type AcademyMatchStats struct {
+	AcademyExternalID string  `json:"academy_external_id"`
+	Athletes          int     `json:"athletes"`
+	Matches           int     `json:"matches"`
+	Wins              int     `json:"wins"`
+	Losses            int     `json:"losses"`
+	WinRate           float64 `json:"win_rate"`
+
+	// SubmissionRate is the share of wins finished by submission;
+	// SubmittedRate the share of losses where the athlete was submitted
+	SubmissionRate float64 `json:"submission_rate"`
+	SubmittedRate  float64 `json:"submitted_rate"`
+
+	WinsBy   MethodBreakdown `json:"wins_by"`
+	LossesBy MethodBreakdown `json:"losses_by"`
+
+	TopFinishes []TechniqueCount `json:"top_finishes"` // techniques used to win
+	TopConceded []TechniqueCount `json:"top_conceded"` // techniques athletes were caught with
+}
+
+const topTechniques = 5
+
+// AcademyStats computes match statistics for an academy. Matches between two
+// athletes of the same academy count once for each side; undecided matches
+// (no winner recorded) are skipped.
+func AcademyStats(academyExternalID string) (*AcademyMatchStats, error) {
+	db := config.GetDB()
+	stats := &AcademyMatchStats{AcademyExternalID: academyExternalID}
+
+	var athleteIDs []uint
+	if err := db.Model(&models.Athlete{}).
+		Where("academy_external_id = ?", academyExternalID).
+		Pluck("id", &athleteIDs).Error; err != nil {
+		return nil, err
+	}
+	stats.Athletes = len(athleteIDs)
+	if len(athleteIDs) == 0 {
+		return stats, nil
+	}
+
+	members := make(map[uint]bool, len(athleteIDs))
+	for _, id := range athleteIDs {
+		members[id] = true
+	}
+
+	var matches []models.Match
+	if err := db.Where("winner_id <> 0 AND (athlete_a_id IN ? OR athlete_b_id IN ?)", athleteIDs, athleteIDs).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	finishes := make(map[string]int)
+	conceded := make(map[string]int)
+
+	for _, match := range matches {
+		for _, athleteID := range []uint{match.AthleteAID, match.AthleteBID} {
+			if athleteID == 0 || !members[athleteID] {
+				continue
+			}
+
+			stats.Matches++
+			technique := strings.TrimSpace(match.Technique)
+			if match.WinnerID == athleteID {
+				stats.Wins++
+				stats.WinsBy.add(match.Method)
+				if match.Method == "submission" && technique != "" {
+					finishes[technique]++
+				}
+			} else {
+				stats.Losses++
+				stats.LossesBy.add(match.Method)
+				if match.Method == "submission" && technique != "" {
+					conceded[technique]++
+				}
+			}
+		}
+	}
+
+	stats.WinRate = ratio(stats.Wins, stats.Matches)
+	stats.SubmissionRate = ratio(stats.WinsBy.Submission, stats.Wins)
+	stats.SubmittedRate = ratio(stats.LossesBy.Submission, stats.Losses)
+	stats.TopFinishes = topCounts(finishes, topTechniques)
+	stats.TopConceded = topCounts(conceded, topTechniques)
+
+	return stats, nil
+}
+
+func (b *MethodBreakdown) add(method string) {
+	switch method {
+	case "submission":
+		b.Submission++
+	case "points":
+		b.Points++
+	case "decision":
+		b.Decision++
+	case "dq":
+		b.DQ++
+	default:
+		b.Other++
+	}
+}
+
+// ratio returns part/total rounded to 3 decimals, 0 when total is 0
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(int(float64(part)/float64(total)*1000+0.5)) / 1000
+}
+
+func topCounts(counts map[string]int, limit int) []TechniqueCount {
+	result := make([]TechniqueCount, 0, len(counts))
+	for technique, count := range counts {
+		result = append(result, TechniqueCount{Technique: technique, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Technique < result[j].Technique
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -0,0 +1,262 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// MaxCompared is the most athletes a comparison accepts
+const MaxCompared = 5
+
+// ComparedAthlete holds the stats of one athlete in a comparison
+type ComparedAthlete struct {
+	ID                int    `json:"id"`
+	ExternalID        string `json:"external_id"`
+	FullName          string `json:"full_name"`
+	Slug              string `json:"slug"`
+	AcademyExternalID string `json:"academy_external_id"`
+	AffiliationName   string `json:"affiliation_name"`
+	CountryCode       string `json:"country_code"`
+	BeltRank          string `json:"belt_rank"`
+
+	// Record from the Smoothcomp profile
+	Wins           int             `json:"wins"`
+	Losses         int             `json:"losses"`
+	WinRate        float64         `json:"win_rate"`
+	SubmissionRate float64         `json:"submission_rate"`
+	SubmittedRate  float64         `json:"submitted_rate"`
+	WinsBy         MethodBreakdown `json:"wins_by"`
+	LossesBy       MethodBreakdown `json:"losses_by"`
+
+	Events int `json:"events"` // events registered in
+}
+
+// HeadToHeadRecord is one athlete's result against an opponent; matches
+// without a recorded winner count only in Matches
+type HeadToHeadRecord struct {
+	Matches int `json:"matches"`
+	Wins    int `json:"wins"`
+	Losses  int `json:"losses"`
+}
+
+// CommonOpponent is someone faced by at least two of the compared athletes.
+// Records are aligned with AthleteComparison.Athletes.
+type CommonOpponent struct {
+	OpponentID uint               `json:"opponent_id,omitempty"`
+	Name       string             `json:"name"`
+	Records    []HeadToHeadRecord `json:"records"`
+}
+
+// SharedEvent is an event at least two of the compared athletes registered
+// for. Divisions are aligned with AthleteComparison.Athletes; empty when the
+// athlete did not compete.
+type SharedEvent struct {
+	EventID   string   `json:"event_id"`
+	EventName string   `json:"event_name"`
+	Divisions []string `json:"divisions"`
+}
+
+// AthleteComparison lines up the stats of several athletes
+type AthleteComparison struct {
+	Athletes        []ComparedAthlete `json:"athletes"`
+	HeadToHead      []models.Match    `json:"head_to_head"` // matches between compared athletes
+	CommonOpponents []CommonOpponent  `json:"common_opponents"`
+	SharedEvents    []SharedEvent     `json:"shared_events"`
+}
+
+// CompareAthletes builds a comparison keeping the order of athletes
+func CompareAthletes(athletes []models.Athlete) (*AthleteComparison, error) {
+	db := config.GetDB()
+
+	index := make(map[uint]int, len(athletes))
+	ids := make([]uint, 0, len(athletes))
+	result := &AthleteComparison{
+		Athletes:        make([]ComparedAthlete, 0, len(athletes)),
+		HeadToHead:      []models.Match{},
+		CommonOpponents: []CommonOpponent{},
+		SharedEvents:    []SharedEvent{},
+	}
+	for i, athlete := range athletes {
+		index[uint(athlete.ID)] = i
+		ids = append(ids, uint(athlete.ID))
+		result.Athletes = append(result.Athletes, comparedAthlete(athlete))
+	}
+
+	var registrations []models.EventRegistration
+	if err := db.Where("athlete_id IN ?", ids).Order("event_id, id").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	result.SharedEvents = sharedEvents(registrations, index, len(athletes))
+	for i := range result.Athletes {
+		events := map[string]bool{}
+		for _, reg := range registrations {
+			if index[reg.AthleteID] == i {
+				events[reg.EventID] = true
+			}
+		}
+		result.Athletes[i].Events = len(events)
+	}
+
+	var matches []models.Match
+	if err := db.Where("athlete_a_id IN ? OR athlete_b_id IN ?", ids, ids).Order("id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	type opponentKey struct {
+		id   uint
+		name string
+	}
+	opponents := map[opponentKey]*CommonOpponent{}
+	var order []opponentKey
+
+	for _, match := range matches {
+		_, aCompared := index[match.AthleteAID]
+		_, bCompared := index[match.AthleteBID]
+		if aCompared && bCompared && match.AthleteAID != 0 && match.AthleteBID != 0 {
+			result.HeadToHead = append(result.HeadToHead, match)
+			continue
+		}
+
+		athleteID, opponentID, opponentName := match.AthleteAID, match.AthleteBID, match.AthleteBName
+		if !aCompared {
+			athleteID, opponentID, opponentName = match.AthleteBID, match.AthleteAID, match.AthleteAName
+		}
+
+		key := opponentKey{id: opponentID}
+		if opponentID == 0 {
+			key.name = strings.ToLower(strings.TrimSpace(opponentName))
+			if key.name == "" {
+				continue
+			}
+		}
+		opponent, ok := opponents[key]
+		if !ok {
+			opponent = &CommonOpponent{
+				OpponentID: opponentID,
+				Name:       opponentName,
+				Records:    make([]HeadToHeadRecord, len(athletes)),
+			}
+			opponents[key] = opponent
+			order = append(order, key)
+		}
+
+		record := &opponent.Records[index[athleteID]]
+		record.Matches++
+		switch match.WinnerID {
+		case 0:
+		case athleteID:
+			record.Wins++
+		default:
+			record.Losses++
+		}
+	}
+
+	for _, key := range order {
+		opponent := opponents[key]
+		faced := 0
+		for _, record := range opponent.Records {
+			if record.Matches > 0 {
+				faced++
+			}
+		}
+		if faced >= 2 {
+			result.CommonOpponents = append(result.CommonOpponents, *opponent)
+		}
+	}
+	sort.SliceStable(result.CommonOpponents, func(i, j int) bool {
+		return result.CommonOpponents[i].Name < result.CommonOpponents[j].Name
+	})
+
+	return result, nil
+}
+
+func comparedAthlete(athlete models.Athlete) ComparedAthlete {
+	compared := ComparedAthlete{
+		ID:                athlete.ID,
+		ExternalID:        athlete.ExternalID,
+		FullName:          athlete.FullName,
+		Slug:              athlete.Slug,
+		AcademyExternalID: athlete.AcademyExternalID,
+		AffiliationName:   athlete.AffiliationName,
+		CountryCode:       athlete.CountryCode,
+		BeltRank:          athlete.BeltRank,
+		Wins:              athlete.TotalWins,
+		Losses:            athlete.TotalLosses,
+		WinsBy: MethodBreakdown{
+			Submission: athlete.WinsBySubmission,
+			Points:     athlete.WinsByPoints,
+			Decision:   athlete.WinsByDecision,
+			DQ:         athlete.WinsByDQ,
+		},
+		LossesBy: MethodBreakdown{
+			Submission: athlete.LossesBySubmission,
+			Points:     athlete.LossesByPoints,
+			Decision:   athlete.LossesByDecision,
+			DQ:         athlete.LossesByDQ,
+		},
+	}
+	compared.WinsBy.Other = max(0, compared.Wins-compared.WinsBy.total())
+	compared.LossesBy.Other = max(0, compared.Losses-compared.LossesBy.total())
+
+	compared.WinRate = ratio(compared.Wins, compared.Wins+compared.Losses)
+	compared.SubmissionRate = ratio(compared.WinsBy.Submission, compared.Wins)
+	compared.SubmittedRate = ratio(compared.LossesBy.Submission, compared.Losses)
+	return compared
+}
+
+func sharedEvents(registrations []models.EventRegistration, index map[uint]int, athletes int) []SharedEvent {
+	events := map[string]*SharedEvent{}
+	var order []string
+
+	for _, reg := range registrations {
+		event, ok := events[reg.EventID]
+		if !ok {
+			event = &SharedEvent{
+				EventID:   reg.EventID,
+				EventName: reg.EventName,
+				Divisions: make([]string, athletes),
+			}
+			events[reg.EventID] = event
+			order = append(order, reg.EventID)
+		}
+		i := index[reg.AthleteID]
+		if event.Divisions[i] == "" {
+			event.Divisions[i] = divisionLabel(reg)
+		}
+	}
+
+	shared := []SharedEvent{}
+	for _, id := range order {
+		event := events[id]
+		attended := 0
+		for _, division := range event.Divisions {
+			if division != "" {
+				attended++
+			}
+		}
+		if attended >= 2 {
+			shared = append(shared, *event)
+		}
+	}
+	return shared
+}
+
+func divisionLabel(reg models.EventRegistration) string {
+	parts := []string{}
+	for _, part := range []string{reg.Division, reg.AgeCategory, reg.Rank, reg.WeightClass} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " / ")
+}
+
+func (b MethodBreakdown) total() int {
+	return b.Submission + b.Points + b.Decision + b.DQ + b.Other
+}
@@ -0,0 +1,192 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"gorm.io/gorm"
+)
+
+// KidsLeaderboardFilter narrows a kids leaderboard to one age group, belt
+// and/or gender; empty fields match everything
+type KidsLeaderboardFilter struct {
+	AgeGroup string
+	Belt     string
+	Gender   models.Gender
+	Limit    int
+
+	// InitialsOnly hides the names, slugs and pictures of minors
+	InitialsOnly bool
+}
+
+// LeaderboardEntry is an athlete's standing in a leaderboard
+type LeaderboardEntry struct {
+	Rank              int     `json:"rank"`
+	AthleteID         uint    `json:"athlete_id"`
+	Name              string  `json:"name"`
+	Slug              string  `json:"slug,omitempty"`
+	ImageURL          string  `json:"image_url,omitempty"`
+	AcademyExternalID string  `json:"academy_external_id,omitempty"`
+	AgeGroup          string  `json:"age_group"` // as of the latest registration
+	Belt              string  `json:"belt"`
+	Events            int     `json:"events"`
+	Matches           int     `json:"matches"`
+	Wins              int     `json:"wins"`
+	Losses            int     `json:"losses"`
+	Submissions       int     `json:"submissions"`
+	WinRate           float64 `json:"win_rate"`
+}
+
+// KidsLeaderboard ranks athletes of kids divisions by their results there
+type KidsLeaderboard struct {
+	AgeGroup     string             `json:"age_group,omitempty"`
+	Belt         string             `json:"belt,omitempty"`
+	Gender       models.Gender      `json:"gender,omitempty"`
+	InitialsOnly bool               `json:"initials_only"`
+	AgeGroups    []string           `json:"age_groups"` // filter values available
+	Belts        []string           `json:"belts"`
+	Entries      []LeaderboardEntry `json:"entries"`
+}
+
+// KidsRanking builds the leaderboard of kids divisions from registrations
+// (events entered) and stored matches of those events. Athletes are ranked
+// by wins, then win rate, then events entered.
+func KidsRanking(filter KidsLeaderboardFilter) (*KidsLeaderboard, error) {
+	db := config.GetDB()
+	board := &KidsLeaderboard{
+		AgeGroup:     filter.AgeGroup,
+		Belt:         filter.Belt,
+		Gender:       filter.Gender,
+		InitialsOnly: filter.InitialsOnly,
+		Entries:      []LeaderboardEntry{},
+	}
+
+	kids := db.Model(&models.EventRegistration{}).Where("is_kids = ?", true)
+	if err := kids.Session(&gorm.Session{}).Distinct().Where("age_category <> ''").
+		Order("age_category").Pluck("age_category", &board.AgeGroups).Error; err != nil {
+		return nil, err
+	}
+	if err := kids.Session(&gorm.Session{}).Distinct().Where("rank <> ''").
+		Order("rank").Pluck("rank", &board.Belts).Error; err != nil {
+		return nil, err
+	}
+
+	query := kids.Session(&gorm.Session{})
+	if filter.AgeGroup != "" {
+		query = query.Where("LOWER(age_category) = ?", strings.ToLower(filter.AgeGroup))
+	}
+	if filter.Belt != "" {
+		query = query.Where("LOWER(rank) = ?", strings.ToLower(filter.Belt))
+	}
+	if filter.Gender != models.GenderUnknown {
+		query = query.Where("gender = ?", filter.Gender)
+	}
+
+	var registrations []models.EventRegistration
+	if err := query.Order("registration_date ASC, id ASC").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	if len(registrations) == 0 {
+		return board, nil
+	}
+
+	type standing struct {
+		entry  LeaderboardEntry
+		events map[string]bool
+	}
+	standings := make(map[uint]*standing)
+	eventIDs := make(map[string]bool)
+	for _, reg := range registrations {
+		s := standings[reg.AthleteID]
+		if s == nil {
+			s = &standing{entry: LeaderboardEntry{AthleteID: reg.AthleteID}, events: make(map[string]bool)}
+			standings[reg.AthleteID] = s
+		}
+		s.events[reg.EventID] = true
+		s.entry.AgeGroup = reg.AgeCategory
+		s.entry.Belt = reg.Rank
+		eventIDs[reg.EventID] = true
+	}
+
+	athleteIDs := make([]uint, 0, len(standings))
+	for id := range standings {
+		athleteIDs = append(athleteIDs, id)
+	}
+	events := make([]string, 0, len(eventIDs))
+	for id := range eventIDs {
+		events = append(events, id)
+	}
+
+	var matches []models.Match
+	if err := db.Where("winner_id <> 0 AND event_id IN ?", events).
+		Where("athlete_a_id IN ? OR athlete_b_id IN ?", athleteIDs, athleteIDs).
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		for _, athleteID := range []uint{match.AthleteAID, match.AthleteBID} {
+			s := standings[athleteID]
+			if athleteID == 0 || s == nil || !s.events[match.EventID] {
+				continue
+			}
+			s.entry.Matches++
+			if match.WinnerID == athleteID {
+				s.entry.Wins++
+				if match.Method == "submission" {
+					s.entry.Submissions++
+				}
+			} else {
+				s.entry.Losses++
+			}
+		}
+	}
+
+	var athletes []models.Athlete
+	if err := db.Where("id IN ?", athleteIDs).Find(&athletes).Error; err != nil {
+		return nil, err
+	}
+	for _, athlete := range athletes {
+		s := standings[uint(athlete.ID)]
+		s.entry.AcademyExternalID = athlete.AcademyExternalID
+		if filter.InitialsOnly {
+			s.entry.Name = privacy.Initials(athlete.FirstName + " " + athlete.LastName)
+			if s.entry.Name == "" {
+				s.entry.Name = privacy.Initials(athlete.FullName)
+			}
+			continue
+		}
+		s.entry.Name = athlete.FullName
+		s.entry.Slug = athlete.Slug
+		s.entry.ImageURL = athlete.ImageURL
+	}
+
+	for _, s := range standings {
+		s.entry.Events = len(s.events)
+		s.entry.WinRate = ratio(s.entry.Wins, s.entry.Matches)
+		board.Entries = append(board.Entries, s.entry)
+	}
+	sort.Slice(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		if a.Events != b.Events {
+			return a.Events > b.Events
+		}
+		return a.AthleteID < b.AthleteID
+	})
+	if filter.Limit > 0 && len(board.Entries) > filter.Limit {
+		board.Entries = board.Entries[:filter.Limit]
+	}
+	for i := range board.Entries {
+		board.Entries[i].Rank = i + 1
+	}
+
+	return board, nil
+}
@@ -0,0 +1,171 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// Inactivity decay of AthleteRating: after ratingGrace without competing, a
+// rating drifts towards the baseline of its belt, halving the gap every
+// ratingHalfLife, since an old record says little about current level
+const (
+	ratingGrace    = 365 * 24 * time.Hour
+	ratingHalfLife = 2 * 365 * 24 * time.Hour
+)
+
+// defaultBaseline is the baseline of a belt without rated athletes
+const defaultBaseline = 50.0
+
+// RatingPoint is the rating of an athlete as one profile snapshot left it
+type RatingPoint struct {
+	CapturedAt   time.Time  `json:"captured_at"`
+	BeltRank     string     `json:"belt_rank"`
+	Wins         int        `json:"wins"`
+	Losses       int        `json:"losses"`
+	Rating       float64    `json:"rating"`                   // AthleteRating of the record
+	LastActiveAt *time.Time `json:"last_active_at,omitempty"` // latest event up to CapturedAt
+	Decayed      float64    `json:"decayed_rating"`           // Rating after inactivity decay at CapturedAt
+}
+
+// RatingHistory is the current rating of an athlete, decayed for
+// inactivity, and how it evolved across profile snapshots, oldest first
+type RatingHistory struct {
+	AthleteID    string        `json:"athlete_id"`
+	Belt         string        `json:"belt"`                     // see beltLevel
+	Baseline     float64       `json:"baseline"`                 // average rating of the belt
+	Rating       *float64      `json:"rating,omitempty"`         // nil without fights
+	Decayed      *float64      `json:"decayed_rating,omitempty"` // Rating after inactivity decay
+	LastActiveAt *time.Time    `json:"last_active_at,omitempty"`
+	InactiveDays int           `json:"inactive_days"`
+	History      []RatingPoint `json:"history"`
+}
+
+// DecayRating moves rating towards baseline for the time between lastActive
+// and at beyond the grace period. Without a known last event the rating is
+// kept.
+func DecayRating(rating, baseline float64, lastActive *time.Time, at time.Time) float64 {
+	if lastActive == nil {
+		return rating
+	}
+	idle := at.Sub(*lastActive) - ratingGrace
+	if idle <= 0 {
+		return rating
+	}
+	kept := math.Pow(0.5, float64(idle)/float64(ratingHalfLife))
+	return baseline + (rating-baseline)*kept
+}
+
+// BeltBaselines returns the average AthleteRating of the athletes with
+// fights of each belt, by belt name (see beltLevel)
+func BeltBaselines() (map[string]float64, error) {
+	var rows []struct {
+		BeltRank string
+		Rating   float64
+		Athletes int
+	}
+	err := config.GetDB().Model(&models.Athlete{}).
+		Select("belt_rank, AVG((total_wins + 1) * 100.0 / (total_wins + total_losses + 2)) AS rating, COUNT(*) AS athletes").
+		Where("total_wins + total_losses > 0").
+		Group("belt_rank").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, row := range rows {
+		belt, _, _ := beltLevel(row.BeltRank)
+		sums[belt] += row.Rating * float64(row.Athletes)
+		counts[belt] += row.Athletes
+	}
+	baselines := make(map[string]float64, len(sums))
+	for belt, sum := range sums {
+		baselines[belt] = round1(sum / float64(counts[belt]))
+	}
+	return baselines, nil
+}
+
+// AthleteRatingHistory rates athlete as of now and at each of its stats
+// snapshots, decaying every rating for the time since the athlete's latest
+// event before it
+func AthleteRatingHistory(athlete models.Athlete, now time.Time) (*RatingHistory, error) {
+	db := config.GetDB()
+
+	baselines, err := BeltBaselines()
+	if err != nil {
+		return nil, err
+	}
+	baselineOf := func(rank string) float64 {
+		belt, _, _ := beltLevel(rank)
+		if baseline, ok := baselines[belt]; ok {
+			return baseline
+		}
+		return defaultBaseline
+	}
+
+	var eventDates []time.Time
+	err = db.Model(&models.EventRegistration{}).
+		Joins("JOIN events ON events.external_id = event_registrations.event_id").
+		Where("event_registrations.athlete_id = ? AND events.start_date IS NOT NULL", athlete.ID).
+		Pluck("events.start_date", &eventDates).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(eventDates, func(i, j int) bool { return eventDates[i].Before(eventDates[j]) })
+	lastActiveAt := func(at time.Time) *time.Time {
+		i := sort.Search(len(eventDates), func(i int) bool { return eventDates[i].After(at) })
+		if i == 0 {
+			return nil
+		}
+		return &eventDates[i-1]
+	}
+
+	var snapshots []models.AthleteSnapshot
+	if err := db.Where("athlete_id = ?", athlete.ID).Order("captured_at, id").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	belt, _, _ := beltLevel(athlete.BeltRank)
+	history := &RatingHistory{
+		AthleteID:    athlete.ExternalID,
+		Belt:         belt,
+		Baseline:     baselineOf(athlete.BeltRank),
+		LastActiveAt: athlete.LastActiveAt,
+		History:      []RatingPoint{},
+	}
+	if history.LastActiveAt == nil {
+		history.LastActiveAt = lastActiveAt(now)
+	}
+	if history.LastActiveAt != nil && now.After(*history.LastActiveAt) {
+		history.InactiveDays = int(now.Sub(*history.LastActiveAt).Hours() / 24)
+	}
+	if rating, ok := AthleteRating(athlete); ok {
+		decayed := round1(DecayRating(rating, history.Baseline, history.LastActiveAt, now))
+		rating = round1(rating)
+		history.Rating, history.Decayed = &rating, &decayed
+	}
+
+	for _, snapshot := range snapshots {
+		record := models.Athlete{TotalWins: snapshot.TotalWins, TotalLosses: snapshot.TotalLosses}
+		rating, ok := AthleteRating(record)
+		if !ok {
+			continue
+		}
+		point := RatingPoint{
+			CapturedAt:   snapshot.CapturedAt,
+			BeltRank:     snapshot.BeltRank,
+			Wins:         snapshot.TotalWins,
+			Losses:       snapshot.TotalLosses,
+			Rating:       round1(rating),
+			LastActiveAt: lastActiveAt(snapshot.CapturedAt),
+		}
+		point.Decayed = round1(DecayRating(rating, baselineOf(snapshot.BeltRank), point.LastActiveAt, snapshot.CapturedAt))
+		history.History = append(history.History, point)
+	}
+	return history, nil
+}
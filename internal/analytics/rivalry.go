@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// RivalrySide is one academy's results in a rivalry
+type RivalrySide struct {
+	ExternalID string          `json:"external_id"`
+	Name       string          `json:"name"`
+	Wins       int             `json:"wins"`
+	WinsBy     MethodBreakdown `json:"wins_by"`
+}
+
+// RivalryOpponent is an academy both rivals have faced, with each side's
+// record against it
+type RivalryOpponent struct {
+	ExternalID string           `json:"external_id"`
+	Name       string           `json:"name"`
+	A          HeadToHeadRecord `json:"a"`
+	B          HeadToHeadRecord `json:"b"`
+}
+
+// AcademyRivalry is the head-to-head history of two academies
+type AcademyRivalry struct {
+	A         RivalrySide `json:"a"`
+	B         RivalrySide `json:"b"`
+	Matches   int         `json:"matches"`
+	Undecided int         `json:"undecided"` // matches without a recorded winner
+
+	History         []models.Match    `json:"history"`
+	CommonOpponents []RivalryOpponent `json:"common_opponents"`
+}
+
+// academyMatch is a match with the academies of both competitors
+type academyMatch struct {
+	ID         int
+	AthleteAID uint
+	AthleteBID uint
+	WinnerID   uint
+	Method     string
+	AcademyA   string
+	AcademyB   string
+}
+
+// AcademyHeadToHead compares two academies through the matches between their
+// athletes and against academies both have faced
+func AcademyHeadToHead(a, b models.Academy) (*AcademyRivalry, error) {
+	db := config.GetDB()
+	rivalry := &AcademyRivalry{
+		A:               RivalrySide{ExternalID: a.ExternalID, Name: a.Name},
+		B:               RivalrySide{ExternalID: b.ExternalID, Name: b.Name},
+		History:         []models.Match{},
+		CommonOpponents: []RivalryOpponent{},
+	}
+
+	var rows []academyMatch
+	err := db.Table("matches").
+		Select("matches.id, matches.athlete_a_id, matches.athlete_b_id, matches.winner_id, matches.method, "+
+			"athlete_a.academy_external_id AS academy_a, athlete_b.academy_external_id AS academy_b").
+		Joins("JOIN athletes athlete_a ON athlete_a.id = matches.athlete_a_id").
+		Joins("JOIN athletes athlete_b ON athlete_b.id = matches.athlete_b_id").
+		Where("athlete_a.academy_external_id IN ? OR athlete_b.academy_external_id IN ?",
+			[]string{a.ExternalID, b.ExternalID}, []string{a.ExternalID, b.ExternalID}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var historyIDs []int
+	// opponent academy -> records of each side against it
+	opponents := map[string]*RivalryOpponent{}
+
+	for _, row := range rows {
+		if row.AcademyA == row.AcademyB {
+			continue
+		}
+
+		if (row.AcademyA == a.ExternalID && row.AcademyB == b.ExternalID) ||
+			(row.AcademyA == b.ExternalID && row.AcademyB == a.ExternalID) {
+			historyIDs = append(historyIDs, row.ID)
+			rivalry.Matches++
+			switch {
+			case row.WinnerID == 0:
+				rivalry.Undecided++
+			case row.academyOf(row.WinnerID) == a.ExternalID:
+				rivalry.A.Wins++
+				rivalry.A.WinsBy.add(row.Method)
+			default:
+				rivalry.B.Wins++
+				rivalry.B.WinsBy.add(row.Method)
+			}
+			continue
+		}
+
+		// A match of one rival against a third academy
+		side, opponent, athleteID := row.AcademyA, row.AcademyB, row.AthleteAID
+		if side != a.ExternalID && side != b.ExternalID {
+			side, opponent, athleteID = row.AcademyB, row.AcademyA, row.AthleteBID
+		}
+		if opponent == "" {
+			continue
+		}
+		entry, ok := opponents[opponent]
+		if !ok {
+			entry = &RivalryOpponent{ExternalID: opponent}
+			opponents[opponent] = entry
+		}
+		record := &entry.A
+		if side == b.ExternalID {
+			record = &entry.B
+		}
+		record.Matches++
+		switch row.WinnerID {
+		case 0:
+		case athleteID:
+			record.Wins++
+		default:
+			record.Losses++
+		}
+	}
+
+	if len(historyIDs) > 0 {
+		if err := db.Where("id IN ?", historyIDs).Order("id DESC").Find(&rivalry.History).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var common []string
+	for id, entry := range opponents {
+		if entry.A.Matches > 0 && entry.B.Matches > 0 {
+			common = append(common, id)
+		}
+	}
+	if len(common) > 0 {
+		var academies []models.Academy
+		if err := db.Select("external_id", "name").Where("external_id IN ?", common).Find(&academies).Error; err != nil {
+			return nil, err
+		}
+		for _, academy := range academies {
+			opponents[academy.ExternalID].Name = academy.Name
+		}
+		for _, id := range common {
+			rivalry.CommonOpponents = append(rivalry.CommonOpponents, *opponents[id])
+		}
+		sort.Slice(rivalry.CommonOpponents, func(i, j int) bool {
+			x, y := rivalry.CommonOpponents[i], rivalry.CommonOpponents[j]
+			if x.A.Matches+x.B.Matches != y.A.Matches+y.B.Matches {
+				return x.A.Matches+x.B.Matches > y.A.Matches+y.B.Matches
+			}
+			return x.ExternalID < y.ExternalID
+		})
+	}
+
+	return rivalry, nil
+}
+
+// academyOf returns the academy of the given competitor of the match
+func (m academyMatch) academyOf(athleteID uint) string {
+	if athleteID == m.AthleteAID {
+		return m.AcademyA
+	}
+	return m.AcademyB
+}
@@ -0,0 +1,202 @@
+package analytics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// Weights of the strength index components; missing components are left out
+// and the rest scaled up
+const (
+	ratingWeight = 0.5
+	beltWeight   = 0.3
+	rankedWeight = 0.2
+)
+
+// beltLevels orders belts for the belt component; kids belts count as white
+var beltLevels = []struct {
+	name     string
+	keywords []string
+	level    int
+}{
+	{"white", []string{"white", "blanc", "branc"}, 0},
+	{"grey", []string{"grey", "gray", "gris", "cinza"}, 0},
+	{"yellow", []string{"yellow", "amarill", "amarel"}, 0},
+	{"orange", []string{"orange", "naranj", "laranj"}, 0},
+	{"green", []string{"green", "verde"}, 0},
+	{"blue", []string{"blue", "azul"}, 1},
+	{"purple", []string{"purple", "violet", "morad", "roxa"}, 2},
+	{"brown", []string{"brown", "marron", "marrón", "cafe", "café", "marrom"}, 3},
+	{"black", []string{"black", "negr", "preta"}, 4},
+}
+
+const maxBeltLevel = 4
+
+// DivisionStrength rates how hard a division was to place in, so podiums
+// can be weighted. Index is 0-100: half the average rating, 30% the belt
+// level and 20% the share of ranked athletes.
+type DivisionStrength struct {
+	EventID        string         `json:"event_id"`
+	DivisionID     string         `json:"division_id"`
+	Division       string         `json:"division"`
+	Athletes       int            `json:"athletes"`
+	RankedAthletes int            `json:"ranked_athletes"`       // with a Smoothcomp ranking
+	AvgRanking     *float64       `json:"avg_ranking,omitempty"` // of ranked athletes; lower is better
+	AvgRating      *float64       `json:"avg_rating,omitempty"`  // of athletes with fights, see AthleteRating
+	Belts          map[string]int `json:"belts"`                 // athletes by belt, "unknown" when not scraped
+	BeltScore      *float64       `json:"belt_score,omitempty"`  // 0 (white) to 100 (black)
+	Index          float64        `json:"strength_index"`
+}
+
+// AthleteRating is the career win rate of an athlete as 0-100, pulled
+// towards 50 for short records: (wins+1) / (wins+losses+2). ok is false
+// without fights.
+func AthleteRating(athlete models.Athlete) (rating float64, ok bool) {
+	fights := athlete.TotalWins + athlete.TotalLosses
+	if fights == 0 {
+		return 0, false
+	}
+	return float64(athlete.TotalWins+1) / float64(fights+2) * 100, true
+}
+
+// EventDivisionStrengths rates every division of an event with stored
+// registrations, strongest first
+func EventDivisionStrengths(eventID string) ([]DivisionStrength, error) {
+	return divisionStrengths(eventID, "")
+}
+
+// LoadDivisionStrength rates one division of an event. ok is false when it
+// has no stored registrations.
+func LoadDivisionStrength(eventID, divisionID string) (strength DivisionStrength, ok bool, err error) {
+	strengths, err := divisionStrengths(eventID, divisionID)
+	if err != nil || len(strengths) == 0 {
+		return DivisionStrength{}, false, err
+	}
+	return strengths[0], true, nil
+}
+
+func divisionStrengths(eventID, divisionID string) ([]DivisionStrength, error) {
+	query := config.GetDB().Preload("Athlete").
+		Where("event_id = ? AND division_id IS NOT NULL AND division_id <> ''", eventID)
+	if divisionID != "" {
+		query = query.Where("division_id = ?", divisionID)
+	}
+
+	var registrations []models.EventRegistration
+	if err := query.Order("id").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+
+	byDivision := map[string][]models.EventRegistration{}
+	var order []string
+	for _, registration := range registrations {
+		id := *registration.DivisionID
+		if _, seen := byDivision[id]; !seen {
+			order = append(order, id)
+		}
+		byDivision[id] = append(byDivision[id], registration)
+	}
+
+	strengths := make([]DivisionStrength, 0, len(order))
+	for _, id := range order {
+		strengths = append(strengths, RateDivision(eventID, id, byDivision[id]))
+	}
+	sort.SliceStable(strengths, func(i, j int) bool {
+		return strengths[i].Index > strengths[j].Index
+	})
+	return strengths, nil
+}
+
+// RateDivision computes the strength of a division from its registrations,
+// with their athletes loaded. registrations must not be empty.
+func RateDivision(eventID, divisionID string, registrations []models.EventRegistration) DivisionStrength {
+	first := registrations[0]
+	strength := DivisionStrength{
+		EventID:    eventID,
+		DivisionID: divisionID,
+		Division:   joinLabel(first.Division, first.AgeCategory, first.Rank, first.WeightClass),
+		Belts:      map[string]int{},
+	}
+
+	var rankingSum, ratingSum float64
+	var rated, belted, levelSum int
+	seen := map[uint]bool{}
+	for _, registration := range registrations {
+		if seen[registration.AthleteID] {
+			continue
+		}
+		seen[registration.AthleteID] = true
+		strength.Athletes++
+
+		if registration.Ranking > 0 {
+			strength.RankedAthletes++
+			rankingSum += float64(registration.Ranking)
+		}
+		if rating, ok := AthleteRating(registration.Athlete); ok {
+			ratingSum += rating
+			rated++
+		}
+
+		belt, level, ok := beltLevel(registration.Athlete.BeltRank)
+		strength.Belts[belt]++
+		if ok {
+			levelSum += level
+			belted++
+		}
+	}
+
+	var weighted, weights float64
+	if strength.RankedAthletes > 0 {
+		avg := round1(rankingSum / float64(strength.RankedAthletes))
+		strength.AvgRanking = &avg
+	}
+	if rated > 0 {
+		avg := round1(ratingSum / float64(rated))
+		strength.AvgRating = &avg
+		weighted += ratingWeight * avg
+		weights += ratingWeight
+	}
+	if belted > 0 {
+		score := round1(float64(levelSum) / float64(belted) / maxBeltLevel * 100)
+		strength.BeltScore = &score
+		weighted += beltWeight * score
+		weights += beltWeight
+	}
+	weighted += rankedWeight * float64(strength.RankedAthletes) / float64(strength.Athletes) * 100
+	weights += rankedWeight
+
+	strength.Index = round1(weighted / weights)
+	return strength
+}
+
+// beltLevel names the belt of a rank as scraped ("Black belt", "Faixa
+// preta") and returns its level; ok is false when it is not recognized
+func beltLevel(rank string) (name string, level int, ok bool) {
+	rank = strings.ToLower(rank)
+	best := -1
+	for _, belt := range beltLevels {
+		for _, keyword := range belt.keywords {
+			if at := strings.Index(rank, keyword); at >= 0 && (best < 0 || at < best) {
+				best = at
+				name, level, ok = belt.name, belt.level, true
+			}
+		}
+	}
+	if !ok {
+		return "unknown", 0, false
+	}
+	return name, level, true
+}
+
+func joinLabel(parts ...string) string {
+	kept := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, " / ")
+}
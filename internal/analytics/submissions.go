@@ -0,0 +1,319 @@
+package analytics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// SubmissionFilter narrows the submission statistics to one belt, weight
+// class, gender and/or period; empty fields match everything
+type SubmissionFilter struct {
+	Belt        string
+	WeightClass string // any label ParseWeightClass understands
+	Gender      models.Gender
+	Year        int
+	From        *time.Time // matches on or after this day
+	To          *time.Time // matches on or before this day
+	Limit       int        // techniques listed per group
+}
+
+// SubmissionGroup is the share of decided matches finished by submission
+// within one belt, weight class, gender or year, and its top techniques
+type SubmissionGroup struct {
+	Key            string           `json:"key"`
+	DecidedMatches int              `json:"decided_matches"`
+	Submissions    int              `json:"submissions"`
+	SubmissionRate float64          `json:"submission_rate"`
+	TopSubmissions []TechniqueCount `json:"top_submissions"`
+}
+
+// SubmissionStats summarizes the finishing submissions of every stored
+// decided match matching a filter
+type SubmissionStats struct {
+	Belt        string        `json:"belt,omitempty"`
+	WeightClass string        `json:"weight_class,omitempty"`
+	Gender      models.Gender `json:"gender,omitempty"`
+	Year        int           `json:"year,omitempty"`
+	From        *time.Time    `json:"from,omitempty"`
+	To          *time.Time    `json:"to,omitempty"`
+
+	DecidedMatches int              `json:"decided_matches"`
+	Submissions    int              `json:"submissions"`
+	SubmissionRate float64          `json:"submission_rate"`
+	TopSubmissions []TechniqueCount `json:"top_submissions"`
+
+	ByBelt        []SubmissionGroup `json:"by_belt"`
+	ByWeightClass []SubmissionGroup `json:"by_weight_class"`
+	ByGender      []SubmissionGroup `json:"by_gender"`
+	ByYear        []SubmissionGroup `json:"by_year"` // oldest first
+}
+
+// unknownGroup collects matches whose belt, weight class, gender or date
+// could not be told
+const unknownGroup = "unknown"
+
+// lookupBatch bounds the IDs bound to one IN query
+const lookupBatch = 500
+
+// submissionMatch is a decided match with the attributes it is grouped by
+type submissionMatch struct {
+	technique   string // empty unless finished by a named submission
+	belt        string
+	weightClass string
+	gender      models.Gender
+	date        *time.Time
+}
+
+// SubmissionMeta computes which submissions finish matches across the whole
+// match dataset. Belt, weight class and gender come from the registrations
+// linked to each match, falling back to its category label; the date is the
+// event's start day, else when the match started.
+func SubmissionMeta(filter SubmissionFilter) (*SubmissionStats, error) {
+	stats := &SubmissionStats{
+		Belt:   filter.Belt,
+		Gender: filter.Gender,
+		Year:   filter.Year,
+		From:   filter.From,
+		To:     filter.To,
+	}
+	weightKey := ""
+	if filter.WeightClass != "" {
+		weightKey = weightClassFilterKey(filter.WeightClass)
+		stats.WeightClass = weightKey
+	}
+
+	matches, err := loadSubmissionMatches()
+	if err != nil {
+		return nil, err
+	}
+
+	all := newSubmissionCounter()
+	byBelt := make(map[string]*submissionCounter)
+	byWeight := make(map[string]*submissionCounter)
+	byGender := make(map[string]*submissionCounter)
+	byYear := make(map[string]*submissionCounter)
+
+	for _, match := range matches {
+		if filter.Belt != "" && !strings.EqualFold(match.belt, filter.Belt) {
+			continue
+		}
+		if weightKey != "" && match.weightClass != weightKey {
+			continue
+		}
+		if filter.Gender != models.GenderUnknown && match.gender != filter.Gender {
+			continue
+		}
+		if filter.Year != 0 || filter.From != nil || filter.To != nil {
+			if match.date == nil ||
+				filter.Year != 0 && match.date.Year() != filter.Year ||
+				filter.From != nil && match.date.Before(*filter.From) ||
+				filter.To != nil && match.date.After(filter.To.AddDate(0, 0, 1).Add(-time.Nanosecond)) {
+				continue
+			}
+		}
+
+		year := unknownGroup
+		if match.date != nil {
+			year = strconv.Itoa(match.date.Year())
+		}
+		all.add(match.technique)
+		counterFor(byBelt, orUnknown(match.belt)).add(match.technique)
+		counterFor(byWeight, orUnknown(match.weightClass)).add(match.technique)
+		counterFor(byGender, orUnknown(string(match.gender))).add(match.technique)
+		counterFor(byYear, year).add(match.technique)
+	}
+
+	stats.DecidedMatches = all.decided
+	stats.Submissions = all.submissions
+	stats.SubmissionRate = ratio(all.submissions, all.decided)
+	stats.TopSubmissions = all.top(filter.Limit)
+	stats.ByBelt = submissionGroups(byBelt, filter.Limit)
+	stats.ByWeightClass = submissionGroups(byWeight, filter.Limit)
+	stats.ByGender = submissionGroups(byGender, filter.Limit)
+	stats.ByYear = submissionGroups(byYear, filter.Limit)
+	sort.Slice(stats.ByYear, func(i, j int) bool {
+		// "unknown" sorts after the years
+		return stats.ByYear[i].Key < stats.ByYear[j].Key
+	})
+
+	return stats, nil
+}
+
+// weightClassFilterKey normalizes a weight class filter the way registrations
+// are normalized, keeping unrecognized labels as typed
+func weightClassFilterKey(label string) string {
+	if key := models.ParseWeightClass(label).Key; key != "" {
+		return key
+	}
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+// loadSubmissionMatches reads every decided match with the belt, weight
+// class, gender and date it is grouped by
+func loadSubmissionMatches() ([]submissionMatch, error) {
+	db := config.GetDB()
+
+	var matches []models.Match
+	if err := db.Select("id, event_id, category, method, technique, registration_a_id, registration_b_id, started_at").
+		Where("winner_id <> 0").
+		Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	// Both sides of a match share the division, so one registration is enough
+	regIDs := make([]uint, 0, len(matches))
+	eventIDs := make(map[string]bool)
+	for _, match := range matches {
+		if id := matchRegistration(match); id != 0 {
+			regIDs = append(regIDs, id)
+		}
+		eventIDs[match.EventID] = true
+	}
+
+	registrations := make(map[uint]models.EventRegistration, len(regIDs))
+	for start := 0; start < len(regIDs); start += lookupBatch {
+		var batch []models.EventRegistration
+		if err := db.Select("id, rank, weight_class_key, gender").
+			Where("id IN ?", regIDs[start:min(start+lookupBatch, len(regIDs))]).
+			Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		for _, reg := range batch {
+			registrations[reg.ID] = reg
+		}
+	}
+
+	eventDates := make(map[string]*time.Time, len(eventIDs))
+	ids := make([]string, 0, len(eventIDs))
+	for id := range eventIDs {
+		ids = append(ids, id)
+	}
+	for start := 0; start < len(ids); start += lookupBatch {
+		var events []models.Event
+		if err := db.Select("external_id, start_date").
+			Where("external_id IN ? AND start_date IS NOT NULL", ids[start:min(start+lookupBatch, len(ids))]).
+			Find(&events).Error; err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			eventDates[event.ExternalID] = event.StartDate
+		}
+	}
+
+	result := make([]submissionMatch, 0, len(matches))
+	for _, match := range matches {
+		entry := submissionMatch{date: eventDates[match.EventID]}
+		if entry.date == nil {
+			entry.date = match.StartedAt
+		}
+		if technique := strings.TrimSpace(match.Technique); match.Method == "submission" && technique != "" {
+			entry.technique = technique
+		}
+		if reg, ok := registrations[matchRegistration(match)]; ok {
+			entry.belt = strings.TrimSpace(reg.Rank)
+			entry.weightClass = reg.WeightClassKey
+			entry.gender = reg.Gender
+		}
+		if entry.weightClass == "" {
+			entry.weightClass = models.ParseWeightClass(match.Category).Key
+		}
+		if entry.gender == models.GenderUnknown {
+			entry.gender, _ = models.ParseGender(match.Category)
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+func matchRegistration(match models.Match) uint {
+	if match.RegistrationAID != nil {
+		return *match.RegistrationAID
+	}
+	if match.RegistrationBID != nil {
+		return *match.RegistrationBID
+	}
+	return 0
+}
+
+// submissionCounter tallies techniques case-insensitively, reporting each
+// under its most common spelling
+type submissionCounter struct {
+	decided     int
+	submissions int
+	counts      map[string]int            // lowercased technique -> count
+	spellings   map[string]map[string]int // lowercased technique -> spelling -> count
+}
+
+func newSubmissionCounter() *submissionCounter {
+	return &submissionCounter{counts: make(map[string]int), spellings: make(map[string]map[string]int)}
+}
+
+func counterFor(groups map[string]*submissionCounter, key string) *submissionCounter {
+	counter, ok := groups[key]
+	if !ok {
+		counter = newSubmissionCounter()
+		groups[key] = counter
+	}
+	return counter
+}
+
+func (c *submissionCounter) add(technique string) {
+	c.decided++
+	if technique == "" {
+		return
+	}
+	c.submissions++
+	key := strings.ToLower(strings.Join(strings.Fields(technique), " "))
+	c.counts[key]++
+	if c.spellings[key] == nil {
+		c.spellings[key] = make(map[string]int)
+	}
+	c.spellings[key][technique]++
+}
+
+func (c *submissionCounter) top(limit int) []TechniqueCount {
+	named := make(map[string]int, len(c.counts))
+	for key, count := range c.counts {
+		best, bestCount := "", 0
+		for spelling, n := range c.spellings[key] {
+			if n > bestCount || n == bestCount && spelling < best {
+				best, bestCount = spelling, n
+			}
+		}
+		named[best] = count
+	}
+	return topCounts(named, limit)
+}
+
+// submissionGroups lists the groups with most submissions first
+func submissionGroups(groups map[string]*submissionCounter, limit int) []SubmissionGroup {
+	result := make([]SubmissionGroup, 0, len(groups))
+	for key, counter := range groups {
+		result = append(result, SubmissionGroup{
+			Key:            key,
+			DecidedMatches: counter.decided,
+			Submissions:    counter.submissions,
+			SubmissionRate: ratio(counter.submissions, counter.decided),
+			TopSubmissions: counter.top(limit),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Submissions != result[j].Submissions {
+			return result[i].Submissions > result[j].Submissions
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return unknownGroup
+	}
+	return value
+}
@@ -0,0 +1,320 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// DefaultCutPercent flags cuts of more than this share of the typical weight
+const DefaultCutPercent = 5.0
+
+// typicalWeighIns is how many recent weigh-ins give the typical weight
+const typicalWeighIns = 10
+
+// WeightLimit is the range of a weight class in kg. Max is 0 for open-ended
+// classes ("+100 kg"); Min is 0 when no lighter class is known.
+type WeightLimit struct {
+	Min float64 `json:"min_kg,omitempty"`
+	Max float64 `json:"max_kg,omitempty"`
+}
+
+// ParseWeightClass reads the limit of a weight class label. ok is false for
+// open weight ("Absolute", "Open") and labels without a limit.
+func ParseWeightClass(label string) (limit WeightLimit, ok bool) {
+	class := models.ParseWeightClass(label)
+	return WeightLimit{Min: class.MinKg, Max: class.MaxKg}, class.HasLimit()
+}
+
+// WeightClassUsage is one weight class an athlete has registered in
+type WeightClassUsage struct {
+	WeightClass string      `json:"weight_class"`
+	Limit       WeightLimit `json:"limit"`
+	Events      int         `json:"events"`
+	WeighIns    int         `json:"weigh_ins"`
+	AvgWeight   *float64    `json:"avg_weight_kg,omitempty"`
+	AvgMargin   *float64    `json:"avg_margin_kg,omitempty"` // limit minus weigh-in; negative means over
+	LastEventID string      `json:"last_event_id"`
+}
+
+// WeightSuggestion is a weight class that fits the typical weight
+type WeightSuggestion struct {
+	WeightClass string      `json:"weight_class"`
+	Limit       WeightLimit `json:"limit"`
+	Kind        string      `json:"kind"`   // "natural" (typical weight fits) or "cut" (next class down)
+	CutKg       float64     `json:"cut_kg"` // weight to lose to make the class
+	CutPercent  float64     `json:"cut_percent"`
+	Competed    bool        `json:"competed"` // the athlete already registered in it
+}
+
+// WeightFlag is a registration that needed a big cut or missed the limit
+type WeightFlag struct {
+	EventID      string      `json:"event_id"`
+	EventName    string      `json:"event_name"`
+	WeightClass  string      `json:"weight_class"`
+	Limit        WeightLimit `json:"limit"`
+	ActualWeight float64     `json:"actual_weight_kg,omitempty"`
+	CutKg        float64     `json:"cut_kg"` // typical weight minus the class limit
+	CutPercent   float64     `json:"cut_percent"`
+	Reasons      []string    `json:"reasons"` // "big_cut", "over_limit"
+	Date         time.Time   `json:"registration_date"`
+}
+
+// WeightProfile summarizes the weight classes of an athlete for coaching
+type WeightProfile struct {
+	AthleteID     int                `json:"athlete_id"`
+	Name          string             `json:"name"`
+	Registrations int                `json:"registrations"` // with a weight class limit
+	WeighIns      int                `json:"weigh_ins"`
+	TypicalWeight *float64           `json:"typical_weight_kg,omitempty"` // median of recent weigh-ins
+	CutPercent    float64            `json:"cut_percent"`                 // threshold of big cuts
+	Classes       []WeightClassUsage `json:"classes"`
+	Suggestions   []WeightSuggestion `json:"suggestions"`
+	Flags         []WeightFlag       `json:"flags"`
+}
+
+// AthleteWeight builds the weight profile of an athlete from its
+// registrations. Suggestions need weigh-ins; the class ladder comes from the
+// stored registrations of the athlete's latest division, age category and
+// rank.
+func AthleteWeight(athlete models.Athlete, cutPercent float64) (*WeightProfile, error) {
+	if cutPercent <= 0 {
+		cutPercent = DefaultCutPercent
+	}
+
+	var registrations []models.EventRegistration
+	if err := config.GetDB().Where("athlete_id = ?", athlete.ID).
+		Order("registration_date DESC, id DESC").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+
+	profile := &WeightProfile{
+		AthleteID:   athlete.ID,
+		Name:        athlete.FullName,
+		CutPercent:  cutPercent,
+		Classes:     []WeightClassUsage{},
+		Suggestions: []WeightSuggestion{},
+		Flags:       []WeightFlag{},
+	}
+
+	type classStats struct {
+		usage     WeightClassUsage
+		weightSum float64
+		marginSum float64
+		margins   int
+	}
+	byClass := map[string]*classStats{}
+	var order []string
+	var weights []float64
+	var limited []models.EventRegistration
+	for _, registration := range registrations {
+		limit, ok := ParseWeightClass(registration.WeightClass)
+		if !ok {
+			continue
+		}
+		limited = append(limited, registration)
+
+		key := normalizeClass(registration.WeightClass)
+		stats, seen := byClass[key]
+		if !seen {
+			stats = &classStats{usage: WeightClassUsage{
+				WeightClass: registration.WeightClass,
+				Limit:       limit,
+				LastEventID: registration.EventID,
+			}}
+			byClass[key] = stats
+			order = append(order, key)
+		}
+		stats.usage.Events++
+
+		if registration.ActualWeight > 0 {
+			stats.usage.WeighIns++
+			stats.weightSum += registration.ActualWeight
+			if limit.Max > 0 {
+				stats.marginSum += limit.Max - registration.ActualWeight
+				stats.margins++
+			}
+			if len(weights) < typicalWeighIns {
+				weights = append(weights, registration.ActualWeight)
+			}
+		}
+	}
+
+	profile.Registrations = len(limited)
+	for _, key := range order {
+		stats := byClass[key]
+		if stats.usage.WeighIns > 0 {
+			avg := round1(stats.weightSum / float64(stats.usage.WeighIns))
+			stats.usage.AvgWeight = &avg
+			profile.WeighIns += stats.usage.WeighIns
+		}
+		if stats.margins > 0 {
+			margin := round1(stats.marginSum / float64(stats.margins))
+			stats.usage.AvgMargin = &margin
+		}
+		profile.Classes = append(profile.Classes, stats.usage)
+	}
+	// Closest fit first: smallest margin either way, then most events
+	sort.SliceStable(profile.Classes, func(i, j int) bool {
+		a, b := profile.Classes[i], profile.Classes[j]
+		if (a.AvgMargin != nil) != (b.AvgMargin != nil) {
+			return a.AvgMargin != nil
+		}
+		if a.AvgMargin != nil && math.Abs(*a.AvgMargin) != math.Abs(*b.AvgMargin) {
+			return math.Abs(*a.AvgMargin) < math.Abs(*b.AvgMargin)
+		}
+		return a.Events > b.Events
+	})
+
+	if len(weights) == 0 {
+		return profile, nil
+	}
+	typical := round1(median(weights))
+	profile.TypicalWeight = &typical
+
+	ladder, err := classLadder(limited[0])
+	if err != nil {
+		return nil, err
+	}
+	competed := make(map[string]bool, len(byClass))
+	for key := range byClass {
+		competed[key] = true
+	}
+	profile.Suggestions = suggest(ladder, typical, competed)
+
+	for _, registration := range limited {
+		limit, _ := ParseWeightClass(registration.WeightClass)
+		if limit.Max == 0 {
+			continue
+		}
+		flag := WeightFlag{
+			EventID:      registration.EventID,
+			EventName:    registration.EventName,
+			WeightClass:  registration.WeightClass,
+			Limit:        limit,
+			ActualWeight: registration.ActualWeight,
+			CutKg:        round1(typical - limit.Max),
+			CutPercent:   round1((typical - limit.Max) / typical * 100),
+			Date:         registration.RegistrationDate,
+		}
+		if flag.CutPercent > cutPercent {
+			flag.Reasons = append(flag.Reasons, "big_cut")
+		}
+		if registration.ActualWeight > limit.Max {
+			flag.Reasons = append(flag.Reasons, "over_limit")
+		}
+		if len(flag.Reasons) > 0 {
+			profile.Flags = append(profile.Flags, flag)
+		}
+	}
+
+	return profile, nil
+}
+
+// ladderClass is one weight class of a division, by limit
+type ladderClass struct {
+	label string
+	limit WeightLimit
+}
+
+// classLadder lists the weight classes stored for the division, age
+// category and rank of a registration, lightest first, with Min set to the
+// limit of the class below
+func classLadder(registration models.EventRegistration) ([]ladderClass, error) {
+	var labels []string
+	if err := config.GetDB().Model(&models.EventRegistration{}).
+		Where("division = ? AND age_category = ? AND rank = ?",
+			registration.Division, registration.AgeCategory, registration.Rank).
+		Distinct().Pluck("weight_class", &labels).Error; err != nil {
+		return nil, err
+	}
+
+	byLimit := map[WeightLimit]string{}
+	for _, label := range labels {
+		if limit, ok := ParseWeightClass(label); ok {
+			if _, seen := byLimit[limit]; !seen {
+				byLimit[limit] = label
+			}
+		}
+	}
+
+	ladder := make([]ladderClass, 0, len(byLimit))
+	for limit, label := range byLimit {
+		ladder = append(ladder, ladderClass{label: label, limit: limit})
+	}
+	// Capped classes by limit, then open-ended ones
+	sort.Slice(ladder, func(i, j int) bool {
+		a, b := ladder[i].limit, ladder[j].limit
+		if (a.Max == 0) != (b.Max == 0) {
+			return b.Max == 0
+		}
+		if a.Max != b.Max {
+			return a.Max < b.Max
+		}
+		return a.Min < b.Min
+	})
+	for i := 1; i < len(ladder); i++ {
+		if ladder[i].limit.Max > 0 {
+			ladder[i].limit.Min = ladder[i-1].limit.Max
+		}
+	}
+	return ladder, nil
+}
+
+// suggest picks the lightest class the typical weight fits ("natural") and
+// the class below it ("cut")
+func suggest(ladder []ladderClass, typical float64, competed map[string]bool) []WeightSuggestion {
+	natural := -1
+	for i, class := range ladder {
+		if class.limit.Max == 0 || typical <= class.limit.Max {
+			natural = i
+			break
+		}
+	}
+	if natural < 0 {
+		return []WeightSuggestion{}
+	}
+
+	suggestion := func(class ladderClass, kind string) WeightSuggestion {
+		cut := 0.0
+		if class.limit.Max > 0 && typical > class.limit.Max {
+			cut = round1(typical - class.limit.Max)
+		}
+		return WeightSuggestion{
+			WeightClass: class.label,
+			Limit:       class.limit,
+			Kind:        kind,
+			CutKg:       cut,
+			CutPercent:  round1(cut / typical * 100),
+			Competed:    competed[normalizeClass(class.label)],
+		}
+	}
+
+	suggestions := []WeightSuggestion{suggestion(ladder[natural], "natural")}
+	if natural > 0 {
+		suggestions = append(suggestions, suggestion(ladder[natural-1], "cut"))
+	}
+	return suggestions
+}
+
+func normalizeClass(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), ""))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
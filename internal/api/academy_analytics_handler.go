@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetAcademyAnalytics returns match-level statistics of an academy
+// (submission rates, outcome breakdown, most common finishes)
+func (h *Handler) GetAcademyAnalytics(w http.ResponseWriter, r *http.Request) {
+	var academy models.Academy
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+
+	stats, err := analytics.AcademyStats(academy.ExternalID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute academy analytics",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy analytics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// GetAcademyRivalry returns the head-to-head history between two academies
+// and their records against academies both have faced
+func (h *Handler) GetAcademyRivalry(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	db := config.GetDB()
+
+	var academy, rival models.Academy
+	if err := findByIDOrSlug(db, vars["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+	if err := findByIDOrSlug(db, vars["rival"], &rival); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Rival academy not found",
+		})
+		return
+	}
+	if academy.ID == rival.ID {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "An academy cannot be compared with itself",
+		})
+		return
+	}
+
+	rivalry, err := analytics.AcademyHeadToHead(academy, rival)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute academy rivalry",
+		})
+		return
+	}
+	h.privacy.MaskMatches(rivalry.History)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy rivalry retrieved successfully",
+		Data:    rivalry,
+	})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+)
+
+// academyContact is an academy's outreach entry; the contact fields are
+// hidden from the public academy JSON
+type academyContact struct {
+	ID          int    `json:"id"`
+	ExternalID  string `json:"external_id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	ClubURL     string `json:"club_url"`
+	CountryCode string `json:"country_code"`
+	Email       string `json:"email,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Address     string `json:"address,omitempty"`
+}
+
+// contactColumns maps ?has= values to academy columns
+var contactColumns = map[string]string{
+	"email":   "contact_email",
+	"phone":   "contact_phone",
+	"address": "contact_address",
+}
+
+// ListAcademyContacts returns the contact details scraped from club pages for
+// outreach: every academy with an email, phone or address, by country and
+// name. ?country= and ?tag= narrow the list, ?has=email (or phone, address)
+// keeps the academies with that field, and ?format=csv downloads it as CSV.
+// Academies that opted out have no contact details.
+func (h *Handler) ListAcademyContacts(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.Academy{}).
+		Where("contact_email <> '' OR contact_phone <> '' OR contact_address <> ''")
+	if country := r.URL.Query().Get("country"); country != "" {
+		query = query.Where("country_code = ?", strings.ToUpper(country))
+	}
+	if has := r.URL.Query().Get("has"); has != "" {
+		column, ok := contactColumns[has]
+		if !ok {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "has must be email, phone or address",
+			})
+			return
+		}
+		query = query.Where(column + " <> ''")
+	}
+	query, ok := h.filterByTags(w, r, query, models.TaggedAcademy)
+	if !ok {
+		return
+	}
+
+	var academies []models.Academy
+	if err := query.Order("country_code, name").Find(&academies).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	contacts := make([]academyContact, len(academies))
+	for i, academy := range academies {
+		contacts[i] = academyContact{
+			ID:          academy.ID,
+			ExternalID:  academy.ExternalID,
+			Name:        academy.Name,
+			Slug:        academy.Slug,
+			ClubURL:     academy.ClubURL,
+			CountryCode: academy.CountryCode,
+			Email:       academy.ContactEmail,
+			Phone:       academy.ContactPhone,
+			Address:     academy.ContactAddress,
+		}
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="academy-contacts.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "external_id", "name", "slug", "club_url", "country_code", "email", "phone", "address"})
+		for _, c := range contacts {
+			writer.Write([]string{
+				strconv.Itoa(c.ID), c.ExternalID, c.Name, c.Slug, c.ClubURL, c.CountryCode, c.Email, c.Phone, c.Address,
+			})
+		}
+		writer.Flush()
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy contacts retrieved successfully",
+		Data:    contacts,
+	})
+}
+
+// OptOutAcademyContact handles an academy's request not to be contacted: its
+// email, phone and address are cleared and future scrapes do not store them
+// again. An optional JSON body {"reason": "..."} is recorded with the request.
+func (h *Handler) OptOutAcademyContact(w http.ResponseWriter, r *http.Request) {
+	var academy models.Academy
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request body",
+			})
+			return
+		}
+	}
+
+	entry, err := scraper.OptOutAcademyContact(academy, input.Reason, requestActor(r))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy opted out of contact",
+		Data:    entry,
+	})
+}
+
+// RemoveAcademyContactOptOut withdraws an academy's opt-out; its contact
+// details are stored again from its next club page scrape
+func (h *Handler) RemoveAcademyContactOptOut(w http.ResponseWriter, r *http.Request) {
+	var academy models.Academy
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+
+	removed, err := scraper.RemoveAcademyContactOptOut(academy.ExternalID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !removed {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy has not opted out",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy contact opt-out removed",
+	})
+}
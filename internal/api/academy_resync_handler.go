@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// mediaRefresh reports the re-fetch of one academy image
+type mediaRefresh struct {
+	SourceURL string `json:"source_url"`
+	Hash      string `json:"hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// academyResyncResponse adds the refreshed image snapshots to the scraper result
+type academyResyncResponse struct {
+	*scraper.AcademyResyncResult
+	Media []mediaRefresh `json:"media,omitempty"`
+}
+
+// ResyncAcademy re-fetches the details, members roster and logo and cover
+// snapshots of one academy. It waits for the result unless ?async=true, which
+// answers 202 with the queued academy_resync job and leaves the images as
+// cached.
+func (h *Handler) ResyncAcademy(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	var academy models.Academy
+	if err := findByIDOrSlug(db, mux.Vars(r)["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	logger.Info("Academy resync triggered",
+		zap.String("academy_id", academy.ExternalID),
+		zap.Bool("async", async),
+		zap.String("actor", requestActor(r)))
+
+	if async {
+		if scraper.IsBlocked(models.BlockedAcademy, academy.ExternalID) {
+			respondJSON(w, http.StatusConflict, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("%v: %s %s", scraper.ErrBlocked, models.BlockedAcademy, academy.ExternalID),
+			})
+			return
+		}
+		queued, ok := h.enqueue(w, queue.JobTypeAcademyResync, models.QueueParams{AcademyID: academy.ExternalID})
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusAccepted, models.APIResponse{
+			Success: true,
+			Message: "Academy resync queued",
+			Data:    map[string]interface{}{"queued": queued},
+		})
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(resyncWriteTimeout))
+	result, err := h.scraper.ResyncAcademy(r.Context(), academy)
+	if err != nil {
+		if errors.Is(err, scraper.ErrBlocked) {
+			respondJSON(w, http.StatusConflict, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		logger.Error("Failed to resync academy",
+			zap.String("academy_id", academy.ExternalID),
+			zap.Error(err))
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := academyResyncResponse{AcademyResyncResult: result}
+	if result.Academy != nil {
+		for _, source := range []string{result.Academy.LogoURL, result.Academy.CoverURL} {
+			if source == "" {
+				continue
+			}
+			refresh := mediaRefresh{SourceURL: source}
+			if asset, err := h.media.Fetch(source); err != nil {
+				refresh.Error = err.Error()
+			} else {
+				refresh.Hash = asset.Hash
+			}
+			response.Media = append(response.Media, refresh)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Academy resync completed",
+		Data:    response,
+	})
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RecomputeAcademyStats queues the aggregation of academy statistics (wins,
+// losses, athletes and medals) from the stored athletes and results, for
+// every academy or the one in ?academy_id= (external ID or slug)
+func (h *Handler) RecomputeAcademyStats(w http.ResponseWriter, r *http.Request) {
+	params := models.QueueParams{}
+	if key := r.URL.Query().Get("academy_id"); key != "" {
+		var academy models.Academy
+		if err := findByIDOrSlug(config.GetDB(), key, &academy); err != nil {
+			respondJSON(w, http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "Academy not found",
+			})
+			return
+		}
+		params.AcademyID = academy.ExternalID
+	}
+
+	logger.Info("Academy statistics recompute triggered", zap.String("academy_id", params.AcademyID))
+
+	queued, ok := h.enqueue(w, queue.JobTypeAcademyStats, params)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Academy statistics recompute queued",
+		Data:    queued,
+	})
+}
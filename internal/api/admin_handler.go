@@ -0,0 +1,274 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// LatencyReport lists the slowest routes and SQL statements of a day
+type LatencyReport struct {
+	Day      string                `json:"day"`
+	BudgetMs int64                 `json:"budget_ms"`
+	Routes   []models.RouteLatency `json:"routes"`
+	Queries  []models.SlowQuery    `json:"queries"`
+}
+
+// GetLatencyReport returns the daily report of slowest endpoints (by p95)
+// and slowest queries with their query plans. ?day=YYYY-MM-DD defaults to today.
+func (h *Handler) GetLatencyReport(w http.ResponseWriter, r *http.Request) {
+	day := r.URL.Query().Get("day")
+	if day == "" {
+		day = time.Now().UTC().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", day); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "day must be formatted as YYYY-MM-DD",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	tracker := metrics.Default()
+	if tracker != nil {
+		tracker.Flush()
+	}
+
+	db := config.GetDB()
+	report := LatencyReport{
+		Day:      day,
+		BudgetMs: h.config.Server.LatencyBudget.Milliseconds(),
+		Routes:   []models.RouteLatency{},
+		Queries:  []models.SlowQuery{},
+	}
+	db.Where("day = ?", day).Order("p95_ms DESC").Limit(limit).Find(&report.Routes)
+	db.Where("day = ?", day).Order("avg_ms DESC").Limit(limit).Find(&report.Queries)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Latency report retrieved successfully",
+		Data:    report,
+	})
+}
+
+// GetParseCoverage returns, per parsed record kind and field, how often the
+// field was found since startup. A drop points at the page section a site
+// redesign broke; GET /jobs/{id} has the same counters per job.
+func (h *Handler) GetParseCoverage(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Parse coverage retrieved successfully",
+		Data:    metrics.ParseCoverage(),
+	})
+}
+
+// GetJobRecording downloads the requests and responses recorded by a job run
+// with ?debug=true, as JSON Lines
+func (h *Handler) GetJobRecording(w http.ResponseWriter, r *http.Request) {
+	var job models.ScrapeJob
+	if err := config.GetDB().First(&job, mux.Vars(r)["id"]).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Job not found",
+		})
+		return
+	}
+
+	path := scraper.RecordingPath(h.config, &job)
+	if path == "" {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Job has no recording",
+		})
+		return
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Recording file not found",
+		})
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.Recording))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+// ConfigReloadResult lists what a configuration reload changed
+type ConfigReloadResult struct {
+	Changed   []string `json:"changed"`
+	LogLevel  string   `json:"log_level"`
+	Schedules int      `json:"schedules"`
+}
+
+// ReloadConfig re-reads .env and the environment and applies the settings that
+// are safe to change while jobs run (request delay, target countries, log
+// level, stale enrichment policy), then re-registers the stored schedules
+func (h *Handler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	changed, err := h.config.Reload()
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if slices.Contains(changed, "LOG_LEVEL") {
+		if err := logger.SetLevel(h.config.Latest().Logging.Level); err != nil {
+			logger.Warn("Keeping previous log level", zap.Error(err))
+		}
+	}
+	scraper.ApplyRequestDelay(h.config)
+
+	if err := h.scheduler.Reload(); err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Configuration applied but schedules failed to reload: " + err.Error(),
+		})
+		return
+	}
+
+	if changed == nil {
+		changed = []string{}
+	}
+	logger.Info("Configuration reloaded", zap.Strings("changed", changed))
+
+	var schedules int64
+	config.GetDB().Model(&models.ScheduleConfig{}).Where("enabled = ?", true).Count(&schedules)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Configuration reloaded successfully",
+		Data: ConfigReloadResult{
+			Changed:   changed,
+			LogLevel:  logger.Level(),
+			Schedules: int(schedules),
+		},
+	})
+}
+
+// LogLevelStatus is the current level of the service logger
+type LogLevelStatus struct {
+	Level        string     `json:"level"`
+	DefaultLevel string     `json:"default_level"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+func currentLogLevel() LogLevelStatus {
+	return LogLevelStatus{
+		Level:        logger.Level(),
+		DefaultLevel: logger.DefaultLevel(),
+		ExpiresAt:    logger.LevelExpiry(),
+	}
+}
+
+// GetLogLevel returns the current log level and when a temporary one expires
+func (h *Handler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Log level retrieved successfully",
+		Data:    currentLogLevel(),
+	})
+}
+
+// GetProxies reports the rotated proxies (SCRAPER_PROXIES) with their request
+// and failure counts and whether they are blacklisted
+func (h *Handler) GetProxies(w http.ResponseWriter, r *http.Request) {
+	proxies := scraper.GetProxyStatus(h.config)
+	if proxies == nil {
+		proxies = []scraper.ProxyStatus{}
+	}
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Proxies retrieved successfully",
+		Data:    proxies,
+	})
+}
+
+// ClearCrawlState forgets the visited pages and cookies the scraper stored,
+// so the next run fetches every club page again
+func (h *Handler) ClearCrawlState(w http.ResponseWriter, r *http.Request) {
+	removed, err := scraper.ClearCrawlState()
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Crawl state cleared",
+		zap.Int64("visits", removed),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Crawl state cleared",
+		Data:    map[string]int64{"visits_removed": removed},
+	})
+}
+
+// SetLogLevel switches the log level without a restart. Body:
+// {"level": "debug", "expires_in_seconds": 900}; with expires_in_seconds the
+// default LOG_LEVEL comes back afterwards, without it the level stays until
+// changed again.
+func (h *Handler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Level            string `json:"level"`
+		ExpiresInSeconds int    `json:"expires_in_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+	if input.ExpiresInSeconds < 0 {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "expires_in_seconds must not be negative",
+		})
+		return
+	}
+
+	ttl := time.Duration(input.ExpiresInSeconds) * time.Second
+	if err := logger.OverrideLevel(input.Level, ttl); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	logger.Info("Log level changed",
+		zap.String("level", input.Level),
+		zap.Duration("expires_in", ttl))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Log level updated successfully",
+		Data:    currentLogLevel(),
+	})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// ListAthleteAliases lists the athlete accounts merged on Smoothcomp, as
+// detected from profile redirects. ?athlete= narrows it to one kept account.
+func (h *Handler) ListAthleteAliases(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.AthleteAlias{})
+	if athlete := r.URL.Query().Get("athlete"); athlete != "" {
+		query = query.Where("external_id = ?", athlete)
+	}
+
+	aliases := []models.AthleteAlias{}
+	query.Order("id DESC").Find(&aliases)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete aliases retrieved successfully",
+		Data:    aliases,
+	})
+}
+
+// ListEventURLAliases lists former event URLs that now redirect, with the
+// canonical URL each resolves to. ?event= narrows it to one event ID.
+func (h *Handler) ListEventURLAliases(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.EventURLAlias{})
+	if event := r.URL.Query().Get("event"); event != "" {
+		query = query.Where("event_id = ?", event)
+	}
+
+	aliases := []models.EventURLAlias{}
+	query.Order("id DESC").Find(&aliases)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event URL aliases retrieved successfully",
+		Data:    aliases,
+	})
+}
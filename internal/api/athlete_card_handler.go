@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"image"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/athletecard"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// GetAthleteCard renders a share-able profile card of an athlete as PNG or
+// JPEG (by the extension of the route). ?layout= picks square (default),
+// story or landscape.
+func (h *Handler) GetAthleteCard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	layoutName := r.URL.Query().Get("layout")
+	if layoutName == "" {
+		layoutName = athletecard.DefaultLayout
+	}
+	layout, ok := athletecard.LookupLayout(layoutName)
+	if !ok {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "layout must be one of: " + strings.Join(athletecard.LayoutNames(), ", "),
+		})
+		return
+	}
+
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB().Preload("Academy"), vars["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+	h.privacy.MaskAthlete(&athlete)
+
+	format, contentType, ext := athletecard.PNG, "image/png", "png"
+	if vars["format"] != "png" {
+		format, contentType, ext = athletecard.JPEG, "image/jpeg", "jpg"
+	}
+
+	card := athletecard.FromAthlete(&athlete, h.athletePhoto(&athlete))
+	body, err := athletecard.Render(card, layout, format)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to render card",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("inline; filename=%q", fmt.Sprintf("athlete-%d-%s.%s", athlete.ID, layout.Name, ext)))
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// athletePhoto loads the photo of an athlete through the media cache; nil
+// when it has none or it cannot be fetched, so the card shows initials
+func (h *Handler) athletePhoto(athlete *models.Athlete) image.Image {
+	source := athlete.ImageURL
+	if source == "" {
+		source = athlete.AvatarURL
+	}
+	if source == "" {
+		return nil
+	}
+
+	asset, err := h.media.Get(source)
+	if err != nil {
+		logger.Debug("Athlete card without photo", zap.Int("athlete_id", athlete.ID), zap.Error(err))
+		return nil
+	}
+	file, err := os.Open(h.media.Path(asset.Hash))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	photo, err := athletecard.DecodePhoto(file)
+	if err != nil {
+		logger.Debug("Athlete card without photo", zap.Int("athlete_id", athlete.ID), zap.Error(err))
+		return nil
+	}
+	return photo
+}
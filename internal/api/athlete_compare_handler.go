@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// CompareAthletes returns aligned stats, common opponents and shared events
+// for 2 to 5 athletes given as ?ids=a,b,c (external IDs or slugs)
+func (h *Handler) CompareAthletes(w http.ResponseWriter, r *http.Request) {
+	var keys []string
+	for _, key := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) < 2 || len(keys) > analytics.MaxCompared {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("ids must list between 2 and %d athletes", analytics.MaxCompared),
+		})
+		return
+	}
+
+	db := config.GetDB()
+	athletes := make([]models.Athlete, 0, len(keys))
+	seen := make(map[int]bool, len(keys))
+	for _, key := range keys {
+		var athlete models.Athlete
+		if err := findByIDOrSlug(db, key, &athlete); err != nil {
+			respondJSON(w, http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Athlete %s not found", key),
+			})
+			return
+		}
+		if seen[athlete.ID] {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Athlete %s is listed more than once", key),
+			})
+			return
+		}
+		seen[athlete.ID] = true
+		athletes = append(athletes, athlete)
+	}
+
+	comparison, err := analytics.CompareAthletes(athletes)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compare athletes",
+		})
+		return
+	}
+	h.maskComparison(comparison)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete comparison retrieved successfully",
+		Data:    comparison,
+	})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// maxHistorySnapshots caps the snapshots returned by one history request
+const maxHistorySnapshots = 1000
+
+// athleteHistoryEntry is a stats snapshot with what changed since the
+// previous one
+type athleteHistoryEntry struct {
+	models.AthleteSnapshot
+	Changes       map[string]int `json:"changes,omitempty"`        // count deltas, e.g. "total_wins": 2
+	PreviousBelt  string         `json:"previous_belt,omitempty"`  // set when the belt changed
+	FirstSnapshot bool           `json:"first_snapshot,omitempty"` // no earlier snapshot to compare with
+}
+
+// GetAthleteHistory returns how the belt and win/loss record of an athlete
+// evolved, one entry per profile scrape that changed them, oldest first.
+// ?since= and ?until= (YYYY-MM-DD) bound the period and ?limit= (default 100)
+// keeps the latest entries.
+func (h *Handler) GetAthleteHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 100
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxHistorySnapshots {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "limit must be a number between 1 and 1000",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	var since, until time.Time
+	for _, bound := range []struct {
+		name string
+		dest *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   bound.name + " must be a date (YYYY-MM-DD)",
+			})
+			return
+		}
+		*bound.dest = parsed
+	}
+
+	db := config.GetDB()
+	var athlete models.Athlete
+	if err := findByIDOrSlug(db, mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	// One extra snapshot gives the first entry something to compare with
+	snapshots := db.Where("athlete_id = ?", athlete.ID)
+	if !until.IsZero() {
+		snapshots = snapshots.Where("captured_at < ?", until.AddDate(0, 0, 1))
+	}
+	var rows []models.AthleteSnapshot
+	if err := snapshots.Order("captured_at DESC, id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load athlete history",
+		})
+		return
+	}
+
+	entries := []athleteHistoryEntry{}
+	for i := len(rows) - 1; i >= 0; i-- {
+		if i == limit || rows[i].CapturedAt.Before(since) {
+			continue
+		}
+		entry := athleteHistoryEntry{AthleteSnapshot: rows[i]}
+		if i+1 < len(rows) {
+			entry.Changes, entry.PreviousBelt = snapshotChanges(rows[i+1], rows[i])
+		} else {
+			entry.FirstSnapshot = true
+		}
+		entries = append(entries, entry)
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete history retrieved successfully",
+		Data: map[string]interface{}{
+			"athlete_id": athlete.ExternalID,
+			"snapshots":  entries,
+		},
+	})
+}
+
+// snapshotChanges returns the count deltas between two snapshots and the
+// previous belt when it changed
+func snapshotChanges(before, after models.AthleteSnapshot) (map[string]int, string) {
+	counts := []struct {
+		name          string
+		before, after int
+	}{
+		{"total_wins", before.TotalWins, after.TotalWins},
+		{"wins_by_submission", before.WinsBySubmission, after.WinsBySubmission},
+		{"wins_by_points", before.WinsByPoints, after.WinsByPoints},
+		{"wins_by_decision", before.WinsByDecision, after.WinsByDecision},
+		{"wins_by_dq", before.WinsByDQ, after.WinsByDQ},
+		{"total_losses", before.TotalLosses, after.TotalLosses},
+		{"losses_by_submission", before.LossesBySubmission, after.LossesBySubmission},
+		{"losses_by_points", before.LossesByPoints, after.LossesByPoints},
+		{"losses_by_decision", before.LossesByDecision, after.LossesByDecision},
+		{"losses_by_dq", before.LossesByDQ, after.LossesByDQ},
+	}
+
+	changes := map[string]int{}
+	for _, count := range counts {
+		if count.after != count.before {
+			changes[count.name] = count.after - count.before
+		}
+	}
+
+	previousBelt := ""
+	if before.BeltRank != after.BeltRank {
+		previousBelt = before.BeltRank
+	}
+	return changes, previousBelt
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetAthleteRating returns the rating of an athlete (career win rate,
+// 0-100) and its history across profile snapshots. After a year without
+// events a rating drifts towards the average of the athlete's belt, halving
+// the gap every two years; decayed_rating applies that drift.
+func (h *Handler) GetAthleteRating(w http.ResponseWriter, r *http.Request) {
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	rating, err := analytics.AthleteRatingHistory(athlete, time.Now())
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute athlete rating",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete rating retrieved successfully",
+		Data:    rating,
+	})
+}
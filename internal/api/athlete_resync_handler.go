@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// resyncWriteTimeout replaces the server write timeout for a synchronous
+// resync, which pages through the whole profile history
+const resyncWriteTimeout = 2 * time.Minute
+
+// ResyncAthlete re-fetches the profile, registration history and win/loss
+// counts of one athlete. It waits for the result unless ?async=true, which
+// answers 202 with the queued athlete_resync job.
+func (h *Handler) ResyncAthlete(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	var athlete models.Athlete
+	if err := findByIDOrSlug(db, mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	logger.Info("Athlete resync triggered",
+		zap.String("athlete_id", athlete.ExternalID),
+		zap.Bool("async", async),
+		zap.String("actor", requestActor(r)))
+
+	if async {
+		if scraper.IsBlocked(models.BlockedAthlete, athlete.ExternalID) {
+			respondJSON(w, http.StatusConflict, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("%v: %s %s", scraper.ErrBlocked, models.BlockedAthlete, athlete.ExternalID),
+			})
+			return
+		}
+		queued, ok := h.enqueue(w, queue.JobTypeAthleteResync, models.QueueParams{AthleteID: athlete.ExternalID})
+		if !ok {
+			return
+		}
+		respondJSON(w, http.StatusAccepted, models.APIResponse{
+			Success: true,
+			Message: "Athlete resync queued",
+			Data:    map[string]interface{}{"queued": queued},
+		})
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(resyncWriteTimeout))
+	result, err := h.scraper.ResyncAthlete(r.Context(), athlete)
+	if err != nil {
+		if errors.Is(err, scraper.ErrBlocked) {
+			respondJSON(w, http.StatusConflict, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		logger.Error("Failed to resync athlete",
+			zap.String("athlete_id", athlete.ExternalID),
+			zap.Error(err))
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if result.Athlete != nil {
+		h.privacy.MaskAthlete(result.Athlete)
+	}
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete resync completed",
+		Data:    result,
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/milestones"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetAthleteStreaks returns the win and submission streaks of an athlete
+// computed from the stored bracket matches, and the milestones reached
+func (h *Handler) GetAthleteStreaks(w http.ResponseWriter, r *http.Request) {
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	streak, reached, err := milestones.Streak(uint(athlete.ID))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load athlete streaks",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete streaks retrieved successfully",
+		Data: map[string]interface{}{
+			"streaks":    streak,
+			"milestones": reached,
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+)
+
+// GetAthleteWeight returns the weight classes an athlete competed in, the
+// classes its typical weigh-in fits and the registrations with big cuts.
+// ?cut_percent= sets the share of the typical weight that counts as a big
+// cut (default 5).
+func (h *Handler) GetAthleteWeight(w http.ResponseWriter, r *http.Request) {
+	cutPercent := analytics.DefaultCutPercent
+	if raw := r.URL.Query().Get("cut_percent"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || parsed >= 50 {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "cut_percent must be a number between 0 and 50",
+			})
+			return
+		}
+		cutPercent = parsed
+	}
+
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	profile, err := analytics.AthleteWeight(athlete, cutPercent)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute weight analytics",
+		})
+		return
+	}
+	if h.privacy.MinorIDs([]uint{uint(athlete.ID)})[uint(athlete.ID)] {
+		profile.Name = privacy.Initials(profile.Name)
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete weight analytics retrieved successfully",
+		Data:    profile,
+	})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ListBlockedEntities returns the scraper blocklist, optionally filtered by ?type=
+func (h *Handler) ListBlockedEntities(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.BlockedEntity{})
+	if entityType := r.URL.Query().Get("type"); entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+
+	entries := []models.BlockedEntity{}
+	query.Order("created_at DESC").Find(&entries)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Blocklist retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// BlockEntity adds an athlete, event or academy to the blocklist so
+// scrapers stop requesting it
+func (h *Handler) BlockEntity(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		EntityType string `json:"entity_type"`
+		ExternalID string `json:"external_id"`
+		Reason     string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	input.ExternalID = strings.TrimSpace(input.ExternalID)
+	if !models.IsBlockedEntityType(input.EntityType) || input.ExternalID == "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "entity_type (athlete, event, academy) and external_id are required",
+		})
+		return
+	}
+
+	db := config.GetDB()
+	entry := models.BlockedEntity{
+		EntityType: input.EntityType,
+		ExternalID: input.ExternalID,
+	}
+	if err := db.Where(&entry).First(&entry).Error; err == nil {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "entity is already blocklisted",
+			Data:    entry,
+		})
+		return
+	}
+
+	entry.Reason = input.Reason
+	entry.Actor = requestActor(r)
+	if err := db.Create(&entry).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Entity blocklisted",
+		zap.String("entity_type", entry.EntityType),
+		zap.String("external_id", entry.ExternalID),
+		zap.String("actor", entry.Actor))
+
+	respondJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Entity blocklisted",
+		Data:    entry,
+	})
+}
+
+// UnblockEntity removes an entry from the blocklist
+func (h *Handler) UnblockEntity(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var entry models.BlockedEntity
+	if err := db.First(&entry, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Blocklist entry not found",
+		})
+		return
+	}
+
+	if err := db.Delete(&entry).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Entity removed from blocklist",
+		zap.String("entity_type", entry.EntityType),
+		zap.String("external_id", entry.ExternalID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Entity removed from blocklist",
+		Data:    entry,
+	})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/bracketsheet"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+)
+
+// ListBracketArchives lists the archived raw bracket versions of an event,
+// optionally filtered by ?division_id=
+func (h *Handler) ListBracketArchives(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Omit("payload").
+		Where("event_id = ?", mux.Vars(r)["id"]).
+		Order("division_id, fetched_at DESC")
+	if divisionID := r.URL.Query().Get("division_id"); divisionID != "" {
+		query = query.Where("division_id = ?", divisionID)
+	}
+
+	var archives []models.BracketArchive
+	if err := query.Find(&archives).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load bracket archives",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Bracket archives retrieved successfully",
+		Data:    archives,
+	})
+}
+
+// GetBracketArchive returns an archived bracket payload as it was fetched
+func (h *Handler) GetBracketArchive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var archive models.BracketArchive
+	if err := config.GetDB().Where("event_id = ? AND id = ?", vars["id"], vars["archiveId"]).First(&archive).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Bracket archive not found",
+		})
+		return
+	}
+
+	payload, err := scraper.BracketPayload(archive)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+archive.Hash+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(payload)
+}
+
+// GetBracketPDF renders a printable bracket sheet of one division, with the
+// matches, seeds and academies scraped so far and the division strength
+// index (also in the X-Division-Strength header)
+func (h *Handler) GetBracketPDF(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	sheet, err := bracketsheet.Load(vars["id"], vars["division"])
+	if errors.Is(err, bracketsheet.ErrNotFound) {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "No bracket data for this division; scrape its participants or brackets first",
+		})
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load bracket",
+		})
+		return
+	}
+	sheet.Mask(h.privacy.MinorIDs(sheet.AthleteIDs()))
+
+	body, err := bracketsheet.Render(sheet)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	if sheet.Strength != nil {
+		w.Header().Set("X-Division-Strength", strconv.FormatFloat(sheet.Strength.Index, 'f', 1, 64))
+	}
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("inline; filename=%q", fmt.Sprintf("bracket-%s-%s.pdf", sheet.EventID, sheet.DivisionID)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ScrapeAthletes queues the country roster pipeline for ?country=: it
+// discovers the past events held in the country, scrapes the participants
+// of each and builds the national athlete registry, tracked as one job.
+// ?resume=<job id> continues a failed run from its first unfinished stage.
+// Requests with only ?event_id= scrape the participants of that event.
+func (h *Handler) ScrapeAthletes(w http.ResponseWriter, r *http.Request) {
+	if raw := r.URL.Query().Get("resume"); raw != "" {
+		jobID, err := strconv.Atoi(raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "resume must be a job ID",
+			})
+			return
+		}
+
+		if err := h.scraper.CheckResumeCountryRoster(jobID); err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		logger.Info("Country roster scraping resumed", zap.Int("job_id", jobID))
+		queued, ok := h.enqueue(w, queue.JobTypeCountryRoster, models.QueueParams{ResumeJobID: jobID})
+		if !ok {
+			return
+		}
+
+		respondJSON(w, http.StatusAccepted, models.APIResponse{
+			Success: true,
+			Message: "Country roster resume queued",
+			Data: map[string]interface{}{
+				"job_id": jobID,
+				"queued": queued,
+			},
+		})
+		return
+	}
+
+	raw := r.URL.Query().Get("country")
+	if raw == "" && r.URL.Query().Get("event_id") != "" {
+		h.ScrapeEventAthletes(w, r)
+		return
+	}
+	country, err := scraper.NormalizeCountryCode(raw)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Country roster scraping triggered", zap.String("country", country))
+
+	queued, ok := h.enqueue(w, queue.JobTypeCountryRoster, models.QueueParams{Country: country})
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Country roster scraping queued",
+		Data: map[string]interface{}{
+			"country": country,
+			"queued":  queued,
+		},
+	})
+}
+
+// GetCountryRoster returns the national athlete registry of a country built
+// by the country roster pipeline, most recently active first
+func (h *Handler) GetCountryRoster(w http.ResponseWriter, r *http.Request) {
+	country, err := scraper.NormalizeCountryCode(mux.Vars(r)["code"])
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	query := config.GetDB().Model(&models.CountryRosterEntry{}).Where("country_code = ?", country)
+	var total int64
+	query.Count(&total)
+
+	entries := []models.CountryRosterEntry{}
+	if err := query.Order("last_event_at DESC, events DESC, id").
+		Offset((page - 1) * limit).Limit(limit).Find(&entries).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load country roster",
+		})
+		return
+	}
+	h.maskRoster(entries)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Country roster retrieved successfully",
+		Data: map[string]interface{}{
+			"country": country,
+			"entries": entries,
+			"page":    page,
+			"limit":   limit,
+			"total":   total,
+		},
+	})
+}
+
+// maskRoster anonymizes the minors of a roster page
+func (h *Handler) maskRoster(entries []models.CountryRosterEntry) {
+	if !h.privacy.Enabled() {
+		return
+	}
+
+	ids := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.AthleteID)
+	}
+	minors := h.privacy.MinorIDs(ids)
+
+	for i := range entries {
+		if minors[entries[i].AthleteID] {
+			entries[i].FullName = privacy.Initials(entries[i].FullName)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetLatestDigest returns the changes found by the most recent upcoming-events scrape
+func (h *Handler) GetLatestDigest(w http.ResponseWriter, r *http.Request) {
+	var digest models.EventDigest
+	err := config.GetDB().Preload("Items").Order("id DESC").First(&digest).Error
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "No digest available yet",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Digest retrieved successfully",
+		Data:    digest,
+	})
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetEventDivisions lists the divisions of an event with stored
+// registrations and their strength index, strongest first. ?division_id=
+// narrows to one division.
+func (h *Handler) GetEventDivisions(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["id"]
+
+	if divisionID := r.URL.Query().Get("division_id"); divisionID != "" {
+		strength, ok, err := analytics.LoadDivisionStrength(eventID, divisionID)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "Failed to compute division strength",
+			})
+			return
+		}
+		if !ok {
+			respondJSON(w, http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "Division not found; scrape the event participants first",
+			})
+			return
+		}
+		respondJSON(w, http.StatusOK, models.APIResponse{
+			Success: true,
+			Message: "Event divisions retrieved successfully",
+			Data:    []analytics.DivisionStrength{strength},
+		})
+		return
+	}
+
+	strengths, err := analytics.EventDivisionStrengths(eventID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute division strength",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event divisions retrieved successfully",
+		Data:    strengths,
+	})
+}
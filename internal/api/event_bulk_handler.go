@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ScrapeEventsBulk queues details, participants and results scrapes for a
+// list of event IDs or URLs, returning the events read and the queued job.
+// Accepts the ?max_duration= and ?resume= of other scrape jobs.
+func (h *Handler) ScrapeEventsBulk(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Events []string `json:"events"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	events, invalid := scraper.ParseBulkEvents(input.Events)
+	if len(invalid) > 0 {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "events must be event IDs or event URLs",
+			Data:    map[string]interface{}{"invalid": invalid},
+		})
+		return
+	}
+	if len(events) == 0 {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "events is required",
+		})
+		return
+	}
+	if len(events) > scraper.MaxBulkEvents {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("at most %d events per request", scraper.MaxBulkEvents),
+		})
+		return
+	}
+
+	opts, err := parseRunOptions(r, "events_bulk")
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Bulk event scraping triggered",
+		zap.Int("events", len(events)),
+		zap.Duration("max_duration", opts.MaxDuration))
+
+	params := queueRunParams(r, opts)
+	for _, event := range events {
+		params.Events = append(params.Events, event.Input)
+	}
+	queued, ok := h.enqueue(w, queue.JobTypeEventsBulk, params)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Bulk event scraping queued",
+		Data: map[string]interface{}{
+			"events": events,
+			"queued": queued,
+		},
+	})
+}
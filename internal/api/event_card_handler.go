@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DownloadEventCards streams a zip with the credential PDF of every
+// registration of an event, for check-in desks. ?division_id= narrows to
+// one division.
+func (h *Handler) DownloadEventCards(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["id"]
+	divisionID := r.URL.Query().Get("division_id")
+
+	registrations, err := scraper.EventCardRegistrations(eventID, divisionID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load registrations",
+		})
+		return
+	}
+	if len(registrations) == 0 {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "No event cards for this event; scrape its participants first",
+		})
+		return
+	}
+
+	// Downloading hundreds of cards outlasts the server write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Debug("Could not lift write deadline", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "event-cards-"+eventID+".zip"))
+	w.WriteHeader(http.StatusOK)
+
+	// Headers are sent; from here errors can only be logged
+	summary, err := h.scraper.WriteEventCards(r.Context(), registrations, w)
+	if err != nil {
+		logger.Error("Event card download aborted",
+			zap.String("event_id", eventID),
+			zap.Int("written", summary.Written),
+			zap.Error(err))
+		return
+	}
+
+	logger.Info("Event cards downloaded",
+		zap.String("event_id", eventID),
+		zap.String("actor", requestActor(r)),
+		zap.Int("written", summary.Written),
+		zap.Int("failed", summary.Failed))
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+)
+
+// GetEventInfo returns all typed info panels stored for an event
+func (h *Handler) GetEventInfo(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["id"]
+
+	info, err := scraper.LoadEventInfo(eventID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event info retrieved successfully",
+		Data:    info,
+	})
+}
+
+// GetEventInfoPanel returns a single typed info panel of an event
+func (h *Handler) GetEventInfoPanel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	eventID := vars["id"]
+	panel := vars["panel"]
+
+	info, err := scraper.LoadEventInfo(eventID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var data interface{}
+	found := true
+	switch panel {
+	case "venue":
+		data, found = info.Venue, info.Venue != nil
+	case "contact":
+		data, found = info.Contact, info.Contact != nil
+	case "rules":
+		data, found = info.Rules, info.Rules != nil
+	case "schedule":
+		data = info.Schedule
+	case "registration-windows":
+		data = info.RegistrationWindows
+	default:
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Unknown info panel: " + panel,
+		})
+		return
+	}
+
+	if !found {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Info panel not found",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event info panel retrieved successfully",
+		Data:    data,
+	})
+}
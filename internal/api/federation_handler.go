@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/federation"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxFederationImportBytes caps the body of a federation ID import
+const maxFederationImportBytes = 10 << 20
+
+// ImportFederationIDs links athletes to official federation IDs. The body
+// is a CSV (Content-Type text/csv, ?federation= for files without a
+// federation column) or JSON {"federation", "rows": [...]}; ?dry_run=true
+// reports the matches without storing them.
+func (h *Handler) ImportFederationIDs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFederationImportBytes)
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	defaultFederation := r.URL.Query().Get("federation")
+
+	var rows []federation.Row
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		parsed, err := federation.ParseCSV(r.Body, defaultFederation)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		rows = parsed
+	default:
+		var input struct {
+			Federation string           `json:"federation"`
+			Rows       []federation.Row `json:"rows"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request body",
+			})
+			return
+		}
+		if input.Federation == "" {
+			input.Federation = defaultFederation
+		}
+		for i := range input.Rows {
+			if input.Rows[i].Federation == "" {
+				input.Rows[i].Federation = input.Federation
+			}
+		}
+		rows = input.Rows
+	}
+
+	if len(rows) == 0 {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "No rows to import",
+		})
+		return
+	}
+
+	report, err := federation.Import(rows, dryRun, requestActor(r))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "Federation IDs imported"
+	if dryRun {
+		message = "Federation ID import checked (dry run)"
+	}
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// ListFederationIDs returns federation ID links, filtered by ?federation=
+// and ?athlete= (Smoothcomp external ID)
+func (h *Handler) ListFederationIDs(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.FederationID{})
+	if name := r.URL.Query().Get("federation"); name != "" {
+		query = query.Where("federation = ?", name)
+	}
+	if athlete := r.URL.Query().Get("athlete"); athlete != "" {
+		query = query.Where("athlete_external_id = ?", athlete)
+	}
+
+	links := []models.FederationID{}
+	query.Order("federation, federation_athlete_id").Find(&links)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Federation IDs retrieved successfully",
+		Data:    links,
+	})
+}
+
+// DeleteFederationID removes a federation ID link, e.g. a wrong name match
+func (h *Handler) DeleteFederationID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var link models.FederationID
+	if err := db.First(&link, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Federation ID not found",
+		})
+		return
+	}
+
+	if err := db.Delete(&link).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Federation ID unlinked",
+		zap.String("federation", link.Federation),
+		zap.String("federation_athlete_id", link.FederationAthleteID),
+		zap.String("athlete_external_id", link.AthleteExternalID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Federation ID removed",
+		Data:    link,
+	})
+}
+
+// GetAthleteByFederationID returns the athlete linked to an IBJJF or AJP ID
+func (h *Handler) GetAthleteByFederationID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, ok := models.ParseFederation(vars["federation"])
+	if !ok {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "federation must be ibjjf or ajp",
+		})
+		return
+	}
+
+	athlete, err := federation.FindAthlete(name, vars["federationId"])
+	if err != nil {
+		status, message := http.StatusInternalServerError, err.Error()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status, message = http.StatusNotFound, "Athlete not found"
+		}
+		respondJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   message,
+		})
+		return
+	}
+	h.privacy.MaskAthlete(athlete)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete retrieved successfully",
+		Data:    athlete,
+	})
+}
@@ -2,6 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,24 +12,44 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/filterdsl"
+	"github.com/kmicac/smoothcomp-scraper/internal/live"
+	"github.com/kmicac/smoothcomp-scraper/internal/media"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/openapi"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
 	"github.com/kmicac/smoothcomp-scraper/internal/scheduler"
 	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/internal/youtube"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
 	config    *config.Config
 	scheduler *scheduler.Scheduler
 	scraper   *scraper.Scraper
+	queue     *queue.Queue
+	media     *media.Store
+	youtube   *youtube.Linker
+	privacy   *privacy.Policy
+	live      *live.Ingestor
+	openapi   *openapi.Document // built by NewRouter once every route is registered
 }
 
-func NewHandler(cfg *config.Config, sched *scheduler.Scheduler) *Handler {
+func NewHandler(cfg *config.Config, sched *scheduler.Scheduler, jobs *queue.Queue, ingestor *live.Ingestor) *Handler {
 	return &Handler{
 		config:    cfg,
 		scheduler: sched,
 		scraper:   scraper.NewScraper(cfg),
+		queue:     jobs,
+		media:     media.NewStore(cfg),
+		youtube:   youtube.NewLinker(cfg),
+		privacy:   privacy.NewPolicy(cfg),
+		live:      ingestor,
 	}
 }
 
@@ -45,9 +68,13 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 
-	// Get schedule config
-	var scheduleConfig models.ScheduleConfig
-	db.First(&scheduleConfig)
+	var schedules []models.ScheduleConfig
+	db.Order("id ASC").Find(&schedules)
+	h.scheduler.Describe(schedules)
+	scheduleEnabled := false
+	for _, schedule := range schedules {
+		scheduleEnabled = scheduleEnabled || schedule.Enabled
+	}
 
 	// Get total counts
 	var totalAcademies, totalAthletes int64
@@ -70,10 +97,12 @@ func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		LastRun:         lastRun,
 		NextRun:         nextRun,
 		IsRunning:       h.scheduler.IsRunning(),
-		ScheduleEnabled: scheduleConfig.Enabled,
-		CronExpression:  scheduleConfig.CronExpr,
+		ScheduleEnabled: scheduleEnabled,
+		Schedules:       schedules,
 		TotalAcademies:  totalAcademies,
 		TotalAthletes:   totalAthletes,
+		HTTPConnections: scraper.GetConnectionStats(),
+		FetchCache:      metrics.FetchCache(),
 	}
 
 	respondJSON(w, http.StatusOK, models.APIResponse{
@@ -87,36 +116,67 @@ func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ScrapeAcademies(w http.ResponseWriter, r *http.Request) {
 	logger.Info("Manual academy scraping triggered")
 
-	go func() {
-		if err := h.scraper.ScrapeAcademies(); err != nil {
-			logger.Error("Failed to scrape academies", zap.Error(err))
-		}
-	}()
+	queued, ok := h.enqueue(w, queue.JobTypeAcademies, models.QueueParams{})
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Academy scraping started",
+		Message: "Academy scraping queued",
+		Data:    queued,
 	})
 }
 
-// ScrapeAthletes triggers manual athlete scraping
-func (h *Handler) ScrapeAthletes(w http.ResponseWriter, r *http.Request) {
-	h.ScrapeEventAthletes(w, r)
-}
-
-// ScrapeAll triggers scraping of both academies and athletes
+// ScrapeAll triggers the full scraping pipeline. ?resume=<job id> continues
+// a failed run from its first unfinished stage.
 func (h *Handler) ScrapeAll(w http.ResponseWriter, r *http.Request) {
-	logger.Info("Manual full scraping triggered")
+	if raw := r.URL.Query().Get("resume"); raw != "" {
+		jobID, err := strconv.Atoi(raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "resume must be a job ID",
+			})
+			return
+		}
+
+		if err := h.scraper.CheckResumeScrapeAll(jobID); err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
 
-	go func() {
-		if err := h.scraper.ScrapeAll(); err != nil {
-			logger.Error("Failed to scrape all", zap.Error(err))
+		logger.Info("Manual full scraping resumed", zap.Int("job_id", jobID))
+		queued, ok := h.enqueue(w, queue.JobTypeAll, models.QueueParams{ResumeJobID: jobID})
+		if !ok {
+			return
 		}
-	}()
+
+		respondJSON(w, http.StatusAccepted, models.APIResponse{
+			Success: true,
+			Message: "Full scraping resume queued",
+			Data: map[string]interface{}{
+				"job_id": jobID,
+				"queued": queued,
+			},
+		})
+		return
+	}
+
+	logger.Info("Manual full scraping triggered")
+
+	queued, ok := h.enqueue(w, queue.JobTypeAll, models.QueueParams{})
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Full scraping started",
+		Message: "Full scraping queued",
+		Data:    queued,
 	})
 }
 
@@ -127,20 +187,49 @@ func (h *Handler) ScrapePastEvents(w http.ResponseWriter, r *http.Request) {
 		country = "AR"
 	}
 
-	logger.Info("Manual past events scraping triggered",
-		zap.String("country", country))
+	rawDepth := r.URL.Query().Get("depth")
+	opts, err := parseRunOptions(r, "events_past")
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if opts.Resume != nil {
+		country, rawDepth = opts.Resume.Country, opts.Resume.Depth
+	}
 
-	go func() {
-		if err := h.scraper.ScrapeEvents("past", country); err != nil {
-			logger.Error("Failed to scrape past events", zap.Error(err))
-		}
-	}()
+	depth, err := scraper.ParseDepth(rawDepth)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Manual past events scraping triggered",
+		zap.String("country", country),
+		zap.String("depth", depth.String()),
+		zap.Duration("max_duration", opts.MaxDuration))
+
+	params := queueRunParams(r, opts)
+	params.Country, params.Depth = country, depth.String()
+	queued, ok := h.enqueue(w, queue.JobTypeEventsPast, params)
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Past events scraping started",
-		Data: map[string]string{
-			"country": country,
+		Message: "Past events scraping queued",
+		Data: map[string]interface{}{
+			"country":      country,
+			"depth":        depth.String(),
+			"max_duration": int(opts.MaxDuration.Seconds()),
+			"resumed":      opts.Resume != nil,
+			"queued":       queued,
 		},
 	})
 }
@@ -152,25 +241,56 @@ func (h *Handler) ScrapeUpcomingEvents(w http.ResponseWriter, r *http.Request) {
 		country = "AR"
 	}
 
-	logger.Info("Manual upcoming events scraping triggered",
-		zap.String("country", country))
+	rawDepth := r.URL.Query().Get("depth")
+	opts, err := parseRunOptions(r, "events_upcoming")
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if opts.Resume != nil {
+		country, rawDepth = opts.Resume.Country, opts.Resume.Depth
+	}
 
-	go func() {
-		if err := h.scraper.ScrapeEvents("upcoming", country); err != nil {
-			logger.Error("Failed to scrape upcoming events", zap.Error(err))
-		}
-	}()
+	depth, err := scraper.ParseDepth(rawDepth)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Manual upcoming events scraping triggered",
+		zap.String("country", country),
+		zap.String("depth", depth.String()),
+		zap.Duration("max_duration", opts.MaxDuration))
+
+	params := queueRunParams(r, opts)
+	params.Country, params.Depth = country, depth.String()
+	queued, ok := h.enqueue(w, queue.JobTypeEventsUpcoming, params)
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Upcoming events scraping started",
-		Data: map[string]string{
-			"country": country,
+		Message: "Upcoming events scraping queued",
+		Data: map[string]interface{}{
+			"country":      country,
+			"depth":        depth.String(),
+			"max_duration": int(opts.MaxDuration.Seconds()),
+			"resumed":      opts.Resume != nil,
+			"queued":       queued,
 		},
 	})
 }
 
-// GetAcademies returns all academies with pagination
+// GetAcademies returns all academies with pagination, or every one with
+// ?all=true, see streamList. Supports conditional requests (ETag /
+// If-Modified-Since), see notModified.
 func (h *Handler) GetAcademies(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 
@@ -194,6 +314,18 @@ func (h *Handler) GetAcademies(w http.ResponseWriter, r *http.Request) {
 	if country != "" {
 		query = query.Where("country_code = ?", country)
 	}
+	query, ok := h.filterByTags(w, r, query, models.TaggedAcademy)
+	if !ok {
+		return
+	}
+
+	if notModified(w, r, query) {
+		return
+	}
+	if wantsAll(r) {
+		streamList[models.Academy](w, r, query, "academies", "Academies retrieved successfully", nil)
+		return
+	}
 
 	// Get total count
 	var total int64
@@ -215,7 +347,7 @@ func (h *Handler) GetAcademies(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetAcademyByID returns a specific academy
+// GetAcademyByID returns a specific academy by external ID or slug
 func (h *Handler) GetAcademyByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -223,13 +355,14 @@ func (h *Handler) GetAcademyByID(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 	var academy models.Academy
 
-	if err := db.Where("external_id = ?", id).Preload("Athletes").First(&academy).Error; err != nil {
+	if err := findByIDOrSlug(db.Preload("Athletes"), id, &academy); err != nil {
 		respondJSON(w, http.StatusNotFound, models.APIResponse{
 			Success: false,
 			Error:   "Academy not found",
 		})
 		return
 	}
+	h.privacy.MaskAthletes(academy.Athletes)
 
 	respondJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
@@ -238,7 +371,17 @@ func (h *Handler) GetAcademyByID(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetAthletes returns all athletes with pagination
+// GetAthletes returns all athletes with pagination. Besides ?gender=, athletes
+// can be filtered by the divisions they registered in: ?weight_class= (any
+// label, e.g. "-167.5 lbs" or "Pesado", normalized), ?weight_min_kg= and
+// ?weight_max_kg= (the class limit in kg) and ?style= (gi or nogi), all
+// matched against the same registration, their latest one with
+// ?registration=latest. ?belt_rank=, ?age_min=, ?age_max= and ?name= filter
+// by profile, see filterByProfile, and ?sort= picks the order (wins, the
+// default, win_rate or recently_scraped). ?filter= takes an expression over
+// the athlete fields, see applyFilter. ?all=true streams every match instead
+// of a page, in ID order, see streamList. Supports conditional requests, see
+// notModified.
 func (h *Handler) GetAthletes(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 
@@ -254,6 +397,7 @@ func (h *Handler) GetAthletes(w http.ResponseWriter, r *http.Request) {
 
 	country := r.URL.Query().Get("country")
 	academyID := r.URL.Query().Get("academy_id")
+	gender, _ := models.ParseGender(r.URL.Query().Get("gender"))
 
 	offset := (page - 1) * limit
 
@@ -264,12 +408,67 @@ func (h *Handler) GetAthletes(w http.ResponseWriter, r *http.Request) {
 	if academyID != "" {
 		query = query.Where("academy_external_id = ?", academyID)
 	}
+	if gender != models.GenderUnknown {
+		query = query.Where("gender = ?", gender)
+	}
+	for _, filter := range []struct{ param, condition string }{
+		{"active_since", "last_active_at >= ?"},
+		{"active_until", "last_active_at < ?"},
+		{"first_seen_since", "first_seen_at >= ?"},
+	} {
+		raw := r.URL.Query().Get(filter.param)
+		if raw == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   filter.param + " must be a date (YYYY-MM-DD)",
+			})
+			return
+		}
+		query = query.Where(filter.condition, date)
+	}
+	query, ok := h.filterByTags(w, r, query, models.TaggedAthlete)
+	if !ok {
+		return
+	}
+	query, ok = filterByDivision(w, r, query)
+	if !ok {
+		return
+	}
+	query, ok = filterByProfile(w, r, query)
+	if !ok {
+		return
+	}
+	query, ok = applyFilter(w, r, query, filterdsl.Athletes)
+	if !ok {
+		return
+	}
+	order, ok := athleteSorts[r.URL.Query().Get("sort")]
+	if !ok {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "sort must be wins, win_rate or recently_scraped",
+		})
+		return
+	}
+	// The listing embeds each athlete's academy
+	if notModified(w, r, query, db.Model(&models.Academy{})) {
+		return
+	}
+	if wantsAll(r) {
+		streamList(w, r, query.Preload("Academy"), "athletes", "Athletes retrieved successfully", h.privacy.MaskAthletes)
+		return
+	}
 
 	var total int64
 	query.Count(&total)
 
 	var athletes []models.Athlete
-	query.Offset(offset).Limit(limit).Preload("Academy").Order("total_wins DESC").Find(&athletes)
+	query.Offset(offset).Limit(limit).Preload("Academy").Order(order).Find(&athletes)
+	h.privacy.MaskAthletes(athletes)
 
 	respondJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
@@ -283,7 +482,156 @@ func (h *Handler) GetAthletes(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetEvents returns all events with pagination
+// athleteSorts are the ?sort= orders of GetAthletes; athletes without
+// matches come last by win rate
+var athleteSorts = map[string]string{
+	"":                 "total_wins DESC, id",
+	"wins":             "total_wins DESC, id",
+	"win_rate":         "CASE WHEN total_wins + total_losses > 0 THEN total_wins * 1.0 / (total_wins + total_losses) ELSE -1 END DESC, total_wins DESC, id",
+	"recently_scraped": "scraped_at DESC, id",
+}
+
+// filterByProfile narrows an athlete query by the profile parameters of
+// GetAthletes: ?belt_rank= (one or more comma separated, "blue" or "Blue
+// belt"), ?age_min= and ?age_max= (athletes of unknown age are left out) and
+// ?name=, a prefix of the full or last name. ok is false after answering 400
+// to an invalid one.
+func filterByProfile(w http.ResponseWriter, r *http.Request, query *gorm.DB) (*gorm.DB, bool) {
+	params := r.URL.Query()
+
+	if raw := params.Get("belt_rank"); raw != "" {
+		var belts []string
+		for _, belt := range strings.Split(raw, ",") {
+			belts = append(belts, beltRank(belt))
+		}
+		query = query.Where("belt_rank IN ?", belts)
+	}
+	for _, bound := range []struct{ param, condition string }{
+		{"age_min", "age >= ?"},
+		{"age_max", "age > 0 AND age <= ?"},
+	} {
+		raw := params.Get(bound.param)
+		if raw == "" {
+			continue
+		}
+		age, err := strconv.Atoi(raw)
+		if err != nil || age < 0 {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   bound.param + " must be a non-negative number of years",
+			})
+			return nil, false
+		}
+		query = query.Where(bound.condition, age)
+	}
+	if name := strings.ToLower(strings.TrimSpace(params.Get("name"))); name != "" {
+		prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(name) + "%"
+		query = query.Where(`LOWER(full_name) LIKE ? ESCAPE '\' OR LOWER(last_name) LIKE ? ESCAPE '\'`, prefix, prefix)
+	}
+	return query, true
+}
+
+// beltRank spells a belt the way athlete profiles store it: "blue" and "BLUE
+// BELT" become "Blue belt". Other ranks are kept as given.
+func beltRank(belt string) string {
+	words := strings.Fields(strings.ToLower(belt))
+	if len(words) == 0 || len(words) > 2 || (len(words) == 2 && words[1] != "belt") {
+		return strings.TrimSpace(belt)
+	}
+	switch words[0] {
+	case "white", "blue", "purple", "brown", "black":
+		return strings.ToUpper(words[0][:1]) + words[0][1:] + " belt"
+	}
+	return strings.TrimSpace(belt)
+}
+
+// applyFilter narrows query with the ?filter= expression, e.g.
+// belt == "Black belt" && total_wins > 20 && country in ["BR", "CL"], over
+// the given fields; ok is false after answering 400 to an invalid one
+func applyFilter(w http.ResponseWriter, r *http.Request, query *gorm.DB, fields filterdsl.Fields) (*gorm.DB, bool) {
+	query, err := filterdsl.Apply(query, r.URL.Query().Get("filter"), fields)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	return query, true
+}
+
+// filterByDivision narrows an athlete query to those with a registration
+// matching the weight class and style parameters of GetAthletes; ok is false
+// after answering 400 to an invalid one
+func filterByDivision(w http.ResponseWriter, r *http.Request, query *gorm.DB) (*gorm.DB, bool) {
+	params := r.URL.Query()
+	registrations := config.GetDB().Model(&models.EventRegistration{}).Select("athlete_id")
+	filtered := false
+	invalid := func(message string) (*gorm.DB, bool) {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   message,
+		})
+		return nil, false
+	}
+	latest := false
+	switch params.Get("registration") {
+	case "", "any":
+	case "latest":
+		latest = true
+	default:
+		return invalid("registration must be any or latest")
+	}
+
+	if raw := params.Get("weight_class"); raw != "" {
+		class := models.ParseWeightClass(raw)
+		if class.Key == "" {
+			return invalid("unrecognized weight_class " + strconv.Quote(raw))
+		}
+		registrations = registrations.Where("weight_class_key = ?", class.Key)
+		filtered = true
+	}
+	for _, bound := range []struct{ param, condition string }{
+		{"weight_min_kg", "CASE WHEN weight_max_kg > 0 THEN weight_max_kg ELSE weight_min_kg END >= ?"},
+		{"weight_max_kg", "CASE WHEN weight_max_kg > 0 THEN weight_max_kg ELSE weight_min_kg END <= ?"},
+	} {
+		raw := params.Get(bound.param)
+		if raw == "" {
+			continue
+		}
+		kg, err := strconv.ParseFloat(raw, 64)
+		if err != nil || kg <= 0 {
+			return invalid(bound.param + " must be a positive number of kg")
+		}
+		registrations = registrations.Where("(weight_max_kg > 0 OR weight_min_kg > 0) AND "+bound.condition, kg)
+		filtered = true
+	}
+	if raw := params.Get("style"); raw != "" {
+		style := models.ParseStyle(raw)
+		if style == models.StyleUnknown {
+			return invalid("style must be gi or nogi")
+		}
+		registrations = registrations.Where("style = ?", style)
+		filtered = true
+	}
+
+	if !filtered {
+		return query, true
+	}
+	if latest {
+		// The registration of the athlete's most recent event, by its start day
+		registrations = registrations.Where(`event_registrations.id = (SELECT latest.id FROM event_registrations latest
+			LEFT JOIN events ON events.external_id = latest.event_id
+			WHERE latest.athlete_id = event_registrations.athlete_id
+			ORDER BY COALESCE(events.start_date, latest.registration_date) DESC, latest.id DESC LIMIT 1)`)
+	}
+	return query.Where("id IN (?)", registrations), true
+}
+
+// GetEvents returns all events with pagination. ?filter= takes an
+// expression over the event fields, see applyFilter. ?all=true streams every
+// match instead of a page, see streamList. Supports conditional requests, see
+// notModified.
 func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 
@@ -309,6 +657,17 @@ func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
 	if country != "" {
 		query = query.Where("country_code = ? OR country = ?", strings.ToUpper(country), country)
 	}
+	query, ok := applyFilter(w, r, query, filterdsl.Events)
+	if !ok {
+		return
+	}
+	if notModified(w, r, query) {
+		return
+	}
+	if wantsAll(r) {
+		streamList[models.Event](w, r, query, "events", "Events retrieved successfully", nil)
+		return
+	}
 
 	var total int64
 	query.Count(&total)
@@ -351,7 +710,7 @@ func (h *Handler) GetEventByID(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetAthleteByID returns a specific athlete
+// GetAthleteByID returns a specific athlete by external ID or slug
 func (h *Handler) GetAthleteByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -359,13 +718,14 @@ func (h *Handler) GetAthleteByID(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 	var athlete models.Athlete
 
-	if err := db.Where("external_id = ?", id).Preload("Academy").First(&athlete).Error; err != nil {
+	if err := findByIDOrSlug(db.Preload("Academy").Preload("FederationIDs"), id, &athlete); err != nil {
 		respondJSON(w, http.StatusNotFound, models.APIResponse{
 			Success: false,
 			Error:   "Athlete not found",
 		})
 		return
 	}
+	h.privacy.MaskAthlete(&athlete)
 
 	respondJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
@@ -374,59 +734,6 @@ func (h *Handler) GetAthleteByID(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetScheduleConfig returns the current schedule configuration
-func (h *Handler) GetScheduleConfig(w http.ResponseWriter, r *http.Request) {
-	db := config.GetDB()
-	var scheduleConfig models.ScheduleConfig
-	db.First(&scheduleConfig)
-
-	respondJSON(w, http.StatusOK, models.APIResponse{
-		Success: true,
-		Message: "Schedule config retrieved successfully",
-		Data:    scheduleConfig,
-	})
-}
-
-// UpdateScheduleConfig updates the schedule configuration
-func (h *Handler) UpdateScheduleConfig(w http.ResponseWriter, r *http.Request) {
-	var input struct {
-		CronExpr string `json:"cron_expr"`
-		Enabled  bool   `json:"enabled"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondJSON(w, http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "Invalid request body",
-		})
-		return
-	}
-
-	db := config.GetDB()
-	var scheduleConfig models.ScheduleConfig
-	db.First(&scheduleConfig)
-
-	scheduleConfig.CronExpr = input.CronExpr
-	scheduleConfig.Enabled = input.Enabled
-
-	if err := db.Save(&scheduleConfig).Error; err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Error:   "Failed to update schedule config",
-		})
-		return
-	}
-
-	// Restart scheduler with new config
-	h.scheduler.UpdateSchedule(input.CronExpr)
-
-	respondJSON(w, http.StatusOK, models.APIResponse{
-		Success: true,
-		Message: "Schedule config updated successfully",
-		Data:    scheduleConfig,
-	})
-}
-
 // GetJobs returns scraping job history
 func (h *Handler) GetJobs(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
@@ -469,7 +776,10 @@ func (h *Handler) GetJobByID(w http.ResponseWriter, r *http.Request) {
 	db := config.GetDB()
 	var job models.ScrapeJob
 
-	if err := db.First(&job, id).Error; err != nil {
+	err := db.Preload("Coverage", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("record, field")
+	}).First(&job, id).Error
+	if err != nil {
 		respondJSON(w, http.StatusNotFound, models.APIResponse{
 			Success: false,
 			Error:   "Job not found",
@@ -484,6 +794,111 @@ func (h *Handler) GetJobByID(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// CancelJob stops a running job. Stage and chunk jobs cancel the job they
+// belong to; the job records "cancelled" once its current request returns.
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	var job models.ScrapeJob
+	if err := config.GetDB().First(&job, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Job not found",
+		})
+		return
+	}
+
+	cancelled, ok := scraper.CancelJob(job.ID)
+	if !ok {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("job %d is %s, not running", job.ID, job.Status),
+		})
+		return
+	}
+
+	logger.Info("Job cancellation requested",
+		zap.Int("job_id", job.ID),
+		zap.Int("cancelled_job_id", cancelled),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Job cancellation requested",
+		Data:    map[string]int{"job_id": job.ID, "cancelled_job_id": cancelled},
+	})
+}
+
+// GetJobProgress reports the phase of a job, how many of its items are done
+// and an estimate of the time left, with the progress of its child jobs
+func (h *Handler) GetJobProgress(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var job models.ScrapeJob
+	if err := db.First(&job, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Job not found",
+		})
+		return
+	}
+
+	var children []models.ScrapeJob
+	db.Where("parent_job_id = ?", job.ID).Order("id").Find(&children)
+
+	progress := jobProgress(job)
+	for _, child := range children {
+		progress.Children = append(progress.Children, jobProgress(child))
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Job progress retrieved successfully",
+		Data:    progress,
+	})
+}
+
+// jobProgress summarizes one job. The ETA assumes the remaining items of the
+// current phase go at the pace of the job so far.
+func jobProgress(job models.ScrapeJob) models.JobProgress {
+	progress := models.JobProgress{
+		JobID:          job.ID,
+		JobType:        job.JobType,
+		Status:         job.Status,
+		CurrentPhase:   job.CurrentPhase,
+		ItemsTotal:     job.ItemsTotal,
+		ItemsProcessed: job.ItemsProcessed,
+		ItemsScraped:   job.ItemsScraped,
+	}
+
+	end := time.Now()
+	if job.CompletedAt != nil {
+		end = *job.CompletedAt
+	}
+	elapsed := end.Sub(job.StartedAt)
+	if job.Status != "pending" {
+		progress.ElapsedSeconds = int(elapsed.Seconds())
+	}
+
+	switch {
+	case job.Status == "completed":
+		percent := 100.0
+		progress.Percent = &percent
+	case job.ItemsTotal > 0:
+		percent := math.Round(float64(job.ItemsProcessed)/float64(job.ItemsTotal)*1000) / 10
+		progress.Percent = &percent
+	}
+
+	if job.Status == "running" && job.ItemsProcessed > 0 && job.ItemsTotal > job.ItemsProcessed {
+		left := elapsed.Seconds() / float64(job.ItemsProcessed) * float64(job.ItemsTotal-job.ItemsProcessed)
+		eta := int(math.Ceil(left))
+		progress.ETASeconds = &eta
+	}
+
+	return progress
+}
+
 // ScrapeEventAthletes triggers scraping of athletes from a specific event
 func (h *Handler) ScrapeEventAthletes(w http.ResponseWriter, r *http.Request) {
 	eventID := r.URL.Query().Get("event_id")
@@ -498,6 +913,14 @@ func (h *Handler) ScrapeEventAthletes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if scraper.IsBlocked(models.BlockedEvent, eventID) {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "event is blocklisted",
+		})
+		return
+	}
+
 	if eventName == "" {
 		eventName = "Event " + eventID
 	}
@@ -507,19 +930,101 @@ func (h *Handler) ScrapeEventAthletes(w http.ResponseWriter, r *http.Request) {
 		zap.String("event_name", eventName),
 		zap.String("event_url", eventURL))
 
-	go func() {
-		if err := h.scraper.ScrapeEventAthletes(eventID, eventName, eventURL); err != nil {
-			logger.Error("Failed to scrape event athletes", zap.Error(err))
-		}
-	}()
+	queued, ok := h.enqueue(w, queue.JobTypeEventAthletes, models.QueueParams{
+		EventID:   eventID,
+		EventName: eventName,
+		EventURL:  eventURL,
+	})
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Event athlete scraping started",
-		Data: map[string]string{
+		Message: "Event athlete scraping queued",
+		Data: map[string]interface{}{
 			"event_id":   eventID,
 			"event_name": eventName,
 			"event_url":  eventURL,
+			"queued":     queued,
+		},
+	})
+}
+
+// ScrapeEventBrackets triggers scraping of the brackets and matches of an
+// event whose participants were already scraped
+func (h *Handler) ScrapeEventBrackets(w http.ResponseWriter, r *http.Request) {
+	eventID := r.URL.Query().Get("event_id")
+	eventURL := r.URL.Query().Get("event_url")
+
+	if eventID == "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "event_id is required",
+		})
+		return
+	}
+
+	if scraper.IsBlocked(models.BlockedEvent, eventID) {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "event is blocklisted",
+		})
+		return
+	}
+
+	logger.Info("Manual event bracket scraping triggered",
+		zap.String("event_id", eventID),
+		zap.String("event_url", eventURL))
+
+	queued, ok := h.enqueue(w, queue.JobTypeEventBrackets, models.QueueParams{EventID: eventID, EventURL: eventURL})
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Event bracket scraping queued",
+		Data: map[string]interface{}{
+			"event_id":  eventID,
+			"event_url": eventURL,
+			"queued":    queued,
+		},
+	})
+}
+
+// ScrapeEventResults triggers scraping of the podium results of an event
+func (h *Handler) ScrapeEventResults(w http.ResponseWriter, r *http.Request) {
+	eventID := r.URL.Query().Get("event_id")
+	if eventID == "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "event_id is required",
+		})
+		return
+	}
+
+	if scraper.IsBlocked(models.BlockedEvent, eventID) {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "event is blocklisted",
+		})
+		return
+	}
+
+	logger.Info("Manual event results scraping triggered", zap.String("event_id", eventID))
+
+	queued, ok := h.enqueue(w, queue.JobTypeEventResults, models.QueueParams{EventID: eventID})
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Event results scraping queued",
+		Data: map[string]interface{}{
+			"event_id": eventID,
+			"queued":   queued,
 		},
 	})
 }
@@ -545,7 +1050,14 @@ func (h *Handler) ScrapeAthleteProfile(w http.ResponseWriter, r *http.Request) {
 		zap.String("athlete_id", athleteID),
 		zap.String("profile_url", profileURL))
 
-	if err := h.scraper.ScrapeAthleteProfile(athleteID, profileURL); err != nil {
+	if err := h.scraper.ScrapeAthleteProfile(r.Context(), athleteID, profileURL); err != nil {
+		if errors.Is(err, scraper.ErrBlocked) {
+			respondJSON(w, http.StatusConflict, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
 		logger.Error("Failed to scrape athlete profile", zap.Error(err))
 		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -571,6 +1083,7 @@ func (h *Handler) ScrapeAthleteProfile(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	h.privacy.MaskAthlete(&athlete)
 
 	respondJSON(w, http.StatusOK, models.APIResponse{
 		Success: true,
@@ -579,15 +1092,20 @@ func (h *Handler) ScrapeAthleteProfile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ScrapeAthleteProfiles triggers scraping of athlete profiles in batch
+// ScrapeAthleteProfiles queues scraping of athlete profiles in batch. The
+// queued job splits them into child jobs of ENRICH_CHUNK_SIZE profiles.
 func (h *Handler) ScrapeAthleteProfiles(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	limit, _ := strconv.Atoi(query.Get("limit"))
 	if limit <= 0 {
 		limit = 50
 	}
-	if limit > 200 {
-		limit = 200
+	if limit > h.config.Scraper.EnrichMaxTotal {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("limit must be at most %d", h.config.Scraper.EnrichMaxTotal),
+		})
+		return
 	}
 
 	offset, _ := strconv.Atoi(query.Get("offset"))
@@ -602,24 +1120,41 @@ func (h *Handler) ScrapeAthleteProfiles(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	opts, err := parseRunOptions(r, "profiles_enrich")
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if opts.Resume != nil {
+		limit, offset, onlyMissing = opts.Resume.Remaining, 0, opts.Resume.OnlyMissing
+	}
+
 	logger.Info("Manual athlete profiles scraping triggered",
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
-		zap.Bool("only_missing", onlyMissing))
+		zap.Bool("only_missing", onlyMissing),
+		zap.Duration("max_duration", opts.MaxDuration))
 
-	go func() {
-		if _, err := h.scraper.ScrapeAthleteProfiles(limit, offset, onlyMissing); err != nil {
-			logger.Error("Failed to scrape athlete profiles", zap.Error(err))
-		}
-	}()
+	params := queueRunParams(r, opts)
+	params.Limit, params.Offset, params.OnlyMissing = limit, offset, onlyMissing
+	queued, ok := h.enqueue(w, queue.JobTypeEnrich, params)
+	if !ok {
+		return
+	}
 
 	respondJSON(w, http.StatusAccepted, models.APIResponse{
 		Success: true,
-		Message: "Athlete profiles scraping started",
+		Message: "Athlete profiles scraping queued",
 		Data: map[string]interface{}{
 			"limit":        limit,
 			"offset":       offset,
 			"only_missing": onlyMissing,
+			"max_duration": int(opts.MaxDuration.Seconds()),
+			"resumed":      opts.Resume != nil,
+			"queued":       queued,
 		},
 	})
 }
@@ -641,9 +1176,13 @@ func (h *Handler) GetEventDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	details, err := h.scraper.FetchEventDetails(eventID, eventURL)
+	details, err := h.scraper.FetchEventDetails(r.Context(), eventID, eventURL)
 	if err != nil {
-		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+		status := http.StatusInternalServerError
+		if errors.Is(err, scraper.ErrBlocked) {
+			status = http.StatusConflict
+		}
+		respondJSON(w, status, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -665,6 +1204,22 @@ func (h *Handler) GetEventDetails(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// findByIDOrSlug loads dest by external ID, falling back to its slug and,
+// for athletes, to the account an alias was merged into
+func findByIDOrSlug(query *gorm.DB, key string, dest interface{}) error {
+	query = query.Session(&gorm.Session{})
+	err := query.Where("external_id = ?", key).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = query.Where("slug = ?", key).First(dest).Error
+	}
+	if _, athlete := dest.(*models.Athlete); athlete && errors.Is(err, gorm.ErrRecordNotFound) {
+		if mergedInto := scraper.ResolveAthleteID(config.GetDB(), key); mergedInto != key {
+			err = query.Where("external_id = ?", mergedInto).First(dest).Error
+		}
+	}
+	return err
+}
+
 // respondJSON sends a JSON response
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,126 @@
+// Code generated by cmd/apidocs; DO NOT EDIT.
+
+package api
+
+import "github.com/kmicac/smoothcomp-scraper/internal/openapi"
+
+// handlerDocs describe the handlers, by method name
+var handlerDocs = map[string]openapi.HandlerDoc{
+	"BlockEntity":                {Doc: "BlockEntity adds an athlete, event or academy to the blocklist so\nscrapers stop requesting it", Body: true},
+	"CancelJob":                  {Doc: "CancelJob stops a running job. Stage and chunk jobs cancel the job they\nbelong to; the job records \"cancelled\" once its current request returns."},
+	"CancelQueuedJob":            {Doc: "CancelQueuedJob removes a job that has not started yet"},
+	"ClearCrawlState":            {Doc: "ClearCrawlState forgets the visited pages and cookies the scraper stored,\nso the next run fetches every club page again"},
+	"CompareAthletes":            {Doc: "CompareAthletes returns aligned stats, common opponents and shared events\nfor 2 to 5 athletes given as ?ids=a,b,c (external IDs or slugs)", Query: []string{"ids"}},
+	"CreateSavedQuery":           {Doc: "CreateSavedQuery stores a named filter set. Attaching a webhook needs the\nadmin API key.", Body: true},
+	"CreateSchedule":             {Doc: "CreateSchedule adds a new schedule configuration", Body: true},
+	"CreateSubscriber":           {Doc: "CreateSubscriber registers a webhook notified of new events matching a filter", Body: true},
+	"DeleteAthletePersonalData":  {Doc: "DeleteAthletePersonalData handles a data removal request: it scrubs the\nathlete's name, pictures and profile URL, keeps its statistics, and stops\nfuture scrapes from storing them again. An optional JSON body\n{\"reason\": \"...\"} is recorded with the request.", Body: true},
+	"DeleteFederationID":         {Doc: "DeleteFederationID removes a federation ID link, e.g. a wrong name match"},
+	"DeleteSavedQuery":           {Doc: "DeleteSavedQuery removes a saved query"},
+	"DeleteSchedule":             {Doc: "DeleteSchedule removes a schedule configuration"},
+	"DeleteSubscriber":           {Doc: "DeleteSubscriber removes a subscriber"},
+	"DisableSchedule":            {Doc: "DisableSchedule turns a schedule off without deleting it"},
+	"DownloadEventCards":         {Doc: "DownloadEventCards streams a zip with the credential PDF of every\nregistration of an event, for check-in desks. ?division_id= narrows to\none division.", Query: []string{"division_id"}},
+	"EnableSchedule":             {Doc: "EnableSchedule turns a schedule on"},
+	"GetAcademies":               {Doc: "GetAcademies returns all academies with pagination, or every one with\n?all=true, see streamList. Supports conditional requests (ETag /\nIf-Modified-Since), see notModified.", Query: []string{"all", "country", "limit", "page"}},
+	"GetAcademyAnalytics":        {Doc: "GetAcademyAnalytics returns match-level statistics of an academy\n(submission rates, outcome breakdown, most common finishes)"},
+	"GetAcademyByID":             {Doc: "GetAcademyByID returns a specific academy by external ID or slug"},
+	"GetAcademyRivalry":          {Doc: "GetAcademyRivalry returns the head-to-head history between two academies\nand their records against academies both have faced"},
+	"GetAcademyTeamRankings":     {Doc: "GetAcademyTeamRankings returns the ranking trajectory of an academy per\nfederation and season, optionally filtered by ?federation= and ?season=", Query: []string{"federation", "season"}},
+	"GetAthleteByFederationID":   {Doc: "GetAthleteByFederationID returns the athlete linked to an IBJJF or AJP ID"},
+	"GetAthleteByID":             {Doc: "GetAthleteByID returns a specific athlete by external ID or slug"},
+	"GetAthleteCard":             {Doc: "GetAthleteCard renders a share-able profile card of an athlete as PNG or\nJPEG (by the extension of the route). ?layout= picks square (default),\nstory or landscape.", Query: []string{"layout"}},
+	"GetAthleteHistory":          {Doc: "GetAthleteHistory returns how the belt and win/loss record of an athlete\nevolved, one entry per profile scrape that changed them, oldest first.\n?since= and ?until= (YYYY-MM-DD) bound the period and ?limit= (default 100)\nkeeps the latest entries.", Query: []string{"limit", "since", "until"}},
+	"GetAthleteRating":           {Doc: "GetAthleteRating returns the rating of an athlete (career win rate,\n0-100) and its history across profile snapshots. After a year without\nevents a rating drifts towards the average of the athlete's belt, halving\nthe gap every two years; decayed_rating applies that drift."},
+	"GetAthleteStreaks":          {Doc: "GetAthleteStreaks returns the win and submission streaks of an athlete\ncomputed from the stored bracket matches, and the milestones reached"},
+	"GetAthleteWeight":           {Doc: "GetAthleteWeight returns the weight classes an athlete competed in, the\nclasses its typical weigh-in fits and the registrations with big cuts.\n?cut_percent= sets the share of the typical weight that counts as a big\ncut (default 5).", Query: []string{"cut_percent"}},
+	"GetAthletes":                {Doc: "GetAthletes returns all athletes with pagination. Besides ?gender=, athletes\ncan be filtered by the divisions they registered in: ?weight_class= (any\nlabel, e.g. \"-167.5 lbs\" or \"Pesado\", normalized), ?weight_min_kg= and\n?weight_max_kg= (the class limit in kg) and ?style= (gi or nogi), all\nmatched against the same registration, their latest one with\n?registration=latest. ?belt_rank=, ?age_min=, ?age_max= and ?name= filter\nby profile, see filterByProfile, and ?sort= picks the order (wins, the\ndefault, win_rate or recently_scraped). ?filter= takes an expression over\nthe athlete fields, see applyFilter. ?all=true streams every match instead\nof a page, in ID order, see streamList. Supports conditional requests, see\nnotModified.", Query: []string{"academy_id", "age_max", "age_min", "all", "belt_rank", "country", "filter", "gender", "limit", "name", "page", "registration", "sort", "style", "weight_class", "weight_max_kg", "weight_min_kg"}},
+	"GetBracketArchive":          {Doc: "GetBracketArchive returns an archived bracket payload as it was fetched"},
+	"GetBracketPDF":              {Doc: "GetBracketPDF renders a printable bracket sheet of one division, with the\nmatches, seeds and academies scraped so far and the division strength\nindex (also in the X-Division-Strength header)"},
+	"GetConfig":                  {Doc: "GetConfig returns the effective configuration with secrets redacted"},
+	"GetCountries":               {Doc: "GetCountries returns the distinct country codes present in the dataset\nwith counts per entity type, for building filter dropdowns. ?lang= names\nthe countries in Spanish, Portuguese or English.", Query: []string{"lang"}},
+	"GetCountryRoster":           {Doc: "GetCountryRoster returns the national athlete registry of a country built\nby the country roster pipeline, most recently active first", Query: []string{"limit", "page"}},
+	"GetDocs":                    {Doc: "GetDocs serves Swagger UI for the OpenAPI document"},
+	"GetEventByID":               {Doc: "GetEventByID returns a specific event"},
+	"GetEventDetails":            {Doc: "GetEventDetails returns detailed event information from SmoothComp", Query: []string{"event_id", "event_url"}},
+	"GetEventDivisions":          {Doc: "GetEventDivisions lists the divisions of an event with stored\nregistrations and their strength index, strongest first. ?division_id=\nnarrows to one division.", Query: []string{"division_id"}},
+	"GetEventInfo":               {Doc: "GetEventInfo returns all typed info panels stored for an event"},
+	"GetEventInfoPanel":          {Doc: "GetEventInfoPanel returns a single typed info panel of an event"},
+	"GetEventMatches":            {Doc: "GetEventMatches lists the scraped matches of an event by division and\nround. ?division_id= narrows to one bracket.", Query: []string{"division_id"}},
+	"GetEventResults":            {Doc: "GetEventResults lists the podium placements of an event by division.\n?division= narrows to one division.", Query: []string{"division"}},
+	"GetEvents":                  {Doc: "GetEvents returns all events with pagination. ?filter= takes an\nexpression over the event fields, see applyFilter. ?all=true streams every\nmatch instead of a page, see streamList. Supports conditional requests, see\nnotModified.", Query: []string{"all", "country", "filter", "limit", "page", "type"}},
+	"GetJobByID":                 {Doc: "GetJobByID returns a specific job"},
+	"GetJobProgress":             {Doc: "GetJobProgress reports the phase of a job, how many of its items are done\nand an estimate of the time left, with the progress of its child jobs"},
+	"GetJobRecording":            {Doc: "GetJobRecording downloads the requests and responses recorded by a job run\nwith ?debug=true, as JSON Lines", Query: []string{"debug"}},
+	"GetJobs":                    {Doc: "GetJobs returns scraping job history", Query: []string{"limit", "page"}},
+	"GetJobsSummary":             {Doc: "GetJobsSummary aggregates the jobs started in the last ?days= days\n(default 30): success and failure rates, average duration and items\nscraped per job type, items scraped per day, and the jobs running now", Query: []string{"days"}},
+	"GetKidsLeaderboard":         {Doc: "GetKidsLeaderboard ranks athletes of kids divisions, filtered by\n?age_group=, ?belt= and ?gender=. Names are shown as initials unless the\ndeployment sets KIDS_INITIALS_ONLY=false.", Query: []string{"age_group", "belt", "gender", "limit"}},
+	"GetLatencyReport":           {Doc: "GetLatencyReport returns the daily report of slowest endpoints (by p95)\nand slowest queries with their query plans. ?day=YYYY-MM-DD defaults to today.", Query: []string{"day", "limit"}},
+	"GetLatestDigest":            {Doc: "GetLatestDigest returns the changes found by the most recent upcoming-events scrape"},
+	"GetLiveScoreTimeline":       {Doc: "GetLiveScoreTimeline returns every recorded score of a match, oldest first"},
+	"GetLiveScores":              {Doc: "GetLiveScores returns the latest recorded score of every match of an\nevent, for real-time dashboards", Query: []string{"mat"}},
+	"GetLogLevel":                {Doc: "GetLogLevel returns the current log level and when a temporary one expires"},
+	"GetMatchVideos":             {Doc: "GetMatchVideos returns candidate YouTube videos linked to a match"},
+	"GetMedia":                   {Doc: "GetMedia serves a cached image by content hash. The content behind a hash\nnever changes, so clients may cache it forever. ?w= and ?h= (pixels, up to\nMEDIA_MAX_DIMENSION, snapped up to MEDIA_VARIANT_SIZES) serve a smaller\ncopy, cropped to fill the box or, with ?fit=contain, fitted inside it;\ncopies are cached like the original.", Query: []string{"fit", "h", "w"}},
+	"GetMediaManifest":           {Doc: "GetMediaManifest returns the academy logo and country flag bundle manifest\nused by offline clients"},
+	"GetOpenAPISpec":             {Doc: "GetOpenAPISpec returns the OpenAPI 3.0 document of the API"},
+	"GetParseCoverage":           {Doc: "GetParseCoverage returns, per parsed record kind and field, how often the\nfield was found since startup. A drop points at the page section a site\nredesign broke; GET /jobs/{id} has the same counters per job."},
+	"GetProxies":                 {Doc: "GetProxies reports the rotated proxies (SCRAPER_PROXIES) with their request\nand failure counts and whether they are blacklisted"},
+	"GetQueue":                   {Doc: "GetQueue lists queued jobs, newest first, optionally filtered by ?status=", Query: []string{"limit", "page", "status"}},
+	"GetQueuedJob":               {Doc: "GetQueuedJob returns one queued job"},
+	"GetRankings":                {Doc: "GetRankings returns athletes ranked by their points in the stored ranking\nentries, filtered by ?division=, ?belt= and ?country=. Without a division\nthe points of every matching division are added up.", Query: []string{"belt", "country", "division", "limit", "page"}},
+	"GetSavedQuery":              {Doc: "GetSavedQuery returns one saved query"},
+	"GetSchedule":                {Doc: "GetSchedule returns a single schedule configuration"},
+	"GetScheduleAudit":           {Doc: "GetScheduleAudit returns the change history of schedule configurations", Query: []string{"limit"}},
+	"GetStatus":                  {Doc: "GetStatus returns the current status of the scraper"},
+	"GetSubmissionStats":         {Doc: "GetSubmissionStats summarizes the most common finishing submissions across\nall stored matches, by belt, weight class, gender and year. ?belt=,\n?weight_class=, ?gender=, ?year=, ?from= and ?to= narrow the matches;\n?limit= sets how many techniques each group lists.", Query: []string{"belt", "from", "gender", "limit", "to", "weight_class", "year"}},
+	"HealthCheck":                {Doc: "HealthCheck returns the health status of the service"},
+	"ImportFederationIDs":        {Doc: "ImportFederationIDs links athletes to official federation IDs. The body\nis a CSV (Content-Type text/csv, ?federation= for files without a\nfederation column) or JSON {\"federation\", \"rows\": [...]}; ?dry_run=true\nreports the matches without storing them.", Query: []string{"dry_run", "federation"}, Body: true},
+	"ImportLegacyData":           {Doc: "ImportLegacyData imports a CSV export of the legacy scraper, athletes or\nresults by the kind in the path. ?map= overrides column mappings\n(\"Competitor:name,Club:academy\") and ?dry_run=true reports the\nreconciliation without storing anything.", Query: []string{"dry_run", "map"}},
+	"LinkMatchVideos":            {Doc: "LinkMatchVideos searches the configured YouTube channels for the match"},
+	"ListAcademyContacts":        {Doc: "ListAcademyContacts returns the contact details scraped from club pages for\noutreach: every academy with an email, phone or address, by country and\nname. ?country= and ?tag= narrow the list, ?has=email (or phone, address)\nkeeps the academies with that field, and ?format=csv downloads it as CSV.\nAcademies that opted out have no contact details.", Query: []string{"country", "format", "has", "tag"}},
+	"ListAthleteAliases":         {Doc: "ListAthleteAliases lists the athlete accounts merged on Smoothcomp, as\ndetected from profile redirects. ?athlete= narrows it to one kept account.", Query: []string{"athlete"}},
+	"ListBlockedEntities":        {Doc: "ListBlockedEntities returns the scraper blocklist, optionally filtered by ?type=", Query: []string{"type"}},
+	"ListBracketArchives":        {Doc: "ListBracketArchives lists the archived raw bracket versions of an event,\noptionally filtered by ?division_id=", Query: []string{"division_id"}},
+	"ListEventURLAliases":        {Doc: "ListEventURLAliases lists former event URLs that now redirect, with the\ncanonical URL each resolves to. ?event= narrows it to one event ID.", Query: []string{"event"}},
+	"ListFederationIDs":          {Doc: "ListFederationIDs returns federation ID links, filtered by ?federation=\nand ?athlete= (Smoothcomp external ID)", Query: []string{"athlete", "federation"}},
+	"ListLiveStreams":            {Doc: "ListLiveStreams reports the scoreboard streams being ingested"},
+	"ListSavedQueries":           {Doc: "ListSavedQueries returns the saved queries"},
+	"ListSchedules":              {Doc: "ListSchedules returns all schedule configurations"},
+	"ListSubscribers":            {Doc: "ListSubscribers returns the new-event notification subscribers"},
+	"ListTagNames":               {Doc: "ListTagNames returns the tags in use with how many entities carry each"},
+	"ListTags":                   {Doc: "ListTags returns tag assignments, filtered by ?entity_type=, ?external_id=\nand ?tag=", Query: []string{"entity_type", "external_id", "tag"}},
+	"OptOutAcademyContact":       {Doc: "OptOutAcademyContact handles an academy's request not to be contacted: its\nemail, phone and address are cleared and future scrapes do not store them\nagain. An optional JSON body {\"reason\": \"...\"} is recorded with the request.", Body: true},
+	"ProxyMedia":                 {Doc: "ProxyMedia fetches (or serves from cache) the image at ?url= and redirects\nto its stable content-addressed URL, keeping the ?w=, ?h= and ?fit= resize\nparameters of GetMedia", Query: []string{"fit", "h", "url", "w"}},
+	"RecomputeAcademyStats":      {Doc: "RecomputeAcademyStats queues the aggregation of academy statistics (wins,\nlosses, athletes and medals) from the stored athletes and results, for\nevery academy or the one in ?academy_id= (external ID or slug)", Query: []string{"academy_id"}},
+	"RecomputeRankings":          {Doc: "RecomputeRankings rebuilds the ranking entries from the stored results\nwith the configured scoring"},
+	"RefreshMediaBundle":         {Doc: "RefreshMediaBundle caches every academy logo and country flag in background"},
+	"ReloadConfig":               {Doc: "ReloadConfig re-reads .env and the environment and applies the settings that\nare safe to change while jobs run (request delay, target countries, log\nlevel, stale enrichment policy), then re-registers the stored schedules"},
+	"RemoveAcademyContactOptOut": {Doc: "RemoveAcademyContactOptOut withdraws an academy's opt-out; its contact\ndetails are stored again from its next club page scrape"},
+	"ResyncAcademy":              {Doc: "ResyncAcademy re-fetches the details, members roster and logo and cover\nsnapshots of one academy. It waits for the result unless ?async=true, which\nanswers 202 with the queued academy_resync job and leaves the images as\ncached.", Query: []string{"async"}},
+	"ResyncAthlete":              {Doc: "ResyncAthlete re-fetches the profile, registration history and win/loss\ncounts of one athlete. It waits for the result unless ?async=true, which\nanswers 202 with the queued athlete_resync job.", Query: []string{"async"}},
+	"RunSavedQuery":              {Doc: "RunSavedQuery executes a saved query. Accepts ?limit= (at most 500) and ?offset=.", Query: []string{"limit", "offset"}},
+	"ScrapeAcademies":            {Doc: "ScrapeAcademies triggers manual academy scraping"},
+	"ScrapeAll":                  {Doc: "ScrapeAll triggers the full scraping pipeline. ?resume=<job id> continues\na failed run from its first unfinished stage.", Query: []string{"resume"}},
+	"ScrapeAthleteProfile":       {Doc: "ScrapeAthleteProfile triggers scraping of a single athlete profile", Query: []string{"athlete_id", "profile_url"}},
+	"ScrapeAthleteProfiles":      {Doc: "ScrapeAthleteProfiles queues scraping of athlete profiles in batch. The\nqueued job splits them into child jobs of ENRICH_CHUNK_SIZE profiles.", Query: []string{"limit", "offset", "only_missing"}},
+	"ScrapeAthletes":             {Doc: "ScrapeAthletes queues the country roster pipeline for ?country=: it\ndiscovers the past events held in the country, scrapes the participants\nof each and builds the national athlete registry, tracked as one job.\n?resume=<job id> continues a failed run from its first unfinished stage.\nRequests with only ?event_id= scrape the participants of that event.", Query: []string{"country", "event_id", "resume"}},
+	"ScrapeEventAthletes":        {Doc: "ScrapeEventAthletes triggers scraping of athletes from a specific event", Query: []string{"event_id", "event_name", "event_url"}},
+	"ScrapeEventBrackets":        {Doc: "ScrapeEventBrackets triggers scraping of the brackets and matches of an\nevent whose participants were already scraped", Query: []string{"event_id", "event_url"}},
+	"ScrapeEventResults":         {Doc: "ScrapeEventResults triggers scraping of the podium results of an event", Query: []string{"event_id"}},
+	"ScrapeEventsBulk":           {Doc: "ScrapeEventsBulk queues details, participants and results scrapes for a\nlist of event IDs or URLs, returning the events read and the queued job.\nAccepts the ?max_duration= and ?resume= of other scrape jobs.", Query: []string{"max_duration", "resume"}, Body: true},
+	"ScrapePastEvents":           {Doc: "ScrapePastEvents triggers scraping of past events for a country", Query: []string{"country", "depth"}},
+	"ScrapeTeamRankings":         {Doc: "ScrapeTeamRankings queues a scrape of the club ranking of ?federation=\n(a smoothcomp subdomain such as ajp) for ?season=, or the current season", Query: []string{"federation", "season"}},
+	"ScrapeUpcomingEvents":       {Doc: "ScrapeUpcomingEvents triggers scraping of upcoming events for a country", Query: []string{"country", "depth"}},
+	"SetLogLevel":                {Doc: "SetLogLevel switches the log level without a restart. Body:\n{\"level\": \"debug\", \"expires_in_seconds\": 900}; with expires_in_seconds the\ndefault LOG_LEVEL comes back afterwards, without it the level stays until\nchanged again.", Body: true},
+	"StartLiveStream":            {Doc: "StartLiveStream starts ingesting the scoreboard stream of an event"},
+	"StopLiveStream":             {Doc: "StopLiveStream stops ingesting the scoreboard stream of an event"},
+	"StreamJobs":                 {Doc: "StreamJobs pushes job and schedule updates as Server-Sent Events until the\nclient disconnects. It starts with a job_progress event per running job;\n?job_id= narrows the stream to one job and its stage and chunk jobs.", Query: []string{"job_id"}},
+	"TagEntity":                  {Doc: "TagEntity labels a stored athlete or academy with a tag", Body: true},
+	"UnblockEntity":              {Doc: "UnblockEntity removes an entry from the blocklist"},
+	"UntagEntity":                {Doc: "UntagEntity removes a tag assignment"},
+	"UpdateMatchVideo":           {Doc: "UpdateMatchVideo confirms or rejects a candidate video", Body: true},
+	"UpdateSavedQuery":           {Doc: "UpdateSavedQuery changes the name, target, filters or webhook of a saved\nquery. New filters reset the baseline used for change notifications.", Body: true},
+	"UpdateSchedule":             {Doc: "UpdateSchedule replaces the name, cron expression, job type, parameters\nand/or enabled flag of a schedule", Body: true},
+	"UpdateSubscriber":           {Doc: "UpdateSubscriber changes the name, webhook, filter or enabled flag of a subscriber", Body: true},
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// streamHeartbeat is how often an idle job stream sends a comment so
+// proxies keep the connection open
+const streamHeartbeat = 15 * time.Second
+
+// streamBuffer is how many events a slow client may fall behind before
+// missing some
+const streamBuffer = 64
+
+// StreamJobs pushes job and schedule updates as Server-Sent Events until the
+// client disconnects. It starts with a job_progress event per running job;
+// ?job_id= narrows the stream to one job and its stage and chunk jobs.
+func (h *Handler) StreamJobs(w http.ResponseWriter, r *http.Request) {
+	var jobID int
+	if raw := r.URL.Query().Get("job_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "job_id must be a number",
+			})
+			return
+		}
+		jobID = id
+	}
+
+	rc := http.NewResponseController(w)
+	// The server write timeout would cut the stream
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := bus.Default().Subscribe(streamBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event bus.Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	query := config.GetDB().Where("status = ?", "running")
+	if jobID != 0 {
+		query = query.Where("id = ? OR parent_job_id = ?", jobID, jobID)
+	}
+	var running []models.ScrapeJob
+	query.Order("id").Find(&running)
+	for i := range running {
+		if send(bus.JobEvent(bus.JobProgress, &running[i])) != nil {
+			return
+		}
+	}
+	if len(running) == 0 && rc.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if jobID != 0 && event.JobID != jobID && (event.ParentJobID == nil || *event.ParentJobID != jobID) {
+				continue
+			}
+			if send(event) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+const (
+	defaultSummaryDays = 30
+	maxSummaryDays     = 365
+)
+
+// jobTypeSummary aggregates the jobs of one type. Rates are over finished
+// jobs (completed, partial or failed); cancelled and postponed jobs count in neither.
+type jobTypeSummary struct {
+	JobType            string  `json:"job_type,omitempty"`
+	Total              int     `json:"total"`
+	Completed          int     `json:"completed"`
+	Partial            int     `json:"partial"`
+	Failed             int     `json:"failed"`
+	Cancelled          int     `json:"cancelled"`
+	Postponed          int     `json:"postponed"`
+	Running            int     `json:"running"`
+	SuccessRate        float64 `json:"success_rate"`
+	FailureRate        float64 `json:"failure_rate"`
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+	ItemsScraped       int     `json:"items_scraped"`
+
+	durationSum   float64
+	durationCount int
+}
+
+// jobDaySummary is one day of the trend
+type jobDaySummary struct {
+	Date         string `json:"date"`
+	Jobs         int    `json:"jobs"`
+	Failed       int    `json:"failed"`
+	ItemsScraped int    `json:"items_scraped"`
+}
+
+// GetJobsSummary aggregates the jobs started in the last ?days= days
+// (default 30): success and failure rates, average duration and items
+// scraped per job type, items scraped per day, and the jobs running now
+func (h *Handler) GetJobsSummary(w http.ResponseWriter, r *http.Request) {
+	days := defaultSummaryDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSummaryDays {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "days must be between 1 and " + strconv.Itoa(maxSummaryDays),
+			})
+			return
+		}
+		days = parsed
+	}
+
+	db := config.GetDB()
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	var jobs []models.ScrapeJob
+	if err := db.Select("id, job_type, status, started_at, completed_at, items_scraped").
+		Where("started_at >= ?", since).
+		Find(&jobs).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	byType := map[string]*jobTypeSummary{}
+	trend := make([]jobDaySummary, days)
+	for i := range trend {
+		trend[i].Date = since.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	totals := jobTypeSummary{}
+
+	for _, job := range jobs {
+		summary, ok := byType[job.JobType]
+		if !ok {
+			summary = &jobTypeSummary{JobType: job.JobType}
+			byType[job.JobType] = summary
+		}
+		summary.add(job)
+		totals.add(job)
+
+		if day := int(job.StartedAt.UTC().Sub(since).Hours() / 24); day >= 0 && day < days {
+			trend[day].Jobs++
+			trend[day].ItemsScraped += job.ItemsScraped
+			if job.Status == "failed" {
+				trend[day].Failed++
+			}
+		}
+	}
+
+	types := make([]*jobTypeSummary, 0, len(byType))
+	for _, summary := range byType {
+		summary.finish()
+		types = append(types, summary)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].JobType < types[j].JobType })
+	totals.finish()
+
+	running := []models.ScrapeJob{}
+	db.Where("status = ?", "running").Order("started_at").Find(&running)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Jobs summary retrieved successfully",
+		Data: map[string]interface{}{
+			"since":     since.Format("2006-01-02"),
+			"days":      days,
+			"totals":    totals,
+			"job_types": types,
+			"daily":     trend,
+			"running":   running,
+		},
+	})
+}
+
+func (s *jobTypeSummary) add(job models.ScrapeJob) {
+	s.Total++
+	s.ItemsScraped += job.ItemsScraped
+
+	switch job.Status {
+	case "completed":
+		s.Completed++
+		if job.CompletedAt != nil {
+			s.durationSum += job.CompletedAt.Sub(job.StartedAt).Seconds()
+			s.durationCount++
+		}
+	case "partial":
+		s.Partial++
+	case "failed":
+		s.Failed++
+	case "cancelled":
+		s.Cancelled++
+	case maintenance.StatusPostponed:
+		s.Postponed++
+	case "running":
+		s.Running++
+	}
+}
+
+func (s *jobTypeSummary) finish() {
+	if finished := s.Completed + s.Partial + s.Failed; finished > 0 {
+		s.SuccessRate = math.Round(float64(s.Completed)/float64(finished)*100) / 100
+		s.FailureRate = math.Round(float64(s.Failed)/float64(finished)*100) / 100
+	}
+	if s.durationCount > 0 {
+		s.AvgDurationSeconds = math.Round(s.durationSum / float64(s.durationCount))
+	}
+}
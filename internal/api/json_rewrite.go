@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// jsonRewriter buffers a JSON response so a middleware can rewrite it once
+// the handler returns. Other responses (files, images, event streams) pass
+// through as they are written.
+type jsonRewriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (jw *jsonRewriter) WriteHeader(code int) {
+	if jw.wroteHeader {
+		return
+	}
+	jw.wroteHeader = true
+	jw.status = code
+	jw.buffering = strings.HasPrefix(jw.Header().Get("Content-Type"), "application/json")
+	if !jw.buffering {
+		jw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (jw *jsonRewriter) Write(p []byte) (int, error) {
+	if !jw.wroteHeader {
+		jw.WriteHeader(http.StatusOK)
+	}
+	if jw.buffering {
+		return jw.body.Write(p)
+	}
+	return jw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (jw *jsonRewriter) Unwrap() http.ResponseWriter {
+	return jw.ResponseWriter
+}
+
+// finish sends the buffered response through rewrite, which returns the new
+// body and content type, or false to send the original body
+func (jw *jsonRewriter) finish(rewrite func(body []byte, status int) ([]byte, string, bool)) {
+	if !jw.buffering {
+		return
+	}
+
+	body, contentType, ok := rewrite(jw.body.Bytes(), jw.status)
+	if !ok {
+		jw.ResponseWriter.WriteHeader(jw.status)
+		jw.ResponseWriter.Write(jw.body.Bytes())
+		return
+	}
+
+	jw.Header().Set("Content-Type", contentType)
+	jw.Header().Del("Content-Length")
+	jw.ResponseWriter.WriteHeader(jw.status)
+	jw.ResponseWriter.Write(append(body, '\n'))
+}
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// jsonAPIMediaType is the media type clients send in Accept to get JSON:API
+// documents (https://jsonapi.org) instead of the APIResponse envelope
+const jsonAPIMediaType = "application/vnd.api+json"
+
+// linkedTypes are the resource types with a GET /api/v1/{type}/{external_id}
+// route, used for resource self links
+var linkedTypes = map[string]bool{
+	"athletes":  true,
+	"academies": true,
+	"events":    true,
+}
+
+// wantsJSONAPI reports whether the request accepts the JSON:API media type
+func wantsJSONAPI(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), jsonAPIMediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonAPIMiddleware rewrites JSON envelope responses as JSON:API documents
+// when the client asks for them. Other responses (files, images, event
+// streams) pass through untouched.
+func jsonAPIMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		if !wantsJSONAPI(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		jw := &jsonRewriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(jw, r)
+		jw.finish(func(body []byte, status int) ([]byte, string, bool) {
+			doc, ok := toJSONAPI(body, status, r)
+			if !ok {
+				return nil, "", false
+			}
+			encoded, err := json.Marshal(doc)
+			return encoded, jsonAPIMediaType, err == nil
+		})
+	})
+}
+
+// jsonAPIDocument is a top-level JSON:API document
+type jsonAPIDocument struct {
+	Data     interface{}            `json:"data,omitempty"`
+	Errors   []jsonAPIError         `json:"errors,omitempty"`
+	Included []*jsonAPIResource     `json:"included,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+	Links    map[string]string      `json:"links,omitempty"`
+	JSONAPI  map[string]string      `json:"jsonapi"`
+}
+
+// jsonAPIError is one entry of a JSON:API errors array
+type jsonAPIError struct {
+	Status string      `json:"status"`
+	Title  string      `json:"title"`
+	Detail string      `json:"detail,omitempty"`
+	Meta   interface{} `json:"meta,omitempty"`
+}
+
+// jsonAPIResource is a resource object
+type jsonAPIResource struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id"`
+	Attributes    map[string]interface{}         `json:"attributes,omitempty"`
+	Relationships map[string]jsonAPIRelationship `json:"relationships,omitempty"`
+	Links         map[string]string              `json:"links,omitempty"`
+}
+
+// jsonAPIIdentifier identifies a resource in a relationship
+type jsonAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// jsonAPIRelationship links a resource to one or many others
+type jsonAPIRelationship struct {
+	Data interface{} `json:"data"`
+}
+
+// toJSONAPI converts an APIResponse body. It reports false for bodies that
+// are not an envelope, which are sent as they are.
+func toJSONAPI(body []byte, status int, r *http.Request) (*jsonAPIDocument, bool) {
+	var envelope struct {
+		Success *bool       `json:"success"`
+		Message string      `json:"message"`
+		Error   string      `json:"error"`
+		Data    interface{} `json:"data"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil || envelope.Success == nil {
+		return nil, false
+	}
+
+	doc := &jsonAPIDocument{
+		Links:   map[string]string{"self": r.URL.RequestURI()},
+		JSONAPI: map[string]string{"version": "1.1"},
+	}
+
+	if !*envelope.Success || status >= http.StatusBadRequest {
+		if status < http.StatusBadRequest {
+			status = http.StatusInternalServerError
+		}
+		doc.Errors = []jsonAPIError{{
+			Status: strconv.Itoa(status),
+			Title:  http.StatusText(status),
+			Detail: envelope.Error,
+			Meta:   envelope.Data,
+		}}
+		return doc, true
+	}
+
+	b := &jsonAPIBuilder{seen: map[string]bool{}}
+	resourceType := routeResourceType(r)
+
+	switch data := envelope.Data.(type) {
+	case nil:
+		doc.Data = []interface{}{}
+	case []interface{}:
+		if resources, ok := b.resources(resourceType, data); ok {
+			doc.Data = resources
+		} else {
+			doc.Meta = map[string]interface{}{"items": data}
+		}
+	case map[string]interface{}:
+		if _, ok := data["id"]; ok {
+			doc.Data = b.resource(resourceType, data)
+			break
+		}
+		if key, resources, ok := b.collection(data); ok {
+			delete(data, key)
+			doc.Data = resources
+			doc.Meta = data
+			addPageLinks(doc.Links, r, data)
+			break
+		}
+		doc.Meta = data
+	default:
+		doc.Meta = map[string]interface{}{"value": data}
+	}
+
+	if envelope.Message != "" {
+		if doc.Meta == nil {
+			doc.Meta = map[string]interface{}{}
+		}
+		doc.Meta["message"] = envelope.Message
+	}
+	doc.Included = b.included
+	return doc, true
+}
+
+// jsonAPIBuilder turns decoded models into resources, collecting related
+// resources once each for the included array
+type jsonAPIBuilder struct {
+	included []*jsonAPIResource
+	seen     map[string]bool
+}
+
+// resource converts a decoded model with an "id". Nested models with an
+// "id" become relationships and are added to the included resources.
+func (b *jsonAPIBuilder) resource(resourceType string, obj map[string]interface{}) *jsonAPIResource {
+	res := &jsonAPIResource{
+		Type:       resourceType,
+		ID:         fmt.Sprint(obj["id"]),
+		Attributes: map[string]interface{}{},
+	}
+
+	for key, value := range obj {
+		if key == "id" || key == "type" {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if _, ok := v["id"]; ok {
+				related := b.resource(pluralType(key), v)
+				b.include(related)
+				res.relate(key, jsonAPIIdentifier{Type: related.Type, ID: related.ID})
+				continue
+			}
+		case []interface{}:
+			if related, ok := b.resources(key, v); ok && len(related) > 0 {
+				ids := make([]jsonAPIIdentifier, 0, len(related))
+				for _, item := range related {
+					b.include(item)
+					ids = append(ids, jsonAPIIdentifier{Type: item.Type, ID: item.ID})
+				}
+				res.relate(key, ids)
+				continue
+			}
+		}
+		res.Attributes[key] = value
+	}
+
+	if externalID, ok := obj["external_id"].(string); ok && externalID != "" && linkedTypes[resourceType] {
+		res.Links = map[string]string{"self": "/api/v1/" + resourceType + "/" + externalID}
+	}
+	return res
+}
+
+// resources converts a list of decoded models. It reports false when an
+// item is not a model with an "id".
+func (b *jsonAPIBuilder) resources(resourceType string, items []interface{}) ([]*jsonAPIResource, bool) {
+	out := make([]*jsonAPIResource, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if _, ok := obj["id"]; !ok {
+			return nil, false
+		}
+		out = append(out, b.resource(resourceType, obj))
+	}
+	return out, true
+}
+
+// collection finds the list in a paginated response such as
+// {"athletes": [...], "page": 1, "limit": 20, "total": 35}
+func (b *jsonAPIBuilder) collection(data map[string]interface{}) (string, []*jsonAPIResource, bool) {
+	var key string
+	for k, v := range data {
+		if _, ok := v.([]interface{}); !ok {
+			continue
+		}
+		if key != "" {
+			return "", nil, false
+		}
+		key = k
+	}
+	if key == "" {
+		return "", nil, false
+	}
+	resources, ok := b.resources(key, data[key].([]interface{}))
+	return key, resources, ok
+}
+
+func (b *jsonAPIBuilder) include(res *jsonAPIResource) {
+	key := res.Type + "/" + res.ID
+	if b.seen[key] {
+		return
+	}
+	b.seen[key] = true
+	b.included = append(b.included, res)
+}
+
+func (res *jsonAPIResource) relate(name string, data interface{}) {
+	if res.Relationships == nil {
+		res.Relationships = map[string]jsonAPIRelationship{}
+	}
+	res.Relationships[name] = jsonAPIRelationship{Data: data}
+}
+
+// routeResourceType names the resources a route returns after its last
+// literal path segment, e.g. "athletes" for /api/v1/athletes/{id}
+func routeResourceType(r *http.Request) string {
+	path := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			path = template
+		}
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segment := segments[i]; segment != "" && !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+	return "resources"
+}
+
+// pluralType names the type of a to-one relationship, e.g. "academies" for
+// an "academy" field
+func pluralType(field string) string {
+	switch {
+	case strings.HasSuffix(field, "y"):
+		return strings.TrimSuffix(field, "y") + "ies"
+	case strings.HasSuffix(field, "s"):
+		return field
+	}
+	return field + "s"
+}
+
+// addPageLinks adds first, prev, next and last links for responses carrying
+// page, limit and total
+func addPageLinks(links map[string]string, r *http.Request, meta map[string]interface{}) {
+	page, okPage := metaInt(meta["page"])
+	limit, okLimit := metaInt(meta["limit"])
+	total, okTotal := metaInt(meta["total"])
+	if !okPage || !okLimit || !okTotal || limit <= 0 {
+		return
+	}
+
+	last := (total + limit - 1) / limit
+	if last < 1 {
+		last = 1
+	}
+	pageURL := func(p int) string {
+		u := *r.URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(p))
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	links["first"] = pageURL(1)
+	links["last"] = pageURL(last)
+	if page > 1 {
+		links["prev"] = pageURL(page - 1)
+	}
+	if page < last {
+		links["next"] = pageURL(page + 1)
+	}
+}
+
+func metaInt(value interface{}) (int, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(number.String())
+	return n, err == nil
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetKidsLeaderboard ranks athletes of kids divisions, filtered by
+// ?age_group=, ?belt= and ?gender=. Names are shown as initials unless the
+// deployment sets KIDS_INITIALS_ONLY=false.
+func (h *Handler) GetKidsLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	gender, _ := models.ParseGender(query.Get("gender"))
+
+	board, err := analytics.KidsRanking(analytics.KidsLeaderboardFilter{
+		AgeGroup:     query.Get("age_group"),
+		Belt:         query.Get("belt"),
+		Gender:       gender,
+		Limit:        limit,
+		InitialsOnly: h.config.Privacy.KidsInitialsOnly,
+	})
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute kids leaderboard",
+		})
+		return
+	}
+	h.maskLeaderboard(board)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Kids leaderboard retrieved successfully",
+		Data:    board,
+	})
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/legacyimport"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxLegacyImportBytes caps the body of a legacy CSV import
+const maxLegacyImportBytes = 50 << 20
+
+// ImportLegacyData imports a CSV export of the legacy scraper, athletes or
+// results by the kind in the path. ?map= overrides column mappings
+// ("Competitor:name,Club:academy") and ?dry_run=true reports the
+// reconciliation without storing anything.
+func (h *Handler) ImportLegacyData(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLegacyImportBytes)
+	kind := mux.Vars(r)["kind"]
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	mapping, err := legacyimport.ParseMapping(r.URL.Query().Get("map"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	rows, recon, err := legacyimport.ParseCSV(r.Body, kind, mapping)
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if len(rows) == 0 {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "No rows to import",
+		})
+		return
+	}
+
+	report, err := legacyimport.Import(kind, rows, recon, dryRun)
+	if err != nil {
+		logger.Error("Legacy import failed", zap.String("kind", kind), zap.Error(err))
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Legacy data import requested",
+		zap.String("kind", kind),
+		zap.Bool("dry_run", dryRun),
+		zap.String("actor", requestActor(r)))
+
+	message := "Legacy data imported"
+	if dryRun {
+		message = "Legacy data import checked (dry run)"
+	}
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/live"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// GetLiveScores returns the latest recorded score of every match of an
+// event, for real-time dashboards
+func (h *Handler) GetLiveScores(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	eventID := mux.Vars(r)["id"]
+
+	latest := db.Model(&models.LiveScore{}).Select("MAX(id)").
+		Where("event_id = ?", eventID).Group("match_id")
+	query := db.Where("id IN (?)", latest)
+	if mat := r.URL.Query().Get("mat"); mat != "" {
+		query = query.Where("mat = ?", mat)
+	}
+
+	scores := []models.LiveScore{}
+	if err := query.Order("mat, match_id").Find(&scores).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load live scores",
+		})
+		return
+	}
+	scores = overlayUnflushed(scores, h.live.Unflushed(eventID, ""), r.URL.Query().Get("mat"))
+	h.privacy.MaskLiveScores(scores)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Live scores retrieved successfully",
+		Data:    scores,
+	})
+}
+
+// GetLiveScoreTimeline returns every recorded score of a match, oldest first
+func (h *Handler) GetLiveScoreTimeline(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	scores := []models.LiveScore{}
+	if err := config.GetDB().Where("event_id = ? AND match_id = ?", vars["id"], vars["match"]).
+		Order("recorded_at, id").Find(&scores).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load live scores",
+		})
+		return
+	}
+	// Rows still waiting for the next flush come after the written ones
+	for _, score := range h.live.Unflushed(vars["id"], vars["match"]) {
+		if len(scores) == 0 || score.RecordedAt.After(scores[len(scores)-1].RecordedAt) {
+			scores = append(scores, score)
+		}
+	}
+	h.privacy.MaskLiveScores(scores)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Live score timeline retrieved successfully",
+		Data:    scores,
+	})
+}
+
+// overlayUnflushed replaces the latest written score of each match with the
+// newest one still waiting to be written, keeping the mat, match order
+func overlayUnflushed(scores, unflushed []models.LiveScore, mat string) []models.LiveScore {
+	if len(unflushed) == 0 {
+		return scores
+	}
+	index := make(map[string]int, len(scores))
+	for i, score := range scores {
+		index[score.MatchID] = i
+	}
+	for _, score := range unflushed {
+		if mat != "" && score.Mat != mat {
+			continue
+		}
+		if i, ok := index[score.MatchID]; ok {
+			scores[i] = score
+			continue
+		}
+		index[score.MatchID] = len(scores)
+		scores = append(scores, score)
+	}
+	sort.SliceStable(scores, func(a, b int) bool {
+		if scores[a].Mat != scores[b].Mat {
+			return scores[a].Mat < scores[b].Mat
+		}
+		return scores[a].MatchID < scores[b].MatchID
+	})
+	return scores
+}
+
+// ListLiveStreams reports the scoreboard streams being ingested
+func (h *Handler) ListLiveStreams(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Live streams retrieved successfully",
+		Data:    h.live.Streams(),
+	})
+}
+
+// StartLiveStream starts ingesting the scoreboard stream of an event
+func (h *Handler) StartLiveStream(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["id"]
+
+	if err := h.live.Start(eventID); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, live.ErrAlreadyStreaming) {
+			status = http.StatusConflict
+		}
+		respondJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Live stream requested",
+		zap.String("event_id", eventID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Live stream started",
+	})
+}
+
+// StopLiveStream stops ingesting the scoreboard stream of an event
+func (h *Handler) StopLiveStream(w http.ResponseWriter, r *http.Request) {
+	eventID := mux.Vars(r)["id"]
+
+	if !h.live.Stop(eventID) {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Event is not being streamed",
+		})
+		return
+	}
+
+	logger.Info("Live stream stop requested",
+		zap.String("event_id", eventID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Live stream stopped",
+	})
+}
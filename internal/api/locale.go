@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/locale"
+)
+
+// requestLang returns the ?lang= of a request, false when it is absent
+func requestLang(r *http.Request) (locale.Lang, bool) {
+	return locale.Parse(r.URL.Query().Get("lang"))
+}
+
+// localeMiddleware adds localized text to GET responses carrying ?lang=es,
+// pt or en: country_name next to each country_code, belt_name next to
+// belt_rank and belt, rank_name next to rank and a <field>_text rendering of
+// each date. Stored values are left as they are.
+func localeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("lang")
+		if raw == "" || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+		lang, ok := locale.Parse(raw)
+		if !ok {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "lang must be one of es, pt, en",
+			})
+			return
+		}
+
+		jw := &jsonRewriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(jw, r)
+		jw.finish(func(body []byte, _ int) ([]byte, string, bool) {
+			// Decoding into the envelope keeps its field order
+			var envelope models.APIResponse
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			decoder.DisallowUnknownFields()
+			if decoder.Decode(&envelope) == nil {
+				localizeValue(lang, envelope.Data)
+				encoded, err := json.Marshal(envelope)
+				return encoded, "application/json", err == nil
+			}
+
+			var payload interface{}
+			decoder = json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			if err := decoder.Decode(&payload); err != nil {
+				return nil, "", false
+			}
+			localizeValue(lang, payload)
+			encoded, err := json.Marshal(payload)
+			return encoded, "application/json", err == nil
+		})
+	})
+}
+
+// localizeValue adds localized fields to every object inside value
+func localizeValue(lang locale.Lang, value interface{}) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			localizeValue(lang, item)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			localizeValue(lang, item)
+		}
+		localizeObject(lang, v)
+	}
+}
+
+func localizeObject(lang locale.Lang, obj map[string]interface{}) {
+	added := map[string]interface{}{}
+
+	if code, ok := obj["country_code"].(string); ok {
+		if name := locale.CountryName(lang, code); name != "" {
+			added["country_name"] = name
+		}
+	}
+	for field, name := range map[string]string{"belt_rank": "belt_name", "belt": "belt_name", "rank": "rank_name"} {
+		if belt, ok := obj[field].(string); ok && belt != "" {
+			added[name] = locale.BeltName(lang, belt)
+		}
+	}
+
+	for field, value := range obj {
+		text, ok := value.(string)
+		if !ok || !isDateField(field) {
+			continue
+		}
+		if date, ok := parseResponseDate(text); ok {
+			added[field+"_text"] = locale.FormatDate(lang, date)
+		}
+	}
+
+	for field, value := range added {
+		if _, exists := obj[field]; !exists {
+			obj[field] = value
+		}
+	}
+}
+
+// isDateField reports whether a response field holds a date or timestamp
+func isDateField(field string) bool {
+	return field == "date" || strings.HasSuffix(field, "_at") || strings.HasSuffix(field, "_date")
+}
+
+// parseResponseDate reads RFC 3339 timestamps and YYYY-MM-DD dates; zero
+// times, used for "never", are skipped
+func parseResponseDate(text string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, !t.IsZero()
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/youtube"
+)
+
+// GetMatchVideos returns candidate YouTube videos linked to a match
+func (h *Handler) GetMatchVideos(w http.ResponseWriter, r *http.Request) {
+	match, ok := loadMatch(w, r)
+	if !ok {
+		return
+	}
+
+	videos, err := youtube.LoadMatchVideos(match.ID)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load match videos",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Match videos retrieved successfully",
+		Data:    videos,
+	})
+}
+
+// LinkMatchVideos searches the configured YouTube channels for the match
+func (h *Handler) LinkMatchVideos(w http.ResponseWriter, r *http.Request) {
+	match, ok := loadMatch(w, r)
+	if !ok {
+		return
+	}
+
+	videos, err := h.youtube.LinkMatch(match)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, youtube.ErrNotConfigured) {
+			status = http.StatusServiceUnavailable
+		}
+		respondJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Match videos linked successfully",
+		Data:    videos,
+	})
+}
+
+// UpdateMatchVideo confirms or rejects a candidate video
+func (h *Handler) UpdateMatchVideo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+	switch input.Status {
+	case "candidate", "confirmed", "rejected":
+	default:
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "status must be candidate, confirmed or rejected",
+		})
+		return
+	}
+
+	db := config.GetDB()
+	var video models.MatchVideo
+	if err := db.Where("match_id = ? AND id = ?", vars["id"], vars["videoId"]).First(&video).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Match video not found",
+		})
+		return
+	}
+
+	if err := db.Model(&video).Update("status", input.Status).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to update match video",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Match video updated successfully",
+		Data:    video,
+	})
+}
+
+func loadMatch(w http.ResponseWriter, r *http.Request) (models.Match, bool) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	var match models.Match
+	if err := config.GetDB().First(&match, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Match not found",
+		})
+		return match, false
+	}
+
+	return match, true
+}
+
+// GetEventMatches lists the scraped matches of an event by division and
+// round. ?division_id= narrows to one bracket.
+func (h *Handler) GetEventMatches(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Where("event_id = ?", mux.Vars(r)["id"])
+	if divisionID := r.URL.Query().Get("division_id"); divisionID != "" {
+		query = query.Where("division_id = ?", divisionID)
+	}
+
+	matches := []models.Match{}
+	if err := query.Order("division_id, started_at, id").Find(&matches).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load matches",
+		})
+		return
+	}
+	h.privacy.MaskMatches(matches)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event matches retrieved successfully",
+		Data:    matches,
+	})
+}
+
+// GetEventResults lists the podium placements of an event by division.
+// ?division= narrows to one division.
+func (h *Handler) GetEventResults(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Where("event_id = ?", mux.Vars(r)["id"])
+	if division := r.URL.Query().Get("division"); division != "" {
+		query = query.Where("division = ?", division)
+	}
+
+	results := []models.EventResult{}
+	if err := query.Order("division, placement, id").Find(&results).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load results",
+		})
+		return
+	}
+	h.privacy.MaskResults(results)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Event results retrieved successfully",
+		Data:    results,
+	})
+}
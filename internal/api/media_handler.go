@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/media"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const mediaPathPrefix = "/api/v1/media/"
+
+// ProxyMedia fetches (or serves from cache) the image at ?url= and redirects
+// to its stable content-addressed URL, keeping the ?w=, ?h= and ?fit= resize
+// parameters of GetMedia
+func (h *Handler) ProxyMedia(w http.ResponseWriter, r *http.Request) {
+	sourceURL := strings.TrimSpace(r.URL.Query().Get("url"))
+	if sourceURL == "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "url is required",
+		})
+		return
+	}
+	variant, ok := h.mediaVariant(w, r)
+	if !ok {
+		return
+	}
+
+	asset, err := h.media.Get(sourceURL)
+	if err != nil {
+		respondJSON(w, http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	target := mediaPathPrefix + asset.Hash
+	if !variant.IsOriginal() {
+		target += "?" + variantQuery(variant)
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// GetMedia serves a cached image by content hash. The content behind a hash
+// never changes, so clients may cache it forever. ?w= and ?h= (pixels, up to
+// MEDIA_MAX_DIMENSION, snapped up to MEDIA_VARIANT_SIZES) serve a smaller
+// copy, cropped to fill the box or, with ?fit=contain, fitted inside it;
+// copies are cached like the original.
+func (h *Handler) GetMedia(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["hash"]
+	variant, ok := h.mediaVariant(w, r)
+	if !ok {
+		return
+	}
+
+	asset, err := h.media.FindByHash(hash)
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Media not found",
+		})
+		return
+	}
+
+	if !variant.IsOriginal() {
+		path, err := h.media.VariantPath(asset, variant)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, media.ErrNotResizable) {
+				status = http.StatusUnprocessableEntity
+			}
+			respondJSON(w, status, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "Failed to read media variant",
+			})
+			return
+		}
+		defer file.Close()
+
+		// Content-Type is sniffed: variants are JPEG or PNG
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", `"`+filepath.Base(path)+`"`)
+		http.ServeContent(w, r, "", asset.FetchedAt, file)
+		return
+	}
+
+	file, err := os.Open(h.media.Path(hash))
+	if err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Media not found",
+		})
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", asset.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+hash+`"`)
+	http.ServeContent(w, r, "", asset.FetchedAt, file)
+}
+
+// mediaVariant reads the resize parameters of a media request; ok is false
+// after answering 400 to invalid ones
+func (h *Handler) mediaVariant(w http.ResponseWriter, r *http.Request) (media.Variant, bool) {
+	params := r.URL.Query()
+	variant, err := h.media.ParseVariant(params.Get("w"), params.Get("h"), params.Get("fit"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return media.Variant{}, false
+	}
+	return variant, true
+}
+
+// variantQuery encodes a variant back into media URL parameters
+func variantQuery(v media.Variant) string {
+	params := url.Values{}
+	if v.Width > 0 {
+		params.Set("w", strconv.Itoa(v.Width))
+	}
+	if v.Height > 0 {
+		params.Set("h", strconv.Itoa(v.Height))
+	}
+	if v.Fit != media.FitCover {
+		params.Set("fit", v.Fit)
+	}
+	return params.Encode()
+}
+
+// GetMediaManifest returns the academy logo and country flag bundle manifest
+// used by offline clients
+func (h *Handler) GetMediaManifest(w http.ResponseWriter, r *http.Request) {
+	manifest, err := h.media.BuildManifest(mediaPathPrefix)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	etag := `"` + manifest.Version + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Media manifest retrieved successfully",
+		Data:    manifest,
+	})
+}
+
+// RefreshMediaBundle caches every academy logo and country flag in background
+func (h *Handler) RefreshMediaBundle(w http.ResponseWriter, r *http.Request) {
+	logger.Info("Media bundle refresh triggered")
+
+	go func() {
+		fetched, failed := h.media.Bundle()
+		logger.Info("Media bundle refresh finished",
+			zap.Int("cached", fetched),
+			zap.Int("failed", failed))
+	}()
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Media bundle refresh started",
+	})
+}
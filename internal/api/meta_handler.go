@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/locale"
+)
+
+// CountryCount is the number of stored entities per country
+type CountryCount struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Athletes  int64  `json:"athletes"`
+	Academies int64  `json:"academies"`
+	Events    int64  `json:"events"`
+	Total     int64  `json:"total"`
+}
+
+type countryRow struct {
+	Code  string
+	Count int64
+}
+
+// GetCountries returns the distinct country codes present in the dataset
+// with counts per entity type, for building filter dropdowns. ?lang= names
+// the countries in Spanish, Portuguese or English.
+func (h *Handler) GetCountries(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+	counts := make(map[string]*CountryCount)
+	lang, localized := requestLang(r)
+
+	collect := func(model interface{}, apply func(*CountryCount, int64)) error {
+		var rows []countryRow
+		err := db.Model(model).
+			Select("UPPER(country_code) AS code, COUNT(*) AS count").
+			Where("country_code <> ''").
+			Group("UPPER(country_code)").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			code := strings.TrimSpace(row.Code)
+			if code == "" {
+				continue
+			}
+			entry, ok := counts[code]
+			if !ok {
+				entry = &CountryCount{Code: code, Name: config.GetCountryName(code)}
+				if name := locale.CountryName(lang, code); localized && name != "" {
+					entry.Name = name
+				}
+				counts[code] = entry
+			}
+			apply(entry, row.Count)
+			entry.Total += row.Count
+		}
+		return nil
+	}
+
+	sources := []struct {
+		model interface{}
+		apply func(*CountryCount, int64)
+	}{
+		{&models.Athlete{}, func(c *CountryCount, n int64) { c.Athletes = n }},
+		{&models.Academy{}, func(c *CountryCount, n int64) { c.Academies = n }},
+		{&models.Event{}, func(c *CountryCount, n int64) { c.Events = n }},
+	}
+	for _, source := range sources {
+		if err := collect(source.model, source.apply); err != nil {
+			respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   "Failed to load countries",
+			})
+			return
+		}
+	}
+
+	countries := make([]CountryCount, 0, len(counts))
+	for _, entry := range counts {
+		countries = append(countries, *entry)
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		if countries[i].Total != countries[j].Total {
+			return countries[i].Total > countries[j].Total
+		}
+		return countries[i].Code < countries[j].Code
+	})
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Countries retrieved successfully",
+		Data:    countries,
+	})
+}
+
+// GetConfig returns the effective configuration with secrets redacted
+func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Configuration retrieved successfully",
+		Data:    h.config.Latest().Redacted(),
+	})
+}
@@ -1,9 +1,16 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -44,12 +51,120 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
+// latencyMiddleware reports request durations per route template
+func latencyMiddleware(tracker *metrics.Tracker) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			next.ServeHTTP(w, r)
+
+			// Event streams last as long as the client stays connected
+			if w.Header().Get("Content-Type") == "text/event-stream" {
+				return
+			}
+
+			route := r.URL.Path
+			if current := mux.CurrentRoute(r); current != nil {
+				if template, err := current.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+			tracker.ObserveRequest(r.Method, route, time.Since(start))
+		})
+	}
+}
+
+// actorKey is the request context key of the authenticated actor
+type actorKey struct{}
+
+// adminMiddleware restricts a subrouter to requests carrying the admin API
+// key, either as X-Admin-Key or as a Bearer token, and records the admin as
+// the request's actor. Without a configured key the admin API is disabled.
+func adminMiddleware(apiKey string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" {
+				respondJSON(w, http.StatusServiceUnavailable, models.APIResponse{
+					Success: false,
+					Error:   "Admin API is disabled (ADMIN_API_KEY not set)",
+				})
+				return
+			}
+
+			if !hasAdminKey(r, apiKey) {
+				respondJSON(w, http.StatusUnauthorized, models.APIResponse{
+					Success: false,
+					Error:   "Invalid admin API key",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, withActor(r, "admin@"+remoteHost(r)))
+		})
+	}
+}
+
+// auditMiddleware protects routes that record who changed something: with
+// ADMIN_API_KEY set they require the admin key and the actor is the admin;
+// without it they stay open and the actor is anonymous@<client address>
+func auditMiddleware(apiKey string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" {
+				next.ServeHTTP(w, withActor(r, "anonymous@"+remoteHost(r)))
+				return
+			}
+
+			if !hasAdminKey(r, apiKey) {
+				respondJSON(w, http.StatusUnauthorized, models.APIResponse{
+					Success: false,
+					Error:   "Invalid admin API key",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, withActor(r, "admin@"+remoteHost(r)))
+		})
+	}
+}
+
+// withActor records the authenticated actor of r for requestActor
+func withActor(r *http.Request, actor string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), actorKey{}, actor))
+}
+
+// remoteHost is the client address of a request, without the port
+func remoteHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// hasAdminKey reports whether the request carries the admin API key, either
+// as X-Admin-Key or as a Bearer token
+func hasAdminKey(r *http.Request, apiKey string) bool {
+	if apiKey == "" {
+		return false
+	}
+	key := r.Header.Get("X-Admin-Key")
+	if key == "" {
+		key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1
+}
+
 // corsMiddleware handles CORS
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Key")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -59,3 +174,13 @@ func corsMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// requestActor identifies who issued a request, for audit records: the actor
+// set by adminMiddleware or auditMiddleware, or anonymous@<client address> on
+// routes without authentication
+func requestActor(r *http.Request) string {
+	if actor, ok := r.Context().Value(actorKey{}).(string); ok {
+		return actor
+	}
+	return "anonymous@" + remoteHost(r)
+}
@@ -0,0 +1,58 @@
+package api
+
+//go:generate go run ../../cmd/apidocs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/openapi"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// docsPage is the Swagger UI page served at /docs
+//
+//go:embed docs.html
+var docsPage []byte
+
+// buildOpenAPI describes the routes of router from the generated handler
+// docs (see cmd/apidocs); the routes of secured take the admin key
+func buildOpenAPI(router *mux.Router, secured ...*mux.Router) *openapi.Document {
+	spec, err := openapi.Builder{
+		Info: openapi.Info{
+			Title:       "Smoothcomp Scraper API",
+			Description: "Scrapes academies, athletes and events from Smoothcomp and serves the stored data.",
+			Version:     "1.0.0",
+		},
+		Docs:        handlerDocs,
+		Prefix:      "/api/v1",
+		AdminPrefix: "/api/v1/admin",
+		Secured:     secured,
+	}.Build(router)
+	if err != nil {
+		logger.Error("Failed to build OpenAPI document", zap.Error(err))
+		return nil
+	}
+	return spec
+}
+
+// GetOpenAPISpec returns the OpenAPI 3.0 document of the API
+func (h *Handler) GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	if h.openapi == nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "OpenAPI document unavailable",
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, h.openapi)
+}
+
+// GetDocs serves Swagger UI for the OpenAPI document
+func (h *Handler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docsPage)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+)
+
+// DeleteAthletePersonalData handles a data removal request: it scrubs the
+// athlete's name, pictures and profile URL, keeps its statistics, and stops
+// future scrapes from storing them again. An optional JSON body
+// {"reason": "..."} is recorded with the request.
+func (h *Handler) DeleteAthletePersonalData(w http.ResponseWriter, r *http.Request) {
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB(), mux.Vars(r)["id"], &athlete); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Athlete not found",
+		})
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request body",
+			})
+			return
+		}
+	}
+
+	entry, err := scraper.RemovePersonalData(athlete, input.Reason, requestActor(r))
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Athlete personal data removed",
+		Data:    entry,
+	})
+}
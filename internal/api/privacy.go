@@ -0,0 +1,63 @@
+package api
+
+import (
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+)
+
+// maskComparison anonymizes the minors of an athlete comparison, after it is
+// computed since opponents without an ID are matched by name
+func (h *Handler) maskComparison(comparison *analytics.AthleteComparison) {
+	if !h.privacy.Enabled() {
+		return
+	}
+
+	ids := make([]uint, 0, len(comparison.Athletes)+len(comparison.CommonOpponents))
+	for _, athlete := range comparison.Athletes {
+		ids = append(ids, uint(athlete.ID))
+	}
+	for _, opponent := range comparison.CommonOpponents {
+		if opponent.OpponentID != 0 {
+			ids = append(ids, opponent.OpponentID)
+		}
+	}
+	minors := h.privacy.MinorIDs(ids)
+
+	for i := range comparison.Athletes {
+		athlete := &comparison.Athletes[i]
+		if minors[uint(athlete.ID)] {
+			athlete.FullName = privacy.Initials(athlete.FullName)
+			athlete.Slug = ""
+		}
+	}
+	for i := range comparison.CommonOpponents {
+		opponent := &comparison.CommonOpponents[i]
+		if opponent.OpponentID != 0 && minors[opponent.OpponentID] {
+			opponent.Name = privacy.Initials(opponent.Name)
+		}
+	}
+	h.privacy.MaskMatches(comparison.HeadToHead)
+}
+
+// maskLeaderboard anonymizes minors left with their full name because the
+// deployment shows full names in kids leaderboards
+func (h *Handler) maskLeaderboard(board *analytics.KidsLeaderboard) {
+	if board.InitialsOnly || !h.privacy.Enabled() {
+		return
+	}
+
+	ids := make([]uint, len(board.Entries))
+	for i, entry := range board.Entries {
+		ids[i] = entry.AthleteID
+	}
+	minors := h.privacy.MinorIDs(ids)
+
+	for i := range board.Entries {
+		entry := &board.Entries[i]
+		if minors[entry.AthleteID] {
+			entry.Name = privacy.Initials(entry.Name)
+			entry.Slug = ""
+			entry.ImageURL = ""
+		}
+	}
+}
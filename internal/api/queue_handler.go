@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// enqueue stores a scrape on the job queue, answering 500 itself when it
+// cannot
+func (h *Handler) enqueue(w http.ResponseWriter, jobType string, params models.QueueParams) (*models.QueuedJob, bool) {
+	queued, err := h.queue.Enqueue(jobType, params)
+	if err != nil {
+		logger.Error("Failed to queue scrape", zap.String("job_type", jobType), zap.Error(err))
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return nil, false
+	}
+	return queued, true
+}
+
+// GetQueue lists queued jobs, newest first, optionally filtered by ?status=
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := db.Model(&models.QueuedJob{})
+	if status := r.URL.Query().Get("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	jobs := []models.QueuedJob{}
+	query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&jobs)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Queue retrieved successfully",
+		Data: map[string]interface{}{
+			"jobs":  jobs,
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetQueuedJob returns one queued job
+func (h *Handler) GetQueuedJob(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	var job models.QueuedJob
+	if err := config.GetDB().First(&job, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Queued job not found",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Queued job retrieved successfully",
+		Data:    job,
+	})
+}
+
+// CancelQueuedJob removes a job that has not started yet
+func (h *Handler) CancelQueuedJob(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	job, err := h.queue.Cancel(id)
+	switch {
+	case errors.Is(err, queue.ErrNotQueued):
+		message := "job is " + job.Status
+		if job.Status == queue.StatusRunning {
+			message += "; cancel its scrape job through /api/v1/jobs/{id}/cancel"
+		}
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   message,
+			Data:    job,
+		})
+		return
+	case err != nil:
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Queued job not found",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Queued job cancelled",
+		Data:    job,
+	})
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/rankings"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// GetRankings returns athletes ranked by their points in the stored ranking
+// entries, filtered by ?division=, ?belt= and ?country=. Without a division
+// the points of every matching division are added up.
+func (h *Handler) GetRankings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	ranking, err := rankings.Query(rankings.Filter{
+		Division: query.Get("division"),
+		Belt:     query.Get("belt"),
+		Country:  query.Get("country"),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load rankings",
+		})
+		return
+	}
+	h.maskRanking(ranking)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Rankings retrieved successfully",
+		Data:    ranking,
+	})
+}
+
+// RecomputeRankings rebuilds the ranking entries from the stored results
+// with the configured scoring
+func (h *Handler) RecomputeRankings(w http.ResponseWriter, r *http.Request) {
+	summary, err := rankings.Recompute(h.config.Latest().Rankings)
+	if err != nil {
+		logger.Error("Rankings recompute failed", zap.Error(err))
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to recompute rankings",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Rankings recomputed successfully",
+		Data:    summary,
+	})
+}
+
+// maskRanking anonymizes the minors of a ranking page
+func (h *Handler) maskRanking(ranking *rankings.Page) {
+	if !h.privacy.Enabled() {
+		return
+	}
+
+	ids := make([]uint, 0, len(ranking.Standings))
+	for _, standing := range ranking.Standings {
+		if standing.AthleteID != 0 {
+			ids = append(ids, standing.AthleteID)
+		}
+	}
+	minors := h.privacy.MinorIDs(ids)
+
+	for i := range ranking.Standings {
+		standing := &ranking.Standings[i]
+		if standing.AthleteID != 0 && minors[standing.AthleteID] {
+			standing.AthleteName = privacy.Initials(standing.AthleteName)
+		}
+	}
+}
@@ -3,15 +3,18 @@ package api
 import (
 	"github.com/gorilla/mux"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/live"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
 	"github.com/kmicac/smoothcomp-scraper/internal/scheduler"
 )
 
 // NewRouter creates and configures the HTTP router
-func NewRouter(cfg *config.Config, scheduler *scheduler.Scheduler) *mux.Router {
+func NewRouter(cfg *config.Config, scheduler *scheduler.Scheduler, jobs *queue.Queue, ingestor *live.Ingestor) *mux.Router {
 	router := mux.NewRouter()
 
 	// Create handler instance
-	handler := NewHandler(cfg, scheduler)
+	handler := NewHandler(cfg, scheduler, jobs, ingestor)
 
 	// API v1 routes
 	api := router.PathPrefix("/api/v1").Subrouter()
@@ -19,37 +22,173 @@ func NewRouter(cfg *config.Config, scheduler *scheduler.Scheduler) *mux.Router {
 	// Health & Status
 	api.HandleFunc("/health", handler.HealthCheck).Methods("GET")
 	api.HandleFunc("/status", handler.GetStatus).Methods("GET")
+	api.HandleFunc("/config", handler.GetConfig).Methods("GET")
+	api.HandleFunc("/openapi.json", handler.GetOpenAPISpec).Methods("GET")
+	router.HandleFunc("/docs", handler.GetDocs).Methods("GET")
 
 	// Manual scraping triggers
 	api.HandleFunc("/scrape/academies", handler.ScrapeAcademies).Methods("POST")
 	api.HandleFunc("/scrape/athletes", handler.ScrapeAthletes).Methods("POST")
 	api.HandleFunc("/scrape/all", handler.ScrapeAll).Methods("POST")
 	api.HandleFunc("/scrape/event/athletes", handler.ScrapeEventAthletes).Methods("POST")
+	api.HandleFunc("/scrape/event/brackets", handler.ScrapeEventBrackets).Methods("POST")
+	api.HandleFunc("/scrape/event/results", handler.ScrapeEventResults).Methods("POST")
 	api.HandleFunc("/scrape/athlete/profile", handler.ScrapeAthleteProfile).Methods("POST")
 	api.HandleFunc("/scrape/athletes/enrich", handler.ScrapeAthleteProfiles).Methods("POST")
 	api.HandleFunc("/scrape/events/past", handler.ScrapePastEvents).Methods("POST")
 	api.HandleFunc("/scrape/events/upcoming", handler.ScrapeUpcomingEvents).Methods("POST")
+	api.HandleFunc("/scrape/events/bulk", handler.ScrapeEventsBulk).Methods("POST")
+	api.HandleFunc("/scrape/team-rankings", handler.ScrapeTeamRankings).Methods("POST")
 
 	// Data retrieval
 	api.HandleFunc("/academies", handler.GetAcademies).Methods("GET")
+	api.HandleFunc("/academies/recompute-stats", handler.RecomputeAcademyStats).Methods("POST")
 	api.HandleFunc("/academies/{id}", handler.GetAcademyByID).Methods("GET")
+	api.HandleFunc("/academies/{id}/analytics", handler.GetAcademyAnalytics).Methods("GET")
+	api.HandleFunc("/academies/{id}/rivalry/{rival}", handler.GetAcademyRivalry).Methods("GET")
+	api.HandleFunc("/academies/{id}/team-rankings", handler.GetAcademyTeamRankings).Methods("GET")
+	api.HandleFunc("/academies/{id}/resync", handler.ResyncAcademy).Methods("POST")
 	api.HandleFunc("/athletes", handler.GetAthletes).Methods("GET")
+	api.HandleFunc("/athletes/compare", handler.CompareAthletes).Methods("GET")
+	api.HandleFunc("/athletes/federation/{federation}/{federationId}", handler.GetAthleteByFederationID).Methods("GET")
 	api.HandleFunc("/athletes/{id}", handler.GetAthleteByID).Methods("GET")
+	api.HandleFunc("/athletes/{id}/card.{format:png|jpg|jpeg}", handler.GetAthleteCard).Methods("GET")
+	api.HandleFunc("/athletes/{id}/weight", handler.GetAthleteWeight).Methods("GET")
+	api.HandleFunc("/athletes/{id}/history", handler.GetAthleteHistory).Methods("GET")
+	api.HandleFunc("/athletes/{id}/rating", handler.GetAthleteRating).Methods("GET")
+	api.HandleFunc("/athletes/{id}/streaks", handler.GetAthleteStreaks).Methods("GET")
+	api.HandleFunc("/athletes/{id}/resync", handler.ResyncAthlete).Methods("POST")
+	// Erasure keeps its place among the athlete routes but is admin only
+	personalData := api.NewRoute().Subrouter()
+	personalData.Use(adminMiddleware(cfg.Server.AdminAPIKey))
+	personalData.HandleFunc("/athletes/{id}/personal-data", handler.DeleteAthletePersonalData).Methods("DELETE")
 	api.HandleFunc("/events", handler.GetEvents).Methods("GET")
 	api.HandleFunc("/events/{id}", handler.GetEventByID).Methods("GET")
 	api.HandleFunc("/events/{id}/details", handler.GetEventDetails).Methods("GET")
+	api.HandleFunc("/events/{id}/info", handler.GetEventInfo).Methods("GET")
+	api.HandleFunc("/events/{id}/info/{panel}", handler.GetEventInfoPanel).Methods("GET")
+	api.HandleFunc("/events/{id}/divisions", handler.GetEventDivisions).Methods("GET")
+	api.HandleFunc("/events/{id}/matches", handler.GetEventMatches).Methods("GET")
+	api.HandleFunc("/events/{id}/results", handler.GetEventResults).Methods("GET")
+	api.HandleFunc("/events/{id}/live", handler.GetLiveScores).Methods("GET")
+	api.HandleFunc("/events/{id}/live/{match}", handler.GetLiveScoreTimeline).Methods("GET")
+	api.HandleFunc("/events/{id}/brackets/archive", handler.ListBracketArchives).Methods("GET")
+	api.HandleFunc("/events/{id}/brackets/archive/{archiveId:[0-9]+}", handler.GetBracketArchive).Methods("GET")
+	api.HandleFunc("/events/{id}/brackets/{division}/pdf", handler.GetBracketPDF).Methods("GET")
+
+	// Leaderboards
+	api.HandleFunc("/leaderboards/kids", handler.GetKidsLeaderboard).Methods("GET")
+
+	// Rankings
+	api.HandleFunc("/rankings", handler.GetRankings).Methods("GET")
+	api.HandleFunc("/rankings/recompute", handler.RecomputeRankings).Methods("POST")
+
+	// Dataset-wide statistics
+	api.HandleFunc("/stats/submissions", handler.GetSubmissionStats).Methods("GET")
+
+	// National athlete registries
+	api.HandleFunc("/countries/{code}/roster", handler.GetCountryRoster).Methods("GET")
+
+	// Event change digests
+	api.HandleFunc("/digests/latest", handler.GetLatestDigest).Methods("GET")
+
+	// Changes kept in an audit trail or spending API quota require
+	// ADMIN_API_KEY when it is set
+	audited := api.NewRoute().Subrouter()
+	audited.Use(auditMiddleware(cfg.Server.AdminAPIKey))
+
+	// Match videos
+	api.HandleFunc("/matches/{id:[0-9]+}/videos", handler.GetMatchVideos).Methods("GET")
+	audited.HandleFunc("/matches/{id:[0-9]+}/videos/link", handler.LinkMatchVideos).Methods("POST")
+	audited.HandleFunc("/matches/{id:[0-9]+}/videos/{videoId:[0-9]+}", handler.UpdateMatchVideo).Methods("PUT")
+
+	// Media proxy and offline bundle
+	api.HandleFunc("/media", handler.ProxyMedia).Methods("GET")
+	api.HandleFunc("/media/manifest", handler.GetMediaManifest).Methods("GET")
+	api.HandleFunc("/media/manifest/refresh", handler.RefreshMediaBundle).Methods("POST")
+	api.HandleFunc("/media/{hash:[0-9a-f]{64}}", handler.GetMedia).Methods("GET")
+
+	// Metadata
+	api.HandleFunc("/meta/countries", handler.GetCountries).Methods("GET")
 
 	// Schedule configuration
-	api.HandleFunc("/schedule/config", handler.GetScheduleConfig).Methods("GET")
-	api.HandleFunc("/schedule/config", handler.UpdateScheduleConfig).Methods("PUT")
+	api.HandleFunc("/schedules", handler.ListSchedules).Methods("GET")
+	audited.HandleFunc("/schedules", handler.CreateSchedule).Methods("POST")
+	api.HandleFunc("/schedules/audit", handler.GetScheduleAudit).Methods("GET")
+	api.HandleFunc("/schedules/{id:[0-9]+}", handler.GetSchedule).Methods("GET")
+	audited.HandleFunc("/schedules/{id:[0-9]+}", handler.UpdateSchedule).Methods("PUT")
+	audited.HandleFunc("/schedules/{id:[0-9]+}", handler.DeleteSchedule).Methods("DELETE")
+	audited.HandleFunc("/schedules/{id:[0-9]+}/enable", handler.EnableSchedule).Methods("POST")
+	audited.HandleFunc("/schedules/{id:[0-9]+}/disable", handler.DisableSchedule).Methods("POST")
+	api.HandleFunc("/schedules/{id:[0-9]+}/audit", handler.GetScheduleAudit).Methods("GET")
+
+	// Saved queries
+	api.HandleFunc("/saved-queries", handler.ListSavedQueries).Methods("GET")
+	api.HandleFunc("/saved-queries", handler.CreateSavedQuery).Methods("POST")
+	api.HandleFunc("/saved-queries/{id:[0-9]+}", handler.GetSavedQuery).Methods("GET")
+	api.HandleFunc("/saved-queries/{id:[0-9]+}", handler.UpdateSavedQuery).Methods("PUT")
+	api.HandleFunc("/saved-queries/{id:[0-9]+}", handler.DeleteSavedQuery).Methods("DELETE")
+	api.HandleFunc("/saved-queries/{id:[0-9]+}/run", handler.RunSavedQuery).Methods("GET", "POST")
 
 	// Jobs history
 	api.HandleFunc("/jobs", handler.GetJobs).Methods("GET")
+	api.HandleFunc("/jobs/stream", handler.StreamJobs).Methods("GET")
+	api.HandleFunc("/jobs/summary", handler.GetJobsSummary).Methods("GET")
 	api.HandleFunc("/jobs/{id}", handler.GetJobByID).Methods("GET")
+	api.HandleFunc("/jobs/{id:[0-9]+}/progress", handler.GetJobProgress).Methods("GET")
+	api.HandleFunc("/jobs/{id:[0-9]+}/cancel", handler.CancelJob).Methods("POST")
+	api.HandleFunc("/queue", handler.GetQueue).Methods("GET")
+	api.HandleFunc("/queue/{id:[0-9]+}", handler.GetQueuedJob).Methods("GET")
+	api.HandleFunc("/queue/{id:[0-9]+}", handler.CancelQueuedJob).Methods("DELETE")
+
+	// Admin (requires ADMIN_API_KEY)
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(adminMiddleware(cfg.Server.AdminAPIKey))
+	admin.HandleFunc("/config/reload", handler.ReloadConfig).Methods("POST")
+	admin.HandleFunc("/log-level", handler.GetLogLevel).Methods("GET")
+	admin.HandleFunc("/log-level", handler.SetLogLevel).Methods("PUT")
+	admin.HandleFunc("/proxies", handler.GetProxies).Methods("GET")
+	admin.HandleFunc("/crawl-state", handler.ClearCrawlState).Methods("DELETE")
+	admin.HandleFunc("/latency", handler.GetLatencyReport).Methods("GET")
+	admin.HandleFunc("/parse-coverage", handler.GetParseCoverage).Methods("GET")
+	admin.HandleFunc("/jobs/{id:[0-9]+}/recording", handler.GetJobRecording).Methods("GET")
+	admin.HandleFunc("/blocklist", handler.ListBlockedEntities).Methods("GET")
+	admin.HandleFunc("/blocklist", handler.BlockEntity).Methods("POST")
+	admin.HandleFunc("/blocklist/{id:[0-9]+}", handler.UnblockEntity).Methods("DELETE")
+	admin.HandleFunc("/tags", handler.ListTags).Methods("GET")
+	admin.HandleFunc("/tags", handler.TagEntity).Methods("POST")
+	admin.HandleFunc("/tags/names", handler.ListTagNames).Methods("GET")
+	admin.HandleFunc("/tags/{id:[0-9]+}", handler.UntagEntity).Methods("DELETE")
+	admin.HandleFunc("/academy-contacts", handler.ListAcademyContacts).Methods("GET")
+	admin.HandleFunc("/academies/{id}/contact-opt-out", handler.OptOutAcademyContact).Methods("POST")
+	admin.HandleFunc("/academies/{id}/contact-opt-out", handler.RemoveAcademyContactOptOut).Methods("DELETE")
+	admin.HandleFunc("/federation-ids", handler.ListFederationIDs).Methods("GET")
+	admin.HandleFunc("/federation-ids/import", handler.ImportFederationIDs).Methods("POST")
+	admin.HandleFunc("/federation-ids/{id:[0-9]+}", handler.DeleteFederationID).Methods("DELETE")
+	admin.HandleFunc("/legacy/import/{kind:athletes|results}", handler.ImportLegacyData).Methods("POST")
+	admin.HandleFunc("/athlete-aliases", handler.ListAthleteAliases).Methods("GET")
+	admin.HandleFunc("/event-url-aliases", handler.ListEventURLAliases).Methods("GET")
+	admin.HandleFunc("/events/{id}/event-cards", handler.DownloadEventCards).Methods("GET")
+	admin.HandleFunc("/live", handler.ListLiveStreams).Methods("GET")
+	admin.HandleFunc("/live/{id}", handler.StartLiveStream).Methods("POST")
+	admin.HandleFunc("/live/{id}", handler.StopLiveStream).Methods("DELETE")
+	admin.HandleFunc("/subscribers", handler.ListSubscribers).Methods("GET")
+	admin.HandleFunc("/subscribers", handler.CreateSubscriber).Methods("POST")
+	admin.HandleFunc("/subscribers/{id:[0-9]+}", handler.UpdateSubscriber).Methods("PUT")
+	admin.HandleFunc("/subscribers/{id:[0-9]+}", handler.DeleteSubscriber).Methods("DELETE")
+
+	// API description, built from every route above
+	handler.openapi = buildOpenAPI(router, audited, personalData)
 
 	// Middleware
 	router.Use(loggingMiddleware)
+	router.Use(gzipMiddleware)
 	router.Use(corsMiddleware)
+	router.Use(jsonAPIMiddleware)
+	router.Use(localeMiddleware)
+	if tracker := metrics.Default(); tracker != nil {
+		router.Use(latencyMiddleware(tracker))
+	}
 
 	return router
 }
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+)
+
+// parseRunOptions reads ?max_duration= (a duration such as "30m", or seconds),
+// ?resume= (a token from a partial job of jobType), ?debug=true and
+// ?profile= (a behavior preset such as "polite")
+func parseRunOptions(r *http.Request, jobType string) (scraper.RunOptions, error) {
+	var opts scraper.RunOptions
+	query := r.URL.Query()
+
+	if raw := query.Get("max_duration"); raw != "" {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			seconds, convErr := strconv.Atoi(raw)
+			if convErr != nil {
+				return opts, fmt.Errorf("invalid max_duration %q", raw)
+			}
+			duration = time.Duration(seconds) * time.Second
+		}
+		if duration <= 0 {
+			return opts, fmt.Errorf("max_duration must be positive")
+		}
+		opts.MaxDuration = duration
+	}
+
+	if token := query.Get("resume"); token != "" {
+		point, err := scraper.ParseResumeToken(token, jobType)
+		if err != nil {
+			return opts, err
+		}
+		opts.Resume = point
+	}
+
+	if raw := query.Get("debug"); raw != "" {
+		debug, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid debug %q", raw)
+		}
+		opts.Debug = debug
+	}
+
+	if name := query.Get("profile"); name != "" {
+		behavior, err := scraper.LookupBehavior(name)
+		if err != nil {
+			return opts, err
+		}
+		opts.Behavior = behavior
+	}
+
+	return opts, nil
+}
+
+// queueRunParams stores the run options of a request on a queued job as the
+// client sent them; the queue parses them again when the job runs
+func queueRunParams(r *http.Request, opts scraper.RunOptions) models.QueueParams {
+	query := r.URL.Query()
+	return models.QueueParams{
+		MaxDuration: int(opts.MaxDuration.Seconds()),
+		Resume:      query.Get("resume"),
+		Debug:       opts.Debug,
+		Profile:     query.Get("profile"),
+	}
+}
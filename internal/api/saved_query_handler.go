@@ -0,0 +1,274 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/savedquery"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// savedQueryInput is the body of saved query create and update requests
+type savedQueryInput struct {
+	Name       *string                   `json:"name"`
+	Target     *string                   `json:"target"`
+	Filters    *models.SavedQueryFilters `json:"filters"`
+	WebhookURL *string                   `json:"webhook_url"`
+}
+
+// ListSavedQueries returns the saved queries
+func (h *Handler) ListSavedQueries(w http.ResponseWriter, r *http.Request) {
+	queries := []models.SavedQuery{}
+	config.GetDB().Order("id").Find(&queries)
+	if !hasAdminKey(r, h.config.Server.AdminAPIKey) {
+		for i := range queries {
+			queries[i].WebhookURL = ""
+		}
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Saved queries retrieved successfully",
+		Data:    queries,
+	})
+}
+
+// GetSavedQuery returns one saved query
+func (h *Handler) GetSavedQuery(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.loadSavedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Saved query retrieved successfully",
+		Data:    query,
+	})
+}
+
+// CreateSavedQuery stores a named filter set. Attaching a webhook needs the
+// admin API key.
+func (h *Handler) CreateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	var input savedQueryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	query := models.SavedQuery{Actor: requestActor(r)}
+	if status, msg := h.applySavedQueryInput(r, &query, input); msg != "" {
+		respondJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := config.GetDB().Create(&query).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err := savedquery.Snapshot(&query); err != nil {
+		logger.Warn("Failed to snapshot saved query", zap.Int("query_id", query.ID), zap.Error(err))
+	}
+
+	logger.Info("Saved query created",
+		zap.Int("query_id", query.ID),
+		zap.String("target", query.Target),
+		zap.String("actor", query.Actor))
+
+	respondJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Saved query created",
+		Data:    query,
+	})
+}
+
+// UpdateSavedQuery changes the name, target, filters or webhook of a saved
+// query. New filters reset the baseline used for change notifications.
+func (h *Handler) UpdateSavedQuery(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.loadSavedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	var input savedQueryInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+	if status, msg := h.applySavedQueryInput(r, query, input); msg != "" {
+		respondJSON(w, status, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := config.GetDB().Save(query).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if input.Target != nil || input.Filters != nil {
+		if err := savedquery.Snapshot(query); err != nil {
+			logger.Warn("Failed to snapshot saved query", zap.Int("query_id", query.ID), zap.Error(err))
+		}
+	}
+
+	logger.Info("Saved query updated",
+		zap.Int("query_id", query.ID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Saved query updated",
+		Data:    query,
+	})
+}
+
+// DeleteSavedQuery removes a saved query
+func (h *Handler) DeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.loadSavedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	if err := config.GetDB().Delete(query).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Saved query deleted",
+		zap.Int("query_id", query.ID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Saved query deleted",
+	})
+}
+
+// RunSavedQuery executes a saved query. Accepts ?limit= (at most 500) and ?offset=.
+func (h *Handler) RunSavedQuery(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.loadSavedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	result, err := savedquery.Run(query, limit, offset)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to run saved query",
+		})
+		return
+	}
+
+	switch rows := result.Rows.(type) {
+	case []models.Athlete:
+		h.privacy.MaskAthletes(rows)
+	case []models.EventRegistration:
+		athletes := make([]models.Athlete, len(rows))
+		for i := range rows {
+			athletes[i] = rows[i].Athlete
+		}
+		h.privacy.MaskAthletes(athletes)
+		for i := range rows {
+			rows[i].Athlete = athletes[i]
+		}
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Saved query executed successfully",
+		Data: map[string]interface{}{
+			"query":  query,
+			"total":  result.Total,
+			"limit":  limit,
+			"offset": offset,
+			"rows":   result.Rows,
+		},
+	})
+}
+
+// loadSavedQuery finds the saved query of the {id} route variable, hiding
+// its webhook from non-admin callers; ok is false after answering 404
+func (h *Handler) loadSavedQuery(w http.ResponseWriter, r *http.Request) (*models.SavedQuery, bool) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	var query models.SavedQuery
+	if err := config.GetDB().First(&query, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Saved query not found",
+		})
+		return nil, false
+	}
+	if r.Method == http.MethodGet && !hasAdminKey(r, h.config.Server.AdminAPIKey) {
+		query.WebhookURL = ""
+	}
+	return &query, true
+}
+
+// applySavedQueryInput copies the provided fields onto query and returns a
+// status and validation message, empty when the result is valid
+func (h *Handler) applySavedQueryInput(r *http.Request, query *models.SavedQuery, input savedQueryInput) (int, string) {
+	if input.Name != nil {
+		query.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Target != nil {
+		query.Target = strings.TrimSpace(*input.Target)
+	}
+	if input.Filters != nil {
+		query.Filters = *input.Filters
+	}
+	if input.WebhookURL != nil {
+		if !hasAdminKey(r, h.config.Server.AdminAPIKey) {
+			return http.StatusUnauthorized, "webhook_url requires the admin API key"
+		}
+		query.WebhookURL = strings.TrimSpace(*input.WebhookURL)
+	}
+
+	if err := savedquery.Validate(query); err != nil {
+		return http.StatusBadRequest, err.Error()
+	}
+	if query.WebhookURL != "" {
+		if parsed, err := url.Parse(query.WebhookURL); err != nil ||
+			(parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return http.StatusBadRequest, "webhook_url must be an http(s) URL"
+		}
+	}
+	return 0, ""
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scheduler"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type scheduleInput struct {
+	Name     string                 `json:"name"`
+	CronExpr string                 `json:"cron_expr"`
+	JobType  string                 `json:"job_type"`
+	Params   *models.ScheduleParams `json:"params"`
+	Enabled  *bool                  `json:"enabled"`
+}
+
+// ListSchedules returns all schedule configurations
+func (h *Handler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	var schedules []models.ScheduleConfig
+	db.Order("id ASC").Find(&schedules)
+	h.scheduler.Describe(schedules)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Schedules retrieved successfully",
+		Data:    schedules,
+	})
+}
+
+// GetSchedule returns a single schedule configuration
+func (h *Handler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := loadSchedule(w, r)
+	if !ok {
+		return
+	}
+	h.describeSchedule(&schedule)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Schedule retrieved successfully",
+		Data:    schedule,
+	})
+}
+
+// CreateSchedule adds a new schedule configuration
+func (h *Handler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var input scheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	if input.JobType == "" {
+		input.JobType = scheduler.JobTypeAll
+	}
+
+	enabled := input.Enabled == nil || *input.Enabled
+	schedule := models.ScheduleConfig{
+		Name:     strings.TrimSpace(input.Name),
+		CronExpr: input.CronExpr,
+		JobType:  input.JobType,
+		Enabled:  enabled,
+	}
+	if input.Params != nil {
+		schedule.Params = *input.Params
+	}
+	if !validSchedule(w, schedule) {
+		return
+	}
+
+	db := config.GetDB()
+	err := db.Create(&schedule).Error
+	// enabled has a column default, so an explicit false must be written separately
+	if err == nil && !enabled {
+		err = db.Model(&schedule).Update("enabled", false).Error
+	}
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to create schedule",
+		})
+		return
+	}
+	recordScheduleAudit(r, "create", schedule.ID, nil, &schedule)
+
+	if err := h.scheduler.ApplySchedule(schedule); err != nil {
+		logger.Error("Failed to apply schedule", zap.Error(err))
+	}
+	h.describeSchedule(&schedule)
+
+	respondJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Schedule created successfully",
+		Data:    schedule,
+	})
+}
+
+// UpdateSchedule replaces the name, cron expression, job type, parameters
+// and/or enabled flag of a schedule
+func (h *Handler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	var input scheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	before := schedule
+	if name := strings.TrimSpace(input.Name); name != "" {
+		schedule.Name = name
+	}
+	if input.CronExpr != "" {
+		schedule.CronExpr = input.CronExpr
+	}
+	if input.JobType != "" {
+		schedule.JobType = input.JobType
+	}
+	if input.Params != nil {
+		schedule.Params = *input.Params
+	}
+	if input.Enabled != nil {
+		schedule.Enabled = *input.Enabled
+	}
+	if !validSchedule(w, schedule) {
+		return
+	}
+
+	h.saveSchedule(w, r, "update", before, schedule)
+}
+
+// EnableSchedule turns a schedule on
+func (h *Handler) EnableSchedule(w http.ResponseWriter, r *http.Request) {
+	h.setScheduleEnabled(w, r, true)
+}
+
+// DisableSchedule turns a schedule off without deleting it
+func (h *Handler) DisableSchedule(w http.ResponseWriter, r *http.Request) {
+	h.setScheduleEnabled(w, r, false)
+}
+
+// DeleteSchedule removes a schedule configuration
+func (h *Handler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	db := config.GetDB()
+	if err := db.Delete(&schedule).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to delete schedule",
+		})
+		return
+	}
+	recordScheduleAudit(r, "delete", schedule.ID, &schedule, nil)
+
+	h.scheduler.RemoveSchedule(schedule.ID)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Schedule deleted successfully",
+	})
+}
+
+// GetScheduleAudit returns the change history of schedule configurations
+func (h *Handler) GetScheduleAudit(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	query := db.Model(&models.ScheduleAudit{})
+	if id := mux.Vars(r)["id"]; id != "" {
+		query = query.Where("schedule_id = ?", id)
+	}
+
+	var entries []models.ScheduleAudit
+	query.Order("created_at DESC").Limit(limit).Find(&entries)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Schedule audit retrieved successfully",
+		Data:    entries,
+	})
+}
+
+func (h *Handler) setScheduleEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	schedule, ok := loadSchedule(w, r)
+	if !ok {
+		return
+	}
+
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+
+	before := schedule
+	schedule.Enabled = enabled
+	h.saveSchedule(w, r, action, before, schedule)
+}
+
+func (h *Handler) saveSchedule(w http.ResponseWriter, r *http.Request, action string, before, schedule models.ScheduleConfig) {
+	db := config.GetDB()
+	if err := db.Save(&schedule).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to update schedule",
+		})
+		return
+	}
+	recordScheduleAudit(r, action, schedule.ID, &before, &schedule)
+
+	if err := h.scheduler.ApplySchedule(schedule); err != nil {
+		logger.Error("Failed to apply schedule", zap.Error(err))
+	}
+	h.describeSchedule(&schedule)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Schedule updated successfully",
+		Data:    schedule,
+	})
+}
+
+// validSchedule checks a schedule before it is stored, answering 400 or 409
+// when it is not valid
+func validSchedule(w http.ResponseWriter, schedule models.ScheduleConfig) bool {
+	if err := scheduler.ValidateSchedule(schedule); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	var count int64
+	config.GetDB().Model(&models.ScheduleConfig{}).
+		Where("name = ? AND id <> ?", schedule.Name, schedule.ID).Count(&count)
+	if count > 0 {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "A schedule with this name already exists",
+		})
+		return false
+	}
+	return true
+}
+
+func (h *Handler) describeSchedule(schedule *models.ScheduleConfig) {
+	one := []models.ScheduleConfig{*schedule}
+	h.scheduler.Describe(one)
+	*schedule = one[0]
+}
+
+func loadSchedule(w http.ResponseWriter, r *http.Request) (models.ScheduleConfig, bool) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var schedule models.ScheduleConfig
+	if err := db.First(&schedule, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Schedule not found",
+		})
+		return schedule, false
+	}
+
+	return schedule, true
+}
+
+// recordScheduleAudit stores a before/after snapshot of a schedule change
+func recordScheduleAudit(r *http.Request, action string, scheduleID int, before, after *models.ScheduleConfig) {
+	entry := models.ScheduleAudit{
+		ScheduleID: scheduleID,
+		Action:     action,
+		Actor:      requestActor(r),
+	}
+	if before != nil {
+		if data, err := json.Marshal(before); err == nil {
+			entry.Before = string(data)
+		}
+	}
+	if after != nil {
+		if data, err := json.Marshal(after); err == nil {
+			entry.After = string(data)
+		}
+	}
+
+	if err := config.GetDB().Create(&entry).Error; err != nil {
+		logger.Error("Failed to record schedule audit", zap.Error(err))
+	}
+}
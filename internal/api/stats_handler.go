@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// GetSubmissionStats summarizes the most common finishing submissions across
+// all stored matches, by belt, weight class, gender and year. ?belt=,
+// ?weight_class=, ?gender=, ?year=, ?from= and ?to= narrow the matches;
+// ?limit= sets how many techniques each group lists.
+func (h *Handler) GetSubmissionStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	gender, _ := models.ParseGender(query.Get("gender"))
+	filter := analytics.SubmissionFilter{
+		Belt:        query.Get("belt"),
+		WeightClass: query.Get("weight_class"),
+		Gender:      gender,
+		Limit:       limit,
+	}
+
+	if raw := query.Get("year"); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil || year < 1900 || year > 9999 {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "year must be a four-digit year",
+			})
+			return
+		}
+		filter.Year = year
+	}
+	for _, bound := range []struct {
+		name string
+		dest **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := query.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   bound.name + " must be a date (YYYY-MM-DD)",
+			})
+			return
+		}
+		*bound.dest = &parsed
+	}
+
+	stats, err := analytics.SubmissionMeta(filter)
+	if err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to compute submission statistics",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Submission statistics retrieved successfully",
+		Data:    stats,
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// streamBatch is how many rows a streamed listing reads at a time
+const streamBatch = 1000
+
+// streamWriteWindow is how long each batch may take to reach the client; it
+// moves the server's write deadline along so long exports are not cut off
+const streamWriteWindow = 30 * time.Second
+
+// wantsAll reports whether a listing was asked for every row with ?all=true
+func wantsAll(r *http.Request) bool {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	return all
+}
+
+// streamList writes the usual listing envelope, {"success", "message",
+// "data": {"total", key: [...]}}, with every row of query in ID order. Rows
+// are read and encoded batch by batch, after prepare (privacy masking), so
+// the whole list is never held in memory. A failure after the first byte
+// can no longer change the status; the body is left unterminated instead.
+func streamList[T any](w http.ResponseWriter, r *http.Request, query *gorm.DB, key, message string, prepare func([]T)) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	out := bufio.NewWriterSize(w, 64*1024)
+
+	head, _ := json.Marshal(message)
+	out.WriteString(`{"success":true,"message":`)
+	out.Write(head)
+	out.WriteString(`,"data":{"total":` + strconv.FormatInt(total, 10) + `,` + strconv.Quote(key) + `:[`)
+
+	first := true
+	var batch []T
+	err := query.FindInBatches(&batch, streamBatch, func(tx *gorm.DB, _ int) error {
+		if prepare != nil {
+			prepare(batch)
+		}
+		for i := range batch {
+			row, err := json.Marshal(&batch[i])
+			if err != nil {
+				return err
+			}
+			if !first {
+				out.WriteByte(',')
+			}
+			first = false
+			out.Write(row)
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		_ = controller.Flush()
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+		return r.Context().Err()
+	}).Error
+	if err != nil {
+		logger.Warn("Streamed listing stopped",
+			zap.String("path", r.URL.Path),
+			zap.Error(err))
+		out.Flush()
+		return
+	}
+
+	out.WriteString("]}}\n")
+	out.Flush()
+}
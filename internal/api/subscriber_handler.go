@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// subscriberInput is the body of subscriber create and update requests
+type subscriberInput struct {
+	Name       *string `json:"name"`
+	WebhookURL *string `json:"webhook_url"`
+	Filter     *string `json:"filter"`
+	Enabled    *bool   `json:"enabled"`
+}
+
+// ListSubscribers returns the new-event notification subscribers
+func (h *Handler) ListSubscribers(w http.ResponseWriter, r *http.Request) {
+	subscribers := []models.NotificationSubscriber{}
+	config.GetDB().Order("id").Find(&subscribers)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Subscribers retrieved successfully",
+		Data:    subscribers,
+	})
+}
+
+// CreateSubscriber registers a webhook notified of new events matching a filter
+func (h *Handler) CreateSubscriber(w http.ResponseWriter, r *http.Request) {
+	var input subscriberInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	subscriber := models.NotificationSubscriber{Enabled: true}
+	if msg := applySubscriberInput(&subscriber, input); msg != "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := config.GetDB().Create(&subscriber).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Notification subscriber created",
+		zap.Int("subscriber_id", subscriber.ID),
+		zap.String("filter", subscriber.Filter),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Subscriber created",
+		Data:    subscriber,
+	})
+}
+
+// UpdateSubscriber changes the name, webhook, filter or enabled flag of a subscriber
+func (h *Handler) UpdateSubscriber(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var subscriber models.NotificationSubscriber
+	if err := db.First(&subscriber, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Subscriber not found",
+		})
+		return
+	}
+
+	var input subscriberInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+	if msg := applySubscriberInput(&subscriber, input); msg != "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   msg,
+		})
+		return
+	}
+
+	if err := db.Save(&subscriber).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Notification subscriber updated",
+		zap.Int("subscriber_id", subscriber.ID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Subscriber updated",
+		Data:    subscriber,
+	})
+}
+
+// DeleteSubscriber removes a subscriber
+func (h *Handler) DeleteSubscriber(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var subscriber models.NotificationSubscriber
+	if err := db.First(&subscriber, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Subscriber not found",
+		})
+		return
+	}
+
+	if err := db.Delete(&subscriber).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Notification subscriber deleted",
+		zap.Int("subscriber_id", subscriber.ID),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Subscriber deleted",
+	})
+}
+
+// applySubscriberInput copies the provided fields onto subscriber and
+// returns a validation message, empty when the result is valid
+func applySubscriberInput(subscriber *models.NotificationSubscriber, input subscriberInput) string {
+	if input.Name != nil {
+		subscriber.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.WebhookURL != nil {
+		subscriber.WebhookURL = strings.TrimSpace(*input.WebhookURL)
+	}
+	if input.Filter != nil {
+		subscriber.Filter = strings.TrimSpace(*input.Filter)
+	}
+	if input.Enabled != nil {
+		subscriber.Enabled = *input.Enabled
+	}
+
+	if subscriber.Name == "" {
+		return "name is required"
+	}
+	if parsed, err := url.Parse(subscriber.WebhookURL); err != nil ||
+		(parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "webhook_url must be an http(s) URL"
+	}
+	if _, err := notify.ParseFilter(subscriber.Filter); err != nil {
+		return "invalid filter: " + err.Error()
+	}
+	return ""
+}
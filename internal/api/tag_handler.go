@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TagCount is how many entities of a type carry a tag
+type TagCount struct {
+	EntityType string `json:"entity_type"`
+	Tag        string `json:"tag"`
+	Count      int64  `json:"count"`
+}
+
+// ListTags returns tag assignments, filtered by ?entity_type=, ?external_id=
+// and ?tag=
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	query := config.GetDB().Model(&models.EntityTag{})
+	if entityType := r.URL.Query().Get("entity_type"); entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if externalID := r.URL.Query().Get("external_id"); externalID != "" {
+		query = query.Where("external_id = ?", externalID)
+	}
+	if tag := models.NormalizeTag(r.URL.Query().Get("tag")); tag != "" {
+		query = query.Where("tag = ?", tag)
+	}
+
+	tags := []models.EntityTag{}
+	query.Order("entity_type, tag, external_id").Find(&tags)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Tags retrieved successfully",
+		Data:    tags,
+	})
+}
+
+// ListTagNames returns the tags in use with how many entities carry each
+func (h *Handler) ListTagNames(w http.ResponseWriter, r *http.Request) {
+	counts := []TagCount{}
+	config.GetDB().Model(&models.EntityTag{}).
+		Select("entity_type, tag, COUNT(*) AS count").
+		Group("entity_type, tag").
+		Order("entity_type, tag").
+		Scan(&counts)
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Tag names retrieved successfully",
+		Data:    counts,
+	})
+}
+
+// TagEntity labels a stored athlete or academy with a tag
+func (h *Handler) TagEntity(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		EntityType string `json:"entity_type"`
+		ExternalID string `json:"external_id"`
+		Tag        string `json:"tag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request body",
+		})
+		return
+	}
+
+	input.ExternalID = strings.TrimSpace(input.ExternalID)
+	input.Tag = models.NormalizeTag(input.Tag)
+	if !models.IsTaggedEntityType(input.EntityType) || input.ExternalID == "" || input.Tag == "" {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "entity_type (athlete, academy), external_id and tag are required",
+		})
+		return
+	}
+	if utf8.RuneCountInString(input.Tag) > models.MaxTagLength {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("tag is longer than %d characters", models.MaxTagLength),
+		})
+		return
+	}
+
+	db := config.GetDB()
+	var target interface{} = &models.Athlete{}
+	if input.EntityType == models.TaggedAcademy {
+		target = &models.Academy{}
+	}
+	var count int64
+	db.Model(target).Where("external_id = ?", input.ExternalID).Count(&count)
+	if count == 0 {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   input.EntityType + " not found",
+		})
+		return
+	}
+
+	entry := models.EntityTag{
+		EntityType: input.EntityType,
+		ExternalID: input.ExternalID,
+		Tag:        input.Tag,
+	}
+	if err := db.Where(&entry).First(&entry).Error; err == nil {
+		respondJSON(w, http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "entity already has this tag",
+			Data:    entry,
+		})
+		return
+	}
+
+	entry.Actor = requestActor(r)
+	if err := db.Create(&entry).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Entity tagged",
+		zap.String("entity_type", entry.EntityType),
+		zap.String("external_id", entry.ExternalID),
+		zap.String("tag", entry.Tag),
+		zap.String("actor", entry.Actor))
+
+	respondJSON(w, http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Entity tagged",
+		Data:    entry,
+	})
+}
+
+// UntagEntity removes a tag assignment
+func (h *Handler) UntagEntity(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	db := config.GetDB()
+	var entry models.EntityTag
+	if err := db.First(&entry, id).Error; err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Tag not found",
+		})
+		return
+	}
+
+	if err := db.Delete(&entry).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Entity untagged",
+		zap.String("entity_type", entry.EntityType),
+		zap.String("external_id", entry.ExternalID),
+		zap.String("tag", entry.Tag),
+		zap.String("actor", requestActor(r)))
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Tag removed",
+		Data:    entry,
+	})
+}
+
+// filterByTags narrows query to the entities carrying every tag of the
+// comma-separated ?tag= parameter. Tags are scouting data, so the filter
+// needs the admin API key; ok is false after answering 401 otherwise.
+func (h *Handler) filterByTags(w http.ResponseWriter, r *http.Request, query *gorm.DB, entityType string) (*gorm.DB, bool) {
+	raw := r.URL.Query().Get("tag")
+	if raw == "" {
+		return query, true
+	}
+	if !hasAdminKey(r, h.config.Server.AdminAPIKey) {
+		respondJSON(w, http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "Filtering by tag requires the admin API key",
+		})
+		return nil, false
+	}
+
+	db := config.GetDB()
+	for _, tag := range strings.Split(raw, ",") {
+		if tag = models.NormalizeTag(tag); tag == "" {
+			continue
+		}
+		query = query.Where("external_id IN (?)", db.Model(&models.EntityTag{}).Select("external_id").
+			Where("entity_type = ? AND tag = ?", entityType, tag))
+	}
+	return query, true
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// teamRankingTrajectory is the ranking history of an academy in one
+// federation season, oldest first
+type teamRankingTrajectory struct {
+	Federation string             `json:"federation"`
+	Season     string             `json:"season"`
+	BestRank   int                `json:"best_rank"`
+	Latest     teamRankingPoint   `json:"latest"`
+	History    []teamRankingPoint `json:"history"`
+}
+
+type teamRankingPoint struct {
+	Rank       int       `json:"rank"`
+	Points     float64   `json:"points"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// ScrapeTeamRankings queues a scrape of the club ranking of ?federation=
+// (a smoothcomp subdomain such as ajp) for ?season=, or the current season
+func (h *Handler) ScrapeTeamRankings(w http.ResponseWriter, r *http.Request) {
+	federation, err := scraper.NormalizeFederation(r.URL.Query().Get("federation"))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	season := r.URL.Query().Get("season")
+
+	logger.Info("Manual team rankings scraping triggered",
+		zap.String("federation", federation),
+		zap.String("season", season))
+
+	queued, ok := h.enqueue(w, queue.JobTypeTeamRankings, models.QueueParams{Federation: federation, Season: season})
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Team rankings scraping queued",
+		Data: map[string]interface{}{
+			"federation": federation,
+			"season":     season,
+			"queued":     queued,
+		},
+	})
+}
+
+// GetAcademyTeamRankings returns the ranking trajectory of an academy per
+// federation and season, optionally filtered by ?federation= and ?season=
+func (h *Handler) GetAcademyTeamRankings(w http.ResponseWriter, r *http.Request) {
+	db := config.GetDB()
+
+	var academy models.Academy
+	if err := findByIDOrSlug(db, mux.Vars(r)["id"], &academy); err != nil {
+		respondJSON(w, http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Academy not found",
+		})
+		return
+	}
+
+	query := db.Where("academy_external_id = ?", academy.ExternalID)
+	if federation := r.URL.Query().Get("federation"); federation != "" {
+		normalized, err := scraper.NormalizeFederation(federation)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		query = query.Where("federation = ?", normalized)
+	}
+	if season := r.URL.Query().Get("season"); season != "" {
+		query = query.Where("season = ?", season)
+	}
+
+	var entries []models.TeamRankingEntry
+	if err := query.Order("federation, season, captured_at, id").Find(&entries).Error; err != nil {
+		respondJSON(w, http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load team rankings",
+		})
+		return
+	}
+
+	trajectories := []*teamRankingTrajectory{}
+	var current *teamRankingTrajectory
+	for _, entry := range entries {
+		if current == nil || current.Federation != entry.Federation || current.Season != entry.Season {
+			current = &teamRankingTrajectory{Federation: entry.Federation, Season: entry.Season}
+			trajectories = append(trajectories, current)
+		}
+		point := teamRankingPoint{
+			Rank:       entry.Rank,
+			Points:     entry.Points,
+			CapturedAt: entry.CapturedAt,
+		}
+		current.History = append(current.History, point)
+		current.Latest = point
+		if current.BestRank == 0 || entry.Rank < current.BestRank {
+			current.BestRank = entry.Rank
+		}
+	}
+
+	respondJSON(w, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Team rankings retrieved successfully",
+		Data: map[string]interface{}{
+			"academy_id":   academy.ID,
+			"academy_name": academy.Name,
+			"trajectories": trajectories,
+		},
+	})
+}
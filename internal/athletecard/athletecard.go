@@ -0,0 +1,451 @@
+// Package athletecard renders share-able athlete profile cards (photo, belt,
+// record and academy) as PNG or JPEG images from the stored data.
+package athletecard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // photo decoders
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/webp" // photo decoder
+)
+
+// Image formats a card can be encoded as
+const (
+	PNG  = "png"
+	JPEG = "jpeg"
+)
+
+// maxPhotoPixels caps the size of a photo to decode
+const maxPhotoPixels = 25_000_000
+
+// Layout is the canvas size of a card
+type Layout struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// DefaultLayout is used when no layout is requested
+const DefaultLayout = "square"
+
+var layouts = map[string]Layout{
+	"square":    {Name: "square", Width: 1080, Height: 1080},   // feed posts
+	"story":     {Name: "story", Width: 1080, Height: 1920},    // stories and reels
+	"landscape": {Name: "landscape", Width: 1200, Height: 630}, // link previews
+}
+
+// LookupLayout returns the layout with the given name
+func LookupLayout(name string) (Layout, bool) {
+	layout, ok := layouts[strings.ToLower(strings.TrimSpace(name))]
+	return layout, ok
+}
+
+// LayoutNames lists the available layouts, sorted
+func LayoutNames() []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Card is the content drawn on a card
+type Card struct {
+	Name             string
+	Academy          string
+	Belt             string
+	CountryCode      string
+	Wins             int
+	Losses           int
+	WinsBySubmission int
+	WinsByPoints     int
+	WinsByDecision   int
+	Photo            image.Image // nil draws the initials instead
+}
+
+// FromAthlete builds the card of an athlete, already masked for privacy.
+// The academy is taken from athlete.Academy when preloaded.
+func FromAthlete(athlete *models.Athlete, photo image.Image) *Card {
+	name := strings.TrimSpace(athlete.FullName)
+	if name == "" {
+		name = strings.TrimSpace(athlete.FirstName + " " + athlete.LastName)
+	}
+	academy := athlete.AffiliationName
+	if athlete.Academy != nil && athlete.Academy.Name != "" {
+		academy = athlete.Academy.Name
+	}
+
+	return &Card{
+		Name:             name,
+		Academy:          strings.TrimSpace(academy),
+		Belt:             strings.TrimSpace(athlete.BeltRank),
+		CountryCode:      strings.ToUpper(athlete.CountryCode),
+		Wins:             athlete.TotalWins,
+		Losses:           athlete.TotalLosses,
+		WinsBySubmission: athlete.WinsBySubmission,
+		WinsByPoints:     athlete.WinsByPoints,
+		WinsByDecision:   athlete.WinsByDecision,
+		Photo:            photo,
+	}
+}
+
+// DecodePhoto reads a JPEG, PNG, GIF or WebP photo, refusing oversized ones
+func DecodePhoto(r io.ReadSeeker) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return nil, fmt.Errorf("photo is too large (%dx%d)", cfg.Width, cfg.Height)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	photo, _, err := image.Decode(r)
+	return photo, err
+}
+
+// Render draws the card on the layout and encodes it as PNG or JPEG
+func Render(card *Card, layout Layout, format string) ([]byte, error) {
+	fonts, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	cv := &canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, layout.Width, layout.Height)),
+		fonts: fonts,
+	}
+	cv.background()
+	if layout.Width > layout.Height {
+		cv.horizontal(card)
+	} else {
+		cv.vertical(card)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case PNG:
+		err = png.Encode(&buf, cv.img)
+	case JPEG:
+		err = jpeg.Encode(&buf, cv.img, &jpeg.Options{Quality: 90})
+	default:
+		return nil, fmt.Errorf("unsupported card format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	colorTop    = color.RGBA{0x0f, 0x17, 0x2a, 0xff}
+	colorBottom = color.RGBA{0x1e, 0x29, 0x3b, 0xff}
+	colorText   = color.RGBA{0xf8, 0xfa, 0xfc, 0xff}
+	colorMuted  = color.RGBA{0x94, 0xa3, 0xb8, 0xff}
+	colorEmpty  = color.RGBA{0x33, 0x41, 0x55, 0xff} // photo placeholder
+	colorRank   = color.RGBA{0x0a, 0x0a, 0x0a, 0xff} // rank bar of colored belts
+	colorRed    = color.RGBA{0xdc, 0x26, 0x26, 0xff} // rank bar of black belts
+)
+
+// beltColors are matched by the earliest keyword in the belt rank, so
+// "Grey/White" is grey; Spanish and Portuguese names are included
+var beltColors = []struct {
+	keywords []string
+	color    color.RGBA
+}{
+	{[]string{"white", "blanc", "branca"}, color.RGBA{0xf5, 0xf5, 0xf5, 0xff}},
+	{[]string{"grey", "gray", "gris", "cinza"}, color.RGBA{0x9c, 0xa3, 0xaf, 0xff}},
+	{[]string{"yellow", "amarill", "amarela"}, color.RGBA{0xfa, 0xcc, 0x15, 0xff}},
+	{[]string{"orange", "naranj", "laranja"}, color.RGBA{0xf9, 0x73, 0x16, 0xff}},
+	{[]string{"green", "verde"}, color.RGBA{0x16, 0xa3, 0x4a, 0xff}},
+	{[]string{"blue", "azul"}, color.RGBA{0x25, 0x63, 0xeb, 0xff}},
+	{[]string{"purple", "morad", "purpura", "púrpura", "roxa"}, color.RGBA{0x7c, 0x3a, 0xed, 0xff}},
+	{[]string{"brown", "marron", "marrón", "cafe", "café", "marrom"}, color.RGBA{0x78, 0x35, 0x0f, 0xff}},
+	{[]string{"black", "negr", "preta"}, color.RGBA{0x0a, 0x0a, 0x0a, 0xff}},
+}
+
+// beltColor returns the color of a belt rank; ok is false when unknown
+func beltColor(belt string) (c color.RGBA, black bool, ok bool) {
+	belt = strings.ToLower(belt)
+	best := -1
+	for i, entry := range beltColors {
+		for _, keyword := range entry.keywords {
+			at := strings.Index(belt, keyword)
+			if at >= 0 && (best < 0 || at < best) {
+				best = at
+				c = entry.color
+				black = i == len(beltColors)-1
+				ok = true
+			}
+		}
+	}
+	return c, black, ok
+}
+
+type fontSet struct {
+	regular, bold *opentype.Font
+}
+
+var (
+	fontsOnce sync.Once
+	fonts     *fontSet
+	fontsErr  error
+)
+
+func loadFonts() (*fontSet, error) {
+	fontsOnce.Do(func() {
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		fonts = &fontSet{regular: regular, bold: bold}
+	})
+	return fonts, fontsErr
+}
+
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+)
+
+// canvas draws on the card image; sizes are in pixels
+type canvas struct {
+	img   *image.RGBA
+	fonts *fontSet
+}
+
+// vertical lays the card out top to bottom, for square and story cards.
+// Positions are designed for 1080x1080 and the block is centered vertically
+// on taller canvases.
+func (cv *canvas) vertical(card *Card) {
+	w, h := cv.img.Bounds().Dx(), cv.img.Bounds().Dy()
+	s := float64(w) / 1080
+	top := float64(h-w) / 2
+	y := func(v float64) int { return int(top + v*s) }
+	px := func(v float64) int { return int(v * s) }
+	pad := px(80)
+
+	belt, black, hasBelt := beltColor(card.Belt)
+	d := px(400)
+	ring := belt
+	if !hasBelt {
+		ring = colorMuted
+	}
+	cv.photo(card, (w-d)/2, y(70), d, px(10), ring)
+
+	cv.text(card.Name, pad, w-pad, y(580), float64(px(72)), true, colorText, alignCenter)
+	if card.Academy != "" {
+		cv.text(card.Academy, pad, w-pad, y(640), float64(px(38)), false, colorMuted, alignCenter)
+	}
+	if hasBelt {
+		barW := px(520)
+		cv.belt((w-barW)/2, y(680), barW, px(44), belt, black)
+		cv.text(card.Belt, pad, w-pad, y(770), float64(px(30)), false, colorMuted, alignCenter)
+	}
+
+	cv.text(record(card), pad, w-pad, y(885), float64(px(96)), true, colorText, alignCenter)
+	cv.text(breakdown(card), pad, w-pad, y(945), float64(px(30)), false, colorMuted, alignCenter)
+
+	cv.text(footer(card), pad, w-pad, h-px(40), float64(px(26)), false, colorMuted, alignCenter)
+}
+
+// horizontal puts the photo left of the text, for link previews
+func (cv *canvas) horizontal(card *Card) {
+	w, h := cv.img.Bounds().Dx(), cv.img.Bounds().Dy()
+	s := float64(h) / 630
+	px := func(v float64) int { return int(v * s) }
+
+	belt, black, hasBelt := beltColor(card.Belt)
+	d := px(400)
+	ring := belt
+	if !hasBelt {
+		ring = colorMuted
+	}
+	cv.photo(card, px(70), (h-d)/2, d, px(8), ring)
+
+	x0, x1 := px(70)+d+px(60), w-px(60)
+	cv.text(card.Name, x0, x1, px(170), float64(px(56)), true, colorText, alignLeft)
+	if card.Academy != "" {
+		cv.text(card.Academy, x0, x1, px(220), float64(px(30)), false, colorMuted, alignLeft)
+	}
+	if hasBelt {
+		cv.belt(x0, px(255), min(x1-x0, px(420)), px(36), belt, black)
+		cv.text(card.Belt, x0, x1, px(330), float64(px(24)), false, colorMuted, alignLeft)
+	}
+
+	cv.text(record(card), x0, x1, px(450), float64(px(80)), true, colorText, alignLeft)
+	cv.text(breakdown(card), x0, x1, px(500), float64(px(24)), false, colorMuted, alignLeft)
+	cv.text(footer(card), x0, x1, h-px(35), float64(px(22)), false, colorMuted, alignLeft)
+}
+
+func record(card *Card) string {
+	return strconv.Itoa(card.Wins) + "W - " + strconv.Itoa(card.Losses) + "L"
+}
+
+func breakdown(card *Card) string {
+	return fmt.Sprintf("%d by submission  ·  %d by points  ·  %d by decision",
+		card.WinsBySubmission, card.WinsByPoints, card.WinsByDecision)
+}
+
+func footer(card *Card) string {
+	if card.CountryCode == "" {
+		return "Smoothcomp stats"
+	}
+	return card.CountryCode + "  ·  Smoothcomp stats"
+}
+
+// background fills a vertical gradient
+func (cv *canvas) background() {
+	b := cv.img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		t := float64(y-b.Min.Y) / float64(max(b.Dy()-1, 1))
+		row := image.Rect(b.Min.X, y, b.Max.X, y+1)
+		draw.Draw(cv.img, row, image.NewUniform(blend(colorTop, colorBottom, t)), image.Point{}, draw.Src)
+	}
+}
+
+// photo draws the round photo of the athlete inside a ring, or the
+// initials of the name when there is no photo
+func (cv *canvas) photo(card *Card, x, y, d, ring int, ringColor color.RGBA) {
+	outer := image.Rect(x-ring, y-ring, x+d+ring, y+d+ring)
+	draw.DrawMask(cv.img, outer, image.NewUniform(ringColor), image.Point{},
+		circle{d: outer.Dx()}, image.Point{}, draw.Over)
+
+	inner := image.Rect(x, y, x+d, y+d)
+	if card.Photo == nil {
+		draw.DrawMask(cv.img, inner, image.NewUniform(colorEmpty), image.Point{},
+			circle{d: d}, image.Point{}, draw.Over)
+		initials := []rune(strings.NewReplacer(".", "", " ", "").Replace(privacy.Initials(card.Name)))
+		if len(initials) > 3 {
+			initials = initials[:3]
+		}
+		size := float64(d) * 0.36
+		cv.text(string(initials), x, x+d, y+d/2+int(size*0.36), size, true, colorMuted, alignCenter)
+		return
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, d, d))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), card.Photo, centerSquare(card.Photo.Bounds()), draw.Src, nil)
+	draw.DrawMask(cv.img, inner, scaled, image.Point{}, circle{d: d}, image.Point{}, draw.Over)
+}
+
+// belt draws a belt bar with its rank bar near the end: black on colored
+// belts, red on black belts
+func (cv *canvas) belt(x, y, w, h int, c color.RGBA, black bool) {
+	if black {
+		// outline so the belt stands out on the dark background
+		draw.Draw(cv.img, image.Rect(x-2, y-2, x+w+2, y+h+2), image.NewUniform(colorMuted), image.Point{}, draw.Src)
+	}
+	draw.Draw(cv.img, image.Rect(x, y, x+w, y+h), image.NewUniform(c), image.Point{}, draw.Src)
+
+	rank := colorRank
+	if black {
+		rank = colorRed
+	}
+	barW := w * 22 / 100
+	barX := x + w - barW - w*8/100
+	draw.Draw(cv.img, image.Rect(barX, y, barX+barW, y+h), image.NewUniform(rank), image.Point{}, draw.Src)
+}
+
+// text draws s on the baseline between x0 and x1, shortened with an
+// ellipsis when it does not fit
+func (cv *canvas) text(s string, x0, x1, baseline int, size float64, bold bool, c color.Color, a align) {
+	if s == "" || size < 1 {
+		return
+	}
+	f := cv.fonts.regular
+	if bold {
+		f = cv.fonts.bold
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return
+	}
+	defer face.Close()
+
+	maxW := fixed.I(x1 - x0)
+	s = fit(face, s, maxW)
+	width := font.MeasureString(face, s)
+
+	x := fixed.I(x0)
+	if a == alignCenter {
+		x += (maxW - width) / 2
+	}
+	d := &font.Drawer{Dst: cv.img, Src: image.NewUniform(c), Face: face, Dot: fixed.Point26_6{X: x, Y: fixed.I(baseline)}}
+	d.DrawString(s)
+}
+
+// fit shortens s with an ellipsis until it is at most maxW wide
+func fit(face font.Face, s string, maxW fixed.Int26_6) string {
+	if font.MeasureString(face, s) <= maxW {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		short := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, short) <= maxW {
+			return short
+		}
+	}
+	return ""
+}
+
+// centerSquare is the largest centered square of r, to crop photos
+func centerSquare(r image.Rectangle) image.Rectangle {
+	side := min(r.Dx(), r.Dy())
+	x := r.Min.X + (r.Dx()-side)/2
+	y := r.Min.Y + (r.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// circle is an antialiased round mask of diameter d
+type circle struct {
+	d int
+}
+
+func (c circle) ColorModel() color.Model { return color.AlphaModel }
+
+func (c circle) Bounds() image.Rectangle { return image.Rect(0, 0, c.d, c.d) }
+
+func (c circle) At(x, y int) color.Color {
+	r := float64(c.d) / 2
+	dist := math.Hypot(float64(x)+0.5-r, float64(y)+0.5-r)
+	alpha := math.Max(0, math.Min(1, r-dist+0.5))
+	return color.Alpha{A: uint8(alpha * 255)}
+}
+
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*t) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
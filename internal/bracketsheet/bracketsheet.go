@@ -0,0 +1,347 @@
+// Package bracketsheet renders printable bracket sheets of event divisions
+// from the stored matches and registrations.
+package bracketsheet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/analytics"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/pkg/pdf"
+)
+
+// ErrNotFound is returned when the division has neither matches nor registrations
+var ErrNotFound = errors.New("no bracket data for this division")
+
+// Sheet is the printable content of one division bracket
+type Sheet struct {
+	EventID     string
+	EventName   string
+	DivisionID  string
+	Division    string
+	Strength    *analytics.DivisionStrength // nil without registrations
+	Competitors []Competitor
+	Rounds      []Round
+}
+
+// Competitor is an athlete registered in the division
+type Competitor struct {
+	AthleteID uint
+	Name      string
+	Academy   string
+	Seed      int
+}
+
+// Round groups the bouts of one bracket round, in bracket order
+type Round struct {
+	Name  string
+	Bouts []Bout
+}
+
+// Bout is one match as printed on the sheet
+type Bout struct {
+	A, B   Competitor
+	Winner int // 1 or 2, 0 while undecided
+	Result string
+	Mat    string
+}
+
+// Load builds the sheet of a division from the database
+func Load(eventID, divisionID string) (*Sheet, error) {
+	db := config.GetDB()
+
+	var registrations []models.EventRegistration
+	if err := db.Preload("Athlete").
+		Where("event_id = ? AND division_id = ?", eventID, divisionID).
+		Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+
+	var matches []models.Match
+	if err := db.Where("event_id = ? AND division_id = ?", eventID, divisionID).
+		Order("id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	if len(registrations) == 0 && len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+
+	sheet := &Sheet{EventID: eventID, DivisionID: divisionID}
+	if len(registrations) > 0 {
+		strength := analytics.RateDivision(eventID, divisionID, registrations)
+		sheet.Strength = &strength
+	}
+
+	academyIDs := make([]string, 0, len(registrations))
+	for _, registration := range registrations {
+		if registration.Athlete.AcademyExternalID != "" {
+			academyIDs = append(academyIDs, registration.Athlete.AcademyExternalID)
+		}
+	}
+	academies := map[string]string{}
+	if len(academyIDs) > 0 {
+		var rows []models.Academy
+		db.Select("external_id", "name").Where("external_id IN ?", academyIDs).Find(&rows)
+		for _, academy := range rows {
+			academies[academy.ExternalID] = academy.Name
+		}
+	}
+
+	byAthlete := map[uint]Competitor{}
+	for _, registration := range registrations {
+		athlete := registration.Athlete
+		name := strings.TrimSpace(athlete.FirstName + " " + athlete.LastName)
+		if name == "" {
+			name = athlete.FullName
+		}
+		competitor := Competitor{
+			AthleteID: registration.AthleteID,
+			Name:      name,
+			Academy:   academies[athlete.AcademyExternalID],
+			Seed:      registration.Seed,
+		}
+		byAthlete[registration.AthleteID] = competitor
+		sheet.Competitors = append(sheet.Competitors, competitor)
+
+		if sheet.EventName == "" {
+			sheet.EventName = registration.EventName
+			sheet.Division = joinNonEmpty(" / ", registration.Division, registration.AgeCategory,
+				registration.Rank, registration.WeightClass)
+		}
+	}
+	sort.SliceStable(sheet.Competitors, func(i, j int) bool {
+		a, b := sheet.Competitors[i], sheet.Competitors[j]
+		if (a.Seed == 0) != (b.Seed == 0) {
+			return a.Seed != 0 // seeded athletes first
+		}
+		if a.Seed != b.Seed {
+			return a.Seed < b.Seed
+		}
+		return a.Name < b.Name
+	})
+
+	roundIndex := map[string]int{}
+	for _, match := range matches {
+		if match.Category != "" {
+			sheet.Division = match.Category
+		}
+		if match.EventName != "" {
+			sheet.EventName = match.EventName
+		}
+
+		index, ok := roundIndex[match.Round]
+		if !ok {
+			index = len(sheet.Rounds)
+			roundIndex[match.Round] = index
+			sheet.Rounds = append(sheet.Rounds, Round{Name: match.Round})
+		}
+
+		bout := Bout{
+			A:      side(byAthlete, match.AthleteAID, match.AthleteAName),
+			B:      side(byAthlete, match.AthleteBID, match.AthleteBName),
+			Result: result(match),
+			Mat:    match.Mat,
+		}
+		switch {
+		case match.WinnerID != 0 && match.WinnerID == match.AthleteAID:
+			bout.Winner = 1
+		case match.WinnerID != 0 && match.WinnerID == match.AthleteBID:
+			bout.Winner = 2
+		}
+		sheet.Rounds[index].Bouts = append(sheet.Rounds[index].Bouts, bout)
+	}
+
+	return sheet, nil
+}
+
+// AthleteIDs lists the athletes printed on the sheet
+func (s *Sheet) AthleteIDs() []uint {
+	ids := make([]uint, 0, len(s.Competitors))
+	for _, competitor := range s.Competitors {
+		ids = append(ids, competitor.AthleteID)
+	}
+	for _, round := range s.Rounds {
+		for _, bout := range round.Bouts {
+			ids = append(ids, bout.A.AthleteID, bout.B.AthleteID)
+		}
+	}
+	return ids
+}
+
+// Mask replaces the names of the given athletes with their initials
+func (s *Sheet) Mask(minors map[uint]bool) {
+	maskOne := func(c *Competitor) {
+		if c.AthleteID != 0 && minors[c.AthleteID] {
+			c.Name = privacy.Initials(c.Name)
+		}
+	}
+	for i := range s.Competitors {
+		maskOne(&s.Competitors[i])
+	}
+	for r := range s.Rounds {
+		for b := range s.Rounds[r].Bouts {
+			maskOne(&s.Rounds[r].Bouts[b].A)
+			maskOne(&s.Rounds[r].Bouts[b].B)
+		}
+	}
+}
+
+// side returns the registered competitor of a match side, falling back to
+// the name stored with the match
+func side(byAthlete map[uint]Competitor, athleteID uint, name string) Competitor {
+	if competitor, ok := byAthlete[athleteID]; ok && athleteID != 0 {
+		return competitor
+	}
+	return Competitor{AthleteID: athleteID, Name: name}
+}
+
+// result summarizes how a decided match ended, e.g. "Submission (Armbar)" or "4-2 pts, 1-0 adv"
+func result(match models.Match) string {
+	switch match.Method {
+	case "":
+		return ""
+	case "submission":
+		if match.Technique != "" {
+			return "Submission (" + match.Technique + ")"
+		}
+		return "Submission"
+	case "points":
+		return fmt.Sprintf("%d-%d pts, %d-%d adv", match.PointsA, match.PointsB, match.AdvantagesA, match.AdvantagesB)
+	case "dq":
+		return "DQ"
+	}
+	return "Decision"
+}
+
+// Layout, in points
+const (
+	margin      = 36.0
+	headerSize  = 14.0
+	maxNameSize = 8.0 // largest font used for names
+	minNameSize = 4.5
+)
+
+// Render draws the sheet as a PDF: the bracket tree on the first page,
+// one column per round, followed by the seeded competitor list
+func Render(s *Sheet) ([]byte, error) {
+	doc := pdf.New()
+	doc.AddPage()
+	top := header(doc, s, "Bracket")
+
+	if len(s.Rounds) == 0 {
+		doc.Text(margin, top+12, 10, false, "No matches published yet; see the competitor list.")
+	} else {
+		drawBracket(doc, s.Rounds, top)
+	}
+
+	doc.AddPage()
+	y := header(doc, s, "Competitors")
+	for i, competitor := range s.Competitors {
+		if y > pdf.PageHeight-margin {
+			doc.AddPage()
+			y = header(doc, s, "Competitors")
+		}
+		seed := ""
+		if competitor.Seed > 0 {
+			seed = fmt.Sprint(competitor.Seed)
+		}
+		doc.Text(margin, y, 9, false, fmt.Sprintf("%d.", i+1))
+		doc.Text(margin+24, y, 9, true, seed)
+		doc.Text(margin+50, y, 9, false, pdf.Fit(competitor.Name, 9, 230))
+		doc.Text(margin+290, y, 9, false, pdf.Fit(competitor.Academy, 9, pdf.PageWidth-2*margin-290))
+		y += 14
+	}
+
+	return doc.Bytes()
+}
+
+// header prints the event and division titles and returns the y where the
+// page body starts
+func header(doc *pdf.Document, s *Sheet, section string) float64 {
+	y := margin + headerSize
+	doc.Text(margin, y, headerSize, true, pdf.Fit(s.EventName, headerSize, pdf.PageWidth-2*margin))
+	y += 16
+	doc.Text(margin, y, 10, false, pdf.Fit(s.Division, 10, pdf.PageWidth-2*margin))
+	y += 13
+	if s.Strength != nil {
+		doc.Text(margin, y, 8, false, strengthLine(*s.Strength))
+		y += 11
+	}
+	doc.Text(margin, y, 8, false, fmt.Sprintf("%s - printed %s", section, time.Now().Format("2006-01-02 15:04")))
+	y += 6
+	doc.Line(margin, y, pdf.PageWidth-margin, y, 0.5)
+	return y + 18
+}
+
+// drawBracket lays the rounds out left to right, spreading each round's
+// bouts evenly over the page height so later rounds sit between the bouts
+// that feed them
+func drawBracket(doc *pdf.Document, rounds []Round, top float64) {
+	width := (pdf.PageWidth - 2*margin) / float64(len(rounds))
+	height := pdf.PageHeight - margin - top
+
+	for r, round := range rounds {
+		x := margin + float64(r)*width
+		doc.Text(x, top-6, 8, true, pdf.Fit(round.Name, 8, width-6))
+
+		slot := height / float64(len(round.Bouts))
+		size := min(maxNameSize, max(minNameSize, slot/4))
+		for i, bout := range round.Bouts {
+			center := top + slot*(float64(i)+0.5)
+			gap := min(slot/4, 14)
+			yA, yB := center-gap, center+gap
+
+			doc.Line(x, yA, x+width-6, yA, 0.5)
+			doc.Line(x, yB, x+width-6, yB, 0.5)
+			doc.Line(x+width-6, yA, x+width-6, yB, 0.5)
+
+			doc.Text(x+2, yA-2, size, bout.Winner == 1, pdf.Fit(sideLabel(bout.A), size, width-10))
+			doc.Text(x+2, yB-2, size, bout.Winner == 2, pdf.Fit(sideLabel(bout.B), size, width-10))
+
+			detail := joinNonEmpty(" - ", bout.Result, bout.Mat)
+			if detail != "" && gap > size {
+				doc.Text(x+2, center+size/3, size-1, false, pdf.Fit(detail, size-1, width-10))
+			}
+		}
+	}
+}
+
+func sideLabel(c Competitor) string {
+	label := c.Name
+	if c.Seed > 0 {
+		label = fmt.Sprintf("(%d) %s", c.Seed, label)
+	}
+	if c.Academy != "" {
+		label += " - " + c.Academy
+	}
+	return label
+}
+
+// strengthLine summarizes the division strength for the sheet header
+func strengthLine(strength analytics.DivisionStrength) string {
+	parts := []string{
+		fmt.Sprintf("Strength index %.0f", strength.Index),
+		fmt.Sprintf("%d/%d ranked", strength.RankedAthletes, strength.Athletes),
+	}
+	if strength.AvgRating != nil {
+		parts = append(parts, fmt.Sprintf("avg rating %.0f", *strength.AvgRating))
+	}
+	return strings.Join(parts, " - ")
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
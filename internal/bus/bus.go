@@ -0,0 +1,138 @@
+// Package bus is an in-process publish/subscribe bus for job updates. The
+// scraper, pipeline and scheduler publish to it; the API streams it to
+// dashboards.
+package bus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// Event types
+const (
+	JobStarted       = "job_started"
+	JobProgress      = "job_progress"
+	JobFinished      = "job_finished" // completed, failed, partial or cancelled; see Status
+	ScheduleStarted  = "schedule_started"
+	ScheduleSkipped  = "schedule_skipped" // the previous run of the schedule is still in progress
+	ScheduleFinished = "schedule_finished"
+)
+
+// Event is one update published on the bus
+type Event struct {
+	Type           string    `json:"type"`
+	Time           time.Time `json:"time"`
+	JobID          int       `json:"job_id,omitempty"`
+	ParentJobID    *int      `json:"parent_job_id,omitempty"`
+	JobType        string    `json:"job_type,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	CurrentPhase   string    `json:"current_phase,omitempty"`
+	ItemsTotal     int       `json:"items_total,omitempty"`
+	ItemsProcessed int       `json:"items_processed,omitempty"`
+	ItemsScraped   int       `json:"items_scraped,omitempty"`
+	ScheduleID     int       `json:"schedule_id,omitempty"`
+	Schedule       string    `json:"schedule,omitempty"` // schedule name
+	Error          string    `json:"error,omitempty"`
+}
+
+// JobEvent describes the current state of job
+func JobEvent(eventType string, job *models.ScrapeJob) Event {
+	return Event{
+		Type:           eventType,
+		Time:           time.Now(),
+		JobID:          job.ID,
+		ParentJobID:    job.ParentJobID,
+		JobType:        job.JobType,
+		Status:         job.Status,
+		CurrentPhase:   job.CurrentPhase,
+		ItemsTotal:     job.ItemsTotal,
+		ItemsProcessed: job.ItemsProcessed,
+		ItemsScraped:   job.ItemsScraped,
+		Error:          job.ErrorMessage,
+	}
+}
+
+// Bus fans published events out to its subscribers
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// New returns an empty bus
+func New() *Bus {
+	return &Bus{subscribers: map[chan Event]struct{}{}}
+}
+
+var defaultBus = New()
+
+// Default returns the process-wide bus
+func Default() *Bus {
+	return defaultBus
+}
+
+// Subscribe returns a channel receiving every event published from now on,
+// buffering up to buffer events, and a func that unsubscribes and closes it.
+// The channel is also closed by Close.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish sends event to every subscriber. It never blocks: a subscriber
+// whose buffer is full misses the event.
+func (b *Bus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close closes every subscriber channel so streams end, e.g. on server
+// shutdown. Later subscribers get a closed channel.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish sends event on the default bus
+func Publish(event Event) {
+	defaultBus.Publish(event)
+}
+
+// PublishJob sends the current state of job on the default bus
+func PublishJob(eventType string, job *models.ScrapeJob) {
+	defaultBus.Publish(JobEvent(eventType, job))
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,36 +17,220 @@ type Config struct {
 	Scheduler SchedulerConfig
 	Database  DatabaseConfig
 	Logging   LoggingConfig
+	Fixtures  FixturesConfig
+	Media     MediaConfig
+	YouTube   YouTubeConfig
+
+	Notifications NotificationsConfig
+	Privacy       PrivacyConfig
+	Live          LiveConfig
+	Rankings      RankingsConfig
+	Queue         QueueConfig
+	Maintenance   MaintenanceConfig
+	Results       ResultsConfig
 }
 
 type ServerConfig struct {
 	Port        string
+	GRPCPort    string // gRPC service alongside HTTP; empty disables it
 	Environment string
+	AdminAPIKey string // enables /api/v1/admin when set
+
+	StartupReachabilityCheck bool // Validate probes SMOOTHCOMP_BASE_URL
+
+	// Latency tracking
+	LatencyBudget      time.Duration
+	SlowQueryThreshold time.Duration
 }
 
 type ScraperConfig struct {
 	BaseURL           string
 	UserAgent         string
 	RequestDelayMs    int
+	Concurrency       int // profiles enriched in parallel, paced together by RequestDelayMs
 	MaxRetries        int
 	RateLimitRequests int
 	RateLimitDuration time.Duration
 	TargetCountries   []string
+	TypedInfoPanels   bool
+	TestBaseURL       string
+	AllowedPaths      []string // path patterns allowed besides the built-in allowlist
+	AllowedDomains    []string // hosts the scraper may visit; "*.smoothcomp.com" matches any subdomain
+
+	// How long a detected event subdomain is reused before probing again
+	SubdomainCacheTTL time.Duration
+
+	// Club page contact details kept for outreach: email, phone and/or
+	// address; empty stores none
+	AcademyContactFields []string
+
+	// Profile enrichment guardrails
+	EnrichMaxTotal  int // most profiles a single enrich request may select
+	EnrichChunkSize int // profiles per child job
+
+	// ScrapeAll pipeline stages
+	PipelineStageRetries int
+	PipelineRetryBackoff time.Duration
+
+	// Colly page retries on 429, 5xx and network errors
+	PageRetries      int
+	PageRetryBackoff time.Duration // grows linearly per attempt
+
+	// Raw bracket archive retention
+	BracketArchiveRetention time.Duration // 0 keeps archives forever
+	BracketArchiveVersions  int           // versions kept per division, 0 keeps all
+
+	// Request recording of debug jobs
+	RecordDir      string
+	RecordMaxBytes int64 // per job; 0 is unlimited
+
+	// Behavior profiles (?profile=)
+	ProxyURL   string   // used by profiles that route through a proxy
+	UserAgents []string // rotated by profiles that rotate headers
+
+	// Proxy rotation for every request (SCRAPER_PROXIES)
+	Proxies          []string      // http(s):// or socks5:// URLs
+	ProxyRotation    string        // round_robin or random
+	ProxyMaxFailures int           // failures in a row before a proxy is blacklisted
+	ProxyCooldown    time.Duration // how long a blacklisted proxy is skipped
+
+	// Colly visited URLs and cookies (SCRAPER_STORAGE)
+	Storage    string        // database or memory
+	VisitedTTL time.Duration // how long a visited page is skipped
+
+	// Response cache shared by every job, so overlapping jobs do not fetch
+	// the same page twice (FETCH_CACHE_*)
+	FetchCacheTTL      time.Duration // 0 disables the cache
+	FetchCacheMaxBytes int64         // bodies kept at once; the oldest go first
+
+	// Shared HTTP transport tuning
+	HTTPMaxIdleConns        int
+	HTTPMaxIdleConnsPerHost int
+	HTTPIdleConnTimeout     time.Duration
 }
 
 type SchedulerConfig struct {
 	CronExpression string
 	Enabled        bool
+
+	// Stale profile re-enrichment policy ("enrich_stale" schedules)
+	StaleProfileAge   time.Duration
+	StaleProfileBatch int
 }
 
 type DatabaseConfig struct {
-	CachePath string
+	Driver    string // "sqlite" (default) or "postgres"
+	CachePath string // SQLite file
+	DSN       string // PostgreSQL connection string
+
+	// Batched writes from scraper workers
+	WriteBatchSize     int
+	WriteFlushInterval time.Duration
+	WriteQueueSize     int
 }
 
 type LoggingConfig struct {
 	Level string
 }
 
+// MediaConfig controls the image proxy cache
+type MediaConfig struct {
+	CacheDir        string
+	MaxBytes        int64
+	AllowedHosts    []string
+	FlagURLTemplate string
+	MaxDimension    int   // largest width or height of a resized variant
+	VariantSizes    []int // sizes requested dimensions snap up to
+}
+
+// YouTubeConfig controls match video linking through the YouTube Data API
+type YouTubeConfig struct {
+	APIKey        string
+	Channels      []string // channel IDs searched for match videos
+	MaxResults    int
+	MinConfidence float64
+}
+
+// NotificationsConfig lists the channels that receive notifications
+type NotificationsConfig struct {
+	WebhookURLs []string
+	Timeout     time.Duration
+
+	// Event digest
+	RegistrationJump int // minimum registrations gained between runs to report
+
+	// Athletes tagged with WatchTag get milestone notifications
+	WatchTag string
+}
+
+// PrivacyConfig controls how data about minors is published
+type PrivacyConfig struct {
+	KidsInitialsOnly bool // kids leaderboards show initials instead of names, slugs and pictures
+	MinorAge         int  // athletes younger than this are anonymized in responses; 0 disables
+}
+
+// LiveConfig controls the optional ingestion of live scoreboard streams
+type LiveConfig struct {
+	StreamURL      string        // WebSocket URL template with {host} and {event}
+	EventIDs       []string      // events streamed from startup
+	ReconnectDelay time.Duration // first wait after a dropped stream, doubled up to 5 minutes
+
+	// Expected time between match starts on a mat, used to estimate when a
+	// watched athlete fights next until the stream shows the mat's pace
+	MatchSlot time.Duration
+
+	// How often recorded scores are written, together in one transaction
+	FlushInterval time.Duration
+}
+
+// RankingsConfig controls how rankings score podium results. Medal points
+// are multiplied by the event weight: registrations divided by
+// ReferenceEventSize, kept between MinEventWeight and MaxEventWeight.
+type RankingsConfig struct {
+	GoldPoints   float64
+	SilverPoints float64
+	BronzePoints float64
+
+	ReferenceEventSize int // 0 gives every event a weight of 1
+	MinEventWeight     float64
+	MaxEventWeight     float64
+}
+
+// QueueConfig controls the persistent queue of scrapes requested through
+// the API. Failed jobs are retried up to MaxAttempts runs, waiting
+// RetryBackoff before the second run and doubling it after each failure.
+type QueueConfig struct {
+	Workers      int // jobs run at the same time; 1 runs them one after another
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	WorkerID     string // identifies this process on the jobs it runs; the host name by default
+}
+
+// MaintenanceConfig controls how jobs stopped by a Smoothcomp maintenance
+// page are postponed: scheduled and queued jobs run again after RetryDelay,
+// at most MaxPostpones times in a row before they fail
+type MaintenanceConfig struct {
+	RetryDelay   time.Duration
+	MaxPostpones int
+}
+
+// ResultsConfig controls the rechecks of past events whose results scrape
+// found nothing yet: the first waits RecheckDelay and each following one
+// twice as long, until results appear or Window has passed since the event
+// started. A zero Window disables rechecks.
+type ResultsConfig struct {
+	RecheckDelay time.Duration
+	Window       time.Duration
+}
+
+// FixturesConfig controls the built-in fixture server used to run scrapes
+// against stored HTML/JSON instead of smoothcomp.com
+type FixturesConfig struct {
+	Enabled bool
+	Port    string
+	Dir     string
+}
+
 // LoadConfig loads configuration from environment variables and .env file
 func LoadConfig() (*Config, error) {
 	viper.SetConfigFile(".env")
@@ -51,46 +238,234 @@ func LoadConfig() (*Config, error) {
 
 	// Attempt to read .env file (optional)
 	if err := viper.ReadInConfig(); err != nil {
-		fmt.Printf("Warning: .env file not found, using environment variables: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: .env file not found, using environment variables: %v\n", err)
 	}
 
 	viper.SetDefault("PORT", "8080")
+	viper.SetDefault("GRPC_PORT", "")
 	viper.SetDefault("ENVIRONMENT", "development")
+	viper.SetDefault("STARTUP_REACHABILITY_CHECK", true)
+	viper.SetDefault("API_LATENCY_BUDGET_MS", 500)
+	viper.SetDefault("SLOW_QUERY_MS", 100)
 	viper.SetDefault("SMOOTHCOMP_BASE_URL", "https://smoothcomp.com")
 	viper.SetDefault("USER_AGENT", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36")
 	viper.SetDefault("REQUEST_DELAY_MS", 2000)
+	viper.SetDefault("SCRAPER_CONCURRENCY", 1)
 	viper.SetDefault("MAX_RETRIES", 3)
 	viper.SetDefault("RATE_LIMIT_REQUESTS", 10)
 	viper.SetDefault("RATE_LIMIT_DURATION", 60)
 	viper.SetDefault("SCHEDULE_CRON", "0 2 * * 0") // Every Sunday at 2 AM
 	viper.SetDefault("TARGET_COUNTRIES", "AR,BR,CL,MX,EC,VE,PE,CO")
+	viper.SetDefault("ENRICH_MAX_TOTAL", 5000)
+	viper.SetDefault("ENRICH_CHUNK_SIZE", 200)
+	viper.SetDefault("PIPELINE_STAGE_RETRIES", 2)
+	viper.SetDefault("PIPELINE_RETRY_BACKOFF_SECONDS", 30)
+	viper.SetDefault("SCRAPER_ALLOWED_DOMAINS", "smoothcomp.com,*.smoothcomp.com")
+	viper.SetDefault("SUBDOMAIN_CACHE_DAYS", 30)
+	viper.SetDefault("ACADEMY_CONTACT_FIELDS", "email,phone,address")
+	viper.SetDefault("SCRAPER_PAGE_RETRIES", 2)
+	viper.SetDefault("SCRAPER_PAGE_RETRY_BACKOFF_SECONDS", 5)
+	viper.SetDefault("BRACKET_ARCHIVE_RETENTION_DAYS", 0)
+	viper.SetDefault("BRACKET_ARCHIVE_VERSIONS", 5)
+	viper.SetDefault("DEBUG_RECORD_DIR", "./storage/recordings")
+	viper.SetDefault("DEBUG_RECORD_MAX_MB", 20)
+	viper.SetDefault("ENRICH_STALE_DAYS", 30)
+	viper.SetDefault("ENRICH_STALE_BATCH", 500)
+	viper.SetDefault("DB_DRIVER", DriverSQLite)
 	viper.SetDefault("CACHE_DB_PATH", "./storage/cache.db")
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("DB_WRITE_BATCH_SIZE", 100)
+	viper.SetDefault("DB_WRITE_FLUSH_INTERVAL_MS", 500)
+	viper.SetDefault("DB_WRITE_QUEUE_SIZE", 1000)
+	viper.SetDefault("STORE_TYPED_INFO_PANELS", true)
+	viper.SetDefault("SCRAPER_USER_AGENTS", strings.Join([]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+	}, "|"))
+	viper.SetDefault("SCRAPER_PROXY_ROTATION", "round_robin")
+	viper.SetDefault("SCRAPER_PROXY_MAX_FAILURES", 3)
+	viper.SetDefault("SCRAPER_PROXY_COOLDOWN_SECONDS", 600)
+	viper.SetDefault("SCRAPER_STORAGE", "database")
+	viper.SetDefault("SCRAPER_VISITED_TTL_HOURS", 24)
+	viper.SetDefault("LIVE_RECONNECT_SECONDS", 10)
+	viper.SetDefault("LIVE_MATCH_SLOT_MINUTES", 10)
+	viper.SetDefault("LIVE_FLUSH_MS", 1000)
+	viper.SetDefault("FETCH_CACHE_TTL_SECONDS", 60)
+	viper.SetDefault("FETCH_CACHE_MAX_MB", 64)
+	viper.SetDefault("HTTP_MAX_IDLE_CONNS", 100)
+	viper.SetDefault("HTTP_MAX_IDLE_CONNS_PER_HOST", 10)
+	viper.SetDefault("HTTP_IDLE_CONN_TIMEOUT", 90)
+	viper.SetDefault("MEDIA_CACHE_DIR", "./storage/media")
+	viper.SetDefault("MEDIA_MAX_BYTES", 5*1024*1024)
+	viper.SetDefault("MEDIA_MAX_DIMENSION", 1024)
+	viper.SetDefault("MEDIA_VARIANT_SIZES", "64,128,256,512,1024")
+	viper.SetDefault("MEDIA_ALLOWED_HOSTS", "smoothcomp.com,*.smoothcomp.com,smoothcomp.s3.amazonaws.com,flagcdn.com")
+	viper.SetDefault("FLAG_URL_TEMPLATE", "https://flagcdn.com/w80/%s.png")
+	viper.SetDefault("YOUTUBE_MAX_RESULTS", 10)
+	viper.SetDefault("YOUTUBE_MIN_CONFIDENCE", 0.5)
+	viper.SetDefault("NOTIFY_TIMEOUT_SECONDS", 10)
+	viper.SetDefault("NOTIFY_WATCH_TAG", "watched")
+	viper.SetDefault("DIGEST_REGISTRATION_JUMP", 10)
+	viper.SetDefault("KIDS_INITIALS_ONLY", true)
+	viper.SetDefault("ANONYMIZE_MINORS_UNDER", 0)
+	viper.SetDefault("RANKING_POINTS_GOLD", 9)
+	viper.SetDefault("RANKING_POINTS_SILVER", 3)
+	viper.SetDefault("RANKING_POINTS_BRONZE", 1)
+	viper.SetDefault("RANKING_REFERENCE_EVENT_SIZE", 200)
+	viper.SetDefault("RANKING_MIN_EVENT_WEIGHT", 0.5)
+	viper.SetDefault("RANKING_MAX_EVENT_WEIGHT", 2)
+	viper.SetDefault("QUEUE_WORKERS", 1)
+	viper.SetDefault("QUEUE_MAX_ATTEMPTS", 3)
+	viper.SetDefault("QUEUE_RETRY_BACKOFF_SECONDS", 60)
+	viper.SetDefault("MAINTENANCE_RETRY_MINUTES", 30)
+	viper.SetDefault("MAINTENANCE_MAX_POSTPONES", 6)
+	viper.SetDefault("RESULTS_RECHECK_HOURS", 6)
+	viper.SetDefault("RESULTS_RECHECK_WINDOW_DAYS", 14)
+	viper.SetDefault("FIXTURE_SERVER_ENABLED", false)
+	viper.SetDefault("FIXTURE_PORT", "8089")
+	viper.SetDefault("FIXTURE_DIR", "./fixtures")
 
 	config := &Config{
 		Server: ServerConfig{
 			Port:        viper.GetString("PORT"),
+			GRPCPort:    viper.GetString("GRPC_PORT"),
 			Environment: viper.GetString("ENVIRONMENT"),
+			AdminAPIKey: viper.GetString("ADMIN_API_KEY"),
+
+			StartupReachabilityCheck: viper.GetBool("STARTUP_REACHABILITY_CHECK"),
+
+			LatencyBudget:      time.Duration(viper.GetInt("API_LATENCY_BUDGET_MS")) * time.Millisecond,
+			SlowQueryThreshold: time.Duration(viper.GetInt("SLOW_QUERY_MS")) * time.Millisecond,
 		},
 		Scraper: ScraperConfig{
 			BaseURL:           viper.GetString("SMOOTHCOMP_BASE_URL"),
 			UserAgent:         viper.GetString("USER_AGENT"),
 			RequestDelayMs:    viper.GetInt("REQUEST_DELAY_MS"),
+			Concurrency:       viper.GetInt("SCRAPER_CONCURRENCY"),
 			MaxRetries:        viper.GetInt("MAX_RETRIES"),
 			RateLimitRequests: viper.GetInt("RATE_LIMIT_REQUESTS"),
 			RateLimitDuration: time.Duration(viper.GetInt("RATE_LIMIT_DURATION")) * time.Second,
 			TargetCountries:   parseCountries(viper.GetString("TARGET_COUNTRIES")),
+			TypedInfoPanels:   viper.GetBool("STORE_TYPED_INFO_PANELS"),
+			TestBaseURL:       viper.GetString("TEST_BASE_URL"),
+			AllowedPaths:      parseList(viper.GetString("SCRAPER_ALLOWED_PATHS"), ","),
+			AllowedDomains:    parseList(strings.ToLower(viper.GetString("SCRAPER_ALLOWED_DOMAINS")), ","),
+			SubdomainCacheTTL: time.Duration(viper.GetInt("SUBDOMAIN_CACHE_DAYS")) * 24 * time.Hour,
+
+			AcademyContactFields: parseOptionalList(strings.ToLower(viper.GetString("ACADEMY_CONTACT_FIELDS"))),
+
+			EnrichMaxTotal:  viper.GetInt("ENRICH_MAX_TOTAL"),
+			EnrichChunkSize: viper.GetInt("ENRICH_CHUNK_SIZE"),
+
+			PipelineStageRetries: viper.GetInt("PIPELINE_STAGE_RETRIES"),
+			PipelineRetryBackoff: time.Duration(viper.GetInt("PIPELINE_RETRY_BACKOFF_SECONDS")) * time.Second,
+
+			PageRetries:      viper.GetInt("SCRAPER_PAGE_RETRIES"),
+			PageRetryBackoff: time.Duration(viper.GetInt("SCRAPER_PAGE_RETRY_BACKOFF_SECONDS")) * time.Second,
+
+			BracketArchiveRetention: time.Duration(viper.GetInt("BRACKET_ARCHIVE_RETENTION_DAYS")) * 24 * time.Hour,
+			BracketArchiveVersions:  viper.GetInt("BRACKET_ARCHIVE_VERSIONS"),
+
+			RecordDir:      viper.GetString("DEBUG_RECORD_DIR"),
+			RecordMaxBytes: viper.GetInt64("DEBUG_RECORD_MAX_MB") * 1024 * 1024,
+
+			ProxyURL:   viper.GetString("SCRAPER_PROXY_URL"),
+			UserAgents: parseList(viper.GetString("SCRAPER_USER_AGENTS"), "|"),
+
+			Proxies:          parseList(viper.GetString("SCRAPER_PROXIES"), ","),
+			ProxyRotation:    viper.GetString("SCRAPER_PROXY_ROTATION"),
+			ProxyMaxFailures: viper.GetInt("SCRAPER_PROXY_MAX_FAILURES"),
+			ProxyCooldown:    time.Duration(viper.GetInt("SCRAPER_PROXY_COOLDOWN_SECONDS")) * time.Second,
+
+			Storage:    viper.GetString("SCRAPER_STORAGE"),
+			VisitedTTL: time.Duration(viper.GetInt("SCRAPER_VISITED_TTL_HOURS")) * time.Hour,
+
+			FetchCacheTTL:      time.Duration(viper.GetInt("FETCH_CACHE_TTL_SECONDS")) * time.Second,
+			FetchCacheMaxBytes: viper.GetInt64("FETCH_CACHE_MAX_MB") * 1024 * 1024,
+
+			HTTPMaxIdleConns:        viper.GetInt("HTTP_MAX_IDLE_CONNS"),
+			HTTPMaxIdleConnsPerHost: viper.GetInt("HTTP_MAX_IDLE_CONNS_PER_HOST"),
+			HTTPIdleConnTimeout:     time.Duration(viper.GetInt("HTTP_IDLE_CONN_TIMEOUT")) * time.Second,
 		},
 		Scheduler: SchedulerConfig{
 			CronExpression: viper.GetString("SCHEDULE_CRON"),
 			Enabled:        true,
+
+			StaleProfileAge:   time.Duration(viper.GetInt("ENRICH_STALE_DAYS")) * 24 * time.Hour,
+			StaleProfileBatch: viper.GetInt("ENRICH_STALE_BATCH"),
 		},
 		Database: DatabaseConfig{
+			Driver:    strings.ToLower(viper.GetString("DB_DRIVER")),
 			CachePath: viper.GetString("CACHE_DB_PATH"),
+			DSN:       viper.GetString("DATABASE_URL"),
+
+			WriteBatchSize:     viper.GetInt("DB_WRITE_BATCH_SIZE"),
+			WriteFlushInterval: time.Duration(viper.GetInt("DB_WRITE_FLUSH_INTERVAL_MS")) * time.Millisecond,
+			WriteQueueSize:     viper.GetInt("DB_WRITE_QUEUE_SIZE"),
 		},
 		Logging: LoggingConfig{
 			Level: viper.GetString("LOG_LEVEL"),
 		},
+		Media: MediaConfig{
+			CacheDir:        viper.GetString("MEDIA_CACHE_DIR"),
+			MaxBytes:        viper.GetInt64("MEDIA_MAX_BYTES"),
+			AllowedHosts:    parseList(viper.GetString("MEDIA_ALLOWED_HOSTS"), ","),
+			FlagURLTemplate: viper.GetString("FLAG_URL_TEMPLATE"),
+			MaxDimension:    viper.GetInt("MEDIA_MAX_DIMENSION"),
+			VariantSizes:    parseSizes(viper.GetString("MEDIA_VARIANT_SIZES")),
+		},
+		YouTube: YouTubeConfig{
+			APIKey:        viper.GetString("YOUTUBE_API_KEY"),
+			Channels:      parseList(viper.GetString("YOUTUBE_CHANNELS"), ","),
+			MaxResults:    viper.GetInt("YOUTUBE_MAX_RESULTS"),
+			MinConfidence: viper.GetFloat64("YOUTUBE_MIN_CONFIDENCE"),
+		},
+		Notifications: NotificationsConfig{
+			WebhookURLs:      parseList(viper.GetString("NOTIFY_WEBHOOK_URLS"), ","),
+			Timeout:          time.Duration(viper.GetInt("NOTIFY_TIMEOUT_SECONDS")) * time.Second,
+			RegistrationJump: viper.GetInt("DIGEST_REGISTRATION_JUMP"),
+			WatchTag:         viper.GetString("NOTIFY_WATCH_TAG"),
+		},
+		Privacy: PrivacyConfig{
+			KidsInitialsOnly: viper.GetBool("KIDS_INITIALS_ONLY"),
+			MinorAge:         viper.GetInt("ANONYMIZE_MINORS_UNDER"),
+		},
+		Live: LiveConfig{
+			StreamURL:      viper.GetString("LIVE_STREAM_URL"),
+			EventIDs:       parseList(viper.GetString("LIVE_EVENT_IDS"), ","),
+			ReconnectDelay: time.Duration(viper.GetInt("LIVE_RECONNECT_SECONDS")) * time.Second,
+			MatchSlot:      time.Duration(viper.GetInt("LIVE_MATCH_SLOT_MINUTES")) * time.Minute,
+			FlushInterval:  time.Duration(viper.GetInt("LIVE_FLUSH_MS")) * time.Millisecond,
+		},
+		Rankings: RankingsConfig{
+			GoldPoints:   viper.GetFloat64("RANKING_POINTS_GOLD"),
+			SilverPoints: viper.GetFloat64("RANKING_POINTS_SILVER"),
+			BronzePoints: viper.GetFloat64("RANKING_POINTS_BRONZE"),
+
+			ReferenceEventSize: viper.GetInt("RANKING_REFERENCE_EVENT_SIZE"),
+			MinEventWeight:     viper.GetFloat64("RANKING_MIN_EVENT_WEIGHT"),
+			MaxEventWeight:     viper.GetFloat64("RANKING_MAX_EVENT_WEIGHT"),
+		},
+		Queue: QueueConfig{
+			Workers:      viper.GetInt("QUEUE_WORKERS"),
+			MaxAttempts:  viper.GetInt("QUEUE_MAX_ATTEMPTS"),
+			RetryBackoff: time.Duration(viper.GetInt("QUEUE_RETRY_BACKOFF_SECONDS")) * time.Second,
+			WorkerID:     viper.GetString("QUEUE_WORKER_ID"),
+		},
+		Maintenance: MaintenanceConfig{
+			RetryDelay:   time.Duration(viper.GetInt("MAINTENANCE_RETRY_MINUTES")) * time.Minute,
+			MaxPostpones: viper.GetInt("MAINTENANCE_MAX_POSTPONES"),
+		},
+		Results: ResultsConfig{
+			RecheckDelay: time.Duration(viper.GetInt("RESULTS_RECHECK_HOURS")) * time.Hour,
+			Window:       time.Duration(viper.GetInt("RESULTS_RECHECK_WINDOW_DAYS")) * 24 * time.Hour,
+		},
+		Fixtures: FixturesConfig{
+			Enabled: viper.GetBool("FIXTURE_SERVER_ENABLED"),
+			Port:    viper.GetString("FIXTURE_PORT"),
+			Dir:     viper.GetString("FIXTURE_DIR"),
+		},
 	}
 
 	return config, nil
@@ -98,15 +473,20 @@ func LoadConfig() (*Config, error) {
 
 // parseCountries splits comma-separated country codes
 func parseCountries(countriesStr string) []string {
-	if countriesStr == "" {
+	return parseList(countriesStr, ",")
+}
+
+// parseList splits s on sep, dropping blank items
+func parseList(s string, sep string) []string {
+	if s == "" {
 		return []string{}
 	}
 
-	countries := strings.Split(countriesStr, ",")
-	result := make([]string, 0, len(countries))
+	items := strings.Split(s, sep)
+	result := make([]string, 0, len(items))
 
-	for _, country := range countries {
-		trimmed := strings.TrimSpace(country)
+	for _, item := range items {
+		trimmed := strings.TrimSpace(item)
 		if trimmed != "" {
 			result = append(result, trimmed)
 		}
@@ -115,6 +495,31 @@ func parseCountries(countriesStr string) []string {
 	return result
 }
 
+// parseSizes reads a comma-separated list of pixel sizes in ascending order.
+// Entries that are not numbers read as 0 so Validate reports them.
+func parseSizes(s string) []int {
+	items := parseList(s, ",")
+	sizes := make([]int, 0, len(items))
+	for _, item := range items {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			n = 0
+		}
+		sizes = append(sizes, n)
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// parseOptionalList is parseList on commas where "none" is the empty list,
+// since an empty variable falls back to its default
+func parseOptionalList(s string) []string {
+	if strings.TrimSpace(s) == "none" {
+		return []string{}
+	}
+	return parseList(s, ",")
+}
+
 // GetCountryName returns the full country name from country code
 func GetCountryName(code string) string {
 	countryMap := map[string]string{
@@ -2,35 +2,123 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// Supported database drivers (DB_DRIVER)
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// migrationLockID is the PostgreSQL advisory lock that serializes migrations
+// of replicas starting at the same time
+const migrationLockID = 72150341
+
 var DB *gorm.DB
 
-func InitDatabase(dbPath string) error {
+func InitDatabase(dbConfig DatabaseConfig) error {
 	var err error
 
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
 
-	DB, err = gorm.Open(sqlite.Open(dbPath), gormConfig)
+	var dialector gorm.Dialector
+	switch dbConfig.Driver {
+	case DriverPostgres:
+		dialector = postgres.Open(dbConfig.DSN)
+	case DriverSQLite, "":
+		dialector = sqlite.Open(dbConfig.CachePath)
+	default:
+		return fmt.Errorf("unsupported database driver %q", dbConfig.Driver)
+	}
+
+	DB, err = gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	err = DB.AutoMigrate(
+	if IsPostgres() {
+		err = DB.Connection(func(conn *gorm.DB) error {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+			return migrate(conn)
+		})
+	} else {
+		// WAL lets the read API keep querying while live scores are written
+		if err := DB.Exec("PRAGMA journal_mode=WAL").Error; err != nil {
+			return fmt.Errorf("failed to enable WAL: %w", err)
+		}
+		err = migrate(DB)
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// migrate creates or updates the schema and seeds the default schedule
+func migrate(db *gorm.DB) error {
+	if err := prepareSlugIndexes(db); err != nil {
+		return fmt.Errorf("failed to prepare slug indexes: %w", err)
+	}
+
+	err := db.AutoMigrate(
 		&models.Academy{},
 		&models.Athlete{},
 		&models.Event{},
 		&models.EventDetail{},
+		&models.EventVenue{},
+		&models.EventContact{},
+		&models.EventRules{},
+		&models.EventScheduleItem{},
+		&models.EventRegistrationWindow{},
 		&models.EventRegistration{},
 		&models.ScrapeJob{},
 		&models.ScheduleConfig{},
+		&models.ScheduleAudit{},
+		&models.MediaAsset{},
+		&models.Match{},
+		&models.MatchVideo{},
+		&models.RouteLatency{},
+		&models.SlowQuery{},
+		&models.EventDigest{},
+		&models.EventDigestItem{},
+		&models.EventRegistrationCount{},
+		&models.BracketArchive{},
+		&models.BlockedEntity{},
+		&models.SuppressedAthlete{},
+		&models.AcademyContactOptOut{},
+		&models.FieldCoverage{},
+		&models.NotificationSubscriber{},
+		&models.EventResult{},
+		&models.CrawlVisit{},
+		&models.CrawlCookie{},
+		&models.LiveScore{},
+		&models.EntityTag{},
+		&models.SavedQuery{},
+		&models.FederationID{},
+		&models.AthleteAlias{},
+		&models.EventURLAlias{},
+		&models.AthleteSnapshot{},
+		&models.RankingEntry{},
+		&models.QueuedJob{},
+		&models.TeamRankingEntry{},
+		&models.AthleteStreak{},
+		&models.AthleteMilestone{},
+		&models.CountryRosterEntry{},
+		&models.EventSubdomain{},
+		&models.DataMigration{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
@@ -38,24 +126,101 @@ func InitDatabase(dbPath string) error {
 
 	// Initialize default schedule config if not exists
 	var scheduleConfig models.ScheduleConfig
-	result := DB.First(&scheduleConfig)
+	result := db.First(&scheduleConfig)
 	if result.Error == gorm.ErrRecordNotFound {
 		defaultSchedule := models.ScheduleConfig{
+			Name:     "Full scrape monthly",
 			CronExpr: "0 2 1 * *", // 1st day of month at 2 AM (Monthly)
+			JobType:  "all",
 			Enabled:  true,
 		}
-		if err := DB.Create(&defaultSchedule).Error; err != nil {
+		if err := db.Create(&defaultSchedule).Error; err != nil {
 			return fmt.Errorf("failed to create default schedule: %w", err)
 		}
 	}
 
+	// Athletes stored before first_seen_at was tracked were first seen when created
+	if err := db.Model(&models.Athlete{}).Where("first_seen_at IS NULL").
+		UpdateColumn("first_seen_at", gorm.Expr("created_at")).Error; err != nil {
+		return fmt.Errorf("failed to backfill athlete first_seen_at: %w", err)
+	}
+
+	// Schedules created before they had names get one from their job type
+	var unnamed []models.ScheduleConfig
+	db.Where("name IS NULL OR name = ''").Find(&unnamed)
+	for _, schedule := range unnamed {
+		name := fmt.Sprintf("%s #%d", schedule.JobType, schedule.ID)
+		if err := db.Model(&schedule).Update("name", name).Error; err != nil {
+			return fmt.Errorf("failed to name schedule %d: %w", schedule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// prepareSlugIndexes readies databases created before slugs were unique:
+// repeated slugs are cleared except on the oldest row, so the unique index
+// can be built (BackfillSlugs gives the cleared rows new ones), and the old
+// non-unique index is dropped
+func prepareSlugIndexes(db *gorm.DB) error {
+	for _, t := range []struct {
+		model interface{}
+		table string
+	}{{&models.Athlete{}, "athletes"}, {&models.Academy{}, "academies"}} {
+		migrator := db.Migrator()
+		if !migrator.HasTable(t.model) || migrator.HasIndex(t.model, "idx_"+t.table+"_slug_unique") {
+			continue
+		}
+		err := db.Model(t.model).
+			Where("slug <> '' AND id > (SELECT MIN(d.id) FROM "+t.table+" d WHERE d.slug = "+t.table+".slug)").
+			UpdateColumn("slug", "").Error
+		if err != nil {
+			return err
+		}
+		if migrator.HasIndex(t.model, "idx_"+t.table+"_slug") {
+			if err := migrator.DropIndex(t.model, "idx_"+t.table+"_slug"); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// RunOnce runs a one-off data migration unless it is recorded as applied,
+// and records it when it succeeds
+func RunOnce(name string, fn func() error) error {
+	var count int64
+	if err := DB.Model(&models.DataMigration{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return DB.Create(&models.DataMigration{Name: name, AppliedAt: time.Now()}).Error
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }
 
+// IsPostgres reports whether the database is PostgreSQL, for the few
+// statements whose SQL differs from SQLite
+func IsPostgres() bool {
+	return DB != nil && DB.Dialector.Name() == DriverPostgres
+}
+
+// DaysBetweenSQL returns an expression for the days, with fractions, from
+// the timestamp column earlier to later
+func DaysBetweenSQL(later, earlier string) string {
+	if IsPostgres() {
+		return fmt.Sprintf("(EXTRACT(EPOCH FROM (%s - %s)) / 86400)", later, earlier)
+	}
+	return fmt.Sprintf("(julianday(%s) - julianday(%s))", later, earlier)
+}
+
 func CloseDatabase() error {
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	reloadMu sync.Mutex
+	// current is the configuration published by the last reload. A published
+	// Config is never modified afterwards; a reload publishes a new copy
+	current atomic.Pointer[Config]
+)
+
+// Latest returns the configuration published by the last reload, or c when
+// there has been none. Jobs take it once when they start, so a reload never
+// changes the settings of a running job
+func (c *Config) Latest() *Config {
+	if published := current.Load(); published != nil {
+		return published
+	}
+	return c
+}
+
+// Reload reads the configuration source again and publishes a copy of the
+// latest configuration with the settings that are safe to change at runtime
+// replaced: request delay, target countries, the extra allowlisted paths, log
+// level, the stale enrichment policy and ranking scoring. Everything else
+// (ports, paths, keys) still needs a restart. c itself is never modified;
+// readers pick the new values up through Latest. Returns the variables whose
+// value changed.
+func (c *Config) Reload() ([]string, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	loaded, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := loaded.Validate(); err != nil {
+		return nil, err
+	}
+
+	next := *c.Latest()
+	var changed []string
+	apply := func(name string, dst, src interface{}) {
+		d := reflect.ValueOf(dst).Elem()
+		s := reflect.ValueOf(src).Elem()
+		if !reflect.DeepEqual(d.Interface(), s.Interface()) {
+			d.Set(s)
+			changed = append(changed, name)
+		}
+	}
+
+	apply("REQUEST_DELAY_MS", &next.Scraper.RequestDelayMs, &loaded.Scraper.RequestDelayMs)
+	apply("TARGET_COUNTRIES", &next.Scraper.TargetCountries, &loaded.Scraper.TargetCountries)
+	apply("SCRAPER_ALLOWED_PATHS", &next.Scraper.AllowedPaths, &loaded.Scraper.AllowedPaths)
+	apply("LOG_LEVEL", &next.Logging.Level, &loaded.Logging.Level)
+	apply("ENRICH_STALE_DAYS", &next.Scheduler.StaleProfileAge, &loaded.Scheduler.StaleProfileAge)
+	apply("ENRICH_STALE_BATCH", &next.Scheduler.StaleProfileBatch, &loaded.Scheduler.StaleProfileBatch)
+	apply("RANKING_POINTS_GOLD", &next.Rankings.GoldPoints, &loaded.Rankings.GoldPoints)
+	apply("RANKING_POINTS_SILVER", &next.Rankings.SilverPoints, &loaded.Rankings.SilverPoints)
+	apply("RANKING_POINTS_BRONZE", &next.Rankings.BronzePoints, &loaded.Rankings.BronzePoints)
+	apply("RANKING_REFERENCE_EVENT_SIZE", &next.Rankings.ReferenceEventSize, &loaded.Rankings.ReferenceEventSize)
+	apply("RANKING_MIN_EVENT_WEIGHT", &next.Rankings.MinEventWeight, &loaded.Rankings.MinEventWeight)
+	apply("RANKING_MAX_EVENT_WEIGHT", &next.Rankings.MaxEventWeight, &loaded.Rankings.MaxEventWeight)
+
+	if len(changed) > 0 {
+		current.Store(&next)
+	}
+	return changed, nil
+}
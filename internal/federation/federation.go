@@ -0,0 +1,388 @@
+// Package federation links stored athletes to their IDs in official
+// federations (IBJJF, AJP) from imported rows, so results from other sources
+// can be joined to the same athlete.
+package federation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxRows is the most rows an import accepts
+const MaxRows = 5000
+
+// Import row statuses
+const (
+	StatusLinked    = "linked"    // a new link was stored
+	StatusUnchanged = "unchanged" // the link already existed
+	StatusAmbiguous = "ambiguous" // several athletes match; see Candidates
+	StatusUnmatched = "unmatched" // no stored athlete matches
+	StatusConflict  = "conflict"  // the ID or the athlete is already linked differently
+	StatusInvalid   = "invalid"   // the row misses required fields
+)
+
+// Row is one imported federation ID. ExternalID names the Smoothcomp athlete
+// when known; otherwise the athlete is found by Name, narrowed by Country,
+// BirthYear and Academy when given.
+type Row struct {
+	Line         int    `json:"line,omitempty"` // CSV line, for reports
+	Federation   string `json:"federation"`
+	FederationID string `json:"federation_id"`
+	ExternalID   string `json:"external_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Country      string `json:"country,omitempty"`
+	BirthYear    int    `json:"birth_year,omitempty"`
+	Academy      string `json:"academy,omitempty"`
+}
+
+// RowResult is the outcome of one imported row
+type RowResult struct {
+	Line              int      `json:"line"`
+	Federation        string   `json:"federation"`
+	FederationID      string   `json:"federation_id"`
+	Status            string   `json:"status"`
+	AthleteExternalID string   `json:"athlete_external_id,omitempty"`
+	MatchedBy         string   `json:"matched_by,omitempty"`
+	Candidates        []string `json:"candidates,omitempty"` // external IDs, when ambiguous
+	Error             string   `json:"error,omitempty"`
+}
+
+// Report summarizes an import
+type Report struct {
+	Rows    int            `json:"rows"`
+	DryRun  bool           `json:"dry_run"`
+	Counts  map[string]int `json:"counts"` // rows by status
+	Results []RowResult    `json:"results"`
+}
+
+// csvColumns maps accepted CSV headers to Row fields
+var csvColumns = map[string]string{
+	"federation":    "federation",
+	"federation_id": "federation_id",
+	"id":            "federation_id",
+	"ibjjf_id":      "federation_id",
+	"ajp_id":        "federation_id",
+	"external_id":   "external_id",
+	"smoothcomp_id": "external_id",
+	"name":          "name",
+	"full_name":     "name",
+	"first_name":    "first_name",
+	"last_name":     "last_name",
+	"country":       "country",
+	"country_code":  "country",
+	"nationality":   "country",
+	"birth_year":    "birth_year",
+	"academy":       "academy",
+	"team":          "academy",
+}
+
+// ParseCSV reads rows from a CSV with a header line. Headers are matched
+// case-insensitively (federation, federation_id or id, external_id, name or
+// first_name and last_name, country, birth_year, academy; unknown columns
+// are ignored). An ibjjf_id or ajp_id column names its federation;
+// otherwise rows without a federation column use federation.
+func ParseCSV(r io.Reader, federation string) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, ok := csvColumns[name]
+		if !ok {
+			continue
+		}
+		if _, dup := columns[field]; !dup {
+			columns[field] = i
+		}
+		if name == "ibjjf_id" || name == "ajp_id" {
+			federation = strings.TrimSuffix(name, "_id")
+		}
+	}
+	if _, ok := columns["federation_id"]; !ok {
+		return nil, errors.New("CSV has no federation_id column")
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("CSV has more than %d rows", MaxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := Row{
+			Line:         line,
+			Federation:   get("federation"),
+			FederationID: get("federation_id"),
+			ExternalID:   get("external_id"),
+			Name:         get("name"),
+			Country:      get("country"),
+			Academy:      get("academy"),
+		}
+		if row.Federation == "" {
+			row.Federation = federation
+		}
+		if row.Name == "" {
+			row.Name = strings.TrimSpace(get("first_name") + " " + get("last_name"))
+		}
+		row.BirthYear, _ = strconv.Atoi(get("birth_year"))
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Import matches every row to a stored athlete and links the federation ID
+// to it. Existing links are never changed: a row whose ID or athlete is
+// already linked differently is reported as a conflict. With dryRun nothing
+// is stored.
+func Import(rows []Row, dryRun bool, actor string) (*Report, error) {
+	if len(rows) > MaxRows {
+		return nil, fmt.Errorf("import has more than %d rows", MaxRows)
+	}
+
+	db := config.GetDB()
+	report := &Report{
+		Rows:    len(rows),
+		DryRun:  dryRun,
+		Counts:  map[string]int{},
+		Results: make([]RowResult, 0, len(rows)),
+	}
+
+	// Links planned by earlier rows, so a dry run reports the same
+	// conflicts a real import would
+	byID := map[string]string{}
+	byAthlete := map[string]string{}
+
+	for i, row := range rows {
+		result := importRow(db, row, dryRun, actor, byID, byAthlete)
+		if result.Line == 0 {
+			result.Line = i + 1
+		}
+		report.Counts[result.Status]++
+		report.Results = append(report.Results, result)
+	}
+
+	logger.Info("Federation IDs imported",
+		zap.Int("rows", report.Rows),
+		zap.Int("linked", report.Counts[StatusLinked]),
+		zap.Int("ambiguous", report.Counts[StatusAmbiguous]),
+		zap.Int("unmatched", report.Counts[StatusUnmatched]),
+		zap.Int("conflicts", report.Counts[StatusConflict]),
+		zap.Bool("dry_run", dryRun),
+		zap.String("actor", actor))
+
+	return report, nil
+}
+
+func importRow(db *gorm.DB, row Row, dryRun bool, actor string, byID, byAthlete map[string]string) RowResult {
+	result := RowResult{
+		Line:         row.Line,
+		Federation:   row.Federation,
+		FederationID: strings.TrimSpace(row.FederationID),
+	}
+
+	federation, ok := models.ParseFederation(row.Federation)
+	if !ok {
+		result.Status = StatusInvalid
+		result.Error = fmt.Sprintf("unknown federation %q (ibjjf, ajp)", row.Federation)
+		return result
+	}
+	result.Federation = federation
+	if result.FederationID == "" {
+		result.Status = StatusInvalid
+		result.Error = "federation_id is required"
+		return result
+	}
+
+	var athletes []models.Athlete
+	switch {
+	case strings.TrimSpace(row.ExternalID) != "":
+		result.MatchedBy = models.FederationMatchExternalID
+		db.Where("external_id = ?", strings.TrimSpace(row.ExternalID)).Limit(1).Find(&athletes)
+	case strings.TrimSpace(row.Name) != "":
+		result.MatchedBy = models.FederationMatchName
+		athletes = matchName(db, row)
+	default:
+		result.Status = StatusInvalid
+		result.Error = "external_id or name is required"
+		return result
+	}
+
+	switch len(athletes) {
+	case 0:
+		result.Status = StatusUnmatched
+		return result
+	case 1:
+	default:
+		result.Status = StatusAmbiguous
+		for _, athlete := range athletes {
+			result.Candidates = append(result.Candidates, athlete.ExternalID)
+		}
+		return result
+	}
+	result.AthleteExternalID = athletes[0].ExternalID
+
+	idKey := federation + "|" + result.FederationID
+	athleteKey := federation + "|" + result.AthleteExternalID
+
+	linkedAthlete, idLinked := byID[idKey]
+	if !idLinked {
+		var link models.FederationID
+		if db.Where("federation = ? AND federation_athlete_id = ?", federation, result.FederationID).
+			Limit(1).Find(&link).RowsAffected > 0 {
+			linkedAthlete, idLinked = link.AthleteExternalID, true
+		}
+	}
+	linkedID, athleteLinked := byAthlete[athleteKey]
+	if !athleteLinked {
+		var link models.FederationID
+		if db.Where("federation = ? AND athlete_external_id = ?", federation, result.AthleteExternalID).
+			Limit(1).Find(&link).RowsAffected > 0 {
+			linkedID, athleteLinked = link.FederationAthleteID, true
+		}
+	}
+
+	switch {
+	case idLinked && linkedAthlete == result.AthleteExternalID:
+		result.Status = StatusUnchanged
+		return result
+	case idLinked:
+		result.Status = StatusConflict
+		result.Error = fmt.Sprintf("%s ID %s is linked to athlete %s", federation, result.FederationID, linkedAthlete)
+		return result
+	case athleteLinked:
+		result.Status = StatusConflict
+		result.Error = fmt.Sprintf("athlete already has %s ID %s", federation, linkedID)
+		return result
+	}
+
+	if !dryRun {
+		link := models.FederationID{
+			AthleteExternalID:   result.AthleteExternalID,
+			Federation:          federation,
+			FederationAthleteID: result.FederationID,
+			Name:                strings.TrimSpace(row.Name),
+			MatchedBy:           result.MatchedBy,
+			Actor:               actor,
+		}
+		if err := db.Create(&link).Error; err != nil {
+			result.Status = StatusInvalid
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	byID[idKey] = result.AthleteExternalID
+	byAthlete[athleteKey] = result.FederationID
+	result.Status = StatusLinked
+	return result
+}
+
+// matchName returns the athletes whose full name equals the row name,
+// ignoring case and accents ("Last, First" is read as "First Last").
+// Country, birth year and academy narrow the candidates when both the row
+// and the athlete have them.
+func matchName(db *gorm.DB, row Row) []models.Athlete {
+	name := strings.TrimSpace(row.Name)
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+	}
+	base := slug.Make(name)
+	if base == "" {
+		return nil
+	}
+
+	// Slugs of namesakes get a -2, -3... suffix
+	var candidates []models.Athlete
+	db.Preload("Academy").Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&candidates)
+
+	matches := candidates[:0]
+	for _, athlete := range candidates {
+		if slug.Make(athlete.FullName) != base {
+			continue
+		}
+		if row.Country != "" && athlete.CountryCode != "" && !strings.EqualFold(row.Country, athlete.CountryCode) &&
+			!strings.EqualFold(row.Country, athlete.Nationality) {
+			continue
+		}
+		if row.BirthYear > 0 && athlete.BirthYear > 0 && row.BirthYear != athlete.BirthYear {
+			continue
+		}
+		if row.Academy != "" && !sameAcademy(row.Academy, athlete) {
+			continue
+		}
+		matches = append(matches, athlete)
+	}
+	return matches
+}
+
+// sameAcademy reports whether academy names the athlete's academy or
+// affiliation; athletes without either are not ruled out
+func sameAcademy(academy string, athlete models.Athlete) bool {
+	want := slug.Make(academy)
+	var names []string
+	if athlete.Academy != nil {
+		names = append(names, athlete.Academy.Name)
+	}
+	if athlete.AffiliationName != "" {
+		names = append(names, athlete.AffiliationName)
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if have := slug.Make(name); have != "" && (strings.Contains(have, want) || strings.Contains(want, have)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAthlete returns the athlete linked to a federation ID
+func FindAthlete(federation, federationID string) (*models.Athlete, error) {
+	db := config.GetDB()
+
+	var link models.FederationID
+	if err := db.Where("federation = ? AND federation_athlete_id = ?", federation, federationID).
+		First(&link).Error; err != nil {
+		return nil, err
+	}
+
+	var athlete models.Athlete
+	if err := db.Preload("Academy").Preload("FederationIDs").
+		Where("external_id = ?", link.AthleteExternalID).First(&athlete).Error; err != nil {
+		return nil, err
+	}
+	return &athlete, nil
+}
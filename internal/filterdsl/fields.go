@@ -0,0 +1,38 @@
+package filterdsl
+
+// Athletes are the fields of GET /athletes?filter=
+var Athletes = Fields{
+	"name":                 {Column: "full_name", Kind: String},
+	"first_name":           {Column: "first_name", Kind: String},
+	"last_name":            {Column: "last_name", Kind: String},
+	"country":              {Column: "country_code", Kind: String},
+	"nationality":          {Column: "nationality", Kind: String},
+	"belt":                 {Column: "belt_rank", Kind: String},
+	"gender":               {Column: "gender", Kind: String},
+	"academy_id":           {Column: "academy_external_id", Kind: String},
+	"affiliation":          {Column: "affiliation_name", Kind: String},
+	"age":                  {Column: "age", Kind: Number},
+	"birth_year":           {Column: "birth_year", Kind: Number},
+	"total_wins":           {Column: "total_wins", Kind: Number},
+	"wins_by_submission":   {Column: "wins_by_submission", Kind: Number},
+	"wins_by_points":       {Column: "wins_by_points", Kind: Number},
+	"wins_by_decision":     {Column: "wins_by_decision", Kind: Number},
+	"wins_by_dq":           {Column: "wins_by_dq", Kind: Number},
+	"total_losses":         {Column: "total_losses", Kind: Number},
+	"losses_by_submission": {Column: "losses_by_submission", Kind: Number},
+	"first_seen_at":        {Column: "first_seen_at", Kind: Date},
+	"last_active_at":       {Column: "last_active_at", Kind: Date, Nullable: true},
+	"profile_scraped_at":   {Column: "profile_scraped_at", Kind: Date, Nullable: true},
+}
+
+// Events are the fields of GET /events?filter=
+var Events = Fields{
+	"name":              {Column: "name", Kind: String},
+	"city":              {Column: "city", Kind: String},
+	"country":           {Column: "country_code", Kind: String},
+	"country_name":      {Column: "country", Kind: String},
+	"type":              {Column: "event_type", Kind: String},
+	"section":           {Column: "section", Kind: String},
+	"start_date":        {Column: "start_date", Kind: Date, Nullable: true},
+	"results_published": {Column: "results_published", Kind: Bool},
+}
@@ -0,0 +1,335 @@
+// Package filterdsl parses the ?filter= expressions of the list endpoints,
+// such as
+//
+//	belt == "Black belt" && total_wins > 20 && country in ["BR", "CL"]
+//
+// and translates them to SQL conditions. Only the fields of an allowlist can
+// be named, each mapped to its column, and values are always bound as
+// parameters.
+package filterdsl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxLength is the longest expression accepted
+const MaxLength = 1000
+
+// maxDepth caps the nesting of parentheses and negations
+const maxDepth = 16
+
+// Kind is the type of a filterable field, which decides the values and
+// operators it accepts
+type Kind int
+
+const (
+	String Kind = iota // ==, !=, in, not in, contains; compared case-insensitively
+	Number             // ==, !=, <, <=, >, >=, in, not in
+	Bool               // ==, !=
+	Date               // ==, !=, <, <=, >, >= against "YYYY-MM-DD"
+)
+
+// Field is a filterable field and the column it reads
+type Field struct {
+	Column   string
+	Kind     Kind
+	Nullable bool // accepts == null and != null
+}
+
+// Fields maps the names accepted in expressions to their columns
+type Fields map[string]Field
+
+// Names returns the field names, sorted
+func (f Fields) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Error is a malformed expression, with the byte offset where it went wrong
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
+
+// Apply narrows query to the rows matching expr. An empty expr leaves the
+// query unchanged.
+func Apply(query *gorm.DB, expr string, fields Fields) (*gorm.DB, error) {
+	if strings.TrimSpace(expr) == "" {
+		return query, nil
+	}
+	condition, args, err := Parse(expr, fields)
+	if err != nil {
+		return nil, err
+	}
+	return query.Where("("+condition+")", args...), nil
+}
+
+// Parse translates expr to a SQL condition with "?" placeholders and the
+// arguments bound to them
+func Parse(expr string, fields Fields) (string, []interface{}, error) {
+	if len(expr) > MaxLength {
+		return "", nil, &Error{Pos: MaxLength, Msg: fmt.Sprintf("expression longer than %d characters", MaxLength)}
+	}
+	tokens, err := lex(expr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	condition, err := p.or(0)
+	if err != nil {
+		return "", nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return "", nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return condition, p.args, nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	fields Fields
+	args   []interface{}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+// or := and ("||" and)*
+func (p *parser) or(depth int) (string, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return "", err
+	}
+	for p.peek().is(tokOp, "||") {
+		p.take()
+		right, err := p.and(depth)
+		if err != nil {
+			return "", err
+		}
+		left = "(" + left + " OR " + right + ")"
+	}
+	return left, nil
+}
+
+// and := unary ("&&" unary)*
+func (p *parser) and(depth int) (string, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return "", err
+	}
+	for p.peek().is(tokOp, "&&") {
+		p.take()
+		right, err := p.unary(depth)
+		if err != nil {
+			return "", err
+		}
+		left = left + " AND " + right
+	}
+	return left, nil
+}
+
+// unary := "!" unary | "(" or ")" | comparison
+func (p *parser) unary(depth int) (string, error) {
+	tok := p.peek()
+	if depth > maxDepth && (tok.is(tokOp, "!") || tok.is(tokOp, "(")) {
+		return "", &Error{Pos: tok.pos, Msg: "expression nested too deeply"}
+	}
+	switch {
+	case tok.is(tokOp, "!"):
+		p.take()
+		inner, err := p.unary(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case tok.is(tokOp, "("):
+		p.take()
+		inner, err := p.or(depth + 1)
+		if err != nil {
+			return "", err
+		}
+		if closing := p.take(); !closing.is(tokOp, ")") {
+			return "", &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ) but found %s", closing)}
+		}
+		return "(" + inner + ")", nil
+	}
+	return p.comparison()
+}
+
+// comparison := field op value | field ["not"] "in" list | field "contains" string
+func (p *parser) comparison() (string, error) {
+	name := p.take()
+	if name.kind != tokIdent {
+		return "", &Error{Pos: name.pos, Msg: fmt.Sprintf("expected a field name but found %s", name)}
+	}
+	field, ok := p.fields[name.text]
+	if !ok {
+		return "", &Error{Pos: name.pos, Msg: fmt.Sprintf("unknown field %q (allowed: %s)", name.text, strings.Join(p.fields.Names(), ", "))}
+	}
+
+	op := p.take()
+	switch {
+	case op.is(tokIdent, "in"):
+		return p.in(field, name, "IN")
+	case op.is(tokIdent, "not"):
+		if in := p.take(); !in.is(tokIdent, "in") {
+			return "", &Error{Pos: in.pos, Msg: fmt.Sprintf("expected in after not but found %s", in)}
+		}
+		return p.in(field, name, "NOT IN")
+	case op.is(tokIdent, "contains"):
+		if field.Kind != String {
+			return "", &Error{Pos: op.pos, Msg: fmt.Sprintf("contains only applies to text fields, not %s", name.text)}
+		}
+		value := p.take()
+		if value.kind != tokString {
+			return "", &Error{Pos: value.pos, Msg: fmt.Sprintf("contains expects a string but found %s", value)}
+		}
+		p.args = append(p.args, "%"+likeEscaper.Replace(strings.ToLower(value.text))+"%")
+		return "LOWER(" + field.Column + `) LIKE ? ESCAPE '\'`, nil
+	case op.kind != tokOp || !comparisonOps[op.text]:
+		return "", &Error{Pos: op.pos, Msg: fmt.Sprintf("expected an operator after %s but found %s", name.text, op)}
+	}
+
+	value := p.take()
+	if value.is(tokIdent, "null") {
+		if !field.Nullable || (op.text != "==" && op.text != "!=") {
+			return "", &Error{Pos: value.pos, Msg: fmt.Sprintf("%s cannot be compared with null", name.text)}
+		}
+		if op.text == "==" {
+			return field.Column + " IS NULL", nil
+		}
+		return field.Column + " IS NOT NULL", nil
+	}
+	if op.text != "==" && op.text != "!=" {
+		switch field.Kind {
+		case String:
+			return "", &Error{Pos: op.pos, Msg: fmt.Sprintf("%s only supports ==, !=, in and contains", name.text)}
+		case Bool:
+			return "", &Error{Pos: op.pos, Msg: fmt.Sprintf("%s only supports == and !=", name.text)}
+		}
+	}
+	arg, err := fieldValue(field, name.text, value)
+	if err != nil {
+		return "", err
+	}
+	p.args = append(p.args, arg)
+
+	sqlOp := op.text
+	switch sqlOp {
+	case "==":
+		sqlOp = "="
+	case "!=":
+		sqlOp = "<>"
+	}
+	if field.Kind == String {
+		return "LOWER(" + field.Column + ") " + sqlOp + " ?", nil
+	}
+	return field.Column + " " + sqlOp + " ?", nil
+}
+
+// in := "[" value ("," value)* "]"
+func (p *parser) in(field Field, name token, sqlOp string) (string, error) {
+	if field.Kind == Bool {
+		return "", &Error{Pos: name.pos, Msg: fmt.Sprintf("%s does not support in", name.text)}
+	}
+	if open := p.take(); !open.is(tokOp, "[") {
+		return "", &Error{Pos: open.pos, Msg: fmt.Sprintf("expected [ but found %s", open)}
+	}
+
+	var values []interface{}
+	for {
+		value := p.take()
+		arg, err := fieldValue(field, name.text, value)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, arg)
+
+		sep := p.take()
+		if sep.is(tokOp, "]") {
+			break
+		}
+		if !sep.is(tokOp, ",") {
+			return "", &Error{Pos: sep.pos, Msg: fmt.Sprintf("expected , or ] but found %s", sep)}
+		}
+	}
+	p.args = append(p.args, values)
+
+	if field.Kind == String {
+		return "LOWER(" + field.Column + ") " + sqlOp + " ?", nil
+	}
+	return field.Column + " " + sqlOp + " ?", nil
+}
+
+var comparisonOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// fieldValue checks that value suits the field and converts it to the
+// argument bound in SQL
+func fieldValue(field Field, name string, value token) (interface{}, error) {
+	mismatch := func(expected string) error {
+		return &Error{Pos: value.pos, Msg: fmt.Sprintf("%s expects %s but found %s", name, expected, value)}
+	}
+
+	switch field.Kind {
+	case String:
+		if value.kind != tokString {
+			return nil, mismatch("a string")
+		}
+		return strings.ToLower(value.text), nil
+	case Number:
+		if value.kind != tokNumber {
+			return nil, mismatch("a number")
+		}
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, mismatch("a number")
+		}
+		return number, nil
+	case Bool:
+		if value.is(tokIdent, "true") {
+			return true, nil
+		}
+		if value.is(tokIdent, "false") {
+			return false, nil
+		}
+		return nil, mismatch("true or false")
+	case Date:
+		if value.kind == tokString {
+			if date, err := time.Parse("2006-01-02", value.text); err == nil {
+				return date, nil
+			}
+			if date, err := time.Parse(time.RFC3339, value.text); err == nil {
+				return date, nil
+			}
+		}
+		return nil, mismatch(`a date such as "2024-05-31"`)
+	}
+	return nil, mismatch("a value")
+}
@@ -0,0 +1,105 @@
+package fixtures
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// OriginalHostHeader carries the host the scraper meant to reach when its
+// requests are redirected to the fixture server via TEST_BASE_URL.
+const OriginalHostHeader = "X-Original-Host"
+
+// fixtureHost matches the hosts with their own fixture directory:
+// smoothcomp.com and its subdomains, with no path separators or ".."
+var fixtureHost = regexp.MustCompile(`^([a-z0-9-]+\.)*smoothcomp\.com$`)
+
+// Server serves stored HTML/JSON pages that mimic smoothcomp.com.
+//
+// A request for https://adcc.smoothcomp.com/en/event/123/participants is
+// resolved against the fixture directory in this order:
+//
+//	<dir>/adcc.smoothcomp.com/en/event/123/participants(.json|.html)
+//	<dir>/en/event/123/participants(.json|.html)
+//
+// Directories resolve to their index.html or index.json. Later pages of
+// paginated endpoints (?page=2) resolve to <path>/page/2(.json|.html).
+type Server struct {
+	dir string
+}
+
+// NewServer creates a fixture server rooted at dir
+func NewServer(dir string) *Server {
+	return &Server{dir: dir}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cleanPath := path.Clean("/" + r.URL.Path)
+	if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+		cleanPath = path.Join(cleanPath, "page", path.Base(page))
+	}
+	host := strings.ToLower(r.Header.Get(OriginalHostHeader))
+
+	file, ok := s.resolve(host, cleanPath)
+	if !ok {
+		logger.Debug("Fixture not found",
+			zap.String("host", host),
+			zap.String("path", cleanPath))
+		http.NotFound(w, r)
+		return
+	}
+
+	switch filepath.Ext(file) {
+	case ".json":
+		w.Header().Set("Content-Type", "application/json")
+	case ".html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+
+	http.ServeFile(w, r, file)
+}
+
+// resolve finds the fixture file for requestPath, first under the directory
+// of host. Hosts other than smoothcomp.com and its subdomains only use the
+// shared files, and nothing outside s.dir is ever served.
+func (s *Server) resolve(host string, requestPath string) (string, bool) {
+	roots := make([]string, 0, 2)
+	if fixtureHost.MatchString(host) {
+		roots = append(roots, filepath.Join(s.dir, host))
+	}
+	roots = append(roots, s.dir)
+
+	for _, root := range roots {
+		base := filepath.Join(root, filepath.FromSlash(requestPath))
+		candidates := []string{
+			base,
+			base + ".json",
+			base + ".html",
+			filepath.Join(base, "index.html"),
+			filepath.Join(base, "index.json"),
+		}
+		for _, candidate := range candidates {
+			if !s.contains(candidate) {
+				continue
+			}
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// contains reports whether file is inside the fixture directory
+func (s *Server) contains(file string) bool {
+	rel, err := filepath.Rel(s.dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
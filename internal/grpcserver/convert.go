@@ -0,0 +1,132 @@
+package grpcserver
+
+import (
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	pb "github.com/kmicac/smoothcomp-scraper/pkg/smoothcomppb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func athleteProto(a *models.Athlete) *pb.Athlete {
+	return &pb.Athlete{
+		Id:                 int64(a.ID),
+		ExternalId:         a.ExternalID,
+		FirstName:          a.FirstName,
+		LastName:           a.LastName,
+		FullName:           a.FullName,
+		Slug:               a.Slug,
+		AcademyExternalId:  a.AcademyExternalID,
+		Nationality:        a.Nationality,
+		CountryCode:        a.CountryCode,
+		BeltRank:           a.BeltRank,
+		Age:                int32(a.Age),
+		Gender:             string(a.Gender),
+		ProfileUrl:         a.ProfileURL,
+		ImageUrl:           a.ImageURL,
+		BirthYear:          int32(a.BirthYear),
+		TotalWins:          int32(a.TotalWins),
+		WinsBySubmission:   int32(a.WinsBySubmission),
+		WinsByPoints:       int32(a.WinsByPoints),
+		WinsByDecision:     int32(a.WinsByDecision),
+		WinsByDq:           int32(a.WinsByDQ),
+		TotalLosses:        int32(a.TotalLosses),
+		LossesBySubmission: int32(a.LossesBySubmission),
+		LossesByPoints:     int32(a.LossesByPoints),
+		LossesByDecision:   int32(a.LossesByDecision),
+		LossesByDq:         int32(a.LossesByDQ),
+		ScrapedAt:          timestamp(a.ScrapedAt),
+		FirstSeenAt:        timestamp(a.FirstSeenAt),
+		LastActiveAt:       optionalTimestamp(a.LastActiveAt),
+	}
+}
+
+func academyProto(a *models.Academy) *pb.Academy {
+	return &pb.Academy{
+		Id:           int64(a.ID),
+		ExternalId:   a.ExternalID,
+		Name:         a.Name,
+		Slug:         a.Slug,
+		ClubUrl:      a.ClubURL,
+		Country:      a.Country,
+		CountryCode:  a.CountryCode,
+		LogoUrl:      a.LogoURL,
+		Website:      a.Website,
+		TotalWins:    int32(a.TotalWins),
+		TotalLosses:  int32(a.TotalLosses),
+		AthleteCount: int32(a.AthleteCount),
+		GoldMedals:   int32(a.GoldMedals),
+		SilverMedals: int32(a.SilverMedals),
+		BronzeMedals: int32(a.BronzeMedals),
+		ScrapedAt:    timestamp(a.ScrapedAt),
+	}
+}
+
+func eventProto(e *models.Event) *pb.Event {
+	return &pb.Event{
+		Id:               int64(e.ID),
+		ExternalId:       e.ExternalID,
+		Name:             e.Name,
+		EventUrl:         e.EventURL,
+		ImageUrl:         e.ImageURL,
+		City:             e.City,
+		Country:          e.Country,
+		CountryCode:      e.CountryCode,
+		DateText:         e.DateText,
+		StartDate:        optionalTimestamp(e.StartDate),
+		EventType:        e.EventType,
+		Section:          e.Section,
+		ResultsPublished: e.ResultsPublished,
+		ScrapedAt:        timestamp(e.ScrapedAt),
+	}
+}
+
+func scrapeJobProto(j *models.ScrapeJob) *pb.ScrapeJob {
+	job := &pb.ScrapeJob{
+		Id:             int64(j.ID),
+		JobType:        j.JobType,
+		Depth:          j.Depth,
+		Status:         j.Status,
+		StartedAt:      timestamp(j.StartedAt),
+		CompletedAt:    optionalTimestamp(j.CompletedAt),
+		ItemsScraped:   int32(j.ItemsScraped),
+		PagesFailed:    int32(j.PagesFailed),
+		CurrentPhase:   j.CurrentPhase,
+		ItemsTotal:     int32(j.ItemsTotal),
+		ItemsProcessed: int32(j.ItemsProcessed),
+		ErrorMessage:   j.ErrorMessage,
+		Country:        j.Country,
+	}
+	if j.ParentJobID != nil {
+		job.ParentJobId = int64(*j.ParentJobID)
+	}
+	return job
+}
+
+func queuedJobProto(j *models.QueuedJob) *pb.QueuedJob {
+	return &pb.QueuedJob{
+		Id:          int64(j.ID),
+		JobType:     j.JobType,
+		Status:      j.Status,
+		Attempts:    int32(j.Attempts),
+		MaxAttempts: int32(j.MaxAttempts),
+		LastError:   j.LastError,
+		RunAfter:    timestamp(j.RunAfter),
+		CreatedAt:   timestamp(j.CreatedAt),
+	}
+}
+
+// timestamp leaves zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
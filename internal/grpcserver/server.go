@@ -0,0 +1,353 @@
+// Package grpcserver serves the stored data and scrape triggers over gRPC
+// (see proto/smoothcomp/v1), for services that would rather not decode the
+// JSON API. It runs alongside the HTTP server on GRPC_PORT, when set.
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/filterdsl"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/queue"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	pb "github.com/kmicac/smoothcomp-scraper/pkg/smoothcomppb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// List pages are larger than the JSON API's, since bulk reads are what the
+// gRPC interface is for
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Service implements pb.SmoothcompServiceServer
+type Service struct {
+	pb.UnimplementedSmoothcompServiceServer
+
+	config  *config.Config
+	queue   *queue.Queue
+	privacy *privacy.Policy
+}
+
+// New creates the gRPC server with the service registered. Reflection is
+// enabled so tools like grpcurl can list the methods.
+func New(cfg *config.Config, jobs *queue.Queue) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(loggingInterceptor, adminInterceptor(cfg.Server.AdminAPIKey)))
+	pb.RegisterSmoothcompServiceServer(server, &Service{
+		config:  cfg,
+		queue:   jobs,
+		privacy: privacy.NewPolicy(cfg),
+	})
+	reflection.Register(server)
+	return server
+}
+
+// loggingInterceptor logs each call like the HTTP logging middleware
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+	logger.Info("gRPC Request",
+		zap.String("method", info.FullMethod),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("remote_addr", remote),
+	)
+	return resp, err
+}
+
+// adminMethods are the calls that need ADMIN_API_KEY, when it is set
+var adminMethods = map[string]bool{
+	pb.SmoothcompService_Scrape_FullMethodName: true,
+}
+
+// adminInterceptor requires the admin key for adminMethods, sent like the
+// HTTP header as "x-admin-key" or "authorization: Bearer ..." metadata
+func adminInterceptor(apiKey string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if apiKey == "" || !adminMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		key := ""
+		if values := md.Get("x-admin-key"); len(values) > 0 {
+			key = values[0]
+		} else if values := md.Get("authorization"); len(values) > 0 {
+			key = strings.TrimPrefix(values[0], "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin API key")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Scrape queues a scrape job, checking its parameters like the HTTP
+// scrape endpoints do
+func (s *Service) Scrape(ctx context.Context, req *pb.ScrapeRequest) (*pb.QueuedJob, error) {
+	params := models.QueueParams{
+		Country:    strings.TrimSpace(req.Country),
+		EventID:    strings.TrimSpace(req.EventId),
+		EventName:  req.EventName,
+		EventURL:   req.EventUrl,
+		Federation: strings.TrimSpace(req.Federation),
+		Season:     strings.TrimSpace(req.Season),
+		AcademyID:  strings.TrimSpace(req.AcademyId),
+	}
+
+	switch req.JobType {
+	case queue.JobTypeAcademies, queue.JobTypeAll, queue.JobTypeAcademyStats:
+	case queue.JobTypeEventsPast, queue.JobTypeEventsUpcoming:
+		if params.Country == "" {
+			params.Country = "AR"
+		}
+		depth, err := scraper.ParseDepth(req.Depth)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		params.Depth = depth.String()
+	case queue.JobTypeEventAthletes, queue.JobTypeEventBrackets, queue.JobTypeEventResults:
+		if params.EventID == "" {
+			return nil, status.Error(codes.InvalidArgument, "event_id is required")
+		}
+		if scraper.IsBlocked(models.BlockedEvent, params.EventID) {
+			return nil, status.Error(codes.FailedPrecondition, "event is blocklisted")
+		}
+		if req.JobType == queue.JobTypeEventAthletes && params.EventName == "" {
+			params.EventName = "Event " + params.EventID
+		}
+	case queue.JobTypeTeamRankings:
+		if params.Federation == "" {
+			return nil, status.Error(codes.InvalidArgument, "federation is required")
+		}
+	case queue.JobTypeCountryRoster:
+		params.Country = strings.ToUpper(params.Country)
+		if len(params.Country) != 2 {
+			return nil, status.Error(codes.InvalidArgument, "country must be a two-letter ISO code")
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown job_type %q", req.JobType)
+	}
+
+	queued, err := s.queue.Enqueue(req.JobType, params)
+	if err != nil {
+		logger.Error("Failed to queue scrape", zap.String("job_type", req.JobType), zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return queuedJobProto(queued), nil
+}
+
+// GetAthlete returns an athlete by external ID or slug
+func (s *Service) GetAthlete(ctx context.Context, req *pb.GetRequest) (*pb.Athlete, error) {
+	var athlete models.Athlete
+	if err := findByIDOrSlug(config.GetDB(), req.Id, &athlete); err != nil {
+		return nil, lookupError(err, "athlete not found")
+	}
+	s.privacy.MaskAthlete(&athlete)
+	return athleteProto(&athlete), nil
+}
+
+// ListAthletes pages through the athletes, most wins first
+func (s *Service) ListAthletes(ctx context.Context, req *pb.ListAthletesRequest) (*pb.ListAthletesResponse, error) {
+	page, limit := pagination(req.Page, req.Limit)
+
+	query := config.GetDB().Model(&models.Athlete{})
+	if req.Country != "" {
+		query = query.Where("country_code = ?", req.Country)
+	}
+	if req.AcademyId != "" {
+		query = query.Where("academy_external_id = ?", req.AcademyId)
+	}
+	if gender, _ := models.ParseGender(req.Gender); gender != models.GenderUnknown {
+		query = query.Where("gender = ?", gender)
+	}
+	query, err := filterdsl.Apply(query, req.Filter, filterdsl.Athletes)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to count athletes")
+	}
+	var athletes []models.Athlete
+	if err := query.Offset(int((page - 1) * limit)).Limit(int(limit)).Order("total_wins DESC").Find(&athletes).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to list athletes")
+	}
+	s.privacy.MaskAthletes(athletes)
+
+	resp := &pb.ListAthletesResponse{Page: page, Limit: limit, Total: total}
+	for i := range athletes {
+		resp.Athletes = append(resp.Athletes, athleteProto(&athletes[i]))
+	}
+	return resp, nil
+}
+
+// GetAcademy returns an academy by external ID or slug
+func (s *Service) GetAcademy(ctx context.Context, req *pb.GetRequest) (*pb.Academy, error) {
+	var academy models.Academy
+	if err := findByIDOrSlug(config.GetDB(), req.Id, &academy); err != nil {
+		return nil, lookupError(err, "academy not found")
+	}
+	return academyProto(&academy), nil
+}
+
+// ListAcademies pages through the academies, most wins first
+func (s *Service) ListAcademies(ctx context.Context, req *pb.ListAcademiesRequest) (*pb.ListAcademiesResponse, error) {
+	page, limit := pagination(req.Page, req.Limit)
+
+	query := config.GetDB().Model(&models.Academy{})
+	if req.Country != "" {
+		query = query.Where("country_code = ?", req.Country)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to count academies")
+	}
+	var academies []models.Academy
+	if err := query.Offset(int((page - 1) * limit)).Limit(int(limit)).Order("total_wins DESC").Find(&academies).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to list academies")
+	}
+
+	resp := &pb.ListAcademiesResponse{Page: page, Limit: limit, Total: total}
+	for i := range academies {
+		resp.Academies = append(resp.Academies, academyProto(&academies[i]))
+	}
+	return resp, nil
+}
+
+// GetEvent returns an event by external ID
+func (s *Service) GetEvent(ctx context.Context, req *pb.GetRequest) (*pb.Event, error) {
+	var event models.Event
+	if err := config.GetDB().Where("external_id = ?", req.Id).First(&event).Error; err != nil {
+		return nil, lookupError(err, "event not found")
+	}
+	return eventProto(&event), nil
+}
+
+// ListEvents pages through the events, most recently scraped first
+func (s *Service) ListEvents(ctx context.Context, req *pb.ListEventsRequest) (*pb.ListEventsResponse, error) {
+	page, limit := pagination(req.Page, req.Limit)
+
+	query := config.GetDB().Model(&models.Event{})
+	if eventType := strings.TrimSpace(req.Type); eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if country := strings.TrimSpace(req.Country); country != "" {
+		query = query.Where("country_code = ? OR country = ?", strings.ToUpper(country), country)
+	}
+	query, err := filterdsl.Apply(query, req.Filter, filterdsl.Events)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to count events")
+	}
+	var events []models.Event
+	if err := query.Offset(int((page - 1) * limit)).Limit(int(limit)).Order("scraped_at DESC").Find(&events).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to list events")
+	}
+
+	resp := &pb.ListEventsResponse{Page: page, Limit: limit, Total: total}
+	for i := range events {
+		resp.Events = append(resp.Events, eventProto(&events[i]))
+	}
+	return resp, nil
+}
+
+// GetScrapeJob returns a scrape job by ID
+func (s *Service) GetScrapeJob(ctx context.Context, req *pb.GetScrapeJobRequest) (*pb.ScrapeJob, error) {
+	var job models.ScrapeJob
+	if err := config.GetDB().First(&job, req.Id).Error; err != nil {
+		return nil, lookupError(err, "job not found")
+	}
+	return scrapeJobProto(&job), nil
+}
+
+// ListScrapeJobs pages through the scrape jobs, newest first
+func (s *Service) ListScrapeJobs(ctx context.Context, req *pb.ListScrapeJobsRequest) (*pb.ListScrapeJobsResponse, error) {
+	page, limit := pagination(req.Page, req.Limit)
+
+	query := config.GetDB().Model(&models.ScrapeJob{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.JobType != "" {
+		query = query.Where("job_type = ?", req.JobType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to count jobs")
+	}
+	var jobs []models.ScrapeJob
+	if err := query.Offset(int((page - 1) * limit)).Limit(int(limit)).Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return nil, status.Error(codes.Internal, "failed to list jobs")
+	}
+
+	resp := &pb.ListScrapeJobsResponse{Page: page, Limit: limit, Total: total}
+	for i := range jobs {
+		resp.Jobs = append(resp.Jobs, scrapeJobProto(&jobs[i]))
+	}
+	return resp, nil
+}
+
+// pagination applies the defaults and bounds of list requests
+func pagination(page, limit int32) (int32, int32) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return page, limit
+}
+
+// findByIDOrSlug loads a record by external ID, then by slug; athletes merged
+// on Smoothcomp are also found by their old ID
+func findByIDOrSlug(query *gorm.DB, key string, dest interface{}) error {
+	query = query.Session(&gorm.Session{})
+	err := query.Where("external_id = ?", key).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = query.Where("slug = ?", key).First(dest).Error
+	}
+	if _, athlete := dest.(*models.Athlete); athlete && errors.Is(err, gorm.ErrRecordNotFound) {
+		if mergedInto := scraper.ResolveAthleteID(config.GetDB(), key); mergedInto != key {
+			err = query.Where("external_id = ?", mergedInto).First(dest).Error
+		}
+	}
+	return err
+}
+
+// lookupError maps a failed lookup to NotFound or Internal
+func lookupError(err error, notFound string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, notFound)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
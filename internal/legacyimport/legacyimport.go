@@ -0,0 +1,559 @@
+// Package legacyimport loads the CSV exports of the legacy Python scraper
+// (athletes and podium results) so its history survives retiring it. Rows
+// are deduplicated against the stored external IDs, never overwrite scraped
+// values, and every import returns a reconciliation report of what was
+// created, already stored or differs.
+package legacyimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/academystats"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/names"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxRows is the most rows an import accepts
+const MaxRows = 50000
+
+// Kinds of legacy files
+const (
+	KindAthletes = "athletes"
+	KindResults  = "results"
+)
+
+// Import row statuses
+const (
+	StatusCreated   = "created"   // a new row was stored
+	StatusFilled    = "filled"    // the stored row gained fields it lacked
+	StatusUnchanged = "unchanged" // already stored with the same data
+	StatusDuplicate = "duplicate" // repeats an earlier row of the file
+	StatusSkipped   = "skipped"   // blocklisted or with removed personal data
+	StatusInvalid   = "invalid"   // the row misses required fields
+)
+
+// Difference is a field whose legacy value disagrees with the stored one.
+// Stored values win; differences are only reported.
+type Difference struct {
+	Field  string `json:"field"`
+	Legacy string `json:"legacy"`
+	Stored string `json:"stored"`
+}
+
+// RowResult is the outcome of one imported row
+type RowResult struct {
+	Line        int          `json:"line"`
+	ExternalID  string       `json:"external_id,omitempty"` // athlete external ID, after merged accounts
+	EventID     string       `json:"event_id,omitempty"`    // results
+	Status      string       `json:"status"`
+	Differences []Difference `json:"differences,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// Reconciliation compares the file with the stored data
+type Reconciliation struct {
+	Stored        int      `json:"stored"`                   // rows whose entity was already stored
+	Differing     int      `json:"differing"`                // stored rows with differences
+	UnknownEvents []string `json:"unknown_events,omitempty"` // results of events never scraped
+	Unlinked      int      `json:"unlinked,omitempty"`       // results whose athlete is not stored
+	Fields        []string `json:"fields"`                   // model fields the columns were mapped to
+	Ignored       []string `json:"ignored,omitempty"`        // columns without a mapping
+}
+
+// Report summarizes an import
+type Report struct {
+	Kind           string         `json:"kind"`
+	Rows           int            `json:"rows"`
+	DryRun         bool           `json:"dry_run"`
+	Counts         map[string]int `json:"counts"` // rows by status
+	Reconciliation Reconciliation `json:"reconciliation"`
+	Results        []RowResult    `json:"results"`
+}
+
+// columns maps the headers of the legacy exports, and common variants, to
+// the fields read from them
+var columns = map[string]map[string]string{
+	KindAthletes: {
+		"athlete_id": "external_id", "external_id": "external_id", "smoothcomp_id": "external_id", "user_id": "external_id",
+		"name": "name", "full_name": "name", "athlete_name": "name",
+		"first_name": "first_name", "last_name": "last_name",
+		"country": "country", "country_code": "country", "nationality": "nationality",
+		"team": "academy", "academy": "academy", "club": "academy", "club_name": "academy",
+		"team_id": "academy_id", "academy_id": "academy_id", "club_id": "academy_id",
+		"belt": "belt", "belt_rank": "belt", "birth_year": "birth_year", "gender": "gender",
+		"wins": "wins", "total_wins": "wins", "losses": "losses", "total_losses": "losses",
+		"profile_url": "profile_url", "url": "profile_url",
+	},
+	KindResults: {
+		"event_id": "event_id", "event": "event_id", "event_name": "event_name",
+		"division": "division", "category": "division",
+		"placement": "placement", "place": "placement", "position": "placement", "rank": "placement",
+		"athlete_id": "athlete_id", "external_id": "athlete_id", "smoothcomp_id": "athlete_id",
+		"athlete_name": "athlete_name", "name": "athlete_name", "athlete": "athlete_name",
+		"team": "academy", "academy": "academy", "club": "academy",
+		"team_id": "academy_id", "academy_id": "academy_id", "club_id": "academy_id",
+	},
+}
+
+// required are the fields a file must have a column for
+var required = map[string][]string{
+	KindAthletes: {"external_id"},
+	KindResults:  {"event_id", "placement"},
+}
+
+// Row is one CSV record by field
+type Row struct {
+	Line   int
+	Fields map[string]string
+}
+
+func (r Row) get(field string) string {
+	return r.Fields[field]
+}
+
+// ParseMapping reads column overrides such as "Competitor:name,Club:academy"
+// (header:field, matched case-insensitively)
+func ParseMapping(raw string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		column, field, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(column) == "" || strings.TrimSpace(field) == "" {
+			return nil, fmt.Errorf("invalid mapping %q (expected column:field)", pair)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(column))] = strings.ToLower(strings.TrimSpace(field))
+	}
+	return mapping, nil
+}
+
+// ParseCSV reads a legacy export of kind. Headers are matched
+// case-insensitively against the known columns, with mapping taking
+// precedence; other columns are ignored and listed in the report.
+func ParseCSV(r io.Reader, kind string, mapping map[string]string) ([]Row, Reconciliation, error) {
+	var recon Reconciliation
+	known, ok := columns[kind]
+	if !ok {
+		return nil, recon, fmt.Errorf("unknown legacy file kind %q (athletes, results)", kind)
+	}
+	fields := map[string]bool{}
+	for _, field := range known {
+		fields[field] = true
+	}
+	for _, field := range mapping {
+		if !fields[field] {
+			return nil, recon, fmt.Errorf("mapping names unknown field %q", field)
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, recon, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, mapped := mapping[name]
+		if !mapped {
+			if field, ok = known[name]; !ok {
+				recon.Ignored = append(recon.Ignored, name)
+				continue
+			}
+		}
+		if _, dup := index[field]; !dup {
+			recon.Fields = append(recon.Fields, field)
+		} else if !mapped {
+			continue
+		}
+		index[field] = i
+	}
+	for _, field := range required[kind] {
+		if _, ok := index[field]; !ok {
+			return nil, recon, fmt.Errorf("CSV has no column for %s", field)
+		}
+	}
+	sort.Strings(recon.Fields)
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, recon, fmt.Errorf("error reading CSV: %w", err)
+		}
+		if len(rows) == MaxRows {
+			return nil, recon, fmt.Errorf("CSV has more than %d rows", MaxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line, Fields: map[string]string{}}
+		for field, i := range index {
+			if i < len(record) {
+				row.Fields[field] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, recon, nil
+}
+
+// Import stores the rows of a legacy file of kind. With dryRun nothing is
+// stored and the report shows what an import would do.
+func Import(kind string, rows []Row, recon Reconciliation, dryRun bool) (*Report, error) {
+	if len(rows) > MaxRows {
+		return nil, fmt.Errorf("import has more than %d rows", MaxRows)
+	}
+
+	report := &Report{
+		Kind:           kind,
+		Rows:           len(rows),
+		DryRun:         dryRun,
+		Counts:         map[string]int{},
+		Reconciliation: recon,
+		Results:        make([]RowResult, 0, len(rows)),
+	}
+
+	db := config.GetDB()
+	var err error
+	switch kind {
+	case KindAthletes:
+		err = importAthletes(db, rows, dryRun, report)
+	case KindResults:
+		err = importResults(db, rows, dryRun, report)
+	default:
+		err = fmt.Errorf("unknown legacy file kind %q (athletes, results)", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range report.Results {
+		report.Counts[result.Status]++
+		if len(result.Differences) > 0 {
+			report.Reconciliation.Differing++
+		}
+	}
+
+	logger.Info("Legacy data imported",
+		zap.String("kind", kind),
+		zap.Int("rows", report.Rows),
+		zap.Int("created", report.Counts[StatusCreated]),
+		zap.Int("filled", report.Counts[StatusFilled]),
+		zap.Int("unchanged", report.Counts[StatusUnchanged]),
+		zap.Int("invalid", report.Counts[StatusInvalid]),
+		zap.Bool("dry_run", dryRun))
+
+	return report, nil
+}
+
+// importAthletes creates the athletes not stored yet and fills the empty
+// fields of stored ones; scraped values are never replaced
+func importAthletes(db *gorm.DB, rows []Row, dryRun bool, report *Report) error {
+	seen := map[string]bool{}
+	created := false
+
+	for _, row := range rows {
+		result := RowResult{Line: row.Line, ExternalID: scraper.ResolveAthleteID(db, row.get("external_id"))}
+		switch {
+		case result.ExternalID == "":
+			result.Status = StatusInvalid
+			result.Error = "external_id is required"
+		case seen[result.ExternalID]:
+			result.Status = StatusDuplicate
+		case skipped(db, result.ExternalID):
+			result.Status = StatusSkipped
+		}
+		if result.Status != "" {
+			report.Results = append(report.Results, result)
+			continue
+		}
+		seen[result.ExternalID] = true
+
+		legacy := legacyAthlete(db, row)
+		legacy.ExternalID = result.ExternalID
+
+		var stored models.Athlete
+		if db.Where("external_id = ?", result.ExternalID).Limit(1).Find(&stored).RowsAffected == 0 {
+			if legacy.FullName == "" {
+				result.Status = StatusInvalid
+				result.Error = "name is required for athletes not stored yet"
+				report.Results = append(report.Results, result)
+				continue
+			}
+			if !dryRun {
+				if err := db.Create(&legacy).Error; err != nil {
+					return fmt.Errorf("error creating athlete %s: %w", result.ExternalID, err)
+				}
+				created = true
+			}
+			result.Status = StatusCreated
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		report.Reconciliation.Stored++
+		updates, differences := reconcileAthlete(legacy, stored)
+		result.Differences = differences
+		result.Status = StatusUnchanged
+		if len(updates) > 0 {
+			result.Status = StatusFilled
+			if !dryRun {
+				if err := db.Model(&models.Athlete{}).Where("id = ?", stored.ID).UpdateColumns(updates).Error; err != nil {
+					return fmt.Errorf("error updating athlete %s: %w", result.ExternalID, err)
+				}
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if created {
+		return scraper.BackfillSlugs()
+	}
+	return nil
+}
+
+// legacyAthlete builds the athlete described by a row
+func legacyAthlete(db *gorm.DB, row Row) models.Athlete {
+	athlete := models.Athlete{
+		FullName:        strings.Join(strings.Fields(row.get("name")), " "),
+		CountryCode:     strings.ToUpper(row.get("country")),
+		Nationality:     row.get("nationality"),
+		BeltRank:        row.get("belt"),
+		ProfileURL:      row.get("profile_url"),
+		AffiliationName: row.get("academy"),
+		ScrapedAt:       time.Now(),
+	}
+	if athlete.FullName == "" {
+		athlete.FullName = strings.TrimSpace(row.get("first_name") + " " + row.get("last_name"))
+	}
+	athlete.FirstName, athlete.LastName = names.Parse(athlete.FullName, row.get("first_name"), row.get("last_name"))
+	athlete.Gender, _ = models.ParseGender(row.get("gender"))
+	athlete.BirthYear, _ = strconv.Atoi(row.get("birth_year"))
+	athlete.TotalWins, _ = strconv.Atoi(row.get("wins"))
+	athlete.TotalLosses, _ = strconv.Atoi(row.get("losses"))
+	if academyID := row.get("academy_id"); academyID != "" {
+		var count int64
+		db.Model(&models.Academy{}).Where("external_id = ?", academyID).Count(&count)
+		if count > 0 {
+			athlete.AcademyExternalID = academyID
+		}
+	}
+	return athlete
+}
+
+// reconcileAthlete returns the columns the stored athlete lacks and the
+// legacy values that disagree with stored ones
+func reconcileAthlete(legacy, stored models.Athlete) (map[string]interface{}, []Difference) {
+	updates := map[string]interface{}{}
+	var differences []Difference
+
+	text := func(column, field, legacyValue, storedValue string) {
+		switch {
+		case legacyValue == "":
+		case storedValue == "":
+			updates[column] = legacyValue
+		case !strings.EqualFold(legacyValue, storedValue):
+			differences = append(differences, Difference{Field: field, Legacy: legacyValue, Stored: storedValue})
+		}
+	}
+	number := func(column, field string, legacyValue, storedValue int) {
+		switch {
+		case legacyValue == 0:
+		case storedValue == 0:
+			updates[column] = legacyValue
+		case legacyValue != storedValue:
+			differences = append(differences, Difference{
+				Field: field, Legacy: strconv.Itoa(legacyValue), Stored: strconv.Itoa(storedValue),
+			})
+		}
+	}
+
+	text("full_name", "name", legacy.FullName, stored.FullName)
+	if _, ok := updates["full_name"]; ok {
+		updates["first_name"], updates["last_name"] = legacy.FirstName, legacy.LastName
+	}
+	text("country_code", "country", legacy.CountryCode, stored.CountryCode)
+	text("nationality", "nationality", legacy.Nationality, stored.Nationality)
+	text("belt_rank", "belt", legacy.BeltRank, stored.BeltRank)
+	text("gender", "gender", string(legacy.Gender), string(stored.Gender))
+	text("academy_external_id", "academy_id", legacy.AcademyExternalID, stored.AcademyExternalID)
+	text("affiliation_name", "academy", legacy.AffiliationName, stored.AffiliationName)
+	text("profile_url", "profile_url", legacy.ProfileURL, stored.ProfileURL)
+	number("birth_year", "birth_year", legacy.BirthYear, stored.BirthYear)
+	// Wins and losses keep growing after the legacy export, so only a
+	// legacy count above the stored one is a difference
+	counter := func(column, field string, legacyValue, storedValue int) {
+		if legacyValue > storedValue && storedValue > 0 {
+			differences = append(differences, Difference{
+				Field: field, Legacy: strconv.Itoa(legacyValue), Stored: strconv.Itoa(storedValue),
+			})
+			return
+		}
+		number(column, field, legacyValue, max(storedValue, legacyValue))
+	}
+	counter("total_wins", "wins", legacy.TotalWins, stored.TotalWins)
+	counter("total_losses", "losses", legacy.TotalLosses, stored.TotalLosses)
+
+	return updates, differences
+}
+
+// importResults stores the podium placements not stored yet. A placement is
+// identified by event, division and athlete (external ID, or name for
+// athletes without one).
+func importResults(db *gorm.DB, rows []Row, dryRun bool, report *Report) error {
+	seen := map[string]bool{}
+	events := map[string]bool{}
+	unknownEvents := map[string]bool{}
+	now := time.Now()
+
+	for _, row := range rows {
+		result := RowResult{
+			Line:       row.Line,
+			EventID:    row.get("event_id"),
+			ExternalID: scraper.ResolveAthleteID(db, row.get("athlete_id")),
+		}
+		placement, _ := strconv.Atoi(strings.TrimLeft(strings.ToLower(row.get("placement")), "#"))
+		athleteName := strings.Join(strings.Fields(row.get("athlete_name")), " ")
+		division := strings.Join(strings.Fields(row.get("division")), " ")
+
+		switch {
+		case result.EventID == "":
+			result.Status, result.Error = StatusInvalid, "event_id is required"
+		case placement < 1 || placement > 4:
+			result.Status, result.Error = StatusInvalid, "placement must be 1 to 4"
+		case result.ExternalID == "" && athleteName == "":
+			result.Status, result.Error = StatusInvalid, "athlete_id or athlete_name is required"
+		case scraper.IsBlocked(models.BlockedEvent, result.EventID) || (result.ExternalID != "" && skipped(db, result.ExternalID)):
+			result.Status = StatusSkipped
+		}
+		if result.Status != "" {
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		athleteKey := result.ExternalID
+		if athleteKey == "" {
+			athleteKey = "name:" + strings.ToLower(athleteName)
+		}
+		key := strings.Join([]string{result.EventID, strings.ToLower(division), athleteKey}, "|")
+		if seen[key] {
+			result.Status = StatusDuplicate
+			report.Results = append(report.Results, result)
+			continue
+		}
+		seen[key] = true
+
+		var event models.Event
+		if db.Where("external_id = ?", result.EventID).Limit(1).Find(&event).RowsAffected == 0 {
+			unknownEvents[result.EventID] = true
+		}
+
+		legacy := models.EventResult{
+			EventID:           result.EventID,
+			EventName:         row.get("event_name"),
+			Division:          division,
+			Placement:         placement,
+			Medal:             models.MedalForPlacement(placement),
+			AthleteExternalID: result.ExternalID,
+			AthleteName:       athleteName,
+			AcademyExternalID: row.get("academy_id"),
+			AcademyName:       row.get("academy"),
+			ScrapedAt:         now,
+		}
+		if event.Name != "" {
+			legacy.EventName = event.Name
+		}
+		if legacy.AthleteExternalID != "" {
+			var athlete models.Athlete
+			if db.Select("id").Where("external_id = ?", legacy.AthleteExternalID).Limit(1).Find(&athlete).RowsAffected > 0 {
+				legacy.AthleteID = uint(athlete.ID)
+			}
+		}
+		if legacy.AthleteID == 0 {
+			report.Reconciliation.Unlinked++
+		}
+
+		query := db.Where("event_id = ? AND LOWER(division) = ?", legacy.EventID, strings.ToLower(division))
+		if legacy.AthleteExternalID != "" {
+			query = query.Where("athlete_external_id = ?", legacy.AthleteExternalID)
+		} else {
+			query = query.Where("LOWER(athlete_name) = ?", strings.ToLower(athleteName))
+		}
+		var stored models.EventResult
+		if query.Limit(1).Find(&stored).RowsAffected > 0 {
+			report.Reconciliation.Stored++
+			result.Status = StatusUnchanged
+			if stored.Placement != placement {
+				result.Differences = []Difference{{
+					Field: "placement", Legacy: strconv.Itoa(placement), Stored: strconv.Itoa(stored.Placement),
+				}}
+			}
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		if !dryRun {
+			if err := db.Create(&legacy).Error; err != nil {
+				return fmt.Errorf("error creating result of event %s: %w", legacy.EventID, err)
+			}
+			events[legacy.EventID] = true
+		}
+		result.Status = StatusCreated
+		report.Results = append(report.Results, result)
+	}
+
+	for eventID := range unknownEvents {
+		report.Reconciliation.UnknownEvents = append(report.Reconciliation.UnknownEvents, eventID)
+	}
+	sort.Strings(report.Reconciliation.UnknownEvents)
+
+	return refreshAcademyStats(events)
+}
+
+// refreshAcademyStats recomputes the academies credited with the imported
+// results of events
+func refreshAcademyStats(events map[string]bool) error {
+	var academyIDs []string
+	for eventID := range events {
+		ids, err := academystats.EventAcademies(eventID)
+		if err != nil {
+			return err
+		}
+		academyIDs = append(academyIDs, ids...)
+	}
+	if len(academyIDs) == 0 {
+		return nil
+	}
+	_, err := academystats.Recompute(academyIDs...)
+	return err
+}
+
+// skipped reports whether an athlete must not be imported: blocklisted, or
+// with its personal data removed
+func skipped(db *gorm.DB, externalID string) bool {
+	if scraper.IsBlocked(models.BlockedAthlete, externalID) {
+		return true
+	}
+	var count int64
+	db.Model(&models.SuppressedAthlete{}).Where("external_id = ?", externalID).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,128 @@
+package live
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// flushBatch is how many score rows one INSERT carries
+	flushBatch = 200
+	// maxPending caps the rows kept while the database refuses them; the
+	// oldest are dropped first
+	maxPending = 20000
+)
+
+// scoreBuffer holds recorded scores in memory and writes them every
+// LIVE_FLUSH_MS in a single transaction, so a busy tournament costs one
+// write per interval instead of one per scoreboard change and SQLite stays
+// free for the read API. Until written, the rows are served from here.
+type scoreBuffer struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []models.LiveScore
+	removed int // rows taken off the front of pending so far, written or dropped
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newScoreBuffer(interval time.Duration) *scoreBuffer {
+	b := &scoreBuffer{
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// add queues a score row for the next flush
+func (b *scoreBuffer) add(score models.LiveScore) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) >= maxPending {
+		logger.Warn("Live score buffer full, dropping the oldest row",
+			zap.String("event_id", b.pending[0].EventID),
+			zap.String("match_id", b.pending[0].MatchID))
+		b.pending = b.pending[1:]
+		b.removed++
+	}
+	b.pending = append(b.pending, score)
+}
+
+// unflushed returns the rows of an event not written yet, oldest first;
+// an empty matchID returns those of every match
+func (b *scoreBuffer) unflushed(eventID, matchID string) []models.LiveScore {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var rows []models.LiveScore
+	for _, score := range b.pending {
+		if score.EventID == eventID && (matchID == "" || score.MatchID == matchID) {
+			rows = append(rows, score)
+		}
+	}
+	return rows
+}
+
+func (b *scoreBuffer) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.flush()
+		case <-b.stop:
+			b.flush()
+			return
+		}
+	}
+}
+
+// flush writes the pending rows. They stay in the buffer until committed,
+// so readers never miss them, and are kept for the next try on failure.
+func (b *scoreBuffer) flush() {
+	b.mu.Lock()
+	rows := append([]models.LiveScore(nil), b.pending...)
+	from := b.removed
+	b.mu.Unlock()
+	if len(rows) == 0 {
+		return
+	}
+
+	start := time.Now()
+	if err := config.GetDB().CreateInBatches(rows, flushBatch).Error; err != nil {
+		logger.Error("Failed to write live scores, retrying on the next flush",
+			zap.Int("rows", len(rows)),
+			zap.Error(err))
+		return
+	}
+
+	b.mu.Lock()
+	// Rows dropped meanwhile were among those written
+	if written := from + len(rows) - b.removed; written > 0 {
+		b.pending = b.pending[written:]
+		b.removed += written
+	}
+	b.mu.Unlock()
+
+	logger.Debug("Live scores written",
+		zap.Int("rows", len(rows)),
+		zap.Duration("duration", time.Since(start)))
+}
+
+// close writes what is left and stops the flushes
+func (b *scoreBuffer) close() {
+	close(b.stop)
+	<-b.done
+}
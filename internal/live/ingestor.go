@@ -0,0 +1,389 @@
+// Package live records the scores of in-progress matches from the live
+// scoreboard streams Smoothcomp pushes over WebSocket during events.
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+var (
+	// ErrDisabled is returned when LIVE_STREAM_URL is not set
+	ErrDisabled = errors.New("live ingestion is disabled (LIVE_STREAM_URL is not set)")
+	// ErrAlreadyStreaming is returned when the event already has a stream
+	ErrAlreadyStreaming = errors.New("event is already being streamed")
+)
+
+const (
+	maxReconnectDelay = 5 * time.Minute
+	readTimeout       = 2 * time.Minute // a silent stream is reconnected
+	maxMessageBytes   = 1 << 20
+)
+
+// StreamStatus describes the stream of one event
+type StreamStatus struct {
+	EventID       string     `json:"event_id"`
+	URL           string     `json:"url"`
+	Connected     bool       `json:"connected"`
+	StartedAt     time.Time  `json:"started_at"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	Messages      int64      `json:"messages"`
+	Recorded      int64      `json:"recorded"` // score rows recorded, written every LIVE_FLUSH_MS
+	Reconnects    int        `json:"reconnects"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Ingestor keeps one scoreboard stream per event
+type Ingestor struct {
+	config   *config.Config
+	notifier *notify.Dispatcher
+	privacy  *privacy.Policy
+	buffer   *scoreBuffer
+	closed   sync.Once
+
+	mu      sync.Mutex
+	streams map[string]*stream
+}
+
+// NewIngestor creates an ingestor with no streams running
+func NewIngestor(cfg *config.Config) *Ingestor {
+	return &Ingestor{
+		config:   cfg,
+		notifier: notify.NewDispatcher(cfg),
+		privacy:  privacy.NewPolicy(cfg),
+		buffer:   newScoreBuffer(cfg.Live.FlushInterval),
+		streams:  map[string]*stream{},
+	}
+}
+
+// StartConfigured starts the streams of LIVE_EVENT_IDS
+func (i *Ingestor) StartConfigured() {
+	for _, eventID := range i.config.Live.EventIDs {
+		if err := i.Start(eventID); err != nil {
+			logger.Error("Failed to start live stream", zap.String("event_id", eventID), zap.Error(err))
+		}
+	}
+}
+
+// Start connects to the scoreboard stream of an event and records its
+// scores until Stop. Dropped connections are retried with a growing delay.
+func (i *Ingestor) Start(eventID string) error {
+	if i.config.Live.StreamURL == "" {
+		return ErrDisabled
+	}
+	streamURL, err := i.streamURL(eventID)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.streams[eventID]; ok {
+		return ErrAlreadyStreaming
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	st := &stream{
+		config:   i.config,
+		notifier: i.notifier,
+		privacy:  i.privacy,
+		buffer:   i.buffer,
+		eventID:  eventID,
+		url:      streamURL,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		last:     map[string]models.LiveScore{},
+		sides:    map[string][2]uint{},
+		started:  map[string]time.Time{},
+		status:   StreamStatus{EventID: eventID, URL: redact(streamURL), StartedAt: time.Now()},
+	}
+	i.streams[eventID] = st
+	go st.run(ctx)
+
+	logger.Info("Live stream started", zap.String("event_id", eventID), zap.String("url", redact(streamURL)))
+	return nil
+}
+
+// Stop closes the stream of an event. Returns false when it had none.
+func (i *Ingestor) Stop(eventID string) bool {
+	i.mu.Lock()
+	st, ok := i.streams[eventID]
+	delete(i.streams, eventID)
+	i.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	st.cancel()
+	<-st.done
+	logger.Info("Live stream stopped", zap.String("event_id", eventID))
+	return true
+}
+
+// StopAll closes every stream and writes the buffered scores, on shutdown
+func (i *Ingestor) StopAll() {
+	i.mu.Lock()
+	ids := make([]string, 0, len(i.streams))
+	for id := range i.streams {
+		ids = append(ids, id)
+	}
+	i.mu.Unlock()
+
+	for _, id := range ids {
+		i.Stop(id)
+	}
+	i.closed.Do(i.buffer.close)
+}
+
+// Unflushed returns the recorded scores of an event that are not in the
+// database yet, oldest first; an empty matchID returns every match
+func (i *Ingestor) Unflushed(eventID, matchID string) []models.LiveScore {
+	return i.buffer.unflushed(eventID, matchID)
+}
+
+// Streams reports the running streams, by event ID
+func (i *Ingestor) Streams() []StreamStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	out := make([]StreamStatus, 0, len(i.streams))
+	for _, st := range i.streams {
+		out = append(out, st.snapshot())
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].EventID < out[b].EventID })
+	return out
+}
+
+// streamURL fills LIVE_STREAM_URL for an event: {event} is its ID and
+// {host} the Smoothcomp host of its stored page (smoothcomp.com otherwise)
+func (i *Ingestor) streamURL(eventID string) (string, error) {
+	host := "smoothcomp.com"
+	var event models.Event
+	config.GetDB().Select("event_url").Where("external_id = ?", eventID).Limit(1).Find(&event)
+	if subdomain := scraper.ExtractSubdomainFromURL(event.EventURL); subdomain != "" {
+		host = subdomain
+	}
+
+	raw := strings.NewReplacer("{host}", host, "{event}", url.PathEscape(eventID)).Replace(i.config.Live.StreamURL)
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream URL: %w", err)
+	}
+	if err := scraper.CheckAllowedHost(i.config, parsed); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// stream is the connection loop of one event
+type stream struct {
+	config   *config.Config
+	notifier *notify.Dispatcher
+	privacy  *privacy.Policy
+	buffer   *scoreBuffer
+	eventID  string
+	url      string
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	last    map[string]models.LiveScore // last recorded row per match
+	sides   map[string][2]uint          // athlete IDs per match, from stored matches
+	started map[string]time.Time        // first row per match, for the pace of each mat
+
+	mu     sync.Mutex
+	status StreamStatus
+}
+
+func (st *stream) run(ctx context.Context) {
+	defer close(st.done)
+
+	delay := st.config.Live.ReconnectDelay
+	for {
+		received, err := st.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			delay = st.config.Live.ReconnectDelay
+		}
+
+		st.mu.Lock()
+		st.status.Connected = false
+		st.status.Reconnects++
+		if err != nil {
+			st.status.LastError = err.Error()
+		}
+		st.mu.Unlock()
+		logger.Warn("Live stream dropped",
+			zap.String("event_id", st.eventID),
+			zap.Duration("retry_in", delay),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// connect reads the stream until it fails or ctx is cancelled, reporting
+// whether any message arrived
+func (st *stream) connect(ctx context.Context) (bool, error) {
+	parsed, err := url.Parse(st.url)
+	if err != nil {
+		return false, err
+	}
+	origin := "https://" + parsed.Host
+	wsConfig, err := websocket.NewConfig(st.url, origin)
+	if err != nil {
+		return false, err
+	}
+	wsConfig.Header.Set("User-Agent", st.config.Scraper.UserAgent)
+
+	conn, err := wsConfig.DialContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	conn.MaxPayloadBytes = maxMessageBytes
+
+	closed := make(chan struct{})
+	defer close(closed)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-closed:
+		}
+		conn.Close()
+	}()
+
+	st.mu.Lock()
+	st.status.Connected = true
+	st.status.LastError = ""
+	st.mu.Unlock()
+	logger.Info("Live stream connected", zap.String("event_id", st.eventID))
+
+	received := false
+	for {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			return received, err
+		}
+		received = true
+
+		now := time.Now()
+		st.mu.Lock()
+		st.status.Messages++
+		st.status.LastMessageAt = &now
+		st.mu.Unlock()
+
+		for _, score := range parseMessage(data) {
+			st.record(score, now)
+		}
+	}
+}
+
+// record stores a score when it differs from the last one of its match.
+// The clock alone does not count as a change, so a running match does not
+// write a row every second. Rows reach the database with the next flush of
+// the score buffer.
+func (st *stream) record(score models.LiveScore, at time.Time) {
+	score.EventID = st.eventID
+	score.RecordedAt = at
+
+	previous, seen := st.last[score.MatchID]
+	if seen && sameScore(previous, score) {
+		return
+	}
+
+	sides, ok := st.sides[score.MatchID]
+	if !ok {
+		sides = st.matchSides(score.MatchID)
+		st.sides[score.MatchID] = sides
+	}
+	score.AthleteAID, score.AthleteBID = sides[0], sides[1]
+	if suppressed(score.AthleteAID) {
+		score.AthleteAName = ""
+	}
+	if suppressed(score.AthleteBID) {
+		score.AthleteBName = ""
+	}
+
+	st.buffer.add(score)
+	st.last[score.MatchID] = score
+	if !seen {
+		st.started[score.MatchID] = at
+	}
+
+	st.mu.Lock()
+	st.status.Recorded++
+	st.mu.Unlock()
+
+	// Only a finish seen happening counts, not the state replayed when the
+	// stream (re)connects
+	if seen && !isFinished(previous.Status) && isFinished(score.Status) {
+		st.notifyProgression(score)
+	}
+}
+
+// matchSides links a streamed match to the athletes of the stored match
+// with the same external ID, when the brackets were scraped
+func (st *stream) matchSides(matchID string) [2]uint {
+	var match models.Match
+	config.GetDB().Select("athlete_a_id", "athlete_b_id").
+		Where("event_id = ? AND external_id = ?", st.eventID, matchID).
+		Limit(1).Find(&match)
+	return [2]uint{match.AthleteAID, match.AthleteBID}
+}
+
+func (st *stream) snapshot() StreamStatus {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.status
+}
+
+func sameScore(a, b models.LiveScore) bool {
+	return a.Status == b.Status && a.Winner == b.Winner && a.Mat == b.Mat &&
+		a.AthleteAName == b.AthleteAName && a.AthleteBName == b.AthleteBName &&
+		a.PointsA == b.PointsA && a.PointsB == b.PointsB &&
+		a.AdvantagesA == b.AdvantagesA && a.AdvantagesB == b.AdvantagesB &&
+		a.PenaltiesA == b.PenaltiesA && a.PenaltiesB == b.PenaltiesB
+}
+
+func suppressed(athleteID uint) bool {
+	if athleteID == 0 {
+		return false
+	}
+	var count int64
+	config.GetDB().Model(&models.SuppressedAthlete{}).Where("athlete_id = ?", athleteID).Count(&count)
+	return count > 0
+}
+
+// redact hides credentials a stream URL may carry in its query
+func redact(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = "REDACTED"
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,258 @@
+package live
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// Keys are compared lowercased and without "_" or "-", so "points_a",
+// "pointsA" and "points-a" are the same key.
+var (
+	matchIDKeys    = []string{"matchid", "fightid", "boutid"}
+	matKeys        = []string{"mat", "matname", "matid"}
+	statusKeys     = []string{"status", "state"}
+	clockKeys      = []string{"clock", "timeleft", "remaining", "remainingtime"}
+	nameKeys       = []string{"name", "fullname"}
+	pointsKeys     = []string{"points", "score"}
+	advantagesKeys = []string{"advantages", "adv"}
+	penaltiesKeys  = []string{"penalties", "pen"}
+	winnerKeys     = []string{"winner", "winnerside"}
+	sideWinnerKeys = []string{"winner", "iswinner", "won"}
+
+	// Nested competitor objects, as pairs of keys
+	sidePairs = [][2]string{
+		{"a", "b"},
+		{"competitor1", "competitor2"},
+		{"competitora", "competitorb"},
+		{"athletea", "athleteb"},
+		{"red", "blue"},
+	}
+)
+
+// parseMessage extracts the match scores in a stream message. Streams wrap
+// their payloads differently (a bare object, a list, an envelope such as
+// {"event": "score", "data": {...}} or a socket.io frame like 42["score",
+// {...}]), so every nested object carrying a match ID and a score is taken.
+func parseMessage(data []byte) []models.LiveScore {
+	data = bytes.TrimLeft(bytes.TrimSpace(data), "0123456789")
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil
+	}
+
+	var scores []models.LiveScore
+	collectScores(payload, &scores)
+	return scores
+}
+
+func collectScores(v any, scores *[]models.LiveScore) {
+	switch value := v.(type) {
+	case []any:
+		for _, item := range value {
+			collectScores(item, scores)
+		}
+	case map[string]any:
+		object := normalizeKeys(value)
+		if score, ok := scoreFromObject(object); ok {
+			*scores = append(*scores, score)
+			return
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			collectScores(object[key], scores)
+		}
+	}
+}
+
+// scoreFromObject reads a score from a normalized object, either from flat
+// keys (points_a, points_b) or from nested competitor objects
+func scoreFromObject(object map[string]any) (models.LiveScore, bool) {
+	matchID := stringValue(object, matchIDKeys...)
+	if matchID == "" {
+		return models.LiveScore{}, false
+	}
+
+	score := models.LiveScore{
+		MatchID:      matchID,
+		Mat:          stringValue(object, matKeys...),
+		Status:       stringValue(object, statusKeys...),
+		ClockSeconds: clockValue(object),
+	}
+
+	found := false
+	for _, pair := range sidePairs {
+		a, aOK := object[pair[0]].(map[string]any)
+		b, bOK := object[pair[1]].(map[string]any)
+		if aOK && bOK {
+			a, b = normalizeKeys(a), normalizeKeys(b)
+			score.AthleteAName, score.AthleteBName = sideName(a), sideName(b)
+			var okA, okB bool
+			score.PointsA, okA = intValue(a, pointsKeys...)
+			score.PointsB, okB = intValue(b, pointsKeys...)
+			found = okA || okB
+			score.AdvantagesA, _ = intValue(a, advantagesKeys...)
+			score.AdvantagesB, _ = intValue(b, advantagesKeys...)
+			score.PenaltiesA, _ = intValue(a, penaltiesKeys...)
+			score.PenaltiesB, _ = intValue(b, penaltiesKeys...)
+			if boolValue(a, sideWinnerKeys...) {
+				score.Winner = 1
+			} else if boolValue(b, sideWinnerKeys...) {
+				score.Winner = 2
+			}
+			break
+		}
+	}
+	if !found {
+		score.AthleteAName = stringValue(object, suffixed(append(nameKeys, "athletename", "competitorname"), "a")...)
+		score.AthleteBName = stringValue(object, suffixed(append(nameKeys, "athletename", "competitorname"), "b")...)
+		var okA, okB bool
+		score.PointsA, okA = intValue(object, suffixed(pointsKeys, "a")...)
+		score.PointsB, okB = intValue(object, suffixed(pointsKeys, "b")...)
+		found = okA || okB
+		score.AdvantagesA, _ = intValue(object, suffixed(advantagesKeys, "a")...)
+		score.AdvantagesB, _ = intValue(object, suffixed(advantagesKeys, "b")...)
+		score.PenaltiesA, _ = intValue(object, suffixed(penaltiesKeys, "a")...)
+		score.PenaltiesB, _ = intValue(object, suffixed(penaltiesKeys, "b")...)
+	}
+
+	if score.Winner == 0 {
+		score.Winner = winnerValue(object, score.AthleteAName, score.AthleteBName)
+	}
+
+	return score, found
+}
+
+// winnerSides maps the ways streams name the winning side to 1 (A) or 2 (B)
+var winnerSides = map[string]int{
+	"1": 1, "a": 1, "red": 1, "competitor1": 1, "competitora": 1, "athletea": 1,
+	"2": 2, "b": 2, "blue": 2, "competitor2": 2, "competitorb": 2, "athleteb": 2,
+}
+
+// winnerValue reads the winning side from a winner key holding a side
+// ("a", "red", 1) or the winner's name
+func winnerValue(object map[string]any, nameA, nameB string) int {
+	winner := stringValue(object, winnerKeys...)
+	if winner == "" {
+		return 0
+	}
+	if side, ok := winnerSides[strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(winner))]; ok {
+		return side
+	}
+	switch {
+	case nameA != "" && strings.EqualFold(winner, nameA):
+		return 1
+	case nameB != "" && strings.EqualFold(winner, nameB):
+		return 2
+	}
+	return 0
+}
+
+func sideName(side map[string]any) string {
+	if name := stringValue(side, nameKeys...); name != "" {
+		return name
+	}
+	return strings.TrimSpace(stringValue(side, "firstname") + " " + stringValue(side, "lastname"))
+}
+
+// suffixed returns keys with the side suffix, and prefixed the way
+// "athlete_a_name" spells it
+func suffixed(keys []string, side string) []string {
+	out := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		out = append(out, key+side)
+		if strings.HasPrefix(key, "athlete") || strings.HasPrefix(key, "competitor") {
+			prefix := strings.TrimSuffix(key, "name")
+			out = append(out, prefix+side+"name")
+		}
+	}
+	return out
+}
+
+func normalizeKeys(object map[string]any) map[string]any {
+	out := make(map[string]any, len(object))
+	for key, value := range object {
+		key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+		out[key] = value
+	}
+	return out
+}
+
+func stringValue(object map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch value := object[key].(type) {
+		case string:
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		case json.Number:
+			return value.String()
+		}
+	}
+	return ""
+}
+
+func intValue(object map[string]any, keys ...string) (int, bool) {
+	for _, key := range keys {
+		switch value := object[key].(type) {
+		case json.Number:
+			if n, err := value.Float64(); err == nil {
+				return int(n), true
+			}
+		case string:
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func boolValue(object map[string]any, keys ...string) bool {
+	for _, key := range keys {
+		switch value := object[key].(type) {
+		case bool:
+			if value {
+				return true
+			}
+		case json.Number:
+			if value.String() == "1" {
+				return true
+			}
+		case string:
+			if strings.EqualFold(strings.TrimSpace(value), "true") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clockValue reads the time left as seconds, from a number or "m:ss"
+func clockValue(object map[string]any) int {
+	if seconds, ok := intValue(object, clockKeys...); ok {
+		return seconds
+	}
+	clock := stringValue(object, clockKeys...)
+	minutes, seconds, ok := strings.Cut(clock, ":")
+	if !ok {
+		return 0
+	}
+	m, errM := strconv.Atoi(minutes)
+	s, errS := strconv.Atoi(seconds)
+	if errM != nil || errS != nil {
+		return 0
+	}
+	return m*60 + s
+}
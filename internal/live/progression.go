@@ -0,0 +1,273 @@
+package live
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/internal/privacy"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// finishedStatuses are the stream statuses of a match that has ended
+var finishedStatuses = map[string]bool{
+	"finished": true, "completed": true, "complete": true, "ended": true, "done": true, "closed": true,
+}
+
+func isFinished(status string) bool {
+	return finishedStatuses[strings.ToLower(strings.TrimSpace(status))]
+}
+
+// semifinalPattern matches the rounds whose winners go to the final
+var semifinalPattern = regexp.MustCompile(`(?i)semi|\b1/2\b`)
+
+// finalPattern matches final rounds, not semi or quarter finals
+var finalPattern = regexp.MustCompile(`(?i)^\s*(the\s+)?finals?\s*$|^\s*final\b`)
+
+// paceSamples caps the match starts averaged into a mat's pace
+const paceSamples = 10
+
+// NextMatch is where and when a winner is expected to fight next
+type NextMatch struct {
+	MatchID     string    `json:"match_id,omitempty"` // stored match, when the bracket already has it
+	Round       string    `json:"round,omitempty"`
+	Mat         string    `json:"mat,omitempty"`
+	Opponent    string    `json:"opponent,omitempty"` // empty while the other side is undecided
+	OpponentID  uint      `json:"opponent_id,omitempty"`
+	EstimatedAt time.Time `json:"estimated_at"`
+	Scheduled   bool      `json:"scheduled"` // EstimatedAt is the bracket's start time, not a guess from the mat pace
+}
+
+// notifyProgression tells the watched athletes of a finished match whether
+// they won or lost and, for winners, who and when they fight next
+func (st *stream) notifyProgression(score models.LiveScore) {
+	if !st.notifier.Enabled() {
+		return
+	}
+	winner := liveWinner(score)
+	if winner == 0 {
+		logger.Debug("Finished live match without a winner",
+			zap.String("event_id", st.eventID),
+			zap.String("match_id", score.MatchID))
+		return
+	}
+
+	var match models.Match
+	config.GetDB().Where("event_id = ? AND external_id = ?", st.eventID, score.MatchID).Limit(1).Find(&match)
+
+	sides := [2]struct {
+		id   uint
+		name string
+	}{
+		{score.AthleteAID, firstNonEmpty(score.AthleteAName, match.AthleteAName)},
+		{score.AthleteBID, firstNonEmpty(score.AthleteBName, match.AthleteBName)},
+	}
+	for i, side := range sides {
+		athlete := scraper.WatchedAthlete(st.config, side.id)
+		if athlete == nil {
+			continue
+		}
+		opponent := sides[1-i]
+		opponentName := st.publicName(opponent.id, opponent.name)
+		won := winner == i+1
+
+		data := map[string]interface{}{
+			"athlete_id":          athlete.ID,
+			"athlete_external_id": athlete.ExternalID,
+			"athlete_name":        athlete.FullName,
+			"event_id":            st.eventID,
+			"match_id":            score.MatchID,
+			"round":               match.Round,
+			"category":            match.Category,
+			"mat":                 firstNonEmpty(score.Mat, match.Mat),
+			"opponent":            opponentName,
+			"opponent_id":         opponent.id,
+			"won":                 won,
+			"score":               scoreLine(score, i),
+		}
+		where := matchLabel(match)
+
+		if !won {
+			logger.Info("Watched athlete lost live match",
+				zap.String("athlete_id", athlete.ExternalID),
+				zap.String("match_id", score.MatchID))
+			// Delivered aside so a slow webhook does not stall the stream
+			go st.notifier.Publish(notify.Notification{
+				Kind:  "live_match_lost",
+				Title: fmt.Sprintf("%s lost to %s", athlete.FullName, orTBD(opponentName)),
+				Text:  fmt.Sprintf("%s lost to %s %s (%s)", athlete.FullName, orTBD(opponentName), where, scoreLine(score, i)),
+				Data:  data,
+			})
+			continue
+		}
+
+		next := st.nextMatch(match, side.id, firstNonEmpty(score.Mat, match.Mat), score.RecordedAt)
+		data["next_match"] = next
+		final := semifinalPattern.MatchString(match.Round) || finalPattern.MatchString(next.Round)
+		data["final"] = final
+
+		kind, title := "live_match_won", fmt.Sprintf("%s beat %s", athlete.FullName, orTBD(opponentName))
+		if final {
+			kind, title = "live_final_reached", fmt.Sprintf("%s is in the final", athlete.FullName)
+		}
+		logger.Info("Watched athlete won live match",
+			zap.String("athlete_id", athlete.ExternalID),
+			zap.String("match_id", score.MatchID),
+			zap.Bool("final", final))
+		go st.notifier.Publish(notify.Notification{
+			Kind:  kind,
+			Title: title,
+			Text: fmt.Sprintf("%s beat %s %s (%s). Next: %s",
+				athlete.FullName, orTBD(opponentName), where, scoreLine(score, i), next.describe()),
+			Data: data,
+		})
+	}
+}
+
+// nextMatch finds the winner's next bout in the stored bracket and when it
+// should start: its scheduled time when the bracket has one, otherwise the
+// mat's pace after the match that just ended
+func (st *stream) nextMatch(match models.Match, athleteID uint, mat string, finishedAt time.Time) NextMatch {
+	next := NextMatch{Mat: mat}
+
+	if match.DivisionID != "" && athleteID != 0 {
+		var stored models.Match
+		config.GetDB().
+			Where("event_id = ? AND division_id = ? AND winner_id = 0 AND external_id <> ? AND (athlete_a_id = ? OR athlete_b_id = ?)",
+				st.eventID, match.DivisionID, match.ExternalID, athleteID, athleteID).
+			Order("id").Limit(1).Find(&stored)
+		if stored.ID != 0 {
+			next.MatchID = stored.ExternalID
+			next.Round = stored.Round
+			if stored.Mat != "" {
+				next.Mat = stored.Mat
+			}
+			opponentID, opponentName := stored.AthleteBID, stored.AthleteBName
+			if stored.AthleteBID == athleteID {
+				opponentID, opponentName = stored.AthleteAID, stored.AthleteAName
+			}
+			next.OpponentID = opponentID
+			next.Opponent = st.publicName(opponentID, opponentName)
+			if stored.StartedAt != nil && stored.StartedAt.After(finishedAt) {
+				next.EstimatedAt = *stored.StartedAt
+				next.Scheduled = true
+				return next
+			}
+		}
+	}
+
+	next.EstimatedAt = finishedAt.Add(st.matPace(next.Mat)).Truncate(time.Minute)
+	return next
+}
+
+// matPace is the average time between match starts on a mat as seen by the
+// stream, LIVE_MATCH_SLOT_MINUTES until it has seen two matches there
+func (st *stream) matPace(mat string) time.Duration {
+	var starts []time.Time
+	for matchID, started := range st.started {
+		if st.last[matchID].Mat == mat {
+			starts = append(starts, started)
+		}
+	}
+	if len(starts) < 2 {
+		return st.config.Live.MatchSlot
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	if len(starts) > paceSamples {
+		starts = starts[len(starts)-paceSamples:]
+	}
+	return starts[len(starts)-1].Sub(starts[0]) / time.Duration(len(starts)-1)
+}
+
+// publicName hides the name of a minor opponent, as the API does
+func (st *stream) publicName(athleteID uint, name string) string {
+	if athleteID != 0 && st.privacy.MinorIDs([]uint{athleteID})[athleteID] {
+		return privacy.Initials(name)
+	}
+	return name
+}
+
+func (n NextMatch) describe() string {
+	parts := []string{"vs " + orTBD(n.Opponent)}
+	if n.Round != "" {
+		parts = append(parts, "("+n.Round+")")
+	}
+	if n.Mat != "" {
+		parts = append(parts, "on "+n.Mat)
+	}
+	at := n.EstimatedAt.Format("15:04")
+	if n.Scheduled {
+		parts = append(parts, "at "+at)
+	} else {
+		parts = append(parts, "around "+at)
+	}
+	return strings.Join(parts, " ")
+}
+
+// liveWinner returns the winning side of a finished match: the one the
+// stream names, else the one ahead on points, advantages and then fewer
+// penalties. 0 when tied.
+func liveWinner(score models.LiveScore) int {
+	if score.Winner == 1 || score.Winner == 2 {
+		return score.Winner
+	}
+	for _, diff := range []int{
+		score.PointsA - score.PointsB,
+		score.AdvantagesA - score.AdvantagesB,
+		score.PenaltiesB - score.PenaltiesA,
+	} {
+		if diff > 0 {
+			return 1
+		}
+		if diff < 0 {
+			return 2
+		}
+	}
+	return 0
+}
+
+// scoreLine formats the score from the point of view of side i (0 or 1)
+func scoreLine(score models.LiveScore, i int) string {
+	points := [2]int{score.PointsA, score.PointsB}
+	advantages := [2]int{score.AdvantagesA, score.AdvantagesB}
+	penalties := [2]int{score.PenaltiesA, score.PenaltiesB}
+	return fmt.Sprintf("%d-%d, advantages %d-%d, penalties %d-%d",
+		points[i], points[1-i], advantages[i], advantages[1-i], penalties[i], penalties[1-i])
+}
+
+func matchLabel(match models.Match) string {
+	var parts []string
+	if match.Round != "" {
+		parts = append(parts, "in "+match.Round)
+	}
+	if match.Category != "" {
+		parts = append(parts, "of "+match.Category)
+	}
+	if len(parts) == 0 {
+		return "in a live match"
+	}
+	return strings.Join(parts, " ")
+}
+
+func orTBD(name string) string {
+	if name == "" {
+		return "TBD"
+	}
+	return name
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+// Package maintenance recognizes Smoothcomp maintenance pages and stops the
+// job that hit one, so it can be postponed instead of failing every request
+// of the maintenance window.
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrActive is the cause of jobs stopped by a maintenance page
+var ErrActive = errors.New("smoothcomp is under maintenance")
+
+// StatusPostponed is the status of jobs stopped by a maintenance page
+const StatusPostponed = "postponed"
+
+// maxPageBytes bounds how much of a 503 body is inspected
+const maxPageBytes = 64 << 10
+
+// markers are texts of the maintenance page ("php artisan down" and the
+// localized banners)
+var markers = [][]byte{
+	[]byte("maintenance"),
+	[]byte("be right back"),
+	[]byte("mantenimiento"),
+	[]byte("manutenção"),
+}
+
+// Detect reports whether resp is a maintenance page: a 503 that names
+// maintenance or announces when to come back. The body stays readable.
+func Detect(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	if resp.Header.Get("Retry-After") != "" {
+		return true
+	}
+	if resp.Body == nil {
+		return false
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	head = bytes.ToLower(head)
+	for _, marker := range markers {
+		if bytes.Contains(head, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+type signalKey struct{}
+
+// WithSignal returns a ctx through which Signal stops a job with cancel
+func WithSignal(ctx context.Context, cancel context.CancelCauseFunc) context.Context {
+	return context.WithValue(ctx, signalKey{}, cancel)
+}
+
+// Signal stops the job running with ctx, if any, with ErrActive
+func Signal(ctx context.Context) {
+	if cancel, ok := ctx.Value(signalKey{}).(context.CancelCauseFunc); ok {
+		cancel(ErrActive)
+	}
+}
+
+// StoppedStatus is the status of a job stopped by cause: postponed for a
+// maintenance page, cancelled otherwise
+func StoppedStatus(cause error) string {
+	if errors.Is(cause, ErrActive) {
+		return StatusPostponed
+	}
+	return "cancelled"
+}
@@ -0,0 +1,323 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Store fetches remote images and keeps them on disk addressed by content hash
+type Store struct {
+	config *config.MediaConfig
+	client *http.Client
+	agent  string
+}
+
+// maxMediaRedirects is how many redirects a media fetch follows
+const maxMediaRedirects = 5
+
+// NewStore creates a media store. Every redirect must stay on
+// MEDIA_ALLOWED_HOSTS, and connections to private, loopback or link-local
+// addresses are refused whatever the host name resolves to.
+func NewStore(cfg *config.Config) *Store {
+	s := &Store{
+		config: &cfg.Media,
+		agent:  cfg.Scraper.UserAgent,
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: refusePrivateAddress}
+	s.client = &http.Client{
+		Timeout: 20 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost: 4,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxMediaRedirects {
+				return errors.New("stopped after 5 redirects")
+			}
+			return s.checkSource(req.URL.String())
+		},
+	}
+	return s
+}
+
+// refusePrivateAddress rejects connections to addresses that are not on the
+// public internet, checked on the resolved IP so DNS cannot point around it
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("media address not allowed: %s", host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Get returns the cached asset for sourceURL, fetching it on first use
+func (s *Store) Get(sourceURL string) (*models.MediaAsset, error) {
+	db := config.GetDB()
+
+	var asset models.MediaAsset
+	err := db.Where("source_url = ?", sourceURL).First(&asset).Error
+	if err == nil {
+		if _, statErr := os.Stat(s.Path(asset.Hash)); statErr == nil {
+			return &asset, nil
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load media asset: %w", err)
+	}
+
+	return s.Fetch(sourceURL)
+}
+
+// Fetch downloads sourceURL and stores it, replacing any previous version
+func (s *Store) Fetch(sourceURL string) (*models.MediaAsset, error) {
+	if err := s.checkSource(sourceURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating media request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.agent)
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("media source returned status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("media source is not an image: %q", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.config.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading media: %w", err)
+	}
+	if int64(len(data)) > s.config.MaxBytes {
+		return nil, fmt.Errorf("media exceeds %d bytes", s.config.MaxBytes)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	path := s.Path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating media dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("error writing media: %w", err)
+	}
+
+	db := config.GetDB()
+	asset := models.MediaAsset{SourceURL: sourceURL}
+	db.Where("source_url = ?", sourceURL).First(&asset)
+
+	asset.Hash = hash
+	asset.ContentType = contentType
+	asset.Size = int64(len(data))
+	asset.FetchedAt = time.Now()
+
+	if err := db.Save(&asset).Error; err != nil {
+		return nil, fmt.Errorf("failed to save media asset: %w", err)
+	}
+
+	return &asset, nil
+}
+
+// Path returns the on-disk location of a content hash
+func (s *Store) Path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(s.config.CacheDir, hash)
+	}
+	return filepath.Join(s.config.CacheDir, hash[:2], hash)
+}
+
+// FindByHash returns the asset metadata of a stored content hash
+func (s *Store) FindByHash(hash string) (*models.MediaAsset, error) {
+	var asset models.MediaAsset
+	if err := config.GetDB().Where("hash = ?", hash).First(&asset).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// FlagURL returns the source URL of a country flag image
+func (s *Store) FlagURL(countryCode string) string {
+	return fmt.Sprintf(s.config.FlagURLTemplate, strings.ToLower(countryCode))
+}
+
+// BuildManifest lists academy logos and country flags already in the cache.
+// urlPrefix is prepended to each hash to build the stable asset URL.
+func (s *Store) BuildManifest(urlPrefix string) (*models.MediaManifest, error) {
+	sources, err := s.bundleSources()
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(sources))
+	for _, source := range sources {
+		urls = append(urls, source.SourceURL)
+	}
+
+	var assets []models.MediaAsset
+	if err := config.GetDB().Where("source_url IN ?", urls).Find(&assets).Error; err != nil {
+		return nil, fmt.Errorf("failed to load media assets: %w", err)
+	}
+	bySource := make(map[string]models.MediaAsset, len(assets))
+	for _, asset := range assets {
+		bySource[asset.SourceURL] = asset
+	}
+
+	manifest := &models.MediaManifest{
+		GeneratedAt: time.Now(),
+		Entries:     make([]models.MediaManifestEntry, 0, len(sources)),
+	}
+
+	version := sha256.New()
+	for _, source := range sources {
+		asset, ok := bySource[source.SourceURL]
+		if !ok {
+			manifest.Missing++
+			continue
+		}
+		source.Hash = asset.Hash
+		source.URL = urlPrefix + asset.Hash
+		source.ContentType = asset.ContentType
+		source.Size = asset.Size
+		manifest.Entries = append(manifest.Entries, source)
+		version.Write([]byte(source.Kind + source.Key + asset.Hash))
+	}
+	manifest.Version = hex.EncodeToString(version.Sum(nil))[:16]
+
+	return manifest, nil
+}
+
+// Bundle fetches every academy logo and country flag not yet cached
+func (s *Store) Bundle() (fetched int, failed int) {
+	sources, err := s.bundleSources()
+	if err != nil {
+		logger.Error("Failed to list media sources", zap.Error(err))
+		return 0, 0
+	}
+
+	for _, source := range sources {
+		if _, err := s.Get(source.SourceURL); err != nil {
+			logger.Warn("Failed to cache media",
+				zap.String("kind", source.Kind),
+				zap.String("key", source.Key),
+				zap.Error(err))
+			failed++
+			continue
+		}
+		fetched++
+	}
+
+	logger.Info("Media bundle refreshed",
+		zap.Int("cached", fetched),
+		zap.Int("failed", failed))
+
+	return fetched, failed
+}
+
+func (s *Store) bundleSources() ([]models.MediaManifestEntry, error) {
+	db := config.GetDB()
+	sources := make([]models.MediaManifestEntry, 0)
+
+	var academies []models.Academy
+	if err := db.Select("external_id, logo_url").Where("logo_url <> ''").Order("external_id").Find(&academies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load academy logos: %w", err)
+	}
+	for _, academy := range academies {
+		sources = append(sources, models.MediaManifestEntry{
+			Kind:      "academy_logo",
+			Key:       academy.ExternalID,
+			SourceURL: academy.LogoURL,
+		})
+	}
+
+	codes := make(map[string]struct{})
+	for _, model := range []interface{}{&models.Academy{}, &models.Athlete{}, &models.Event{}} {
+		var rows []string
+		if err := db.Model(model).Distinct().Where("country_code <> ''").Pluck("UPPER(country_code)", &rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to load country codes: %w", err)
+		}
+		for _, code := range rows {
+			codes[code] = struct{}{}
+		}
+	}
+
+	sortedCodes := make([]string, 0, len(codes))
+	for code := range codes {
+		sortedCodes = append(sortedCodes, code)
+	}
+	sort.Strings(sortedCodes)
+
+	for _, code := range sortedCodes {
+		sources = append(sources, models.MediaManifestEntry{
+			Kind:      "country_flag",
+			Key:       code,
+			SourceURL: s.FlagURL(code),
+		})
+	}
+
+	return sources, nil
+}
+
+func (s *Store) checkSource(sourceURL string) error {
+	parsed, err := url.Parse(sourceURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("invalid media url: %s", sourceURL)
+	}
+
+	// "*.example.com" allows the subdomains of example.com, any other entry
+	// only that exact host
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range s.config.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return nil
+			}
+		} else if host == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("media host not allowed: %s", host)
+}
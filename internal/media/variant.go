@@ -0,0 +1,218 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // source decoders
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Fit modes of a resized variant
+const (
+	FitCover   = "cover"   // fill the box, cropping the overflow around the center
+	FitContain = "contain" // fit inside the box, keeping the whole image
+)
+
+// Variant is a resized copy of a cached image. A zero Width or Height
+// follows the aspect ratio; the zero Variant is the original.
+type Variant struct {
+	Width  int
+	Height int
+	Fit    string
+}
+
+// IsOriginal reports whether v asks for the image unchanged
+func (v Variant) IsOriginal() bool {
+	return v.Width == 0 && v.Height == 0
+}
+
+// Key identifies the variant in cache file names and ETags
+func (v Variant) Key() string {
+	return fmt.Sprintf("%dx%d-%s", v.Width, v.Height, v.Fit)
+}
+
+// ParseVariant reads the ?w=, ?h= and ?fit= parameters of a media request.
+// Sizes go from 1 to MEDIA_MAX_DIMENSION pixels and snap up to the next of
+// MEDIA_VARIANT_SIZES, so each image has a handful of variants; fit defaults
+// to cover.
+func (s *Store) ParseVariant(width, height, fit string) (Variant, error) {
+	var v Variant
+	for _, dim := range []struct {
+		name, raw string
+		dst       *int
+	}{{"w", width, &v.Width}, {"h", height, &v.Height}} {
+		if dim.raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(dim.raw)
+		if err != nil || n < 1 || n > s.config.MaxDimension {
+			return Variant{}, fmt.Errorf("%s must be between 1 and %d pixels", dim.name, s.config.MaxDimension)
+		}
+		*dim.dst = s.snapSize(n)
+	}
+
+	switch fit {
+	case "", FitCover:
+		v.Fit = FitCover
+	case FitContain:
+		v.Fit = FitContain
+	default:
+		return Variant{}, fmt.Errorf("fit must be %s or %s", FitCover, FitContain)
+	}
+	if v.IsOriginal() {
+		return Variant{}, nil
+	}
+	return v, nil
+}
+
+// snapSize rounds n up to the smallest configured variant size, or down to
+// the largest when n is above them all
+func (s *Store) snapSize(n int) int {
+	sizes := s.config.VariantSizes
+	if len(sizes) == 0 {
+		return n
+	}
+	for _, size := range sizes {
+		if size >= n {
+			return size
+		}
+	}
+	return sizes[len(sizes)-1]
+}
+
+// ErrNotResizable is returned for images in a format that cannot be decoded
+// or too large to decode safely
+var ErrNotResizable = errors.New("image format cannot be resized")
+
+// maxSourcePixels bounds the images decoded for a variant, since a small
+// compressed file can expand to gigabytes of pixels
+const maxSourcePixels = 25_000_000
+
+// VariantPath returns the file of a resized copy of asset, rendering and
+// caching it on first use. Files are keyed on the output size, so boxes
+// that the no-upscale rule shrinks to the same image share one copy.
+// Variants are derived from the content hash, so like the original they
+// never change.
+func (s *Store) VariantPath(asset *models.MediaAsset, v Variant) (string, error) {
+	file, err := os.Open(s.Path(asset.Hash))
+	if err != nil {
+		return "", fmt.Errorf("error opening media: %w", err)
+	}
+	defer file.Close()
+
+	// The header alone gives the size, before paying for a full decode
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return "", fmt.Errorf("%w: %s", ErrNotResizable, asset.ContentType)
+	}
+	if cfg.Width*cfg.Height > maxSourcePixels {
+		return "", fmt.Errorf("%w: %dx%d source is above %d pixels", ErrNotResizable, cfg.Width, cfg.Height, maxSourcePixels)
+	}
+
+	bounds := image.Rect(0, 0, cfg.Width, cfg.Height)
+	crop, width, height := variantBox(bounds, v)
+	out := Variant{Width: width, Height: height, Fit: FitContain}
+	if crop != bounds {
+		out.Fit = FitCover
+	}
+	path := filepath.Join(s.config.CacheDir, "variants", asset.Hash[:2], asset.Hash+"-"+out.Key())
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error reading media: %w", err)
+	}
+	src, _, err := image.Decode(file)
+	if err != nil || src.Bounds().Empty() {
+		return "", fmt.Errorf("%w: %s", ErrNotResizable, asset.ContentType)
+	}
+	resized := resize(src, v)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("error creating media dir: %w", err)
+	}
+	// Written aside and renamed, so concurrent requests never serve half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".variant-*")
+	if err != nil {
+		return "", fmt.Errorf("error writing media variant: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	// Opaque images are sent as JPEG, the smallest for photos; anything
+	// with transparency stays PNG
+	if opaque, ok := src.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		err = jpeg.Encode(tmp, resized, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(tmp, resized)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("error encoding media variant: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("error writing media variant: %w", err)
+	}
+	return path, nil
+}
+
+// resize scales src into the variant box
+func resize(src image.Image, v Variant) image.Image {
+	crop, width, height := variantBox(src.Bounds(), v)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, xdraw.Src, nil)
+	return dst
+}
+
+// variantBox returns the region of an image with the given bounds that the
+// variant shows and the size it is scaled to. Images are never enlarged: a
+// box bigger than the source shrinks to it, keeping the requested
+// proportions.
+func variantBox(bounds image.Rectangle, v Variant) (crop image.Rectangle, width, height int) {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	width, height = v.Width, v.Height
+	switch {
+	case width == 0:
+		width = max(1, srcW*height/srcH)
+	case height == 0:
+		height = max(1, srcH*width/srcW)
+	}
+
+	crop = bounds
+	if v.Fit == FitCover && v.Width > 0 && v.Height > 0 {
+		// Largest centered region with the box proportions
+		cropW, cropH := srcW, srcW*height/width
+		if cropH > srcH {
+			cropW, cropH = srcH*width/height, srcH
+		}
+		x := bounds.Min.X + (srcW-cropW)/2
+		y := bounds.Min.Y + (srcH-cropH)/2
+		crop = image.Rect(x, y, x+cropW, y+cropH)
+	} else if v.Width > 0 && v.Height > 0 {
+		// Contain: shrink the box to the source proportions
+		if srcW*height > srcH*width {
+			height = max(1, srcH*width/srcW)
+		} else {
+			width = max(1, srcW*height/srcH)
+		}
+	}
+
+	// No upscaling
+	if scale := min(float64(crop.Dx())/float64(width), float64(crop.Dy())/float64(height)); scale < 1 {
+		width = max(1, int(float64(width)*scale))
+		height = max(1, int(float64(height)*scale))
+	}
+	return crop, width, height
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import "sync/atomic"
+
+// fetchCacheCounts tallies, since startup, the scraper requests answered by
+// the fetch cache and those sent to Smoothcomp
+var fetchCacheCounts struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// CacheStats reports how often a cache answered its lookups
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"` // hits over lookups, 0 before the first
+}
+
+// ObserveFetchCache counts a cacheable scraper request served from the fetch
+// cache (hit) or fetched
+func ObserveFetchCache(hit bool) {
+	if hit {
+		fetchCacheCounts.hits.Add(1)
+	} else {
+		fetchCacheCounts.misses.Add(1)
+	}
+}
+
+// FetchCache returns the fetch cache counters since startup
+func FetchCache() CacheStats {
+	stats := CacheStats{
+		Hits:   fetchCacheCounts.hits.Load(),
+		Misses: fetchCacheCounts.misses.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
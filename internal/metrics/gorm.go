@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	startKey = "metrics:start"
+	skipKey  = "metrics:skip" // set on the tracker's own statements
+)
+
+// InstrumentDB registers GORM callbacks that report SELECT durations to t
+func InstrumentDB(db *gorm.DB, t *Tracker) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(tx *gorm.DB) {
+		if skip, ok := tx.Get(skipKey); ok && skip == true {
+			return
+		}
+		start, ok := tx.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		t.ObserveQuery(tx.Statement.SQL.String(), tx.Statement.Vars, time.Since(start.(time.Time)))
+	}
+
+	if err := db.Callback().Query().Before("gorm:query").Register("metrics:before_query", before); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("metrics:after_query", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("metrics:before_row", before); err != nil {
+		return err
+	}
+	return db.Callback().Row().After("gorm:row").Register("metrics:after_row", after)
+}
@@ -0,0 +1,314 @@
+package metrics
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// samples kept per route for percentile estimation
+const maxSamples = 1024
+
+// Tracker aggregates API route latencies and SQL statement durations per UTC
+// day and persists them as RouteLatency / SlowQuery rows
+type Tracker struct {
+	budget    time.Duration
+	slowQuery time.Duration
+
+	mu      sync.Mutex
+	day     string
+	routes  map[routeKey]*routeStats
+	queries map[string]*queryStats
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type routeStats struct {
+	count      int64
+	totalMs    float64
+	maxMs      float64
+	overBudget int64
+	samples    []float64
+	next       int
+}
+
+type queryStats struct {
+	sql     string
+	sample  string // slowest statement as executed, for EXPLAIN
+	vars    []interface{}
+	count   int64
+	totalMs float64
+	maxMs   float64
+}
+
+var (
+	defaultTracker *Tracker
+	inList         = regexp.MustCompile(`\((?:\?,\s*)+\?\)`)
+)
+
+// Init creates the process-wide tracker
+func Init(cfg *config.Config) *Tracker {
+	defaultTracker = &Tracker{
+		budget:    cfg.Server.LatencyBudget,
+		slowQuery: cfg.Server.SlowQueryThreshold,
+		day:       today(),
+		routes:    make(map[routeKey]*routeStats),
+		queries:   make(map[string]*queryStats),
+	}
+	return defaultTracker
+}
+
+// Default returns the process-wide tracker, nil before Init
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// Budget returns the per-request latency budget
+func (t *Tracker) Budget() time.Duration {
+	return t.budget
+}
+
+// ObserveRequest records the duration of one API request
+func (t *Tracker) ObserveRequest(method, route string, duration time.Duration) {
+	ms := float64(duration) / float64(time.Millisecond)
+
+	t.mu.Lock()
+	t.rollover()
+	key := routeKey{method: method, route: route}
+	stats, ok := t.routes[key]
+	if !ok {
+		stats = &routeStats{samples: make([]float64, 0, 64)}
+		t.routes[key] = stats
+	}
+	stats.count++
+	stats.totalMs += ms
+	if ms > stats.maxMs {
+		stats.maxMs = ms
+	}
+	if duration > t.budget {
+		stats.overBudget++
+	}
+	if len(stats.samples) < maxSamples {
+		stats.samples = append(stats.samples, ms)
+	} else {
+		stats.samples[stats.next] = ms
+		stats.next = (stats.next + 1) % maxSamples
+	}
+	t.mu.Unlock()
+
+	if duration > t.budget {
+		logger.Warn("Request exceeded latency budget",
+			zap.String("method", method),
+			zap.String("route", route),
+			zap.Duration("duration", duration),
+			zap.Duration("budget", t.budget))
+	}
+}
+
+// ObserveQuery records the duration of one SQL statement
+func (t *Tracker) ObserveQuery(sql string, vars []interface{}, duration time.Duration) {
+	if sql == "" {
+		return
+	}
+	sample := sql
+	sql = inList.ReplaceAllString(sql, "(?)")
+	ms := float64(duration) / float64(time.Millisecond)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover()
+
+	stats, ok := t.queries[sql]
+	if !ok {
+		stats = &queryStats{sql: sql}
+		t.queries[sql] = stats
+	}
+	stats.count++
+	stats.totalMs += ms
+	if ms >= stats.maxMs {
+		stats.maxMs = ms
+		stats.sample = sample
+		stats.vars = vars
+	}
+}
+
+// Flush persists the current day's aggregates
+func (t *Tracker) Flush() {
+	t.mu.Lock()
+	day, routes, queries := t.snapshot()
+	t.mu.Unlock()
+
+	t.persist(day, routes, queries)
+}
+
+// Run flushes periodically until stop is closed, then flushes once more
+func (t *Tracker) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.Flush()
+		case <-stop:
+			t.Flush()
+			return
+		}
+	}
+}
+
+// rollover starts a new day, persisting the previous one. Caller holds mu.
+func (t *Tracker) rollover() {
+	if now := today(); now != t.day {
+		day, routes, queries := t.snapshot()
+		go t.persist(day, routes, queries)
+
+		t.day = now
+		t.routes = make(map[routeKey]*routeStats)
+		t.queries = make(map[string]*queryStats)
+	}
+}
+
+// snapshot copies the aggregates into rows. Caller holds mu.
+func (t *Tracker) snapshot() (string, []models.RouteLatency, []queryRow) {
+	routes := make([]models.RouteLatency, 0, len(t.routes))
+	for key, stats := range t.routes {
+		routes = append(routes, models.RouteLatency{
+			Day:        t.day,
+			Method:     key.method,
+			Route:      key.route,
+			Count:      stats.count,
+			AvgMs:      round(stats.totalMs / float64(stats.count)),
+			P95Ms:      round(percentile(stats.samples, 0.95)),
+			MaxMs:      round(stats.maxMs),
+			OverBudget: stats.overBudget,
+		})
+	}
+
+	threshold := float64(t.slowQuery) / float64(time.Millisecond)
+	queries := make([]queryRow, 0)
+	for _, stats := range t.queries {
+		if stats.maxMs < threshold {
+			continue
+		}
+		queries = append(queries, queryRow{
+			SlowQuery: models.SlowQuery{
+				Day:   t.day,
+				Hash:  hashSQL(stats.sql),
+				SQL:   stats.sql,
+				Count: stats.count,
+				AvgMs: round(stats.totalMs / float64(stats.count)),
+				MaxMs: round(stats.maxMs),
+			},
+			sample: stats.sample,
+			vars:   stats.vars,
+		})
+	}
+
+	return t.day, routes, queries
+}
+
+type queryRow struct {
+	models.SlowQuery
+	sample string
+	vars   []interface{}
+}
+
+func (t *Tracker) persist(day string, routes []models.RouteLatency, queries []queryRow) {
+	db := config.GetDB()
+	if db == nil {
+		return
+	}
+	db = db.Set(skipKey, true).Session(&gorm.Session{})
+
+	if len(routes) > 0 {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}, {Name: "method"}, {Name: "route"}},
+			DoUpdates: clause.AssignmentColumns([]string{"count", "avg_ms", "p95_ms", "max_ms", "over_budget", "updated_at"}),
+		}).Create(&routes).Error
+		if err != nil {
+			logger.Error("Failed to persist route latencies", zap.Error(err))
+		}
+	}
+
+	for _, query := range queries {
+		var existing models.SlowQuery
+		if err := db.Where("day = ? AND hash = ?", day, query.Hash).First(&existing).Error; err == nil {
+			query.ID = existing.ID
+			query.Plan = existing.Plan
+		}
+		if query.Plan == "" {
+			query.Plan = explain(db, query.sample, query.vars)
+		}
+		if err := db.Save(&query.SlowQuery).Error; err != nil {
+			logger.Error("Failed to persist slow query", zap.Error(err))
+		}
+	}
+}
+
+// explain returns the query plan of a statement, one step per line
+func explain(db *gorm.DB, sql string, vars []interface{}) string {
+	if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+		return ""
+	}
+
+	if db.Dialector.Name() == "postgres" {
+		var plan []string
+		if err := db.Raw("EXPLAIN "+sql, vars...).Scan(&plan).Error; err != nil {
+			return ""
+		}
+		return strings.Join(plan, "\n")
+	}
+
+	type planRow struct {
+		ID     int
+		Parent int
+		Detail string
+	}
+	var rows []planRow
+	if err := db.Raw("EXPLAIN QUERY PLAN "+sql, vars...).Scan(&rows).Error; err != nil {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, row.Detail)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func percentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
+
+func round(ms float64) float64 {
+	return float64(int64(ms*100+0.5)) / 100
+}
+
+func hashSQL(sql string) string {
+	sum := sha1.Sum([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
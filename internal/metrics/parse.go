@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// parseCounts tallies, since startup, how many parsed records had each field
+var parseCounts = struct {
+	sync.Mutex
+	fields map[[2]string]*models.FieldCoverage
+}{fields: make(map[[2]string]*models.FieldCoverage)}
+
+// ObserveField counts a parsed record of kind record that had (or lacked) field
+func ObserveField(record, field string, found bool) {
+	parseCounts.Lock()
+	defer parseCounts.Unlock()
+
+	key := [2]string{record, field}
+	count := parseCounts.fields[key]
+	if count == nil {
+		count = &models.FieldCoverage{Record: record, Field: field}
+		parseCounts.fields[key] = count
+	}
+	count.Add(found)
+}
+
+// ParseCoverage returns the per-field parse counters since startup, sorted
+// by record and field
+func ParseCoverage() []models.FieldCoverage {
+	parseCounts.Lock()
+	result := make([]models.FieldCoverage, 0, len(parseCounts.fields))
+	for _, count := range parseCounts.fields {
+		result = append(result, *count)
+	}
+	parseCounts.Unlock()
+
+	models.SortFieldCoverage(result)
+	return result
+}
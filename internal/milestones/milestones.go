@@ -0,0 +1,156 @@
+// Package milestones computes the win streaks of athletes from their stored
+// bracket matches and records the milestones they reach along the way: win
+// counts such as the 100th win and win or submission streaks.
+package milestones
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"gorm.io/gorm"
+)
+
+// Milestone thresholds, by kind
+var thresholds = map[string][]int{
+	models.MilestoneWins:             {10, 25, 50, 100, 150, 200, 250, 300, 400, 500, 750, 1000},
+	models.MilestoneWinStreak:        {5, 10, 15, 20, 30, 50},
+	models.MilestoneSubmissionStreak: {3, 5, 10, 15, 20},
+}
+
+// Reached is a milestone recorded by Update
+type Reached struct {
+	models.AthleteMilestone
+	Streak models.AthleteStreak `json:"streak"` // the record after the update
+}
+
+// match is a decided match of an athlete, in the order it was fought
+type match struct {
+	ID        int
+	EventID   string
+	EventName string
+	StartedAt *time.Time
+	WinnerID  uint
+	Method    string
+}
+
+// Update recomputes the streaks of athletes and records the milestones
+// they reached that were not recorded yet. A total wins milestone is only
+// recorded once per athlete, even when matches scraped later move it to
+// another match.
+func Update(athleteIDs []uint) ([]Reached, error) {
+	db := config.GetDB()
+	var reached []Reached
+
+	for _, athleteID := range athleteIDs {
+		streak, milestones, err := compute(db, athleteID)
+		if err != nil {
+			return reached, err
+		}
+		if err := db.Save(&streak).Error; err != nil {
+			return reached, fmt.Errorf("error saving streaks of athlete %d: %w", athleteID, err)
+		}
+
+		for _, milestone := range milestones {
+			query := db.Model(&models.AthleteMilestone{}).
+				Where("athlete_id = ? AND kind = ? AND value = ?", athleteID, milestone.Kind, milestone.Value)
+			if milestone.Kind != models.MilestoneWins {
+				query = query.Where("match_id = ?", milestone.MatchID)
+			}
+			var existing int64
+			if err := query.Count(&existing).Error; err != nil {
+				return reached, err
+			}
+			if existing > 0 {
+				continue
+			}
+
+			if err := db.Create(&milestone).Error; err != nil {
+				return reached, fmt.Errorf("error saving milestone of athlete %d: %w", athleteID, err)
+			}
+			reached = append(reached, Reached{AthleteMilestone: milestone, Streak: streak})
+		}
+	}
+	return reached, nil
+}
+
+// Streak returns the stored streaks and milestones of an athlete, newest
+// milestone first
+func Streak(athleteID uint) (*models.AthleteStreak, []models.AthleteMilestone, error) {
+	db := config.GetDB()
+
+	var streak models.AthleteStreak
+	if err := db.Where("athlete_id = ?", athleteID).Limit(1).Find(&streak).Error; err != nil {
+		return nil, nil, err
+	}
+	streak.AthleteID = athleteID
+
+	milestones := []models.AthleteMilestone{}
+	if err := db.Where("athlete_id = ?", athleteID).
+		Order("reached_at DESC, id DESC").Find(&milestones).Error; err != nil {
+		return nil, nil, err
+	}
+	return &streak, milestones, nil
+}
+
+// compute walks the decided matches of an athlete in order, counting wins
+// and streaks and noting each milestone threshold crossed
+func compute(db *gorm.DB, athleteID uint) (models.AthleteStreak, []models.AthleteMilestone, error) {
+	streak := models.AthleteStreak{AthleteID: athleteID, ComputedAt: time.Now()}
+
+	var matches []match
+	err := db.Table("matches").
+		Select("matches.id, matches.event_id, matches.event_name, matches.started_at, matches.winner_id, matches.method").
+		Joins("LEFT JOIN events ON events.external_id = matches.event_id").
+		Where("(matches.athlete_a_id = ? OR matches.athlete_b_id = ?) AND (matches.winner_id <> 0 OR matches.winner_name <> '' OR matches.method <> '')",
+			athleteID, athleteID).
+		Order("COALESCE(matches.started_at, events.start_date), matches.id").
+		Scan(&matches).Error
+	if err != nil {
+		return streak, nil, fmt.Errorf("error loading matches of athlete %d: %w", athleteID, err)
+	}
+
+	var milestones []models.AthleteMilestone
+	crossed := func(m match, kind string, value int) {
+		for _, threshold := range thresholds[kind] {
+			if threshold == value {
+				milestones = append(milestones, models.AthleteMilestone{
+					AthleteID: athleteID,
+					Kind:      kind,
+					Value:     value,
+					MatchID:   m.ID,
+					EventID:   m.EventID,
+					EventName: m.EventName,
+					ReachedAt: m.StartedAt,
+				})
+			}
+		}
+	}
+
+	for _, m := range matches {
+		streak.Matches++
+		if m.WinnerID != athleteID {
+			streak.CurrentWinStreak = 0
+			streak.CurrentSubmissionStreak = 0
+			continue
+		}
+
+		streak.Wins++
+		streak.CurrentWinStreak++
+		streak.LongestWinStreak = max(streak.LongestWinStreak, streak.CurrentWinStreak)
+		crossed(m, models.MilestoneWins, streak.Wins)
+		crossed(m, models.MilestoneWinStreak, streak.CurrentWinStreak)
+
+		if m.Method == "submission" {
+			streak.SubmissionWins++
+			streak.CurrentSubmissionStreak++
+			streak.LongestSubmissionStreak = max(streak.LongestSubmissionStreak, streak.CurrentSubmissionStreak)
+			crossed(m, models.MilestoneSubmissionStreak, streak.CurrentSubmissionStreak)
+		} else {
+			streak.CurrentSubmissionStreak = 0
+		}
+	}
+
+	return streak, milestones, nil
+}
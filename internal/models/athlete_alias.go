@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// AthleteAlias maps the external ID of a Smoothcomp account that was merged
+// into another one, detected when its profile redirects, to the ID it now
+// redirects to. Scrapers store rows listing the old ID under the new one.
+type AthleteAlias struct {
+	ID              int       `json:"id" gorm:"primaryKey"`
+	AliasExternalID string    `json:"alias_external_id" gorm:"uniqueIndex;not null"` // merged away
+	ExternalID      string    `json:"external_id" gorm:"index;not null"`             // kept by Smoothcomp
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
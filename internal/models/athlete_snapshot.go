@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AthleteSnapshot is the belt and win/loss record of an athlete as a profile
+// scrape left it. A snapshot is only stored when the stats differ from the
+// previous one, so consecutive rows show how the record evolved.
+type AthleteSnapshot struct {
+	ID        int    `json:"id" gorm:"primaryKey"`
+	AthleteID int    `json:"athlete_id" gorm:"not null;index:idx_athlete_snapshot"`
+	BeltRank  string `json:"belt_rank"`
+
+	TotalWins        int `json:"total_wins"`
+	WinsBySubmission int `json:"wins_by_submission"`
+	WinsByPoints     int `json:"wins_by_points"`
+	WinsByDecision   int `json:"wins_by_decision"`
+	WinsByDQ         int `json:"wins_by_dq"`
+
+	TotalLosses        int `json:"total_losses"`
+	LossesBySubmission int `json:"losses_by_submission"`
+	LossesByPoints     int `json:"losses_by_points"`
+	LossesByDecision   int `json:"losses_by_decision"`
+	LossesByDQ         int `json:"losses_by_dq"`
+
+	CapturedAt time.Time `json:"captured_at" gorm:"not null;index:idx_athlete_snapshot"`
+}
+
+// NewAthleteSnapshot captures the current stats of athlete
+func NewAthleteSnapshot(athlete Athlete, at time.Time) AthleteSnapshot {
+	return AthleteSnapshot{
+		AthleteID:          athlete.ID,
+		BeltRank:           athlete.BeltRank,
+		TotalWins:          athlete.TotalWins,
+		WinsBySubmission:   athlete.WinsBySubmission,
+		WinsByPoints:       athlete.WinsByPoints,
+		WinsByDecision:     athlete.WinsByDecision,
+		WinsByDQ:           athlete.WinsByDQ,
+		TotalLosses:        athlete.TotalLosses,
+		LossesBySubmission: athlete.LossesBySubmission,
+		LossesByPoints:     athlete.LossesByPoints,
+		LossesByDecision:   athlete.LossesByDecision,
+		LossesByDQ:         athlete.LossesByDQ,
+		CapturedAt:         at,
+	}
+}
+
+// SameStats reports whether two snapshots hold the same belt and record
+func (s AthleteSnapshot) SameStats(other AthleteSnapshot) bool {
+	s.ID, s.AthleteID, s.CapturedAt = other.ID, other.AthleteID, other.CapturedAt
+	return s == other
+}
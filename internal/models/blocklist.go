@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Blocked entity types
+const (
+	BlockedAthlete = "athlete"
+	BlockedEvent   = "event"
+	BlockedAcademy = "academy"
+)
+
+// BlockedEntity is a Smoothcomp athlete, event or club that scrapers skip,
+// e.g. profiles that always error or honeypot pages
+type BlockedEntity struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"not null;uniqueIndex:idx_blocked_entity"` // athlete, event, academy
+	ExternalID string    `json:"external_id" gorm:"not null;uniqueIndex:idx_blocked_entity"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IsBlockedEntityType reports whether t is a blockable entity type
+func IsBlockedEntityType(t string) bool {
+	return t == BlockedAthlete || t == BlockedEvent || t == BlockedAcademy
+}
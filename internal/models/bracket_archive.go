@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// BracketArchive is a raw bracket payload of one event division as fetched
+// from Smoothcomp, gzip-compressed. A new version is stored only when the
+// payload changes.
+type BracketArchive struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	EventID    string    `json:"event_id" gorm:"index:idx_bracket_archive;not null"`
+	DivisionID string    `json:"division_id" gorm:"index:idx_bracket_archive;not null"`
+	SourceURL  string    `json:"source_url"`
+	Hash       string    `json:"hash" gorm:"not null"` // sha256 of the raw payload
+	RawSize    int       `json:"raw_size"`
+	Payload    []byte    `json:"-"` // gzip
+	FetchedAt  time.Time `json:"fetched_at" gorm:"index"`
+}
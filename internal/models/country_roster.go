@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CountryRosterEntry is an athlete of a country's national registry: an
+// athlete of that nationality registered in events held in the country,
+// built by the country roster pipeline from the stored registrations
+type CountryRosterEntry struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	CountryCode       string     `json:"country_code" gorm:"not null;uniqueIndex:idx_country_roster"`
+	AthleteID         uint       `json:"athlete_id" gorm:"not null;uniqueIndex:idx_country_roster"`
+	AthleteExternalID string     `json:"athlete_external_id"`
+	FullName          string     `json:"full_name"`
+	AcademyExternalID string     `json:"academy_external_id,omitempty"`
+	Events            int        `json:"events"` // events in the country the athlete registered in
+	FirstEventAt      *time.Time `json:"first_event_at,omitempty"`
+	LastEventAt       *time.Time `json:"last_event_at,omitempty" gorm:"index"`
+	BuiltAt           time.Time  `json:"built_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "sort"
+
+// FieldCoverage is how many parsed records of a kind ("profile",
+// "participant", "event_details") had a field, for one job
+type FieldCoverage struct {
+	ID     int     `json:"-" gorm:"primaryKey"`
+	JobID  int     `json:"-" gorm:"index"`
+	Record string  `json:"record"`
+	Field  string  `json:"field"`
+	Found  int     `json:"found"`
+	Total  int     `json:"total"`
+	Rate   float64 `json:"rate"` // found / total
+}
+
+// Add counts one parsed record
+func (c *FieldCoverage) Add(found bool) {
+	c.Total++
+	if found {
+		c.Found++
+	}
+	c.Rate = float64(int(float64(c.Found)/float64(c.Total)*1000+0.5)) / 1000
+}
+
+// SortFieldCoverage orders coverage rows by record, then field
+func SortFieldCoverage(rows []FieldCoverage) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Record != rows[j].Record {
+			return rows[i].Record < rows[j].Record
+		}
+		return rows[i].Field < rows[j].Field
+	})
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CrawlVisit is a request colly already made, keyed by colly's request hash,
+// so restarts do not fetch the same pages again
+type CrawlVisit struct {
+	RequestID int64     `json:"request_id" gorm:"primaryKey;autoIncrement:false"` // uint64 hash stored as its bits
+	VisitedAt time.Time `json:"visited_at" gorm:"index"`
+}
+
+// CrawlCookie holds the cookies colly collected for a host
+type CrawlCookie struct {
+	Host      string    `json:"host" gorm:"primaryKey"`
+	Cookies   string    `json:"-" gorm:"type:text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
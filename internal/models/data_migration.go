@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// DataMigration records a one-off data fix that already ran, so it is not
+// repeated on later startups
+type DataMigration struct {
+	Name      string    `json:"name" gorm:"primaryKey"`
+	AppliedAt time.Time `json:"applied_at"`
+}
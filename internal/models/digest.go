@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Digest item kinds
+const (
+	DigestNewEvent          = "new_event"
+	DigestDateChanged       = "date_changed"
+	DigestVenueChanged      = "venue_changed"
+	DigestRegistrationsJump = "registrations_jump"
+)
+
+// EventDigest lists the interesting changes found by one upcoming-events scrape
+type EventDigest struct {
+	ID        int               `json:"id" gorm:"primaryKey"`
+	JobID     int               `json:"job_id" gorm:"index"`
+	EventType string            `json:"event_type"`
+	Items     []EventDigestItem `json:"items" gorm:"foreignKey:DigestID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time         `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// EventDigestItem is one change to an event
+type EventDigestItem struct {
+	ID        int    `json:"-" gorm:"primaryKey"`
+	DigestID  int    `json:"-" gorm:"index;not null"`
+	Kind      string `json:"kind" gorm:"not null"`
+	EventID   string `json:"event_id"`
+	EventName string `json:"event_name"`
+	EventURL  string `json:"event_url"`
+	Before    string `json:"before,omitempty"`
+	After     string `json:"after,omitempty"`
+	Delta     int    `json:"delta,omitempty"` // registrations gained since the previous digest
+}
+
+// EventRegistrationCount is the registration count of an event as of the
+// last digest, used to detect jumps between runs
+type EventRegistrationCount struct {
+	EventID   string    `json:"event_id" gorm:"primaryKey"`
+	Count     int       `json:"count"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
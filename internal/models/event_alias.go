@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EventURLAlias is a former URL of an event that now redirects to EventURL,
+// e.g. after the event moved to a federation subdomain. SaveEvent stores
+// events listed under an alias on the canonical row.
+type EventURLAlias struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	URL       string    `json:"url" gorm:"uniqueIndex;not null"` // redirects
+	EventURL  string    `json:"event_url" gorm:"index;not null"` // where it redirects to
+	EventID   string    `json:"event_id" gorm:"index"`           // Smoothcomp event ID
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// EventVenue stores the venue panel of an event page
+type EventVenue struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	EventID   string    `json:"event_id" gorm:"uniqueIndex;not null"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	City      string    `json:"city"`
+	Country   string    `json:"country"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// EventContact stores the organizer contact panel of an event page
+type EventContact struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	EventID   string    `json:"event_id" gorm:"uniqueIndex;not null"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Website   string    `json:"website"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// EventRules stores the rules panel of an event page
+type EventRules struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	EventID   string    `json:"event_id" gorm:"uniqueIndex;not null"`
+	Ruleset   string    `json:"ruleset"`
+	Text      string    `json:"text" gorm:"type:text"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// EventScheduleItem is a single entry of the schedule panel
+type EventScheduleItem struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	EventID     string    `json:"event_id" gorm:"index;not null"`
+	Position    int       `json:"position"`
+	Date        string    `json:"date"`
+	StartTime   string    `json:"start_time"`
+	EndTime     string    `json:"end_time"`
+	Title       string    `json:"title"`
+	Description string    `json:"description" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// EventRegistrationWindow is a registration period (early, regular, late...)
+type EventRegistrationWindow struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	EventID   string    `json:"event_id" gorm:"index;not null"`
+	Name      string    `json:"name"`
+	OpensAt   string    `json:"opens_at"`
+	ClosesAt  string    `json:"closes_at"`
+	Price     string    `json:"price"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// EventInfo groups the typed info panels of an event
+type EventInfo struct {
+	EventID             string                    `json:"event_id"`
+	Venue               *EventVenue               `json:"venue,omitempty"`
+	Contact             *EventContact             `json:"contact,omitempty"`
+	Rules               *EventRules               `json:"rules,omitempty"`
+	Schedule            []EventScheduleItem       `json:"schedule"`
+	RegistrationWindows []EventRegistrationWindow `json:"registration_windows"`
+}
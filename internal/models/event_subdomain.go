@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// EventSubdomain caches the Smoothcomp host an event was detected on, so
+// scrapes of events without a stored URL skip probing every federation
+// subdomain. Entries older than SUBDOMAIN_CACHE_DAYS, or marked stale after
+// the host stopped serving the event, are detected again.
+type EventSubdomain struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	EventID    string    `json:"event_id" gorm:"uniqueIndex;not null"`
+	Host       string    `json:"host" gorm:"not null"`              // e.g. "ajp.smoothcomp.com"
+	Federation string    `json:"federation,omitempty" gorm:"index"` // subdomain label, empty on smoothcomp.com
+	Stale      bool      `json:"stale"`                             // the host answered 404 since it was detected
+	DetectedAt time.Time `json:"detected_at"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
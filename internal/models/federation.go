@@ -0,0 +1,43 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Federations whose athlete IDs can be linked
+const (
+	FederationIBJJF = "ibjjf"
+	FederationAJP   = "ajp"
+)
+
+// How a federation ID was linked to an athlete
+const (
+	FederationMatchExternalID = "external_id" // the import named the Smoothcomp athlete
+	FederationMatchName       = "name"        // a single athlete had the imported name
+)
+
+// FederationID links a Smoothcomp athlete to its ID in an official
+// federation, so results from other sources can be joined to the same
+// athlete. Links reference the Smoothcomp external ID and live apart from
+// scraped rows, so re-scrapes keep them.
+type FederationID struct {
+	ID                  int       `json:"id" gorm:"primaryKey"`
+	AthleteExternalID   string    `json:"athlete_external_id" gorm:"not null;uniqueIndex:idx_federation_athlete"`
+	Federation          string    `json:"federation" gorm:"not null;uniqueIndex:idx_federation_athlete;uniqueIndex:idx_federation_id"` // ibjjf, ajp
+	FederationAthleteID string    `json:"federation_athlete_id" gorm:"not null;uniqueIndex:idx_federation_id"`
+	Name                string    `json:"name"`       // as supplied by the import
+	MatchedBy           string    `json:"matched_by"` // external_id, name
+	Actor               string    `json:"actor"`
+	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ParseFederation returns the federation named by s in any case, or false
+// when it is not supported
+func ParseFederation(s string) (string, bool) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case FederationIBJJF, FederationAJP:
+		return f, true
+	}
+	return "", false
+}
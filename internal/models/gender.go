@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+)
+
+// Gender is the normalized gender of an athlete or division
+type Gender string
+
+const (
+	GenderUnknown Gender = ""
+	GenderMale    Gender = "male"
+	GenderFemale  Gender = "female"
+	GenderMixed   Gender = "mixed"
+)
+
+var genderWords = map[string]Gender{
+	"m": GenderMale, "male": GenderMale, "males": GenderMale, "man": GenderMale, "men": GenderMale,
+	"boy": GenderMale, "boys": GenderMale, "masculino": GenderMale, "masculine": GenderMale,
+	"hombre": GenderMale, "hombres": GenderMale, "varones": GenderMale, "nino": GenderMale, "ninos": GenderMale,
+	"f": GenderFemale, "w": GenderFemale, "female": GenderFemale, "females": GenderFemale,
+	"woman": GenderFemale, "women": GenderFemale, "girl": GenderFemale, "girls": GenderFemale,
+	"femenino": GenderFemale, "feminino": GenderFemale, "feminine": GenderFemale,
+	"mujer": GenderFemale, "mujeres": GenderFemale, "nina": GenderFemale, "ninas": GenderFemale,
+	"mixed": GenderMixed, "mixto": GenderMixed, "misto": GenderMixed, "coed": GenderMixed, "unisex": GenderMixed,
+}
+
+var kidsWords = map[string]bool{
+	"boy": true, "boys": true, "girl": true, "girls": true, "kid": true, "kids": true,
+	"child": true, "children": true, "youth": true, "infantil": true,
+	"nino": true, "ninos": true, "nina": true, "ninas": true,
+}
+
+// ParseGender normalizes a gender or division label ("Women", "Girls",
+// "Masculino", "F", "Mixed"...) and reports whether it denotes a kids division.
+// Unrecognized labels return GenderUnknown.
+func ParseGender(label string) (gender Gender, kids bool) {
+	label = slug.Fold(label)
+	for _, word := range strings.FieldsFunc(label, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/' || r == '_' || r == '(' || r == ')'
+	}) {
+		if kidsWords[word] {
+			kids = true
+		}
+		if g, ok := genderWords[word]; ok && gender == GenderUnknown {
+			gender = g
+		}
+	}
+	return gender, kids
+}
+
+// IsKidsCategory reports whether an age category label denotes kids
+func IsKidsCategory(ageCategory string) bool {
+	_, kids := ParseGender(ageCategory)
+	return kids
+}
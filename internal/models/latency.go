@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RouteLatency is the daily latency summary of one API route
+type RouteLatency struct {
+	ID         int       `json:"-" gorm:"primaryKey"`
+	Day        string    `json:"day" gorm:"uniqueIndex:idx_route_latency;not null"` // YYYY-MM-DD (UTC)
+	Method     string    `json:"method" gorm:"uniqueIndex:idx_route_latency;not null"`
+	Route      string    `json:"route" gorm:"uniqueIndex:idx_route_latency;not null"` // mux path template
+	Count      int64     `json:"count"`
+	AvgMs      float64   `json:"avg_ms"`
+	P95Ms      float64   `json:"p95_ms"`
+	MaxMs      float64   `json:"max_ms"`
+	OverBudget int64     `json:"over_budget"` // requests slower than the latency budget
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// SlowQuery is the daily summary of one SQL statement shape with its plan
+type SlowQuery struct {
+	ID        int       `json:"-" gorm:"primaryKey"`
+	Day       string    `json:"day" gorm:"uniqueIndex:idx_slow_query;not null"`
+	Hash      string    `json:"hash" gorm:"uniqueIndex:idx_slow_query;not null"`
+	SQL       string    `json:"sql" gorm:"type:text"`
+	Count     int64     `json:"count"`
+	AvgMs     float64   `json:"avg_ms"`
+	MaxMs     float64   `json:"max_ms"`
+	Plan      string    `json:"plan" gorm:"type:text"` // EXPLAIN QUERY PLAN output
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// LiveScore is a snapshot of an in-progress match taken from a live
+// scoreboard stream. A row is recorded each time the score, the status or the
+// competitors change, so the rows of a match form its score timeline.
+type LiveScore struct {
+	ID           int    `json:"id" gorm:"primaryKey"`
+	EventID      string `json:"event_id" gorm:"index:idx_live_score_match;not null"`
+	MatchID      string `json:"match_id" gorm:"index:idx_live_score_match;not null"` // match ID used by the stream
+	Mat          string `json:"mat,omitempty"`
+	Status       string `json:"status,omitempty"` // as sent by the stream, e.g. "running", "paused", "finished"
+	Winner       int    `json:"winner,omitempty"` // 1 (side A) or 2 (side B) when the stream names it
+	AthleteAID   uint   `json:"athlete_a_id,omitempty" gorm:"index"`
+	AthleteAName string `json:"athlete_a_name"`
+	AthleteBID   uint   `json:"athlete_b_id,omitempty" gorm:"index"`
+	AthleteBName string `json:"athlete_b_name"`
+
+	PointsA     int `json:"points_a"`
+	PointsB     int `json:"points_b"`
+	AdvantagesA int `json:"advantages_a"`
+	AdvantagesB int `json:"advantages_b"`
+	PenaltiesA  int `json:"penalties_a"`
+	PenaltiesB  int `json:"penalties_b"`
+
+	ClockSeconds int       `json:"clock_seconds,omitempty"` // time left on the clock when recorded
+	RecordedAt   time.Time `json:"recorded_at" gorm:"index"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Match is a single bout between two competitors of an event bracket
+type Match struct {
+	ID           int    `json:"id" gorm:"primaryKey"`
+	ExternalID   string `json:"external_id" gorm:"index"`
+	EventID      string `json:"event_id" gorm:"index;not null"`
+	EventName    string `json:"event_name"`
+	DivisionID   string `json:"division_id,omitempty" gorm:"index"` // bracket the match belongs to
+	Category     string `json:"category"`                           // e.g. "Adult / Male / Black / -76 kg"
+	Round        string `json:"round"`
+	Mat          string `json:"mat,omitempty"`
+	AthleteAID   uint   `json:"athlete_a_id" gorm:"index"`
+	AthleteAName string `json:"athlete_a_name"`
+	AthleteBID   uint   `json:"athlete_b_id" gorm:"index"`
+	AthleteBName string `json:"athlete_b_name"`
+	WinnerID     uint   `json:"winner_id" gorm:"index"`
+	WinnerName   string `json:"winner_name"`
+	Method       string `json:"method"`    // submission, points, decision, dq
+	Technique    string `json:"technique"` // finishing technique, e.g. "Armbar"
+
+	// Registrations of each side in the event, when they were scraped
+	RegistrationAID *uint `json:"registration_a_id,omitempty" gorm:"index"`
+	RegistrationBID *uint `json:"registration_b_id,omitempty" gorm:"index"`
+
+	// Score as points / advantages / penalties of each side
+	PointsA     int `json:"points_a"`
+	PointsB     int `json:"points_b"`
+	AdvantagesA int `json:"advantages_a"`
+	AdvantagesB int `json:"advantages_b"`
+	PenaltiesA  int `json:"penalties_a"`
+	PenaltiesB  int `json:"penalties_b"`
+
+	DurationSeconds int        `json:"duration_seconds,omitempty"` // time on the clock when the match ended
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+
+	// Bracket endpoint the match was read from and when, to check disputed
+	// results against Smoothcomp
+	SourceURL string     `json:"source_url,omitempty"`
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Videos []MatchVideo `json:"videos,omitempty" gorm:"foreignKey:MatchID"`
+}
+
+// MatchVideo is a YouTube video linked to a match as a candidate recording
+type MatchVideo struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	MatchID      int       `json:"match_id" gorm:"uniqueIndex:idx_match_video;not null"`
+	VideoID      string    `json:"video_id" gorm:"uniqueIndex:idx_match_video;not null"`
+	ChannelID    string    `json:"channel_id"`
+	ChannelTitle string    `json:"channel_title"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	PublishedAt  time.Time `json:"published_at"`
+	Confidence   float64   `json:"confidence"`                        // 0..1
+	Status       string    `json:"status" gorm:"default:'candidate'"` // "candidate", "confirmed", "rejected"
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
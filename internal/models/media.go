@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// MediaAsset is an image fetched through the media proxy and stored on disk,
+// addressed by the SHA-256 of its content
+type MediaAsset struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	SourceURL   string    `json:"source_url" gorm:"uniqueIndex;not null"`
+	Hash        string    `json:"hash" gorm:"index;not null"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// MediaManifestEntry describes one asset of the offline media bundle
+type MediaManifestEntry struct {
+	Kind        string `json:"kind"` // "academy_logo", "country_flag"
+	Key         string `json:"key"`  // academy external ID or country code
+	SourceURL   string `json:"source_url"`
+	URL         string `json:"url"`
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// MediaManifest lists cached assets; Version changes whenever any asset does
+type MediaManifest struct {
+	Version     string               `json:"version"`
+	GeneratedAt time.Time            `json:"generated_at"`
+	Entries     []MediaManifestEntry `json:"entries"`
+	Missing     int                  `json:"missing"`
+}
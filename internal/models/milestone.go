@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Milestone kinds
+const (
+	MilestoneWins             = "wins"              // total wins reached Value
+	MilestoneWinStreak        = "win_streak"        // Value wins in a row
+	MilestoneSubmissionStreak = "submission_streak" // Value submission wins in a row
+)
+
+// AthleteStreak is the win record of an athlete computed from the stored
+// bracket matches, refreshed after every bracket scrape that involves them
+type AthleteStreak struct {
+	AthleteID               uint      `json:"athlete_id" gorm:"primaryKey;autoIncrement:false"`
+	Matches                 int       `json:"matches"` // decided matches
+	Wins                    int       `json:"wins"`
+	SubmissionWins          int       `json:"submission_wins"`
+	CurrentWinStreak        int       `json:"current_win_streak"`
+	LongestWinStreak        int       `json:"longest_win_streak"`
+	CurrentSubmissionStreak int       `json:"current_submission_streak"`
+	LongestSubmissionStreak int       `json:"longest_submission_streak"`
+	ComputedAt              time.Time `json:"computed_at"`
+}
+
+// AthleteMilestone is a milestone an athlete reached at a match, such as the
+// 100th win or a 10 match win streak
+type AthleteMilestone struct {
+	ID        int        `json:"id" gorm:"primaryKey"`
+	AthleteID uint       `json:"athlete_id" gorm:"not null;uniqueIndex:idx_athlete_milestone"`
+	Kind      string     `json:"kind" gorm:"not null;uniqueIndex:idx_athlete_milestone"`
+	Value     int        `json:"value" gorm:"not null;uniqueIndex:idx_athlete_milestone"`
+	MatchID   int        `json:"match_id" gorm:"not null;uniqueIndex:idx_athlete_milestone"`
+	EventID   string     `json:"event_id"`
+	EventName string     `json:"event_name"`
+	ReachedAt *time.Time `json:"reached_at,omitempty"` // match start, when known
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// QueuedJob is a scrape requested through the API, stored until a queue
+// worker runs it so that pending work survives restarts
+type QueuedJob struct {
+	ID          int         `json:"id" gorm:"primaryKey"`
+	JobType     string      `json:"job_type" gorm:"index"` // see queue.JobTypes
+	Params      QueueParams `json:"params" gorm:"serializer:json;type:text"`
+	Status      string      `json:"status" gorm:"index"` // "queued", "running", "postponed", "completed", "failed", "cancelled"
+	Attempts    int         `json:"attempts"`            // runs started, restarts and postponements excluded
+	Postpones   int         `json:"postpones"`           // runs stopped by a Smoothcomp maintenance page
+	MaxAttempts int         `json:"max_attempts"`
+	RunAfter    time.Time   `json:"run_after" gorm:"index"` // not picked before this time (retry backoff)
+	LastError   string      `json:"last_error,omitempty" gorm:"type:text"`
+	Owner       string      `json:"owner,omitempty" gorm:"index"` // process running the job (QUEUE_WORKER_ID or host name)
+	HeartbeatAt *time.Time  `json:"heartbeat_at,omitempty"`       // refreshed while running; a stale one means the process died
+	StartedAt   *time.Time  `json:"started_at,omitempty"`
+	FinishedAt  *time.Time  `json:"finished_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// QueueParams are the arguments of a queued job; which apply depends on the
+// job type
+type QueueParams struct {
+	Country     string   `json:"country,omitempty"`       // events_*, country_roster
+	Depth       string   `json:"depth,omitempty"`         // events_*
+	MaxDuration int      `json:"max_duration,omitempty"`  // events_*, enrich; seconds
+	Resume      string   `json:"resume,omitempty"`        // events_*, enrich; resume token
+	Debug       bool     `json:"debug,omitempty"`         // events_*, enrich
+	Profile     string   `json:"profile,omitempty"`       // events_*, enrich; behavior preset
+	Limit       int      `json:"limit,omitempty"`         // enrich, athletes to enrich
+	Offset      int      `json:"offset,omitempty"`        // enrich
+	OnlyMissing bool     `json:"only_missing,omitempty"`  // enrich, skip athletes already enriched
+	EventID     string   `json:"event_id,omitempty"`      // event_*
+	EventName   string   `json:"event_name,omitempty"`    // event_athletes
+	EventURL    string   `json:"event_url,omitempty"`     // event_*
+	ResumeJobID int      `json:"resume_job_id,omitempty"` // all, country_roster; failed pipeline to continue
+	Federation  string   `json:"federation,omitempty"`    // team_rankings, subdomain
+	Season      string   `json:"season,omitempty"`        // team_rankings; empty for the current one
+	AcademyID   string   `json:"academy_id,omitempty"`    // academy_stats (empty for every academy), academy_resync; external ID
+	AthleteID   string   `json:"athlete_id,omitempty"`    // athlete_resync, external ID
+	Events      []string `json:"events,omitempty"`        // events_bulk, event IDs or URLs
+}
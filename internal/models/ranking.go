@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RankingEntry is the standing of an athlete in one division, computed from
+// stored podium results by the rankings package. Entries are rebuilt as a
+// whole on every recompute.
+type RankingEntry struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	Division          string    `json:"division" gorm:"index"` // as listed on results, e.g. "Men / Adults / Blue / -76 kg"
+	Belt              string    `json:"belt" gorm:"index"`     // belt color taken from the division, e.g. "Blue"
+	Country           string    `json:"country" gorm:"index"`  // ISO code of the athlete, or of the academy
+	Rank              int       `json:"rank"`                  // within the division; ties share a rank
+	AthleteID         uint      `json:"athlete_id" gorm:"index"`
+	AthleteExternalID string    `json:"athlete_external_id" gorm:"index"`
+	AthleteName       string    `json:"athlete_name"`
+	AcademyExternalID string    `json:"academy_external_id,omitempty"`
+	AcademyName       string    `json:"academy_name,omitempty"`
+	Gold              int       `json:"gold"`
+	Silver            int       `json:"silver"`
+	Bronze            int       `json:"bronze"`
+	Points            float64   `json:"points"`
+	ComputedAt        time.Time `json:"computed_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Podium medals by placement
+const (
+	MedalGold   = "gold"
+	MedalSilver = "silver"
+	MedalBronze = "bronze"
+)
+
+// EventResult is a podium placement (1st to 4th) of an athlete in an event
+// division, as published on the event results page
+type EventResult struct {
+	ID                int       `json:"id" gorm:"primaryKey"`
+	EventID           string    `json:"event_id" gorm:"index;not null"`
+	EventName         string    `json:"event_name"`
+	Division          string    `json:"division" gorm:"index"`   // e.g. "Men / Adults / Blue / -76 kg"
+	Placement         int       `json:"placement"`               // 1-4; brackets may award two bronzes
+	Medal             string    `json:"medal,omitempty"`         // gold, silver, bronze
+	AthleteID         uint      `json:"athlete_id" gorm:"index"` // 0 when the athlete is not stored
+	AthleteExternalID string    `json:"athlete_external_id"`
+	AthleteName       string    `json:"athlete_name"`
+	AcademyExternalID string    `json:"academy_external_id,omitempty"`
+	AcademyName       string    `json:"academy_name,omitempty"`
+	ScrapedAt         time.Time `json:"scraped_at"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Results page the placement was read from and when, to check disputed
+	// results against Smoothcomp
+	SourceURL string     `json:"source_url,omitempty"`
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+}
+
+// MedalForPlacement returns the medal awarded for a placement, empty for 4th
+func MedalForPlacement(placement int) string {
+	switch placement {
+	case 1:
+		return MedalGold
+	case 2:
+		return MedalSilver
+	case 3:
+		return MedalBronze
+	}
+	return ""
+}
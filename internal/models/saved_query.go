@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Saved query targets
+const (
+	SavedQueryAthletes      = "athletes"
+	SavedQueryRegistrations = "registrations"
+)
+
+// SavedQueryFilters are the filters of a saved query. Text filters match
+// case-insensitively as substrings, so "purple" matches "Purple belt".
+type SavedQueryFilters struct {
+	Country     string `json:"country,omitempty"`      // athlete country code
+	AcademyID   string `json:"academy_id,omitempty"`   // academy external ID
+	Gender      string `json:"gender,omitempty"`       // male, female
+	Belt        string `json:"belt,omitempty"`         // athlete belt, or registration rank
+	AgeCategory string `json:"age_category,omitempty"` // registrations only
+	WeightClass string `json:"weight_class,omitempty"` // registrations only
+	EventID     string `json:"event_id,omitempty"`     // registrations only
+	Kids        *bool  `json:"kids,omitempty"`         // registrations only
+}
+
+// SavedQuery is a named filter set that can be run again, e.g.
+// "CL adult purple -76". With a webhook it notifies when the rows it
+// selects change after a scrape.
+type SavedQuery struct {
+	ID         int               `json:"id" gorm:"primaryKey"`
+	Name       string            `json:"name" gorm:"not null"`
+	Target     string            `json:"target" gorm:"not null"` // athletes, registrations
+	Filters    SavedQueryFilters `json:"filters" gorm:"serializer:json;type:text"`
+	WebhookURL string            `json:"webhook_url,omitempty"`
+
+	// Result of the last check, to detect changes
+	ResultCount   int        `json:"result_count"`
+	ResultIDs     []uint     `json:"-" gorm:"serializer:json;type:text"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastChangedAt *time.Time `json:"last_changed_at,omitempty"`
+
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsSavedQueryTarget reports whether t is a saved query target
+func IsSavedQueryTarget(t string) bool {
+	return t == SavedQueryAthletes || t == SavedQueryRegistrations
+}
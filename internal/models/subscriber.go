@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// NotificationSubscriber receives a push for every newly discovered event
+// that matches its filter, e.g. "federation=AJP AND country in [CL,AR] AND gi=true"
+type NotificationSubscriber struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"not null"`
+	WebhookURL string    `json:"webhook_url" gorm:"not null"`
+	Filter     string    `json:"filter"` // empty matches every new event
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// SuppressedAthlete is an athlete whose personal data was removed on request.
+// Scrapers keep updating its statistics but never store its personal fields again.
+type SuppressedAthlete struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	ExternalID string    `json:"external_id" gorm:"uniqueIndex;not null"`
+	AthleteID  int       `json:"athlete_id" gorm:"index"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AcademyContactOptOut is an academy that asked not to be contacted. Its
+// contact details are cleared and scrapers never store them again.
+type AcademyContactOptOut struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	ExternalID string    `json:"external_id" gorm:"uniqueIndex;not null"` // academy external ID
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Taggable entity types
+const (
+	TaggedAthlete = "athlete"
+	TaggedAcademy = "academy"
+)
+
+// MaxTagLength is the longest tag accepted, in characters
+const MaxTagLength = 64
+
+// EntityTag is a user-defined label on an athlete or academy, such as
+// "national team" or "scholarship candidate". Tags reference the Smoothcomp
+// external ID and live apart from scraped rows, so re-scrapes keep them.
+type EntityTag struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"not null;uniqueIndex:idx_entity_tag"` // athlete, academy
+	ExternalID string    `json:"external_id" gorm:"not null;uniqueIndex:idx_entity_tag"`
+	Tag        string    `json:"tag" gorm:"not null;uniqueIndex:idx_entity_tag;index"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IsTaggedEntityType reports whether t is a taggable entity type
+func IsTaggedEntityType(t string) bool {
+	return t == TaggedAthlete || t == TaggedAcademy
+}
+
+// NormalizeTag lowercases a tag and collapses its whitespace, so "National
+// Team" and "national  team" are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
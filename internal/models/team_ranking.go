@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TeamRankingEntry is the standing of an academy in the club ranking a
+// federation subdomain publishes for a season. An entry is only stored when
+// the rank or points differ from the previous one of that academy, so
+// consecutive rows show its trajectory.
+type TeamRankingEntry struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	Federation        string    `json:"federation" gorm:"not null;index:idx_team_ranking"` // subdomain, e.g. "ajp"
+	Season            string    `json:"season" gorm:"not null;index:idx_team_ranking"`     // as published, e.g. "2025" or "2024/2025"
+	AcademyExternalID string    `json:"academy_external_id" gorm:"not null;index:idx_team_ranking"`
+	AcademyID         uint      `json:"academy_id,omitempty" gorm:"index"` // stored academy, when scraped
+	AcademyName       string    `json:"academy_name"`
+	Rank              int       `json:"rank"`
+	Points            float64   `json:"points"`
+	CapturedAt        time.Time `json:"captured_at" gorm:"not null"`
+}
+
+// SameStanding reports whether two entries hold the same rank and points
+func (e TeamRankingEntry) SameStanding(other TeamRankingEntry) bool {
+	return e.Rank == other.Rank && e.Points == other.Points
+}
@@ -7,9 +7,10 @@ type Academy struct {
 	ID          int    `json:"id" gorm:"primaryKey"`
 	ExternalID  string `json:"external_id" gorm:"uniqueIndex;not null"`
 	Name        string `json:"name" gorm:"not null"`
-	Slug        string `json:"slug"`
+	Slug        string `json:"slug" gorm:"uniqueIndex:idx_academies_slug_unique,where:slug <> ''"`
+	ClubURL     string `json:"club_url"`
 	Country     string `json:"country"`
-	CountryCode string `json:"country_code"`
+	CountryCode string `json:"country_code" gorm:"index:idx_academy_normalized_name,priority:1"`
 	LogoURL     string `json:"logo_url"`
 	CoverURL    string `json:"cover_url"`
 	Bio         string `json:"bio" gorm:"type:text"`
@@ -17,6 +18,15 @@ type Academy struct {
 	Instagram   string `json:"instagram"`
 	Facebook    string `json:"facebook"`
 
+	// Contact details from the club page, limited by ACADEMY_CONTACT_FIELDS
+	// and only served by the admin outreach listing
+	ContactEmail   string `json:"-"`
+	ContactPhone   string `json:"-"`
+	ContactAddress string `json:"-"`
+
+	// Accent- and case-insensitive name; near-duplicates share it within a country
+	NormalizedName string `json:"-" gorm:"index:idx_academy_normalized_name,priority:2"`
+
 	// Statistics
 	TotalWins    int `json:"total_wins"`
 	TotalLosses  int `json:"total_losses"`
@@ -25,6 +35,10 @@ type Academy struct {
 	SilverMedals int `json:"silver_medals"`
 	BronzeMedals int `json:"bronze_medals"`
 
+	// Set once the statistics are aggregated from our own data (see
+	// academystats); the club page no longer overwrites them afterwards
+	StatsComputedAt *time.Time `json:"stats_computed_at,omitempty"`
+
 	// Metadata
 	ScrapedAt time.Time `json:"scraped_at"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -41,12 +55,13 @@ type Athlete struct {
 	FirstName         string `json:"first_name" gorm:"not null"`
 	LastName          string `json:"last_name" gorm:"not null"`
 	FullName          string `json:"full_name"`
+	Slug              string `json:"slug" gorm:"uniqueIndex:idx_athletes_slug_unique,where:slug <> ''"`
 	AcademyExternalID string `json:"academy_external_id"`
 	Nationality       string `json:"nationality"`
 	CountryCode       string `json:"country_code"`
-	BeltRank          string `json:"belt_rank"`
-	Age               int    `json:"age"`
-	Gender            string `json:"gender"`
+	BeltRank          string `json:"belt_rank" gorm:"index"`
+	Age               int    `json:"age" gorm:"index"`
+	Gender            Gender `json:"gender" gorm:"index"` // male, female (see ParseGender)
 	ProfileURL        string `json:"profile_url"`
 	AvatarURL         string `json:"avatar_url"`
 
@@ -56,7 +71,7 @@ type Athlete struct {
 	AffiliationName string `json:"affiliation_name"` // Afiliación (opcional)
 
 	// Win Statistics
-	TotalWins        int `json:"total_wins"`
+	TotalWins        int `json:"total_wins" gorm:"index"`
 	WinsBySubmission int `json:"wins_by_submission"`
 	WinsByPoints     int `json:"wins_by_points"`
 	WinsByDecision   int `json:"wins_by_decision"`
@@ -70,29 +85,40 @@ type Athlete struct {
 	LossesByDQ         int `json:"losses_by_dq"`
 
 	// Metadata
-	ScrapedAt time.Time `json:"scraped_at"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ScrapedAt        time.Time  `json:"scraped_at" gorm:"index"`
+	ProfileScrapedAt *time.Time `json:"profile_scraped_at,omitempty" gorm:"index"` // last profile enrichment
+	FirstSeenAt      time.Time  `json:"first_seen_at" gorm:"index;autoCreateTime"` // first stored; the earliest of merged accounts
+	LastActiveAt     *time.Time `json:"last_active_at,omitempty" gorm:"index"`     // start day of the latest event registered in
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	Academy            *Academy            `json:"academy,omitempty" gorm:"foreignKey:AcademyExternalID;references:ExternalID"`
 	EventRegistrations []EventRegistration `json:"event_registrations,omitempty" gorm:"foreignKey:AthleteID"`
+	FederationIDs      []FederationID      `json:"federation_ids,omitempty" gorm:"foreignKey:AthleteExternalID;references:ExternalID"`
 }
 
 // Event represents a SmoothComp event card
 type Event struct {
-	ID          int    `json:"id" gorm:"primaryKey"`
-	ExternalID  string `json:"external_id" gorm:"index"`
-	Name        string `json:"name" gorm:"not null"`
-	EventURL    string `json:"event_url" gorm:"uniqueIndex;not null"`
-	ImageURL    string `json:"image_url"`
-	City        string `json:"city"`
-	Country     string `json:"country"`
-	CountryCode string `json:"country_code"`
-	DateText    string `json:"date_text"`
-	DaysText    string `json:"days_text"`
-	EventType   string `json:"event_type"`
-	Section     string `json:"section"`
+	ID          int        `json:"id" gorm:"primaryKey"`
+	ExternalID  string     `json:"external_id" gorm:"index"`
+	Name        string     `json:"name" gorm:"not null"`
+	EventURL    string     `json:"event_url" gorm:"uniqueIndex;not null"`
+	ImageURL    string     `json:"image_url"`
+	City        string     `json:"city"`
+	Country     string     `json:"country"`
+	CountryCode string     `json:"country_code"`
+	DateText    string     `json:"date_text"`
+	StartDate   *time.Time `json:"start_date,omitempty" gorm:"index"` // start day, from the event listing
+	DaysText    string     `json:"days_text"`
+	EventType   string     `json:"event_type"`
+	Section     string     `json:"section"`
+
+	// Set once a results scrape finds placements. Until then past events
+	// are scraped again at ResultsRecheckAt, see scraper.trackResults.
+	ResultsPublished bool       `json:"results_published"`
+	ResultsChecks    int        `json:"results_checks"` // results scrapes that found nothing
+	ResultsRecheckAt *time.Time `json:"results_recheck_at,omitempty" gorm:"index"`
 
 	ScrapedAt time.Time `json:"scraped_at"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -123,22 +149,68 @@ type EventDetail struct {
 
 // ScrapeJob represents a scraping job execution
 type ScrapeJob struct {
-	ID           int        `json:"id" gorm:"primaryKey"`
-	JobType      string     `json:"job_type"` // "academies", "athletes", "all"
-	Status       string     `json:"status"`   // "running", "completed", "failed"
-	StartedAt    time.Time  `json:"started_at"`
-	CompletedAt  *time.Time `json:"completed_at,omitempty"`
-	ItemsScraped int        `json:"items_scraped"`
-	ErrorMessage string     `json:"error_message,omitempty" gorm:"type:text"`
-	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	ID             int        `json:"id" gorm:"primaryKey"`
+	ParentJobID    *int       `json:"parent_job_id,omitempty" gorm:"index"` // set on chunks of a split job
+	JobType        string     `json:"job_type"`                             // "academies", "athletes", "all"
+	Depth          string     `json:"depth,omitempty"`                      // "listing", "details", "participants", "profiles", "brackets"
+	Status         string     `json:"status"`                               // "pending", "running", "completed", "partial", "failed", "cancelled", "postponed"
+	StartedAt      time.Time  `json:"started_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	ItemsScraped   int        `json:"items_scraped"`
+	PagesFailed    int        `json:"pages_failed,omitempty"`    // pages that still failed after retries
+	CurrentPhase   string     `json:"current_phase,omitempty"`   // step the running job is in, e.g. "profiles"
+	ItemsTotal     int        `json:"items_total,omitempty"`     // items of the current phase, 0 while unknown
+	ItemsProcessed int        `json:"items_processed,omitempty"` // items of the current phase done so far
+	ErrorMessage   string     `json:"error_message,omitempty" gorm:"type:text"`
+	MaxDuration    int        `json:"max_duration,omitempty"` // seconds; the job stops as "partial" when exceeded
+	ResumeToken    string     `json:"resume_token,omitempty"` // continues a partial job
+	Attempts       int        `json:"attempts,omitempty"`     // runs of a retried pipeline stage
+	Recording      string     `json:"recording,omitempty"`    // file of recorded requests, for debug runs
+	Profile        string     `json:"profile,omitempty"`      // behavior profile the job ran with
+	Country        string     `json:"country,omitempty"`      // country of a country roster pipeline
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	Coverage []FieldCoverage `json:"coverage,omitempty" gorm:"foreignKey:JobID"` // fields found by the job's parsers
 }
 
-// ScheduleConfig represents the cron schedule configuration
+// ScheduleConfig is one named cron schedule; each runs its job type with its
+// own parameters, independently of the others
 type ScheduleConfig struct {
-	ID        int       `json:"id" gorm:"primaryKey"`
-	CronExpr  string    `json:"cron_expr" gorm:"not null"`
-	Enabled   bool      `json:"enabled" gorm:"default:true"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID        int            `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name"`
+	CronExpr  string         `json:"cron_expr" gorm:"not null"`
+	JobType   string         `json:"job_type" gorm:"default:'all'"` // see scheduler.JobTypes
+	Params    ScheduleParams `json:"params" gorm:"serializer:json;type:text"`
+	Enabled   bool           `json:"enabled" gorm:"default:true"`
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Filled from the running scheduler, not stored
+	NextRun        *time.Time `json:"next_run,omitempty" gorm:"-"`
+	Running        bool       `json:"running" gorm:"-"`
+	PostponedUntil *time.Time `json:"postponed_until,omitempty" gorm:"-"` // run stopped by Smoothcomp maintenance
+}
+
+// ScheduleParams are the job parameters of a schedule; which apply depends
+// on the job type
+type ScheduleParams struct {
+	Country     string `json:"country,omitempty"`      // events_*; empty runs every TARGET_COUNTRIES entry
+	Depth       string `json:"depth,omitempty"`        // events_*
+	MaxDuration int    `json:"max_duration,omitempty"` // events_*, seconds
+	Profile     string `json:"profile,omitempty"`      // events_*, behavior preset
+	Limit       int    `json:"limit,omitempty"`        // enrich_stale; 0 uses ENRICH_STALE_BATCH
+	MaxAgeDays  int    `json:"max_age_days,omitempty"` // enrich_stale; 0 uses ENRICH_STALE_DAYS
+}
+
+// ScheduleAudit records who changed a schedule configuration and how
+type ScheduleAudit struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	ScheduleID int       `json:"schedule_id" gorm:"index"`
+	Action     string    `json:"action"` // "create", "update", "delete", "enable", "disable"
+	Actor      string    `json:"actor"`
+	Before     string    `json:"before,omitempty" gorm:"type:text"`
+	After      string    `json:"after,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
 // API Response structures
@@ -155,29 +227,54 @@ type HealthResponse struct {
 	Version   string    `json:"version"`
 }
 
+// JobProgress is how far along a job is, with its child jobs (pipeline
+// stages, enrichment chunks)
+type JobProgress struct {
+	JobID          int           `json:"job_id"`
+	JobType        string        `json:"job_type"`
+	Status         string        `json:"status"`
+	CurrentPhase   string        `json:"current_phase,omitempty"`
+	ItemsTotal     int           `json:"items_total"`
+	ItemsProcessed int           `json:"items_processed"`
+	ItemsScraped   int           `json:"items_scraped"`
+	Percent        *float64      `json:"percent,omitempty"` // nil while the total is unknown
+	ElapsedSeconds int           `json:"elapsed_seconds"`
+	ETASeconds     *int          `json:"eta_seconds,omitempty"` // linear estimate for running jobs
+	Children       []JobProgress `json:"children,omitempty"`
+}
+
 type StatusResponse struct {
-	LastRun         *time.Time `json:"last_run,omitempty"`
-	NextRun         *time.Time `json:"next_run,omitempty"`
-	IsRunning       bool       `json:"is_running"`
-	ScheduleEnabled bool       `json:"schedule_enabled"`
-	CronExpression  string     `json:"cron_expression"`
-	TotalAcademies  int64      `json:"total_academies"`
-	TotalAthletes   int64      `json:"total_athletes"`
+	LastRun         *time.Time       `json:"last_run,omitempty"`
+	NextRun         *time.Time       `json:"next_run,omitempty"`
+	IsRunning       bool             `json:"is_running"`
+	ScheduleEnabled bool             `json:"schedule_enabled"` // any schedule enabled
+	Schedules       []ScheduleConfig `json:"schedules"`
+	TotalAcademies  int64            `json:"total_academies"`
+	TotalAthletes   int64            `json:"total_athletes"`
+	HTTPConnections interface{}      `json:"http_connections,omitempty"`
+	FetchCache      interface{}      `json:"fetch_cache,omitempty"`
 }
 
 // EventRegistration representa la inscripción de un atleta en un evento
 type EventRegistration struct {
 	ID               uint      `json:"id" gorm:"primaryKey"`
-	AthleteID        uint      `json:"athlete_id" gorm:"not null;index"`
-	EventID          string    `json:"event_id" gorm:"not null;index"`
+	AthleteID        uint      `json:"athlete_id" gorm:"not null;index;uniqueIndex:idx_registration_key"`
+	EventID          string    `json:"event_id" gorm:"not null;index;uniqueIndex:idx_registration_key"`
 	EventName        string    `json:"event_name" gorm:"not null"`
-	Division         string    `json:"division" gorm:"not null"`     // Men/Women
-	AgeCategory      string    `json:"age_category" gorm:"not null"` // Adults/Masters/Juveniles
-	Rank             string    `json:"rank" gorm:"not null"`         // Beginner/Intermediate/Advanced
-	WeightClass      string    `json:"weight_class" gorm:"not null"` // -60 kg, -65 kg
-	ActualWeight     float64   `json:"actual_weight"`                // Peso real en el pesaje
-	Seed             int       `json:"seed" gorm:"default:0"`        // Seed en el bracket
-	Ranking          int       `json:"ranking" gorm:"default:0"`     // Ranking global
+	DivisionID       *string   `json:"division_id,omitempty" gorm:"uniqueIndex:idx_registration_key"` // Smoothcomp division (bracket group) ID; nil for legacy rows
+	Division         string    `json:"division" gorm:"not null"`                                      // Men/Women, as listed by the event
+	Gender           Gender    `json:"gender" gorm:"index"`                                           // normalized division gender
+	IsKids           bool      `json:"is_kids"`                                                       // Boys/Girls/Kids divisions
+	AgeCategory      string    `json:"age_category" gorm:"not null"`                                  // Adults/Masters/Juveniles
+	Rank             string    `json:"rank" gorm:"not null"`                                          // Beginner/Intermediate/Advanced
+	WeightClass      string    `json:"weight_class" gorm:"not null"`                                  // -60 kg, -65 kg
+	WeightClassKey   string    `json:"weight_class_key" gorm:"index"`                                 // normalized weight class (see ParseWeightClass)
+	WeightMinKg      float64   `json:"weight_min_kg,omitempty"`                                       // lower limit of open-ended classes
+	WeightMaxKg      float64   `json:"weight_max_kg,omitempty"`                                       // weight limit in kg
+	Style            Style     `json:"style" gorm:"index"`                                            // gi/nogi, when the category names it
+	ActualWeight     float64   `json:"actual_weight"`                                                 // Peso real en el pesaje
+	Seed             int       `json:"seed" gorm:"default:0"`                                         // Seed en el bracket
+	Ranking          int       `json:"ranking" gorm:"default:0"`                                      // Ranking global
 	EventCardURL     string    `json:"event_card_url"`
 	RegistrationDate time.Time `json:"registration_date"`
 	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+)
+
+// Style is whether a division is fought with or without the gi
+type Style string
+
+const (
+	StyleUnknown Style = ""
+	StyleGi      Style = "gi"
+	StyleNoGi    Style = "nogi"
+)
+
+const kgPerLb = 0.45359237
+
+// weightLimitPattern reads "-76 kg", "+100kg", "-170.5 lbs" or a bare "-76"
+var weightLimitPattern = regexp.MustCompile(`(?i)([+-])\s*(\d+(?:[.,]\d+)?)\s*(kg|kgs|lb|lbs)?`)
+
+// namedClasses maps the named weight classes, in English, Portuguese and
+// Spanish, to their English name
+var namedClasses = map[string]string{
+	"rooster": "rooster", "galo": "rooster", "gallo": "rooster",
+	"light feather": "light feather", "lightfeather": "light feather", "pluma": "light feather",
+	"feather": "feather", "pena": "feather",
+	"light": "light", "leve": "light", "ligero": "light", "liviano": "light",
+	"middle": "middle", "medio": "middle", "mediano": "middle",
+	"medium heavy": "medium heavy", "meio pesado": "medium heavy", "medio pesado": "medium heavy",
+	"heavy": "heavy", "pesado": "heavy",
+	"super heavy": "super heavy", "super pesado": "super heavy",
+	"ultra heavy": "ultra heavy", "pesadissimo": "ultra heavy", "ultra pesado": "ultra heavy",
+	"open": "open", "open class": "open", "absolute": "open", "absoluto": "open", "openweight": "open",
+}
+
+var styleWords = map[string]Style{
+	"gi": StyleGi, "kimono": StyleGi, "com kimono": StyleGi, "con kimono": StyleGi,
+	"nogi": StyleNoGi, "no gi": StyleNoGi, "sem kimono": StyleNoGi, "sin kimono": StyleNoGi,
+}
+
+// WeightClass is a normalized weight class label. Key is "-76 kg" or
+// "+100 kg" when the label has a limit (lbs are converted to kg), the English
+// class name ("rooster", "open") otherwise, and empty when unrecognized.
+type WeightClass struct {
+	Key   string  `json:"key"`
+	MinKg float64 `json:"min_kg,omitempty"` // open-ended classes ("+100 kg")
+	MaxKg float64 `json:"max_kg,omitempty"`
+}
+
+// HasLimit reports whether the class has a weight limit
+func (c WeightClass) HasLimit() bool {
+	return c.MinKg > 0 || c.MaxKg > 0
+}
+
+// ParseWeightClass normalizes a weight class label ("-76 kg", "-167.5 lbs",
+// "Medio / -82.3 kg", "Pesadissimo", "Absolute")
+func ParseWeightClass(label string) WeightClass {
+	if match := weightLimitPattern.FindStringSubmatch(label); match != nil {
+		value, err := strconv.ParseFloat(strings.Replace(match[2], ",", ".", 1), 64)
+		if err == nil && value > 0 {
+			if strings.HasPrefix(strings.ToLower(match[3]), "lb") {
+				value = math.Round(value*kgPerLb*10) / 10
+			}
+			key := match[1] + strconv.FormatFloat(value, 'f', -1, 64) + " kg"
+			if match[1] == "+" {
+				return WeightClass{Key: key, MinKg: value}
+			}
+			return WeightClass{Key: key, MaxKg: value}
+		}
+	}
+
+	words := labelWords(label)
+	for n := len(words); n > 0; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			if name, ok := namedClasses[strings.Join(words[i:i+n], " ")]; ok {
+				return WeightClass{Key: name}
+			}
+		}
+	}
+	return WeightClass{}
+}
+
+// NormalizeWeightClass fills the normalized weight class fields of a
+// registration from its WeightClass label
+func (r *EventRegistration) NormalizeWeightClass() {
+	class := ParseWeightClass(r.WeightClass)
+	r.WeightClassKey = class.Key
+	r.WeightMinKg = class.MinKg
+	r.WeightMaxKg = class.MaxKg
+}
+
+// ParseStyle reads whether a category or division label is gi or no-gi
+// ("No-Gi", "NoGi", "Sem Kimono", "Gi"). Labels naming neither return
+// StyleUnknown.
+func ParseStyle(label string) Style {
+	words := labelWords(label)
+	for i := range words {
+		if i+1 < len(words) {
+			if style, ok := styleWords[words[i]+" "+words[i+1]]; ok {
+				return style
+			}
+		}
+		if style, ok := styleWords[words[i]]; ok {
+			return style
+		}
+	}
+	return StyleUnknown
+}
+
+// IsStyleLabel reports whether a category segment only names the style
+// ("No-Gi", "Gi", "Sem Kimono")
+func IsStyleLabel(label string) bool {
+	_, ok := styleWords[strings.Join(labelWords(label), " ")]
+	return ok
+}
+
+// labelWords lowercases a label without accents and splits it into words
+func labelWords(label string) []string {
+	label = slug.Fold(label)
+	return strings.FieldsFunc(label, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/' || r == '_' || r == '(' || r == ')' || r == ','
+	})
+}
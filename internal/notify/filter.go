@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// Filter fields a subscriber can match events on
+const (
+	FieldFederation = "federation"
+	FieldCountry    = "country"
+	FieldGi         = "gi"
+	FieldType       = "type"
+)
+
+// federations are detected as whole words in the event name and organizer;
+// the first match wins
+var federations = []string{"AJP", "UAEJJF", "IBJJF", "SJJIF", "JJIF", "CBJJ", "ADCC", "NAGA", "GRAPPLING INDUSTRIES"}
+
+var federationPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(federations))
+	for i, federation := range federations {
+		patterns[i] = regexp.MustCompile(`\b` + federation + `\b`)
+	}
+	return patterns
+}()
+
+var (
+	noGiPattern = regexp.MustCompile(`(?i)\bno[\s-]?gi\b`)
+	giPattern   = regexp.MustCompile(`(?i)\bgi\b`)
+)
+
+// Condition is one clause of a filter, e.g. country in [CL,AR]
+type Condition struct {
+	Field  string   `json:"field"`
+	Negate bool     `json:"negate,omitempty"`
+	Values []string `json:"values"`
+}
+
+// Filter is a list of conditions that must all hold. An empty filter
+// matches every event.
+type Filter []Condition
+
+// EventFacts are the event attributes filters are evaluated against
+type EventFacts struct {
+	Federation string
+	Country    string
+	Gi         bool // false only for no-gi events
+	Type       string
+}
+
+// FactsFromEvent derives the filterable attributes of an event. The
+// federation is looked up in the name and organizer; events are gi unless
+// they only mention no-gi.
+func FactsFromEvent(event models.Event, organizer string) EventFacts {
+	text := strings.ToUpper(event.Name + " " + organizer)
+	facts := EventFacts{
+		Country: strings.ToUpper(event.CountryCode),
+		Type:    strings.ToLower(event.EventType),
+		Gi:      true,
+	}
+	for i, pattern := range federationPatterns {
+		if pattern.MatchString(text) {
+			facts.Federation = federations[i]
+			break
+		}
+	}
+	if noGiPattern.MatchString(event.Name) {
+		facts.Gi = giPattern.MatchString(noGiPattern.ReplaceAllString(event.Name, ""))
+	}
+	return facts
+}
+
+// ParseFilter parses an expression such as
+// "federation=AJP AND country in [CL,AR] AND gi=true". Clauses are joined
+// with AND and use =, != or in [..]; fields are federation, country, gi
+// and type.
+func ParseFilter(expr string) (Filter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	var filter Filter
+	for _, clause := range andPattern.Split(expr, -1) {
+		condition, err := parseCondition(strings.TrimSpace(clause))
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, condition)
+	}
+	return filter, nil
+}
+
+var (
+	andPattern       = regexp.MustCompile(`(?i)\s+AND\s+`)
+	conditionPattern = regexp.MustCompile(`(?i)^([a-z_]+)\s*(!=|=|\s+not\s+in\s+|\s+in\s+)\s*(.+)$`)
+)
+
+func parseCondition(clause string) (Condition, error) {
+	parts := conditionPattern.FindStringSubmatch(clause)
+	if parts == nil {
+		return Condition{}, fmt.Errorf("invalid clause %q (expected field=value, field!=value or field in [a,b])", clause)
+	}
+
+	field := strings.ToLower(parts[1])
+	op := strings.ToLower(strings.Join(strings.Fields(parts[2]), " "))
+	condition := Condition{Field: field, Negate: op == "!=" || op == "not in"}
+
+	raw := strings.TrimSpace(parts[3])
+	if op == "in" || op == "not in" {
+		if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+			return Condition{}, fmt.Errorf("invalid clause %q: list values go in brackets, e.g. [CL,AR]", clause)
+		}
+		raw = raw[1 : len(raw)-1]
+	}
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.Trim(strings.TrimSpace(value), `"'`); value != "" {
+			condition.Values = append(condition.Values, value)
+		}
+	}
+	if len(condition.Values) == 0 {
+		return Condition{}, fmt.Errorf("invalid clause %q: missing value", clause)
+	}
+
+	switch field {
+	case FieldFederation, FieldCountry, FieldType:
+	case FieldGi:
+		for _, value := range condition.Values {
+			if value != "true" && value != "false" {
+				return Condition{}, fmt.Errorf("invalid clause %q: gi is true or false", clause)
+			}
+		}
+	default:
+		return Condition{}, fmt.Errorf("unknown field %q (expected %s, %s, %s or %s)",
+			field, FieldFederation, FieldCountry, FieldGi, FieldType)
+	}
+	return condition, nil
+}
+
+// Matches reports whether every condition holds for the event
+func (f Filter) Matches(facts EventFacts) bool {
+	for _, condition := range f {
+		if condition.matches(facts) == condition.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) matches(facts EventFacts) bool {
+	var actual string
+	switch c.Field {
+	case FieldFederation:
+		actual = facts.Federation
+	case FieldCountry:
+		actual = facts.Country
+	case FieldGi:
+		actual = fmt.Sprint(facts.Gi)
+	case FieldType:
+		actual = facts.Type
+	}
+
+	for _, value := range c.Values {
+		if strings.EqualFold(value, actual) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// Package notify delivers notifications to the configured channels.
+package notify
+
+import (
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Notification is the payload sent to every channel
+type Notification struct {
+	Kind   string      `json:"kind"` // e.g. "event_digest"
+	Title  string      `json:"title"`
+	Text   string      `json:"text"`
+	Data   interface{} `json:"data,omitempty"`
+	SentAt time.Time   `json:"sent_at"`
+}
+
+// Channel is a notification destination
+type Channel interface {
+	Name() string
+	Send(n Notification) error
+}
+
+// Dispatcher fans notifications out to all channels
+type Dispatcher struct {
+	channels []Channel
+}
+
+// NewDispatcher builds the channels enabled in the configuration
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	d := &Dispatcher{}
+	for _, url := range cfg.Notifications.WebhookURLs {
+		d.channels = append(d.channels, NewWebhook(url, cfg.Notifications.Timeout))
+	}
+	return d
+}
+
+// Enabled reports whether any channel is configured
+func (d *Dispatcher) Enabled() bool {
+	return d != nil && len(d.channels) > 0
+}
+
+// Publish sends n to every channel. Failures are logged and do not stop
+// delivery to the remaining channels.
+func (d *Dispatcher) Publish(n Notification) {
+	if !d.Enabled() {
+		return
+	}
+	if n.SentAt.IsZero() {
+		n.SentAt = time.Now()
+	}
+
+	for _, channel := range d.channels {
+		if err := channel.Send(n); err != nil {
+			logger.Warn("Failed to deliver notification",
+				zap.String("channel", channel.Name()),
+				zap.String("kind", n.Kind),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,314 @@
+// Package openapi builds an OpenAPI 3.0 document from the routes of a mux
+// router. Operations are described by the doc comments, query parameters and
+// JSON bodies that cmd/apidocs collects from the handlers, so the document
+// follows the code instead of a hand-maintained file.
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gorilla/mux"
+)
+
+// Version is the OpenAPI version of the documents built here
+const Version = "3.0.3"
+
+// HandlerDoc describes a handler: its doc comment, the query parameters it
+// reads and whether it decodes a JSON body
+type HandlerDoc struct {
+	Doc   string
+	Query []string
+	Body  bool
+}
+
+// Info is the info object of a document
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Tag groups operations, one per first path segment
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of a path, by lowercase method
+type PathItem map[string]*Operation
+
+// Operation is one method of a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema"`
+}
+
+// RequestBody is the JSON body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// Schema is a JSON schema object
+type Schema map[string]interface{}
+
+// Components holds the shared schemas and security schemes
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is how a client authenticates
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// Builder turns the routes of a router into a document
+type Builder struct {
+	Info Info
+	// Docs describe the handlers by method name
+	Docs map[string]HandlerDoc
+	// Prefix selects the routes to describe and is removed from paths to
+	// name their tag, e.g. "/api/v1"
+	Prefix string
+	// AdminPrefix marks the paths requiring the admin key, e.g. "/api/v1/admin"
+	AdminPrefix string
+	// Secured are subrouters whose routes also require the admin key
+	Secured []*mux.Router
+}
+
+// Build walks router and describes every route under Prefix with a method
+func (b Builder) Build(router *mux.Router) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    b.Info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: map[string]Schema{"APIResponse": envelopeSchema},
+		},
+	}
+	if b.AdminPrefix != "" {
+		doc.Components.SecuritySchemes = map[string]SecurityScheme{
+			"adminKey":    {Type: "apiKey", In: "header", Name: "X-Admin-Key"},
+			"adminBearer": {Type: "http", Scheme: "bearer"},
+		}
+	}
+
+	tags := make(map[string]bool)
+	operationIDs := make(map[string]int)
+	err := router.Walk(func(route *mux.Route, parent *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		if !strings.HasPrefix(template, b.Prefix) {
+			return nil // pages such as /docs
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil // subrouter prefixes
+		}
+
+		path, params, err := pathParameters(template)
+		if err != nil {
+			return err
+		}
+		name := handlerName(route.GetHandler())
+		handlerDoc := b.Docs[name]
+		tag := b.tag(template)
+		tags[tag] = true
+
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		for _, method := range methods {
+			operationIDs[name]++
+			op := &Operation{
+				OperationID: name,
+				Summary:     summary(name, handlerDoc.Doc),
+				Description: handlerDoc.Doc,
+				Tags:        []string{tag},
+				Parameters:  append([]Parameter{}, params...),
+				Responses: map[string]Response{
+					"default": {
+						Description: "APIResponse envelope; files, images and event streams are sent as they are",
+						Content:     jsonContent(Schema{"$ref": "#/components/schemas/APIResponse"}),
+					},
+				},
+			}
+			if n := operationIDs[name]; n > 1 {
+				op.OperationID = fmt.Sprintf("%s%d", name, n)
+			}
+			for _, query := range handlerDoc.Query {
+				op.Parameters = append(op.Parameters, Parameter{Name: query, In: "query", Schema: Schema{"type": "string"}})
+			}
+			if handlerDoc.Body && method != "GET" {
+				op.RequestBody = &RequestBody{Required: true, Content: jsonContent(Schema{"type": "object"})}
+			}
+			if b.AdminPrefix != "" && strings.HasPrefix(template, b.AdminPrefix) || slices.Contains(b.Secured, parent) {
+				op.Security = []map[string][]string{{"adminKey": {}}, {"adminBearer": {}}}
+			}
+			item[strings.ToLower(method)] = op
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc, nil
+}
+
+// envelopeSchema is models.APIResponse
+var envelopeSchema = Schema{
+	"type": "object",
+	"properties": map[string]Schema{
+		"success": {"type": "boolean"},
+		"message": {"type": "string"},
+		"data":    {},
+		"error":   {"type": "string"},
+	},
+}
+
+func jsonContent(schema Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// tag is the first path segment after the prefix, e.g. "academies"
+func (b Builder) tag(template string) string {
+	rest := strings.Trim(strings.TrimPrefix(template, b.Prefix), "/")
+	segment, _, _ := strings.Cut(rest, "/")
+	if i := strings.IndexAny(segment, ".{"); i >= 0 {
+		segment = segment[:i]
+	}
+	if segment == "" {
+		return "default"
+	}
+	return segment
+}
+
+// handlerName is the method name of a handler method value, e.g.
+// "GetAcademies" for handler.GetAcademies
+func handlerName(handler interface{}) string {
+	value := reflect.ValueOf(handler)
+	if value.Kind() != reflect.Func {
+		return ""
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := strings.TrimSuffix(fn.Name(), "-fm")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// summary is the first sentence of a doc comment without the method name
+func summary(name, doc string) string {
+	if doc == "" {
+		return name
+	}
+	sentence := strings.Join(strings.Fields(doc), " ")
+	if i := strings.Index(sentence, ". "); i >= 0 {
+		sentence = sentence[:i]
+	}
+	sentence = strings.TrimSuffix(sentence, ".")
+	if rest, ok := strings.CutPrefix(sentence, name+" "); ok && rest != "" {
+		runes := []rune(rest)
+		runes[0] = unicode.ToUpper(runes[0])
+		sentence = string(runes)
+	}
+	return sentence
+}
+
+// pathParameters turns a mux path template into an OpenAPI path, e.g.
+// "/queue/{id:[0-9]+}" into "/queue/{id}" with a required id parameter
+// restricted to the pattern
+func pathParameters(template string) (string, []Parameter, error) {
+	var path strings.Builder
+	var params []Parameter
+
+	for i := 0; i < len(template); i++ {
+		if template[i] != '{' {
+			path.WriteByte(template[i])
+			continue
+		}
+		depth, end := 0, -1
+		for j := i; j < len(template); j++ {
+			switch template[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+			if depth == 0 {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			return "", nil, fmt.Errorf("unbalanced braces in route %s", template)
+		}
+
+		name, pattern, _ := strings.Cut(template[i+1:end], ":")
+		schema := Schema{"type": "string"}
+		switch {
+		case pattern == "":
+		case strings.Contains(pattern, "|") && !strings.ContainsAny(pattern, "[]()*+?\\"):
+			schema["enum"] = strings.Split(pattern, "|")
+		default:
+			schema["pattern"] = "^" + pattern + "$"
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		path.WriteString("{" + name + "}")
+		i = end
+	}
+	return path.String(), params, nil
+}
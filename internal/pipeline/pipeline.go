@@ -0,0 +1,268 @@
+// Package pipeline runs multi-stage scraping jobs. Every stage is recorded as
+// its own job under the pipeline job, retried on failure, and skipped when a
+// failed pipeline is resumed after the stage already completed. A cancelled
+// run stops without retrying and can be resumed like a failed one.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Stage is one step of a pipeline. Run must be idempotent: it may be retried
+// and re-run when the pipeline is resumed. It reports progress through
+// job.ItemsScraped and should return soon after ctx is cancelled.
+type Stage struct {
+	Name string
+	Run  func(ctx context.Context, run *Run, job *models.ScrapeJob) error
+}
+
+// Pipeline is an ordered list of stages
+type Pipeline struct {
+	Name    string // job type of the pipeline job; stages are "<name>_<stage>"
+	Stages  []Stage
+	Retries int           // extra attempts per stage after a failure
+	Backoff time.Duration // wait before the first retry, doubled on each one
+}
+
+// Run is one execution of a pipeline, possibly spanning several resumes
+type Run struct {
+	Job *models.ScrapeJob // the pipeline job; stage jobs point to it
+
+	// Since is when the run first started; stages use it to select the data
+	// written by earlier stages of the same run
+	Since time.Time
+}
+
+// Start records a new pipeline job
+func (p *Pipeline) Start() *Run {
+	job := &models.ScrapeJob{
+		JobType:   p.Name,
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+	config.GetDB().Create(job)
+	bus.PublishJob(bus.JobStarted, job)
+
+	logger.Info("Pipeline started", zap.String("pipeline", p.Name), zap.Int("job_id", job.ID))
+
+	return &Run{Job: job, Since: job.StartedAt}
+}
+
+// CheckResume reports why jobID cannot be resumed, or nil when Resume
+// would reopen it
+func (p *Pipeline) CheckResume(jobID int) error {
+	_, err := p.resumable(jobID)
+	return err
+}
+
+// Resume reopens a failed, partial, cancelled or postponed pipeline job so
+// Execute continues from the first stage that did not complete
+func (p *Pipeline) Resume(jobID int) (*Run, error) {
+	db := config.GetDB()
+
+	job, err := p.resumable(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = "running"
+	job.CompletedAt = nil
+	job.ErrorMessage = ""
+	db.Save(job)
+	bus.PublishJob(bus.JobStarted, job)
+
+	logger.Info("Pipeline resumed", zap.String("pipeline", p.Name), zap.Int("job_id", job.ID))
+
+	return &Run{Job: job, Since: job.StartedAt}, nil
+}
+
+func (p *Pipeline) resumable(jobID int) (*models.ScrapeJob, error) {
+	var job models.ScrapeJob
+	if err := config.GetDB().First(&job, jobID).Error; err != nil {
+		return nil, fmt.Errorf("job %d not found", jobID)
+	}
+	if job.JobType != p.Name {
+		return nil, fmt.Errorf("job %d is not a %q pipeline job", jobID, p.Name)
+	}
+	if job.Status != "failed" && job.Status != "partial" && job.Status != "cancelled" && job.Status != maintenance.StatusPostponed {
+		return nil, fmt.Errorf("job %d is %s and cannot be resumed", jobID, job.Status)
+	}
+	return &job, nil
+}
+
+// Execute runs the stages in order, stopping at the first stage that still
+// fails after its retries or when ctx is cancelled. The pipeline job's
+// progress counts stages; each stage job tracks its own items.
+func (p *Pipeline) Execute(ctx context.Context, run *Run) error {
+	db := config.GetDB()
+
+	for i, stage := range p.Stages {
+		run.Job.CurrentPhase = stage.Name
+		run.Job.ItemsTotal = len(p.Stages)
+		run.Job.ItemsProcessed = i
+		db.Model(run.Job).Updates(map[string]interface{}{
+			"current_phase":   run.Job.CurrentPhase,
+			"items_total":     run.Job.ItemsTotal,
+			"items_processed": run.Job.ItemsProcessed,
+		})
+		bus.PublishJob(bus.JobProgress, run.Job)
+
+		job := p.stageJob(run, stage)
+		if job.Status == "completed" {
+			logger.Info("Pipeline stage already completed",
+				zap.String("stage", stage.Name),
+				zap.Int("job_id", job.ID))
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return p.cancel(ctx, run, stage)
+		}
+
+		if err := p.runStage(ctx, run, stage, job); err != nil {
+			if ctx.Err() != nil {
+				return p.cancel(ctx, run, stage)
+			}
+
+			now := time.Now()
+			run.Job.Status = "failed"
+			run.Job.CompletedAt = &now
+			run.Job.ErrorMessage = fmt.Sprintf("stage %s: %v", stage.Name, err)
+			db.Save(run.Job)
+			bus.PublishJob(bus.JobFinished, run.Job)
+
+			logger.Error("Pipeline failed",
+				zap.String("pipeline", p.Name),
+				zap.String("stage", stage.Name),
+				zap.Int("job_id", run.Job.ID),
+				zap.Error(err))
+			return err
+		}
+
+		run.Job.ItemsScraped += job.ItemsScraped
+		db.Model(run.Job).Update("items_scraped", run.Job.ItemsScraped)
+	}
+
+	now := time.Now()
+	run.Job.Status = "completed"
+	run.Job.CompletedAt = &now
+	run.Job.ItemsProcessed = len(p.Stages)
+	db.Save(run.Job)
+	bus.PublishJob(bus.JobFinished, run.Job)
+
+	logger.Info("Pipeline completed",
+		zap.String("pipeline", p.Name),
+		zap.Int("job_id", run.Job.ID),
+		zap.Int("items_scraped", run.Job.ItemsScraped))
+
+	return nil
+}
+
+// cancel records the run as cancelled, or postponed by maintenance, at stage
+func (p *Pipeline) cancel(ctx context.Context, run *Run, stage Stage) error {
+	err := context.Cause(ctx)
+
+	now := time.Now()
+	run.Job.Status = maintenance.StoppedStatus(err)
+	run.Job.CompletedAt = &now
+	run.Job.ErrorMessage = fmt.Sprintf("stage %s: %v", stage.Name, err)
+	config.GetDB().Save(run.Job)
+	bus.PublishJob(bus.JobFinished, run.Job)
+
+	logger.Info("Pipeline cancelled",
+		zap.String("pipeline", p.Name),
+		zap.String("stage", stage.Name),
+		zap.Int("job_id", run.Job.ID),
+		zap.Error(err))
+	return err
+}
+
+// stageJob returns the job of a stage in this run, creating it on first use
+func (p *Pipeline) stageJob(run *Run, stage Stage) *models.ScrapeJob {
+	db := config.GetDB()
+	jobType := p.Name + "_" + stage.Name
+
+	var job models.ScrapeJob
+	found := db.Where("parent_job_id = ? AND job_type = ?", run.Job.ID, jobType).
+		Order("id DESC").Limit(1).Find(&job).RowsAffected > 0
+	if !found {
+		job = models.ScrapeJob{
+			ParentJobID: &run.Job.ID,
+			JobType:     jobType,
+			Status:      "pending",
+			StartedAt:   time.Now(),
+		}
+		db.Create(&job)
+	}
+	return &job
+}
+
+func (p *Pipeline) runStage(ctx context.Context, run *Run, stage Stage, job *models.ScrapeJob) error {
+	db := config.GetDB()
+	backoff := p.Backoff
+
+	var err error
+	for attempt := 0; attempt <= p.Retries; attempt++ {
+		if attempt > 0 {
+			logger.Warn("Retrying pipeline stage",
+				zap.String("stage", stage.Name),
+				zap.Int("attempt", job.Attempts+1),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+			backoff *= 2
+		}
+
+		job.Status = "running"
+		job.StartedAt = time.Now()
+		job.CompletedAt = nil
+		job.ErrorMessage = ""
+		job.ItemsScraped = 0
+		job.Attempts++
+		db.Save(job)
+		bus.PublishJob(bus.JobStarted, job)
+
+		if err = stage.Run(ctx, run, job); err == nil && ctx.Err() == nil {
+			now := time.Now()
+			job.Status = "completed"
+			job.CompletedAt = &now
+			db.Save(job)
+			bus.PublishJob(bus.JobFinished, job)
+
+			logger.Info("Pipeline stage completed",
+				zap.String("stage", stage.Name),
+				zap.Int("job_id", job.ID),
+				zap.Int("items_scraped", job.ItemsScraped))
+			return nil
+		}
+
+		now := time.Now()
+		job.CompletedAt = &now
+		if ctx.Err() != nil {
+			job.Status = maintenance.StoppedStatus(context.Cause(ctx))
+			job.ErrorMessage = context.Cause(ctx).Error()
+			db.Save(job)
+			bus.PublishJob(bus.JobFinished, job)
+			return context.Cause(ctx)
+		}
+		job.Status = "failed"
+		job.ErrorMessage = err.Error()
+		db.Save(job)
+		bus.PublishJob(bus.JobFinished, job)
+	}
+
+	return err
+}
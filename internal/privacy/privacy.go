@@ -0,0 +1,226 @@
+package privacy
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Policy masks the names and pictures of minors in API responses and
+// exports. Internal and Smoothcomp IDs are kept so records stay linkable.
+type Policy struct {
+	minorAge int
+	now      func() time.Time
+}
+
+// NewPolicy creates the policy configured by ANONYMIZE_MINORS_UNDER
+func NewPolicy(cfg *config.Config) *Policy {
+	return &Policy{
+		minorAge: cfg.Privacy.MinorAge,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether minors are anonymized
+func (p *Policy) Enabled() bool {
+	return p != nil && p.minorAge > 0
+}
+
+// ageRow is the subset of an athlete needed to tell its age
+type ageRow struct {
+	ID        uint
+	BirthYear int
+	Age       int
+}
+
+// MinorIDs returns which of the athletes are under the configured age.
+// Age comes from the birth year, then the scraped age; athletes with neither
+// count as minors when they registered in a kids division.
+func (p *Policy) MinorIDs(ids []uint) map[uint]bool {
+	minors := make(map[uint]bool)
+	if !p.Enabled() || len(ids) == 0 {
+		return minors
+	}
+
+	var rows []ageRow
+	if err := config.GetDB().Model(&models.Athlete{}).
+		Select("id, birth_year, age").Where("id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		// Fail closed: without ages every athlete is masked
+		logger.Warn("Failed to load athlete ages, masking all", zap.Error(err))
+		for _, id := range ids {
+			minors[id] = true
+		}
+		return minors
+	}
+	return p.classify(rows)
+}
+
+func (p *Policy) classify(rows []ageRow) map[uint]bool {
+	minors := make(map[uint]bool)
+	unknown := make([]uint, 0)
+
+	year := p.now().Year()
+	for _, row := range rows {
+		switch {
+		case row.BirthYear > 0:
+			// Without the birthday, the athlete may still be a year younger
+			if year-row.BirthYear-1 < p.minorAge {
+				minors[row.ID] = true
+			}
+		case row.Age > 0:
+			if row.Age < p.minorAge {
+				minors[row.ID] = true
+			}
+		default:
+			unknown = append(unknown, row.ID)
+		}
+	}
+
+	if len(unknown) > 0 {
+		var kids []uint
+		if err := config.GetDB().Model(&models.EventRegistration{}).Distinct().
+			Where("is_kids = ? AND athlete_id IN ?", true, unknown).
+			Pluck("athlete_id", &kids).Error; err != nil {
+			logger.Warn("Failed to load kids registrations, masking athletes of unknown age", zap.Error(err))
+			kids = unknown
+		}
+		for _, id := range kids {
+			minors[id] = true
+		}
+	}
+
+	return minors
+}
+
+// MaskAthlete anonymizes the athlete if it is a minor
+func (p *Policy) MaskAthlete(athlete *models.Athlete) {
+	if athlete == nil || !p.Enabled() {
+		return
+	}
+	one := []models.Athlete{*athlete}
+	p.MaskAthletes(one)
+	*athlete = one[0]
+}
+
+// MaskAthletes anonymizes the minors among athletes in place
+func (p *Policy) MaskAthletes(athletes []models.Athlete) {
+	if !p.Enabled() || len(athletes) == 0 {
+		return
+	}
+
+	rows := make([]ageRow, len(athletes))
+	for i, athlete := range athletes {
+		rows[i] = ageRow{ID: uint(athlete.ID), BirthYear: athlete.BirthYear, Age: athlete.Age}
+	}
+	minors := p.classify(rows)
+
+	for i := range athletes {
+		if minors[uint(athletes[i].ID)] {
+			mask(&athletes[i])
+		}
+	}
+}
+
+// MaskMatches replaces the names of minors in matches with initials
+func (p *Policy) MaskMatches(matches []models.Match) {
+	if !p.Enabled() || len(matches) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(matches)*2)
+	for _, match := range matches {
+		ids = append(ids, match.AthleteAID, match.AthleteBID)
+	}
+	minors := p.MinorIDs(ids)
+
+	for i := range matches {
+		match := &matches[i]
+		if minors[match.AthleteAID] {
+			match.AthleteAName = Initials(match.AthleteAName)
+		}
+		if minors[match.AthleteBID] {
+			match.AthleteBName = Initials(match.AthleteBName)
+		}
+		if match.WinnerID != 0 && minors[match.WinnerID] {
+			match.WinnerName = Initials(match.WinnerName)
+		}
+	}
+}
+
+// MaskResults replaces the names of minors in podium results with initials
+func (p *Policy) MaskResults(results []models.EventResult) {
+	if !p.Enabled() || len(results) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.AthleteID)
+	}
+	minors := p.MinorIDs(ids)
+
+	for i := range results {
+		if minors[results[i].AthleteID] {
+			results[i].AthleteName = Initials(results[i].AthleteName)
+		}
+	}
+}
+
+// MaskLiveScores replaces the names of minors in live scores with initials
+func (p *Policy) MaskLiveScores(scores []models.LiveScore) {
+	if !p.Enabled() || len(scores) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(scores)*2)
+	for _, score := range scores {
+		ids = append(ids, score.AthleteAID, score.AthleteBID)
+	}
+	minors := p.MinorIDs(ids)
+
+	for i := range scores {
+		if minors[scores[i].AthleteAID] {
+			scores[i].AthleteAName = Initials(scores[i].AthleteAName)
+		}
+		if minors[scores[i].AthleteBID] {
+			scores[i].AthleteBName = Initials(scores[i].AthleteBName)
+		}
+	}
+}
+
+// mask hides everything identifying the athlete except its IDs
+func mask(athlete *models.Athlete) {
+	name := Initials(athlete.FirstName + " " + athlete.LastName)
+	if name == "" {
+		name = Initials(athlete.FullName)
+	}
+	athlete.FirstName = ""
+	athlete.LastName = ""
+	athlete.FullName = name
+	athlete.Slug = ""
+	athlete.ProfileURL = ""
+	athlete.AvatarURL = ""
+	athlete.ImageURL = ""
+	athlete.BirthYear = 0
+}
+
+// Initials renders "Juan Pablo Perez" as "J. P. P."
+func Initials(name string) string {
+	words := strings.Fields(name)
+	parts := make([]string, 0, len(words))
+	for _, word := range words {
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				parts = append(parts, string(unicode.ToUpper(r))+".")
+				break
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
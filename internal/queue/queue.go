@@ -0,0 +1,581 @@
+// Package queue runs the scrapes requested through the API from a table of
+// queued jobs, so that pending work survives restarts. A bounded number of
+// workers takes the oldest due job, and failed jobs are retried with a
+// growing backoff.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/academystats"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// pollInterval is how often idle workers look for jobs whose backoff is over
+const pollInterval = 5 * time.Second
+
+// recheckInterval is how often events due for a results recheck are queued
+const recheckInterval = time.Minute
+
+// heartbeatInterval is how often a running job records that its process is
+// alive; jobs silent for abandonedAfter are requeued by any process, since
+// the one running them died without a shutdown
+const (
+	heartbeatInterval = 30 * time.Second
+	abandonedAfter    = 3 * time.Minute
+)
+
+// Queued job types
+const (
+	JobTypeAcademies      = "academies"       // academy listings of the target countries
+	JobTypeAll            = "all"             // full pipeline, or params.resume_job_id
+	JobTypeEventsPast     = "events_past"     // past events of params.country
+	JobTypeEventsUpcoming = "events_upcoming" // upcoming events of params.country
+	JobTypeEventAthletes  = "event_athletes"  // participants of params.event_id
+	JobTypeEventBrackets  = "event_brackets"  // brackets and matches of params.event_id
+	JobTypeEventResults   = "event_results"   // podium results of params.event_id
+	JobTypeTeamRankings   = "team_rankings"   // club ranking of params.federation and params.season
+	JobTypeAcademyStats   = "academy_stats"   // statistics of params.academy_id, or of every academy
+	JobTypeCountryRoster  = "country_roster"  // national registry of params.country, or params.resume_job_id
+	JobTypeEnrich         = "enrich"          // profiles of params.limit athletes from params.offset
+	JobTypeEventsBulk     = "events_bulk"     // details, participants and results of params.events
+	JobTypeAthleteResync  = "athlete_resync"  // full refresh of params.athlete_id
+	JobTypeAcademyResync  = "academy_resync"  // club page and roster of params.academy_id
+)
+
+// Queued job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusPostponed = maintenance.StatusPostponed // stopped by a maintenance page, runs again after run_after
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// waiting are the statuses of jobs that have not run to the end yet
+var waiting = []string{StatusQueued, StatusPostponed}
+
+// ErrNotQueued is returned when cancelling a job that is no longer waiting
+var ErrNotQueued = errors.New("job is not queued")
+
+// Queue hands queued jobs to its workers. Several processes may share the
+// table: a job is claimed by one of them and marked with its owner.
+type Queue struct {
+	config  *config.Config
+	scraper *scraper.Scraper
+	owner   string
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a queue; no job runs until Start
+func New(cfg *config.Config) *Queue {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	owner := cfg.Queue.WorkerID
+	if owner == "" {
+		owner, _ = os.Hostname()
+	}
+	if owner == "" {
+		owner = "local"
+	}
+	return &Queue{
+		config:  cfg,
+		scraper: scraper.NewScraper(cfg),
+		owner:   owner,
+		wake:    make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start requeues the jobs this process left running before a restart, and
+// those of processes that stopped sending heartbeats, and starts the workers
+// and the results rechecks
+func (q *Queue) Start() error {
+	requeued, err := q.requeueInterrupted(true)
+	if err != nil {
+		return fmt.Errorf("failed to requeue interrupted jobs: %w", err)
+	}
+	if requeued > 0 {
+		logger.Warn("Requeued jobs interrupted by a restart", zap.Int64("jobs", requeued))
+	}
+
+	workers := q.config.Queue.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for range workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+	q.wg.Add(1)
+	go q.recheckResults()
+	q.wg.Add(1)
+	go q.watchAbandoned()
+
+	logger.Info("Job queue started", zap.Int("workers", workers))
+	return nil
+}
+
+// requeueInterrupted puts back in the queue the running jobs whose heartbeat
+// is older than abandonedAfter and, with own, those owned by this process
+func (q *Queue) requeueInterrupted(own bool) (int64, error) {
+	query := config.GetDB().Model(&models.QueuedJob{}).Where("status = ?", StatusRunning)
+	stale := time.Now().Add(-abandonedAfter)
+	if own {
+		query = query.Where("owner = ? OR owner = '' OR owner IS NULL OR heartbeat_at IS NULL OR heartbeat_at < ?", q.owner, stale)
+	} else {
+		query = query.Where("heartbeat_at IS NULL OR heartbeat_at < ?", stale)
+	}
+	result := query.Updates(map[string]interface{}{
+		"status":       StatusQueued,
+		"attempts":     gorm.Expr("attempts - 1"),
+		"started_at":   nil,
+		"owner":        "",
+		"heartbeat_at": nil,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// watchAbandoned requeues the jobs of processes that died while running them
+func (q *Queue) watchAbandoned() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		requeued, err := q.requeueInterrupted(false)
+		if err != nil {
+			logger.Error("Failed to requeue abandoned jobs", zap.Error(err))
+			continue
+		}
+		if requeued > 0 {
+			logger.Warn("Requeued jobs abandoned by a stopped process", zap.Int64("jobs", requeued))
+			select {
+			case q.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Stop cancels the running jobs, which go back to the queue, and waits for
+// the workers until ctx is done
+func (q *Queue) Stop(ctx context.Context) {
+	q.cancel(scraper.ErrShutdown)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		logger.Info("Job queue stopped")
+	case <-ctx.Done():
+		logger.Warn("Queued jobs still running at shutdown")
+	}
+}
+
+// Enqueue stores a job and wakes an idle worker
+func (q *Queue) Enqueue(jobType string, params models.QueueParams) (*models.QueuedJob, error) {
+	job := &models.QueuedJob{
+		JobType:     jobType,
+		Params:      params,
+		Status:      StatusQueued,
+		MaxAttempts: q.config.Queue.MaxAttempts,
+		RunAfter:    time.Now(),
+	}
+	if err := config.GetDB().Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to queue job: %w", err)
+	}
+
+	logger.Info("Job queued",
+		zap.Int("queue_id", job.ID),
+		zap.String("job_type", jobType))
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Cancel removes a job that has not started. Running jobs are stopped by
+// cancelling their scrape job.
+func (q *Queue) Cancel(id int) (*models.QueuedJob, error) {
+	db := config.GetDB()
+	now := time.Now()
+
+	result := db.Model(&models.QueuedJob{}).
+		Where("id = ? AND status IN ?", id, waiting).
+		Updates(map[string]interface{}{"status": StatusCancelled, "finished_at": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var job models.QueuedJob
+	if err := db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return &job, ErrNotQueued
+	}
+	return &job, nil
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for q.ctx.Err() == nil {
+		job, ok := q.next()
+		if ok {
+			q.run(job)
+			continue
+		}
+
+		select {
+		case <-q.ctx.Done():
+		case <-q.wake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// recheckResults queues a results scrape for every event whose recheck is
+// due (see scraper.DueResultsRechecks)
+func (q *Queue) recheckResults() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(recheckInterval)
+	defer ticker.Stop()
+
+	for {
+		q.queueResultsRechecks()
+
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) queueResultsRechecks() {
+	events, err := scraper.DueResultsRechecks(time.Now())
+	if err != nil {
+		logger.Error("Failed to read results rechecks", zap.Error(err))
+		return
+	}
+
+	db := config.GetDB()
+	for _, event := range events {
+		// The scrape schedules the next recheck if results are still missing
+		claimed := db.Model(&models.Event{}).
+			Where("id = ? AND results_recheck_at = ?", event.ID, event.ResultsRecheckAt).
+			Update("results_recheck_at", nil)
+		if claimed.Error != nil || claimed.RowsAffected == 0 {
+			continue
+		}
+
+		if _, err := q.Enqueue(JobTypeEventResults, models.QueueParams{EventID: event.ExternalID}); err != nil {
+			logger.Error("Failed to queue results recheck", zap.String("event_id", event.ExternalID), zap.Error(err))
+			continue
+		}
+		logger.Info("Queued results recheck",
+			zap.String("event_id", event.ExternalID),
+			zap.Int("checks", event.ResultsChecks))
+	}
+}
+
+// claimTries is how many due jobs a worker tries to claim before waiting,
+// when workers of other processes claim them first
+const claimTries = 3
+
+// next claims the oldest due job. The claim is a conditional UPDATE, so of
+// several workers, in this process or others, only one gets each job.
+func (q *Queue) next() (*models.QueuedJob, bool) {
+	db := config.GetDB()
+
+	for range claimTries {
+		var job models.QueuedJob
+		err := db.Where("status IN ? AND run_after <= ?", waiting, time.Now()).
+			Order("run_after, id").
+			Limit(1).
+			Find(&job).Error
+		if err != nil {
+			logger.Error("Failed to read job queue", zap.Error(err))
+			return nil, false
+		}
+		if job.ID == 0 {
+			return nil, false
+		}
+
+		now := time.Now()
+		claimed := db.Model(&models.QueuedJob{}).
+			Where("id = ? AND status IN ?", job.ID, waiting).
+			Updates(map[string]interface{}{
+				"status":       StatusRunning,
+				"attempts":     gorm.Expr("attempts + 1"),
+				"started_at":   now,
+				"heartbeat_at": now,
+				"owner":        q.owner,
+			})
+		if claimed.Error != nil {
+			logger.Error("Failed to claim queued job", zap.Int("queue_id", job.ID), zap.Error(claimed.Error))
+			return nil, false
+		}
+		if claimed.RowsAffected == 0 {
+			continue
+		}
+
+		job.Status = StatusRunning
+		job.Attempts++
+		job.StartedAt = &now
+		job.HeartbeatAt = &now
+		job.Owner = q.owner
+		return &job, true
+	}
+	return nil, false
+}
+
+// heartbeat refreshes heartbeat_at of a running job until the returned func
+// is called
+func (q *Queue) heartbeat(job *models.QueuedJob) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				config.GetDB().Model(&models.QueuedJob{}).
+					Where("id = ? AND owner = ? AND status = ?", job.ID, q.owner, StatusRunning).
+					Update("heartbeat_at", now)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// run executes a claimed job and records its outcome. Jobs stopped by a
+// shutdown go back to the queue without counting the attempt; jobs stopped
+// by a maintenance page are postponed for MAINTENANCE_RETRY_MINUTES.
+func (q *Queue) run(job *models.QueuedJob) {
+	logger.Info("Running queued job",
+		zap.Int("queue_id", job.ID),
+		zap.String("job_type", job.JobType),
+		zap.Int("attempt", job.Attempts))
+
+	ctx := q.ctx
+	if job.Attempts > 1 {
+		// A retry must not get the pages the failed run just cached
+		ctx = scraper.WithFreshFetch(ctx)
+	}
+	stopHeartbeat := q.heartbeat(job)
+	err := q.execute(ctx, job)
+	stopHeartbeat()
+	now := time.Now()
+
+	switch {
+	case q.ctx.Err() != nil:
+		job.Status = StatusQueued
+		job.Attempts--
+		job.StartedAt = nil
+	case errors.Is(err, maintenance.ErrActive) && job.Postpones < q.config.Maintenance.MaxPostpones:
+		job.Status = StatusPostponed
+		job.Attempts--
+		job.Postpones++
+		job.LastError = err.Error()
+		job.RunAfter = now.Add(q.config.Maintenance.RetryDelay)
+		logger.Warn("Queued job postponed by Smoothcomp maintenance",
+			zap.Int("queue_id", job.ID),
+			zap.Time("run_after", job.RunAfter))
+	case errors.Is(err, scraper.ErrJobCancelled):
+		job.Status = StatusCancelled
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+	case err != nil && job.Attempts < job.MaxAttempts && retryable(err):
+		backoff := q.config.Queue.RetryBackoff << (job.Attempts - 1)
+		job.Status = StatusQueued
+		job.LastError = err.Error()
+		job.RunAfter = now.Add(backoff)
+		logger.Warn("Queued job failed, retrying",
+			zap.Int("queue_id", job.ID),
+			zap.Int("attempt", job.Attempts),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+	case err != nil:
+		job.Status = StatusFailed
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+		logger.Error("Queued job failed",
+			zap.Int("queue_id", job.ID),
+			zap.Int("attempts", job.Attempts),
+			zap.Error(err))
+	default:
+		job.Status = StatusCompleted
+		job.FinishedAt = &now
+	}
+
+	if job.Status == StatusQueued || job.Status == StatusPostponed {
+		job.Owner = ""
+		job.HeartbeatAt = nil
+	}
+
+	// Only while still ours: a job requeued as abandoned may run elsewhere
+	recorded := config.GetDB().Model(&models.QueuedJob{}).
+		Where("id = ? AND owner = ? AND status = ?", job.ID, q.owner, StatusRunning).
+		Select("*").Omit("id", "created_at").
+		Updates(job)
+	if recorded.Error != nil {
+		logger.Error("Failed to record queued job", zap.Int("queue_id", job.ID), zap.Error(recorded.Error))
+	} else if recorded.RowsAffected == 0 {
+		logger.Warn("Queued job was requeued by another process while running", zap.Int("queue_id", job.ID))
+	}
+}
+
+// retryable reports whether another run may succeed; blocklisted entities
+// and URLs outside the allowlist fail the same way every time, and a
+// maintenance window outlasting every postponement is not retried sooner
+func retryable(err error) bool {
+	return !errors.Is(err, scraper.ErrBlocked) && !errors.Is(err, scraper.ErrNotAllowed) &&
+		!errors.Is(err, maintenance.ErrActive)
+}
+
+func (q *Queue) execute(ctx context.Context, job *models.QueuedJob) error {
+	p := job.Params
+
+	switch job.JobType {
+	case JobTypeAcademies:
+		return q.scraper.ScrapeAcademies(ctx)
+	case JobTypeAll:
+		if p.ResumeJobID == 0 {
+			return q.scraper.ScrapeAll(ctx)
+		}
+		resume, err := q.scraper.ResumeScrapeAll(p.ResumeJobID)
+		if err != nil {
+			return err
+		}
+		return resume(ctx)
+	case JobTypeEventsPast, JobTypeEventsUpcoming:
+		depth, err := scraper.ParseDepth(p.Depth)
+		if err != nil {
+			return err
+		}
+		opts, err := runOptions(p, job.JobType)
+		if err != nil {
+			return err
+		}
+		return q.scraper.ScrapeEvents(ctx, strings.TrimPrefix(job.JobType, "events_"), p.Country, depth, opts)
+	case JobTypeEnrich:
+		opts, err := runOptions(p, "profiles_enrich")
+		if err != nil {
+			return err
+		}
+		return q.scraper.EnrichProfiles(ctx, p.Limit, p.Offset, p.OnlyMissing, opts)
+	case JobTypeEventsBulk:
+		events, invalid := scraper.ParseBulkEvents(p.Events)
+		if len(invalid) > 0 {
+			return fmt.Errorf("invalid events %q", invalid)
+		}
+		opts, err := runOptions(p, job.JobType)
+		if err != nil {
+			return err
+		}
+		return q.scraper.IngestEventsBulk(ctx, events, opts)
+	case JobTypeAthleteResync:
+		var athlete models.Athlete
+		if err := config.GetDB().Where("external_id = ?", p.AthleteID).First(&athlete).Error; err != nil {
+			return fmt.Errorf("error loading athlete %s: %w", p.AthleteID, err)
+		}
+		_, err := q.scraper.ResyncAthlete(ctx, athlete)
+		return err
+	case JobTypeAcademyResync:
+		var academy models.Academy
+		if err := config.GetDB().Where("external_id = ?", p.AcademyID).First(&academy).Error; err != nil {
+			return fmt.Errorf("error loading academy %s: %w", p.AcademyID, err)
+		}
+		_, err := q.scraper.ResyncAcademy(ctx, academy)
+		return err
+	case JobTypeEventAthletes:
+		return q.scraper.ScrapeEventAthletes(ctx, p.EventID, p.EventName, p.EventURL)
+	case JobTypeEventBrackets:
+		_, err := q.scraper.ScrapeEventBrackets(ctx, p.EventID, p.EventURL)
+		return err
+	case JobTypeEventResults:
+		_, err := q.scraper.ScrapeEventResults(ctx, p.EventID)
+		return err
+	case JobTypeTeamRankings:
+		_, err := q.scraper.ScrapeTeamRankings(ctx, p.Federation, p.Season)
+		return err
+	case JobTypeCountryRoster:
+		if p.ResumeJobID == 0 {
+			return q.scraper.ScrapeCountryRoster(ctx, p.Country)
+		}
+		resume, err := q.scraper.ResumeCountryRoster(p.ResumeJobID)
+		if err != nil {
+			return err
+		}
+		return resume(ctx)
+	case JobTypeAcademyStats:
+		if p.AcademyID == "" {
+			_, err := academystats.Recompute()
+			return err
+		}
+		_, err := academystats.Recompute(p.AcademyID)
+		return err
+	}
+	return fmt.Errorf("unknown queued job type %q", job.JobType)
+}
+
+// runOptions rebuilds the run options a request stored on a queued job;
+// resumeType is the scrape job type its resume token must belong to
+func runOptions(p models.QueueParams, resumeType string) (scraper.RunOptions, error) {
+	opts := scraper.RunOptions{
+		MaxDuration: time.Duration(p.MaxDuration) * time.Second,
+		Debug:       p.Debug,
+	}
+	var err error
+	if p.Resume != "" {
+		if opts.Resume, err = scraper.ParseResumeToken(p.Resume, resumeType); err != nil {
+			return opts, err
+		}
+	}
+	if p.Profile != "" {
+		if opts.Behavior, err = scraper.LookupBehavior(p.Profile); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,389 @@
+// Package rankings scores stored podium results into per-division athlete
+// rankings: medal points weighted by the size of the event, persisted as
+// models.RankingEntry and queried by division, belt and country.
+package rankings
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recomputeMu keeps recomputes from replacing each other's entries
+var recomputeMu sync.Mutex
+
+// Summary describes a recompute
+type Summary struct {
+	Results    int       `json:"results"` // podium results scored
+	Events     int       `json:"events"`
+	Divisions  int       `json:"divisions"`
+	Entries    int       `json:"entries"`
+	ComputedAt time.Time `json:"computed_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Recompute scores every stored podium result with cfg and replaces the
+// stored ranking entries
+func Recompute(cfg config.RankingsConfig) (*Summary, error) {
+	recomputeMu.Lock()
+	defer recomputeMu.Unlock()
+
+	started := time.Now()
+	db := config.GetDB()
+
+	var results []models.EventResult
+	if err := db.Where("medal <> ''").Order("id").Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("error loading results: %w", err)
+	}
+
+	weights, err := eventWeights(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	countries, err := loadCountries(db)
+	if err != nil {
+		return nil, err
+	}
+
+	type entryKey struct{ division, athlete string }
+	entries := make(map[entryKey]*models.RankingEntry)
+	events := make(map[string]bool)
+	for _, result := range results {
+		athlete := result.AthleteExternalID
+		if athlete == "" {
+			athlete = "name:" + strings.ToLower(result.AthleteName)
+		}
+		key := entryKey{result.Division, athlete}
+		entry := entries[key]
+		if entry == nil {
+			entry = &models.RankingEntry{
+				Division:          result.Division,
+				Belt:              DivisionBelt(result.Division),
+				AthleteExternalID: result.AthleteExternalID,
+				AthleteName:       result.AthleteName,
+			}
+			entries[key] = entry
+		}
+		// Later results carry the current academy and stored athlete
+		if result.AthleteID != 0 {
+			entry.AthleteID = result.AthleteID
+		}
+		if result.AcademyExternalID != "" || result.AcademyName != "" {
+			entry.AcademyExternalID = result.AcademyExternalID
+			entry.AcademyName = result.AcademyName
+		}
+		if country := countries.of(result); country != "" {
+			entry.Country = country
+		}
+
+		points := 0.0
+		switch result.Medal {
+		case models.MedalGold:
+			entry.Gold++
+			points = cfg.GoldPoints
+		case models.MedalSilver:
+			entry.Silver++
+			points = cfg.SilverPoints
+		case models.MedalBronze:
+			entry.Bronze++
+			points = cfg.BronzePoints
+		}
+		weight, ok := weights[result.EventID]
+		if !ok {
+			weight = 1
+		}
+		entry.Points += points * weight
+		events[result.EventID] = true
+	}
+
+	now := time.Now()
+	byDivision := make(map[string][]*models.RankingEntry)
+	for _, entry := range entries {
+		entry.Points = math.Round(entry.Points*100) / 100
+		entry.ComputedAt = now
+		byDivision[entry.Division] = append(byDivision[entry.Division], entry)
+	}
+	rows := make([]*models.RankingEntry, 0, len(entries))
+	for _, division := range byDivision {
+		rankEntries(division)
+		rows = append(rows, division...)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Division != rows[j].Division {
+			return rows[i].Division < rows[j].Division
+		}
+		return rows[i].Rank < rows[j].Rank
+	})
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.RankingEntry{}).Error; err != nil {
+			return fmt.Errorf("error clearing rankings: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+			return fmt.Errorf("error saving rankings: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Results:    len(results),
+		Events:     len(events),
+		Divisions:  len(byDivision),
+		Entries:    len(rows),
+		ComputedAt: now,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	logger.Info("Rankings recomputed",
+		zap.Int("results", summary.Results),
+		zap.Int("divisions", summary.Divisions),
+		zap.Int("entries", summary.Entries))
+	return summary, nil
+}
+
+// rankEntries orders the entries of one division by points, then golds,
+// silvers and bronzes, and numbers them. Entries tied on all four share a
+// rank and the next one skips the shared places (1, 1, 3).
+func rankEntries(entries []*models.RankingEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !tied(a, b) {
+			return ahead(a, b)
+		}
+		return a.AthleteName < b.AthleteName
+	})
+	for i, entry := range entries {
+		if i > 0 && tied(entries[i-1], entry) {
+			entry.Rank = entries[i-1].Rank
+			continue
+		}
+		entry.Rank = i + 1
+	}
+}
+
+func tied(a, b *models.RankingEntry) bool {
+	return a.Points == b.Points && a.Gold == b.Gold && a.Silver == b.Silver && a.Bronze == b.Bronze
+}
+
+func ahead(a, b *models.RankingEntry) bool {
+	if a.Points != b.Points {
+		return a.Points > b.Points
+	}
+	if a.Gold != b.Gold {
+		return a.Gold > b.Gold
+	}
+	if a.Silver != b.Silver {
+		return a.Silver > b.Silver
+	}
+	return a.Bronze > b.Bronze
+}
+
+// eventWeights returns the weight of each event with stored registrations.
+// Events without registrations weigh 1.
+func eventWeights(db *gorm.DB, cfg config.RankingsConfig) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	if cfg.ReferenceEventSize <= 0 {
+		return weights, nil
+	}
+
+	var sizes []struct {
+		EventID string
+		Size    int
+	}
+	if err := db.Model(&models.EventRegistration{}).Select("event_id, COUNT(*) AS size").
+		Group("event_id").Scan(&sizes).Error; err != nil {
+		return nil, fmt.Errorf("error counting registrations: %w", err)
+	}
+	for _, event := range sizes {
+		weight := float64(event.Size) / float64(cfg.ReferenceEventSize)
+		weights[event.EventID] = math.Min(math.Max(weight, cfg.MinEventWeight), cfg.MaxEventWeight)
+	}
+	return weights, nil
+}
+
+// countryIndex holds the country codes of the stored athletes and academies
+type countryIndex struct {
+	athletes  map[uint]string
+	academies map[string]string
+}
+
+// of returns the country of the athlete of result, falling back to the
+// country of their academy
+func (c countryIndex) of(result models.EventResult) string {
+	if country := c.athletes[result.AthleteID]; country != "" {
+		return country
+	}
+	return c.academies[result.AcademyExternalID]
+}
+
+func loadCountries(db *gorm.DB) (countryIndex, error) {
+	index := countryIndex{athletes: map[uint]string{}, academies: map[string]string{}}
+
+	var athletes []models.Athlete
+	if err := db.Select("id", "country_code").Where("country_code <> ''").Find(&athletes).Error; err != nil {
+		return index, fmt.Errorf("error loading athlete countries: %w", err)
+	}
+	for _, athlete := range athletes {
+		index.athletes[uint(athlete.ID)] = strings.ToUpper(athlete.CountryCode)
+	}
+
+	var academies []models.Academy
+	if err := db.Select("external_id", "country_code").Where("country_code <> ''").Find(&academies).Error; err != nil {
+		return index, fmt.Errorf("error loading academy countries: %w", err)
+	}
+	for _, academy := range academies {
+		index.academies[academy.ExternalID] = strings.ToUpper(academy.CountryCode)
+	}
+	return index, nil
+}
+
+// beltColors are the belt colors recognized in division names
+var beltColors = map[string]bool{
+	"white": true, "grey": true, "gray": true, "yellow": true, "orange": true,
+	"green": true, "blue": true, "purple": true, "brown": true, "black": true,
+}
+
+// DivisionBelt returns the belt color of a division such as
+// "Men / Adults / Blue / -76 kg" ("Blue"), or "" when none is listed
+func DivisionBelt(division string) string {
+	for _, segment := range strings.Split(division, "/") {
+		words := strings.Fields(strings.ToLower(segment))
+		if len(words) == 0 || !beltColors[words[0]] {
+			continue
+		}
+		color := words[0]
+		if color == "gray" {
+			color = "grey"
+		}
+		return strings.ToUpper(color[:1]) + color[1:]
+	}
+	return ""
+}
+
+// Filter narrows a ranking query; empty fields match everything
+type Filter struct {
+	Division string
+	Belt     string // a belt color, "Blue" or "blue belt"
+	Country  string
+	Page     int
+	Limit    int
+}
+
+// Standing is an athlete's position in a ranking. Without a division filter
+// it adds up the athlete's entries across the matching divisions.
+type Standing struct {
+	Rank              int     `json:"rank"`
+	AthleteID         uint    `json:"athlete_id,omitempty"`
+	AthleteExternalID string  `json:"athlete_external_id"`
+	AthleteName       string  `json:"athlete_name"`
+	AcademyName       string  `json:"academy_name,omitempty"`
+	Country           string  `json:"country,omitempty"`
+	Divisions         int     `json:"divisions"`
+	Gold              int     `json:"gold"`
+	Silver            int     `json:"silver"`
+	Bronze            int     `json:"bronze"`
+	Points            float64 `json:"points"`
+}
+
+// Page is one page of a ranking
+type Page struct {
+	Division   string     `json:"division,omitempty"`
+	Belt       string     `json:"belt,omitempty"`
+	Country    string     `json:"country,omitempty"`
+	Standings  []Standing `json:"standings"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	Total      int64      `json:"total"`
+	ComputedAt *time.Time `json:"computed_at,omitempty"` // nil before the first recompute
+}
+
+// Query ranks the athletes of the stored entries matching filter by their
+// total points. Ranks count from the top of the whole ranking, so they hold
+// across pages.
+func Query(filter Filter) (*Page, error) {
+	db := config.GetDB()
+	page := &Page{
+		Division:  filter.Division,
+		Belt:      filter.Belt,
+		Country:   strings.ToUpper(filter.Country),
+		Standings: []Standing{},
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+	}
+
+	var computed []time.Time
+	if err := db.Model(&models.RankingEntry{}).Order("computed_at DESC").Limit(1).
+		Pluck("computed_at", &computed).Error; err != nil {
+		return nil, err
+	}
+	if len(computed) > 0 {
+		page.ComputedAt = &computed[0]
+	}
+
+	entries := db.Model(&models.RankingEntry{})
+	if filter.Division != "" {
+		entries = entries.Where("LOWER(division) = ?", strings.ToLower(strings.TrimSpace(filter.Division)))
+	}
+	if belt := normalizeBelt(filter.Belt); belt != "" {
+		entries = entries.Where("LOWER(belt) = ?", belt)
+	}
+	if page.Country != "" {
+		entries = entries.Where("country = ?", page.Country)
+	}
+
+	grouped := entries.Select(`MAX(athlete_id) AS athlete_id, athlete_external_id, athlete_name,
+		MAX(academy_name) AS academy_name, MAX(country) AS country, COUNT(*) AS divisions,
+		SUM(gold) AS gold, SUM(silver) AS silver, SUM(bronze) AS bronze, SUM(points) AS points`).
+		Group("athlete_external_id, athlete_name")
+
+	// Ties share a rank, so every standing is numbered before the page is cut
+	var standings []Standing
+	if err := db.Table("(?) AS standings", grouped).
+		Order("points DESC, gold DESC, silver DESC, bronze DESC, athlete_name").
+		Scan(&standings).Error; err != nil {
+		return nil, err
+	}
+	for i := range standings {
+		standings[i].Points = math.Round(standings[i].Points*100) / 100
+		if i > 0 && sameStanding(standings[i-1], standings[i]) {
+			standings[i].Rank = standings[i-1].Rank
+			continue
+		}
+		standings[i].Rank = i + 1
+	}
+
+	page.Total = int64(len(standings))
+	offset := (filter.Page - 1) * filter.Limit
+	if offset < len(standings) {
+		end := offset + filter.Limit
+		if end > len(standings) {
+			end = len(standings)
+		}
+		page.Standings = standings[offset:end]
+	}
+	return page, nil
+}
+
+func sameStanding(a, b Standing) bool {
+	return a.Points == b.Points && a.Gold == b.Gold && a.Silver == b.Silver && a.Bronze == b.Bronze
+}
+
+// normalizeBelt turns "Blue belt" or "BLUE" into "blue"
+func normalizeBelt(belt string) string {
+	belt = strings.ToLower(strings.TrimSpace(belt))
+	return strings.TrimSpace(strings.TrimSuffix(belt, "belt"))
+}
@@ -0,0 +1,266 @@
+// Package savedquery runs named filter sets over the stored athletes and
+// registrations and notifies when their results change after a scrape.
+package savedquery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxRows is the most rows a run returns
+const MaxRows = 500
+
+// maxNotifiedIDs caps the added and removed IDs listed in a notification
+const maxNotifiedIDs = 50
+
+// Result is one page of the rows selected by a saved query
+type Result struct {
+	Total int64       `json:"total"`
+	Rows  interface{} `json:"rows"` // []models.Athlete or []models.EventRegistration
+}
+
+// Change is sent to the webhook of a saved query whose rows changed
+type Change struct {
+	QueryID int    `json:"query_id"`
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Total   int    `json:"total"`
+	Added   []uint `json:"added"`   // athlete or registration IDs, at most 50
+	Removed []uint `json:"removed"` // athlete or registration IDs, at most 50
+}
+
+// Validate checks that the filters make sense for the target
+func Validate(q *models.SavedQuery) error {
+	if strings.TrimSpace(q.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !models.IsSavedQueryTarget(q.Target) {
+		return fmt.Errorf("target must be %q or %q", models.SavedQueryAthletes, models.SavedQueryRegistrations)
+	}
+	f := q.Filters
+	if q.Target == models.SavedQueryAthletes &&
+		(f.AgeCategory != "" || f.WeightClass != "" || f.EventID != "" || f.Kids != nil) {
+		return errors.New("age_category, weight_class, event_id and kids filter registrations only")
+	}
+	if f.Gender != "" {
+		if gender, _ := models.ParseGender(f.Gender); gender == models.GenderUnknown {
+			return fmt.Errorf("unknown gender %q", f.Gender)
+		}
+	}
+	return nil
+}
+
+// Run returns the rows selected by q, most recent first
+func Run(q *models.SavedQuery, limit, offset int) (*Result, error) {
+	if limit < 1 || limit > MaxRows {
+		limit = MaxRows
+	}
+
+	query := filtered(q)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: total}
+	switch q.Target {
+	case models.SavedQueryAthletes:
+		athletes := []models.Athlete{}
+		err := query.Preload("Academy").Order("athletes.total_wins DESC, athletes.id").
+			Limit(limit).Offset(offset).Find(&athletes).Error
+		result.Rows = athletes
+		return result, err
+	default:
+		registrations := []models.EventRegistration{}
+		err := query.Preload("Athlete").Order("event_registrations.id DESC").
+			Limit(limit).Offset(offset).Find(&registrations).Error
+		result.Rows = registrations
+		return result, err
+	}
+}
+
+// filtered builds the query of a saved query without ordering or paging
+func filtered(q *models.SavedQuery) *gorm.DB {
+	db := config.GetDB()
+	f := q.Filters
+
+	if q.Target == models.SavedQueryAthletes {
+		query := db.Model(&models.Athlete{})
+		if f.Country != "" {
+			query = query.Where("athletes.country_code = ?", strings.ToUpper(f.Country))
+		}
+		if f.AcademyID != "" {
+			query = query.Where("athletes.academy_external_id = ?", f.AcademyID)
+		}
+		if gender, _ := models.ParseGender(f.Gender); gender != models.GenderUnknown {
+			query = query.Where("athletes.gender = ?", gender)
+		}
+		if f.Belt != "" {
+			query = query.Where(contains("athletes.belt_rank"), like(f.Belt))
+		}
+		return query
+	}
+
+	query := db.Model(&models.EventRegistration{})
+	if f.Country != "" || f.AcademyID != "" {
+		query = query.Joins("JOIN athletes ON athletes.id = event_registrations.athlete_id")
+		if f.Country != "" {
+			query = query.Where("athletes.country_code = ?", strings.ToUpper(f.Country))
+		}
+		if f.AcademyID != "" {
+			query = query.Where("athletes.academy_external_id = ?", f.AcademyID)
+		}
+	}
+	if gender, _ := models.ParseGender(f.Gender); gender != models.GenderUnknown {
+		query = query.Where("event_registrations.gender = ?", gender)
+	}
+	if f.Belt != "" {
+		query = query.Where(contains("event_registrations.rank"), like(f.Belt))
+	}
+	if f.AgeCategory != "" {
+		query = query.Where(contains("event_registrations.age_category"), like(f.AgeCategory))
+	}
+	if f.WeightClass != "" {
+		query = query.Where(contains("event_registrations.weight_class"), like(f.WeightClass))
+	}
+	if f.EventID != "" {
+		query = query.Where("event_registrations.event_id = ?", f.EventID)
+	}
+	if f.Kids != nil {
+		query = query.Where("event_registrations.is_kids = ?", *f.Kids)
+	}
+	return query
+}
+
+// contains matches column case-insensitively against a like pattern
+func contains(column string) string {
+	return "LOWER(" + column + `) LIKE ? ESCAPE '\'`
+}
+
+func like(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(strings.TrimSpace(s)))
+	return "%" + s + "%"
+}
+
+// Snapshot records the rows q selects now as its baseline, without notifying
+func Snapshot(q *models.SavedQuery) error {
+	ids, err := resultIDs(q)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	q.ResultIDs = ids
+	q.ResultCount = len(ids)
+	q.LastRunAt = &now
+	return config.GetDB().Model(q).Select("result_ids", "result_count", "last_run_at").Updates(q).Error
+}
+
+// CheckAll runs every saved query that has a webhook and notifies those
+// whose rows changed since the previous check. Called after scrapes.
+func CheckAll(cfg *config.Config) {
+	var queries []models.SavedQuery
+	if err := config.GetDB().Where("webhook_url <> ''").Find(&queries).Error; err != nil {
+		logger.Error("Failed to load saved queries", zap.Error(err))
+		return
+	}
+
+	for i := range queries {
+		if err := check(cfg, &queries[i]); err != nil {
+			logger.Warn("Failed to check saved query",
+				zap.Int("query_id", queries[i].ID),
+				zap.Error(err))
+		}
+	}
+}
+
+func check(cfg *config.Config, q *models.SavedQuery) error {
+	ids, err := resultIDs(q)
+	if err != nil {
+		return err
+	}
+	added, removed := diff(q.ResultIDs, ids)
+
+	now := time.Now()
+	q.ResultIDs = ids
+	q.ResultCount = len(ids)
+	q.LastRunAt = &now
+	columns := []string{"result_ids", "result_count", "last_run_at"}
+	if len(added) > 0 || len(removed) > 0 {
+		q.LastChangedAt = &now
+		columns = append(columns, "last_changed_at")
+	}
+	if err := config.GetDB().Model(q).Select(columns).Updates(q).Error; err != nil {
+		return err
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	change := Change{
+		QueryID: q.ID,
+		Name:    q.Name,
+		Target:  q.Target,
+		Total:   len(ids),
+		Added:   added[:min(len(added), maxNotifiedIDs)],
+		Removed: removed[:min(len(removed), maxNotifiedIDs)],
+	}
+	n := notify.Notification{
+		Kind:   "saved_query_changed",
+		Title:  fmt.Sprintf("%s: %d new, %d gone", q.Name, len(added), len(removed)),
+		Text:   fmt.Sprintf("Saved query %q now selects %d %s (%d new, %d gone).", q.Name, len(ids), q.Target, len(added), len(removed)),
+		Data:   change,
+		SentAt: now,
+	}
+	webhook := notify.NewWebhook(q.WebhookURL, cfg.Notifications.Timeout)
+	if err := webhook.Send(n); err != nil {
+		return fmt.Errorf("notify %s: %w", webhook.Name(), err)
+	}
+
+	logger.Info("Saved query change notified",
+		zap.Int("query_id", q.ID),
+		zap.Int("added", len(added)),
+		zap.Int("removed", len(removed)))
+	return nil
+}
+
+// resultIDs lists the IDs of every row q selects, sorted
+func resultIDs(q *models.SavedQuery) ([]uint, error) {
+	column := "athletes.id"
+	if q.Target == models.SavedQueryRegistrations {
+		column = "event_registrations.id"
+	}
+	ids := []uint{}
+	if err := filtered(q).Order(column).Pluck(column, &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// diff compares two sorted ID lists
+func diff(before, after []uint) (added, removed []uint) {
+	seen := make(map[uint]bool, len(before))
+	for _, id := range before {
+		seen[id] = true
+	}
+	for _, id := range after {
+		if !seen[id] {
+			added = append(added, id)
+		}
+		delete(seen, id)
+	}
+	for id := range seen {
+		removed = append(removed, id)
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	return added, removed
+}
@@ -1,10 +1,17 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/internal/scraper"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"github.com/robfig/cron/v3"
@@ -12,22 +19,101 @@ import (
 )
 
 type Scheduler struct {
-	cron      *cron.Cron
-	config    *config.Config
-	scraper   *scraper.Scraper
-	isRunning bool
-	mu        sync.RWMutex
-	entryID   cron.EntryID
+	cron    *cron.Cron
+	config  *config.Config
+	scraper *scraper.Scraper
+	mu      sync.RWMutex
+	entries map[int]cron.EntryID  // schedule config ID -> cron entry
+	running map[int]bool          // schedule config IDs with a job in progress
+	retries map[int]*postponedRun // schedule config ID -> run postponed by maintenance
+}
+
+// postponedRun is a scheduled run stopped by a Smoothcomp maintenance page,
+// waiting to run again
+type postponedRun struct {
+	at    time.Time
+	timer *time.Timer
 }
 
 // NewScheduler creates a new scheduler instance
 func NewScheduler(cfg *config.Config) *Scheduler {
 	return &Scheduler{
-		cron:      cron.New(),
-		config:    cfg,
-		scraper:   scraper.NewScraper(cfg),
-		isRunning: false,
+		cron:    cron.New(),
+		config:  cfg,
+		scraper: scraper.NewScraper(cfg),
+		entries: make(map[int]cron.EntryID),
+		running: make(map[int]bool),
+		retries: make(map[int]*postponedRun),
+	}
+}
+
+// Scheduled job types
+const (
+	JobTypeAll            = "all"             // full academy + athlete scrape
+	JobTypeEnrichStale    = "enrich_stale"    // re-enrich profiles older than the stale policy
+	JobTypeAcademies      = "academies"       // academy listings of the target countries
+	JobTypeEventsUpcoming = "events_upcoming" // upcoming events, down to params.depth
+	JobTypeEventsPast     = "events_past"     // past events, down to params.depth
+)
+
+// JobTypes lists the job types a schedule can run
+var JobTypes = []string{JobTypeAll, JobTypeEnrichStale, JobTypeAcademies, JobTypeEventsUpcoming, JobTypeEventsPast}
+
+// ValidateJobType checks that a schedule job type is known. Empty means JobTypeAll.
+func ValidateJobType(jobType string) error {
+	if jobType == "" {
+		return nil
+	}
+	for _, known := range JobTypes {
+		if jobType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid job type %q (expected one of %s)", jobType, strings.Join(JobTypes, ", "))
+}
+
+// ValidateSchedule checks the name, cron expression, job type and the
+// parameters that job type accepts
+func ValidateSchedule(schedule models.ScheduleConfig) error {
+	if strings.TrimSpace(schedule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if err := ValidateCronExpr(schedule.CronExpr); err != nil {
+		return err
 	}
+	if err := ValidateJobType(schedule.JobType); err != nil {
+		return err
+	}
+
+	p := schedule.Params
+	if p.Limit < 0 || p.MaxAgeDays < 0 || p.MaxDuration < 0 {
+		return fmt.Errorf("limit, max_age_days and max_duration cannot be negative")
+	}
+	events := schedule.JobType == JobTypeEventsUpcoming || schedule.JobType == JobTypeEventsPast
+	if !events && (p.Country != "" || p.Depth != "" || p.MaxDuration != 0 || p.Profile != "") {
+		return fmt.Errorf("country, depth, max_duration and profile apply to %s and %s only",
+			JobTypeEventsUpcoming, JobTypeEventsPast)
+	}
+	if schedule.JobType != JobTypeEnrichStale && (p.Limit != 0 || p.MaxAgeDays != 0) {
+		return fmt.Errorf("limit and max_age_days apply to %s only", JobTypeEnrichStale)
+	}
+	if _, err := scraper.ParseDepth(p.Depth); err != nil {
+		return err
+	}
+	if p.Profile != "" {
+		if _, err := scraper.LookupBehavior(p.Profile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCronExpr checks that a cron expression can be parsed by the scheduler
+func ValidateCronExpr(cronExpr string) error {
+	if _, err := cron.ParseStandard(cronExpr); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+	return nil
 }
 
 // Start starts the scheduler
@@ -35,35 +121,27 @@ func (s *Scheduler) Start() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Get schedule config from database
+	// Get schedule configs from database
 	db := config.GetDB()
-	var scheduleConfig struct {
-		CronExpr string
-		Enabled  bool
+	var scheduleConfigs []models.ScheduleConfig
+	if err := db.Where("enabled = ?", true).Find(&scheduleConfigs).Error; err != nil {
+		return err
 	}
 
-	db.Table("schedule_configs").First(&scheduleConfig)
-
-	if !scheduleConfig.Enabled {
+	if len(scheduleConfigs) == 0 {
 		logger.Info("Scheduler is disabled")
-		return nil
 	}
 
-	// Add cron job
-	entryID, err := s.cron.AddFunc(scheduleConfig.CronExpr, func() {
-		logger.Info("Starting scheduled scraping job")
-		s.runScrapingJob()
-	})
-
-	if err != nil {
-		return err
+	for _, scheduleConfig := range scheduleConfigs {
+		if err := s.addEntry(scheduleConfig); err != nil {
+			return err
+		}
 	}
 
-	s.entryID = entryID
 	s.cron.Start()
 
 	logger.Info("Scheduler started successfully",
-		zap.String("schedule", scheduleConfig.CronExpr))
+		zap.Int("schedules", len(s.entries)))
 
 	return nil
 }
@@ -77,79 +155,293 @@ func (s *Scheduler) Stop() {
 		s.cron.Stop()
 		logger.Info("Scheduler stopped")
 	}
+	for scheduleID := range s.retries {
+		s.clearRetry(scheduleID)
+	}
 }
 
-// UpdateSchedule updates the cron schedule
-func (s *Scheduler) UpdateSchedule(cronExpr string) error {
+// ApplySchedule (re)registers a schedule config, removing its previous cron
+// entry. Disabled configs are only removed.
+func (s *Scheduler) ApplySchedule(scheduleConfig models.ScheduleConfig) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Remove old schedule
-	if s.entryID != 0 {
-		s.cron.Remove(s.entryID)
+	s.removeEntry(scheduleConfig.ID)
+
+	if !scheduleConfig.Enabled {
+		logger.Info("Schedule disabled", zap.Int("schedule_id", scheduleConfig.ID))
+		return nil
 	}
 
-	// Add new schedule
-	entryID, err := s.cron.AddFunc(cronExpr, func() {
-		logger.Info("Starting scheduled scraping job")
-		s.runScrapingJob()
-	})
+	if err := s.addEntry(scheduleConfig); err != nil {
+		return err
+	}
 
-	if err != nil {
+	logger.Info("Schedule updated",
+		zap.Int("schedule_id", scheduleConfig.ID),
+		zap.String("new_schedule", scheduleConfig.CronExpr))
+
+	return nil
+}
+
+// Reload re-registers every enabled schedule from the database, picking up
+// rows changed outside the API. Running jobs are not interrupted.
+func (s *Scheduler) Reload() error {
+	var scheduleConfigs []models.ScheduleConfig
+	if err := config.GetDB().Where("enabled = ?", true).Find(&scheduleConfigs).Error; err != nil {
 		return err
 	}
 
-	s.entryID = entryID
-	logger.Info("Schedule updated", zap.String("new_schedule", cronExpr))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for scheduleID := range s.entries {
+		s.removeEntry(scheduleID)
+	}
+	for _, scheduleConfig := range scheduleConfigs {
+		if err := s.addEntry(scheduleConfig); err != nil {
+			return fmt.Errorf("schedule %d: %w", scheduleConfig.ID, err)
+		}
+	}
+
+	logger.Info("Schedules reloaded", zap.Int("schedules", len(s.entries)))
+	return nil
+}
+
+// RemoveSchedule unregisters a schedule config
+func (s *Scheduler) RemoveSchedule(scheduleID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeEntry(scheduleID)
+	logger.Info("Schedule removed", zap.Int("schedule_id", scheduleID))
+}
 
+func (s *Scheduler) addEntry(scheduleConfig models.ScheduleConfig) error {
+	entryID, err := s.cron.AddFunc(scheduleConfig.CronExpr, func() {
+		logger.Info("Starting scheduled scraping job",
+			zap.Int("schedule_id", scheduleConfig.ID),
+			zap.String("schedule", scheduleConfig.Name),
+			zap.String("job_type", scheduleConfig.JobType))
+		s.runScrapingJob(scheduleConfig, 0)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.entries[scheduleConfig.ID] = entryID
 	return nil
 }
 
-// IsRunning returns whether a scraping job is currently running
+func (s *Scheduler) removeEntry(scheduleID int) {
+	if entryID, ok := s.entries[scheduleID]; ok {
+		s.cron.Remove(entryID)
+		delete(s.entries, scheduleID)
+	}
+	s.clearRetry(scheduleID)
+}
+
+func (s *Scheduler) clearRetry(scheduleID int) {
+	if retry, ok := s.retries[scheduleID]; ok {
+		retry.timer.Stop()
+		delete(s.retries, scheduleID)
+	}
+}
+
+// IsRunning returns whether any scheduled job is currently running
 func (s *Scheduler) IsRunning() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.isRunning
+	return len(s.running) > 0
 }
 
-// GetNextRun returns the next scheduled run time
-func (s *Scheduler) GetNextRun() *time.Time {
+// Describe fills the next run and running flag of schedules from the cron
+// entries
+func (s *Scheduler) Describe(schedules []models.ScheduleConfig) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.entryID == 0 {
-		return nil
+	for i := range schedules {
+		schedules[i].Running = s.running[schedules[i].ID]
+		schedules[i].NextRun = nil
+		schedules[i].PostponedUntil = nil
+		if retry, ok := s.retries[schedules[i].ID]; ok {
+			at := retry.at
+			schedules[i].PostponedUntil = &at
+		}
+		if entryID, ok := s.entries[schedules[i].ID]; ok {
+			if next := s.cron.Entry(entryID).Next; !next.IsZero() {
+				schedules[i].NextRun = &next
+			}
+		}
 	}
+}
+
+// GetNextRun returns the next scheduled run time across all schedules
+func (s *Scheduler) GetNextRun() *time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	entry := s.cron.Entry(s.entryID)
-	nextRun := entry.Next
-	return &nextRun
+	var nextRun *time.Time
+	for _, entryID := range s.entries {
+		next := s.cron.Entry(entryID).Next
+		if next.IsZero() {
+			continue
+		}
+		if nextRun == nil || next.Before(*nextRun) {
+			nextRun = &next
+		}
+	}
+	return nextRun
 }
 
-// runScrapingJob executes the scraping job
-func (s *Scheduler) runScrapingJob() {
+// runScrapingJob executes the job of a schedule. A schedule whose previous
+// run is still in progress skips this execution; other schedules run
+// independently. A run stopped by a Smoothcomp maintenance page is postponed
+// for MAINTENANCE_RETRY_MINUTES, up to MAINTENANCE_MAX_POSTPONES times;
+// postpones counts the times this run already was.
+func (s *Scheduler) runScrapingJob(schedule models.ScheduleConfig, postpones int) {
 	s.mu.Lock()
-	if s.isRunning {
-		logger.Warn("Scraping job already running, skipping this execution")
+	if s.running[schedule.ID] {
+		logger.Warn("Scheduled job already running, skipping this execution",
+			zap.Int("schedule_id", schedule.ID),
+			zap.String("schedule", schedule.Name))
 		s.mu.Unlock()
+		publishSchedule(bus.ScheduleSkipped, schedule, nil)
 		return
 	}
-	s.isRunning = true
+	s.running[schedule.ID] = true
+	// A regular run replaces a postponed one
+	s.clearRetry(schedule.ID)
 	s.mu.Unlock()
 
 	defer func() {
 		s.mu.Lock()
-		s.isRunning = false
+		delete(s.running, schedule.ID)
 		s.mu.Unlock()
 	}()
 
-	logger.Info("Executing scheduled scraping job")
+	logger.Info("Executing scheduled scraping job",
+		zap.Int("schedule_id", schedule.ID),
+		zap.String("job_type", schedule.JobType))
+	publishSchedule(bus.ScheduleStarted, schedule, nil)
 
-	// Run scraping
-	if err := s.scraper.ScrapeAll(); err != nil {
-		logger.Error("Scheduled scraping job failed", zap.Error(err))
+	err := s.runJob(context.Background(), schedule.JobType, schedule.Params)
+	publishSchedule(bus.ScheduleFinished, schedule, err)
+	if errors.Is(err, maintenance.ErrActive) && postpones < s.config.Maintenance.MaxPostpones {
+		s.postpone(schedule, postpones+1)
+		return
+	}
+	if err != nil {
+		logger.Error("Scheduled scraping job failed",
+			zap.Int("schedule_id", schedule.ID),
+			zap.String("schedule", schedule.Name),
+			zap.Error(err))
 		return
 	}
 
-	logger.Info("Scheduled scraping job completed successfully")
+	logger.Info("Scheduled scraping job completed successfully",
+		zap.Int("schedule_id", schedule.ID),
+		zap.String("schedule", schedule.Name))
+}
+
+// postpone runs the job of a schedule again after the maintenance delay,
+// unless the schedule was removed or disabled meanwhile
+func (s *Scheduler) postpone(schedule models.ScheduleConfig, postpones int) {
+	delay := s.config.Maintenance.RetryDelay
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[schedule.ID]; !ok {
+		return
+	}
+	s.clearRetry(schedule.ID)
+	s.retries[schedule.ID] = &postponedRun{
+		at: time.Now().Add(delay),
+		timer: time.AfterFunc(delay, func() {
+			s.runScrapingJob(schedule, postpones)
+		}),
+	}
+
+	logger.Warn("Scheduled job postponed by Smoothcomp maintenance",
+		zap.Int("schedule_id", schedule.ID),
+		zap.String("schedule", schedule.Name),
+		zap.Duration("delay", delay),
+		zap.Int("postpones", postpones))
+}
+
+// publishSchedule announces a schedule execution on the bus; err is the
+// outcome of a finished one
+func publishSchedule(eventType string, schedule models.ScheduleConfig, err error) {
+	event := bus.Event{
+		Type:       eventType,
+		JobType:    schedule.JobType,
+		ScheduleID: schedule.ID,
+		Schedule:   schedule.Name,
+	}
+	if eventType == bus.ScheduleFinished {
+		event.Status = "completed"
+		if err != nil {
+			event.Status = "failed"
+			if errors.Is(err, maintenance.ErrActive) {
+				event.Status = maintenance.StatusPostponed
+			}
+			event.Error = err.Error()
+		}
+	}
+	bus.Publish(event)
+}
+
+func (s *Scheduler) runJob(ctx context.Context, jobType string, params models.ScheduleParams) error {
+	switch jobType {
+	case JobTypeEnrichStale:
+		cfg := s.config.Latest()
+		maxAge, limit := cfg.Scheduler.StaleProfileAge, cfg.Scheduler.StaleProfileBatch
+		if params.MaxAgeDays > 0 {
+			maxAge = time.Duration(params.MaxAgeDays) * 24 * time.Hour
+		}
+		if params.Limit > 0 {
+			limit = params.Limit
+		}
+		_, err := s.scraper.EnrichStaleAthletes(ctx, maxAge, limit)
+		return err
+	case JobTypeAcademies:
+		return s.scraper.ScrapeAcademies(ctx)
+	case JobTypeEventsUpcoming, JobTypeEventsPast:
+		return s.runEvents(ctx, strings.TrimPrefix(jobType, "events_"), params)
+	default:
+		return s.scraper.ScrapeAll(ctx)
+	}
+}
+
+// runEvents scrapes the events of params.country, or of every target
+// country when it is empty. Cancelling the job of one country skips the rest.
+func (s *Scheduler) runEvents(ctx context.Context, eventType string, params models.ScheduleParams) error {
+	depth, err := scraper.ParseDepth(params.Depth)
+	if err != nil {
+		return err
+	}
+	opts := scraper.RunOptions{MaxDuration: time.Duration(params.MaxDuration) * time.Second}
+	if params.Profile != "" {
+		if opts.Behavior, err = scraper.LookupBehavior(params.Profile); err != nil {
+			return err
+		}
+	}
+
+	countries := s.config.Latest().Scraper.TargetCountries
+	if params.Country != "" {
+		countries = []string{strings.ToUpper(params.Country)}
+	}
+
+	var errs []error
+	for _, country := range countries {
+		if err := s.scraper.ScrapeEvents(ctx, eventType, country, depth, opts); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", country, err))
+			if scraper.IsCancelled(err) || errors.Is(err, maintenance.ErrActive) {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,205 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// contactBlocks are the parts of a club page that list its contact details;
+// emails and phones are only looked for as text inside them
+const contactBlocks = ".club-contact, .contact, .club-info, [itemprop=address], address"
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+	// phonePattern only trusts numbers in text that are labeled or international,
+	// so street numbers and postal codes are not taken for phones
+	phonePattern = regexp.MustCompile(`(?i)(?:phone|tel[eé]fono|telefone|tel|whatsapp|celular|mobile)\.?\s*:?\s*(\+?\(?\d[\d\s().\-]{5,}\d)|(\+\d[\d\s().\-]{5,}\d)`)
+
+	// addressLabel is a leading "Address:" in the languages Smoothcomp uses
+	addressLabel = regexp.MustCompile(`(?i)^(address|direcci[oó]n|endere[cç]o|adresse)\s*:?\s*`)
+)
+
+// parseClubContact reads the email, phone and address a club page lists:
+// mailto: and tel: links first, then the text of its contact blocks
+func parseClubContact(e *colly.HTMLElement, academy *models.Academy) {
+	for _, href := range e.ChildAttrs("a[href^='mailto:']", "href") {
+		if email := normalizeEmail(strings.TrimPrefix(strings.SplitN(href, "?", 2)[0], "mailto:")); email != "" {
+			academy.ContactEmail = email
+			break
+		}
+	}
+	for _, href := range e.ChildAttrs("a[href^='tel:']", "href") {
+		if phone := normalizePhone(strings.TrimPrefix(href, "tel:")); phone != "" {
+			academy.ContactPhone = phone
+			break
+		}
+	}
+
+	address := e.ChildText("[itemprop=address], address, .club-address, .address")
+	// Address blocks often list the email and phone too
+	address = phonePattern.ReplaceAllString(emailPattern.ReplaceAllString(address, " "), " ")
+	address = strings.Trim(strings.Join(strings.Fields(address), " "), " ,;|-")
+	academy.ContactAddress = addressLabel.ReplaceAllString(address, "")
+
+	if academy.ContactEmail != "" && academy.ContactPhone != "" {
+		return
+	}
+	e.ForEach(contactBlocks, func(_ int, block *colly.HTMLElement) {
+		if academy.ContactEmail == "" {
+			for _, match := range emailPattern.FindAllString(block.Text, -1) {
+				if email := normalizeEmail(match); email != "" {
+					academy.ContactEmail = email
+					break
+				}
+			}
+		}
+		if academy.ContactPhone == "" {
+			for _, match := range phonePattern.FindAllStringSubmatch(block.Text, -1) {
+				if phone := normalizePhone(match[1] + match[2]); phone != "" {
+					academy.ContactPhone = phone
+					break
+				}
+			}
+		}
+	})
+}
+
+// normalizeEmail lowercases an address, rejecting malformed ones and
+// Smoothcomp's own
+func normalizeEmail(value string) string {
+	email := strings.ToLower(strings.TrimSpace(value))
+	if emailPattern.FindString(email) != email || strings.HasSuffix(email, "smoothcomp.com") {
+		return ""
+	}
+	return email
+}
+
+// normalizePhone keeps the digits and a leading +; numbers with fewer than
+// 7 or more than 15 digits (years, IDs, scores) are rejected
+func normalizePhone(value string) string {
+	value = strings.TrimSpace(value)
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	if digits.Len() < 7 || digits.Len() > 15 {
+		return ""
+	}
+	if strings.HasPrefix(value, "+") {
+		return "+" + digits.String()
+	}
+	return digits.String()
+}
+
+// applyContactPolicy clears the contact details ACADEMY_CONTACT_FIELDS leaves
+// out, and all of them for an academy that opted out of contact
+func (s *Scraper) applyContactPolicy(tx *gorm.DB, academy *models.Academy) {
+	if contactOptedOut(tx, academy.ExternalID) {
+		clearContact(academy)
+		return
+	}
+	kept := make(map[string]bool, len(s.config.Scraper.AcademyContactFields))
+	for _, field := range s.config.Scraper.AcademyContactFields {
+		kept[field] = true
+	}
+	if !kept["email"] {
+		academy.ContactEmail = ""
+	}
+	if !kept["phone"] {
+		academy.ContactPhone = ""
+	}
+	if !kept["address"] {
+		academy.ContactAddress = ""
+	}
+}
+
+func contactOptedOut(tx *gorm.DB, externalID string) bool {
+	var count int64
+	tx.Model(&models.AcademyContactOptOut{}).Where("external_id = ?", externalID).Count(&count)
+	return count > 0
+}
+
+func clearContact(academy *models.Academy) {
+	academy.ContactEmail = ""
+	academy.ContactPhone = ""
+	academy.ContactAddress = ""
+}
+
+// OptOutAcademyContact clears an academy's contact details and records the
+// opt-out so later scrapes do not store them again
+func OptOutAcademyContact(academy models.Academy, reason string, actor string) (*models.AcademyContactOptOut, error) {
+	entry := models.AcademyContactOptOut{
+		ExternalID: academy.ExternalID,
+		Reason:     reason,
+		Actor:      actor,
+	}
+
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Academy{}).Where("id = ?", academy.ID).
+			UpdateColumns(map[string]interface{}{
+				"contact_email":   "",
+				"contact_phone":   "",
+				"contact_address": "",
+			}).Error; err != nil {
+			return fmt.Errorf("error clearing academy contact: %w", err)
+		}
+		return tx.Where(models.AcademyContactOptOut{ExternalID: academy.ExternalID}).
+			Attrs(entry).FirstOrCreate(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Academy opted out of contact",
+		zap.Int("academy_id", academy.ID),
+		zap.String("external_id", academy.ExternalID),
+		zap.String("actor", actor))
+
+	return &entry, nil
+}
+
+// RemoveAcademyContactOptOut lets scrapes store the academy's contact details
+// again; they come back on its next club page scrape. It reports whether
+// there was an opt-out.
+func RemoveAcademyContactOptOut(externalID string) (bool, error) {
+	result := config.GetDB().Where("external_id = ?", externalID).Delete(&models.AcademyContactOptOut{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RedactAcademyContacts clears stored contact details that
+// ACADEMY_CONTACT_FIELDS no longer keeps, so narrowing it takes effect
+// without rescraping every club
+func RedactAcademyContacts(cfg *config.Config) error {
+	kept := make(map[string]bool, len(cfg.Scraper.AcademyContactFields))
+	for _, field := range cfg.Scraper.AcademyContactFields {
+		kept[field] = true
+	}
+
+	db := config.GetDB()
+	for _, field := range []string{"email", "phone", "address"} {
+		if kept[field] {
+			continue
+		}
+		column := "contact_" + field
+		result := db.Model(&models.Academy{}).Where(column+" <> ''").UpdateColumn(column, "")
+		if result.Error != nil {
+			return fmt.Errorf("error redacting academy %s: %w", field, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			logger.Info("Redacted academy contact field",
+				zap.String("field", field),
+				zap.Int64("academies", result.RowsAffected))
+		}
+	}
+	return nil
+}
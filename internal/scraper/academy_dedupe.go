@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// normalizeAcademyName folds accents, case and punctuation so that
+// "Academia Lótus" and "ACADEMIA LOTUS" compare equal
+func normalizeAcademyName(name string) string {
+	return slug.Make(name)
+}
+
+// findAcademyByName looks an academy up by normalized name, preferring one in
+// countryCode. Returns false when none matches.
+func findAcademyByName(tx *gorm.DB, name, countryCode string) (models.Academy, bool) {
+	var academy models.Academy
+	normalized := normalizeAcademyName(name)
+	if normalized == "" {
+		return academy, false
+	}
+
+	err := tx.Where("normalized_name = ?", normalized).
+		Order(gorm.Expr("CASE WHEN country_code = ? THEN 0 ELSE 1 END, id ASC", countryCode)).
+		First(&academy).Error
+	return academy, err == nil
+}
+
+// MergeDuplicateAcademies fills the normalized name of stored academies and
+// merges rows of the same country that share it into the oldest one,
+// repointing their athletes. It is idempotent and safe to run on every
+// startup.
+func MergeDuplicateAcademies() error {
+	db := config.GetDB()
+
+	var missing []models.Academy
+	if err := db.Select("id", "name").Where("normalized_name = '' OR normalized_name IS NULL").Find(&missing).Error; err != nil {
+		return err
+	}
+	for _, academy := range missing {
+		if err := db.Model(&models.Academy{}).Where("id = ?", academy.ID).
+			UpdateColumn("normalized_name", normalizeAcademyName(academy.Name)).Error; err != nil {
+			return err
+		}
+	}
+
+	type duplicateGroup struct {
+		CountryCode    string
+		NormalizedName string
+	}
+	var groups []duplicateGroup
+	if err := db.Model(&models.Academy{}).
+		Select("country_code, normalized_name").
+		Where("normalized_name <> ''").
+		Group("country_code, normalized_name").
+		Having("COUNT(*) > 1").
+		Scan(&groups).Error; err != nil {
+		return err
+	}
+
+	merged := 0
+	for _, group := range groups {
+		var academies []models.Academy
+		if err := db.Where("country_code = ? AND normalized_name = ?", group.CountryCode, group.NormalizedName).
+			Order("id ASC").Find(&academies).Error; err != nil {
+			return err
+		}
+		if len(academies) < 2 {
+			continue
+		}
+
+		keep := academies[0]
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, duplicate := range academies[1:] {
+				if err := tx.Model(&models.Athlete{}).
+					Where("academy_external_id = ?", duplicate.ExternalID).
+					Update("academy_external_id", keep.ExternalID).Error; err != nil {
+					return err
+				}
+				if err := tx.Delete(&models.Academy{}, duplicate.ID).Error; err != nil {
+					return err
+				}
+				logger.Debug("Academy merged",
+					zap.String("name", duplicate.Name),
+					zap.String("external_id", duplicate.ExternalID),
+					zap.String("into", keep.ExternalID))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		merged += len(academies) - 1
+	}
+
+	if merged > 0 {
+		logger.Info("Duplicate academies merged", zap.Int("academies", merged))
+	}
+	return nil
+}
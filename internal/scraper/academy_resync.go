@@ -0,0 +1,239 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/names"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AcademyResyncResult reports what a resync of one academy found and changed
+type AcademyResyncResult struct {
+	JobID         int             `json:"job_id"`
+	Roster        int             `json:"roster"`         // athletes listed on the club members page
+	RosterCreated int             `json:"roster_created"` // athletes not stored before
+	RosterMoved   int             `json:"roster_moved"`   // stored athletes now assigned to the academy
+	RosterError   string          `json:"roster_error,omitempty"`
+	Academy       *models.Academy `json:"academy,omitempty"` // as stored after the resync
+}
+
+// rosterEntry is an athlete linked from a club members page
+type rosterEntry struct {
+	ExternalID string
+	Name       string
+	ProfileURL string
+}
+
+// ResyncAcademy re-scrapes the club page of one stored academy, even when it
+// was visited recently, and its members page, assigning the listed athletes
+// to it, instead of re-running whole-country discovery. The work is recorded
+// as an academy_resync job that CancelJob can stop.
+func (s *Scraper) ResyncAcademy(ctx context.Context, academy models.Academy) (*AcademyResyncResult, error) {
+	if err := checkBlocked(models.BlockedAcademy, academy.ExternalID); err != nil {
+		return nil, err
+	}
+
+	job := s.createJob("academy_resync")
+	ctx, release := trackJob(WithFreshFetch(ctx), job)
+	defer release()
+	runner, finish := s.forJob(job, RunOptions{})
+	defer finish()
+	// Recent visits must not skip the pages being refreshed
+	runner.collector = s.collector.Clone()
+	runner.collector.AllowURLRevisit = true
+
+	result, err := runner.resyncAcademy(ctx, job, academy)
+	switch {
+	case ctx.Err() != nil:
+		s.cancelJob(ctx, job)
+		return nil, context.Cause(ctx)
+	case err != nil:
+		s.failJob(job, err)
+		return nil, err
+	}
+
+	job.ItemsScraped = 1 + result.RosterCreated + result.RosterMoved
+	s.completeJob(job)
+	result.JobID = job.ID
+	return result, nil
+}
+
+func (s *Scraper) resyncAcademy(ctx context.Context, job *models.ScrapeJob, academy models.Academy) (*AcademyResyncResult, error) {
+	clubURL := academy.ClubURL
+	if clubURL == "" {
+		clubURL = fmt.Sprintf("%s/en/club/%s", s.config.Scraper.BaseURL, academy.ExternalID)
+	}
+
+	logger.Info("Resyncing academy",
+		zap.Int("job_id", job.ID),
+		zap.String("academy_id", academy.ExternalID),
+		zap.String("url", clubURL))
+
+	s.progress.phase("details", 1)
+	details, err := s.scrapeAcademyDetails(ctx, clubURL, academy.ExternalID, academy.CountryCode)
+	if err != nil {
+		return nil, err
+	}
+	details.Country = academy.Country
+	if err := s.SaveAcademy(details); err != nil {
+		return nil, err
+	}
+	s.progress.add(1)
+
+	result := &AcademyResyncResult{}
+
+	// A missing members page leaves the details refreshed
+	roster, err := s.scrapeRoster(ctx, strings.TrimSuffix(urlnorm.ClubURL(clubURL), "/")+"/members")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		logger.Warn("Failed to scrape academy roster",
+			zap.String("academy_id", academy.ExternalID),
+			zap.Error(err))
+		result.RosterError = err.Error()
+	}
+	result.Roster = len(roster)
+
+	s.progress.phase("roster", len(roster))
+	err = config.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, entry := range roster {
+			created, moved, err := saveRosterAthlete(tx, details.ExternalID, entry)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.RosterCreated++
+			}
+			if moved {
+				result.RosterMoved++
+			}
+			s.progress.add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stored models.Academy
+	if err := config.GetDB().First(&stored, "external_id = ?", details.ExternalID).Error; err != nil {
+		return nil, fmt.Errorf("error reloading academy: %w", err)
+	}
+	result.Academy = &stored
+
+	logger.Info("Academy resynced",
+		zap.Int("job_id", job.ID),
+		zap.String("academy_id", academy.ExternalID),
+		zap.Int("roster", result.Roster),
+		zap.Int("created", result.RosterCreated),
+		zap.Int("moved", result.RosterMoved))
+
+	return result, nil
+}
+
+// scrapeRoster collects the athlete profiles linked from a club members
+// page, following its pagination
+func (s *Scraper) scrapeRoster(ctx context.Context, membersURL string) ([]rosterEntry, error) {
+	var roster []rosterEntry
+	seen := map[string]bool{}
+
+	c := s.collector.Clone()
+	c.Context = ctx
+
+	c.OnHTML("a[href*='/profile/']", func(e *colly.HTMLElement) {
+		profileURL := urlnorm.ProfileURL(e.Request.AbsoluteURL(e.Attr("href")))
+		externalID := ExtractIDFromURL(profileURL)
+		name := strings.Join(strings.Fields(e.Text), " ")
+		if externalID == "" || seen[externalID] || name == "" {
+			return
+		}
+		seen[externalID] = true
+		roster = append(roster, rosterEntry{ExternalID: externalID, Name: name, ProfileURL: profileURL})
+	})
+
+	var next []string
+	c.OnHTML("a[rel='next']", func(e *colly.HTMLElement) {
+		if href := e.Request.AbsoluteURL(e.Attr("href")); href != "" {
+			next = append(next, href)
+		}
+	})
+
+	visited := map[string]bool{}
+	for pageURL := membersURL; pageURL != "" && !visited[pageURL]; {
+		visited[pageURL] = true
+		next = next[:0]
+		if err := s.visitPage(ctx, c, pageURL); err != nil {
+			return roster, err
+		}
+		c.Wait()
+
+		pageURL = ""
+		if len(next) > 0 {
+			pageURL = next[0]
+		}
+	}
+
+	return roster, nil
+}
+
+// saveRosterAthlete assigns a roster athlete to the academy, creating it
+// from its name when it is not stored yet. Blocklisted athletes are skipped
+// and suppressed ones stored without personal data.
+func saveRosterAthlete(tx *gorm.DB, academyExternalID string, entry rosterEntry) (created bool, moved bool, err error) {
+	if mergedInto := ResolveAthleteID(tx, entry.ExternalID); mergedInto != entry.ExternalID {
+		entry.ExternalID = mergedInto
+		entry.ProfileURL = fmt.Sprintf("https://smoothcomp.com/en/profile/%s", mergedInto)
+	}
+	if IsBlocked(models.BlockedAthlete, entry.ExternalID) {
+		return false, false, nil
+	}
+
+	var athlete models.Athlete
+	result := tx.Where("external_id = ?", entry.ExternalID).Limit(1).Find(&athlete)
+	if result.Error != nil {
+		return false, false, fmt.Errorf("error finding athlete: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		if athlete.AcademyExternalID == academyExternalID {
+			return false, false, nil
+		}
+		if err := tx.Model(&athlete).UpdateColumn("academy_external_id", academyExternalID).Error; err != nil {
+			return false, false, fmt.Errorf("error updating athlete academy: %w", err)
+		}
+		return false, true, nil
+	}
+
+	first, last := names.Split(entry.Name)
+	athlete = models.Athlete{
+		ExternalID:        entry.ExternalID,
+		FirstName:         first,
+		LastName:          last,
+		FullName:          entry.Name,
+		ProfileURL:        entry.ProfileURL,
+		AcademyExternalID: academyExternalID,
+		ScrapedAt:         time.Now(),
+	}
+	slugName := entry.Name
+	if isSuppressed(tx, entry.ExternalID) {
+		scrubPersonalData(&athlete)
+		slugName = ""
+	}
+	err = saveSlugged(tx, &models.Athlete{}, slugName, &athlete.Slug, &athlete.ID, func(tx *gorm.DB) error {
+		return tx.Create(&athlete).Error
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("error creating athlete: %w", err)
+	}
+	return true, false, nil
+}
@@ -1,6 +1,8 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -10,11 +12,15 @@ import (
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/sanitize"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-// ScrapeAcademiesByCountry scrapes academies from a specific country
-func (s *Scraper) ScrapeAcademiesByCountry(countryCode string) ([]models.Academy, error) {
+// ScrapeAcademiesByCountry scrapes academies from a specific country. Also
+// returns how many club pages failed after retries.
+func (s *Scraper) ScrapeAcademiesByCountry(ctx context.Context, countryCode string) ([]models.Academy, int, error) {
 
 	countryName := config.GetCountryName(countryCode)
 	logger.Info("Scraping academies",
@@ -22,12 +28,19 @@ func (s *Scraper) ScrapeAcademiesByCountry(countryCode string) ([]models.Academy
 		zap.String("country_name", countryName))
 
 	var academies []models.Academy
+	failedPages := 0
 
-	// Create a new collector for this country
+	// Create a new collector for this country. The listing is always
+	// fetched; club pages visited recently are skipped (see dbStorage).
 	c := s.collector.Clone()
+	c.Context = ctx
+	c.AllowURLRevisit = true
 
 	// Set up the collector to scrape academy listings
 	c.OnHTML("a[href*='/club/']", func(e *colly.HTMLElement) {
+		if ctx.Err() != nil {
+			return
+		}
 		academyURL := e.Request.AbsoluteURL(e.Attr("href"))
 
 		// Skip if not a valid academy URL
@@ -52,12 +65,25 @@ func (s *Scraper) ScrapeAcademiesByCountry(countryCode string) ([]models.Academy
 			zap.String("id", externalID),
 			zap.String("url", academyURL))
 
+		if IsBlocked(models.BlockedAcademy, externalID) {
+			logger.Info("Skipping blocklisted academy", zap.String("id", externalID))
+			return
+		}
+
 		// Scrape detailed academy info
-		academy, err := s.scrapeAcademyDetails(academyURL, externalID, countryCode)
-		if err != nil {
+		academy, err := s.scrapeAcademyDetails(ctx, academyURL, externalID, countryCode)
+		switch {
+		case isAlreadyVisited(err):
+			logger.Debug("Skipping academy visited recently", zap.String("id", externalID))
+			return
+		case errors.Is(err, ErrPageNotFound):
+			blockMissing(models.BlockedAcademy, externalID, "club page not found")
+			return
+		case err != nil:
 			logger.Error("Failed to scrape academy details",
 				zap.String("academy", name),
 				zap.Error(err))
+			failedPages++
 			return
 		}
 
@@ -66,41 +92,38 @@ func (s *Scraper) ScrapeAcademiesByCountry(countryCode string) ([]models.Academy
 		}
 	})
 
-	c.OnError(func(r *colly.Response, err error) {
-		logger.Error("Scraping error",
-			zap.String("url", r.Request.URL.String()),
-			zap.Error(err))
-	})
-
 	// Visit the academies page filtered by country
 	// We'll start with the general club page and filter later
 	url := fmt.Sprintf("%s/en/club", s.config.Scraper.BaseURL)
 
 	logger.Info("Visiting URL", zap.String("url", url))
 
-	if err := c.Visit(url); err != nil {
-		return nil, fmt.Errorf("failed to visit academies page: %w", err)
+	if err := s.visitPage(ctx, c, url); err != nil {
+		return nil, failedPages, fmt.Errorf("failed to visit academies page: %w", err)
 	}
 
 	c.Wait()
 
 	logger.Info("Finished scraping academies",
 		zap.String("country", countryCode),
-		zap.Int("count", len(academies)))
+		zap.Int("count", len(academies)),
+		zap.Int("failed_pages", failedPages))
 
-	return academies, nil
+	return academies, failedPages, nil
 }
 
 // scrapeAcademyDetails scrapes detailed information from an academy page
-func (s *Scraper) scrapeAcademyDetails(url, externalID, countryCode string) (*models.Academy, error) {
+func (s *Scraper) scrapeAcademyDetails(ctx context.Context, url, externalID, countryCode string) (*models.Academy, error) {
 	logger.Debug("Scraping academy details", zap.String("url", url))
 
 	var academy models.Academy
 	academy.ExternalID = externalID
+	academy.ClubURL = urlnorm.ClubURL(url)
 	academy.CountryCode = countryCode
 	academy.ScrapedAt = time.Now()
 
 	c := s.collector.Clone()
+	c.Context = ctx
 
 	c.OnHTML("body", func(e *colly.HTMLElement) {
 		// Extract academy name
@@ -121,8 +144,10 @@ func (s *Scraper) scrapeAcademyDetails(url, externalID, countryCode string) (*mo
 			academy.CoverURL = e.Request.AbsoluteURL(coverURL)
 		}
 
-		// Extract bio/description
-		academy.Bio = strings.TrimSpace(e.ChildText(".club-bio, .club-description"))
+		// Extract bio/description, keeping its markup for sanitization on save
+		if bio, err := e.DOM.Find(".club-bio, .club-description").First().Html(); err == nil {
+			academy.Bio = bio
+		}
 
 		// Extract statistics
 		e.ForEach(".stat-item, .stats-item", func(_ int, stat *colly.HTMLElement) {
@@ -151,15 +176,11 @@ func (s *Scraper) scrapeAcademyDetails(url, externalID, countryCode string) (*mo
 		academy.Instagram = e.ChildAttr("a[href*='instagram.com']", "href")
 		academy.Facebook = e.ChildAttr("a[href*='facebook.com']", "href")
 
-		// Generate slug from name
-		academy.Slug = generateSlug(academy.Name)
-	})
-
-	c.OnError(func(r *colly.Response, err error) {
-		logger.Error("Error scraping academy details", zap.Error(err))
+		// Email, phone and address, for outreach
+		parseClubContact(e, &academy)
 	})
 
-	if err := c.Visit(url); err != nil {
+	if err := s.visitPage(ctx, c, url); err != nil {
 		return nil, err
 	}
 
@@ -181,22 +202,55 @@ func (s *Scraper) scrapeAcademyDetails(url, externalID, countryCode string) (*mo
 // SaveAcademy saves or updates an academy in the database
 func (s *Scraper) SaveAcademy(academy *models.Academy) error {
 	db := config.GetDB()
+	academy.ClubURL = urlnorm.ClubURL(academy.ClubURL)
+	academy.Bio = sanitize.HTML(academy.Bio)
+	academy.NormalizedName = normalizeAcademyName(academy.Name)
 
 	// Check if academy already exists
 	var existing models.Academy
 	result := db.Where("external_id = ?", academy.ExternalID).First(&existing)
+	if result.Error != nil && academy.NormalizedName != "" {
+		// Same club under another ID, with different accents or casing
+		result = db.Where("country_code = ? AND normalized_name = ?", academy.CountryCode, academy.NormalizedName).
+			Order("id ASC").First(&existing)
+		if result.Error == nil {
+			logger.Debug("Academy matched by normalized name",
+				zap.String("name", academy.Name),
+				zap.String("external_id", academy.ExternalID),
+				zap.String("existing_external_id", existing.ExternalID))
+			academy.ExternalID = existing.ExternalID
+		}
+	}
+
+	// After the lookup, which may switch to the stored club's external ID
+	s.applyContactPolicy(db, academy)
 
 	if result.Error == nil {
 		// Update existing academy
 		academy.ID = existing.ID
 		academy.CreatedAt = existing.CreatedAt
-		if err := db.Save(academy).Error; err != nil {
+		// keep published slugs stable across renames
+		academy.Slug = existing.Slug
+		// statistics aggregated from our own data beat the club page
+		if existing.StatsComputedAt != nil {
+			academy.TotalWins, academy.TotalLosses = existing.TotalWins, existing.TotalLosses
+			academy.AthleteCount = existing.AthleteCount
+			academy.GoldMedals, academy.SilverMedals, academy.BronzeMedals = existing.GoldMedals, existing.SilverMedals, existing.BronzeMedals
+			academy.StatsComputedAt = existing.StatsComputedAt
+		}
+		err := saveSlugged(db, &models.Academy{}, academy.Name, &academy.Slug, &academy.ID, func(tx *gorm.DB) error {
+			return tx.Save(academy).Error
+		})
+		if err != nil {
 			return fmt.Errorf("failed to update academy: %w", err)
 		}
 		logger.Debug("Academy updated", zap.String("name", academy.Name))
 	} else {
 		// Create new academy
-		if err := db.Create(academy).Error; err != nil {
+		err := saveSlugged(db, &models.Academy{}, academy.Name, &academy.Slug, &academy.ID, func(tx *gorm.DB) error {
+			return tx.Create(academy).Error
+		})
+		if err != nil {
 			return fmt.Errorf("failed to create academy: %w", err)
 		}
 		logger.Debug("Academy created", zap.String("name", academy.Name))
@@ -204,18 +258,3 @@ func (s *Scraper) SaveAcademy(academy *models.Academy) error {
 
 	return nil
 }
-
-// generateSlug creates a URL-friendly slug from a name
-func generateSlug(name string) string {
-	slug := strings.ToLower(name)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "/", "-")
-	// Remove special characters
-	var result strings.Builder
-	for _, char := range slug {
-		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' {
-			result.WriteRune(char)
-		}
-	}
-	return result.String()
-}
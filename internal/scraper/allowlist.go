@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrNotAllowed is returned for requests outside the scraping allowlist
+var ErrNotAllowed = errors.New("url is outside the scraping allowlist")
+
+// defaultAllowedPaths are the Smoothcomp sections the scraper reads. Like
+// robots.txt rules they match by prefix, segment by segment; "*" matches
+// any single segment. Locale prefixes (/en, /pt-br) are ignored.
+var defaultAllowedPaths = []string{
+	"/events",    // upcoming/past listings
+	"/event/*",   // event pages, participants, brackets, results, cards
+	"/profile/*", // athlete profiles and their events
+	"/club",      // club listing and club pages
+	"/ranking",
+	"/rankings",
+}
+
+var localePathPrefix = regexp.MustCompile(`^/[a-z]{2}(?:-[a-z]{2})?(/|$)`)
+
+// checkAllowedURL rejects hosts outside SCRAPER_ALLOWED_DOMAINS (smoothcomp.com
+// and its federation subdomains by default) and SMOOTHCOMP_BASE_URL, and
+// paths matching no allowlist pattern, including the extra
+// SCRAPER_ALLOWED_PATHS
+func checkAllowedURL(cfg *config.Config, target *url.URL) error {
+	if !allowedHost(cfg, strings.ToLower(target.Hostname())) {
+		return fmt.Errorf("%w: host %s", ErrNotAllowed, target.Host)
+	}
+
+	path := localePathPrefix.ReplaceAllString(strings.ToLower(target.EscapedPath()), "/")
+	for _, patterns := range [][]string{defaultAllowedPaths, cfg.Latest().Scraper.AllowedPaths} {
+		for _, pattern := range patterns {
+			if pathMatches(pattern, path) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: path %s", ErrNotAllowed, target.EscapedPath())
+}
+
+// CheckAllowedHost rejects URLs outside SCRAPER_ALLOWED_DOMAINS and
+// SMOOTHCOMP_BASE_URL, for connections that do not go through the scraper
+// transport (e.g. live scoreboard streams)
+func CheckAllowedHost(cfg *config.Config, target *url.URL) error {
+	if !allowedHost(cfg, strings.ToLower(target.Hostname())) {
+		return fmt.Errorf("%w: host %s", ErrNotAllowed, target.Host)
+	}
+	return nil
+}
+
+func allowedHost(cfg *config.Config, host string) bool {
+	if domainAllowed(cfg.Scraper.AllowedDomains, host) {
+		return true
+	}
+	base, err := url.Parse(cfg.Scraper.BaseURL)
+	return err == nil && host != "" && host == strings.ToLower(base.Hostname())
+}
+
+// domainAllowed reports whether host is one of domains. A "*." entry
+// matches any subdomain ("*.smoothcomp.com" takes adcc.smoothcomp.com, not
+// smoothcomp.com itself).
+func domainAllowed(domains []string, host string) bool {
+	for _, domain := range domains {
+		if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+			if len(host) > len(suffix) && strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// hostFilter is the colly URL filter of the allowed hosts. Colly only
+// compares AllowedDomains exactly, which would reject federation
+// subdomains such as ajp.smoothcomp.com.
+func hostFilter(cfg *config.Config) *regexp.Regexp {
+	hosts := make([]string, 0, len(cfg.Scraper.AllowedDomains)+1)
+	for _, domain := range cfg.Scraper.AllowedDomains {
+		if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+			hosts = append(hosts, `[a-z0-9-]+(?:\.[a-z0-9-]+)*`+regexp.QuoteMeta(suffix))
+		} else {
+			hosts = append(hosts, regexp.QuoteMeta(domain))
+		}
+	}
+	if base, err := url.Parse(cfg.Scraper.BaseURL); err == nil && base.Hostname() != "" {
+		hosts = append(hosts, regexp.QuoteMeta(strings.ToLower(base.Hostname())))
+	}
+	return regexp.MustCompile(`(?i)^https?://(?:` + strings.Join(hosts, "|") + `)(?::\d+)?(?:[/?#]|$)`)
+}
+
+// pathMatches reports whether path starts with the segments of pattern
+func pathMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(strings.ToLower(pattern), "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathSegments) < len(patternSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if segment == "*" {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// allowlistTransport refuses requests outside the allowlist before they
+// leave the process, so a badly parsed href cannot send the scraper into
+// unrelated sections or other sites
+type allowlistTransport struct {
+	base   http.RoundTripper
+	config *config.Config
+}
+
+func (t *allowlistTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkAllowedURL(t.config, req.URL); err != nil {
+		logger.Warn("Request rejected by allowlist",
+			zap.String("url", req.URL.String()),
+			zap.Error(err))
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
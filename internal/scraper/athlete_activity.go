@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"gorm.io/gorm"
+)
+
+// parseEventDate reads the day of a Smoothcomp event date such as
+// "2024-05-04", "2024-05-04 09:00:00" or "2024-05-04T09:00:00-03:00"
+func parseEventDate(value string) (time.Time, bool) {
+	if len(value) < len("2006-01-02") {
+		return time.Time{}, false
+	}
+	day, err := time.Parse("2006-01-02", value[:len("2006-01-02")])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+// eventDate returns the start day of a stored event, from the event listing
+// or else from its details page
+func eventDate(tx *gorm.DB, eventID string) (time.Time, bool) {
+	if eventID == "" {
+		return time.Time{}, false
+	}
+	var event models.Event
+	if tx.Select("start_date").Where("external_id = ? AND start_date IS NOT NULL", eventID).
+		Limit(1).Find(&event).RowsAffected > 0 && event.StartDate != nil {
+		return *event.StartDate, true
+	}
+
+	var starts []string
+	tx.Model(&models.EventDetail{}).Where("event_id = ?", eventID).Limit(1).Pluck("start_date", &starts)
+	if len(starts) > 0 {
+		return parseEventDate(starts[0])
+	}
+	return time.Time{}, false
+}
+
+// touchAthleteActivity moves the last active date of an athlete forward to
+// the date of an event they are registered in, when the date is known
+func touchAthleteActivity(tx *gorm.DB, athleteID uint, eventID string) error {
+	date, ok := eventDate(tx, eventID)
+	if !ok {
+		return nil
+	}
+	if err := tx.Model(&models.Athlete{}).
+		Where("id = ? AND (last_active_at IS NULL OR last_active_at < ?)", athleteID, date).
+		UpdateColumn("last_active_at", date).Error; err != nil {
+		return fmt.Errorf("error updating athlete activity: %w", err)
+	}
+	return nil
+}
+
+// touchEventActivity moves the last active date of every athlete registered
+// in an event forward to its date, once the date is known
+func touchEventActivity(tx *gorm.DB, eventID string) error {
+	date, ok := eventDate(tx, eventID)
+	if !ok {
+		return nil
+	}
+	if err := tx.Model(&models.Athlete{}).
+		Where("id IN (?)", tx.Model(&models.EventRegistration{}).Select("athlete_id").Where("event_id = ?", eventID)).
+		Where("last_active_at IS NULL OR last_active_at < ?", date).
+		UpdateColumn("last_active_at", date).Error; err != nil {
+		return fmt.Errorf("error updating athlete activity: %w", err)
+	}
+	return nil
+}
@@ -1,17 +1,19 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/names"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -109,12 +111,12 @@ func (f *FlexibleFloat) UnmarshalJSON(data []byte) error {
 
 // Category representa las categorías del evento
 type Category struct {
-	EventCategoryID    int64    `json:"event_category_id"`
-	CategoryName       string   `json:"category_name"`
-	Datatype           string   `json:"datatype"`
-	DatatypeWeightUnit string   `json:"datatype_weight_unit"`
-	ID                 int64    `json:"id"`
-	Name               string   `json:"name"`
+	EventCategoryID    int64          `json:"event_category_id"`
+	CategoryName       string         `json:"category_name"`
+	Datatype           string         `json:"datatype"`
+	DatatypeWeightUnit string         `json:"datatype_weight_unit"`
+	ID                 int64          `json:"id"`
+	Name               string         `json:"name"`
 	WeightMaximum      *FlexibleFloat `json:"weight_maximum"`
 	WeightMinimum      *FlexibleFloat `json:"weight_minimum"`
 }
@@ -141,70 +143,63 @@ type AthleteEventData struct {
 	ActualWeight    float64
 	Seed            int
 	Ranking         int
-	Gender          string
+	DivisionID      string        // ID de la división en Smoothcomp (estable aunque cambie el nombre)
+	EventCardURL    string        // credencial (PDF) de la inscripción
+	Gender          models.Gender // género del atleta (o de la división si no viene)
+	DivisionGender  models.Gender
+	IsKids          bool
+	Style           models.Style // gi/nogi, si la categoría lo indica
 }
 
 // ScrapeEventAthletes extrae todos los atletas de un evento usando la API de SmoothComp
-func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL string) error {
+func (s *Scraper) ScrapeEventAthletes(ctx context.Context, eventID string, eventName string, eventURL string) error {
+	if err := checkBlocked(models.BlockedEvent, eventID); err != nil {
+		return err
+	}
+
 	logger.Info("Iniciando scraping de atletas del evento via API",
 		zap.String("event_id", eventID),
 		zap.String("event_name", eventName),
 		zap.String("event_url", eventURL))
 
-	subdomain := "smoothcomp.com"
-	if eventURL != "" {
-		subdomain = ExtractSubdomainFromURL(eventURL)
-	} else {
-		subdomain = s.DetectEventSubdomain(eventID)
-	}
+	subdomain := s.eventHost(ctx, eventID, eventURL)
 
 	apiURL := BuildAPIURL(subdomain, eventID)
 
 	logger.Debug("API URL", zap.String("url", apiURL), zap.String("subdomain", subdomain))
 
 	// Crear cliente HTTP con timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// Crear request
-	req, err := http.NewRequest("POST", apiURL, nil)
-	if err != nil {
-		return fmt.Errorf("error creando request: %w", err)
-	}
-
-	// Headers importantes
-	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
-	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
-	req.Header.Set("X-Requested-With", "XMLHttpRequest")
-	req.Header.Set("Content-Type", "application/json")
-
-	// Hacer el request
-	logger.Debug("Realizando request a API")
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("error haciendo request: %w", err)
+	client := s.newHTTPClient(30 * time.Second)
+
+	// Leer todas las páginas de participantes (los eventos grandes vienen paginados)
+	apiResponse, complete, fetchErr := s.fetchParticipants(ctx, client, apiURL)
+	if errors.Is(fetchErr, errEventHostNotFound) {
+		// El evento ya no está en el host guardado
+		if host, ok := s.redetectEventHost(ctx, eventID, subdomain); ok {
+			subdomain, apiURL = host, BuildAPIURL(host, eventID)
+			apiResponse, complete, fetchErr = s.fetchParticipants(ctx, client, apiURL)
+		}
 	}
-	defer resp.Body.Close()
-
-	// Verificar status code
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API retornó status %d: %s", resp.StatusCode, string(bodyBytes))
+	if errors.Is(fetchErr, maintenance.ErrActive) {
+		// Durante el mantenimiento la página HTML tampoco responde
+		return fetchErr
 	}
-
-	// Leer body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error leyendo response: %w", err)
+	if apiResponse == nil {
+		// Si la API falla se lee la página HTML de participantes
+		logger.Warn("API de participantes falló, leyendo la página HTML",
+			zap.String("event_id", eventID),
+			zap.Error(fetchErr))
+		var htmlErr error
+		apiResponse, htmlErr = s.scrapeParticipantsHTML(ctx, apiURL)
+		if apiResponse == nil {
+			return fmt.Errorf("participants API failed (%v) and HTML fallback failed: %w", fetchErr, htmlErr)
+		}
+		complete, fetchErr = htmlErr == nil, htmlErr
 	}
-
-	logger.Debug("Response recibido", zap.Int("bytes", len(bodyBytes)))
-
-	// Parsear JSON
-	var apiResponse SmoothCompAPIResponse
-	if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil {
-		return fmt.Errorf("error parseando JSON: %w", err)
+	if fetchErr != nil {
+		logger.Warn("Lista de participantes incompleta, se guardan las páginas leídas",
+			zap.String("event_id", eventID),
+			zap.Error(fetchErr))
 	}
 
 	logger.Info("API response parseado",
@@ -217,7 +212,15 @@ func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL
 
 	for _, participant := range apiResponse.Participants {
 		// participant.Name contiene: "Men / Adults / Beginner / -60 kg"
-		division, ageCategory, rank, weightClass := parseCategory(participant.Name)
+		division, ageCategory, rank, weightClass, style := parseCategory(participant.Name)
+		divisionGender, isKids := models.ParseGender(division)
+		divisionID := ""
+		if participant.ID != 0 {
+			divisionID = strconv.FormatInt(participant.ID, 10)
+		}
+		if !isKids {
+			isKids = models.IsKidsCategory(ageCategory)
+		}
 
 		logger.Debug("Procesando categoría",
 			zap.String("category", participant.Name),
@@ -239,10 +242,19 @@ func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL
 				AffiliationName: reg.AffiliationName,
 				ImageURL:        reg.ProfileImage,
 				Division:        division,
+				DivisionID:      divisionID,
 				AgeCategory:     ageCategory,
 				Rank:            rank,
 				WeightClass:     weightClass,
-				Gender:          reg.Gender,
+				Style:           style,
+				DivisionGender:  divisionGender,
+				IsKids:          isKids,
+			}
+
+			// Normalizar género; si el registro no lo trae se usa el de la división
+			athlete.Gender, _ = models.ParseGender(reg.Gender)
+			if athlete.Gender == models.GenderUnknown && divisionGender != models.GenderMixed {
+				athlete.Gender = divisionGender
 			}
 
 			// Construir nombre completo
@@ -252,9 +264,13 @@ func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL
 			}
 			nameParts = append(nameParts, reg.LastName)
 			athlete.FullName = strings.Join(nameParts, " ")
+			athlete.FirstName, athlete.LastName = names.Parse(athlete.FullName, reg.FirstName, reg.LastName)
 
 			// Construir profile URL
 			athlete.ProfileURL = fmt.Sprintf("https://smoothcomp.com/en/profile/%d", reg.UserID)
+			if reg.ID != 0 {
+				athlete.EventCardURL = BuildEventCardURL(subdomain, eventID, reg.ID)
+			}
 
 			// Parsear año de nacimiento
 			if reg.Birth != "" {
@@ -278,6 +294,19 @@ func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL
 				}
 			}
 
+			s.observeFields("participant", map[string]bool{
+				"name":       reg.FirstName != "" || reg.LastName != "",
+				"country":    athlete.CountryCode != "",
+				"birth_year": athlete.BirthYear > 0,
+				"age":        reg.Age > 0,
+				"academy":    reg.ClubName != "",
+				"image":      reg.ProfileImage != "",
+				"gender":     athlete.Gender != models.GenderUnknown,
+				"category":   weightClass != "", // division / age / rank / weight split
+				"weight":     athlete.ActualWeight > 0,
+				"seed":       reg.SeedPosition != nil,
+			})
+
 			// Solo agregar si tenemos los datos mínimos requeridos
 			if athlete.SmoothCompID != "" && athlete.FullName != "" {
 				athletes = append(athletes, athlete)
@@ -292,31 +321,59 @@ func (s *Scraper) ScrapeEventAthletes(eventID string, eventName string, eventURL
 	// Guardar atletas en la base de datos
 	logger.Info("Guardando atletas en la base de datos", zap.Int("total", len(athletes)))
 
+	// Las escrituras se agrupan en transacciones por lote (ver WriteBuffer)
+	savingStarted := time.Now()
+	results := make([]<-chan error, len(athletes))
+	for i := range athletes {
+		athlete := athletes[i]
+		results[i] = s.writes.Submit(func(tx *gorm.DB) error {
+			return saveAthleteFromEvent(tx, athlete, eventID, eventName)
+		})
+	}
+
 	savedCount := 0
-	for _, athlete := range athletes {
-		if err := s.saveAthleteFromEvent(athlete, eventID, eventName); err != nil {
+	for i, result := range results {
+		if err := <-result; err != nil {
 			logger.Error("Error guardando atleta",
-				zap.String("name", athlete.FullName),
+				zap.String("name", athletes[i].FullName),
 				zap.Error(err))
 		} else {
 			savedCount++
 		}
 	}
 
+	// Si se guardó la lista completa, las inscripciones no vistas quedaron
+	// de un bracket anterior (divisiones fusionadas o renombradas)
+	if complete && len(athletes) > 0 && savedCount == len(athletes) {
+		err := <-s.writes.Submit(func(tx *gorm.DB) error {
+			return pruneStaleRegistrations(tx, eventID, savingStarted)
+		})
+		if err != nil {
+			logger.Error("Error eliminando inscripciones obsoletas", zap.String("event_id", eventID), zap.Error(err))
+		}
+	}
+
 	logger.Info("Scraping de evento completado",
 		zap.String("event_id", eventID),
 		zap.Int("saved", savedCount),
 		zap.Int("total", len(athletes)))
 
-	return nil
+	return fetchErr
 }
 
-// parseCategory extrae división, categoría de edad, rank y peso de la categoría
-// Ejemplo: "Men / Adults / Beginner / -60 kg"
-func parseCategory(category string) (division, ageCategory, rank, weightClass string) {
-	parts := strings.Split(category, "/")
+// parseCategory extrae división, categoría de edad, rank, peso y estilo (gi/nogi)
+// de la categoría. Ejemplo: "Men / Adults / Beginner / -60 kg"; los segmentos que
+// solo indican el estilo ("No-Gi / Men / ...") se quitan antes de separarla.
+func parseCategory(category string) (division, ageCategory, rank, weightClass string, style models.Style) {
+	style = models.ParseStyle(category)
+	var parts []string
+	for _, part := range strings.Split(category, "/") {
+		if !models.IsStyleLabel(part) {
+			parts = append(parts, part)
+		}
+	}
 	if len(parts) >= 4 {
-		division = strings.TrimSpace(parts[0])    // Men, Women, Boys, Girls
+		division = strings.TrimSpace(parts[0])    // Men, Women, Boys, Girls, Mixed (ver models.ParseGender)
 		ageCategory = strings.TrimSpace(parts[1]) // Adults, Masters, Age ranges
 		rank = strings.TrimSpace(parts[2])        // Beginner, Intermediate, Advanced
 		weightClass = strings.TrimSpace(parts[3]) // -60 kg, -65 kg, etc
@@ -324,122 +381,183 @@ func parseCategory(category string) (division, ageCategory, rank, weightClass st
 	return
 }
 
-// saveAthleteFromEvent guarda un atleta y su inscripción al evento en la base de datos usando GORM
-func (s *Scraper) saveAthleteFromEvent(data AthleteEventData, eventID string, eventName string) error {
-	db := config.GetDB()
+// saveAthleteFromEvent guarda un atleta y su inscripción al evento dentro de la transacción tx
+func saveAthleteFromEvent(tx *gorm.DB, data AthleteEventData, eventID string, eventName string) error {
+	// 1. Buscar o crear el atleta
+	var athlete models.Athlete
 
-	// Usar transacción
-	err := db.Transaction(func(tx *gorm.DB) error {
-		// 1. Buscar o crear el atleta
-		var athlete models.Athlete
-
-		result := tx.Where("external_id = ?", data.SmoothCompID).First(&athlete)
+	// Las cuentas fusionadas en Smoothcomp se guardan bajo la cuenta que queda
+	if mergedInto := ResolveAthleteID(tx, data.SmoothCompID); mergedInto != data.SmoothCompID {
+		data.SmoothCompID = mergedInto
+		data.ProfileURL = fmt.Sprintf("https://smoothcomp.com/en/profile/%s", mergedInto)
+	}
 
-		if result.Error == gorm.ErrRecordNotFound {
-			// Atleta no existe, crear nuevo
+	result := tx.Where("external_id = ?", data.SmoothCompID).First(&athlete)
+	suppressed := isSuppressed(tx, data.SmoothCompID)
+	slugName := athleteSlugName(data)
+	if suppressed {
+		slugName = ""
+	}
 
-			// Buscar academy_external_id si existe
-			var academy models.Academy
-			if data.AcademyName != "" {
-				tx.Where("name = ?", data.AcademyName).First(&academy)
-			}
+	if result.Error == gorm.ErrRecordNotFound {
+		// Atleta no existe, crear nuevo
 
-			athlete = models.Athlete{
-				ExternalID:        data.SmoothCompID,
-				FirstName:         data.FirstName,
-				LastName:          data.LastName,
-				FullName:          data.FullName,
-				CountryCode:       data.CountryCode,
-				Nationality:       data.Country,
-				BirthYear:         data.BirthYear,
-				Age:               data.Age,
-				ProfileURL:        data.ProfileURL,
-				ImageURL:          data.ImageURL,
-				AvatarURL:         data.ImageURL,
-				AffiliationName:   data.AffiliationName,
-				AcademyExternalID: academy.ExternalID,
-				Gender:            data.Gender,
-				ScrapedAt:         time.Now(),
-			}
+		// Buscar academy_external_id si existe
+		var academy models.Academy
+		if data.AcademyName != "" {
+			academy, _ = findAcademyByName(tx, data.AcademyName, data.CountryCode)
+		}
 
-			if err := tx.Create(&athlete).Error; err != nil {
-				return fmt.Errorf("error creando atleta: %w", err)
-			}
+		athlete = models.Athlete{
+			ExternalID:        data.SmoothCompID,
+			FirstName:         data.FirstName,
+			LastName:          data.LastName,
+			FullName:          data.FullName,
+			CountryCode:       data.CountryCode,
+			Nationality:       data.Country,
+			BirthYear:         data.BirthYear,
+			Age:               data.Age,
+			ProfileURL:        urlnorm.ProfileURL(data.ProfileURL),
+			ImageURL:          data.ImageURL,
+			AvatarURL:         data.ImageURL,
+			AffiliationName:   data.AffiliationName,
+			AcademyExternalID: academy.ExternalID,
+			Gender:            data.Gender,
+			ScrapedAt:         time.Now(),
+		}
+		if suppressed {
+			scrubPersonalData(&athlete)
+		}
+		err := saveSlugged(tx, &models.Athlete{}, slugName, &athlete.Slug, &athlete.ID, func(tx *gorm.DB) error {
+			return tx.Create(&athlete).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error creando atleta: %w", err)
+		}
 
-			logger.Debug("Atleta creado", zap.String("name", athlete.FullName))
+		logger.Debug("Atleta creado", zap.String("name", athlete.FullName))
 
-		} else if result.Error != nil {
-			return fmt.Errorf("error buscando atleta: %w", result.Error)
-		} else {
-			// Atleta existe, actualizar datos
+	} else if result.Error != nil {
+		return fmt.Errorf("error buscando atleta: %w", result.Error)
+	} else {
+		// Atleta existe, actualizar datos
 
-			// Buscar academy_external_id si existe
-			var academy models.Academy
-			if data.AcademyName != "" {
-				tx.Where("name = ?", data.AcademyName).First(&academy)
-				athlete.AcademyExternalID = academy.ExternalID
-			}
+		// Buscar academy_external_id si existe
+		if data.AcademyName != "" {
+			academy, _ := findAcademyByName(tx, data.AcademyName, data.CountryCode)
+			athlete.AcademyExternalID = academy.ExternalID
+		}
 
-			athlete.FirstName = data.FirstName
-			athlete.LastName = data.LastName
-			athlete.FullName = data.FullName
-			athlete.CountryCode = data.CountryCode
-			athlete.Nationality = data.Country
-			athlete.BirthYear = data.BirthYear
-			athlete.Age = data.Age
-			athlete.ProfileURL = data.ProfileURL
-			athlete.ImageURL = data.ImageURL
-			athlete.AvatarURL = data.ImageURL
-			athlete.AffiliationName = data.AffiliationName
+		athlete.FirstName = data.FirstName
+		athlete.LastName = data.LastName
+		athlete.FullName = data.FullName
+		athlete.CountryCode = data.CountryCode
+		athlete.Nationality = data.Country
+		athlete.BirthYear = data.BirthYear
+		athlete.Age = data.Age
+		athlete.ProfileURL = urlnorm.ProfileURL(data.ProfileURL)
+		athlete.ImageURL = data.ImageURL
+		athlete.AvatarURL = data.ImageURL
+		athlete.AffiliationName = data.AffiliationName
+		if data.Gender != models.GenderUnknown {
 			athlete.Gender = data.Gender
-			athlete.ScrapedAt = time.Now()
+		}
+		athlete.ScrapedAt = time.Now()
+		if suppressed {
+			scrubPersonalData(&athlete)
+		}
+		err := saveSlugged(tx, &models.Athlete{}, slugName, &athlete.Slug, &athlete.ID, func(tx *gorm.DB) error {
+			return tx.Save(&athlete).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error actualizando atleta: %w", err)
+		}
 
-			if err := tx.Save(&athlete).Error; err != nil {
-				return fmt.Errorf("error actualizando atleta: %w", err)
-			}
+		logger.Debug("Atleta actualizado", zap.String("name", athlete.FullName))
+	}
 
-			logger.Debug("Atleta actualizado", zap.String("name", athlete.FullName))
-		}
+	// 2. Insertar o actualizar la inscripción al evento
+	registration := models.EventRegistration{
+		AthleteID:        uint(athlete.ID),
+		EventID:          eventID,
+		EventName:        eventName,
+		Division:         data.Division,
+		Gender:           data.DivisionGender,
+		IsKids:           data.IsKids,
+		AgeCategory:      data.AgeCategory,
+		Rank:             data.Rank,
+		WeightClass:      data.WeightClass,
+		Style:            data.Style,
+		ActualWeight:     data.ActualWeight,
+		Seed:             data.Seed,
+		Ranking:          data.Ranking,
+		EventCardURL:     data.EventCardURL,
+		RegistrationDate: time.Now(),
+	}
+
+	if data.DivisionID != "" {
+		registration.DivisionID = &data.DivisionID
+	}
+	registration.NormalizeWeightClass()
 
-		// 2. Insertar o actualizar la inscripción al evento
-		registration := models.EventRegistration{
-			AthleteID:        uint(athlete.ID),
-			EventID:          eventID,
-			EventName:        eventName,
-			Division:         data.Division,
-			AgeCategory:      data.AgeCategory,
-			Rank:             data.Rank,
-			WeightClass:      data.WeightClass,
-			ActualWeight:     data.ActualWeight,
-			Seed:             data.Seed,
-			Ranking:          data.Ranking,
-			RegistrationDate: time.Now(),
+	// Buscar si ya existe la inscripción
+	existingReg, err := findRegistration(tx, uint(athlete.ID), eventID, data)
+	if err != nil {
+		return fmt.Errorf("error buscando inscripción: %w", err)
+	}
+
+	if existingReg == nil {
+		// No existe, crear nueva
+		if err := tx.Create(&registration).Error; err != nil {
+			return fmt.Errorf("error creando inscripción: %w", err)
+		}
+		logger.Debug("Inscripción creada", zap.String("athlete", athlete.FullName))
+	} else {
+		// Ya existe, actualizar
+		registration.ID = existingReg.ID
+		if err := tx.Save(&registration).Error; err != nil {
+			return fmt.Errorf("error actualizando inscripción: %w", err)
 		}
+		logger.Debug("Inscripción actualizada", zap.String("athlete", athlete.FullName))
+	}
 
-		// Buscar si ya existe la inscripción
-		var existingReg models.EventRegistration
-		result = tx.Where(
-			"athlete_id = ? AND event_id = ? AND division = ? AND age_category = ? AND rank = ? AND weight_class = ?",
-			athlete.ID, eventID, data.Division, data.AgeCategory, data.Rank, data.WeightClass,
-		).First(&existingReg)
-
-		if result.Error == gorm.ErrRecordNotFound {
-			// No existe, crear nueva
-			if err := tx.Create(&registration).Error; err != nil {
-				return fmt.Errorf("error creando inscripción: %w", err)
-			}
-			logger.Debug("Inscripción creada", zap.String("athlete", athlete.FullName))
-		} else {
-			// Ya existe, actualizar
-			registration.ID = existingReg.ID
-			if err := tx.Save(&registration).Error; err != nil {
-				return fmt.Errorf("error actualizando inscripción: %w", err)
-			}
-			logger.Debug("Inscripción actualizada", zap.String("athlete", athlete.FullName))
+	return touchAthleteActivity(tx, uint(athlete.ID), eventID)
+}
+
+// findRegistration busca la inscripción por su clave natural (atleta + evento +
+// división de Smoothcomp). Las filas anteriores a guardar la división se
+// reconocen por los textos de la categoría y se adoptan al actualizarlas.
+func findRegistration(tx *gorm.DB, athleteID uint, eventID string, data AthleteEventData) (*models.EventRegistration, error) {
+	base := tx.Where("athlete_id = ? AND event_id = ?", athleteID, eventID).Session(&gorm.Session{})
+
+	var reg models.EventRegistration
+	if data.DivisionID != "" {
+		err := base.Where("division_id = ?", data.DivisionID).First(&reg).Error
+		if err == nil {
+			return &reg, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
 		}
+	}
 
-		return nil
-	})
+	err := base.Where(
+		"division_id IS NULL AND division = ? AND age_category = ? AND rank = ? AND weight_class = ?",
+		data.Division, data.AgeCategory, data.Rank, data.WeightClass,
+	).First(&reg).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
 
-	return err
+// athleteSlugName devuelve el nombre usado para generar el slug del atleta
+func athleteSlugName(data AthleteEventData) string {
+	if data.FullName != "" {
+		return data.FullName
+	}
+	return strings.TrimSpace(data.FirstName + " " + data.LastName)
 }
@@ -0,0 +1,209 @@
+package scraper
+
+import (
+	"fmt"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ResolveAthleteID returns the external ID that externalID was merged into on
+// Smoothcomp, or externalID itself when it is not an alias
+func ResolveAthleteID(tx *gorm.DB, externalID string) string {
+	if externalID == "" {
+		return ""
+	}
+	var targets []string
+	tx.Model(&models.AthleteAlias{}).Where("alias_external_id = ?", externalID).Limit(1).Pluck("external_id", &targets)
+	if len(targets) > 0 && targets[0] != "" {
+		return targets[0]
+	}
+	return externalID
+}
+
+// recordAthleteMerge records that the profile of oldID now redirects to
+// newID and moves everything stored under oldID to newID: the athlete row is
+// renamed, or folded into the stored newID athlete along with its
+// registrations, matches, results, stat snapshots and activity dates. Tags,
+// federation IDs and a personal data suppression follow the athlete.
+func recordAthleteMerge(oldID, newID string) error {
+	db := config.GetDB()
+
+	var suppression models.SuppressedAthlete
+	suppressed := db.Where("external_id = ?", oldID).Limit(1).Find(&suppression).RowsAffected > 0
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// newID is an account again if it was merged away before
+		if err := tx.Where("alias_external_id = ?", newID).Delete(&models.AthleteAlias{}).Error; err != nil {
+			return fmt.Errorf("error updating aliases: %w", err)
+		}
+		// Keep chains one hop long
+		if err := tx.Model(&models.AthleteAlias{}).Where("external_id = ?", oldID).
+			UpdateColumn("external_id", newID).Error; err != nil {
+			return fmt.Errorf("error updating aliases: %w", err)
+		}
+		alias := models.AthleteAlias{AliasExternalID: oldID}
+		if err := tx.Where(alias).Assign(models.AthleteAlias{ExternalID: newID}).FirstOrCreate(&alias).Error; err != nil {
+			return fmt.Errorf("error recording alias: %w", err)
+		}
+
+		if err := mergeAthleteRows(tx, oldID, newID); err != nil {
+			return err
+		}
+		return mergeAthleteLabels(tx, oldID, newID)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Athlete account merge recorded",
+		zap.String("alias_id", oldID),
+		zap.String("athlete_id", newID))
+
+	if !suppressed {
+		return nil
+	}
+	if err := db.Delete(&suppression).Error; err != nil {
+		return fmt.Errorf("error moving suppression: %w", err)
+	}
+	var athlete models.Athlete
+	if db.Where("external_id = ?", newID).Limit(1).Find(&athlete).RowsAffected == 0 {
+		suppression.ID = 0
+		suppression.ExternalID = newID
+		return db.Create(&suppression).Error
+	}
+	_, err = RemovePersonalData(athlete, suppression.Reason, suppression.Actor)
+	return err
+}
+
+// mergeAthleteRows renames the oldID athlete to newID, or folds it into the
+// stored newID athlete when both exist
+func mergeAthleteRows(tx *gorm.DB, oldID, newID string) error {
+	var old models.Athlete
+	if result := tx.Where("external_id = ?", oldID).Limit(1).Find(&old); result.Error != nil {
+		return fmt.Errorf("error finding athlete: %w", result.Error)
+	} else if result.RowsAffected == 0 {
+		return tx.Model(&models.EventResult{}).Where("athlete_external_id = ?", oldID).
+			UpdateColumn("athlete_external_id", newID).Error
+	}
+
+	var kept models.Athlete
+	result := tx.Where("external_id = ?", newID).Limit(1).Find(&kept)
+	if result.Error != nil {
+		return fmt.Errorf("error finding athlete: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		columns := map[string]interface{}{"external_id": newID}
+		if old.ProfileURL != "" {
+			columns["profile_url"] = fmt.Sprintf("https://smoothcomp.com/en/profile/%s", newID)
+		}
+		if err := tx.Model(&old).UpdateColumns(columns).Error; err != nil {
+			return fmt.Errorf("error renaming athlete: %w", err)
+		}
+		return tx.Model(&models.EventResult{}).Where("athlete_external_id = ?", oldID).
+			UpdateColumn("athlete_external_id", newID).Error
+	}
+
+	var registrations []models.EventRegistration
+	if err := tx.Where("athlete_id = ?", old.ID).Find(&registrations).Error; err != nil {
+		return fmt.Errorf("error loading registrations: %w", err)
+	}
+	for _, registration := range registrations {
+		// A registration listed under both accounts keeps the newID row
+		var duplicate models.EventRegistration
+		query := tx.Where("athlete_id = ? AND event_id = ?", kept.ID, registration.EventID)
+		if registration.DivisionID != nil {
+			query = query.Where("division_id = ?", *registration.DivisionID)
+		} else {
+			query = query.Where("division_id IS NULL AND division = ? AND age_category = ? AND rank = ? AND weight_class = ?",
+				registration.Division, registration.AgeCategory, registration.Rank, registration.WeightClass)
+		}
+		if query.Limit(1).Find(&duplicate).RowsAffected == 0 {
+			if err := tx.Model(&registration).UpdateColumn("athlete_id", kept.ID).Error; err != nil {
+				return fmt.Errorf("error moving registration: %w", err)
+			}
+			continue
+		}
+		for _, side := range []string{"registration_a_id", "registration_b_id"} {
+			if err := tx.Model(&models.Match{}).Where(side+" = ?", registration.ID).
+				UpdateColumn(side, duplicate.ID).Error; err != nil {
+				return fmt.Errorf("error moving matches: %w", err)
+			}
+		}
+		if err := tx.Delete(&registration).Error; err != nil {
+			return fmt.Errorf("error removing registration: %w", err)
+		}
+	}
+
+	for _, side := range []string{"athlete_a_id", "athlete_b_id", "winner_id"} {
+		if err := tx.Model(&models.Match{}).Where(side+" = ?", old.ID).
+			UpdateColumn(side, kept.ID).Error; err != nil {
+			return fmt.Errorf("error moving matches: %w", err)
+		}
+	}
+	for _, side := range []string{"athlete_a_id", "athlete_b_id"} {
+		if err := tx.Model(&models.LiveScore{}).Where(side+" = ?", old.ID).
+			UpdateColumn(side, kept.ID).Error; err != nil {
+			return fmt.Errorf("error moving live scores: %w", err)
+		}
+	}
+	if err := tx.Model(&models.AthleteSnapshot{}).Where("athlete_id = ?", old.ID).
+		UpdateColumn("athlete_id", kept.ID).Error; err != nil {
+		return fmt.Errorf("error moving snapshots: %w", err)
+	}
+	if err := tx.Model(&models.EventResult{}).Where("athlete_id = ? OR athlete_external_id = ?", old.ID, oldID).
+		UpdateColumns(map[string]interface{}{"athlete_id": kept.ID, "athlete_external_id": newID}).Error; err != nil {
+		return fmt.Errorf("error moving results: %w", err)
+	}
+
+	// The kept athlete was seen since either account was and is as active
+	// as either one
+	columns := map[string]interface{}{}
+	if kept.AcademyExternalID == "" && old.AcademyExternalID != "" {
+		columns["academy_external_id"] = old.AcademyExternalID
+	}
+	if !old.FirstSeenAt.IsZero() && old.FirstSeenAt.Before(kept.FirstSeenAt) {
+		columns["first_seen_at"] = old.FirstSeenAt
+	}
+	if old.LastActiveAt != nil && (kept.LastActiveAt == nil || old.LastActiveAt.After(*kept.LastActiveAt)) {
+		columns["last_active_at"] = *old.LastActiveAt
+	}
+	if len(columns) > 0 {
+		if err := tx.Model(&kept).UpdateColumns(columns).Error; err != nil {
+			return fmt.Errorf("error updating athlete: %w", err)
+		}
+	}
+	if err := tx.Delete(&old).Error; err != nil {
+		return fmt.Errorf("error removing merged athlete: %w", err)
+	}
+	return nil
+}
+
+// mergeAthleteLabels moves the tags and federation IDs of oldID to newID,
+// dropping those newID already has
+func mergeAthleteLabels(tx *gorm.DB, oldID, newID string) error {
+	if err := tx.Where("entity_type = ? AND external_id = ? AND tag IN (?)", models.TaggedAthlete, oldID,
+		tx.Model(&models.EntityTag{}).Select("tag").Where("entity_type = ? AND external_id = ?", models.TaggedAthlete, newID)).
+		Delete(&models.EntityTag{}).Error; err != nil {
+		return fmt.Errorf("error moving tags: %w", err)
+	}
+	if err := tx.Model(&models.EntityTag{}).Where("entity_type = ? AND external_id = ?", models.TaggedAthlete, oldID).
+		UpdateColumn("external_id", newID).Error; err != nil {
+		return fmt.Errorf("error moving tags: %w", err)
+	}
+
+	if err := tx.Where("athlete_external_id = ? AND federation IN (?)", oldID,
+		tx.Model(&models.FederationID{}).Select("federation").Where("athlete_external_id = ?", newID)).
+		Delete(&models.FederationID{}).Error; err != nil {
+		return fmt.Errorf("error moving federation IDs: %w", err)
+	}
+	if err := tx.Model(&models.FederationID{}).Where("athlete_external_id = ?", oldID).
+		UpdateColumn("athlete_external_id", newID).Error; err != nil {
+		return fmt.Errorf("error moving federation IDs: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -38,8 +41,8 @@ type labelValue struct {
 }
 
 type profileEventsResponse struct {
-	Data        []profileEvent `json:"data"`
-	NextPageURL *string        `json:"next_page_url"`
+	Data        []json.RawMessage `json:"data"`
+	NextPageURL *string           `json:"next_page_url"`
 }
 
 type profileEvent struct {
@@ -69,10 +72,19 @@ type profileStats struct {
 }
 
 // ScrapeAthleteProfile obtiene el perfil del atleta y actualiza sus estadisticas en la BD.
-func (s *Scraper) ScrapeAthleteProfile(externalID string, profileURL string) error {
+func (s *Scraper) ScrapeAthleteProfile(ctx context.Context, externalID string, profileURL string) error {
+	_, err := s.scrapeAthleteProfile(ctx, externalID, profileURL, false)
+	return err
+}
+
+// scrapeAthleteProfile scrapes a profile page and the profile events API and
+// updates the athlete. Event stats normally only fill counts the page lacks;
+// with recount they replace the stored counts, zeros included, and a failed
+// events request is an error. It returns the events listed by the API.
+func (s *Scraper) scrapeAthleteProfile(ctx context.Context, externalID string, profileURL string, recount bool) ([]json.RawMessage, error) {
 	if profileURL == "" {
 		if externalID == "" {
-			return fmt.Errorf("athlete_id or profile_url is required")
+			return nil, fmt.Errorf("athlete_id or profile_url is required")
 		}
 		profileURL = fmt.Sprintf("https://smoothcomp.com/en/profile/%s", externalID)
 	}
@@ -81,50 +93,115 @@ func (s *Scraper) ScrapeAthleteProfile(externalID string, profileURL string) err
 		externalID = ExtractIDFromURL(profileURL)
 	}
 	if externalID == "" {
-		return fmt.Errorf("failed to resolve athlete id from profile url")
+		return nil, fmt.Errorf("failed to resolve athlete id from profile url")
+	}
+	if err := checkBlocked(models.BlockedAthlete, externalID); err != nil {
+		return nil, err
 	}
 
 	logger.Info("Scraping athlete profile",
 		zap.String("athlete_id", externalID),
 		zap.String("profile_url", profileURL))
 
-	client := &http.Client{Timeout: 20 * time.Second}
-	req, err := http.NewRequest("GET", profileURL, nil)
+	client := s.newHTTPClient(20 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", profileURL, nil)
 	if err != nil {
-		return fmt.Errorf("error creating profile request: %w", err)
+		return nil, fmt.Errorf("error creating profile request: %w", err)
 	}
 	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("error fetching profile: %w", err)
+		return nil, fmt.Errorf("error fetching profile: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("profile returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("profile returned status %d", resp.StatusCode)
+	}
+
+	// Smoothcomp redirects the profile of a merged account to the kept one
+	if mergedInto := profileIDFromPath(resp.Request.URL.Path); mergedInto != "" && mergedInto != externalID {
+		if err := recordAthleteMerge(externalID, mergedInto); err != nil {
+			return nil, fmt.Errorf("error recording account merge: %w", err)
+		}
+		externalID = mergedInto
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return fmt.Errorf("error parsing profile html: %w", err)
+		return nil, fmt.Errorf("error parsing profile html: %w", err)
 	}
 
 	data := parseAthleteProfile(doc)
-	if stats, err := s.fetchProfileEventStats(externalID); err != nil {
-		logger.Warn("Failed to fetch profile event stats", zap.Error(err))
-	} else {
+	events, statsErr := s.fetchProfileEvents(ctx, externalID)
+	var stats profileStats
+	if statsErr == nil {
+		stats, statsErr = profileStatsFromEvents(events)
+	}
+	s.observeFields("profile", map[string]bool{
+		"belt":         data.BeltRank != nil,
+		"wins":         data.TotalWins != nil,
+		"losses":       data.TotalLosses != nil,
+		"win_methods":  data.WinsBySubmission != nil || data.WinsByPoints != nil || data.WinsByDecision != nil || data.WinsByDQ != nil,
+		"loss_methods": data.LossesBySubmission != nil || data.LossesByPoints != nil || data.LossesByDecision != nil || data.LossesByDQ != nil,
+		"event_stats":  statsErr == nil,
+	})
+	switch {
+	case statsErr != nil:
+		logger.Warn("Failed to fetch profile event stats", zap.Error(statsErr))
+	case recount:
+		data = replaceProfileStats(data, stats)
+	default:
 		data = mergeProfileStatsFromEvents(data, stats)
 	}
 
-	return s.updateAthleteProfile(externalID, data)
+	if err := s.updateAthleteProfile(externalID, data); err != nil {
+		return nil, err
+	}
+	if recount && statsErr != nil {
+		return nil, fmt.Errorf("error fetching profile events: %w", statsErr)
+	}
+	return events, nil
+}
+
+// profileIDFromPath returns the athlete ID of a profile page path such as
+// "/en/profile/123", or "" for other pages
+func profileIDFromPath(path string) string {
+	_, id, found := strings.Cut(path, "/profile/")
+	if !found {
+		return ""
+	}
+	id, _, _ = strings.Cut(id, "/")
+	if _, err := strconv.Atoi(id); err != nil {
+		return ""
+	}
+	return id
 }
 
 // ScrapeAthleteProfiles procesa perfiles en lote para completar campos faltantes.
-func (s *Scraper) ScrapeAthleteProfiles(limit int, offset int, onlyMissing bool) (int, error) {
+func (s *Scraper) ScrapeAthleteProfiles(ctx context.Context, limit int, offset int, onlyMissing bool) (int, error) {
+	var athletes []models.Athlete
+	if err := profileSelection(limit, offset, onlyMissing).Find(&athletes).Error; err != nil {
+		return 0, fmt.Errorf("error loading athletes: %w", err)
+	}
+
+	if len(athletes) == 0 {
+		logger.Info("No athletes found for profile scraping")
+		return 0, nil
+	}
+
+	scraped, _ := s.scrapeProfiles(ctx, athletes, timeBox{})
+	return scraped, nil
+}
+
+// profileSelection arma la consulta de atletas a enriquecer
+func profileSelection(limit int, offset int, onlyMissing bool) *gorm.DB {
 	db := config.GetDB()
-	query := db.Model(&models.Athlete{}).Order("id ASC")
+	query := db.Model(&models.Athlete{}).
+		Where("external_id NOT IN (?)", blockedIDs(db, models.BlockedAthlete)).
+		Order("id ASC")
 
 	if onlyMissing {
 		query = query.Where("belt_rank = '' OR belt_rank IS NULL OR (total_wins = 0 AND total_losses = 0)")
@@ -137,44 +214,75 @@ func (s *Scraper) ScrapeAthleteProfiles(limit int, offset int, onlyMissing bool)
 		query = query.Limit(limit)
 	}
 
-	var athletes []models.Athlete
-	if err := query.Find(&athletes).Error; err != nil {
-		return 0, fmt.Errorf("error loading athletes: %w", err)
-	}
+	return query
+}
 
-	if len(athletes) == 0 {
-		logger.Info("No athletes found for profile scraping")
-		return 0, nil
+// scrapeProfiles enriquece los perfiles indicados con SCRAPER_CONCURRENCY workers
+// que comparten un token bucket (ver profileLimiter), o con la concurrencia y el
+// ritmo del perfil de comportamiento del job. Los atletas se despachan en orden y
+// se deja de despachar si se excede box o se cancela ctx; processed indica cuantos
+// se recorrieron (siempre un prefijo de athletes, para poder reanudar).
+func (s *Scraper) scrapeProfiles(ctx context.Context, athletes []models.Athlete, box timeBox) (scraped int, processed int) {
+	workers := s.config.Scraper.Concurrency
+	limiter := profileLimiter(s.config)
+	if s.behavior != nil {
+		workers = s.behavior.Concurrency
+		limiter = nil // the profile paces every request of the job
+	}
+	workers = max(1, min(workers, len(athletes)))
+
+	var (
+		wg   sync.WaitGroup
+		done atomic.Int64
+	)
+	queue := make(chan models.Athlete)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for athlete := range queue {
+				err := s.ScrapeAthleteProfile(ctx, athlete.ExternalID, athlete.ProfileURL)
+				s.progress.add(1)
+				if err != nil {
+					logger.Error("Failed to scrape athlete profile",
+						zap.String("athlete_id", athlete.ExternalID),
+						zap.Error(err))
+					continue
+				}
+				done.Add(1)
+			}
+		}()
 	}
 
-	delay := time.Duration(s.config.Scraper.RequestDelayMs) * time.Millisecond
-	scraped := 0
+	for _, athlete := range athletes {
+		if box.exceeded() || ctx.Err() != nil {
+			break
+		}
+		if limiter != nil && limiter.wait(ctx) != nil {
+			break
+		}
+		processed++
 
-	for i, athlete := range athletes {
 		if athlete.ExternalID == "" && athlete.ProfileURL == "" {
 			logger.Warn("Skipping athlete without profile reference",
 				zap.Int("athlete_id", athlete.ID))
+			s.progress.add(1)
 			continue
 		}
 
-		if err := s.ScrapeAthleteProfile(athlete.ExternalID, athlete.ProfileURL); err != nil {
-			logger.Error("Failed to scrape athlete profile",
-				zap.String("athlete_id", athlete.ExternalID),
-				zap.Error(err))
-		} else {
-			scraped++
-		}
-
-		if delay > 0 && i < len(athletes)-1 {
-			time.Sleep(delay)
-		}
+		queue <- athlete
 	}
+	close(queue)
+	wg.Wait()
+	scraped = int(done.Load())
 
 	logger.Info("Athlete profile batch completed",
 		zap.Int("selected", len(athletes)),
-		zap.Int("scraped", scraped))
+		zap.Int("processed", processed),
+		zap.Int("scraped", scraped),
+		zap.Int("workers", workers))
 
-	return scraped, nil
+	return scraped, processed
 }
 
 func parseAthleteProfile(doc *goquery.Document) AthleteProfileData {
@@ -248,48 +356,77 @@ func mergeProfileStatsFromEvents(data AthleteProfileData, stats profileStats) At
 	return data
 }
 
-func (s *Scraper) fetchProfileEventStats(externalID string) (profileStats, error) {
+// replaceProfileStats sets every win and loss count of data from stats
+func replaceProfileStats(data AthleteProfileData, stats profileStats) AthleteProfileData {
+	data.TotalWins = &stats.TotalWins
+	data.TotalLosses = &stats.TotalLosses
+	data.WinsBySubmission = &stats.WinsBySubmission
+	data.WinsByPoints = &stats.WinsByPoints
+	data.WinsByDecision = &stats.WinsByDecision
+	data.WinsByDQ = &stats.WinsByDQ
+	data.LossesBySubmission = &stats.LossesBySubmission
+	data.LossesByPoints = &stats.LossesByPoints
+	data.LossesByDecision = &stats.LossesByDecision
+	data.LossesByDQ = &stats.LossesByDQ
+	return data
+}
+
+// profileStatsFromEvents counts the wins and losses of the matches listed
+// by the profile events API
+func profileStatsFromEvents(events []json.RawMessage) (profileStats, error) {
 	stats := profileStats{}
+	for _, raw := range events {
+		var event profileEvent
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return stats, fmt.Errorf("error decoding events response: %w", err)
+		}
+		for _, reg := range event.Registrations {
+			for _, match := range reg.Matches {
+				applyEventMatchStats(&stats, match)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// fetchProfileEvents returns every event of the profile events API,
+// following its pages
+func (s *Scraper) fetchProfileEvents(ctx context.Context, externalID string) ([]json.RawMessage, error) {
 	if externalID == "" {
-		return stats, fmt.Errorf("athlete_id is required")
+		return nil, fmt.Errorf("athlete_id is required")
 	}
+	var events []json.RawMessage
 
-	client := &http.Client{Timeout: 20 * time.Second}
+	client := s.newHTTPClient(20 * time.Second)
 	url := fmt.Sprintf("https://smoothcomp.com/en/profile/%s/events", externalID)
 
 	for {
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
-			return stats, fmt.Errorf("error creating events request: %w", err)
+			return nil, fmt.Errorf("error creating events request: %w", err)
 		}
 		req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
 		req.Header.Set("Accept", "application/json")
 
 		resp, err := client.Do(req)
 		if err != nil {
-			return stats, fmt.Errorf("error fetching events: %w", err)
+			return nil, fmt.Errorf("error fetching events: %w", err)
 		}
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
-			return stats, fmt.Errorf("events endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(bodyBytes)))
+			return nil, fmt.Errorf("events endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(bodyBytes)))
 		}
 
 		var payload profileEventsResponse
 		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 			resp.Body.Close()
-			return stats, fmt.Errorf("error decoding events response: %w", err)
+			return nil, fmt.Errorf("error decoding events response: %w", err)
 		}
 		resp.Body.Close()
 
-		for _, event := range payload.Data {
-			for _, reg := range event.Registrations {
-				for _, match := range reg.Matches {
-					applyEventMatchStats(&stats, match)
-				}
-			}
-		}
+		events = append(events, payload.Data...)
 
 		if payload.NextPageURL == nil || *payload.NextPageURL == "" {
 			break
@@ -302,7 +439,7 @@ func (s *Scraper) fetchProfileEventStats(externalID string) (profileStats, error
 		}
 	}
 
-	return stats, nil
+	return events, nil
 }
 
 func applyEventMatchStats(stats *profileStats, match profileEventMatch) {
@@ -682,66 +819,113 @@ func applyStat(data *AthleteProfileData, label string, value int) {
 	}
 }
 
+// updateAthleteProfile writes the scraped fields with a single UPDATE keyed by
+// external_id. updated_at only moves when a value actually differs from the
+// stored one; scraped_at and profile_scraped_at always do, so unchanged
+// profiles count as fresh.
 func (s *Scraper) updateAthleteProfile(externalID string, data AthleteProfileData) error {
-	db := config.GetDB()
-	var athlete models.Athlete
+	fields := profileFields(data)
+	if len(fields) == 0 {
+		logger.Info("No profile fields found", zap.String("athlete_id", externalID))
+		return nil
+	}
 
-	if err := db.Where("external_id = ?", externalID).First(&athlete).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	now := time.Now()
+	updates := make(map[string]interface{}, len(fields)+2)
+	changed := make([]string, 0, len(fields))
+	args := make([]interface{}, 0, len(fields)+1)
+	for _, field := range fields {
+		updates[field.column] = field.value
+		changed = append(changed, field.column+" IS DISTINCT FROM ?")
+		args = append(args, field.value)
+	}
+	args = append(args, now)
+
+	updates["scraped_at"] = now
+	updates["profile_scraped_at"] = now
+	updates["updated_at"] = gorm.Expr(
+		"CASE WHEN "+strings.Join(changed, " OR ")+" THEN ? ELSE updated_at END", args...)
+
+	// Queued on the write buffer so parallel profile workers share batches
+	// instead of contending for the database lock
+	err := s.writes.Write(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Athlete{}).
+			Where("external_id = ?", externalID).
+			UpdateColumns(updates)
+		if result.Error != nil {
+			return fmt.Errorf("error updating athlete profile: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
 			return fmt.Errorf("athlete not found: %s", externalID)
 		}
-		return fmt.Errorf("error loading athlete: %w", err)
+		return snapshotAthleteStats(tx, externalID, now)
+	})
+	if err != nil {
+		return err
 	}
 
-	updates := map[string]interface{}{}
-	if data.BeltRank != nil && *data.BeltRank != "" {
-		updates["belt_rank"] = *data.BeltRank
-	}
-	if data.TotalWins != nil {
-		updates["total_wins"] = *data.TotalWins
-	}
-	if data.WinsBySubmission != nil {
-		updates["wins_by_submission"] = *data.WinsBySubmission
-	}
-	if data.WinsByPoints != nil {
-		updates["wins_by_points"] = *data.WinsByPoints
-	}
-	if data.WinsByDecision != nil {
-		updates["wins_by_decision"] = *data.WinsByDecision
-	}
-	if data.WinsByDQ != nil {
-		updates["wins_by_dq"] = *data.WinsByDQ
-	}
-	if data.TotalLosses != nil {
-		updates["total_losses"] = *data.TotalLosses
-	}
-	if data.LossesBySubmission != nil {
-		updates["losses_by_submission"] = *data.LossesBySubmission
-	}
-	if data.LossesByPoints != nil {
-		updates["losses_by_points"] = *data.LossesByPoints
-	}
-	if data.LossesByDecision != nil {
-		updates["losses_by_decision"] = *data.LossesByDecision
-	}
-	if data.LossesByDQ != nil {
-		updates["losses_by_dq"] = *data.LossesByDQ
+	logger.Info("Athlete profile updated",
+		zap.String("athlete_id", externalID),
+		zap.Int("fields", len(fields)))
+
+	return nil
+}
+
+// snapshotAthleteStats stores the stats of an athlete as a new snapshot when
+// they differ from its latest one
+func snapshotAthleteStats(tx *gorm.DB, externalID string, at time.Time) error {
+	var athlete models.Athlete
+	if err := tx.Where("external_id = ?", externalID).First(&athlete).Error; err != nil {
+		return fmt.Errorf("error loading athlete stats: %w", err)
 	}
+	snapshot := models.NewAthleteSnapshot(athlete, at)
 
-	if len(updates) == 0 {
-		logger.Info("No profile fields found", zap.String("athlete_id", externalID))
+	var latest models.AthleteSnapshot
+	found := tx.Where("athlete_id = ?", athlete.ID).Order("captured_at DESC, id DESC").Limit(1).Find(&latest)
+	if found.Error != nil {
+		return fmt.Errorf("error loading athlete snapshot: %w", found.Error)
+	}
+	if found.RowsAffected > 0 && latest.SameStats(snapshot) {
 		return nil
 	}
+	if err := tx.Create(&snapshot).Error; err != nil {
+		return fmt.Errorf("error saving athlete snapshot: %w", err)
+	}
+	return nil
+}
 
-	updates["scraped_at"] = time.Now()
+type profileField struct {
+	column string
+	value  interface{}
+}
 
-	if err := db.Model(&athlete).Updates(updates).Error; err != nil {
-		return fmt.Errorf("error updating athlete profile: %w", err)
+// profileFields lists the columns present in a scraped profile, in a stable order
+func profileFields(data AthleteProfileData) []profileField {
+	fields := make([]profileField, 0, 11)
+	if data.BeltRank != nil && *data.BeltRank != "" {
+		fields = append(fields, profileField{"belt_rank", *data.BeltRank})
 	}
 
-	logger.Info("Athlete profile updated",
-		zap.String("athlete_id", externalID),
-		zap.Int("fields", len(updates)-1))
+	counts := []struct {
+		column string
+		value  *int
+	}{
+		{"total_wins", data.TotalWins},
+		{"wins_by_submission", data.WinsBySubmission},
+		{"wins_by_points", data.WinsByPoints},
+		{"wins_by_decision", data.WinsByDecision},
+		{"wins_by_dq", data.WinsByDQ},
+		{"total_losses", data.TotalLosses},
+		{"losses_by_submission", data.LossesBySubmission},
+		{"losses_by_points", data.LossesByPoints},
+		{"losses_by_decision", data.LossesByDecision},
+		{"losses_by_dq", data.LossesByDQ},
+	}
+	for _, count := range counts {
+		if count.value != nil {
+			fields = append(fields, profileField{count.column, *count.value})
+		}
+	}
 
-	return nil
+	return fields
 }
@@ -0,0 +1,245 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ResyncResult reports what a full resync of one athlete found and changed
+type ResyncResult struct {
+	JobID                int             `json:"job_id"`
+	Events               int             `json:"events"`                // listed by the profile events API
+	Registrations        int             `json:"registrations"`         // with event and division, see profileHistory
+	RegistrationsCreated int             `json:"registrations_created"` // missing before the resync
+	RegistrationsUpdated int             `json:"registrations_updated"` // legacy rows given their division ID
+	Athlete              *models.Athlete `json:"athlete,omitempty"`     // as stored after the resync
+}
+
+// profileEventInfo is the part of a profile events API entry naming the
+// event and the division of each registration. Entries that do not decode
+// are skipped; their matches still count in profileStatsFromEvents.
+type profileEventInfo struct {
+	ID            json.RawMessage `json:"id"`
+	Title         string          `json:"title"`
+	Name          string          `json:"name"`
+	Registrations []struct {
+		EventGroupID json.RawMessage `json:"event_group_id"`
+		GroupName    string          `json:"group_name"` // "Men / Adults / Beginner / -60 kg"
+	} `json:"registrations"`
+}
+
+// rawID returns a JSON number or string ID as text, "" when missing
+func rawID(raw json.RawMessage) string {
+	id := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if id == "null" || id == "0" {
+		return ""
+	}
+	return id
+}
+
+// ResyncAthlete re-fetches the profile of one stored athlete, recounts its
+// wins and losses from the profile events API and stores every registration
+// that API lists, for support cases where one record is wrong. The work is
+// recorded as an athlete_resync job that CancelJob can stop.
+func (s *Scraper) ResyncAthlete(ctx context.Context, athlete models.Athlete) (*ResyncResult, error) {
+	if err := checkBlocked(models.BlockedAthlete, athlete.ExternalID); err != nil {
+		return nil, err
+	}
+
+	job := s.createJob("athlete_resync")
+	ctx, release := trackJob(WithFreshFetch(ctx), job)
+	defer release()
+	runner, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	result, err := runner.resyncAthlete(ctx, job, athlete)
+	switch {
+	case ctx.Err() != nil:
+		s.cancelJob(ctx, job)
+		return nil, context.Cause(ctx)
+	case err != nil:
+		s.failJob(job, err)
+		return nil, err
+	}
+
+	job.ItemsScraped = result.RegistrationsCreated + result.RegistrationsUpdated
+	s.completeJob(job)
+	result.JobID = job.ID
+	return result, nil
+}
+
+func (s *Scraper) resyncAthlete(ctx context.Context, job *models.ScrapeJob, athlete models.Athlete) (*ResyncResult, error) {
+	logger.Info("Resyncing athlete",
+		zap.Int("job_id", job.ID),
+		zap.String("athlete_id", athlete.ExternalID))
+
+	s.progress.phase("profile", 1)
+	events, err := s.scrapeAthleteProfile(ctx, athlete.ExternalID, athlete.ProfileURL, true)
+	if err != nil {
+		return nil, err
+	}
+	s.progress.add(1)
+
+	// A merged account continues under the ID it was merged into
+	if mergedInto := ResolveAthleteID(config.GetDB(), athlete.ExternalID); mergedInto != athlete.ExternalID {
+		var merged models.Athlete
+		if err := config.GetDB().Where("external_id = ?", mergedInto).First(&merged).Error; err != nil {
+			return nil, fmt.Errorf("error loading merged athlete: %w", err)
+		}
+		athlete = merged
+	}
+
+	history := profileHistory(events)
+	result := &ResyncResult{Events: len(events), Registrations: len(history)}
+
+	s.progress.phase("registrations", len(history))
+	err = config.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, entry := range history {
+			created, updated, err := saveHistoryRegistration(tx, athlete, entry)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.RegistrationsCreated++
+			}
+			if updated {
+				result.RegistrationsUpdated++
+			}
+			s.progress.add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stored models.Athlete
+	if err := config.GetDB().Preload("Academy").First(&stored, athlete.ID).Error; err != nil {
+		return nil, fmt.Errorf("error reloading athlete: %w", err)
+	}
+	result.Athlete = &stored
+
+	logger.Info("Athlete resynced",
+		zap.Int("job_id", job.ID),
+		zap.String("athlete_id", athlete.ExternalID),
+		zap.Int("events", result.Events),
+		zap.Int("registrations", result.Registrations),
+		zap.Int("created", result.RegistrationsCreated),
+		zap.Int("updated", result.RegistrationsUpdated))
+
+	return result, nil
+}
+
+// historyRegistration is one registration listed by the profile events API
+type historyRegistration struct {
+	EventID   string
+	EventName string
+	Data      AthleteEventData // division fields only
+}
+
+// profileHistory extracts the registrations with an event ID and a division
+// from profile events API entries
+func profileHistory(events []json.RawMessage) []historyRegistration {
+	var history []historyRegistration
+	for _, raw := range events {
+		var event profileEventInfo
+		if json.Unmarshal(raw, &event) != nil {
+			continue
+		}
+		eventID := rawID(event.ID)
+		if eventID == "" {
+			continue
+		}
+		eventName := event.Title
+		if eventName == "" {
+			eventName = event.Name
+		}
+
+		for _, reg := range event.Registrations {
+			division, ageCategory, rank, weightClass, style := parseCategory(reg.GroupName)
+			divisionID := rawID(reg.EventGroupID)
+			if divisionID == "" && division == "" {
+				continue
+			}
+			divisionGender, isKids := models.ParseGender(division)
+			if !isKids {
+				isKids = models.IsKidsCategory(ageCategory)
+			}
+
+			history = append(history, historyRegistration{
+				EventID:   eventID,
+				EventName: eventName,
+				Data: AthleteEventData{
+					DivisionID:     divisionID,
+					Division:       division,
+					AgeCategory:    ageCategory,
+					Rank:           rank,
+					WeightClass:    weightClass,
+					Style:          style,
+					DivisionGender: divisionGender,
+					IsKids:         isKids,
+				},
+			})
+		}
+	}
+	return history
+}
+
+// saveHistoryRegistration stores a registration from the profile history.
+// Registrations already scraped from the event keep their seed, ranking and
+// weigh-in; legacy rows only gain their division ID.
+func saveHistoryRegistration(tx *gorm.DB, athlete models.Athlete, entry historyRegistration) (created bool, updated bool, err error) {
+	existing, err := findRegistration(tx, uint(athlete.ID), entry.EventID, entry.Data)
+	if err != nil {
+		return false, false, fmt.Errorf("error finding registration: %w", err)
+	}
+
+	if existing != nil {
+		if existing.DivisionID != nil || entry.Data.DivisionID == "" {
+			return false, false, nil
+		}
+		if err := tx.Model(existing).UpdateColumn("division_id", entry.Data.DivisionID).Error; err != nil {
+			return false, false, fmt.Errorf("error updating registration: %w", err)
+		}
+		return false, true, nil
+	}
+
+	registration := models.EventRegistration{
+		AthleteID:        uint(athlete.ID),
+		EventID:          entry.EventID,
+		EventName:        entry.EventName,
+		Division:         entry.Data.Division,
+		Gender:           entry.Data.DivisionGender,
+		IsKids:           entry.Data.IsKids,
+		AgeCategory:      entry.Data.AgeCategory,
+		Rank:             entry.Data.Rank,
+		WeightClass:      entry.Data.WeightClass,
+		Style:            entry.Data.Style,
+		RegistrationDate: time.Now(),
+	}
+	if entry.Data.DivisionID != "" {
+		registration.DivisionID = &entry.Data.DivisionID
+	}
+	registration.NormalizeWeightClass()
+	if registration.EventName == "" {
+		var names []string
+		tx.Model(&models.Event{}).Where("external_id = ?", entry.EventID).Limit(1).Pluck("name", &names)
+		registration.EventName = entry.EventID
+		if len(names) > 0 {
+			registration.EventName = names[0]
+		}
+	}
+	if err := tx.Create(&registration).Error; err != nil {
+		return false, false, fmt.Errorf("error creating registration: %w", err)
+	}
+	return true, false, touchAthleteActivity(tx, uint(athlete.ID), entry.EventID)
+}
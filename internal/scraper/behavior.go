@@ -0,0 +1,164 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Behavior bundles how a job talks to Smoothcomp: pacing, parallelism,
+// headers, proxy and retries. Jobs run without one use the REQUEST_DELAY_MS
+// and transport settings of the environment.
+type Behavior struct {
+	Name             string
+	Delay            time.Duration // minimum gap between request starts
+	Concurrency      int           // requests in flight at once
+	RotateUserAgents bool          // cycle SCRAPER_USER_AGENTS
+	UseProxy         bool          // route through SCRAPER_PROXY_URL
+	MaxRetries       int           // retries on 429, 5xx and network errors
+	RetryBackoff     time.Duration // grows linearly per attempt
+}
+
+// behaviorProfiles are the presets selectable with ?profile=
+var behaviorProfiles = map[string]Behavior{
+	"aggressive": {
+		Name:         "aggressive",
+		Delay:        250 * time.Millisecond,
+		Concurrency:  8,
+		MaxRetries:   1,
+		RetryBackoff: 2 * time.Second,
+	},
+	"polite": {
+		Name:         "polite",
+		Delay:        3 * time.Second,
+		Concurrency:  1,
+		MaxRetries:   3,
+		RetryBackoff: 10 * time.Second,
+	},
+	"stealth": {
+		Name:             "stealth",
+		Delay:            5 * time.Second,
+		Concurrency:      1,
+		RotateUserAgents: true,
+		UseProxy:         true,
+		MaxRetries:       2,
+		RetryBackoff:     30 * time.Second,
+	},
+}
+
+// LookupBehavior returns the preset called name
+func LookupBehavior(name string) (*Behavior, error) {
+	behavior, ok := behaviorProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q (expected one of %v)", name, BehaviorNames())
+	}
+	return &behavior, nil
+}
+
+// BehaviorNames lists the available presets
+func BehaviorNames() []string {
+	names := make([]string, 0, len(behaviorProfiles))
+	for name := range behaviorProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// behaviorTransport applies a Behavior to the requests of one job
+type behaviorTransport struct {
+	base       http.RoundTripper
+	behavior   Behavior
+	userAgents []string
+
+	slots   chan struct{}
+	paceMu  sync.Mutex
+	nextAt  time.Time
+	counter atomic.Uint64
+}
+
+func newBehaviorTransport(base http.RoundTripper, behavior Behavior, userAgents []string) *behaviorTransport {
+	concurrency := max(behavior.Concurrency, 1)
+	return &behaviorTransport{
+		base:       base,
+		behavior:   behavior,
+		userAgents: userAgents,
+		slots:      make(chan struct{}, concurrency),
+	}
+}
+
+func (t *behaviorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.slots }()
+
+	if t.behavior.RotateUserAgents && len(t.userAgents) > 0 {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgents[int(t.counter.Add(1)-1)%len(t.userAgents)])
+	}
+
+	for attempt := 0; ; attempt++ {
+		t.pace()
+
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt >= t.behavior.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		wait := t.behavior.RetryBackoff * time.Duration(attempt+1)
+		logger.Debug("Retrying request",
+			zap.String("profile", t.behavior.Name),
+			zap.String("url", req.URL.String()),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait))
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// pace waits until Delay has passed since the previous request started
+func (t *behaviorTransport) pace() {
+	if t.behavior.Delay <= 0 {
+		return
+	}
+
+	t.paceMu.Lock()
+	now := time.Now()
+	start := t.nextAt
+	if start.Before(now) {
+		start = now
+	}
+	t.nextAt = start.Add(t.behavior.Delay)
+	t.paceMu.Unlock()
+
+	time.Sleep(time.Until(start))
+}
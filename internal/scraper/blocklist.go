@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrBlocked is returned when a scrape targets a blocklisted entity
+var ErrBlocked = errors.New("entity is blocklisted")
+
+// IsBlocked reports whether the entity is on the blocklist. Lookup errors
+// are logged and treated as not blocked so scraping keeps going.
+func IsBlocked(entityType string, externalID string) bool {
+	if externalID == "" {
+		return false
+	}
+
+	var count int64
+	err := config.GetDB().Model(&models.BlockedEntity{}).
+		Where("entity_type = ? AND external_id = ?", entityType, externalID).
+		Count(&count).Error
+	if err != nil {
+		logger.Warn("Failed to check blocklist",
+			zap.String("entity_type", entityType),
+			zap.String("external_id", externalID),
+			zap.Error(err))
+		return false
+	}
+	return count > 0
+}
+
+// checkBlocked returns ErrBlocked for a blocklisted entity
+func checkBlocked(entityType string, externalID string) error {
+	if IsBlocked(entityType, externalID) {
+		return fmt.Errorf("%w: %s %s", ErrBlocked, entityType, externalID)
+	}
+	return nil
+}
+
+// blockedIDs selects the external IDs of blocklisted entities of a type,
+// for use as a NOT IN subquery
+func blockedIDs(db *gorm.DB, entityType string) *gorm.DB {
+	return db.Model(&models.BlockedEntity{}).Select("external_id").Where("entity_type = ?", entityType)
+}
+
+// blockMissing blocklists an entity whose page no longer exists, so later
+// runs skip it. An entity already on the blocklist is left as is.
+func blockMissing(entityType, externalID, reason string) {
+	err := config.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(&models.BlockedEntity{
+		EntityType: entityType,
+		ExternalID: externalID,
+		Reason:     reason,
+		Actor:      "scraper",
+	}).Error
+	if err != nil {
+		logger.Warn("Failed to blocklist missing entity",
+			zap.String("entity_type", entityType),
+			zap.String("external_id", externalID),
+			zap.Error(err))
+		return
+	}
+	logger.Info("Missing entity blocklisted",
+		zap.String("entity_type", entityType),
+		zap.String("external_id", externalID),
+		zap.String("reason", reason))
+}
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ArchiveBracket stores the raw bracket payload of an event division so it
+// can be re-parsed after the event pages change or disappear. Unchanged
+// payloads only refresh the fetch time of the latest version; older versions
+// beyond the configured count are dropped.
+func (s *Scraper) ArchiveBracket(eventID, divisionID, sourceURL string, payload []byte) error {
+	db := config.GetDB()
+	sum := sha256.Sum256(payload)
+	hash := hex.EncodeToString(sum[:])
+
+	var latest models.BracketArchive
+	found := db.Select("id", "hash").
+		Where("event_id = ? AND division_id = ?", eventID, divisionID).
+		Order("fetched_at DESC").Limit(1).Find(&latest).RowsAffected > 0
+	if found && latest.Hash == hash {
+		return db.Model(&latest).Update("fetched_at", time.Now()).Error
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(payload); err != nil {
+		return fmt.Errorf("error compressing bracket: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("error compressing bracket: %w", err)
+	}
+
+	archive := models.BracketArchive{
+		EventID:    eventID,
+		DivisionID: divisionID,
+		SourceURL:  sourceURL,
+		Hash:       hash,
+		RawSize:    len(payload),
+		Payload:    compressed.Bytes(),
+		FetchedAt:  time.Now(),
+	}
+	if err := db.Create(&archive).Error; err != nil {
+		return fmt.Errorf("error archiving bracket: %w", err)
+	}
+
+	if keep := s.config.Scraper.BracketArchiveVersions; keep > 0 {
+		stale := db.Model(&models.BracketArchive{}).Select("id").
+			Where("event_id = ? AND division_id = ?", eventID, divisionID).
+			Order("fetched_at DESC").Offset(keep).Limit(-1)
+		if err := db.Where("id IN (?)", stale).Delete(&models.BracketArchive{}).Error; err != nil {
+			return fmt.Errorf("error pruning bracket versions: %w", err)
+		}
+	}
+
+	return PruneBracketArchive(s.config.Scraper.BracketArchiveRetention)
+}
+
+// BracketPayload returns the decompressed payload of an archived bracket
+func BracketPayload(archive models.BracketArchive) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(archive.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("error reading archived bracket: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// PruneBracketArchive deletes archived brackets older than the retention
+// period. A zero retention keeps them forever.
+func PruneBracketArchive(retention time.Duration) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	result := config.GetDB().Where("fetched_at < ?", time.Now().Add(-retention)).Delete(&models.BracketArchive{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("Expired bracket archives removed", zap.Int64("archives", result.RowsAffected))
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// BracketResponse is the match list Smoothcomp returns for one division
+// (bracket group) of an event
+type BracketResponse struct {
+	Name    string         `json:"name"` // "Men / Adults / Beginner / -60 kg"
+	Matches []BracketMatch `json:"matches"`
+
+	// Where and when the bracket was fetched, copied to its matches
+	SourceURL string     `json:"-"`
+	FetchedAt *time.Time `json:"-"`
+}
+
+// BracketMatch is one bout of a bracket
+type BracketMatch struct {
+	ID          int64              `json:"id"`
+	Round       string             `json:"round_name"`
+	RoundNumber int                `json:"round"`
+	Mat         string             `json:"mat_name"`
+	StartTime   string             `json:"start_time"`
+	MatchTime   *FlexibleFloat     `json:"match_time"` // seconds on the clock at the end
+	Winner      int                `json:"winner"`     // 1 or 2, 0 while undecided
+	WinType     string             `json:"win_type"`   // "Submission", "Points", "Referee decision", "DQ"...
+	Submission  string             `json:"submission"`
+	Competitor1 *BracketCompetitor `json:"competitor1"`
+	Competitor2 *BracketCompetitor `json:"competitor2"`
+}
+
+// BracketCompetitor is one side of a bracket match
+type BracketCompetitor struct {
+	UserID     int64          `json:"user_id"`
+	Name       string         `json:"name"`
+	Points     *FlexibleFloat `json:"points"`
+	Advantages *FlexibleFloat `json:"advantages"`
+	Penalties  *FlexibleFloat `json:"penalties"`
+}
+
+// BuildBracketURL returns the match list endpoint of an event division
+func BuildBracketURL(subdomain, eventID, divisionID string) string {
+	return fmt.Sprintf("https://%s/en/event/%s/bracket/%s/matches", subdomain, eventID, divisionID)
+}
+
+// ScrapeEventBrackets fetches the brackets of every division with scraped
+// registrations in the event, archives their raw payload and stores their
+// matches. Returns how many matches were saved.
+func (s *Scraper) ScrapeEventBrackets(ctx context.Context, eventID string, eventURL string) (int, error) {
+	if err := checkBlocked(models.BlockedEvent, eventID); err != nil {
+		return 0, err
+	}
+
+	db := config.GetDB()
+	var divisionIDs []string
+	if err := db.Model(&models.EventRegistration{}).Distinct().
+		Where("event_id = ? AND division_id IS NOT NULL AND division_id <> ''", eventID).
+		Pluck("division_id", &divisionIDs).Error; err != nil {
+		return 0, err
+	}
+	if len(divisionIDs) == 0 {
+		return 0, fmt.Errorf("event %s has no divisions; scrape its participants first", eventID)
+	}
+
+	var event models.Event
+	db.Where("external_id = ?", eventID).Limit(1).Find(&event)
+	if eventURL == "" {
+		eventURL = event.EventURL
+	}
+	subdomain := s.eventHost(ctx, eventID, eventURL)
+
+	saved := 0
+	redetected := false
+	for _, divisionID := range divisionIDs {
+		if ctx.Err() != nil {
+			return saved, context.Cause(ctx)
+		}
+		n, err := s.scrapeBracket(ctx, subdomain, eventID, event.Name, divisionID)
+		if errors.Is(err, errEventHostNotFound) && !redetected {
+			// The event may have moved host; detected again once per scrape
+			redetected = true
+			if host, ok := s.redetectEventHost(ctx, eventID, subdomain); ok {
+				subdomain = host
+				n, err = s.scrapeBracket(ctx, subdomain, eventID, event.Name, divisionID)
+			}
+		}
+		if err != nil {
+			logger.Warn("Failed to scrape bracket",
+				zap.String("event_id", eventID),
+				zap.String("division_id", divisionID),
+				zap.Error(err))
+			continue
+		}
+		saved += n
+	}
+
+	logger.Info("Event brackets scraped",
+		zap.String("event_id", eventID),
+		zap.Int("divisions", len(divisionIDs)),
+		zap.Int("matches", saved))
+
+	return saved, nil
+}
+
+// scrapeBracket fetches, archives and stores the matches of one division
+func (s *Scraper) scrapeBracket(ctx context.Context, subdomain, eventID, eventName, divisionID string) (int, error) {
+	bracketURL := BuildBracketURL(subdomain, eventID, divisionID)
+
+	client := s.newHTTPClient(20 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", bracketURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching %s: %w", bracketURL, err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error reading bracket: %w", err)
+	}
+	fetchedAt := time.Now()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, fmt.Errorf("bracket returned status %d: %w", resp.StatusCode, errEventHostNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("bracket returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(payload)))
+	}
+
+	if err := s.ArchiveBracket(eventID, divisionID, bracketURL, payload); err != nil {
+		logger.Warn("Failed to archive bracket", zap.String("division_id", divisionID), zap.Error(err))
+	}
+
+	var bracket BracketResponse
+	if err := json.Unmarshal(payload, &bracket); err != nil {
+		return 0, fmt.Errorf("error parsing bracket JSON: %w", err)
+	}
+
+	bracket.SourceURL = bracketURL
+	bracket.FetchedAt = &fetchedAt
+	return s.SaveBracketMatches(eventID, eventName, divisionID, bracket)
+}
+
+// SaveBracketMatches stores the matches of a parsed bracket, keyed by event
+// and Smoothcomp match ID so re-scrapes update scores and winners in place.
+// Sides are linked to stored athletes and their registrations in the division,
+// whose streaks and milestones are then refreshed.
+func (s *Scraper) SaveBracketMatches(eventID, eventName, divisionID string, bracket BracketResponse) (int, error) {
+	saved := 0
+	athletes := make(map[uint]bool)
+	matchIDs := make(map[int]bool)
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, raw := range bracket.Matches {
+			if raw.ID == 0 || raw.Competitor1 == nil || raw.Competitor2 == nil {
+				continue // byes and placeholder slots
+			}
+
+			match := models.Match{
+				ExternalID:   strconv.FormatInt(raw.ID, 10),
+				EventID:      eventID,
+				EventName:    eventName,
+				DivisionID:   divisionID,
+				Category:     bracket.Name,
+				Round:        bracketRound(raw),
+				Mat:          strings.TrimSpace(raw.Mat),
+				AthleteAName: strings.TrimSpace(raw.Competitor1.Name),
+				AthleteBName: strings.TrimSpace(raw.Competitor2.Name),
+				PointsA:      flexibleInt(raw.Competitor1.Points),
+				PointsB:      flexibleInt(raw.Competitor2.Points),
+				AdvantagesA:  flexibleInt(raw.Competitor1.Advantages),
+				AdvantagesB:  flexibleInt(raw.Competitor2.Advantages),
+				PenaltiesA:   flexibleInt(raw.Competitor1.Penalties),
+				PenaltiesB:   flexibleInt(raw.Competitor2.Penalties),
+
+				DurationSeconds: flexibleInt(raw.MatchTime),
+				SourceURL:       bracket.SourceURL,
+				FetchedAt:       bracket.FetchedAt,
+			}
+			if started, err := time.Parse("2006-01-02 15:04:05", raw.StartTime); err == nil {
+				match.StartedAt = &started
+			}
+
+			match.AthleteAID, match.RegistrationAID = bracketSide(tx, eventID, divisionID, raw.Competitor1.UserID)
+			match.AthleteBID, match.RegistrationBID = bracketSide(tx, eventID, divisionID, raw.Competitor2.UserID)
+			if isSuppressed(tx, strconv.FormatInt(raw.Competitor1.UserID, 10)) {
+				match.AthleteAName = ""
+			}
+			if isSuppressed(tx, strconv.FormatInt(raw.Competitor2.UserID, 10)) {
+				match.AthleteBName = ""
+			}
+
+			switch raw.Winner {
+			case 1:
+				match.WinnerID, match.WinnerName = match.AthleteAID, match.AthleteAName
+			case 2:
+				match.WinnerID, match.WinnerName = match.AthleteBID, match.AthleteBName
+			}
+			if raw.Winner != 0 {
+				match.Method = matchMethod(raw.WinType)
+				if match.Method == "submission" {
+					match.Technique = strings.TrimSpace(raw.Submission)
+				}
+			}
+
+			s.observeFields("match", map[string]bool{
+				"round":      match.Round != "",
+				"mat":        match.Mat != "",
+				"athletes":   match.AthleteAID != 0 && match.AthleteBID != 0,
+				"winner":     raw.Winner != 0,
+				"method":     match.Method != "",
+				"duration":   match.DurationSeconds > 0,
+				"start_time": match.StartedAt != nil,
+			})
+
+			var existing models.Match
+			found := tx.Select("id", "created_at").
+				Where("event_id = ? AND external_id = ?", eventID, match.ExternalID).
+				Limit(1).Find(&existing).RowsAffected > 0
+			if found {
+				match.ID = existing.ID
+				match.CreatedAt = existing.CreatedAt
+			}
+			if err := tx.Save(&match).Error; err != nil {
+				return fmt.Errorf("error saving match %s: %w", match.ExternalID, err)
+			}
+			saved++
+			matchIDs[match.ID] = true
+			for _, athleteID := range []uint{match.AthleteAID, match.AthleteBID} {
+				if athleteID != 0 {
+					athletes[athleteID] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.detectMilestones(athletes, matchIDs)
+	return saved, nil
+}
+
+// bracketSide resolves a Smoothcomp user to the stored athlete ID and its
+// registration in the division, zero/nil when not scraped
+func bracketSide(tx *gorm.DB, eventID, divisionID string, userID int64) (uint, *uint) {
+	if userID == 0 {
+		return 0, nil
+	}
+
+	var athlete models.Athlete
+	if tx.Select("id").Where("external_id = ?", ResolveAthleteID(tx, strconv.FormatInt(userID, 10))).
+		Limit(1).Find(&athlete).RowsAffected == 0 {
+		return 0, nil
+	}
+	athleteID := uint(athlete.ID)
+
+	var registration models.EventRegistration
+	if tx.Select("id").Where("athlete_id = ? AND event_id = ? AND division_id = ?", athleteID, eventID, divisionID).
+		Limit(1).Find(&registration).RowsAffected == 0 {
+		return athleteID, nil
+	}
+	return athleteID, &registration.ID
+}
+
+// bracketRound returns the round label, falling back to its number
+func bracketRound(raw BracketMatch) string {
+	if round := strings.TrimSpace(raw.Round); round != "" {
+		return round
+	}
+	if raw.RoundNumber > 0 {
+		return fmt.Sprintf("Round %d", raw.RoundNumber)
+	}
+	return ""
+}
+
+// matchMethod maps a Smoothcomp win type to submission, points, decision or dq
+func matchMethod(winType string) string {
+	value := strings.ToLower(winType)
+	switch {
+	case strings.Contains(value, "sub"):
+		return "submission"
+	case strings.Contains(value, "point"), strings.Contains(value, "advantage"):
+		return "points"
+	case strings.Contains(value, "dq"), strings.Contains(value, "disqualif"):
+		return "dq"
+	case value != "":
+		return "decision"
+	}
+	return ""
+}
+
+func flexibleInt(value *FlexibleFloat) int {
+	if value == nil || value.Value == nil {
+		return 0
+	}
+	return int(*value.Value)
+}
@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+)
+
+// MaxBulkEvents caps the events of one bulk ingestion request
+const MaxBulkEvents = 500
+
+// BulkEvent is one event of a bulk ingestion request
+type BulkEvent struct {
+	Input    string `json:"input"`
+	EventID  string `json:"event_id"`
+	EventURL string `json:"event_url,omitempty"`
+	JobID    int    `json:"job_id,omitempty"`
+}
+
+// ParseBulkEvents resolves event IDs and event URLs to events, dropping
+// duplicates. It returns the inputs that are neither.
+func ParseBulkEvents(inputs []string) ([]BulkEvent, []string) {
+	var events []BulkEvent
+	var invalid []string
+	seen := map[string]bool{}
+
+	for _, input := range inputs {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+
+		event := BulkEvent{Input: input, EventID: input}
+		if strings.Contains(input, "/") {
+			event.EventURL = urlnorm.EventURL(input)
+			event.EventID = ExtractIDFromURL(event.EventURL)
+		}
+		if _, err := strconv.ParseUint(event.EventID, 10, 64); err != nil {
+			invalid = append(invalid, input)
+			continue
+		}
+
+		if seen[event.EventID] {
+			continue
+		}
+		seen[event.EventID] = true
+		events = append(events, event)
+	}
+
+	return events, invalid
+}
+
+// IngestEventsBulk records a parent job with one pending child job per
+// event and scrapes details, participants and results of each event
+// sequentially until done or ctx is cancelled. A resumed run skips the
+// events the partial run already processed.
+func (s *Scraper) IngestEventsBulk(ctx context.Context, events []BulkEvent, opts RunOptions) error {
+	position := 0
+	if opts.Resume != nil {
+		position = opts.Resume.Position
+		if position > len(events) {
+			position = len(events)
+		}
+		events = events[position:]
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no events to scrape")
+	}
+	if len(events) > MaxBulkEvents {
+		return fmt.Errorf("%d events exceed the maximum of %d per request", len(events), MaxBulkEvents)
+	}
+
+	db := config.GetDB()
+	parent := s.createJob("events_bulk")
+	if opts.MaxDuration > 0 {
+		parent.MaxDuration = int(opts.MaxDuration.Seconds())
+		db.Model(parent).Update("max_duration", parent.MaxDuration)
+	}
+
+	for i := range events {
+		child := &models.ScrapeJob{
+			ParentJobID: &parent.ID,
+			JobType:     "event_ingest",
+			Status:      "pending",
+			StartedAt:   time.Now(),
+		}
+		db.Create(child)
+		events[i].JobID = child.ID
+	}
+
+	logger.Info("Bulk event ingestion planned",
+		zap.Int("job_id", parent.ID),
+		zap.Int("events", len(events)))
+
+	ctx, release := trackJob(ctx, parent)
+	defer release()
+	runner, finish := s.forJob(parent, opts)
+	defer finish()
+	return runner.runBulkEvents(ctx, parent, events, position, newTimeBox(opts.MaxDuration))
+}
+
+func (s *Scraper) runBulkEvents(ctx context.Context, parent *models.ScrapeJob, events []BulkEvent, position int, box timeBox) error {
+	db := config.GetDB()
+	s.progress.phase("events", len(events))
+
+	childIDs := make([]int, len(events))
+	for i, event := range events {
+		childIDs[i] = event.JobID
+	}
+
+	failed := 0
+	for i, event := range events {
+		var child models.ScrapeJob
+		if err := db.First(&child, event.JobID).Error; err != nil {
+			logger.Error("Bulk event job missing", zap.Int("job_id", event.JobID), zap.Error(err))
+			continue
+		}
+
+		if box.exceeded() {
+			// Max duration reached: resubmitting the same list with the resume
+			// token skips the events already done
+			point := ResumePoint{
+				JobType:   parent.JobType,
+				Position:  position + i,
+				Remaining: len(events) - i,
+			}
+			db.Model(&models.ScrapeJob{}).
+				Where("id IN ? AND status = ?", childIDs[i:], "pending").
+				Updates(map[string]interface{}{"status": "partial", "completed_at": time.Now()})
+			s.partialJob(parent, point)
+			return nil
+		}
+
+		child.Status = "running"
+		child.StartedAt = time.Now()
+		child.CurrentPhase = "details"
+		db.Save(&child)
+		bus.PublishJob(bus.JobStarted, &child)
+
+		results, err := s.ingestEvent(ctx, &child, event)
+		child.ItemsScraped = results
+		parent.ItemsScraped += results
+		db.Model(parent).Update("items_scraped", parent.ItemsScraped)
+		s.progress.add(1)
+
+		if ctx.Err() != nil {
+			s.cancelJob(ctx, &child)
+			db.Model(&models.ScrapeJob{}).
+				Where("id IN ? AND status = ?", childIDs[i+1:], "pending").
+				Updates(map[string]interface{}{"status": maintenance.StoppedStatus(context.Cause(ctx)), "completed_at": time.Now()})
+			s.cancelJob(ctx, parent)
+			return context.Cause(ctx)
+		}
+
+		if err != nil {
+			failed++
+			s.failJob(&child, err)
+			continue
+		}
+		s.completeJob(&child)
+	}
+
+	if failed == len(events) {
+		err := fmt.Errorf("all %d events failed", failed)
+		s.failJob(parent, err)
+		return err
+	}
+	s.completeJob(parent)
+	return nil
+}
+
+// ingestEvent scrapes details, participants and results of one event,
+// returning the results saved. A failed stage does not stop the next ones;
+// their errors are joined.
+func (s *Scraper) ingestEvent(ctx context.Context, job *models.ScrapeJob, event BulkEvent) (int, error) {
+	if err := checkBlocked(models.BlockedEvent, event.EventID); err != nil {
+		return 0, err
+	}
+
+	db := config.GetDB()
+	var stored models.Event
+	db.Where("external_id = ?", event.EventID).Limit(1).Find(&stored)
+	if event.EventURL == "" {
+		event.EventURL = stored.EventURL
+	}
+
+	var errs []error
+	details, err := s.FetchEventDetails(ctx, event.EventID, event.EventURL)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("details: %w", err))
+	} else {
+		if err := s.SaveEventDetails(details); err != nil {
+			errs = append(errs, fmt.Errorf("details: %w", err))
+		}
+		event.EventURL = details.EventURL
+		if stored.ID == 0 {
+			// Events outside the listings are only known by their page
+			stored = models.Event{
+				ExternalID: event.EventID,
+				Name:       details.Name,
+				EventURL:   details.EventURL,
+				ImageURL:   details.ImageURL,
+				City:       details.LocationCity,
+				Country:    details.LocationCountry,
+				ScrapedAt:  time.Now(),
+			}
+			if stored.Name == "" {
+				stored.Name = "Event " + event.EventID
+			}
+			if date, ok := parseEventDate(details.StartDate); ok {
+				stored.StartDate = &date
+			}
+			if err := s.SaveEvent(&stored); err != nil {
+				errs = append(errs, fmt.Errorf("event: %w", err))
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	name := stored.Name
+	if name == "" {
+		name = "Event " + event.EventID
+	}
+	s.bulkPhase(job, "participants")
+	if err := s.ScrapeEventAthletes(ctx, event.EventID, name, event.EventURL); err != nil {
+		errs = append(errs, fmt.Errorf("participants: %w", err))
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	s.bulkPhase(job, "results")
+	results, err := s.ScrapeEventResults(ctx, event.EventID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("results: %w", err))
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (s *Scraper) bulkPhase(job *models.ScrapeJob, phase string) {
+	job.CurrentPhase = phase
+	config.GetDB().Model(job).Update("current_phase", phase)
+}
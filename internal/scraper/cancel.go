@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrJobCancelled is the cause of jobs stopped by CancelJob
+var ErrJobCancelled = errors.New("job cancelled")
+
+// ErrShutdown is the cause of jobs stopped by CancelJobs on server shutdown
+var ErrShutdown = errors.New("server shutting down")
+
+// runningJob is a top-level job that can be cancelled
+type runningJob struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+}
+
+var (
+	runningJobs   = map[int]*runningJob{}
+	runningJobsMu sync.Mutex
+)
+
+// trackJob derives the context a top-level job runs with, so CancelJob,
+// CancelJobs and maintenance pages can stop it. release must be called once the job has recorded
+// its final status.
+func trackJob(ctx context.Context, job *models.ScrapeJob) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	ctx = maintenance.WithSignal(ctx, cancel)
+	running := &runningJob{cancel: cancel, done: make(chan struct{})}
+
+	runningJobsMu.Lock()
+	runningJobs[job.ID] = running
+	runningJobsMu.Unlock()
+
+	return ctx, func() {
+		runningJobsMu.Lock()
+		delete(runningJobs, job.ID)
+		runningJobsMu.Unlock()
+
+		cancel(nil)
+		close(running.done)
+	}
+}
+
+// CancelJob stops the running job jobID. Stage and chunk jobs cancel the
+// job they belong to. Returns the ID of the cancelled job, or false when
+// no such job is running in this process.
+func CancelJob(jobID int) (int, bool) {
+	db := config.GetDB()
+
+	// Walk up to the job that owns the context
+	id := jobID
+	for range 8 {
+		runningJobsMu.Lock()
+		running, ok := runningJobs[id]
+		runningJobsMu.Unlock()
+		if ok {
+			running.cancel(ErrJobCancelled)
+			logger.Info("Scrape job cancellation requested",
+				zap.Int("job_id", id),
+				zap.Int("requested_job_id", jobID))
+			return id, true
+		}
+
+		var job models.ScrapeJob
+		if err := db.Select("id", "parent_job_id").First(&job, id).Error; err != nil || job.ParentJobID == nil {
+			return 0, false
+		}
+		id = *job.ParentJobID
+	}
+	return 0, false
+}
+
+// CancelJobs stops every running job and waits until they record their
+// final status or ctx is done
+func CancelJobs(ctx context.Context) {
+	runningJobsMu.Lock()
+	jobs := make([]*runningJob, 0, len(runningJobs))
+	for _, running := range runningJobs {
+		running.cancel(ErrShutdown)
+		jobs = append(jobs, running)
+	}
+	runningJobsMu.Unlock()
+
+	if len(jobs) > 0 {
+		logger.Info("Cancelling running scrape jobs", zap.Int("jobs", len(jobs)))
+	}
+	for _, running := range jobs {
+		select {
+		case <-running.done:
+		case <-ctx.Done():
+			logger.Warn("Scrape jobs still running at shutdown")
+			return
+		}
+	}
+}
+
+// IsCancelled reports whether err comes from a job stopped by CancelJob or
+// CancelJobs
+func IsCancelled(err error) bool {
+	return errors.Is(err, ErrJobCancelled) || errors.Is(err, ErrShutdown)
+}
+
+// cancelJob marks a job stopped by its context, recording the cause. Jobs
+// stopped by a maintenance page are postponed rather than cancelled.
+func (s *Scraper) cancelJob(ctx context.Context, job *models.ScrapeJob) {
+	now := time.Now()
+	job.Status = maintenance.StoppedStatus(context.Cause(ctx))
+	job.CompletedAt = &now
+	job.ErrorMessage = context.Cause(ctx).Error()
+
+	config.GetDB().Save(job)
+	bus.PublishJob(bus.JobFinished, job)
+
+	logger.Info("Scrape job cancelled",
+		zap.Int("job_id", job.ID),
+		zap.Int("items_scraped", job.ItemsScraped),
+		zap.String("cause", job.ErrorMessage))
+}
+
+// endJob marks a job completed, or cancelled when ctx was cancelled before
+// it finished
+func (s *Scraper) endJob(ctx context.Context, job *models.ScrapeJob) {
+	if ctx.Err() != nil {
+		s.cancelJob(ctx, job)
+		return
+	}
+	s.completeJob(job)
+}
+
+// sleep waits for d, returning early with the cause when ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// dbStorage is a colly storage backed by the database, so visited pages and
+// cookies survive restarts and are shared by every collector clone. Visits
+// older than ttl no longer count, which lets pages be refreshed on a later
+// run.
+type dbStorage struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	visited map[uint64]time.Time // visits seen by this process
+}
+
+func newDBStorage(ttl time.Duration) *dbStorage {
+	return &dbStorage{ttl: ttl}
+}
+
+var (
+	sharedStorage     *dbStorage
+	sharedStorageOnce sync.Once
+)
+
+// getSharedStorage returns the process-wide crawl storage, or nil with
+// SCRAPER_STORAGE=memory
+func getSharedStorage(cfg *config.Config) *dbStorage {
+	sharedStorageOnce.Do(func() {
+		if cfg.Scraper.Storage == "database" {
+			sharedStorage = newDBStorage(cfg.Scraper.VisitedTTL)
+		}
+	})
+	return sharedStorage
+}
+
+// Init implements storage.Storage and drops the visits that expired
+func (s *dbStorage) Init() error {
+	s.mu.Lock()
+	if s.visited == nil {
+		s.visited = map[uint64]time.Time{}
+	}
+	s.mu.Unlock()
+
+	return config.GetDB().Where("visited_at < ?", time.Now().Add(-s.ttl)).
+		Delete(&models.CrawlVisit{}).Error
+}
+
+// Visited implements storage.Storage
+func (s *dbStorage) Visited(requestID uint64) error {
+	now := time.Now()
+	s.mu.Lock()
+	s.visited[requestID] = now
+	s.mu.Unlock()
+
+	return config.GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "request_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"visited_at"}),
+	}).Create(&models.CrawlVisit{RequestID: int64(requestID), VisitedAt: now}).Error
+}
+
+// IsVisited implements storage.Storage
+func (s *dbStorage) IsVisited(requestID uint64) (bool, error) {
+	since := time.Now().Add(-s.ttl)
+
+	s.mu.RLock()
+	at, ok := s.visited[requestID]
+	s.mu.RUnlock()
+	if ok {
+		return at.After(since), nil
+	}
+
+	var count int64
+	err := config.GetDB().Model(&models.CrawlVisit{}).
+		Where("request_id = ? AND visited_at >= ?", int64(requestID), since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Cookies implements storage.Storage
+func (s *dbStorage) Cookies(u *url.URL) string {
+	var row models.CrawlCookie
+	config.GetDB().Where("host = ?", u.Host).Limit(1).Find(&row)
+	return row.Cookies
+}
+
+// SetCookies implements storage.Storage
+func (s *dbStorage) SetCookies(u *url.URL, cookies string) {
+	err := config.GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "host"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cookies", "updated_at"}),
+	}).Create(&models.CrawlCookie{Host: u.Host, Cookies: cookies}).Error
+	if err != nil {
+		logger.Warn("Failed to store cookies", zap.String("host", u.Host), zap.Error(err))
+	}
+}
+
+// forget drops every visit this process saw; stored visits are cleared by
+// ClearCrawlState
+func (s *dbStorage) forget() {
+	s.mu.Lock()
+	s.visited = map[uint64]time.Time{}
+	s.mu.Unlock()
+}
+
+// ClearCrawlState deletes the stored visits and cookies so the next run
+// fetches every page again. Returns how many visits were removed.
+func ClearCrawlState() (int64, error) {
+	db := config.GetDB()
+	result := db.Where("1 = 1").Delete(&models.CrawlVisit{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if err := db.Where("1 = 1").Delete(&models.CrawlCookie{}).Error; err != nil {
+		return 0, err
+	}
+
+	sharedStorageOnce.Do(func() {})
+	if sharedStorage != nil {
+		sharedStorage.forget()
+	}
+
+	return result.RowsAffected, nil
+}
+
+// isAlreadyVisited reports whether a Visit was skipped because the page was
+// fetched recently
+func isAlreadyVisited(err error) bool {
+	var visited *colly.AlreadyVisitedError
+	return errors.As(err, &visited)
+}
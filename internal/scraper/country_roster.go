@@ -0,0 +1,244 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/pipeline"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CountryRosterPipeline is the job type of country roster pipelines
+const CountryRosterPipeline = "country_roster"
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NormalizeCountryCode uppercases an ISO country code ("br" -> "BR") and
+// rejects anything else
+func NormalizeCountryCode(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if !countryCodePattern.MatchString(country) {
+		return "", fmt.Errorf("invalid country %q (expected a two-letter code such as BR)", country)
+	}
+	return country, nil
+}
+
+// countryRosterPipeline builds the stages of a country roster: the past
+// events held in the country, their participants, and the national registry
+// built from them
+func (s *Scraper) countryRosterPipeline(country string) *pipeline.Pipeline {
+	return &pipeline.Pipeline{
+		Name:    CountryRosterPipeline,
+		Retries: s.config.Scraper.PipelineStageRetries,
+		Backoff: s.config.Scraper.PipelineRetryBackoff,
+		Stages: []pipeline.Stage{
+			{Name: "discover", Run: func(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+				return s.stageRosterDiscover(ctx, country, job)
+			}},
+			{Name: "participants", Run: func(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+				return s.stageRosterParticipants(ctx, country, run, job)
+			}},
+			{Name: "registry", Run: func(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+				built, err := BuildCountryRoster(country)
+				job.ItemsScraped = built
+				return err
+			}},
+			{Name: "aggregates", Run: s.stageAggregates},
+		},
+	}
+}
+
+// ScrapeCountryRoster discovers the past events held in a country, scrapes
+// the participants of each and builds the national athlete registry, all
+// tracked as one pipeline job
+func (s *Scraper) ScrapeCountryRoster(ctx context.Context, country string) error {
+	country, err := NormalizeCountryCode(country)
+	if err != nil {
+		return err
+	}
+	logger.Info("Starting country roster scraping", zap.String("country", country))
+
+	roster := s.countryRosterPipeline(country)
+	run := roster.Start()
+	run.Job.Country = country
+	config.GetDB().Model(run.Job).Update("country", country)
+
+	ctx, release := trackJob(ctx, run.Job)
+	defer release()
+	return roster.Execute(ctx, run)
+}
+
+// CheckResumeCountryRoster reports why a country roster job cannot be
+// resumed, without reopening it
+func (s *Scraper) CheckResumeCountryRoster(jobID int) error {
+	return s.countryRosterPipeline("").CheckResume(jobID)
+}
+
+// ResumeCountryRoster validates that a failed country roster job can be
+// resumed and returns a function that continues it from its first
+// unfinished stage
+func (s *Scraper) ResumeCountryRoster(jobID int) (func(ctx context.Context) error, error) {
+	var job models.ScrapeJob
+	if err := config.GetDB().First(&job, jobID).Error; err != nil {
+		return nil, fmt.Errorf("job %d not found", jobID)
+	}
+	roster := s.countryRosterPipeline(job.Country)
+	run, err := roster.Resume(jobID)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		ctx, release := trackJob(ctx, run.Job)
+		defer release()
+		return roster.Execute(ctx, run)
+	}, nil
+}
+
+// stageRosterDiscover stores the past events listed for the country
+func (s *Scraper) stageRosterDiscover(ctx context.Context, country string, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	s.progress.phase("listing", 0)
+	listing, err := s.ScrapeEventsByCountry(ctx, "past", country)
+	if err != nil {
+		return err
+	}
+
+	s.progress.phase("events", len(listing))
+	for i := range listing {
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if err := s.SaveEvent(&listing[i]); err != nil {
+			logger.Error("Failed to save event", zap.String("event", listing[i].Name), zap.Error(err))
+		} else {
+			job.ItemsScraped++
+		}
+		s.progress.add(1)
+	}
+	return nil
+}
+
+// stageRosterParticipants stores the registrations of every stored past
+// event of the country. Events whose participants were already stored by
+// this run are skipped, so a retried or resumed stage continues where it
+// stopped.
+func (s *Scraper) stageRosterParticipants(ctx context.Context, country string, run *pipeline.Run, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	db := config.GetDB()
+	var events []models.Event
+	err := db.Where("country_code = ? AND event_type = ? AND external_id <> ''", country, "past").
+		Where("external_id NOT IN (?)", blockedIDs(db, models.BlockedEvent)).
+		Where("external_id NOT IN (?)", db.Model(&models.EventRegistration{}).Select("event_id").Where("updated_at >= ?", run.Since)).
+		Order("id ASC").
+		Find(&events).Error
+	if err != nil {
+		return fmt.Errorf("error loading country events: %w", err)
+	}
+
+	return s.eachEvent(ctx, events, job, func(event models.Event) error {
+		return s.ScrapeEventAthletes(ctx, event.ExternalID, event.Name, event.EventURL)
+	})
+}
+
+// BuildCountryRoster rebuilds the national registry of a country from the
+// stored registrations: every athlete of that nationality registered in a
+// past event held there. Returns how many athletes it holds.
+func BuildCountryRoster(country string) (int, error) {
+	db := config.GetDB()
+
+	registered := db.Table("event_registrations").
+		Joins("JOIN athletes ON athletes.id = event_registrations.athlete_id").
+		Joins("JOIN events ON events.external_id = event_registrations.event_id").
+		Where("events.country_code = ? AND events.event_type = ? AND athletes.country_code = ?", country, "past", country)
+
+	var rows []struct {
+		AthleteID         uint
+		ExternalID        string
+		FullName          string
+		AcademyExternalID string
+		Events            int
+	}
+	err := registered.Session(&gorm.Session{}).
+		Select("athletes.id AS athlete_id, athletes.external_id, athletes.full_name, athletes.academy_external_id, " +
+			"COUNT(DISTINCT events.external_id) AS events").
+		Group("athletes.id, athletes.external_id, athletes.full_name, athletes.academy_external_id").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, fmt.Errorf("error aggregating country registrations: %w", err)
+	}
+
+	// sqlite hands MIN/MAX of a datetime back as text, so the first and last
+	// event dates are worked out here rather than in SQL
+	var dates []struct {
+		AthleteID uint
+		StartDate *time.Time
+	}
+	err = registered.Session(&gorm.Session{}).
+		Select("event_registrations.athlete_id, events.start_date").
+		Where("events.start_date IS NOT NULL").
+		Scan(&dates).Error
+	if err != nil {
+		return 0, fmt.Errorf("error loading country event dates: %w", err)
+	}
+	first := make(map[uint]*time.Time)
+	last := make(map[uint]*time.Time)
+	for _, d := range dates {
+		if d.StartDate == nil {
+			continue
+		}
+		if f := first[d.AthleteID]; f == nil || d.StartDate.Before(*f) {
+			first[d.AthleteID] = d.StartDate
+		}
+		if l := last[d.AthleteID]; l == nil || d.StartDate.After(*l) {
+			last[d.AthleteID] = d.StartDate
+		}
+	}
+
+	now := time.Now()
+	entries := make([]models.CountryRosterEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, models.CountryRosterEntry{
+			CountryCode:       country,
+			AthleteID:         row.AthleteID,
+			AthleteExternalID: row.ExternalID,
+			FullName:          row.FullName,
+			AcademyExternalID: row.AcademyExternalID,
+			Events:            row.Events,
+			FirstEventAt:      first[row.AthleteID],
+			LastEventAt:       last[row.AthleteID],
+			BuiltAt:           now,
+		})
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("country_code = ?", country).Delete(&models.CountryRosterEntry{}).Error; err != nil {
+			return fmt.Errorf("error clearing country roster: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(entries, 500).Error; err != nil {
+			return fmt.Errorf("error saving country roster: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("Country roster built",
+		zap.String("country", country),
+		zap.Int("athletes", len(entries)))
+	return len(entries), nil
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"sync"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// parseCoverage tallies which fields the records parsed by one job had
+type parseCoverage struct {
+	mu     sync.Mutex
+	fields map[[2]string]*models.FieldCoverage
+}
+
+// forJob returns a copy of the scraper that tallies parse coverage and
+// progress for job, records its requests with opts.Debug and applies
+// opts.Behavior. finish stores the coverage in the job summary, writes the
+// last progress and closes the recording once the job is done.
+func (s *Scraper) forJob(job *models.ScrapeJob, opts RunOptions) (*Scraper, func()) {
+	scoped := *s
+	// Snapshot the configuration, so a reload only affects later jobs
+	scoped.config = s.config.Latest()
+	if scoped.config != s.config {
+		scoped.collector, scoped.limit, scoped.storage = newCollector(scoped.config, s.transport, s.storage)
+	}
+	scoped.coverage = &parseCoverage{fields: make(map[[2]string]*models.FieldCoverage)}
+	scoped.progress = newJobProgress(job)
+
+	if opts.Behavior != nil && opts.Behavior.UseProxy {
+		if proxied := getProxyTransport(scoped.config); proxied != nil {
+			scoped.transport = proxied
+		} else if getProxyPool(scoped.config) == nil {
+			logger.Warn("Profile asks for a proxy but neither SCRAPER_PROXY_URL nor SCRAPER_PROXIES is set",
+				zap.String("profile", opts.Behavior.Name))
+		}
+	}
+
+	var recorder *jobRecorder
+	if opts.Debug {
+		if recorder = scoped.newJobRecorder(job); recorder != nil {
+			scoped.transport = recorder
+		}
+	}
+
+	if opts.Behavior != nil {
+		scoped.behavior = opts.Behavior
+		scoped.transport = newBehaviorTransport(scoped.transport, *opts.Behavior, scoped.config.Scraper.UserAgents)
+		job.Profile = opts.Behavior.Name
+		config.GetDB().Model(job).UpdateColumn("profile", job.Profile)
+	}
+
+	return &scoped, func() {
+		scoped.coverage.save(job.ID)
+		scoped.progress.flush()
+		if recorder != nil {
+			recorder.close()
+		}
+	}
+}
+
+// observeFields counts one parsed record of kind record, with whether each
+// field was found, in the process metrics and the current job's coverage
+func (s *Scraper) observeFields(record string, fields map[string]bool) {
+	for field, found := range fields {
+		metrics.ObserveField(record, field, found)
+	}
+
+	c := s.coverage
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for field, found := range fields {
+		key := [2]string{record, field}
+		count := c.fields[key]
+		if count == nil {
+			count = &models.FieldCoverage{Record: record, Field: field}
+			c.fields[key] = count
+		}
+		count.Add(found)
+	}
+}
+
+// save replaces the stored coverage of the job, so a retried stage keeps
+// only its last attempt
+func (c *parseCoverage) save(jobID int) {
+	c.mu.Lock()
+	rows := make([]models.FieldCoverage, 0, len(c.fields))
+	for _, count := range c.fields {
+		row := *count
+		row.JobID = jobID
+		rows = append(rows, row)
+	}
+	c.mu.Unlock()
+
+	db := config.GetDB()
+	if err := db.Where("job_id = ?", jobID).Delete(&models.FieldCoverage{}).Error; err != nil {
+		logger.Warn("Failed to clear job parse coverage", zap.Int("job_id", jobID), zap.Error(err))
+		return
+	}
+	if len(rows) == 0 {
+		return
+	}
+	models.SortFieldCoverage(rows)
+	if err := db.Create(&rows).Error; err != nil {
+		logger.Warn("Failed to save job parse coverage", zap.Int("job_id", jobID), zap.Error(err))
+	}
+}
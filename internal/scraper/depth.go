@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Depth controls how far a discovery job follows each listed event. Every
+// level includes the ones before it.
+type Depth int
+
+const (
+	DepthListing      Depth = iota // event cards only
+	DepthDetails                   // + event detail page and info panels
+	DepthParticipants              // + registered athletes
+	DepthProfiles                  // + profiles of registered athletes
+	DepthBrackets                  // + brackets, matches and results
+)
+
+var depthNames = []string{"listing", "details", "participants", "profiles", "brackets"}
+
+// String returns the name of the depth as accepted by ParseDepth
+func (d Depth) String() string {
+	if d < DepthListing || int(d) >= len(depthNames) {
+		return fmt.Sprintf("depth(%d)", int(d))
+	}
+	return depthNames[d]
+}
+
+// ParseDepth parses a depth name. An empty string means DepthListing.
+func ParseDepth(value string) (Depth, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return DepthListing, nil
+	}
+	for i, name := range depthNames {
+		if name == value {
+			return Depth(i), nil
+		}
+	}
+	return DepthListing, fmt.Errorf("invalid depth %q (expected one of %s)", value, strings.Join(depthNames, ", "))
+}
+
+// crawlEvent follows a saved event down to the requested depth. Failures are
+// logged and do not stop the remaining levels; a cancelled ctx does.
+func (s *Scraper) crawlEvent(ctx context.Context, event models.Event, depth Depth) {
+	if depth < DepthDetails {
+		return
+	}
+
+	eventID := event.ExternalID
+	if eventID == "" {
+		eventID = ExtractIDFromURL(event.EventURL)
+	}
+	if eventID == "" {
+		logger.Warn("Skipping deep crawl of event without ID", zap.String("event_url", event.EventURL))
+		return
+	}
+	if IsBlocked(models.BlockedEvent, eventID) {
+		logger.Info("Skipping deep crawl of blocklisted event", zap.String("event_id", eventID))
+		return
+	}
+
+	if details, err := s.FetchEventDetails(ctx, eventID, event.EventURL); err != nil {
+		logger.Error("Failed to fetch event details", zap.String("event_id", eventID), zap.Error(err))
+	} else if err := s.SaveEventDetails(details); err != nil {
+		logger.Error("Failed to save event details", zap.String("event_id", eventID), zap.Error(err))
+	}
+
+	if depth < DepthParticipants || ctx.Err() != nil {
+		return
+	}
+	if err := s.ScrapeEventAthletes(ctx, eventID, event.Name, event.EventURL); err != nil {
+		logger.Error("Failed to scrape event athletes", zap.String("event_id", eventID), zap.Error(err))
+		return
+	}
+
+	if depth < DepthProfiles || ctx.Err() != nil {
+		return
+	}
+	s.scrapeEventProfiles(ctx, eventID)
+
+	if depth < DepthBrackets || ctx.Err() != nil {
+		return
+	}
+	if _, err := s.ScrapeEventBrackets(ctx, eventID, event.EventURL); err != nil {
+		logger.Error("Failed to scrape event brackets", zap.String("event_id", eventID), zap.Error(err))
+	}
+	if _, err := s.ScrapeEventResults(ctx, eventID); err != nil {
+		logger.Error("Failed to scrape event results", zap.String("event_id", eventID), zap.Error(err))
+	}
+}
+
+// scrapeEventProfiles refreshes the profile of every athlete registered in an event
+func (s *Scraper) scrapeEventProfiles(ctx context.Context, eventID string) {
+	db := config.GetDB()
+
+	var athletes []models.Athlete
+	err := db.Where("id IN (?)", db.Model(&models.EventRegistration{}).Select("athlete_id").Where("event_id = ?", eventID)).
+		Find(&athletes).Error
+	if err != nil {
+		logger.Error("Failed to load event athletes", zap.String("event_id", eventID), zap.Error(err))
+		return
+	}
+
+	for _, athlete := range athletes {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.ScrapeAthleteProfile(ctx, athlete.ExternalID, athlete.ProfileURL); err != nil {
+			logger.Warn("Failed to scrape athlete profile",
+				zap.String("athlete_id", athlete.ExternalID),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// eventDigest collects the changes of one upcoming-events scrape
+type eventDigest struct {
+	digest  models.EventDigest
+	seen    []models.Event
+	created []models.Event // events not stored before this scrape
+}
+
+func newEventDigest(jobID int, eventType string) *eventDigest {
+	return &eventDigest{digest: models.EventDigest{JobID: jobID, EventType: eventType}}
+}
+
+// observe compares a scraped event with the stored one; it must run before
+// the event is saved
+func (d *eventDigest) observe(event models.Event) {
+	var stored models.Event
+	query := config.GetDB().Where("event_url = ?", ResolveEventURL(config.GetDB(), urlnorm.EventURL(event.EventURL)))
+	if event.EventURL == "" && event.ExternalID != "" {
+		query = config.GetDB().Where("external_id = ?", event.ExternalID)
+	}
+
+	item := models.EventDigestItem{
+		EventID:   event.ExternalID,
+		EventName: event.Name,
+		EventURL:  urlnorm.EventURL(event.EventURL),
+	}
+
+	if err := query.First(&stored).Error; err != nil {
+		item.Kind = models.DigestNewEvent
+		item.After = event.DateText
+		d.add(item)
+		d.seen = append(d.seen, event)
+		d.created = append(d.created, event)
+		return
+	}
+
+	if stored.DateText != "" && event.DateText != "" && stored.DateText != event.DateText {
+		change := item
+		change.Kind = models.DigestDateChanged
+		change.Before = stored.DateText
+		change.After = event.DateText
+		d.add(change)
+	}
+
+	before, after := venueText(stored), venueText(event)
+	if before != "" && after != "" && before != after {
+		change := item
+		change.Kind = models.DigestVenueChanged
+		change.Before = before
+		change.After = after
+		d.add(change)
+	}
+
+	d.seen = append(d.seen, event)
+}
+
+func (d *eventDigest) add(item models.EventDigestItem) {
+	d.digest.Items = append(d.digest.Items, item)
+}
+
+// countRegistrations reports events whose registrations grew by at least
+// jump since the previous digest and records the new counts
+func (d *eventDigest) countRegistrations(jump int) error {
+	db := config.GetDB()
+
+	for _, event := range d.seen {
+		if event.ExternalID == "" {
+			continue
+		}
+
+		var count int64
+		if err := db.Model(&models.EventRegistration{}).Where("event_id = ?", event.ExternalID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		var previous models.EventRegistrationCount
+		found := db.Where("event_id = ?", event.ExternalID).Limit(1).Find(&previous).RowsAffected > 0
+		if found && jump > 0 && int(count)-previous.Count >= jump {
+			d.add(models.EventDigestItem{
+				Kind:      models.DigestRegistrationsJump,
+				EventID:   event.ExternalID,
+				EventName: event.Name,
+				EventURL:  urlnorm.EventURL(event.EventURL),
+				Before:    fmt.Sprint(previous.Count),
+				After:     fmt.Sprint(count),
+				Delta:     int(count) - previous.Count,
+			})
+		}
+
+		if found && previous.Count == int(count) {
+			continue
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"count", "updated_at"}),
+		}).Create(&models.EventRegistrationCount{EventID: event.ExternalID, Count: int(count)}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishDigest stores the digest and sends it to the notification channels
+// when it has changes
+func (s *Scraper) publishDigest(d *eventDigest) {
+	if err := d.countRegistrations(s.config.Notifications.RegistrationJump); err != nil {
+		logger.Error("Failed to count event registrations for digest", zap.Error(err))
+	}
+
+	s.notifySubscribers(d.created)
+
+	if err := config.GetDB().Create(&d.digest).Error; err != nil {
+		logger.Error("Failed to save event digest", zap.Error(err))
+		return
+	}
+
+	logger.Info("Event digest generated",
+		zap.Int("digest_id", d.digest.ID),
+		zap.Int("changes", len(d.digest.Items)))
+
+	if len(d.digest.Items) == 0 {
+		return
+	}
+	s.notifier.Publish(notify.Notification{
+		Kind:  "event_digest",
+		Title: fmt.Sprintf("%d event changes", len(d.digest.Items)),
+		Text:  digestText(d.digest),
+		Data:  d.digest,
+	})
+}
+
+func digestText(digest models.EventDigest) string {
+	lines := make([]string, 0, len(digest.Items))
+	for _, item := range digest.Items {
+		switch item.Kind {
+		case models.DigestNewEvent:
+			lines = append(lines, fmt.Sprintf("New event: %s %s", item.EventName, item.After))
+		case models.DigestDateChanged:
+			lines = append(lines, fmt.Sprintf("Date changed: %s (%s -> %s)", item.EventName, item.Before, item.After))
+		case models.DigestVenueChanged:
+			lines = append(lines, fmt.Sprintf("Venue changed: %s (%s -> %s)", item.EventName, item.Before, item.After))
+		case models.DigestRegistrationsJump:
+			lines = append(lines, fmt.Sprintf("Registrations: %s +%d (%s)", item.EventName, item.Delta, item.After))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func venueText(event models.Event) string {
+	parts := []string{}
+	for _, part := range []string{event.City, event.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
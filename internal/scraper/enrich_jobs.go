@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// assumed cost of one profile (page + events API) before any job has been measured
+const defaultProfileCost = 1500 * time.Millisecond
+
+// EnrichProfiles selects the athletes to enrich, records a parent job with one
+// pending child job per chunk of EnrichChunkSize athletes, and runs the chunks
+// sequentially until done or ctx is cancelled. A resumed run continues after
+// the last athlete processed by the partial run.
+func (s *Scraper) EnrichProfiles(ctx context.Context, limit int, offset int, onlyMissing bool, opts RunOptions) error {
+	if opts.Resume != nil {
+		limit, offset, onlyMissing = opts.Resume.Remaining, 0, opts.Resume.OnlyMissing
+	}
+
+	maxTotal := s.config.Scraper.EnrichMaxTotal
+	if limit > maxTotal {
+		return fmt.Errorf("limit %d exceeds the maximum of %d profiles per request", limit, maxTotal)
+	}
+	if limit <= 0 {
+		limit = maxTotal
+	}
+
+	// Resolve the selection now: with only_missing, enriching a chunk would
+	// otherwise shift the offset of the next one
+	query := profileSelection(limit, offset, onlyMissing)
+	if opts.Resume != nil {
+		query = query.Where("id > ?", opts.Resume.AfterID)
+	}
+	var athletes []models.Athlete
+	if err := query.Find(&athletes).Error; err != nil {
+		return fmt.Errorf("error loading athletes: %w", err)
+	}
+
+	chunkSize := s.config.Scraper.EnrichChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(athletes)
+	}
+
+	db := config.GetDB()
+	parent := s.createJob("profiles_enrich")
+	if opts.MaxDuration > 0 {
+		parent.MaxDuration = int(opts.MaxDuration.Seconds())
+		db.Model(parent).Update("max_duration", parent.MaxDuration)
+	}
+	var chunkIDs []int
+	var chunks [][]models.Athlete
+	for start := 0; start < len(athletes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(athletes) {
+			end = len(athletes)
+		}
+		chunk := &models.ScrapeJob{
+			ParentJobID: &parent.ID,
+			JobType:     "profiles_enrich_chunk",
+			Status:      "pending",
+			StartedAt:   time.Now(),
+		}
+		db.Create(chunk)
+		chunkIDs = append(chunkIDs, chunk.ID)
+		chunks = append(chunks, athletes[start:end])
+	}
+
+	logger.Info("Profile enrichment planned",
+		zap.Int("job_id", parent.ID),
+		zap.Int("athletes", len(athletes)),
+		zap.Int("chunks", len(chunks)),
+		zap.Duration("estimate", time.Duration(len(athletes))*s.profileCost()))
+
+	ctx, release := trackJob(ctx, parent)
+	defer release()
+	runner, finish := s.forJob(parent, opts)
+	defer finish()
+	return runner.runEnrichmentChunks(ctx, parent, chunkIDs, chunks, onlyMissing, newTimeBox(opts.MaxDuration))
+}
+
+func (s *Scraper) runEnrichmentChunks(ctx context.Context, parent *models.ScrapeJob, chunkIDs []int, chunks [][]models.Athlete, onlyMissing bool, box timeBox) error {
+	db := config.GetDB()
+
+	remaining := 0
+	for _, athletes := range chunks {
+		remaining += len(athletes)
+	}
+	s.progress.phase("profiles", remaining)
+
+	for i, athletes := range chunks {
+		var chunk models.ScrapeJob
+		if err := db.First(&chunk, chunkIDs[i]).Error; err != nil {
+			logger.Error("Enrichment chunk job missing", zap.Int("job_id", chunkIDs[i]), zap.Error(err))
+			continue
+		}
+
+		chunk.Status = "running"
+		chunk.StartedAt = time.Now()
+		chunk.CurrentPhase = "profiles"
+		chunk.ItemsTotal = len(athletes)
+		db.Save(&chunk)
+		bus.PublishJob(bus.JobStarted, &chunk)
+
+		scraped, processed := s.scrapeProfiles(ctx, athletes, box)
+		chunk.ItemsScraped = scraped
+		chunk.ItemsProcessed = processed
+		remaining -= processed
+
+		parent.ItemsScraped += chunk.ItemsScraped
+		db.Model(parent).Update("items_scraped", parent.ItemsScraped)
+
+		if ctx.Err() != nil {
+			s.cancelJob(ctx, &chunk)
+			db.Model(&models.ScrapeJob{}).
+				Where("id IN ? AND status = ?", chunkIDs[i+1:], "pending").
+				Updates(map[string]interface{}{"status": maintenance.StoppedStatus(context.Cause(ctx)), "completed_at": time.Now()})
+			s.cancelJob(ctx, parent)
+			return context.Cause(ctx)
+		}
+
+		if processed < len(athletes) {
+			// Max duration reached: resume from the first athlete not processed
+			// (the selection is ordered by ID)
+			point := ResumePoint{
+				JobType:     parent.JobType,
+				AfterID:     athletes[processed].ID - 1,
+				Remaining:   remaining,
+				OnlyMissing: onlyMissing,
+			}
+			s.partialJob(&chunk, point)
+			db.Model(&models.ScrapeJob{}).
+				Where("id IN ? AND status = ?", chunkIDs[i+1:], "pending").
+				Updates(map[string]interface{}{"status": "partial", "completed_at": time.Now()})
+			s.partialJob(parent, point)
+			return nil
+		}
+
+		s.completeJob(&chunk)
+	}
+
+	s.completeJob(parent)
+	return nil
+}
+
+// profileCost estimates the wall time of one profile from finished
+// enrichment chunks, falling back to the request delay plus a default
+func (s *Scraper) profileCost() time.Duration {
+	var stats struct {
+		Seconds float64
+		Items   int64
+	}
+	config.GetDB().Model(&models.ScrapeJob{}).
+		Select("SUM("+config.DaysBetweenSQL("completed_at", "started_at")+" * 86400) AS seconds, SUM(items_scraped) AS items").
+		Where("job_type = ? AND status = ? AND items_scraped > 0", "profiles_enrich_chunk", "completed").
+		Scan(&stats)
+
+	if stats.Items > 0 && stats.Seconds > 0 {
+		return time.Duration(stats.Seconds / float64(stats.Items) * float64(time.Second))
+	}
+	return time.Duration(s.config.Scraper.RequestDelayMs)*time.Millisecond + defaultProfileCost
+}
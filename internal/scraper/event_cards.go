@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+	"go.uber.org/zap"
+)
+
+// maxEventCardBytes caps a single credential download
+const maxEventCardBytes = 5 << 20
+
+// EventCardsSummary reports the outcome of a bulk event card download
+type EventCardsSummary struct {
+	Written int
+	Failed  int
+}
+
+// EventCardRegistrations returns the registrations of an event that have an
+// event card, optionally narrowed to one division. Athletes whose personal
+// data was removed are left out.
+func EventCardRegistrations(eventID, divisionID string) ([]models.EventRegistration, error) {
+	db := config.GetDB()
+	query := db.Preload("Athlete").
+		Where("event_id = ? AND event_card_url <> ''", eventID).
+		Where("athlete_id NOT IN (?)", db.Model(&models.SuppressedAthlete{}).Select("athlete_id"))
+	if divisionID != "" {
+		query = query.Where("division_id = ?", divisionID)
+	}
+
+	var registrations []models.EventRegistration
+	if err := query.Order("division, weight_class, id").Find(&registrations).Error; err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// WriteEventCards downloads the credential PDF of every registration and
+// writes them to w as a zip, one folder per division. Cards that fail to
+// download are listed in missing.txt instead of aborting the archive.
+func (s *Scraper) WriteEventCards(ctx context.Context, registrations []models.EventRegistration, w io.Writer) (EventCardsSummary, error) {
+	var summary EventCardsSummary
+	archive := zip.NewWriter(w)
+	client := s.newHTTPClient(30 * time.Second)
+
+	var missing []string
+	for _, registration := range registrations {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		name := eventCardFileName(registration)
+		card, err := s.fetchEventCard(ctx, client, registration.EventCardURL)
+		if err != nil {
+			summary.Failed++
+			missing = append(missing, fmt.Sprintf("%s\t%s\t%v", name, registration.EventCardURL, err))
+			logger.Warn("Failed to download event card",
+				zap.Uint("registration_id", registration.ID),
+				zap.Error(err))
+			continue
+		}
+
+		entry, err := archive.Create(name)
+		if err != nil {
+			return summary, err
+		}
+		if _, err := entry.Write(card); err != nil {
+			return summary, err
+		}
+		summary.Written++
+	}
+
+	if len(missing) > 0 {
+		entry, err := archive.Create("missing.txt")
+		if err != nil {
+			return summary, err
+		}
+		if _, err := io.WriteString(entry, strings.Join(missing, "\n")+"\n"); err != nil {
+			return summary, err
+		}
+	}
+
+	return summary, archive.Close()
+}
+
+// fetchEventCard downloads one credential and checks that it is a PDF
+func (s *Scraper) fetchEventCard(ctx context.Context, client *http.Client, cardURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", cardURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+	req.Header.Set("Accept", "application/pdf")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxEventCardBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEventCardBytes {
+		return nil, fmt.Errorf("card exceeds %d bytes", maxEventCardBytes)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return nil, fmt.Errorf("not a PDF (%s)", resp.Header.Get("Content-Type"))
+	}
+	return data, nil
+}
+
+// eventCardFileName places a card under its division folder, named after
+// the athlete and the registration ID so names never collide
+func eventCardFileName(registration models.EventRegistration) string {
+	division := slug.Make(strings.Join([]string{registration.Division, registration.AgeCategory,
+		registration.Rank, registration.WeightClass}, " "))
+	if division == "" {
+		division = "division"
+	}
+
+	athlete := slug.Make(registration.Athlete.LastName + " " + registration.Athlete.FirstName)
+	if athlete == "" {
+		athlete = "athlete"
+	}
+	return fmt.Sprintf("%s/%s-%d.pdf", division, athlete, registration.ID)
+}
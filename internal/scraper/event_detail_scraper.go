@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,8 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/sanitize"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
 	"gorm.io/gorm"
 )
 
@@ -54,14 +57,13 @@ type eventJSONLD struct {
 }
 
 // FetchEventDetails loads event details for the given event ID or URL.
-func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDetails, error) {
+func (s *Scraper) FetchEventDetails(ctx context.Context, eventID string, eventURL string) (*EventDetails, error) {
 	if eventID == "" && eventURL == "" {
 		return nil, fmt.Errorf("event_id or event_url is required")
 	}
 
 	if eventURL == "" {
-		subdomain := s.DetectEventSubdomain(eventID)
-		eventURL = fmt.Sprintf("https://%s/en/event/%s", subdomain, eventID)
+		eventURL = fmt.Sprintf("https://%s/en/event/%s", s.eventHost(ctx, eventID, ""), eventID)
 	}
 
 	if eventID == "" {
@@ -70,9 +72,13 @@ func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDeta
 	if eventID == "" {
 		return nil, fmt.Errorf("failed to resolve event_id from event_url")
 	}
+	if err := checkBlocked(models.BlockedEvent, eventID); err != nil {
+		return nil, err
+	}
 
-	client := &http.Client{Timeout: 20 * time.Second}
-	req, err := http.NewRequest("GET", eventURL, nil)
+	client := s.newHTTPClient(20 * time.Second)
+	redirected := trackRedirects(client)
+	req, err := http.NewRequestWithContext(ctx, "GET", eventURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating event request: %w", err)
 	}
@@ -85,10 +91,19 @@ func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDeta
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		markSubdomainStale(eventID)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("event page returned status %d", resp.StatusCode)
 	}
 
+	// Events moved to another subdomain or renamed answer with a redirect
+	if eventURL, err = followEventRedirect(eventID, eventURL, redirected()); err != nil {
+		return nil, fmt.Errorf("error recording event redirect: %w", err)
+	}
+	rememberEventSubdomain(eventID, ExtractSubdomainFromURL(eventURL))
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing event HTML: %w", err)
@@ -112,7 +127,7 @@ func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDeta
 		details.OrganizerName = ld.Organizer.Name
 	}
 
-	if infoPanels, err := s.fetchEventInfoPanels(eventURL, eventID); err == nil {
+	if infoPanels, err := s.fetchEventInfoPanels(ctx, eventURL, eventID); err == nil {
 		details.InfoPanels = infoPanels
 		if details.LocationCity == "" {
 			if city, ok := infoPanels["location_city"].(string); ok {
@@ -143,7 +158,7 @@ func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDeta
 		}
 	}
 
-	if blocks, err := s.fetchEventInfoBlocks(eventURL, eventID); err == nil {
+	if blocks, err := s.fetchEventInfoBlocks(ctx, eventURL, eventID); err == nil {
 		if value, ok := blocks["infoPageBlocks"].(interface{}); ok {
 			details.InfoPageBlocks = value
 		} else {
@@ -151,6 +166,16 @@ func (s *Scraper) FetchEventDetails(eventID string, eventURL string) (*EventDeta
 		}
 	}
 
+	s.observeFields("event_details", map[string]bool{
+		"name":        details.Name != "",
+		"start_date":  details.StartDate != "",
+		"location":    details.LocationName != "" || details.LocationCity != "",
+		"organizer":   details.OrganizerName != "",
+		"description": details.Description != "",
+		"image":       details.ImageURL != "",
+		"info_panels": details.InfoPanels != nil,
+	})
+
 	return details, nil
 }
 
@@ -189,22 +214,22 @@ func isSportsEvent(eventType string) bool {
 	return strings.EqualFold(strings.TrimSpace(eventType), "SportsEvent")
 }
 
-func (s *Scraper) fetchEventInfoPanels(eventURL string, eventID string) (map[string]interface{}, error) {
+func (s *Scraper) fetchEventInfoPanels(ctx context.Context, eventURL string, eventID string) (map[string]interface{}, error) {
 	endpoint, err := buildEventEndpoint(eventURL, eventID, "getInfoPanelsData")
 	if err != nil {
 		return nil, err
 	}
 
-	return fetchJSON(endpoint, s.config.Scraper.UserAgent)
+	return s.fetchJSON(ctx, endpoint)
 }
 
-func (s *Scraper) fetchEventInfoBlocks(eventURL string, eventID string) (map[string]interface{}, error) {
+func (s *Scraper) fetchEventInfoBlocks(ctx context.Context, eventURL string, eventID string) (map[string]interface{}, error) {
 	endpoint, err := buildEventEndpoint(eventURL, eventID, "getCmsData")
 	if err != nil {
 		return nil, err
 	}
 
-	return fetchJSON(endpoint, s.config.Scraper.UserAgent)
+	return s.fetchJSON(ctx, endpoint)
 }
 
 func buildEventEndpoint(eventURL string, eventID string, suffix string) (string, error) {
@@ -217,13 +242,13 @@ func buildEventEndpoint(eventURL string, eventID string, suffix string) (string,
 	return fmt.Sprintf("%s/en/event/%s/%s", host, eventID, suffix), nil
 }
 
-func fetchJSON(endpoint string, userAgent string) (map[string]interface{}, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	req, err := http.NewRequest("GET", endpoint, nil)
+func (s *Scraper) fetchJSON(ctx context.Context, endpoint string) (map[string]interface{}, error) {
+	client := s.newHTTPClient(20 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
-	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := client.Do(req)
@@ -264,9 +289,9 @@ func (s *Scraper) SaveEventDetails(details *EventDetails) error {
 
 	record := models.EventDetail{
 		EventID:            details.EventID,
-		EventURL:           details.EventURL,
+		EventURL:           ResolveEventURL(config.GetDB(), urlnorm.EventURL(details.EventURL)),
 		Name:               details.Name,
-		Description:        details.Description,
+		Description:        sanitize.HTML(details.Description),
 		StartDate:          details.StartDate,
 		EndDate:            details.EndDate,
 		ImageURL:           details.ImageURL,
@@ -284,8 +309,8 @@ func (s *Scraper) SaveEventDetails(details *EventDetails) error {
 	var existing models.EventDetail
 
 	query := db.Where("event_id = ?", details.EventID)
-	if details.EventURL != "" {
-		query = query.Or("event_url = ?", details.EventURL)
+	if record.EventURL != "" {
+		query = query.Or("event_url = ?", record.EventURL)
 	}
 
 	result := query.First(&existing)
@@ -295,18 +320,20 @@ func (s *Scraper) SaveEventDetails(details *EventDetails) error {
 		if err := db.Save(&record).Error; err != nil {
 			return fmt.Errorf("failed to update event details: %w", err)
 		}
-		return nil
-	}
-
-	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+	} else if result.Error != gorm.ErrRecordNotFound {
 		return fmt.Errorf("failed to check event details: %w", result.Error)
+	} else if err := db.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to create event details: %w", err)
 	}
 
-	if err := db.Create(&record).Error; err != nil {
-		return fmt.Errorf("failed to create event details: %w", err)
+	if s.config.Scraper.TypedInfoPanels {
+		info := ParseEventInfoPanels(details.EventID, details.InfoPanels)
+		if err := s.SaveEventInfo(info); err != nil {
+			return fmt.Errorf("failed to save event info panels: %w", err)
+		}
 	}
 
-	return nil
+	return touchEventActivity(db, details.EventID)
 }
 
 func marshalJSONString(value interface{}) (string, error) {
@@ -0,0 +1,281 @@
+package scraper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/sanitize"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ParseEventInfoPanels extracts the typed panels (venue, contact, rules,
+// schedule and registration windows) from the raw getInfoPanelsData payload.
+func ParseEventInfoPanels(eventID string, panels map[string]interface{}) *models.EventInfo {
+	info := &models.EventInfo{
+		EventID:             eventID,
+		Schedule:            []models.EventScheduleItem{},
+		RegistrationWindows: []models.EventRegistrationWindow{},
+	}
+	if len(panels) == 0 {
+		return info
+	}
+
+	venue := models.EventVenue{
+		EventID:   eventID,
+		Name:      panelString(panels, "location_name", "venue_name"),
+		Address:   panelString(panels, "location_address", "address"),
+		City:      panelString(panels, "location_city", "city"),
+		Country:   panelString(panels, "location_country_human", "location_country"),
+		Latitude:  panelFloat(panels, "location_lat", "latitude", "lat"),
+		Longitude: panelFloat(panels, "location_lng", "longitude", "lng"),
+	}
+	if venueMap, ok := panelMap(panels, "location", "venue"); ok {
+		fillString(&venue.Name, venueMap, "name")
+		fillString(&venue.Address, venueMap, "address", "description")
+		fillString(&venue.City, venueMap, "city", "locality")
+		fillString(&venue.Country, venueMap, "country_human", "country")
+	}
+	if venue.Name != "" || venue.Address != "" || venue.City != "" {
+		info.Venue = &venue
+	}
+
+	contact := models.EventContact{
+		EventID: eventID,
+		Email:   panelString(panels, "contact_email", "email"),
+		Phone:   panelString(panels, "contact_phone", "phone"),
+		Website: panelString(panels, "website", "homepage"),
+	}
+	for _, key := range []string{"organizer", "contact"} {
+		if contactMap, ok := panelMap(panels, key); ok {
+			fillString(&contact.Name, contactMap, "name")
+			fillString(&contact.Email, contactMap, "email", "contact_email")
+			fillString(&contact.Phone, contactMap, "phone", "contact_phone")
+			fillString(&contact.Website, contactMap, "website", "url", "homepage")
+		}
+	}
+	if contact.Name != "" || contact.Email != "" || contact.Phone != "" || contact.Website != "" {
+		info.Contact = &contact
+	}
+
+	rules := models.EventRules{
+		EventID: eventID,
+		Ruleset: panelString(panels, "ruleset", "ruleset_name"),
+		Text:    panelString(panels, "rules_text"),
+		URL:     panelString(panels, "rules_url"),
+	}
+	if rulesMap, ok := panelMap(panels, "rules"); ok {
+		fillString(&rules.Ruleset, rulesMap, "name", "ruleset")
+		fillString(&rules.Text, rulesMap, "text", "description", "content")
+		fillString(&rules.URL, rulesMap, "url", "link")
+	} else if text := panelString(panels, "rules"); text != "" && rules.Text == "" {
+		rules.Text = text
+	}
+	rules.Text = sanitize.HTML(rules.Text)
+	if rules.Ruleset != "" || rules.Text != "" || rules.URL != "" {
+		info.Rules = &rules
+	}
+
+	for i, item := range panelList(panels, "schedule", "schedules", "program") {
+		entry := models.EventScheduleItem{
+			EventID:     eventID,
+			Position:    i,
+			Date:        panelString(item, "date", "day"),
+			StartTime:   panelString(item, "start", "start_time", "from"),
+			EndTime:     panelString(item, "end", "end_time", "to"),
+			Title:       panelString(item, "title", "name"),
+			Description: sanitize.HTML(panelString(item, "description", "text")),
+		}
+		if entry.Title != "" || entry.Description != "" || entry.StartTime != "" {
+			info.Schedule = append(info.Schedule, entry)
+		}
+	}
+
+	windows := panelList(panels, "registration_periods", "registration_windows", "prices")
+	for _, item := range windows {
+		window := models.EventRegistrationWindow{
+			EventID:  eventID,
+			Name:     panelString(item, "name", "title"),
+			OpensAt:  panelString(item, "start", "opens_at", "from"),
+			ClosesAt: panelString(item, "end", "closes_at", "to", "deadline"),
+			Price:    panelString(item, "price", "amount"),
+			Currency: panelString(item, "currency"),
+		}
+		if window.OpensAt != "" || window.ClosesAt != "" {
+			info.RegistrationWindows = append(info.RegistrationWindows, window)
+		}
+	}
+	if len(windows) == 0 {
+		opens := panelString(panels, "registration_start", "registration_opens")
+		closes := panelString(panels, "registration_end", "registration_closes", "registration_deadline")
+		if opens != "" || closes != "" {
+			info.RegistrationWindows = append(info.RegistrationWindows, models.EventRegistrationWindow{
+				EventID:  eventID,
+				Name:     "Registration",
+				OpensAt:  opens,
+				ClosesAt: closes,
+			})
+		}
+	}
+
+	return info
+}
+
+// SaveEventInfo replaces the typed info panels stored for an event; panels
+// missing from info are deleted.
+func (s *Scraper) SaveEventInfo(info *models.EventInfo) error {
+	if info == nil || info.EventID == "" {
+		return fmt.Errorf("event info requires an event_id")
+	}
+
+	db := config.GetDB()
+	return db.Transaction(func(tx *gorm.DB) error {
+		upsert := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			UpdateAll: true,
+		}
+
+		// A panel no longer on the event page is removed with its row
+		if info.Venue != nil {
+			if err := tx.Clauses(upsert).Create(info.Venue).Error; err != nil {
+				return fmt.Errorf("failed to save event venue: %w", err)
+			}
+		} else if err := tx.Where("event_id = ?", info.EventID).Delete(&models.EventVenue{}).Error; err != nil {
+			return fmt.Errorf("failed to clear event venue: %w", err)
+		}
+		if info.Contact != nil {
+			if err := tx.Clauses(upsert).Create(info.Contact).Error; err != nil {
+				return fmt.Errorf("failed to save event contact: %w", err)
+			}
+		} else if err := tx.Where("event_id = ?", info.EventID).Delete(&models.EventContact{}).Error; err != nil {
+			return fmt.Errorf("failed to clear event contact: %w", err)
+		}
+		if info.Rules != nil {
+			if err := tx.Clauses(upsert).Create(info.Rules).Error; err != nil {
+				return fmt.Errorf("failed to save event rules: %w", err)
+			}
+		} else if err := tx.Where("event_id = ?", info.EventID).Delete(&models.EventRules{}).Error; err != nil {
+			return fmt.Errorf("failed to clear event rules: %w", err)
+		}
+
+		if err := tx.Where("event_id = ?", info.EventID).Delete(&models.EventScheduleItem{}).Error; err != nil {
+			return fmt.Errorf("failed to clear event schedule: %w", err)
+		}
+		if len(info.Schedule) > 0 {
+			if err := tx.Create(&info.Schedule).Error; err != nil {
+				return fmt.Errorf("failed to save event schedule: %w", err)
+			}
+		}
+
+		if err := tx.Where("event_id = ?", info.EventID).Delete(&models.EventRegistrationWindow{}).Error; err != nil {
+			return fmt.Errorf("failed to clear registration windows: %w", err)
+		}
+		if len(info.RegistrationWindows) > 0 {
+			if err := tx.Create(&info.RegistrationWindows).Error; err != nil {
+				return fmt.Errorf("failed to save registration windows: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// LoadEventInfo reads the typed info panels stored for an event.
+func LoadEventInfo(eventID string) (*models.EventInfo, error) {
+	db := config.GetDB()
+	info := &models.EventInfo{EventID: eventID}
+
+	var venue models.EventVenue
+	if err := db.Where("event_id = ?", eventID).First(&venue).Error; err == nil {
+		info.Venue = &venue
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load event venue: %w", err)
+	}
+
+	var contact models.EventContact
+	if err := db.Where("event_id = ?", eventID).First(&contact).Error; err == nil {
+		info.Contact = &contact
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load event contact: %w", err)
+	}
+
+	var rules models.EventRules
+	if err := db.Where("event_id = ?", eventID).First(&rules).Error; err == nil {
+		info.Rules = &rules
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load event rules: %w", err)
+	}
+
+	if err := db.Where("event_id = ?", eventID).Order("position ASC").Find(&info.Schedule).Error; err != nil {
+		return nil, fmt.Errorf("failed to load event schedule: %w", err)
+	}
+	if err := db.Where("event_id = ?", eventID).Order("id ASC").Find(&info.RegistrationWindows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load registration windows: %w", err)
+	}
+
+	return info, nil
+}
+
+func panelString(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch value := data[key].(type) {
+		case string:
+			if trimmed := strings.TrimSpace(value); trimmed != "" {
+				return trimmed
+			}
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+func panelFloat(data map[string]interface{}, keys ...string) float64 {
+	for _, key := range keys {
+		switch value := data[key].(type) {
+		case float64:
+			return value
+		case string:
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				return parsed
+			}
+		}
+	}
+	return 0
+}
+
+func panelMap(data map[string]interface{}, keys ...string) (map[string]interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := data[key].(map[string]interface{}); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func panelList(data map[string]interface{}, keys ...string) []map[string]interface{} {
+	for _, key := range keys {
+		raw, ok := data[key].([]interface{})
+		if !ok {
+			continue
+		}
+		items := make([]map[string]interface{}, 0, len(raw))
+		for _, entry := range raw {
+			if item, ok := entry.(map[string]interface{}); ok {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return nil
+}
+
+func fillString(target *string, data map[string]interface{}, keys ...string) {
+	if *target != "" {
+		return
+	}
+	*target = panelString(data, keys...)
+}
@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxRedirects matches the net/http default
+const maxRedirects = 10
+
+var eventPagePath = regexp.MustCompile(`/event/\d+`)
+
+// trackRedirects makes client remember the last URL it was redirected to.
+// The returned func reports it, "" when the request was not redirected.
+// Targets are read before the transport rewrites hosts, so they keep the
+// smoothcomp.com host even against a TEST_BASE_URL fixture.
+func trackRedirects(client *http.Client) func() string {
+	var target string
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("stopped after 10 redirects")
+		}
+		target = req.URL.String()
+		return nil
+	}
+	return func() string { return target }
+}
+
+// ResolveEventURL returns the canonical URL a former event URL redirects to,
+// or eventURL itself when it is not an alias
+func ResolveEventURL(tx *gorm.DB, eventURL string) string {
+	if eventURL == "" {
+		return ""
+	}
+	var targets []string
+	tx.Model(&models.EventURLAlias{}).Where("url = ?", eventURL).Limit(1).Pluck("event_url", &targets)
+	if len(targets) > 0 && targets[0] != "" {
+		return targets[0]
+	}
+	return eventURL
+}
+
+// recordEventRedirect stores oldURL as an alias of newURL, where the event
+// page now redirects, and moves the stored event and its details to newURL.
+// When both URLs have an event row the one under newURL is kept.
+func recordEventRedirect(eventID, oldURL, newURL string) error {
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		// newURL serves the event again if it redirected before
+		if err := tx.Where("url = ?", newURL).Delete(&models.EventURLAlias{}).Error; err != nil {
+			return fmt.Errorf("error updating event aliases: %w", err)
+		}
+		// Keep chains one hop long
+		if err := tx.Model(&models.EventURLAlias{}).Where("event_url = ?", oldURL).
+			UpdateColumn("event_url", newURL).Error; err != nil {
+			return fmt.Errorf("error updating event aliases: %w", err)
+		}
+		alias := models.EventURLAlias{URL: oldURL}
+		if err := tx.Where(alias).Assign(models.EventURLAlias{EventURL: newURL, EventID: eventID}).
+			FirstOrCreate(&alias).Error; err != nil {
+			return fmt.Errorf("error recording event alias: %w", err)
+		}
+
+		var kept int64
+		if err := tx.Model(&models.Event{}).Where("event_url = ?", newURL).Count(&kept).Error; err != nil {
+			return fmt.Errorf("error finding event: %w", err)
+		}
+		if kept > 0 {
+			if err := tx.Where("event_url = ?", oldURL).Delete(&models.Event{}).Error; err != nil {
+				return fmt.Errorf("error removing moved event: %w", err)
+			}
+		} else if err := tx.Model(&models.Event{}).Where("event_url = ?", oldURL).
+			UpdateColumn("event_url", newURL).Error; err != nil {
+			return fmt.Errorf("error moving event: %w", err)
+		}
+
+		if err := tx.Model(&models.EventDetail{}).Where("event_url = ?", oldURL).
+			UpdateColumn("event_url", newURL).Error; err != nil {
+			return fmt.Errorf("error moving event details: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Event URL redirect recorded",
+		zap.String("event_id", eventID),
+		zap.String("from", oldURL),
+		zap.String("to", newURL))
+	return nil
+}
+
+// followEventRedirect records a redirect of the event page at eventURL to
+// redirected and returns the canonical URL the event now lives at
+func followEventRedirect(eventID, eventURL, redirected string) (string, error) {
+	if redirected == "" || !eventPagePath.MatchString(redirected) {
+		return eventURL, nil
+	}
+	oldURL, newURL := urlnorm.EventURL(eventURL), urlnorm.EventURL(redirected)
+	if oldURL == newURL {
+		return eventURL, nil
+	}
+	if err := recordEventRedirect(eventID, oldURL, newURL); err != nil {
+		return "", err
+	}
+	return newURL, nil
+}
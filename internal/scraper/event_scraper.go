@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,36 +17,98 @@ import (
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-// ScrapeEvents fetches and stores events for the given type and country.
-func (s *Scraper) ScrapeEvents(eventType string, countryCode string) error {
-	job := s.createJob("events_" + eventType)
-
-	events, err := s.ScrapeEventsByCountry(eventType, countryCode)
+// ScrapeEvents fetches and stores events for the given type and country,
+// following each event down to depth. With a max duration the job stops
+// between events once it is exceeded and records where to resume; a
+// cancelled ctx stops it between events too.
+func (s *Scraper) ScrapeEvents(ctx context.Context, eventType string, countryCode string, depth Depth, opts RunOptions) error {
+	job := s.createDepthJob("events_"+eventType, depth.String())
+	job.MaxDuration = int(opts.MaxDuration.Seconds())
+	box := newTimeBox(opts.MaxDuration)
+	ctx, release := trackJob(ctx, job)
+	defer release()
+	s, finish := s.forJob(job, opts)
+	defer finish()
+
+	s.progress.phase("listing", 0)
+	events, err := s.ScrapeEventsByCountry(ctx, eventType, countryCode)
+	if ctx.Err() != nil {
+		s.cancelJob(ctx, job)
+		return context.Cause(ctx)
+	}
 	if err != nil {
 		s.failJob(job, err)
 		return err
 	}
 
+	start := 0
+	if opts.Resume != nil {
+		start = min(opts.Resume.Position, len(events))
+	}
+
+	// Upcoming scrapes report what changed since the previous run
+	var digest *eventDigest
+	if eventType == "upcoming" {
+		digest = newEventDigest(job.ID, eventType)
+	}
+
+	s.progress.phase("events", len(events)-start)
 	savedCount := 0
-	for i := range events {
+	var resume *ResumePoint
+	for i := start; i < len(events); i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		if box.exceeded() {
+			resume = &ResumePoint{
+				JobType:   job.JobType,
+				EventType: eventType,
+				Country:   countryCode,
+				Depth:     depth.String(),
+				Position:  i,
+			}
+			break
+		}
+
+		if digest != nil {
+			digest.observe(events[i])
+		}
 		if err := s.SaveEvent(&events[i]); err != nil {
 			logger.Error("Failed to save event",
 				zap.String("event", events[i].Name),
 				zap.Error(err))
+			s.progress.add(1)
 			continue
 		}
 		savedCount++
+
+		s.crawlEvent(ctx, events[i], depth)
+		s.progress.add(1)
 	}
 
 	job.ItemsScraped = savedCount
+	if ctx.Err() != nil {
+		s.cancelJob(ctx, job)
+		return context.Cause(ctx)
+	}
+
+	if digest != nil {
+		s.publishDigest(digest)
+	}
+	if resume != nil {
+		s.partialJob(job, *resume)
+		return nil
+	}
 	s.completeJob(job)
 
 	logger.Info("Event scraping completed",
 		zap.String("type", eventType),
+		zap.String("depth", depth.String()),
 		zap.Int("saved", savedCount),
 		zap.Int("total", len(events)))
 
@@ -53,14 +116,14 @@ func (s *Scraper) ScrapeEvents(eventType string, countryCode string) error {
 }
 
 // ScrapeEventsByCountry scrapes events from SmoothComp listings.
-func (s *Scraper) ScrapeEventsByCountry(eventType string, countryCode string) ([]models.Event, error) {
+func (s *Scraper) ScrapeEventsByCountry(ctx context.Context, eventType string, countryCode string) ([]models.Event, error) {
 	eventsURL, err := s.buildEventsURL(eventType, countryCode)
 	if err != nil {
 		return nil, err
 	}
 
-	client := &http.Client{Timeout: 20 * time.Second}
-	req, err := http.NewRequest("GET", eventsURL, nil)
+	client := s.newHTTPClient(20 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", eventsURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating events request: %w", err)
 	}
@@ -161,6 +224,8 @@ func (s *Scraper) SaveEvent(event *models.Event) error {
 	db := config.GetDB()
 	var existing models.Event
 
+	event.EventURL = ResolveEventURL(db, urlnorm.EventURL(event.EventURL))
+
 	query := db.Where("event_url = ?", event.EventURL)
 	if event.EventURL == "" && event.ExternalID != "" {
 		query = db.Where("external_id = ?", event.ExternalID)
@@ -170,10 +235,14 @@ func (s *Scraper) SaveEvent(event *models.Event) error {
 	if result.Error == nil {
 		event.ID = existing.ID
 		event.CreatedAt = existing.CreatedAt
+		// Event cards scraped from HTML carry no start date
+		if event.StartDate == nil {
+			event.StartDate = existing.StartDate
+		}
 		if err := db.Save(event).Error; err != nil {
 			return fmt.Errorf("failed to update event: %w", err)
 		}
-		return nil
+		return touchEventActivity(db, event.ExternalID)
 	}
 
 	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
@@ -184,7 +253,7 @@ func (s *Scraper) SaveEvent(event *models.Event) error {
 		return fmt.Errorf("failed to create event: %w", err)
 	}
 
-	return nil
+	return touchEventActivity(db, event.ExternalID)
 }
 
 func normalizeEventURL(baseURL string, href string) string {
@@ -281,6 +350,9 @@ func parseEventsFromScript(body []byte, eventType string) ([]models.Event, error
 		if event.ImageURL == "" {
 			event.ImageURL = strings.TrimSpace(item.CoverImageFallback)
 		}
+		if start, ok := parseEventDate(strings.TrimSpace(item.StartDate)); ok {
+			event.StartDate = &start
+		}
 
 		if item.DaysToStart != nil {
 			if *item.DaysToStart >= 0 {
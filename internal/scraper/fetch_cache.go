@@ -0,0 +1,263 @@
+package scraper
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/metrics"
+)
+
+var (
+	sharedFetchCache     *fetchCache
+	sharedFetchCacheOnce sync.Once
+)
+
+// fetchCache keeps successful responses for FETCH_CACHE_TTL_SECONDS so jobs
+// that overlap, such as an event bundle and a participants scrape of the same
+// event, do not fetch identical pages twice. A request for a page already
+// being fetched waits for that fetch instead of sending its own.
+type fetchCache struct {
+	ttl      time.Duration
+	maxBytes int64
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element // of *cachedResponse, oldest first in order
+	order    *list.List
+	size     int64
+	inflight map[string]*fetchCall
+}
+
+type cachedResponse struct {
+	key     string
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// fetchCall is a fetch other requests for the same page wait on; entry is
+// nil when its response could not be cached
+type fetchCall struct {
+	done  chan struct{}
+	entry *cachedResponse
+}
+
+// getFetchCache returns the process-wide fetch cache, nil when
+// FETCH_CACHE_TTL_SECONDS is 0
+func getFetchCache(cfg *config.Config) *fetchCache {
+	sharedFetchCacheOnce.Do(func() {
+		if cfg.Scraper.FetchCacheTTL <= 0 {
+			return
+		}
+		sharedFetchCache = &fetchCache{
+			ttl:      cfg.Scraper.FetchCacheTTL,
+			maxBytes: cfg.Scraper.FetchCacheMaxBytes,
+			entries:  make(map[string]*list.Element),
+			order:    list.New(),
+			inflight: make(map[string]*fetchCall),
+		}
+	})
+	return sharedFetchCache
+}
+
+type freshFetchKey struct{}
+
+// WithFreshFetch returns a ctx whose requests skip the fetch cache, for
+// resyncs and retries that must see the current page. Their responses
+// still refresh the cache for other jobs.
+func WithFreshFetch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshFetchKey{}, true)
+}
+
+// wantsFresh reports whether req must not be answered from the cache
+func wantsFresh(req *http.Request) bool {
+	fresh, _ := req.Context().Value(freshFetchKey{}).(bool)
+	return fresh || strings.Contains(req.Header.Get("Cache-Control"), "no-cache")
+}
+
+// fetchCacheKey identifies the response of a request, or reports that it
+// should not be cached: only GETs and bodiless POSTs (the participants API)
+// are
+func fetchCacheKey(req *http.Request) (string, bool) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0 {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	if req.Header.Get("Range") != "" || req.Header.Get("Authorization") != "" {
+		return "", false
+	}
+	// JSON and HTML variants of a page are kept apart
+	return strings.Join([]string{
+		req.Method,
+		req.URL.String(),
+		req.Header.Get("Accept"),
+		req.Header.Get("X-Requested-With"),
+	}, "\n"), true
+}
+
+// fetchCacheTransport answers cacheable requests from the fetch cache
+type fetchCacheTransport struct {
+	base  http.RoundTripper
+	cache *fetchCache
+}
+
+func (t *fetchCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, ok := fetchCacheKey(req)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	c := t.cache
+	if wantsFresh(req) {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			var entry *cachedResponse
+			if resp, entry, err = c.capture(key, resp); entry != nil {
+				c.mu.Lock()
+				c.store(entry)
+				c.mu.Unlock()
+			}
+		}
+		return resp, err
+	}
+
+	c.mu.Lock()
+	if entry := c.lookup(key); entry != nil {
+		c.mu.Unlock()
+		metrics.ObserveFetchCache(true)
+		return entry.response(req), nil
+	}
+	if call := c.inflight[key]; call != nil {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if call.entry != nil {
+			metrics.ObserveFetchCache(true)
+			return call.entry.response(req), nil
+		}
+		// The other fetch failed or was not cacheable
+		metrics.ObserveFetchCache(false)
+		return t.base.RoundTrip(req)
+	}
+	call := &fetchCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	metrics.ObserveFetchCache(false)
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusOK {
+		resp, call.entry, err = c.capture(key, resp)
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.entry != nil {
+		c.store(call.entry)
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return resp, err
+}
+
+// capture reads the body of resp to cache it. Bodies over a tenth of the
+// cache are passed through uncached.
+func (c *fetchCache) capture(key string, resp *http.Response) (*http.Response, *cachedResponse, error) {
+	limit := c.maxBytes / 10
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, nil, err
+	}
+	if int64(len(body)) > limit {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	// Cookies belong to the session that fetched the page
+	header.Del("Set-Cookie")
+	return resp, &cachedResponse{
+		key:     key,
+		status:  resp.StatusCode,
+		header:  header,
+		body:    body,
+		expires: time.Now().Add(c.ttl),
+	}, nil
+}
+
+// lookup returns the live entry for key, dropping it when expired. The
+// caller holds c.mu.
+func (c *fetchCache) lookup(key string) *cachedResponse {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*cachedResponse)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil
+	}
+	return entry
+}
+
+// store adds entry, dropping expired entries and then the oldest ones until
+// the bodies fit in maxBytes. The caller holds c.mu.
+func (c *fetchCache) store(entry *cachedResponse) {
+	if element, ok := c.entries[entry.key]; ok {
+		c.remove(element)
+	}
+	c.entries[entry.key] = c.order.PushBack(entry)
+	c.size += int64(len(entry.body))
+
+	now := time.Now()
+	for front := c.order.Front(); front != nil && front != c.order.Back(); front = c.order.Front() {
+		// Entries share the TTL, so the oldest expire first
+		if c.size <= c.maxBytes && !now.After(front.Value.(*cachedResponse).expires) {
+			break
+		}
+		c.remove(front)
+	}
+}
+
+func (c *fetchCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*cachedResponse)
+	delete(c.entries, entry.key)
+	c.size -= int64(len(entry.body))
+}
+
+// response rebuilds the cached response for req
+func (e *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
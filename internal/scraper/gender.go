@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// NormalizeStoredGenders rewrites free-form athlete genders into the
+// models.Gender values and fills the gender/kids flags of registrations
+// stored before they were parsed. It is idempotent and safe to run on every
+// startup.
+func NormalizeStoredGenders() error {
+	db := config.GetDB()
+
+	var genders []string
+	if err := db.Model(&models.Athlete{}).Distinct().
+		Where("gender NOT IN ?", []models.Gender{models.GenderUnknown, models.GenderMale, models.GenderFemale, models.GenderMixed}).
+		Pluck("gender", &genders).Error; err != nil {
+		return err
+	}
+
+	athletes := int64(0)
+	for _, raw := range genders {
+		gender, _ := models.ParseGender(raw)
+		result := db.Model(&models.Athlete{}).Where("gender = ?", raw).Update("gender", gender)
+		if result.Error != nil {
+			return result.Error
+		}
+		athletes += result.RowsAffected
+	}
+
+	type categoryRow struct {
+		Division    string
+		AgeCategory string
+	}
+	var categories []categoryRow
+	if err := db.Model(&models.EventRegistration{}).Distinct("division", "age_category").
+		Where("gender = '' OR gender IS NULL").
+		Scan(&categories).Error; err != nil {
+		return err
+	}
+
+	registrations := int64(0)
+	for _, category := range categories {
+		gender, kids := models.ParseGender(category.Division)
+		if !kids {
+			kids = models.IsKidsCategory(category.AgeCategory)
+		}
+		if gender == models.GenderUnknown && !kids {
+			continue
+		}
+		result := db.Model(&models.EventRegistration{}).
+			Where("division = ? AND age_category = ? AND (gender = '' OR gender IS NULL)", category.Division, category.AgeCategory).
+			Updates(map[string]interface{}{"gender": gender, "is_kids": kids})
+		if result.Error != nil {
+			return result.Error
+		}
+		registrations += result.RowsAffected
+	}
+
+	if athletes+registrations > 0 {
+		logger.Info("Stored genders normalized",
+			zap.Int64("athletes", athletes),
+			zap.Int64("registrations", registrations))
+	}
+
+	return nil
+}
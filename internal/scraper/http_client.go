@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/fixtures"
+	"github.com/kmicac/smoothcomp-scraper/internal/maintenance"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	sharedTransport     http.RoundTripper
+	sharedTransportOnce sync.Once
+
+	proxyTransport     http.RoundTripper
+	proxyTransportOnce sync.Once
+
+	connectionsCreated atomic.Int64
+	connectionsReused  atomic.Int64
+)
+
+// ConnectionStats reports how many outbound connections were opened versus
+// reused from the shared pool since startup
+type ConnectionStats struct {
+	Created int64 `json:"created"`
+	Reused  int64 `json:"reused"`
+}
+
+// GetConnectionStats returns the connection reuse counters
+func GetConnectionStats() ConnectionStats {
+	return ConnectionStats{
+		Created: connectionsCreated.Load(),
+		Reused:  connectionsReused.Load(),
+	}
+}
+
+// newHTTPClient returns the client used for raw (non-colly) requests
+func (s *Scraper) newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: s.transport,
+	}
+}
+
+// getSharedTransport returns the process-wide round tripper so every Scraper
+// instance (API handler, scheduler) draws from the same connection pool
+func getSharedTransport(cfg *config.Config) http.RoundTripper {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = newTransport(cfg, nil)
+	})
+	return sharedTransport
+}
+
+// getProxyTransport returns the round tripper that sends requests through
+// SCRAPER_PROXY_URL, for behavior profiles with UseProxy; nil when unset
+func getProxyTransport(cfg *config.Config) http.RoundTripper {
+	proxyTransportOnce.Do(func() {
+		if cfg.Scraper.ProxyURL == "" {
+			return
+		}
+		proxyURL, err := url.Parse(cfg.Scraper.ProxyURL)
+		if err != nil {
+			return
+		}
+		proxyTransport = newTransport(cfg, http.ProxyURL(proxyURL))
+	})
+	return proxyTransport
+}
+
+// newTransport builds the round tripper shared by colly and raw requests.
+// Requests outside the allowlist are refused (see allowlistTransport). With
+// SCRAPER_PROXIES and no explicit proxy, every request goes through a proxy
+// picked from the rotation pool (see proxyRotationTransport). When
+// TEST_BASE_URL is set, every smoothcomp.com request is rewritten to it.
+// Maintenance pages stop the job that requested them (see
+// maintenanceTransport), and pages fetched moments ago are served from the
+// fetch cache (see fetchCacheTransport). A nil proxy uses the proxy of the
+// environment.
+func newTransport(cfg *config.Config, proxy func(*http.Request) (*url.URL, error)) http.RoundTripper {
+	var pool *proxyPool
+	if proxy == nil {
+		pool = getProxyPool(cfg)
+		proxy = proxyFromContext(http.ProxyFromEnvironment)
+	}
+	base := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.Scraper.HTTPMaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.Scraper.HTTPMaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.Scraper.HTTPIdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{ClientSessionCache: tls.NewLRUClientSessionCache(64)},
+	}
+
+	var transport http.RoundTripper = &tracingTransport{base: base}
+	if pool != nil {
+		transport = &proxyRotationTransport{base: transport, pool: pool}
+	}
+
+	if cfg.Scraper.TestBaseURL != "" {
+		if target, err := url.Parse(cfg.Scraper.TestBaseURL); err == nil && target.Host != "" {
+			transport = &rewriteTransport{base: transport, target: target}
+		}
+	}
+
+	transport = &maintenanceTransport{base: transport}
+	if cache := getFetchCache(cfg); cache != nil {
+		transport = &fetchCacheTransport{base: transport, cache: cache}
+	}
+	return &allowlistTransport{base: transport, config: cfg}
+}
+
+// tracingTransport counts new versus reused connections
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				connectionsReused.Add(1)
+			} else {
+				connectionsCreated.Add(1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	return t.base.RoundTrip(req)
+}
+
+// rewriteTransport redirects smoothcomp.com traffic to a fixture server
+type rewriteTransport struct {
+	base   http.RoundTripper
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Hostname())
+	if host != "smoothcomp.com" && !strings.HasSuffix(host, ".smoothcomp.com") {
+		return t.base.RoundTrip(req)
+	}
+
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = t.target.Scheme
+	rewritten.URL.Host = t.target.Host
+	rewritten.Host = t.target.Host
+	rewritten.Header.Set(fixtures.OriginalHostHeader, host)
+
+	return t.base.RoundTrip(rewritten)
+}
+
+// maintenanceTransport turns Smoothcomp maintenance pages into ErrActive and
+// stops the job of the request, which is then postponed
+type maintenanceTransport struct {
+	base http.RoundTripper
+}
+
+func (t *maintenanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !maintenance.Detect(resp) {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	logger.Warn("Smoothcomp maintenance page, stopping job", zap.String("url", req.URL.String()))
+	maintenance.Signal(req.Context())
+	return nil, fmt.Errorf("%w (%s)", maintenance.ErrActive, req.URL.Host)
+}
@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/milestones"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// milestoneLabels name the milestone kinds in notification titles
+var milestoneLabels = map[string]string{
+	models.MilestoneWins:             "wins",
+	models.MilestoneWinStreak:        "win streak",
+	models.MilestoneSubmissionStreak: "submission win streak",
+}
+
+// detectMilestones refreshes the streaks of the athletes of saved matches
+// and notifies the milestones watched athletes reached in those matches.
+// Milestones reached in matches scraped earlier are recorded silently.
+func (s *Scraper) detectMilestones(athletes map[uint]bool, matchIDs map[int]bool) {
+	if len(athletes) == 0 {
+		return
+	}
+	athleteIDs := make([]uint, 0, len(athletes))
+	for athleteID := range athletes {
+		athleteIDs = append(athleteIDs, athleteID)
+	}
+	slices.Sort(athleteIDs)
+
+	reached, err := milestones.Update(athleteIDs)
+	if err != nil {
+		logger.Error("Failed to update athlete streaks", zap.Error(err))
+	}
+
+	watched := make(map[uint]*models.Athlete)
+	for _, milestone := range reached {
+		if !matchIDs[milestone.MatchID] {
+			continue
+		}
+		athlete, ok := watched[milestone.AthleteID]
+		if !ok {
+			athlete = s.watchedAthlete(milestone.AthleteID)
+			watched[milestone.AthleteID] = athlete
+		}
+		if athlete == nil {
+			continue
+		}
+
+		logger.Info("Athlete milestone reached",
+			zap.String("athlete_id", athlete.ExternalID),
+			zap.String("kind", milestone.Kind),
+			zap.Int("value", milestone.Value))
+		s.notifier.Publish(notify.Notification{
+			Kind:  "athlete_milestone",
+			Title: fmt.Sprintf("%s: %d %s", athlete.FullName, milestone.Value, milestoneLabels[milestone.Kind]),
+			Text: fmt.Sprintf("%s reached %d %s at %s (current win streak %d, longest submission streak %d)",
+				athlete.FullName, milestone.Value, milestoneLabels[milestone.Kind], milestone.EventName,
+				milestone.Streak.CurrentWinStreak, milestone.Streak.LongestSubmissionStreak),
+			Data: map[string]interface{}{
+				"athlete_id":          athlete.ID,
+				"athlete_external_id": athlete.ExternalID,
+				"athlete_name":        athlete.FullName,
+				"milestone":           milestone,
+			},
+		})
+	}
+}
+
+// watchedAthlete returns the athlete when it carries the NOTIFY_WATCH_TAG
+// tag, nil otherwise
+func (s *Scraper) watchedAthlete(athleteID uint) *models.Athlete {
+	return WatchedAthlete(s.config, athleteID)
+}
+
+// WatchedAthlete returns the athlete when it carries the NOTIFY_WATCH_TAG
+// tag, nil otherwise
+func WatchedAthlete(cfg *config.Config, athleteID uint) *models.Athlete {
+	tag := models.NormalizeTag(cfg.Notifications.WatchTag)
+	if tag == "" || athleteID == 0 {
+		return nil
+	}
+
+	db := config.GetDB()
+	var athlete models.Athlete
+	if err := db.First(&athlete, athleteID).Error; err != nil {
+		return nil
+	}
+	var tagged int64
+	db.Model(&models.EntityTag{}).
+		Where("entity_type = ? AND external_id = ? AND tag = ?", models.TaggedAthlete, athlete.ExternalID, tag).
+		Count(&tagged)
+	if tagged == 0 {
+		return nil
+	}
+	return &athlete
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/names"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RederiveAthleteNames recomputes first and last names of stored athletes
+// from their full name with names.Parse, fixing surnames with particles
+// ("de la Cruz") that were split apart. The full name is never changed. It is
+// idempotent and safe to run on every startup.
+func RederiveAthleteNames() error {
+	db := config.GetDB()
+	updated := 0
+
+	var batch []models.Athlete
+	err := db.Select("id", "first_name", "last_name", "full_name").
+		Where("full_name <> ''").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, athlete := range batch {
+				first, last := names.Parse(athlete.FullName, athlete.FirstName, athlete.LastName)
+				if first == athlete.FirstName && last == athlete.LastName {
+					continue
+				}
+				if err := db.Model(&models.Athlete{}).Where("id = ?", athlete.ID).
+					UpdateColumns(map[string]interface{}{"first_name": first, "last_name": last}).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	if updated > 0 {
+		logger.Info("Athlete first/last names re-derived", zap.Int("athletes", updated))
+	}
+	return nil
+}
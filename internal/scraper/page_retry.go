@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrPageNotFound is returned when a page answers 404 or 410; retrying it
+// will not help
+var ErrPageNotFound = errors.New("page not found")
+
+// visitPage visits pageURL with c, retrying 429, 5xx and network errors up
+// to SCRAPER_PAGE_RETRIES times with a growing backoff. A page that is gone
+// returns ErrPageNotFound right away, and a cancelled ctx stops retrying. c
+// must not have been used to visit another page.
+func (s *Scraper) visitPage(ctx context.Context, c *colly.Collector, pageURL string) error {
+	var failed *colly.Response
+	c.OnError(func(r *colly.Response, _ error) {
+		failed = r
+	})
+
+	err := c.Visit(pageURL)
+	for attempt := 1; err != nil && failed != nil; attempt++ {
+		status := failed.StatusCode
+		if status == http.StatusNotFound || status == http.StatusGone {
+			return fmt.Errorf("%w: %s (status %d)", ErrPageNotFound, pageURL, status)
+		}
+		if !retryablePage(status) || attempt > s.config.Scraper.PageRetries {
+			break
+		}
+
+		wait := s.config.Scraper.PageRetryBackoff * time.Duration(attempt)
+		logger.Debug("Retrying page",
+			zap.String("url", pageURL),
+			zap.Int("status", status),
+			zap.Int("attempt", attempt),
+			zap.Duration("wait", wait))
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+
+		retry := failed.Request
+		failed = nil
+		err = retry.Retry()
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	if err != nil && failed != nil {
+		return fmt.Errorf("%s (status %d): %w", pageURL, failed.StatusCode, err)
+	}
+	return err
+}
+
+// retryablePage reports whether a failed page may load on a later attempt:
+// throttling, server errors and network errors (status 0)
+func retryablePage(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxParticipantPages caps the pages read from the participants API, far
+// above the largest events (a few thousand competitors)
+const maxParticipantPages = 200
+
+// participantsPage is one response of the participants API. Large events
+// are paginated with Laravel fields next to the participants; small ones
+// leave them empty.
+type participantsPage struct {
+	SmoothCompAPIResponse
+	CurrentPage int    `json:"current_page"`
+	LastPage    int    `json:"last_page"`
+	NextPageURL string `json:"next_page_url"`
+	Total       int    `json:"total"` // registrations of the whole event
+}
+
+// participantSet merges participant pages, keeping each division once and
+// skipping registrations already read
+type participantSet struct {
+	response   SmoothCompAPIResponse
+	divisions  map[string]int // division key -> index in response.Participants
+	seen       map[string]bool
+	categories map[int64]bool
+}
+
+func newParticipantSet() *participantSet {
+	return &participantSet{
+		divisions:  make(map[string]int),
+		seen:       make(map[string]bool),
+		categories: make(map[int64]bool),
+	}
+}
+
+// add merges the divisions of a page and returns how many registrations
+// were new. Divisions without an ID (HTML pages) are matched by name.
+func (p *participantSet) add(participants []Participant) int {
+	added := 0
+	for _, participant := range participants {
+		registrations := participant.Registrations
+		participant.Registrations = nil
+
+		division := strconv.FormatInt(participant.ID, 10)
+		if participant.ID == 0 {
+			division = "name:" + participant.Name
+		}
+		index, ok := p.divisions[division]
+		if !ok {
+			index = len(p.response.Participants)
+			p.divisions[division] = index
+			p.response.Participants = append(p.response.Participants, participant)
+		}
+
+		for _, reg := range registrations {
+			key := fmt.Sprintf("%d/%d", reg.ID, reg.UserID)
+			if reg.ID == 0 {
+				key = division + "/" + strconv.FormatInt(reg.UserID, 10)
+			}
+			if p.seen[key] {
+				continue
+			}
+			p.seen[key] = true
+			p.response.Participants[index].Registrations = append(p.response.Participants[index].Registrations, reg)
+			added++
+		}
+	}
+	return added
+}
+
+func (p *participantSet) addCategories(categories []Category) {
+	for _, category := range categories {
+		if !p.categories[category.ID] {
+			p.categories[category.ID] = true
+			p.response.Categories = append(p.response.Categories, category)
+		}
+	}
+}
+
+// fetchParticipants reads every page of the participants API of an event
+// and merges them. complete is false when a later page failed, in which case
+// the pages read so far are returned with the error.
+func (s *Scraper) fetchParticipants(ctx context.Context, client *http.Client, apiURL string) (response *SmoothCompAPIResponse, complete bool, err error) {
+	set := newParticipantSet()
+
+	pageURL := apiURL
+	for page := 1; page <= maxParticipantPages; page++ {
+		current, err := s.fetchParticipantsPage(ctx, client, pageURL)
+		if err != nil {
+			if page == 1 {
+				return nil, false, err
+			}
+			return &set.response, false, fmt.Errorf("error reading participants page %d: %w", page, err)
+		}
+
+		added := set.add(current.Participants)
+		set.addCategories(current.Categories)
+
+		next := current.NextPageURL
+		more := next != "" || current.LastPage > page || (current.Total > len(set.seen) && added > 0)
+		// A page without new registrations means the API ignores the page
+		// parameter or ran out of competitors
+		if !more || (page > 1 && added == 0) {
+			return &set.response, true, nil
+		}
+
+		if next == "" {
+			next = withPage(apiURL, page+1)
+		}
+		pageURL = next
+		logger.Debug("Participants page read",
+			zap.Int("page", page),
+			zap.Int("last_page", current.LastPage),
+			zap.Int("registrations", len(set.seen)),
+			zap.Int("total", current.Total))
+	}
+
+	logger.Warn("Participants pagination stopped at the page limit",
+		zap.String("url", apiURL),
+		zap.Int("pages", maxParticipantPages))
+	return &set.response, false, nil
+}
+
+// fetchParticipantsPage requests one page of the participants API
+func (s *Scraper) fetchParticipantsPage(ctx context.Context, client *http.Client, pageURL string) (*participantsPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando request: %w", err)
+	}
+
+	// Headers importantes
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error haciendo request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("API retornó status %d: %w", resp.StatusCode, errEventHostNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API retornó status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo response: %w", err)
+	}
+	logger.Debug("Response recibido", zap.String("url", pageURL), zap.Int("bytes", len(bodyBytes)))
+
+	var page participantsPage
+	if err := json.Unmarshal(bodyBytes, &page); err != nil {
+		return nil, fmt.Errorf("error parseando JSON: %w", err)
+	}
+	return &page, nil
+}
+
+// withPage sets the page query parameter of rawURL
+func withPage(rawURL string, page int) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := parsed.Query()
+	query.Set("page", strconv.Itoa(page))
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
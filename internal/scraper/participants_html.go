@@ -0,0 +1,135 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/pkg/names"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+)
+
+const (
+	participantBlockSelector = ".participants-category, .participant-category, .category-participants, .division-participants"
+	participantRowSelector   = ".participant, .registration, li, tr"
+)
+
+var flagCodePattern = regexp.MustCompile(`flag-icon-([a-z]{2})`)
+
+// scrapeParticipantsHTML reads the participants page of an event, used when
+// the JSON API fails. Each division block has a title and one row per
+// competitor linking their profile; further pages are followed through
+// rel="next". Only the fields shown on the page are filled.
+func (s *Scraper) scrapeParticipantsHTML(ctx context.Context, pageURL string) (*SmoothCompAPIResponse, error) {
+	set := newParticipantSet()
+	firstURL := pageURL
+
+	c := s.collector.Clone()
+	c.Context = ctx
+
+	c.OnHTML("body", func(e *colly.HTMLElement) {
+		set.add(parseParticipantBlocks(e.DOM))
+	})
+
+	var next []string
+	c.OnHTML("a[rel='next']", func(e *colly.HTMLElement) {
+		if href := e.Request.AbsoluteURL(e.Attr("href")); href != "" {
+			next = append(next, href)
+		}
+	})
+
+	visited := map[string]bool{}
+	for pageURL != "" && !visited[pageURL] && len(visited) < maxParticipantPages {
+		visited[pageURL] = true
+		next = next[:0]
+		if err := s.visitPage(ctx, c, pageURL); err != nil {
+			if len(visited) == 1 {
+				return nil, err
+			}
+			return &set.response, err
+		}
+		c.Wait()
+
+		pageURL = ""
+		if len(next) > 0 {
+			pageURL = next[0]
+		}
+	}
+
+	if len(set.seen) == 0 {
+		return nil, fmt.Errorf("no participants found on %s", firstURL)
+	}
+	return &set.response, nil
+}
+
+// parseParticipantBlocks reads the division blocks of a participants page
+func parseParticipantBlocks(page *goquery.Selection) []Participant {
+	var participants []Participant
+
+	page.Find(participantBlockSelector).Each(func(_ int, block *goquery.Selection) {
+		participant := Participant{
+			Name: strings.Join(strings.Fields(block.Find(".category-name, .title, h2, h3, h4").First().Text()), " "),
+		}
+		for _, attr := range []string{"data-id", "data-category-id", "data-bracket-id"} {
+			if id, err := strconv.ParseInt(block.AttrOr(attr, ""), 10, 64); err == nil {
+				participant.ID = id
+				break
+			}
+		}
+
+		block.Find(participantRowSelector).Each(func(_ int, row *goquery.Selection) {
+			if row.Find(participantRowSelector).Length() > 0 {
+				return // a wrapper; its inner rows are visited on their own
+			}
+			if reg, ok := parseParticipantRow(row); ok {
+				participant.Registrations = append(participant.Registrations, reg)
+			}
+		})
+
+		if participant.Name != "" && len(participant.Registrations) > 0 {
+			participants = append(participants, participant)
+		}
+	})
+
+	return participants
+}
+
+// parseParticipantRow reads a competitor row: profile link and name, club
+// and country flag
+func parseParticipantRow(row *goquery.Selection) (Registration, bool) {
+	profile := row.Find("a[href*='/profile/']").First()
+	href, ok := profile.Attr("href")
+	if !ok {
+		return Registration{}, false
+	}
+	userID, err := strconv.ParseInt(ExtractIDFromURL(urlnorm.ProfileURL(href)), 10, 64)
+	if err != nil {
+		return Registration{}, false
+	}
+	fullName := strings.Join(strings.Fields(profile.Text()), " ")
+	if fullName == "" {
+		return Registration{}, false
+	}
+
+	reg := Registration{UserID: userID}
+	reg.FirstName, reg.LastName = names.Parse(fullName, "", "")
+	reg.ID, _ = strconv.ParseInt(row.AttrOr("data-registration-id", ""), 10, 64)
+
+	if club := row.Find("a[href*='/club/']").First(); club.Length() > 0 {
+		reg.ClubName = strings.Join(strings.Fields(club.Text()), " ")
+		if m := clubIDPattern.FindStringSubmatch(club.AttrOr("href", "")); m != nil {
+			reg.ClubID, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+	}
+	if m := flagCodePattern.FindStringSubmatch(row.Find(".flag-icon").First().AttrOr("class", "")); m != nil {
+		reg.CountryCode = m[1]
+	}
+	if image, ok := row.Find("img").First().Attr("src"); ok {
+		reg.ProfileImage = image
+	}
+	return reg, true
+}
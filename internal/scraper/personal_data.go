@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RemovePersonalData scrubs the personal fields of an athlete and of the
+// matches, results, rosters and rankings naming it, keeping IDs and
+// statistics, and adds it to the suppression list so later scrapes do not
+// store them again. updated_at moves forward so conditional requests do not
+// keep answering 304 with the removed name.
+func RemovePersonalData(athlete models.Athlete, reason string, actor string) (*models.SuppressedAthlete, error) {
+	entry := models.SuppressedAthlete{
+		ExternalID: athlete.ExternalID,
+		AthleteID:  athlete.ID,
+		Reason:     reason,
+		Actor:      actor,
+	}
+
+	now := time.Now()
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		// The slug is replaced by the nameless n-a-<id> one
+		slugValue := ""
+		err := saveSlugged(tx, &models.Athlete{}, "", &slugValue, &athlete.ID, func(tx *gorm.DB) error {
+			return tx.Model(&models.Athlete{}).Where("id = ?", athlete.ID).
+				UpdateColumns(map[string]interface{}{
+					"first_name":  "",
+					"last_name":   "",
+					"full_name":   "",
+					"slug":        slugValue,
+					"profile_url": "",
+					"image_url":   "",
+					"avatar_url":  "",
+					"birth_year":  0,
+					"age":         0,
+					"updated_at":  now,
+				}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("error scrubbing athlete: %w", err)
+		}
+
+		for _, side := range []string{"athlete_a", "athlete_b", "winner"} {
+			if err := tx.Model(&models.Match{}).Where(side+"_id = ?", athlete.ID).
+				UpdateColumns(map[string]interface{}{side + "_name": "", "updated_at": now}).Error; err != nil {
+				return fmt.Errorf("error scrubbing matches: %w", err)
+			}
+		}
+
+		if err := tx.Model(&models.EventResult{}).Where("athlete_id = ?", athlete.ID).
+			UpdateColumn("athlete_name", "").Error; err != nil {
+			return fmt.Errorf("error scrubbing results: %w", err)
+		}
+
+		for _, side := range []string{"athlete_a", "athlete_b"} {
+			if err := tx.Model(&models.LiveScore{}).Where(side+"_id = ?", athlete.ID).
+				UpdateColumn(side+"_name", "").Error; err != nil {
+				return fmt.Errorf("error scrubbing live scores: %w", err)
+			}
+		}
+
+		if err := tx.Model(&models.CountryRosterEntry{}).Where("athlete_id = ?", athlete.ID).
+			UpdateColumn("full_name", "").Error; err != nil {
+			return fmt.Errorf("error scrubbing country rosters: %w", err)
+		}
+
+		if err := tx.Model(&models.RankingEntry{}).Where("athlete_id = ?", athlete.ID).
+			UpdateColumn("athlete_name", "").Error; err != nil {
+			return fmt.Errorf("error scrubbing rankings: %w", err)
+		}
+
+		if err := tx.Where("entity_type = ? AND external_id = ?", models.TaggedAthlete, athlete.ExternalID).
+			Delete(&models.EntityTag{}).Error; err != nil {
+			return fmt.Errorf("error removing tags: %w", err)
+		}
+
+		if err := tx.Where("athlete_external_id = ?", athlete.ExternalID).
+			Delete(&models.FederationID{}).Error; err != nil {
+			return fmt.Errorf("error removing federation IDs: %w", err)
+		}
+
+		return tx.Where(models.SuppressedAthlete{ExternalID: athlete.ExternalID}).
+			Attrs(entry).FirstOrCreate(&entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Athlete personal data removed",
+		zap.Int("athlete_id", athlete.ID),
+		zap.String("external_id", athlete.ExternalID),
+		zap.String("actor", actor))
+
+	return &entry, nil
+}
+
+// isSuppressed reports whether the athlete's personal data was removed on request
+func isSuppressed(tx *gorm.DB, externalID string) bool {
+	var count int64
+	tx.Model(&models.SuppressedAthlete{}).Where("external_id = ?", externalID).Count(&count)
+	return count > 0
+}
+
+// scrubPersonalData clears the fields RemovePersonalData removes; the slug,
+// derived from the name, is left for the caller to regenerate
+func scrubPersonalData(athlete *models.Athlete) {
+	athlete.FirstName = ""
+	athlete.LastName = ""
+	athlete.FullName = ""
+	athlete.ProfileURL = ""
+	athlete.ImageURL = ""
+	athlete.AvatarURL = ""
+	athlete.BirthYear = 0
+	athlete.Age = 0
+}
@@ -0,0 +1,210 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/pipeline"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// fullPipeline builds the stages run by ScrapeAll. Each stage reads what the
+// previous ones stored during the run, so any of them can be retried or
+// resumed on its own.
+func (s *Scraper) fullPipeline() *pipeline.Pipeline {
+	return &pipeline.Pipeline{
+		Name:    "all",
+		Retries: s.config.Scraper.PipelineStageRetries,
+		Backoff: s.config.Scraper.PipelineRetryBackoff,
+		Stages: []pipeline.Stage{
+			{Name: "discover", Run: s.stageDiscover},
+			{Name: "details", Run: s.stageDetails},
+			{Name: "participants", Run: s.stageParticipants},
+			{Name: "enrich", Run: s.stageEnrich},
+			{Name: "aggregates", Run: s.stageAggregates},
+		},
+	}
+}
+
+// stageDiscover stores the academies and the past and upcoming event
+// listings of every target country
+func (s *Scraper) stageDiscover(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	academies, failed, failedPages := s.discoverAcademies(ctx)
+	attempts := len(s.config.Scraper.TargetCountries)
+
+	events := 0
+	s.progress.phase("listings", 2*len(s.config.Scraper.TargetCountries))
+	for _, countryCode := range s.config.Scraper.TargetCountries {
+		for _, eventType := range []string{"upcoming", "past"} {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			attempts++
+			listing, err := s.ScrapeEventsByCountry(ctx, eventType, countryCode)
+			s.progress.add(1)
+			if err != nil {
+				logger.Error("Failed to discover events",
+					zap.String("country", countryCode),
+					zap.String("type", eventType),
+					zap.Error(err))
+				failed++
+				continue
+			}
+			for i := range listing {
+				if err := s.SaveEvent(&listing[i]); err != nil {
+					logger.Error("Failed to save event", zap.String("event", listing[i].Name), zap.Error(err))
+					continue
+				}
+				events++
+			}
+		}
+	}
+
+	job.ItemsScraped = academies + events
+	job.PagesFailed = failedPages
+	if attempts > 0 && failed == attempts {
+		return fmt.Errorf("all %d listings failed", attempts)
+	}
+	return nil
+}
+
+// stageDetails fetches the event page of the events selected for this run
+func (s *Scraper) stageDetails(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	events, err := runEvents(run.Since)
+	if err != nil {
+		return err
+	}
+
+	return s.eachEvent(ctx, events, job, func(event models.Event) error {
+		details, err := s.FetchEventDetails(ctx, event.ExternalID, event.EventURL)
+		if err != nil {
+			return err
+		}
+		return s.SaveEventDetails(details)
+	})
+}
+
+// stageParticipants stores the registrations of the events selected for this run
+func (s *Scraper) stageParticipants(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	events, err := runEvents(run.Since)
+	if err != nil {
+		return err
+	}
+
+	return s.eachEvent(ctx, events, job, func(event models.Event) error {
+		return s.ScrapeEventAthletes(ctx, event.ExternalID, event.Name, event.EventURL)
+	})
+}
+
+// stageEnrich refreshes the profiles of athletes registered in this run's
+// events that were never enriched or are older than the stale profile age
+func (s *Scraper) stageEnrich(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	db := config.GetDB()
+
+	events, err := runEvents(run.Since)
+	if err != nil {
+		return err
+	}
+	eventIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ExternalID)
+	}
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	staleBefore := time.Now().Add(-s.config.Scheduler.StaleProfileAge)
+	var athletes []models.Athlete
+	err = db.Where("id IN (?)", db.Model(&models.EventRegistration{}).Select("athlete_id").Where("event_id IN ?", eventIDs)).
+		Where("profile_scraped_at IS NULL OR profile_scraped_at < ?", staleBefore).
+		Order("id ASC").
+		Find(&athletes).Error
+	if err != nil {
+		return fmt.Errorf("error loading athletes: %w", err)
+	}
+
+	s.progress.phase("profiles", len(athletes))
+	job.ItemsScraped, _ = s.scrapeProfiles(ctx, athletes, timeBox{})
+	return context.Cause(ctx)
+}
+
+// stageAggregates recomputes data derived from what the run stored
+func (s *Scraper) stageAggregates(ctx context.Context, run *pipeline.Run, job *models.ScrapeJob) error {
+	return RecomputeAggregates()
+}
+
+// RecomputeAggregates rebuilds derived data after a large ingest: normalized
+// division genders and weight classes.
+func RecomputeAggregates() error {
+	if err := NormalizeStoredGenders(); err != nil {
+		return fmt.Errorf("error normalizing genders: %w", err)
+	}
+	if err := NormalizeStoredWeightClasses(); err != nil {
+		return fmt.Errorf("error normalizing weight classes: %w", err)
+	}
+	return nil
+}
+
+// runEvents selects the events a pipeline run follows: those listed during
+// the run, except past events whose details were stored by an earlier run.
+// The selection does not change as later stages write, so stages can be
+// retried or resumed.
+func runEvents(since time.Time) ([]models.Event, error) {
+	db := config.GetDB()
+
+	var events []models.Event
+	err := db.Where("scraped_at >= ? AND external_id <> ''", since).
+		Where("external_id NOT IN (?)", blockedIDs(db, models.BlockedEvent)).
+		Where("event_type = ? OR external_id NOT IN (?)", "upcoming",
+			db.Model(&models.EventDetail{}).Select("event_id").Where("scraped_at < ?", since)).
+		Order("id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading run events: %w", err)
+	}
+	return events, nil
+}
+
+// eachEvent applies fn to every event, failing only when all of them fail
+// or ctx is cancelled
+func (s *Scraper) eachEvent(ctx context.Context, events []models.Event, job *models.ScrapeJob, fn func(event models.Event) error) error {
+	s.progress.phase("events", len(events))
+	var lastErr error
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		err := fn(event)
+		s.progress.add(1)
+		if err != nil {
+			logger.Error("Pipeline stage failed for event",
+				zap.String("job_type", job.JobType),
+				zap.String("event_id", event.ExternalID),
+				zap.Error(err))
+			lastErr = err
+			continue
+		}
+		job.ItemsScraped++
+	}
+
+	if len(events) > 0 && job.ItemsScraped == 0 {
+		return fmt.Errorf("all %d events failed: %w", len(events), lastErr)
+	}
+	return nil
+}
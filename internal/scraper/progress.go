@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+)
+
+// progressInterval limits how often progress is written while items advance
+const progressInterval = 2 * time.Second
+
+// jobProgress records the phase of a running job and how many of its items
+// are done, publishing each write on the bus. Profile workers report
+// concurrently, so writes are throttled and serialized. A nil jobProgress
+// ignores every call.
+type jobProgress struct {
+	mu    sync.Mutex
+	job   *models.ScrapeJob
+	saved time.Time
+}
+
+func newJobProgress(job *models.ScrapeJob) *jobProgress {
+	return &jobProgress{job: job}
+}
+
+// phase starts a phase of total items (0 when unknown) and saves it right away
+func (p *jobProgress) phase(name string, total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.job.CurrentPhase = name
+	p.job.ItemsTotal = total
+	p.job.ItemsProcessed = 0
+	p.save()
+}
+
+// add counts n more items of the current phase as done
+func (p *jobProgress) add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.job.ItemsProcessed += n
+	if time.Since(p.saved) >= progressInterval || (p.job.ItemsTotal > 0 && p.job.ItemsProcessed >= p.job.ItemsTotal) {
+		p.save()
+	}
+}
+
+// flush saves progress not written yet
+func (p *jobProgress) flush() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.save()
+}
+
+func (p *jobProgress) save() {
+	p.saved = time.Now()
+	config.GetDB().Model(&models.ScrapeJob{}).Where("id = ?", p.job.ID).UpdateColumns(map[string]interface{}{
+		"current_phase":   p.job.CurrentPhase,
+		"items_total":     p.job.ItemsTotal,
+		"items_processed": p.job.ItemsProcessed,
+	})
+	// The final flush comes after the job finished
+	if p.job.Status == "running" {
+		bus.PublishJob(bus.JobProgress, p.job)
+	}
+}
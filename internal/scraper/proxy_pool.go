@@ -0,0 +1,211 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Proxy rotation strategies (SCRAPER_PROXY_ROTATION)
+const (
+	ProxyRoundRobin = "round_robin"
+	ProxyRandom     = "random"
+)
+
+// ProxyStatus is the health of one rotated proxy, with credentials redacted
+type ProxyStatus struct {
+	URL                 string     `json:"url"`
+	Requests            int64      `json:"requests"`
+	Failures            int64      `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	BlacklistedUntil    *time.Time `json:"blacklisted_until,omitempty"`
+}
+
+type proxyState struct {
+	url         *url.URL
+	requests    int64
+	failures    int64
+	consecutive int
+	bannedUntil time.Time
+}
+
+// proxyPool picks a proxy per request and blacklists proxies that keep
+// failing for a cooldown
+type proxyPool struct {
+	mu          sync.Mutex
+	proxies     []*proxyState
+	random      bool
+	next        int
+	maxFailures int
+	cooldown    time.Duration
+}
+
+var (
+	sharedProxyPool     *proxyPool
+	sharedProxyPoolOnce sync.Once
+)
+
+// getProxyPool returns the pool built from SCRAPER_PROXIES, nil when unset
+func getProxyPool(cfg *config.Config) *proxyPool {
+	sharedProxyPoolOnce.Do(func() {
+		sharedProxyPool = newProxyPool(cfg)
+	})
+	return sharedProxyPool
+}
+
+func newProxyPool(cfg *config.Config) *proxyPool {
+	pool := &proxyPool{
+		random:      cfg.Scraper.ProxyRotation == ProxyRandom,
+		maxFailures: max(cfg.Scraper.ProxyMaxFailures, 1),
+		cooldown:    cfg.Scraper.ProxyCooldown,
+	}
+	for _, raw := range cfg.Scraper.Proxies {
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Host == "" {
+			logger.Warn("Ignoring invalid proxy", zap.String("proxy", raw))
+			continue
+		}
+		pool.proxies = append(pool.proxies, &proxyState{url: parsed})
+	}
+	if len(pool.proxies) == 0 {
+		return nil
+	}
+	return pool
+}
+
+// pick returns the next usable proxy. When every proxy is blacklisted the
+// one released soonest is used rather than going out from our own IP.
+func (p *proxyPool) pick() *proxyState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var usable []*proxyState
+	for _, proxy := range p.proxies {
+		if !now.Before(proxy.bannedUntil) {
+			usable = append(usable, proxy)
+		}
+	}
+
+	var chosen *proxyState
+	switch {
+	case len(usable) == 0:
+		chosen = p.proxies[0]
+		for _, proxy := range p.proxies[1:] {
+			if proxy.bannedUntil.Before(chosen.bannedUntil) {
+				chosen = proxy
+			}
+		}
+	case p.random:
+		chosen = usable[rand.IntN(len(usable))]
+	default:
+		chosen = usable[p.next%len(usable)]
+		p.next++
+	}
+
+	chosen.requests++
+	return chosen
+}
+
+// report records the outcome of a request through proxy. Network errors
+// and answers that usually mean the proxy is refused or throttled (403, 407,
+// 429) count as failures; maxFailures in a row blacklist it for cooldown.
+func (p *proxyPool) report(proxy *proxyState, resp *http.Response, err error) {
+	failed := err != nil && !errors.Is(err, context.Canceled)
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusForbidden, http.StatusProxyAuthRequired, http.StatusTooManyRequests:
+			failed = true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !failed {
+		proxy.consecutive = 0
+		return
+	}
+
+	proxy.failures++
+	proxy.consecutive++
+	if proxy.consecutive >= p.maxFailures {
+		proxy.consecutive = 0
+		proxy.bannedUntil = time.Now().Add(p.cooldown)
+		logger.Warn("Proxy blacklisted",
+			zap.String("proxy", proxy.url.Redacted()),
+			zap.Duration("cooldown", p.cooldown))
+	}
+}
+
+// status lists the proxies and their health
+func (p *proxyPool) status() []ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	out := make([]ProxyStatus, len(p.proxies))
+	for i, proxy := range p.proxies {
+		out[i] = ProxyStatus{
+			URL:                 proxy.url.Redacted(),
+			Requests:            proxy.requests,
+			Failures:            proxy.failures,
+			ConsecutiveFailures: proxy.consecutive,
+		}
+		if now.Before(proxy.bannedUntil) {
+			until := proxy.bannedUntil
+			out[i].BlacklistedUntil = &until
+		}
+	}
+	return out
+}
+
+// GetProxyStatus reports the rotated proxies, nil when SCRAPER_PROXIES is unset
+func GetProxyStatus(cfg *config.Config) []ProxyStatus {
+	pool := getProxyPool(cfg)
+	if pool == nil {
+		return nil
+	}
+	return pool.status()
+}
+
+type proxyContextKey struct{}
+
+// proxyFromContext is the Proxy func of transports behind a rotation: it
+// uses the proxy picked for the request, falling back to fallback
+func proxyFromContext(fallback func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if proxy, ok := req.Context().Value(proxyContextKey{}).(*proxyState); ok {
+			return proxy.url, nil
+		}
+		return fallback(req)
+	}
+}
+
+// proxyRotationTransport picks a proxy from the pool for every request and
+// reports how it went
+type proxyRotationTransport struct {
+	base http.RoundTripper
+	pool *proxyPool
+}
+
+func (t *proxyRotationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy := t.pool.pick()
+	req = req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxy))
+
+	resp, err := t.base.RoundTrip(req)
+	t.pool.report(proxy, resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("via proxy %s: %w", proxy.url.Redacted(), err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+)
+
+// tokenBucket hands out one token per interval, holding up to burst unused
+// tokens. Profile workers of every job share one bucket so running several
+// in parallel never exceeds the configured request pace.
+type tokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(interval time.Duration, burst int) *tokenBucket {
+	b := &tokenBucket{last: time.Now()}
+	b.setRate(interval, burst)
+	b.tokens = b.burst
+	return b
+}
+
+// setRate changes the refill interval and capacity, keeping saved tokens
+func (b *tokenBucket) setRate(interval time.Duration, burst int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.interval = interval
+	b.burst = float64(max(burst, 1))
+	b.tokens = min(b.tokens, b.burst)
+}
+
+// wait blocks until a token is available and takes it, or until ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.refill(now)
+		if b.interval <= 0 || b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) * float64(b.interval))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.interval > 0 {
+		b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	}
+	b.last = now
+}
+
+var (
+	profileBucket     *tokenBucket
+	profileBucketOnce sync.Once
+)
+
+// profileLimiter returns the bucket shared by profile workers, paced by
+// REQUEST_DELAY_MS with a burst of SCRAPER_CONCURRENCY
+func profileLimiter(c *config.Config) *tokenBucket {
+	profileBucketOnce.Do(func() {
+		cfg := c.Latest().Scraper
+		profileBucket = newTokenBucket(time.Duration(cfg.RequestDelayMs)*time.Millisecond, cfg.Concurrency)
+	})
+	return profileBucket
+}
@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RecordedExchange is one outbound request and its response, stored as a
+// line of a job recording
+type RecordedExchange struct {
+	Seq             int         `json:"seq"`
+	StartedAt       time.Time   `json:"started_at"`
+	DurationMs      int64       `json:"duration_ms"`
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	Status          int         `json:"status,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+	BodyEncoding    string      `json:"body_encoding,omitempty"` // "base64" when a body is not UTF-8
+	Truncated       bool        `json:"truncated,omitempty"`     // bodies dropped to stay under the size cap
+	Error           string      `json:"error,omitempty"`
+}
+
+// RecordingPath returns the file holding the recording of a debug job
+func RecordingPath(cfg *config.Config, job *models.ScrapeJob) string {
+	if job.Recording == "" {
+		return ""
+	}
+	return filepath.Join(cfg.Scraper.RecordDir, job.Recording)
+}
+
+// jobRecorder is a round tripper that appends every exchange of a debug job
+// to a JSON Lines file, up to maxBytes
+type jobRecorder struct {
+	base     http.RoundTripper
+	jobID    int
+	maxBytes int64
+
+	mu      sync.Mutex
+	file    *os.File
+	written int64
+	seq     int
+	dropped int
+}
+
+// newJobRecorder opens the recording of job on top of the scraper's
+// transport, or returns nil when the file cannot be created
+func (s *Scraper) newJobRecorder(job *models.ScrapeJob) *jobRecorder {
+	name := fmt.Sprintf("job-%d.jsonl", job.ID)
+	if err := os.MkdirAll(s.config.Scraper.RecordDir, 0o755); err != nil {
+		logger.Warn("Failed to create recording directory", zap.Error(err))
+		return nil
+	}
+	file, err := os.Create(filepath.Join(s.config.Scraper.RecordDir, name))
+	if err != nil {
+		logger.Warn("Failed to create job recording", zap.Int("job_id", job.ID), zap.Error(err))
+		return nil
+	}
+
+	job.Recording = name
+	config.GetDB().Model(job).UpdateColumn("recording", name)
+
+	logger.Info("Recording job requests",
+		zap.Int("job_id", job.ID),
+		zap.String("file", file.Name()))
+
+	return &jobRecorder{
+		base:     s.transport,
+		jobID:    job.ID,
+		maxBytes: s.config.Scraper.RecordMaxBytes,
+		file:     file,
+	}
+}
+
+func (r *jobRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange := RecordedExchange{
+		StartedAt:      time.Now(),
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: req.Header.Clone(),
+	}
+
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	exchange.DurationMs = time.Since(exchange.StartedAt).Milliseconds()
+	if err != nil {
+		exchange.Error = err.Error()
+		r.write(exchange, requestBody, nil)
+		return resp, err
+	}
+
+	responseBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	if readErr != nil {
+		exchange.Error = readErr.Error()
+	}
+
+	exchange.Status = resp.StatusCode
+	exchange.ResponseHeaders = resp.Header.Clone()
+	r.write(exchange, requestBody, responseBody)
+
+	return resp, nil
+}
+
+// write appends the exchange, dropping the bodies (or the whole line) when
+// it would push the file over maxBytes
+func (r *jobRecorder) write(exchange RecordedExchange, requestBody, responseBody []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+	r.seq++
+	exchange.Seq = r.seq
+
+	if !utf8.Valid(requestBody) || !utf8.Valid(responseBody) {
+		exchange.BodyEncoding = "base64"
+		exchange.RequestBody = base64.StdEncoding.EncodeToString(requestBody)
+		exchange.ResponseBody = base64.StdEncoding.EncodeToString(responseBody)
+	} else {
+		exchange.RequestBody = string(requestBody)
+		exchange.ResponseBody = string(responseBody)
+	}
+
+	line, _ := json.Marshal(exchange)
+	if r.maxBytes > 0 && r.written+int64(len(line))+1 > r.maxBytes {
+		exchange.RequestBody, exchange.ResponseBody, exchange.BodyEncoding = "", "", ""
+		exchange.Truncated = true
+		line, _ = json.Marshal(exchange)
+		if r.written+int64(len(line))+1 > r.maxBytes {
+			r.dropped++
+			return
+		}
+	}
+
+	n, err := r.file.Write(append(line, '\n'))
+	r.written += int64(n)
+	if err != nil {
+		logger.Warn("Failed to write job recording", zap.Int("job_id", r.jobID), zap.Error(err))
+	}
+}
+
+func (r *jobRecorder) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+	if err := r.file.Close(); err != nil {
+		logger.Warn("Failed to close job recording", zap.Int("job_id", r.jobID), zap.Error(err))
+	}
+	r.file = nil
+
+	logger.Info("Job recording closed",
+		zap.Int("job_id", r.jobID),
+		zap.Int("exchanges", r.seq-r.dropped),
+		zap.Int("dropped", r.dropped),
+		zap.Int64("bytes", r.written))
+}
@@ -0,0 +1,100 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// pruneStaleRegistrations deletes the registrations of an event that were not
+// written by the scrape that started at since.
+func pruneStaleRegistrations(tx *gorm.DB, eventID string, since time.Time) error {
+	result := tx.Where("event_id = ? AND updated_at < ?", eventID, since).Delete(&models.EventRegistration{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("Stale registrations removed",
+			zap.String("event_id", eventID),
+			zap.Int64("registrations", result.RowsAffected))
+	}
+	return nil
+}
+
+// DedupeRegistrations removes registrations duplicated before they were keyed
+// by division ID: rows of the same athlete, event and division, or of the same
+// category when the division ID is unknown. The newest row of each is kept
+// and matches pointing at a removed row are moved to it. Different divisions
+// of an event (gi, no-gi, absolute) are separate registrations and all stay.
+// It runs once, as a data migration.
+func DedupeRegistrations() error {
+	var rows []struct {
+		ID          uint
+		AthleteID   uint
+		EventID     string
+		DivisionID  *string
+		Division    string
+		AgeCategory string
+		Rank        string
+		WeightClass string
+	}
+	if err := config.GetDB().Model(&models.EventRegistration{}).
+		Select("id, athlete_id, event_id, division_id, division, age_category, rank, weight_class").
+		Order("id DESC").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	// Rows come newest first, so the first of each key is the one kept
+	kept := make(map[string]uint, len(rows))
+	duplicates := map[uint]uint{}
+	for _, row := range rows {
+		key := fmt.Sprintf("%d|%s|", row.AthleteID, row.EventID)
+		if row.DivisionID != nil {
+			key += "division|" + *row.DivisionID
+		} else {
+			key += strings.Join([]string{"category", row.Division, row.AgeCategory, row.Rank, row.WeightClass}, "|")
+		}
+		if keep, ok := kept[key]; ok {
+			duplicates[row.ID] = keep
+			continue
+		}
+		kept[key] = row.ID
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		for id, keep := range duplicates {
+			for _, side := range []string{"registration_a_id", "registration_b_id"} {
+				if err := tx.Model(&models.Match{}).Where(side+" = ?", id).
+					UpdateColumn(side, keep).Error; err != nil {
+					return err
+				}
+			}
+		}
+		ids := make([]uint, 0, len(duplicates))
+		for id := range duplicates {
+			ids = append(ids, id)
+		}
+		for start := 0; start < len(ids); start += 500 {
+			end := min(start+500, len(ids))
+			if err := tx.Delete(&models.EventRegistration{}, ids[start:end]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Duplicate registrations removed", zap.Int("registrations", len(duplicates)))
+	return nil
+}
@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxRecheckShift bounds the doubling of the recheck delay
+const maxRecheckShift = 16
+
+// trackResults records the outcome of a results scrape on a stored event.
+// Past events without placements get a recheck after RESULTS_RECHECK_HOURS,
+// doubling with each empty scrape, until the window since the event start
+// (RESULTS_RECHECK_WINDOW_DAYS) has passed.
+func (s *Scraper) trackResults(event *models.Event, found bool) {
+	if event.ID == 0 {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"results_published":  true,
+		"results_checks":     0,
+		"results_recheck_at": nil,
+	}
+	if !found {
+		event.ResultsChecks++
+		recheckAt := s.nextResultsCheck(event, time.Now())
+		updates = map[string]interface{}{
+			"results_checks":     event.ResultsChecks,
+			"results_recheck_at": recheckAt,
+		}
+		if recheckAt != nil {
+			logger.Info("Event results not published yet, recheck scheduled",
+				zap.String("event_id", event.ExternalID),
+				zap.Int("checks", event.ResultsChecks),
+				zap.Time("recheck_at", *recheckAt))
+		}
+	}
+
+	if err := config.GetDB().Model(event).Updates(updates).Error; err != nil {
+		logger.Error("Failed to record event results state",
+			zap.String("event_id", event.ExternalID),
+			zap.Error(err))
+	}
+}
+
+// nextResultsCheck is when an event without results is scraped again, or
+// nil for upcoming events and once the recheck window is over
+func (s *Scraper) nextResultsCheck(event *models.Event, now time.Time) *time.Time {
+	cfg := s.config.Results
+	if cfg.Window <= 0 || event.StartDate == nil || event.StartDate.After(now) {
+		return nil
+	}
+
+	shift := min(event.ResultsChecks-1, maxRecheckShift)
+	next := now.Add(cfg.RecheckDelay << shift)
+	if next.After(event.StartDate.Add(cfg.Window)) {
+		logger.Info("Giving up on event results",
+			zap.String("event_id", event.ExternalID),
+			zap.Int("checks", event.ResultsChecks))
+		return nil
+	}
+	return &next
+}
+
+// DueResultsRechecks returns the events whose results recheck is due
+func DueResultsRechecks(now time.Time) ([]models.Event, error) {
+	var events []models.Event
+	err := config.GetDB().
+		Where("results_published = ? AND results_recheck_at IS NOT NULL AND results_recheck_at <= ?", false, now).
+		Order("results_recheck_at").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,249 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/kmicac/smoothcomp-scraper/internal/academystats"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// BuildResultsURL returns the results page of an event
+func BuildResultsURL(subdomain, eventID string) string {
+	return fmt.Sprintf("https://%s/en/event/%s/results", subdomain, eventID)
+}
+
+func (s *Scraper) fetchResultsPage(ctx context.Context, client *http.Client, resultsURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", resultsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", resultsURL, err)
+	}
+	return resp, nil
+}
+
+const resultRowSelector = ".placement, .result-row, .result, li, tr"
+
+var (
+	placementPattern = regexp.MustCompile(`^\s*([1-4])(?:st|nd|rd|th|\.|º|°)?(?:\s|$)`)
+	clubIDPattern    = regexp.MustCompile(`/club/(\d+)`)
+)
+
+// ScrapeEventResults fetches the results page of an event and stores the
+// podium placements (1st to 4th) of every division, replacing the results
+// saved by earlier scrapes. Stored events without placements yet are
+// scheduled for a recheck (see trackResults). Returns how many placements
+// were saved.
+func (s *Scraper) ScrapeEventResults(ctx context.Context, eventID string) (int, error) {
+	if err := checkBlocked(models.BlockedEvent, eventID); err != nil {
+		return 0, err
+	}
+
+	var event models.Event
+	config.GetDB().Where("external_id = ?", eventID).Limit(1).Find(&event)
+	host := s.eventHost(ctx, eventID, event.EventURL)
+	resultsURL := BuildResultsURL(host, eventID)
+
+	client := s.newHTTPClient(20 * time.Second)
+	resp, err := s.fetchResultsPage(ctx, client, resultsURL)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// The event may have moved host since it was stored
+		if moved, ok := s.redetectEventHost(ctx, eventID, host); ok {
+			resp.Body.Close()
+			resultsURL = BuildResultsURL(moved, eventID)
+			if resp, err = s.fetchResultsPage(ctx, client, resultsURL); err != nil {
+				return 0, err
+			}
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("results page returned status %d", resp.StatusCode)
+	}
+
+	fetchedAt := time.Now()
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing results page: %w", err)
+	}
+
+	results := s.parseEventResults(doc)
+	for i := range results {
+		results[i].SourceURL = resultsURL
+		results[i].FetchedAt = &fetchedAt
+	}
+	previous, _ := academystats.EventAcademies(eventID)
+	saved, err := SaveEventResults(eventID, event.Name, results)
+	if err != nil {
+		return 0, err
+	}
+	s.trackResults(&event, saved > 0)
+	if saved > 0 {
+		refreshAcademyStats(eventID, previous)
+	}
+
+	logger.Info("Event results scraped",
+		zap.String("event_id", eventID),
+		zap.Int("placements", saved))
+
+	return saved, nil
+}
+
+// refreshAcademyStats recomputes the statistics of the academies credited
+// with the results of an event, before (previous) and after a scrape
+func refreshAcademyStats(eventID string, previous []string) {
+	current, err := academystats.EventAcademies(eventID)
+	if err != nil {
+		logger.Error("Failed to read academies of event results", zap.String("event_id", eventID), zap.Error(err))
+		return
+	}
+	academyIDs := append(previous, current...)
+	slices.Sort(academyIDs)
+	academyIDs = slices.Compact(academyIDs)
+	if len(academyIDs) == 0 {
+		return
+	}
+	if _, err := academystats.Recompute(academyIDs...); err != nil {
+		logger.Error("Failed to recompute academy statistics", zap.String("event_id", eventID), zap.Error(err))
+	}
+}
+
+// parseEventResults reads the division blocks of a results page. Each block
+// has a division title and one row per placement, linking the athlete
+// profile and usually the club.
+func (s *Scraper) parseEventResults(doc *goquery.Document) []models.EventResult {
+	var results []models.EventResult
+
+	doc.Find(".results-category, .result-category, .category-results, .bracket-results").Each(func(_ int, block *goquery.Selection) {
+		division := strings.Join(strings.Fields(block.Find(".category-name, .title, h2, h3, h4").First().Text()), " ")
+
+		block.Find(resultRowSelector).Each(func(_ int, row *goquery.Selection) {
+			if row.Find(resultRowSelector).Length() > 0 {
+				return // a wrapper; its inner rows are visited on their own
+			}
+			profile := row.Find("a[href*='/profile/']").First()
+			href, ok := profile.Attr("href")
+			if !ok {
+				return
+			}
+
+			placement := rowPlacement(row)
+			if placement == 0 {
+				return
+			}
+
+			result := models.EventResult{
+				Division:          division,
+				Placement:         placement,
+				Medal:             models.MedalForPlacement(placement),
+				AthleteExternalID: ExtractIDFromURL(urlnorm.ProfileURL(href)),
+				AthleteName:       strings.Join(strings.Fields(profile.Text()), " "),
+			}
+			club := row.Find("a[href*='/club/']").First()
+			if clubHref, ok := club.Attr("href"); ok {
+				if m := clubIDPattern.FindStringSubmatch(clubHref); m != nil {
+					result.AcademyExternalID = m[1]
+				}
+				result.AcademyName = strings.Join(strings.Fields(club.Text()), " ")
+			}
+
+			s.observeFields("result", map[string]bool{
+				"division": division != "",
+				"athlete":  result.AthleteExternalID != "",
+				"name":     result.AthleteName != "",
+				"academy":  result.AcademyName != "",
+			})
+			results = append(results, result)
+		})
+	})
+
+	return results
+}
+
+// rowPlacement reads the placement of a result row from its placement
+// element, its medal class or its leading number
+func rowPlacement(row *goquery.Selection) int {
+	text := strings.TrimSpace(row.Find(".place, .placement-number, .rank").First().Text())
+	if text == "" {
+		text = strings.TrimSpace(row.Text())
+	}
+	if m := placementPattern.FindStringSubmatch(text); m != nil {
+		placement, _ := strconv.Atoi(m[1])
+		return placement
+	}
+
+	class, _ := row.Attr("class")
+	class += " " + row.Find("[class*='gold'], [class*='silver'], [class*='bronze']").AttrOr("class", "")
+	switch {
+	case strings.Contains(class, "gold"):
+		return 1
+	case strings.Contains(class, "silver"):
+		return 2
+	case strings.Contains(class, "bronze"):
+		return 3
+	}
+	return 0
+}
+
+// SaveEventResults replaces the stored placements of an event, linking them
+// to stored athletes and blanking the names of suppressed ones. An empty
+// list keeps the stored results, so a page that failed to parse does not
+// wipe them.
+func SaveEventResults(eventID, eventName string, results []models.EventResult) (int, error) {
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.EventResult{}).Error; err != nil {
+			return fmt.Errorf("error clearing results: %w", err)
+		}
+
+		for i := range results {
+			result := &results[i]
+			result.EventID = eventID
+			result.EventName = eventName
+			result.ScrapedAt = now
+
+			if result.AthleteExternalID != "" {
+				result.AthleteExternalID = ResolveAthleteID(tx, result.AthleteExternalID)
+				var athlete models.Athlete
+				if tx.Select("id").Where("external_id = ?", result.AthleteExternalID).
+					Limit(1).Find(&athlete).RowsAffected > 0 {
+					result.AthleteID = uint(athlete.ID)
+				}
+				if isSuppressed(tx, result.AthleteExternalID) {
+					result.AthleteName = ""
+				}
+			}
+		}
+
+		return tx.CreateInBatches(results, 200).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
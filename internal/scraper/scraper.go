@@ -1,12 +1,17 @@
 package scraper
 
 import (
+	"context"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gocolly/colly/v2"
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
 	"github.com/kmicac/smoothcomp-scraper/internal/config"
 	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/internal/savedquery"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -14,67 +19,152 @@ import (
 type Scraper struct {
 	config    *config.Config
 	collector *colly.Collector
+	limit     *colly.LimitRule // shared with collector clones, see forJob
+	transport http.RoundTripper
+	writes    *WriteBuffer
+	notifier  *notify.Dispatcher
+	coverage  *parseCoverage // set on copies scoped to a job, see forJob
+	behavior  *Behavior      // set on copies scoped to a job, see forJob
+	progress  *jobProgress   // set on copies scoped to a job, see forJob
+	storage   *dbStorage     // nil with SCRAPER_STORAGE=memory
 }
 
 // NewScraper creates a new scraper instance
 func NewScraper(cfg *config.Config) *Scraper {
+	transport := getSharedTransport(cfg)
+
+	// Persist visited pages and cookies across clones and restarts
+	c, limit, store := newCollector(cfg, transport, getSharedStorage(cfg))
+
+	s := &Scraper{
+		config:    cfg,
+		collector: c,
+		limit:     limit,
+		transport: transport,
+		storage:   store,
+		writes:    getSharedWrites(cfg),
+		notifier:  notify.NewDispatcher(cfg),
+	}
+	return s
+}
+
+// newCollector builds a collector paced by cfg's REQUEST_DELAY_MS. Falls back
+// to memory storage (nil store) if store cannot be attached
+func newCollector(cfg *config.Config, transport http.RoundTripper, store *dbStorage) (*colly.Collector, *colly.LimitRule, *dbStorage) {
 	c := colly.NewCollector(
 		colly.UserAgent(cfg.Scraper.UserAgent),
-		colly.AllowedDomains("smoothcomp.com", "www.smoothcomp.com"),
+		colly.URLFilters(hostFilter(cfg)),
 	)
+	c.WithTransport(transport)
 
 	// Set request delay
-	c.Limit(&colly.LimitRule{
+	limit := &colly.LimitRule{
 		DomainGlob:  "*smoothcomp.com*",
 		Delay:       time.Duration(cfg.Scraper.RequestDelayMs) * time.Millisecond,
 		RandomDelay: 1 * time.Second,
-	})
+	}
+	c.Limit(limit)
 
-	return &Scraper{
-		config:    cfg,
-		collector: c,
+	if store != nil {
+		if err := c.SetStorage(store); err != nil {
+			logger.Warn("Failed to initialize crawl storage, using memory", zap.Error(err))
+			store = nil
+		}
 	}
+	return c, limit, store
 }
 
-// ScrapeAll scrapes both academies and athletes
-func (s *Scraper) ScrapeAll() error {
+// ApplyRequestDelay updates the pace shared by profile workers to the
+// REQUEST_DELAY_MS of cfg's latest configuration, after a reload. Other
+// requests pick the new delay up with the next job (see forJob); running
+// jobs keep theirs.
+func ApplyRequestDelay(cfg *config.Config) {
+	scraperCfg := cfg.Latest().Scraper
+	profileLimiter(cfg).setRate(time.Duration(scraperCfg.RequestDelayMs)*time.Millisecond, scraperCfg.Concurrency)
+}
+
+// ScrapeAll runs the full pipeline (discover, details, participants,
+// enrich, aggregates) over the target countries until done or ctx is
+// cancelled
+func (s *Scraper) ScrapeAll(ctx context.Context) error {
 	logger.Info("Starting full scraping job")
 
-	job := s.createJob("all")
+	full := s.fullPipeline()
+	run := full.Start()
+	ctx, release := trackJob(ctx, run.Job)
+	defer release()
 
-	// Scrape academies first
-	if err := s.ScrapeAcademies(); err != nil {
-		s.failJob(job, err)
-		return err
-	}
+	err := full.Execute(ctx, run)
+	savedquery.CheckAll(s.config)
+	return err
+}
 
-	// Then scrape athletes
-	if err := s.ScrapeAthletes(); err != nil {
-		s.failJob(job, err)
-		return err
+// CheckResumeScrapeAll reports why a full pipeline job cannot be resumed,
+// without reopening it
+func (s *Scraper) CheckResumeScrapeAll(jobID int) error {
+	return s.fullPipeline().CheckResume(jobID)
+}
+
+// ResumeScrapeAll validates that a failed full pipeline job can be resumed
+// and returns a function that continues it from its first unfinished stage
+func (s *Scraper) ResumeScrapeAll(jobID int) (func(ctx context.Context) error, error) {
+	full := s.fullPipeline()
+	run, err := full.Resume(jobID)
+	if err != nil {
+		return nil, err
 	}
+	return func(ctx context.Context) error {
+		ctx, release := trackJob(ctx, run.Job)
+		defer release()
 
-	s.completeJob(job)
-	logger.Info("Full scraping completed successfully")
-	return nil
+		err := full.Execute(ctx, run)
+		savedquery.CheckAll(s.config)
+		return err
+	}, nil
 }
 
 // ScrapeAcademies scrapes academy data from SmoothComp
-func (s *Scraper) ScrapeAcademies() error {
+func (s *Scraper) ScrapeAcademies(ctx context.Context) error {
 	logger.Info("Starting academy scraping")
 
 	job := s.createJob("academies")
-	itemsScraped := 0
+	ctx, release := trackJob(ctx, job)
+	defer release()
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+
+	itemsScraped, _, failedPages := s.discoverAcademies(ctx)
 
-	// Scrape academies for each target country
+	job.ItemsScraped = itemsScraped
+	job.PagesFailed = failedPages
+	s.endJob(ctx, job)
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	logger.Info("Academy scraping completed", zap.Int("total", itemsScraped))
+	return nil
+}
+
+// discoverAcademies scrapes and saves the academies of every target country,
+// returning how many were saved, how many countries failed and how many club
+// pages failed
+func (s *Scraper) discoverAcademies(ctx context.Context) (itemsScraped int, failedCountries int, failedPages int) {
+	s.progress.phase("academies", len(s.config.Scraper.TargetCountries))
 	for _, countryCode := range s.config.Scraper.TargetCountries {
+		if ctx.Err() != nil {
+			break
+		}
 		logger.Info("Scraping country", zap.String("country", countryCode))
 
-		academies, err := s.ScrapeAcademiesByCountry(countryCode)
+		academies, failed, err := s.ScrapeAcademiesByCountry(ctx, countryCode)
+		failedPages += failed
 		if err != nil {
 			logger.Error("Failed to scrape country",
 				zap.String("country", countryCode),
 				zap.Error(err))
+			failedCountries++
+			s.progress.add(1)
 			continue
 		}
 
@@ -92,44 +182,35 @@ func (s *Scraper) ScrapeAcademies() error {
 		logger.Info("Country scraping completed",
 			zap.String("country", countryCode),
 			zap.Int("academies", len(academies)))
+		s.progress.add(1)
 	}
 
-	job.ItemsScraped = itemsScraped
-	s.completeJob(job)
-
-	logger.Info("Academy scraping completed", zap.Int("total", itemsScraped))
-	return nil
-}
-
-// ScrapeAthletes scrapes athlete data from SmoothComp
-func (s *Scraper) ScrapeAthletes() error {
-	logger.Info("Starting athlete scraping")
-
-	job := s.createJob("athletes")
-
-	// TODO: Implement actual scraping logic
-	// For now, this is a placeholder
-	logger.Info("Athlete scraping placeholder - will implement actual logic next")
-
-	s.completeJob(job)
-	return nil
+	return itemsScraped, failedCountries, failedPages
 }
 
 // createJob creates a new scrape job record
 func (s *Scraper) createJob(jobType string) *models.ScrapeJob {
+	return s.createDepthJob(jobType, "")
+}
+
+// createDepthJob creates a new scrape job record that records its crawl depth
+func (s *Scraper) createDepthJob(jobType string, depth string) *models.ScrapeJob {
 	db := config.GetDB()
 
 	job := &models.ScrapeJob{
 		JobType:   jobType,
+		Depth:     depth,
 		Status:    "running",
 		StartedAt: time.Now(),
 	}
 
 	db.Create(job)
+	bus.PublishJob(bus.JobStarted, job)
 
 	logger.Info("Scrape job created",
 		zap.Int("job_id", job.ID),
-		zap.String("type", jobType))
+		zap.String("type", jobType),
+		zap.String("depth", job.Depth))
 
 	return job
 }
@@ -143,10 +224,16 @@ func (s *Scraper) completeJob(job *models.ScrapeJob) {
 	job.CompletedAt = &now
 
 	db.Save(job)
+	bus.PublishJob(bus.JobFinished, job)
 
 	logger.Info("Scrape job completed",
 		zap.Int("job_id", job.ID),
 		zap.Int("items_scraped", job.ItemsScraped))
+
+	// Chunks are followed by their parent job
+	if job.ParentJobID == nil {
+		savedquery.CheckAll(s.config)
+	}
 }
 
 // failJob marks a job as failed
@@ -159,6 +246,7 @@ func (s *Scraper) failJob(job *models.ScrapeJob, err error) {
 	job.ErrorMessage = err.Error()
 
 	db.Save(job)
+	bus.PublishJob(bus.JobFinished, job)
 
 	logger.Error("Scrape job failed",
 		zap.Int("job_id", job.ID),
@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// slugAttempts bounds the retries of saveSlugged when other writers keep
+// taking the chosen slug
+const slugAttempts = 5
+
+// saveSlugged runs save with *slugField set to a slug for name that no other
+// row of model uses, keeping a slug that is already set. Named rows get their
+// name's slug, or -2, -3... when it is taken; nameless (or suppressed) rows
+// get n-a-<id>, so a new one is saved once without a slug to learn its ID.
+// The slug columns have a unique index: save runs in a savepoint and is
+// retried with the next free slug when another writer took it in between.
+func saveSlugged(tx *gorm.DB, model interface{}, name string, slugField *string, id *int, save func(tx *gorm.DB) error) error {
+	if *slugField != "" {
+		return save(tx)
+	}
+
+	base := slug.Make(name)
+	if base == "" {
+		if *id == 0 {
+			if err := save(tx); err != nil {
+				return err
+			}
+			save = func(tx *gorm.DB) error {
+				return tx.Model(model).Where("id = ?", *id).UpdateColumn("slug", *slugField).Error
+			}
+		}
+		base = fmt.Sprintf("n-a-%d", *id)
+	}
+
+	var err error
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		*slugField = freeSlug(tx, model, base, *id)
+		if err = tx.Transaction(save); err == nil || !isDuplicateKey(tx, err) {
+			return err
+		}
+	}
+	return err
+}
+
+// freeSlug returns base, or base-2, base-3... the first one no other row of
+// model uses. id is the row being slugged (0 if new).
+func freeSlug(tx *gorm.DB, model interface{}, base string, id int) string {
+	candidate := base
+	for n := 2; ; n++ {
+		var count int64
+		tx.Model(model).Where("slug = ? AND id <> ?", candidate, id).Count(&count)
+		if count == 0 {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// isDuplicateKey reports whether err is a unique index violation
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// BackfillSlugs assigns slugs to athletes and academies that have none and
+// regenerates academy slugs produced by the old ASCII-only generator and
+// nameless n-a-N slugs from before they were ID-based. It is idempotent and
+// safe to run on every startup.
+func BackfillSlugs() error {
+	db := config.GetDB()
+
+	athletes, err := backfillSlugs(db, &models.Athlete{}, "full_name")
+	if err != nil {
+		return fmt.Errorf("failed to backfill athlete slugs: %w", err)
+	}
+	academies, err := backfillSlugs(db, &models.Academy{}, "name")
+	if err != nil {
+		return fmt.Errorf("failed to backfill academy slugs: %w", err)
+	}
+
+	if athletes+academies > 0 {
+		logger.Info("Slugs backfilled",
+			zap.Int("athletes", athletes),
+			zap.Int("academies", academies))
+	}
+
+	return nil
+}
+
+type slugRow struct {
+	ID   int
+	Name string
+	Slug string
+}
+
+func backfillSlugs(db *gorm.DB, model interface{}, nameColumn string) (int, error) {
+	var rows []slugRow
+	err := db.Model(model).
+		Select("id, " + nameColumn + " AS name, slug").
+		Order("id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		if !needsSlug(row) {
+			continue
+		}
+		row.Slug = ""
+		err := saveSlugged(db, model, row.Name, &row.Slug, &row.ID, func(tx *gorm.DB) error {
+			return tx.Model(model).Where("id = ?", row.ID).Update("slug", row.Slug).Error
+		})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// needsSlug reports whether row has no slug, one from the old ASCII-only
+// generator, or a nameless slug that is not based on its ID
+func needsSlug(row slugRow) bool {
+	switch {
+	case row.Slug == "":
+		return true
+	case slug.Make(row.Name) == "":
+		idSlug := fmt.Sprintf("n-a-%d", row.ID)
+		return row.Slug != idSlug && !strings.HasPrefix(row.Slug, idSlug+"-")
+	default:
+		return row.Slug == legacySlug(row.Name) && row.Slug != slug.Make(row.Name)
+	}
+}
+
+// legacySlug reproduces the previous generator, which dropped every
+// non-ASCII letter (João -> jo)
+func legacySlug(name string) string {
+	s := strings.ToLower(name)
+	s = strings.ReplaceAll(s, " ", "-")
+	s = strings.ReplaceAll(s, "/", "-")
+	var result strings.Builder
+	for _, char := range s {
+		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' {
+			result.WriteRune(char)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// EnrichStaleAthletes re-scrapes up to limit profiles that were never enriched
+// or were last enriched more than maxAge ago. Athletes who competed most
+// recently (last_active_at, the date of their latest event) go first, so
+// active competitors stay freshest.
+func (s *Scraper) EnrichStaleAthletes(ctx context.Context, maxAge time.Duration, limit int) (int, error) {
+	job := s.createJob("enrich_stale")
+	ctx, release := trackJob(ctx, job)
+	defer release()
+	s, finish := s.forJob(job, RunOptions{})
+	defer finish()
+	cutoff := time.Now().Add(-maxAge)
+
+	db := config.GetDB()
+	query := db.Model(&models.Athlete{}).
+		Where("profile_scraped_at IS NULL OR profile_scraped_at < ?", cutoff).
+		Where("external_id NOT IN (?)", blockedIDs(db, models.BlockedAthlete)).
+		Order("last_active_at IS NULL, last_active_at DESC, profile_scraped_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var athletes []models.Athlete
+	if err := query.Find(&athletes).Error; err != nil {
+		err = fmt.Errorf("error loading stale athletes: %w", err)
+		s.failJob(job, err)
+		return 0, err
+	}
+
+	s.progress.phase("profiles", len(athletes))
+	scraped, _ := s.scrapeProfiles(ctx, athletes, timeBox{})
+
+	job.ItemsScraped = scraped
+	if ctx.Err() != nil {
+		s.cancelJob(ctx, job)
+		return scraped, context.Cause(ctx)
+	}
+	s.completeJob(job)
+
+	logger.Info("Stale athlete re-enrichment completed",
+		zap.Duration("max_age", maxAge),
+		zap.Int("selected", len(athletes)),
+		zap.Int("scraped", scraped))
+
+	return scraped, nil
+}
@@ -1,19 +1,39 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
 	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
 	"go.uber.org/zap"
 )
 
+// errEventHostNotFound es el 404 de una página del evento en su host guardado
+var errEventHostNotFound = errors.New("event not found on this host")
+
 // DetectEventSubdomain detecta el subdominio correcto para un evento
 // Algunos eventos están en subdominios específicos (adcc.smoothcomp.com, ibjjf.smoothcomp.com)
-// mientras que otros están en el dominio principal (smoothcomp.com)
-func (s *Scraper) DetectEventSubdomain(eventID string) string {
+// mientras que otros están en el dominio principal (smoothcomp.com).
+// El subdominio detectado se guarda en event_subdomains y se reutiliza sin
+// probar hosts hasta que vence (SUBDOMAIN_CACHE_DAYS) o queda marcado stale.
+func (s *Scraper) DetectEventSubdomain(ctx context.Context, eventID string) string {
+	cached, fresh := s.cachedEventSubdomain(eventID)
+	if fresh {
+		logger.Debug("Subdominio en cache",
+			zap.String("event_id", eventID),
+			zap.String("subdomain", cached.Host))
+		return cached.Host
+	}
+
 	// Lista de subdominios comunes para probar
 	subdomains := []string{
 		"",          // smoothcomp.com (sin subdominio)
@@ -26,73 +46,200 @@ func (s *Scraper) DetectEventSubdomain(eventID string) string {
 		"grappling", // grappling.smoothcomp.com
 	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// No seguir redirects automáticamente
-			return http.ErrUseLastResponse
-		},
+	// La federación detectada antes se prueba aunque no esté en la lista
+	if cached.Federation != "" && !slices.Contains(subdomains, cached.Federation) {
+		subdomains = append(subdomains, cached.Federation)
+	}
+
+	client := s.newHTTPClient(10 * time.Second)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		// No seguir redirects automáticamente
+		return http.ErrUseLastResponse
 	}
 
 	logger.Info("Detectando subdominio del evento", zap.String("event_id", eventID))
 
+	// Se prueban todos los hosts a la vez y gana el primero que responde bien;
+	// al encontrarlo se cancelan las pruebas que siguen en curso
+	probeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	hosts := make(chan string, len(subdomains))
 	for _, subdomain := range subdomains {
-		var baseURL string
-		if subdomain == "" {
-			baseURL = "smoothcomp.com"
-		} else {
+		baseURL := "smoothcomp.com"
+		if subdomain != "" {
 			baseURL = fmt.Sprintf("%s.smoothcomp.com", subdomain)
 		}
+		go func() {
+			hosts <- s.probeEventHost(probeCtx, client, baseURL, eventID)
+		}()
+	}
 
-		// Intentar hacer HEAD request a la página del evento
-		eventURL := fmt.Sprintf("https://%s/en/event/%s", baseURL, eventID)
-
-		req, err := http.NewRequest("HEAD", eventURL, nil)
-		if err != nil {
-			continue
+	for range subdomains {
+		if host := <-hosts; host != "" {
+			cancel()
+			rememberEventSubdomain(eventID, host)
+			return host
 		}
+	}
+
+	// Si no encontramos ningún subdominio válido, usar el dominio principal
+	logger.Warn("No se detectó subdominio específico, usando smoothcomp.com",
+		zap.String("event_id", eventID))
+	return "smoothcomp.com"
+}
 
-		req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+// probeEventHost pide la página del evento en baseURL y devuelve el host que
+// la sirve (baseURL, o el subdominio al que redirige), o "" si no la sirve
+func (s *Scraper) probeEventHost(ctx context.Context, client *http.Client, baseURL, eventID string) string {
+	// Intentar hacer HEAD request a la página del evento
+	eventURL := fmt.Sprintf("https://%s/en/event/%s", baseURL, eventID)
 
-		resp, err := client.Do(req)
-		if err != nil {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", eventURL, nil)
+	if err != nil {
+		return ""
+	}
+
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Las pruebas canceladas porque otro host ganó no son fallas
+		if ctx.Err() == nil {
 			logger.Debug("Subdominio falló",
 				zap.String("subdomain", baseURL),
 				zap.Error(err))
-			continue
 		}
-		resp.Body.Close()
+		return ""
+	}
+	resp.Body.Close()
 
-		// Si recibimos 200 OK, este es el subdominio correcto
-		if resp.StatusCode == http.StatusOK {
-			logger.Info("Subdominio detectado",
+	// Si recibimos 200 OK, este es el subdominio correcto
+	if resp.StatusCode == http.StatusOK {
+		logger.Info("Subdominio detectado",
+			zap.String("subdomain", baseURL),
+			zap.String("event_url", eventURL))
+		return baseURL
+	}
+
+	// Si recibimos 301/302 y nos redirigen al mismo dominio con https, también es válido
+	if resp.StatusCode == http.StatusMovedPermanently ||
+		resp.StatusCode == http.StatusFound {
+		location := resp.Header.Get("Location")
+		// Verificar si el redirect es al mismo dominio
+		if location != "" && containsSubstring(location, baseURL) {
+			logger.Info("Subdominio detectado via redirect",
 				zap.String("subdomain", baseURL),
-				zap.String("event_url", eventURL))
+				zap.String("redirect", location))
 			return baseURL
 		}
-
-		// Si recibimos 301/302 y nos redirigen al mismo dominio con https, también es válido
-		if resp.StatusCode == http.StatusMovedPermanently ||
-			resp.StatusCode == http.StatusFound {
-			location := resp.Header.Get("Location")
-			// Verificar si el redirect es al mismo dominio
-			if location != "" && containsSubstring(location, baseURL) {
-				logger.Info("Subdominio detectado via redirect",
-					zap.String("subdomain", baseURL),
-					zap.String("redirect", location))
-				return baseURL
-			}
+		// smoothcomp.com redirige los eventos de federación a su subdominio
+		if host := s.redirectEventHost(eventURL, location, eventID); host != "" {
+			logger.Info("Subdominio detectado via redirect",
+				zap.String("subdomain", host),
+				zap.String("redirect", location))
+			return host
 		}
+	}
 
-		logger.Debug("Subdominio no válido",
-			zap.String("subdomain", baseURL),
-			zap.Int("status", resp.StatusCode))
+	logger.Debug("Subdominio no válido",
+		zap.String("subdomain", baseURL),
+		zap.Int("status", resp.StatusCode))
+	return ""
+}
+
+// rememberEventSubdomain guarda el host detectado (o que sirvió la página de
+// detalle) de un evento y la federación (el subdominio, vacío en
+// smoothcomp.com)
+func rememberEventSubdomain(eventID, host string) {
+	federation := ""
+	if label, ok := strings.CutSuffix(host, ".smoothcomp.com"); ok && !strings.Contains(label, ".") && label != "www" {
+		federation = label
 	}
+	entry := models.EventSubdomain{EventID: eventID}
+	err := config.GetDB().Where(entry).
+		Assign(map[string]interface{}{
+			"host":        host,
+			"federation":  federation,
+			"stale":       false,
+			"detected_at": time.Now(),
+		}).
+		FirstOrCreate(&entry).Error
+	if err != nil {
+		logger.Warn("No se pudo guardar el subdominio del evento",
+			zap.String("event_id", eventID),
+			zap.Error(err))
+	}
+}
 
-	// Si no encontramos ningún subdominio válido, usar el dominio principal
-	logger.Warn("No se detectó subdominio específico, usando smoothcomp.com",
-		zap.String("event_id", eventID))
-	return "smoothcomp.com"
+// cachedEventSubdomain devuelve el host guardado de un evento mientras no
+// venció (SUBDOMAIN_CACHE_DAYS) ni quedó stale
+func (s *Scraper) cachedEventSubdomain(eventID string) (models.EventSubdomain, bool) {
+	var cached models.EventSubdomain
+	found := config.GetDB().Where("event_id = ?", eventID).Limit(1).Find(&cached).RowsAffected > 0
+	return cached, found && !cached.Stale && time.Since(cached.DetectedAt) < s.config.Scraper.SubdomainCacheTTL
+}
+
+// markSubdomainStale marca el subdominio guardado de un evento para volver a
+// detectarlo, cuando su página dejó de responder en ese host
+func markSubdomainStale(eventID string) {
+	config.GetDB().Model(&models.EventSubdomain{}).
+		Where("event_id = ?", eventID).
+		Update("stale", true)
+}
+
+// redetectEventHost descarta el host de un evento que respondió 404 y lo
+// vuelve a detectar. Devuelve el host nuevo, u ok false si es el mismo que
+// falló (el evento no está en ningún otro).
+func (s *Scraper) redetectEventHost(ctx context.Context, eventID, failed string) (host string, ok bool) {
+	markSubdomainStale(eventID)
+	host = s.DetectEventSubdomain(ctx, eventID)
+	if host == failed {
+		return "", false
+	}
+	logger.Info("Evento movido de subdominio",
+		zap.String("event_id", eventID),
+		zap.String("from", failed),
+		zap.String("to", host))
+	return host, true
+}
+
+// redirectEventHost devuelve el host al que redirige la página del evento
+// cuando es otro host permitido y sigue siendo el mismo evento, o "" si no
+func (s *Scraper) redirectEventHost(eventURL, location, eventID string) string {
+	base, err := url.Parse(eventURL)
+	if err != nil {
+		return ""
+	}
+	target, err := base.Parse(location)
+	if err != nil || eventPagePath.FindString(target.Path) != "/event/"+eventID {
+		return ""
+	}
+	host := strings.ToLower(target.Hostname())
+	if host == base.Hostname() || !allowedHost(s.config, host) {
+		return ""
+	}
+	return host
+}
+
+// eventHost devuelve el host (subdominio de federación) de un evento: el
+// guardado en event_subdomains mientras siga vigente, el de eventURL si
+// viene, el de la URL guardada del evento, o el detectado. Así los scrapes de
+// inscriptos, llaves y resultados usan el mismo subdominio que el listado o
+// el detalle ya encontraron; si responde 404 se vuelve a detectar
+// (redetectEventHost).
+func (s *Scraper) eventHost(ctx context.Context, eventID, eventURL string) string {
+	if cached, fresh := s.cachedEventSubdomain(eventID); fresh {
+		return cached.Host
+	}
+	if eventURL == "" {
+		var event models.Event
+		config.GetDB().Select("event_url").Where("external_id = ?", eventID).Limit(1).Find(&event)
+		eventURL = event.EventURL
+	}
+	if eventURL != "" {
+		return ExtractSubdomainFromURL(eventURL)
+	}
+	return s.DetectEventSubdomain(ctx, eventID)
 }
 
 // ExtractSubdomainFromURL extrae el subdominio de una URL de evento
@@ -123,6 +270,11 @@ func BuildAPIURL(subdomain string, eventID string) string {
 	return fmt.Sprintf("https://%s/en/event/%s/participants", subdomain, eventID)
 }
 
+// BuildEventCardURL devuelve la URL de la credencial (PDF) de una inscripción
+func BuildEventCardURL(subdomain string, eventID string, registrationID int64) string {
+	return fmt.Sprintf("https://%s/en/event/%s/registration/%d/eventcard", subdomain, eventID, registrationID)
+}
+
 // containsSubstring verifica si str contiene substr (helper function)
 func containsSubstring(str, substr string) bool {
 	return len(str) >= len(substr) && (str == substr ||
@@ -130,41 +282,11 @@ func containsSubstring(str, substr string) bool {
 			str[len(str)-len(substr):] == substr))
 }
 
-// ScrapeEventAthletesWithSubdomainDetection es una versión mejorada que detecta el subdominio
-func (s *Scraper) ScrapeEventAthletesWithSubdomainDetection(eventID string, eventName string, eventURL string) error {
-	var subdomain string
-
-	// Opción 1: Si tenemos la URL del evento, extraer el subdominio
-	if eventURL != "" {
-		subdomain = ExtractSubdomainFromURL(eventURL)
-		logger.Info("Subdominio extraído de URL",
-			zap.String("subdomain", subdomain),
-			zap.String("event_url", eventURL))
-	} else {
-		// Opción 2: Detectar automáticamente probando diferentes subdominios
-		subdomain = s.DetectEventSubdomain(eventID)
-	}
-
-	// Construir la URL de la API con el subdominio correcto
-	apiURL := BuildAPIURL(subdomain, eventID)
-
-	logger.Info("Iniciando scraping de atletas del evento via API",
-		zap.String("event_id", eventID),
-		zap.String("event_name", eventName),
-		zap.String("api_url", apiURL),
-		zap.String("subdomain", subdomain))
-
-	// Aquí va el resto del código de ScrapeEventAthletes...
-	// (El mismo código del archivo anterior, pero usando apiURL en lugar de construir la URL)
-
-	return fmt.Errorf("implementación completa en athlete_event_scraper_v2.go")
-}
-
 // TestSubdomainDetection es una función de utilidad para testing
-func (s *Scraper) TestSubdomainDetection(eventID string) {
+func (s *Scraper) TestSubdomainDetection(ctx context.Context, eventID string) {
 	logger.Info("=== TEST: Detección de Subdominio ===")
 
-	subdomain := s.DetectEventSubdomain(eventID)
+	subdomain := s.DetectEventSubdomain(ctx, eventID)
 	apiURL := BuildAPIURL(subdomain, eventID)
 
 	logger.Info("Resultado del test",
@@ -173,8 +295,8 @@ func (s *Scraper) TestSubdomainDetection(eventID string) {
 		zap.String("api_url", apiURL))
 
 	// Intentar hacer un request de prueba
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, _ := http.NewRequest("POST", apiURL, nil)
+	client := s.newHTTPClient(10 * time.Second)
+	req, _ := http.NewRequestWithContext(ctx, "POST", apiURL, nil)
 	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
 	req.Header.Set("Accept", "application/json")
 
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/internal/notify"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+)
+
+// notifySubscribers pushes newly discovered events to every enabled
+// subscriber whose filter matches them, one notification per subscriber
+func (s *Scraper) notifySubscribers(events []models.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	db := config.GetDB()
+	var subscribers []models.NotificationSubscriber
+	if err := db.Where("enabled = ?", true).Find(&subscribers).Error; err != nil {
+		logger.Error("Failed to load notification subscribers", zap.Error(err))
+		return
+	}
+	if len(subscribers) == 0 {
+		return
+	}
+
+	facts := make([]notify.EventFacts, len(events))
+	for i, event := range events {
+		var organizer string
+		if event.ExternalID != "" {
+			db.Model(&models.EventDetail{}).Where("event_id = ?", event.ExternalID).
+				Limit(1).Pluck("organizer_name", &organizer)
+		}
+		facts[i] = notify.FactsFromEvent(event, organizer)
+	}
+
+	for _, subscriber := range subscribers {
+		filter, err := notify.ParseFilter(subscriber.Filter)
+		if err != nil {
+			logger.Warn("Skipping subscriber with invalid filter",
+				zap.Int("subscriber_id", subscriber.ID),
+				zap.Error(err))
+			continue
+		}
+
+		var matched []models.Event
+		for i, event := range events {
+			if filter.Matches(facts[i]) {
+				matched = append(matched, event)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		n := notify.Notification{
+			Kind:   "new_events",
+			Title:  fmt.Sprintf("%d new events", len(matched)),
+			Text:   newEventsText(matched),
+			Data:   matched,
+			SentAt: time.Now(),
+		}
+		webhook := notify.NewWebhook(subscriber.WebhookURL, s.config.Notifications.Timeout)
+		if err := webhook.Send(n); err != nil {
+			logger.Warn("Failed to notify subscriber",
+				zap.Int("subscriber_id", subscriber.ID),
+				zap.String("channel", webhook.Name()),
+				zap.Error(err))
+			continue
+		}
+
+		logger.Info("Subscriber notified",
+			zap.Int("subscriber_id", subscriber.ID),
+			zap.Int("events", len(matched)))
+	}
+}
+
+func newEventsText(events []models.Event) string {
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		line := fmt.Sprintf("New event: %s %s", event.Name, event.DateText)
+		if venue := venueText(event); venue != "" {
+			line += " (" + venue + ")"
+		}
+		lines = append(lines, strings.TrimSpace(line)+" "+urlnorm.EventURL(event.EventURL))
+	}
+	return strings.Join(lines, "\n")
+}
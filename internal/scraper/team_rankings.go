@@ -0,0 +1,226 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const teamRankingRowSelector = ".ranking-row, .club-ranking tr, table tr, li"
+
+var (
+	federationPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	leadingRank       = regexp.MustCompile(`^\s*#?(\d+)`)
+	pointsPattern     = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+)
+
+// NormalizeFederation lowercases a federation subdomain ("AJP" -> "ajp")
+// and rejects anything that is not a single host label
+func NormalizeFederation(federation string) (string, error) {
+	federation = strings.ToLower(strings.TrimSpace(federation))
+	federation = strings.TrimSuffix(federation, ".smoothcomp.com")
+	if !federationPattern.MatchString(federation) {
+		return "", fmt.Errorf("invalid federation %q (expected a smoothcomp subdomain such as ajp)", federation)
+	}
+	return federation, nil
+}
+
+// BuildTeamRankingURL returns the club ranking page of a federation
+// subdomain, for season or the current one when empty
+func BuildTeamRankingURL(federation, season string) string {
+	rankingURL := fmt.Sprintf("https://%s.smoothcomp.com/en/ranking/clubs", federation)
+	if season != "" {
+		rankingURL += "?season=" + url.QueryEscape(season)
+	}
+	return rankingURL
+}
+
+// ScrapeTeamRankings fetches the club ranking of a federation and stores
+// the standings that changed since the previous scrape. Without a season
+// the one selected on the page is used. Returns how many entries were saved.
+func (s *Scraper) ScrapeTeamRankings(ctx context.Context, federation, season string) (int, error) {
+	federation, err := NormalizeFederation(federation)
+	if err != nil {
+		return 0, err
+	}
+	rankingURL := BuildTeamRankingURL(federation, season)
+
+	client := s.newHTTPClient(20 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", rankingURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.config.Scraper.UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching %s: %w", rankingURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("team ranking page returned status %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing team ranking page: %w", err)
+	}
+
+	if season == "" {
+		season = pageSeason(doc)
+	}
+	entries := s.parseTeamRankings(doc)
+	saved, err := SaveTeamRankings(federation, season, entries)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("Team rankings scraped",
+		zap.String("federation", federation),
+		zap.String("season", season),
+		zap.Int("teams", len(entries)),
+		zap.Int("changed", saved))
+
+	return saved, nil
+}
+
+// pageSeason reads the season selected on a ranking page, falling back to
+// the current year
+func pageSeason(doc *goquery.Document) string {
+	season := strings.TrimSpace(doc.Find("select[name='season'] option[selected]").First().Text())
+	if season == "" {
+		season = strings.TrimSpace(doc.Find(".season-name, .ranking-season").First().Text())
+	}
+	if season == "" {
+		season = strconv.Itoa(time.Now().Year())
+	}
+	return strings.Join(strings.Fields(season), " ")
+}
+
+// parseTeamRankings reads the ranking rows of a club ranking page. Each row
+// links the club page and shows its rank and points.
+func (s *Scraper) parseTeamRankings(doc *goquery.Document) []models.TeamRankingEntry {
+	var entries []models.TeamRankingEntry
+	seen := make(map[string]bool)
+
+	doc.Find(teamRankingRowSelector).Each(func(_ int, row *goquery.Selection) {
+		if row.Find(teamRankingRowSelector).Length() > 0 {
+			return // a wrapper; its inner rows are visited on their own
+		}
+		club := row.Find("a[href*='/club/']").First()
+		href, ok := club.Attr("href")
+		if !ok {
+			return
+		}
+		m := clubIDPattern.FindStringSubmatch(href)
+		if m == nil || seen[m[1]] {
+			return
+		}
+		seen[m[1]] = true
+
+		entry := models.TeamRankingEntry{
+			AcademyExternalID: m[1],
+			AcademyName:       strings.Join(strings.Fields(club.Text()), " "),
+			Rank:              rowRank(row),
+			Points:            rowPoints(row),
+		}
+		if entry.Rank == 0 {
+			entry.Rank = len(entries) + 1 // listed in ranking order
+		}
+
+		s.observeFields("team_ranking", map[string]bool{
+			"name":   entry.AcademyName != "",
+			"points": entry.Points != 0,
+		})
+		entries = append(entries, entry)
+	})
+
+	return entries
+}
+
+// rowRank reads the rank of a ranking row from its rank element or its
+// leading number
+func rowRank(row *goquery.Selection) int {
+	text := strings.TrimSpace(row.Find(".rank, .position, .place").First().Text())
+	if text == "" {
+		text = strings.TrimSpace(row.Children().First().Text())
+	}
+	if m := leadingRank.FindStringSubmatch(text); m != nil {
+		rank, _ := strconv.Atoi(m[1])
+		return rank
+	}
+	return 0
+}
+
+// rowPoints reads the points of a ranking row from its points element or
+// its last cell
+func rowPoints(row *goquery.Selection) float64 {
+	text := strings.TrimSpace(row.Find(".points, .score").First().Text())
+	if text == "" {
+		text = strings.TrimSpace(row.Children().Last().Text())
+	}
+	m := pointsPattern.FindString(text)
+	if m == "" {
+		return 0
+	}
+	points, _ := strconv.ParseFloat(strings.ReplaceAll(m, ",", "."), 64)
+	return points
+}
+
+// SaveTeamRankings stores the entries of a federation season whose rank or
+// points differ from the latest stored entry of the academy, linking them to
+// stored academies. Returns how many were saved.
+func SaveTeamRankings(federation, season string, entries []models.TeamRankingEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	saved := 0
+	err := config.GetDB().Transaction(func(tx *gorm.DB) error {
+		for i := range entries {
+			entry := &entries[i]
+			entry.Federation = federation
+			entry.Season = season
+			entry.CapturedAt = now
+
+			var previous models.TeamRankingEntry
+			found := tx.Where("federation = ? AND season = ? AND academy_external_id = ?",
+				federation, season, entry.AcademyExternalID).
+				Order("captured_at DESC, id DESC").
+				Limit(1).Find(&previous).RowsAffected > 0
+			if found && previous.SameStanding(*entry) {
+				continue
+			}
+
+			var academy models.Academy
+			if tx.Select("id").Where("external_id = ?", entry.AcademyExternalID).
+				Limit(1).Find(&academy).RowsAffected > 0 {
+				entry.AcademyID = uint(academy.ID)
+			}
+
+			if err := tx.Create(entry).Error; err != nil {
+				return fmt.Errorf("error saving team ranking: %w", err)
+			}
+			saved++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return saved, nil
+}
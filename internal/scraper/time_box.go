@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/bus"
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// RunOptions bound a job run in time and let it continue a previous run
+type RunOptions struct {
+	MaxDuration time.Duration // 0 runs until done
+	Resume      *ResumePoint  // where a previous partial run stopped
+	Debug       bool          // record outbound requests and responses
+	Behavior    *Behavior     // request pacing, retries and proxy; nil uses the environment
+}
+
+// ResumePoint is the checkpoint of a partial job, handed to clients as an
+// opaque resume token
+type ResumePoint struct {
+	JobType     string `json:"job_type"`
+	EventType   string `json:"event_type,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Depth       string `json:"depth,omitempty"`
+	Position    int    `json:"position,omitempty"` // items of the listing already processed
+	AfterID     int    `json:"after_id,omitempty"` // last athlete ID processed
+	Remaining   int    `json:"remaining,omitempty"`
+	OnlyMissing bool   `json:"only_missing,omitempty"`
+}
+
+// Token encodes the resume point
+func (p ResumePoint) Token() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseResumeToken decodes a token issued for a job of jobType
+func ParseResumeToken(token string, jobType string) (*ResumePoint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resume token")
+	}
+	var point ResumePoint
+	if err := json.Unmarshal(data, &point); err != nil {
+		return nil, fmt.Errorf("invalid resume token")
+	}
+	if point.JobType != jobType {
+		return nil, fmt.Errorf("resume token belongs to a %s job", point.JobType)
+	}
+	return &point, nil
+}
+
+// timeBox tells a job when its max duration is over
+type timeBox struct {
+	until time.Time
+}
+
+func newTimeBox(maxDuration time.Duration) timeBox {
+	if maxDuration <= 0 {
+		return timeBox{}
+	}
+	return timeBox{until: time.Now().Add(maxDuration)}
+}
+
+func (t timeBox) exceeded() bool {
+	return !t.until.IsZero() && time.Now().After(t.until)
+}
+
+// partialJob marks a job stopped by its max duration, storing where to resume
+func (s *Scraper) partialJob(job *models.ScrapeJob, point ResumePoint) {
+	now := time.Now()
+	job.Status = "partial"
+	job.CompletedAt = &now
+	job.ResumeToken = point.Token()
+
+	config.GetDB().Save(job)
+	bus.PublishJob(bus.JobFinished, job)
+
+	logger.Info("Scrape job stopped at max duration",
+		zap.Int("job_id", job.ID),
+		zap.Int("items_scraped", job.ItemsScraped),
+		zap.Int("max_duration_seconds", job.MaxDuration))
+}
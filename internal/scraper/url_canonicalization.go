@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"fmt"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/urlnorm"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CanonicalizeStoredURLs rewrites stored profile, club and event URLs into
+// their canonical form and removes event rows whose only difference was the
+// URL form. It is idempotent and safe to run on every startup.
+func CanonicalizeStoredURLs() error {
+	db := config.GetDB()
+
+	rewritten, err := canonicalizeColumn(db, &models.Athlete{}, "profile_url", urlnorm.ProfileURL)
+	if err != nil {
+		return fmt.Errorf("failed to canonicalize athlete profile urls: %w", err)
+	}
+	academies, err := canonicalizeColumn(db, &models.Academy{}, "club_url", urlnorm.ClubURL)
+	if err != nil {
+		return fmt.Errorf("failed to canonicalize club urls: %w", err)
+	}
+	details, err := canonicalizeColumn(db, &models.EventDetail{}, "event_url", urlnorm.EventURL)
+	if err != nil {
+		return fmt.Errorf("failed to canonicalize event detail urls: %w", err)
+	}
+	merged, err := dedupeEventURLs(db)
+	if err != nil {
+		return fmt.Errorf("failed to deduplicate events: %w", err)
+	}
+
+	if rewritten+academies+details+merged > 0 {
+		logger.Info("Stored URLs canonicalized",
+			zap.Int("athletes", rewritten),
+			zap.Int("academies", academies),
+			zap.Int("event_details", details),
+			zap.Int("events_merged", merged))
+	}
+
+	return nil
+}
+
+type urlRow struct {
+	ID  int
+	URL string
+}
+
+// canonicalizeColumn rewrites a URL column in place for tables where the URL
+// is not a unique key
+func canonicalizeColumn(db *gorm.DB, model interface{}, column string, canonical func(string) string) (int, error) {
+	var rows []urlRow
+	err := db.Model(model).
+		Select("id, " + column + " AS url").
+		Where(column + " <> ''").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		normalized := canonical(row.URL)
+		if normalized == row.URL {
+			continue
+		}
+		if err := db.Model(model).Where("id = ?", row.ID).UpdateColumn(column, normalized).Error; err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// dedupeEventURLs canonicalizes event URLs. When several rows collapse into
+// the same canonical URL, the oldest row is kept and receives the data of
+// the most recently scraped duplicate.
+func dedupeEventURLs(db *gorm.DB) (int, error) {
+	var events []models.Event
+	if err := db.Order("id ASC").Find(&events).Error; err != nil {
+		return 0, err
+	}
+
+	groups := make(map[string][]models.Event)
+	order := make([]string, 0, len(events))
+	for _, event := range events {
+		normalized := urlnorm.EventURL(event.EventURL)
+		if _, ok := groups[normalized]; !ok {
+			order = append(order, normalized)
+		}
+		groups[normalized] = append(groups[normalized], event)
+	}
+
+	merged := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, normalized := range order {
+			group := groups[normalized]
+			kept := group[0]
+			latest := group[len(group)-1]
+
+			if len(group) == 1 && kept.EventURL == normalized {
+				continue
+			}
+
+			for _, duplicate := range group[1:] {
+				if err := tx.Delete(&models.Event{}, duplicate.ID).Error; err != nil {
+					return err
+				}
+				merged++
+			}
+
+			latest.ID = kept.ID
+			latest.CreatedAt = kept.CreatedAt
+			latest.EventURL = normalized
+			if err := tx.Save(&latest).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return merged, err
+}
@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// NormalizeStoredWeightClasses fills the normalized weight class of
+// registrations stored before weight classes were parsed, and their style
+// when the division or weight class label names it. It is idempotent and
+// safe to run on every startup.
+func NormalizeStoredWeightClasses() error {
+	db := config.GetDB()
+
+	type categoryRow struct {
+		Division    string
+		WeightClass string
+	}
+	var categories []categoryRow
+	if err := db.Model(&models.EventRegistration{}).Distinct("division", "weight_class").
+		Where("(weight_class_key = '' OR weight_class_key IS NULL) AND weight_class <> ''").
+		Scan(&categories).Error; err != nil {
+		return err
+	}
+
+	registrations := int64(0)
+	for _, category := range categories {
+		class := models.ParseWeightClass(category.WeightClass)
+		if class.Key == "" {
+			continue
+		}
+		updates := map[string]interface{}{
+			"weight_class_key": class.Key,
+			"weight_min_kg":    class.MinKg,
+			"weight_max_kg":    class.MaxKg,
+		}
+		if style := models.ParseStyle(category.Division + " / " + category.WeightClass); style != models.StyleUnknown {
+			updates["style"] = style
+		}
+		result := db.Model(&models.EventRegistration{}).
+			Where("division = ? AND weight_class = ? AND (weight_class_key = '' OR weight_class_key IS NULL)", category.Division, category.WeightClass).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		registrations += result.RowsAffected
+	}
+
+	if registrations > 0 {
+		logger.Info("Stored weight classes normalized", zap.Int64("registrations", registrations))
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package scraper
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WriteFunc is a unit of work executed inside a batched transaction.
+// It must be safe to run more than once: when a batch fails, its writes are
+// retried one by one to isolate the failing entity.
+type WriteFunc func(tx *gorm.DB) error
+
+type writeOp struct {
+	fn   WriteFunc
+	done chan error
+}
+
+// WriteBuffer coalesces upserts from concurrent scraper workers into batched
+// transactions. A batch is flushed when it reaches maxBatch operations or
+// when flushInterval elapses. The queue is bounded, so Submit blocks while
+// the database lags behind (backpressure) instead of buffering without limit.
+type WriteBuffer struct {
+	ops           chan writeOp
+	maxBatch      int
+	flushInterval time.Duration
+
+	mu     sync.RWMutex // guards closed against Submit sending on ops
+	closed bool
+	done   chan struct{}
+}
+
+// ErrWriteBufferClosed is returned for writes submitted after Close
+var ErrWriteBufferClosed = errors.New("write buffer closed")
+
+var (
+	sharedWrites     *WriteBuffer
+	sharedWritesOnce sync.Once
+)
+
+// getSharedWrites returns the process-wide write buffer, so every Scraper
+// instance (API handler, scheduler, queue) batches into the same transactions
+func getSharedWrites(cfg *config.Config) *WriteBuffer {
+	sharedWritesOnce.Do(func() {
+		sharedWrites = NewWriteBuffer(
+			cfg.Database.WriteBatchSize,
+			cfg.Database.WriteFlushInterval,
+			cfg.Database.WriteQueueSize,
+		)
+	})
+	return sharedWrites
+}
+
+// CloseWrites commits the writes still queued in the process-wide buffer;
+// call it on shutdown once jobs have stopped, before closing the database
+func CloseWrites() {
+	sharedWritesOnce.Do(func() {})
+	if sharedWrites != nil {
+		sharedWrites.Close()
+	}
+}
+
+// NewWriteBuffer creates and starts a write buffer
+func NewWriteBuffer(maxBatch int, flushInterval time.Duration, queueSize int) *WriteBuffer {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+	if flushInterval <= 0 {
+		flushInterval = 500 * time.Millisecond
+	}
+	if queueSize < maxBatch {
+		queueSize = maxBatch
+	}
+
+	b := &WriteBuffer{
+		ops:           make(chan writeOp, queueSize),
+		maxBatch:      maxBatch,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Submit queues a write and returns a channel that receives its result once
+// the batch containing it has been committed (or rolled back).
+func (b *WriteBuffer) Submit(fn WriteFunc) <-chan error {
+	op := writeOp{fn: fn, done: make(chan error, 1)}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		op.done <- ErrWriteBufferClosed
+		return op.done
+	}
+
+	select {
+	case b.ops <- op:
+	default:
+		start := time.Now()
+		b.ops <- op
+		logger.Debug("Write buffer full, producer throttled",
+			zap.Duration("waited", time.Since(start)))
+	}
+
+	return op.done
+}
+
+// Write queues a write and waits for its result
+func (b *WriteBuffer) Write(fn WriteFunc) error {
+	return <-b.Submit(fn)
+}
+
+// Close stops accepting writes and returns once the queued ones are
+// committed. Later writes fail with ErrWriteBufferClosed.
+func (b *WriteBuffer) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.ops)
+	}
+	b.mu.Unlock()
+	<-b.done
+}
+
+func (b *WriteBuffer) run() {
+	defer close(b.done)
+	batch := make([]writeOp, 0, b.maxBatch)
+	timer := time.NewTimer(b.flushInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case op, ok := <-b.ops:
+			if !ok {
+				if len(batch) > 0 {
+					b.flush(batch)
+				}
+				return
+			}
+			batch = append(batch, op)
+			if len(batch) >= b.maxBatch {
+				b.flush(batch)
+				batch = batch[:0]
+			}
+		case <-timer.C:
+			if len(batch) > 0 {
+				b.flush(batch)
+				batch = batch[:0]
+			}
+			timer.Reset(b.flushInterval)
+		}
+	}
+}
+
+func (b *WriteBuffer) flush(batch []writeOp) {
+	db := config.GetDB()
+	start := time.Now()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, op := range batch {
+			if err := op.fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err == nil {
+		for _, op := range batch {
+			op.done <- nil
+		}
+		logger.Debug("Write batch committed",
+			zap.Int("size", len(batch)),
+			zap.Duration("duration", time.Since(start)))
+		return
+	}
+
+	logger.Warn("Write batch failed, retrying writes individually",
+		zap.Int("size", len(batch)),
+		zap.Error(err))
+
+	for _, op := range batch {
+		op.done <- db.Transaction(op.fn)
+	}
+}
@@ -0,0 +1,119 @@
+package youtube
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const searchEndpoint = "https://www.googleapis.com/youtube/v3/search"
+
+// Video is a search result from the YouTube Data API
+type Video struct {
+	VideoID      string
+	ChannelID    string
+	ChannelTitle string
+	Title        string
+	Description  string
+	ThumbnailURL string
+	PublishedAt  time.Time
+}
+
+// Client is a minimal YouTube Data API v3 client
+type Client struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewClient creates a YouTube client
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type searchResponse struct {
+	Items []struct {
+		ID struct {
+			VideoID string `json:"videoId"`
+		} `json:"id"`
+		Snippet struct {
+			ChannelID    string    `json:"channelId"`
+			ChannelTitle string    `json:"channelTitle"`
+			Title        string    `json:"title"`
+			Description  string    `json:"description"`
+			PublishedAt  time.Time `json:"publishedAt"`
+			Thumbnails   map[string]struct {
+				URL string `json:"url"`
+			} `json:"thumbnails"`
+		} `json:"snippet"`
+	} `json:"items"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Search returns videos of channelID matching query. An empty channelID
+// searches all of YouTube.
+func (c *Client) Search(channelID, query string, maxResults int) ([]Video, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("type", "video")
+	params.Set("q", query)
+	params.Set("maxResults", strconv.Itoa(maxResults))
+	params.Set("key", c.apiKey)
+	if channelID != "" {
+		params.Set("channelId", channelID)
+	}
+
+	resp, err := c.client.Get(searchEndpoint + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("error searching youtube: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result searchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding youtube response: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("youtube api error %d: %s", result.Error.Code, result.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("youtube api returned status %d", resp.StatusCode)
+	}
+
+	videos := make([]Video, 0, len(result.Items))
+	for _, item := range result.Items {
+		if item.ID.VideoID == "" {
+			continue
+		}
+		video := Video{
+			VideoID:      item.ID.VideoID,
+			ChannelID:    item.Snippet.ChannelID,
+			ChannelTitle: item.Snippet.ChannelTitle,
+			Title:        item.Snippet.Title,
+			Description:  item.Snippet.Description,
+			PublishedAt:  item.Snippet.PublishedAt,
+		}
+		for _, size := range []string{"high", "medium", "default"} {
+			if thumb, ok := item.Snippet.Thumbnails[size]; ok {
+				video.ThumbnailURL = thumb.URL
+				break
+			}
+		}
+		videos = append(videos, video)
+	}
+
+	return videos, nil
+}
+
+// WatchURL returns the public URL of a video
+func WatchURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
@@ -0,0 +1,176 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kmicac/smoothcomp-scraper/internal/config"
+	"github.com/kmicac/smoothcomp-scraper/internal/models"
+	"github.com/kmicac/smoothcomp-scraper/pkg/logger"
+	"github.com/kmicac/smoothcomp-scraper/pkg/slug"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotConfigured is returned when no YouTube API key is set
+var ErrNotConfigured = errors.New("youtube api key not configured")
+
+// Linker searches configured channels for recordings of stored matches
+type Linker struct {
+	config *config.YouTubeConfig
+	client *Client
+}
+
+// NewLinker creates a match video linker
+func NewLinker(cfg *config.Config) *Linker {
+	return &Linker{
+		config: &cfg.YouTube,
+		client: NewClient(cfg.YouTube.APIKey),
+	}
+}
+
+// LinkMatch searches for videos of a match, stores candidates scoring at least
+// the configured minimum confidence and returns all videos linked to it.
+// Reviewed videos (confirmed/rejected) keep their status.
+func (l *Linker) LinkMatch(match models.Match) ([]models.MatchVideo, error) {
+	if l.config.APIKey == "" {
+		return nil, ErrNotConfigured
+	}
+	if match.AthleteAName == "" || match.AthleteBName == "" {
+		return nil, fmt.Errorf("match %d has no competitor names", match.ID)
+	}
+
+	query := fmt.Sprintf("%s vs %s", match.AthleteAName, match.AthleteBName)
+	if match.EventName != "" {
+		query += " " + match.EventName
+	}
+
+	channels := l.config.Channels
+	if len(channels) == 0 {
+		channels = []string{""}
+	}
+
+	found := make(map[string]models.MatchVideo)
+	for _, channelID := range channels {
+		videos, err := l.client.Search(channelID, query, l.config.MaxResults)
+		if err != nil {
+			logger.Warn("YouTube search failed",
+				zap.String("channel_id", channelID),
+				zap.Int("match_id", match.ID),
+				zap.Error(err))
+			continue
+		}
+
+		for _, video := range videos {
+			confidence := Score(match, video)
+			if confidence < l.config.MinConfidence {
+				continue
+			}
+			if existing, ok := found[video.VideoID]; ok && existing.Confidence >= confidence {
+				continue
+			}
+			found[video.VideoID] = models.MatchVideo{
+				MatchID:      match.ID,
+				VideoID:      video.VideoID,
+				ChannelID:    video.ChannelID,
+				ChannelTitle: video.ChannelTitle,
+				Title:        video.Title,
+				URL:          WatchURL(video.VideoID),
+				ThumbnailURL: video.ThumbnailURL,
+				PublishedAt:  video.PublishedAt,
+				Confidence:   confidence,
+				Status:       "candidate",
+			}
+		}
+	}
+
+	db := config.GetDB()
+	for _, video := range found {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "match_id"}, {Name: "video_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "channel_title", "thumbnail_url", "confidence", "updated_at"}),
+		}).Create(&video).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to save match video: %w", err)
+		}
+	}
+
+	logger.Info("Match videos linked",
+		zap.Int("match_id", match.ID),
+		zap.Int("candidates", len(found)))
+
+	return LoadMatchVideos(match.ID)
+}
+
+// LoadMatchVideos returns the non-rejected videos of a match, best first
+func LoadMatchVideos(matchID int) ([]models.MatchVideo, error) {
+	var videos []models.MatchVideo
+	err := config.GetDB().
+		Where("match_id = ? AND status <> ?", matchID, "rejected").
+		Order("confidence DESC").
+		Find(&videos).Error
+	return videos, err
+}
+
+// Score estimates how likely a video is a recording of the match, from 0 to 1.
+// Each competitor found in the title is worth 0.4 (0.3 if only the last name
+// matches) and the event name adds up to 0.2.
+func Score(match models.Match, video Video) float64 {
+	title := normalize(video.Title)
+	text := title + " " + normalize(video.Description)
+
+	score := nameScore(match.AthleteAName, title) + nameScore(match.AthleteBName, title)
+	if match.EventName != "" {
+		score += 0.2 * tokenOverlap(normalize(match.EventName), text)
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return float64(int(score*100+0.5)) / 100
+}
+
+func nameScore(name, title string) float64 {
+	tokens := strings.Fields(normalize(name))
+	if len(tokens) == 0 {
+		return 0
+	}
+	if containsWord(title, strings.Join(tokens, " ")) {
+		return 0.4
+	}
+	if containsWord(title, tokens[0]) && containsWord(title, tokens[len(tokens)-1]) {
+		return 0.4
+	}
+	if containsWord(title, tokens[len(tokens)-1]) {
+		return 0.3
+	}
+	return 0
+}
+
+// tokenOverlap is the share of significant words of phrase present in text
+func tokenOverlap(phrase, text string) float64 {
+	var total, matched int
+	for _, token := range strings.Fields(phrase) {
+		if len(token) < 3 {
+			continue
+		}
+		total++
+		if containsWord(text, token) {
+			matched++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+func containsWord(text, word string) bool {
+	return strings.Contains(" "+text+" ", " "+word+" ")
+}
+
+// normalize lowercases, strips accents and replaces punctuation with spaces
+func normalize(s string) string {
+	return strings.ReplaceAll(slug.Make(s), "-", " ")
+}
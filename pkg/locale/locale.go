@@ -0,0 +1,117 @@
+// Package locale renders derived text (country names, belt names and dates)
+// in the languages the API serves: English, Spanish and Portuguese.
+package locale
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// Lang is a supported response language
+type Lang string
+
+// Supported languages
+const (
+	English    Lang = "en"
+	Spanish    Lang = "es"
+	Portuguese Lang = "pt"
+)
+
+// Parse returns the language for a lang parameter such as "es" or "pt-BR"
+func Parse(s string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	switch Lang(base) {
+	case English, Spanish, Portuguese:
+		return Lang(base), true
+	}
+	return "", false
+}
+
+// dictionaries only links the CLDR tables of the supported languages
+var dictionaries = map[Lang]*display.Dictionary{
+	English:    display.English,
+	Spanish:    display.Spanish,
+	Portuguese: display.Portuguese,
+}
+
+// CountryName returns the name of an ISO 3166-1 alpha-2 country code, e.g.
+// "Brasil" for "BR" in Spanish, or "" for unknown codes
+func CountryName(lang Lang, code string) string {
+	dictionary, ok := dictionaries[lang]
+	if !ok || len(code) != 2 {
+		return ""
+	}
+	region, err := language.ParseRegion(strings.ToUpper(code))
+	if err != nil || !region.IsCountry() {
+		return ""
+	}
+	return dictionary.Regions().Name(region)
+}
+
+// beltColors holds each belt color in English, Spanish and Portuguese
+var beltColors = map[string]map[Lang]string{
+	"white":  {English: "White", Spanish: "Blanco", Portuguese: "Branca"},
+	"grey":   {English: "Grey", Spanish: "Gris", Portuguese: "Cinza"},
+	"yellow": {English: "Yellow", Spanish: "Amarillo", Portuguese: "Amarela"},
+	"orange": {English: "Orange", Spanish: "Naranja", Portuguese: "Laranja"},
+	"green":  {English: "Green", Spanish: "Verde", Portuguese: "Verde"},
+	"blue":   {English: "Blue", Spanish: "Azul", Portuguese: "Azul"},
+	"purple": {English: "Purple", Spanish: "Violeta", Portuguese: "Roxa"},
+	"brown":  {English: "Brown", Spanish: "Marrón", Portuguese: "Marrom"},
+	"black":  {English: "Black", Spanish: "Negro", Portuguese: "Preta"},
+}
+
+// BeltName translates a belt as Smoothcomp lists it: "Blue belt" becomes
+// "Cinturón azul" in Spanish and "Faixa azul" in Portuguese, a bare "Blue"
+// (registration ranks) becomes "Azul". Other values, such as "Beginner",
+// are returned unchanged.
+func BeltName(lang Lang, belt string) string {
+	words := strings.Fields(strings.ToLower(belt))
+	if len(words) == 0 || len(words) > 2 || (len(words) == 2 && words[1] != "belt") {
+		return belt
+	}
+	color := words[0]
+	if color == "gray" {
+		color = "grey"
+	}
+	names, ok := beltColors[color]
+	if !ok {
+		return belt
+	}
+	name, ok := names[lang]
+	if !ok {
+		return belt
+	}
+	if len(words) == 1 {
+		return name
+	}
+
+	switch lang {
+	case Spanish:
+		return "Cinturón " + strings.ToLower(name)
+	case Portuguese:
+		return "Faixa " + strings.ToLower(name)
+	}
+	return name + " belt"
+}
+
+var months = map[Lang][12]string{
+	Spanish: {"enero", "febrero", "marzo", "abril", "mayo", "junio",
+		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	Portuguese: {"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+		"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+}
+
+// FormatDate writes a date the way lang reads it: "May 4, 2025",
+// "4 de mayo de 2025" or "4 de maio de 2025"
+func FormatDate(lang Lang, t time.Time) string {
+	names, ok := months[lang]
+	if !ok {
+		return t.Format("January 2, 2006")
+	}
+	return fmt.Sprintf("%d de %s de %d", t.Day(), names[t.Month()-1], t.Year())
+}
@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -9,22 +12,27 @@ import (
 
 var Log *zap.Logger
 
-// InitLogger initializes the global logger
-func InitLogger(level string) error {
-	var zapLevel zapcore.Level
+// level is shared by the core so it can change without rebuilding the logger
+var level = zap.NewAtomicLevel()
 
-	switch level {
-	case "debug":
-		zapLevel = zapcore.DebugLevel
-	case "info":
-		zapLevel = zapcore.InfoLevel
-	case "warn":
-		zapLevel = zapcore.WarnLevel
-	case "error":
-		zapLevel = zapcore.ErrorLevel
-	default:
+// Temporary level overrides revert to baseLevel when they expire
+var (
+	levelMu        sync.Mutex
+	baseLevel      = zapcore.InfoLevel
+	overrideTimer  *time.Timer
+	overrideExpiry *time.Time
+)
+
+// InitLogger initializes the global logger
+func InitLogger(levelName string) error {
+	zapLevel, err := ParseLevel(levelName)
+	if err != nil {
 		zapLevel = zapcore.InfoLevel
 	}
+	levelMu.Lock()
+	baseLevel = zapLevel
+	level.SetLevel(zapLevel)
+	levelMu.Unlock()
 
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "timestamp"
@@ -34,7 +42,7 @@ func InitLogger(level string) error {
 	core := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(encoderConfig),
 		zapcore.AddSync(os.Stdout),
-		zapLevel,
+		level,
 	)
 
 	Log = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
@@ -42,6 +50,96 @@ func InitLogger(level string) error {
 	return nil
 }
 
+// ParseLevel maps a LOG_LEVEL name (debug, info, warn, error) to a zap level
+func ParseLevel(levelName string) (zapcore.Level, error) {
+	switch levelName {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	return zapcore.InfoLevel, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", levelName)
+}
+
+// SetLevel changes the default level of the global logger at runtime,
+// dropping any temporary override
+func SetLevel(levelName string) error {
+	zapLevel, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	stopOverride()
+	baseLevel = zapLevel
+	level.SetLevel(zapLevel)
+	return nil
+}
+
+// OverrideLevel switches the global logger to levelName. With ttl > 0 the
+// default level comes back after ttl; otherwise the override stays until
+// the next SetLevel or OverrideLevel call.
+func OverrideLevel(levelName string, ttl time.Duration) error {
+	zapLevel, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	stopOverride()
+	level.SetLevel(zapLevel)
+	if ttl > 0 {
+		expiry := time.Now().Add(ttl)
+		overrideExpiry = &expiry
+		overrideTimer = time.AfterFunc(ttl, func() {
+			levelMu.Lock()
+			defer levelMu.Unlock()
+			if overrideExpiry == nil || !overrideExpiry.Equal(expiry) {
+				return // replaced by a newer override
+			}
+			overrideTimer, overrideExpiry = nil, nil
+			level.SetLevel(baseLevel)
+			Log.Info("Log level override expired", zap.String("level", baseLevel.String()))
+		})
+	}
+	return nil
+}
+
+func stopOverride() {
+	if overrideTimer != nil {
+		overrideTimer.Stop()
+	}
+	overrideTimer, overrideExpiry = nil, nil
+}
+
+// Level returns the current level of the global logger
+func Level() string {
+	return level.Level().String()
+}
+
+// DefaultLevel returns the level restored when an override expires
+func DefaultLevel() string {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	return baseLevel.String()
+}
+
+// LevelExpiry returns when the current override expires, nil when the
+// current level is the default or the override has no expiry
+func LevelExpiry() *time.Time {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	return overrideExpiry
+}
+
 // Info logs an info message
 func Info(msg string, fields ...zap.Field) {
 	Log.Info(msg, fields...)
@@ -0,0 +1,105 @@
+package names
+
+import "strings"
+
+// particles belong to the surname that follows them
+// (Maria de la Cruz, João dos Santos, Ludwig van der Berg)
+var particles = map[string]bool{
+	"de": true, "del": true, "della": true, "di": true, "da": true, "das": true,
+	"do": true, "dos": true, "du": true, "la": true, "las": true, "los": true,
+	"le": true, "van": true, "von": true, "der": true, "den": true, "ter": true,
+	"y": true, "e": true, "st.": true, "san": true, "santa": true,
+}
+
+// IsParticle reports whether word is a surname particle such as "de" or "dos"
+func IsParticle(word string) bool {
+	return particles[strings.ToLower(word)]
+}
+
+// Split derives first and last names from a full name. Particles stay glued
+// to the word after them, so "Maria de la Cruz García" splits into "Maria"
+// and "de la Cruz García". With three name units the first one is the given
+// name (one given name and two surnames is the usual Hispanic/Portuguese
+// form); with four or more the first two are.
+func Split(full string) (first, last string) {
+	units := nameUnits(strings.Fields(full))
+
+	given := 1
+	if len(units) >= 4 {
+		given = 2
+	}
+	if len(units) <= given {
+		return strings.Join(units, " "), ""
+	}
+	return strings.Join(units[:given], " "), strings.Join(units[given:], " ")
+}
+
+// Parse returns the first and last names of full. The source first/last
+// fields are kept when they are consistent with full (middle names in
+// between are fine), moving trailing particles from the first name to the
+// surname ("Maria de la" + "Cruz"); otherwise full is split with Split.
+func Parse(full, first, last string) (string, string) {
+	full = strings.Join(strings.Fields(full), " ")
+	first = strings.Join(strings.Fields(first), " ")
+	last = strings.Join(strings.Fields(last), " ")
+
+	if full == "" {
+		return first, last
+	}
+	if first == "" || last == "" || !consistent(full, first, last) {
+		return Split(full)
+	}
+
+	words := strings.Fields(first)
+	moved := 0
+	for moved < len(words)-1 && IsParticle(words[len(words)-1-moved]) {
+		moved++
+	}
+	if moved > 0 {
+		cut := len(words) - moved
+		last = strings.Join(words[cut:], " ") + " " + last
+		first = strings.Join(words[:cut], " ")
+	}
+	return first, last
+}
+
+// consistent reports whether full starts with first and ends with last,
+// compared word by word and without case
+func consistent(full, first, last string) bool {
+	fullWords := strings.Fields(strings.ToLower(full))
+	firstWords := strings.Fields(strings.ToLower(first))
+	lastWords := strings.Fields(strings.ToLower(last))
+	if len(firstWords)+len(lastWords) > len(fullWords) {
+		return false
+	}
+
+	for i, word := range firstWords {
+		if fullWords[i] != word {
+			return false
+		}
+	}
+	offset := len(fullWords) - len(lastWords)
+	for i, word := range lastWords {
+		if fullWords[offset+i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// nameUnits groups words so that particles join the word that follows them
+func nameUnits(words []string) []string {
+	var units []string
+	pending := ""
+
+	for i, word := range words {
+		// A trailing particle has nothing to attach to and stands alone
+		if IsParticle(word) && i < len(words)-1 && i > 0 {
+			pending += word + " "
+			continue
+		}
+		units = append(units, pending+word)
+		pending = ""
+	}
+	return units
+}
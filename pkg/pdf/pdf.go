@@ -0,0 +1,159 @@
+// Package pdf writes simple text-and-line PDF documents using the standard
+// Helvetica fonts, enough for printable sheets without external dependencies.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document is a PDF being built page by page. Coordinates are in points
+// from the top-left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page; drawing calls go to the latest page
+func (d *Document) AddPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+}
+
+// Text draws s with its baseline at (x, y). Characters outside Windows-1252
+// are replaced with "?".
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(s))
+}
+
+// Line draws a straight line of the given width
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// TextWidth estimates the width of s in points. Helvetica glyphs average a
+// little over half the font size.
+func TextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * 0.52
+}
+
+// Fit shortens s with an ellipsis so it fits in width points
+func Fit(s string, size, width float64) string {
+	if TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if d.page == nil {
+		d.AddPage()
+	}
+	return d.page
+}
+
+// Bytes serializes the document
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// 1 catalog, 2 page tree, 3-4 fonts, then a page and a content stream per page
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2))
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// escape encodes s as Windows-1252 and escapes PDF string delimiters
+func escape(s string) string {
+	var b strings.Builder
+	encoder := charmap.Windows1252.NewEncoder()
+	for _, r := range s {
+		switch r {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+			continue
+		}
+		if r < 0x20 {
+			b.WriteByte(' ')
+			continue
+		}
+		if r < 0x80 {
+			b.WriteRune(r)
+			continue
+		}
+		encoded, err := encoder.Bytes([]byte(string(r)))
+		if err != nil || len(encoded) != 1 {
+			b.WriteByte('?')
+			continue
+		}
+		fmt.Fprintf(&b, "\\%03o", encoded[0])
+	}
+	return b.String()
+}
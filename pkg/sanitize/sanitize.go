@@ -0,0 +1,35 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// policy keeps basic text formatting and http(s)/mailto links. Scripts,
+// styles, iframes, event handlers and inline CSS are dropped.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "b", "strong", "i", "em", "u", "s",
+		"ul", "ol", "li", "blockquote", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}()
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// HTML returns s with unsafe markup removed. Plain text passes through
+// unchanged apart from HTML escaping of <, > and &.
+func HTML(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	s = policy.Sanitize(s)
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
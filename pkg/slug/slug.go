@@ -0,0 +1,54 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letters that do not decompose into an ASCII base letter plus marks
+var special = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i",
+}
+
+// Fold lowercases s and transliterates accented letters (João Conceição ->
+// joao conceicao), leaving every other character as it is
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case special[r] != "":
+			b.WriteString(special[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Make returns a lowercase ASCII slug of s. Accented letters are
+// transliterated (João Conceição -> joao-conceicao) and every other run of
+// non-alphanumeric characters becomes a single dash.
+func Make(s string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range Fold(s) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		default:
+			if b.Len() > 0 && !dash {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
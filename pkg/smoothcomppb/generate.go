@@ -0,0 +1,5 @@
+// Package smoothcomppb holds the Go code generated from
+// proto/smoothcomp/v1/smoothcomp.proto, for clients of the gRPC service.
+package smoothcomppb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/kmicac/smoothcomp-scraper --go-grpc_out=../.. --go-grpc_opt=module=github.com/kmicac/smoothcomp-scraper smoothcomp/v1/smoothcomp.proto
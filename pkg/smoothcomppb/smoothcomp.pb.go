@@ -0,0 +1,1896 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: smoothcomp/v1/smoothcomp.proto
+
+// gRPC interface of the scraper: the stored athletes, academies and events,
+// and the scrape jobs that fill them. Reads mirror the /api/v1 endpoints,
+// including the masking of minors.
+
+package smoothcomppb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Athlete struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId         string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	FirstName          string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName           string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	FullName           string                 `protobuf:"bytes,5,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Slug               string                 `protobuf:"bytes,6,opt,name=slug,proto3" json:"slug,omitempty"`
+	AcademyExternalId  string                 `protobuf:"bytes,7,opt,name=academy_external_id,json=academyExternalId,proto3" json:"academy_external_id,omitempty"`
+	Nationality        string                 `protobuf:"bytes,8,opt,name=nationality,proto3" json:"nationality,omitempty"`
+	CountryCode        string                 `protobuf:"bytes,9,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	BeltRank           string                 `protobuf:"bytes,10,opt,name=belt_rank,json=beltRank,proto3" json:"belt_rank,omitempty"`
+	Age                int32                  `protobuf:"varint,11,opt,name=age,proto3" json:"age,omitempty"`
+	Gender             string                 `protobuf:"bytes,12,opt,name=gender,proto3" json:"gender,omitempty"` // male, female
+	ProfileUrl         string                 `protobuf:"bytes,13,opt,name=profile_url,json=profileUrl,proto3" json:"profile_url,omitempty"`
+	ImageUrl           string                 `protobuf:"bytes,14,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	BirthYear          int32                  `protobuf:"varint,15,opt,name=birth_year,json=birthYear,proto3" json:"birth_year,omitempty"`
+	TotalWins          int32                  `protobuf:"varint,16,opt,name=total_wins,json=totalWins,proto3" json:"total_wins,omitempty"`
+	WinsBySubmission   int32                  `protobuf:"varint,17,opt,name=wins_by_submission,json=winsBySubmission,proto3" json:"wins_by_submission,omitempty"`
+	WinsByPoints       int32                  `protobuf:"varint,18,opt,name=wins_by_points,json=winsByPoints,proto3" json:"wins_by_points,omitempty"`
+	WinsByDecision     int32                  `protobuf:"varint,19,opt,name=wins_by_decision,json=winsByDecision,proto3" json:"wins_by_decision,omitempty"`
+	WinsByDq           int32                  `protobuf:"varint,20,opt,name=wins_by_dq,json=winsByDq,proto3" json:"wins_by_dq,omitempty"`
+	TotalLosses        int32                  `protobuf:"varint,21,opt,name=total_losses,json=totalLosses,proto3" json:"total_losses,omitempty"`
+	LossesBySubmission int32                  `protobuf:"varint,22,opt,name=losses_by_submission,json=lossesBySubmission,proto3" json:"losses_by_submission,omitempty"`
+	LossesByPoints     int32                  `protobuf:"varint,23,opt,name=losses_by_points,json=lossesByPoints,proto3" json:"losses_by_points,omitempty"`
+	LossesByDecision   int32                  `protobuf:"varint,24,opt,name=losses_by_decision,json=lossesByDecision,proto3" json:"losses_by_decision,omitempty"`
+	LossesByDq         int32                  `protobuf:"varint,25,opt,name=losses_by_dq,json=lossesByDq,proto3" json:"losses_by_dq,omitempty"`
+	ScrapedAt          *timestamppb.Timestamp `protobuf:"bytes,26,opt,name=scraped_at,json=scrapedAt,proto3" json:"scraped_at,omitempty"`
+	FirstSeenAt        *timestamppb.Timestamp `protobuf:"bytes,27,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	LastActiveAt       *timestamppb.Timestamp `protobuf:"bytes,28,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"` // unset when unknown
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Athlete) Reset() {
+	*x = Athlete{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Athlete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Athlete) ProtoMessage() {}
+
+func (x *Athlete) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Athlete.ProtoReflect.Descriptor instead.
+func (*Athlete) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Athlete) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Athlete) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Athlete) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Athlete) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Athlete) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Athlete) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Athlete) GetAcademyExternalId() string {
+	if x != nil {
+		return x.AcademyExternalId
+	}
+	return ""
+}
+
+func (x *Athlete) GetNationality() string {
+	if x != nil {
+		return x.Nationality
+	}
+	return ""
+}
+
+func (x *Athlete) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Athlete) GetBeltRank() string {
+	if x != nil {
+		return x.BeltRank
+	}
+	return ""
+}
+
+func (x *Athlete) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Athlete) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *Athlete) GetProfileUrl() string {
+	if x != nil {
+		return x.ProfileUrl
+	}
+	return ""
+}
+
+func (x *Athlete) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Athlete) GetBirthYear() int32 {
+	if x != nil {
+		return x.BirthYear
+	}
+	return 0
+}
+
+func (x *Athlete) GetTotalWins() int32 {
+	if x != nil {
+		return x.TotalWins
+	}
+	return 0
+}
+
+func (x *Athlete) GetWinsBySubmission() int32 {
+	if x != nil {
+		return x.WinsBySubmission
+	}
+	return 0
+}
+
+func (x *Athlete) GetWinsByPoints() int32 {
+	if x != nil {
+		return x.WinsByPoints
+	}
+	return 0
+}
+
+func (x *Athlete) GetWinsByDecision() int32 {
+	if x != nil {
+		return x.WinsByDecision
+	}
+	return 0
+}
+
+func (x *Athlete) GetWinsByDq() int32 {
+	if x != nil {
+		return x.WinsByDq
+	}
+	return 0
+}
+
+func (x *Athlete) GetTotalLosses() int32 {
+	if x != nil {
+		return x.TotalLosses
+	}
+	return 0
+}
+
+func (x *Athlete) GetLossesBySubmission() int32 {
+	if x != nil {
+		return x.LossesBySubmission
+	}
+	return 0
+}
+
+func (x *Athlete) GetLossesByPoints() int32 {
+	if x != nil {
+		return x.LossesByPoints
+	}
+	return 0
+}
+
+func (x *Athlete) GetLossesByDecision() int32 {
+	if x != nil {
+		return x.LossesByDecision
+	}
+	return 0
+}
+
+func (x *Athlete) GetLossesByDq() int32 {
+	if x != nil {
+		return x.LossesByDq
+	}
+	return 0
+}
+
+func (x *Athlete) GetScrapedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScrapedAt
+	}
+	return nil
+}
+
+func (x *Athlete) GetFirstSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return nil
+}
+
+func (x *Athlete) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+type Academy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	ClubUrl       string                 `protobuf:"bytes,5,opt,name=club_url,json=clubUrl,proto3" json:"club_url,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,7,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	LogoUrl       string                 `protobuf:"bytes,8,opt,name=logo_url,json=logoUrl,proto3" json:"logo_url,omitempty"`
+	Website       string                 `protobuf:"bytes,9,opt,name=website,proto3" json:"website,omitempty"`
+	TotalWins     int32                  `protobuf:"varint,10,opt,name=total_wins,json=totalWins,proto3" json:"total_wins,omitempty"`
+	TotalLosses   int32                  `protobuf:"varint,11,opt,name=total_losses,json=totalLosses,proto3" json:"total_losses,omitempty"`
+	AthleteCount  int32                  `protobuf:"varint,12,opt,name=athlete_count,json=athleteCount,proto3" json:"athlete_count,omitempty"`
+	GoldMedals    int32                  `protobuf:"varint,13,opt,name=gold_medals,json=goldMedals,proto3" json:"gold_medals,omitempty"`
+	SilverMedals  int32                  `protobuf:"varint,14,opt,name=silver_medals,json=silverMedals,proto3" json:"silver_medals,omitempty"`
+	BronzeMedals  int32                  `protobuf:"varint,15,opt,name=bronze_medals,json=bronzeMedals,proto3" json:"bronze_medals,omitempty"`
+	ScrapedAt     *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=scraped_at,json=scrapedAt,proto3" json:"scraped_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Academy) Reset() {
+	*x = Academy{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Academy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Academy) ProtoMessage() {}
+
+func (x *Academy) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Academy.ProtoReflect.Descriptor instead.
+func (*Academy) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Academy) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Academy) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Academy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Academy) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Academy) GetClubUrl() string {
+	if x != nil {
+		return x.ClubUrl
+	}
+	return ""
+}
+
+func (x *Academy) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Academy) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Academy) GetLogoUrl() string {
+	if x != nil {
+		return x.LogoUrl
+	}
+	return ""
+}
+
+func (x *Academy) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+func (x *Academy) GetTotalWins() int32 {
+	if x != nil {
+		return x.TotalWins
+	}
+	return 0
+}
+
+func (x *Academy) GetTotalLosses() int32 {
+	if x != nil {
+		return x.TotalLosses
+	}
+	return 0
+}
+
+func (x *Academy) GetAthleteCount() int32 {
+	if x != nil {
+		return x.AthleteCount
+	}
+	return 0
+}
+
+func (x *Academy) GetGoldMedals() int32 {
+	if x != nil {
+		return x.GoldMedals
+	}
+	return 0
+}
+
+func (x *Academy) GetSilverMedals() int32 {
+	if x != nil {
+		return x.SilverMedals
+	}
+	return 0
+}
+
+func (x *Academy) GetBronzeMedals() int32 {
+	if x != nil {
+		return x.BronzeMedals
+	}
+	return 0
+}
+
+func (x *Academy) GetScrapedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScrapedAt
+	}
+	return nil
+}
+
+type Event struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId       string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	EventUrl         string                 `protobuf:"bytes,4,opt,name=event_url,json=eventUrl,proto3" json:"event_url,omitempty"`
+	ImageUrl         string                 `protobuf:"bytes,5,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	City             string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	Country          string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	CountryCode      string                 `protobuf:"bytes,8,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	DateText         string                 `protobuf:"bytes,9,opt,name=date_text,json=dateText,proto3" json:"date_text,omitempty"`
+	StartDate        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"` // unset when the listing had no date
+	EventType        string                 `protobuf:"bytes,11,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Section          string                 `protobuf:"bytes,12,opt,name=section,proto3" json:"section,omitempty"`
+	ResultsPublished bool                   `protobuf:"varint,13,opt,name=results_published,json=resultsPublished,proto3" json:"results_published,omitempty"`
+	ScrapedAt        *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=scraped_at,json=scrapedAt,proto3" json:"scraped_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Event) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Event) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Event) GetEventUrl() string {
+	if x != nil {
+		return x.EventUrl
+	}
+	return ""
+}
+
+func (x *Event) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Event) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Event) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Event) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Event) GetDateText() string {
+	if x != nil {
+		return x.DateText
+	}
+	return ""
+}
+
+func (x *Event) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Event) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *Event) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Event) GetResultsPublished() bool {
+	if x != nil {
+		return x.ResultsPublished
+	}
+	return false
+}
+
+func (x *Event) GetScrapedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScrapedAt
+	}
+	return nil
+}
+
+type ScrapeJob struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ParentJobId    int64                  `protobuf:"varint,2,opt,name=parent_job_id,json=parentJobId,proto3" json:"parent_job_id,omitempty"` // 0 unless the job is a chunk of a split job
+	JobType        string                 `protobuf:"bytes,3,opt,name=job_type,json=jobType,proto3" json:"job_type,omitempty"`
+	Depth          string                 `protobuf:"bytes,4,opt,name=depth,proto3" json:"depth,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed, partial, failed, cancelled, postponed
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	ItemsScraped   int32                  `protobuf:"varint,8,opt,name=items_scraped,json=itemsScraped,proto3" json:"items_scraped,omitempty"`
+	PagesFailed    int32                  `protobuf:"varint,9,opt,name=pages_failed,json=pagesFailed,proto3" json:"pages_failed,omitempty"`
+	CurrentPhase   string                 `protobuf:"bytes,10,opt,name=current_phase,json=currentPhase,proto3" json:"current_phase,omitempty"`
+	ItemsTotal     int32                  `protobuf:"varint,11,opt,name=items_total,json=itemsTotal,proto3" json:"items_total,omitempty"`
+	ItemsProcessed int32                  `protobuf:"varint,12,opt,name=items_processed,json=itemsProcessed,proto3" json:"items_processed,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,13,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Country        string                 `protobuf:"bytes,14,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScrapeJob) Reset() {
+	*x = ScrapeJob{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrapeJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrapeJob) ProtoMessage() {}
+
+func (x *ScrapeJob) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrapeJob.ProtoReflect.Descriptor instead.
+func (*ScrapeJob) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ScrapeJob) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetParentJobId() int64 {
+	if x != nil {
+		return x.ParentJobId
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetJobType() string {
+	if x != nil {
+		return x.JobType
+	}
+	return ""
+}
+
+func (x *ScrapeJob) GetDepth() string {
+	if x != nil {
+		return x.Depth
+	}
+	return ""
+}
+
+func (x *ScrapeJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScrapeJob) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ScrapeJob) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *ScrapeJob) GetItemsScraped() int32 {
+	if x != nil {
+		return x.ItemsScraped
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetPagesFailed() int32 {
+	if x != nil {
+		return x.PagesFailed
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetCurrentPhase() string {
+	if x != nil {
+		return x.CurrentPhase
+	}
+	return ""
+}
+
+func (x *ScrapeJob) GetItemsTotal() int32 {
+	if x != nil {
+		return x.ItemsTotal
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetItemsProcessed() int32 {
+	if x != nil {
+		return x.ItemsProcessed
+	}
+	return 0
+}
+
+func (x *ScrapeJob) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ScrapeJob) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+// QueuedJob is a scrape waiting in the job queue; it starts a ScrapeJob
+// when a worker picks it
+type QueuedJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	JobType       string                 `protobuf:"bytes,2,opt,name=job_type,json=jobType,proto3" json:"job_type,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // queued, running, postponed, completed, failed, cancelled
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	MaxAttempts   int32                  `protobuf:"varint,5,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	RunAfter      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=run_after,json=runAfter,proto3" json:"run_after,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueuedJob) Reset() {
+	*x = QueuedJob{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueuedJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueuedJob) ProtoMessage() {}
+
+func (x *QueuedJob) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueuedJob.ProtoReflect.Descriptor instead.
+func (*QueuedJob) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueuedJob) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *QueuedJob) GetJobType() string {
+	if x != nil {
+		return x.JobType
+	}
+	return ""
+}
+
+func (x *QueuedJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *QueuedJob) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *QueuedJob) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *QueuedJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *QueuedJob) GetRunAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RunAfter
+	}
+	return nil
+}
+
+func (x *QueuedJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ScrapeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// academies, all, events_past, events_upcoming, event_athletes,
+	// event_brackets, event_results, team_rankings, academy_stats or
+	// country_roster
+	JobType       string `protobuf:"bytes,1,opt,name=job_type,json=jobType,proto3" json:"job_type,omitempty"`
+	Country       string `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`                      // events_*, country_roster; events_* default to AR
+	Depth         string `protobuf:"bytes,3,opt,name=depth,proto3" json:"depth,omitempty"`                          // events_*: listing, details, participants, profiles or brackets
+	EventId       string `protobuf:"bytes,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`       // event_*, required
+	EventName     string `protobuf:"bytes,5,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"` // event_athletes
+	EventUrl      string `protobuf:"bytes,6,opt,name=event_url,json=eventUrl,proto3" json:"event_url,omitempty"`    // event_*
+	Federation    string `protobuf:"bytes,7,opt,name=federation,proto3" json:"federation,omitempty"`                // team_rankings, required
+	Season        string `protobuf:"bytes,8,opt,name=season,proto3" json:"season,omitempty"`                        // team_rankings; empty for the current one
+	AcademyId     string `protobuf:"bytes,9,opt,name=academy_id,json=academyId,proto3" json:"academy_id,omitempty"` // academy_stats; empty for every academy
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScrapeRequest) Reset() {
+	*x = ScrapeRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrapeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrapeRequest) ProtoMessage() {}
+
+func (x *ScrapeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrapeRequest.ProtoReflect.Descriptor instead.
+func (*ScrapeRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScrapeRequest) GetJobType() string {
+	if x != nil {
+		return x.JobType
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetDepth() string {
+	if x != nil {
+		return x.Depth
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetEventUrl() string {
+	if x != nil {
+		return x.EventUrl
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetFederation() string {
+	if x != nil {
+		return x.Federation
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetSeason() string {
+	if x != nil {
+		return x.Season
+	}
+	return ""
+}
+
+func (x *ScrapeRequest) GetAcademyId() string {
+	if x != nil {
+		return x.AcademyId
+	}
+	return ""
+}
+
+// GetRequest names a record by external ID or slug
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Pages start at 1; limit defaults to 100 and is capped at 1000
+type ListAthletesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	AcademyId     string                 `protobuf:"bytes,4,opt,name=academy_id,json=academyId,proto3" json:"academy_id,omitempty"`
+	Gender        string                 `protobuf:"bytes,5,opt,name=gender,proto3" json:"gender,omitempty"`
+	Filter        string                 `protobuf:"bytes,6,opt,name=filter,proto3" json:"filter,omitempty"` // same expressions as ?filter= of GET /api/v1/athletes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAthletesRequest) Reset() {
+	*x = ListAthletesRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAthletesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAthletesRequest) ProtoMessage() {}
+
+func (x *ListAthletesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAthletesRequest.ProtoReflect.Descriptor instead.
+func (*ListAthletesRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListAthletesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAthletesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAthletesRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ListAthletesRequest) GetAcademyId() string {
+	if x != nil {
+		return x.AcademyId
+	}
+	return ""
+}
+
+func (x *ListAthletesRequest) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *ListAthletesRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type ListAthletesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Athletes      []*Athlete             `protobuf:"bytes,1,rep,name=athletes,proto3" json:"athletes,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAthletesResponse) Reset() {
+	*x = ListAthletesResponse{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAthletesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAthletesResponse) ProtoMessage() {}
+
+func (x *ListAthletesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAthletesResponse.ProtoReflect.Descriptor instead.
+func (*ListAthletesResponse) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListAthletesResponse) GetAthletes() []*Athlete {
+	if x != nil {
+		return x.Athletes
+	}
+	return nil
+}
+
+func (x *ListAthletesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAthletesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAthletesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ListAcademiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAcademiesRequest) Reset() {
+	*x = ListAcademiesRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAcademiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAcademiesRequest) ProtoMessage() {}
+
+func (x *ListAcademiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAcademiesRequest.ProtoReflect.Descriptor instead.
+func (*ListAcademiesRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListAcademiesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAcademiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAcademiesRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type ListAcademiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Academies     []*Academy             `protobuf:"bytes,1,rep,name=academies,proto3" json:"academies,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAcademiesResponse) Reset() {
+	*x = ListAcademiesResponse{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAcademiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAcademiesResponse) ProtoMessage() {}
+
+func (x *ListAcademiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAcademiesResponse.ProtoReflect.Descriptor instead.
+func (*ListAcademiesResponse) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListAcademiesResponse) GetAcademies() []*Academy {
+	if x != nil {
+		return x.Academies
+	}
+	return nil
+}
+
+func (x *ListAcademiesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListAcademiesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAcademiesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ListEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Filter        string                 `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"` // same expressions as ?filter= of GET /api/v1/events
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListEventsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type ListEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListEventsResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListEventsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListEventsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListEventsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetScrapeJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScrapeJobRequest) Reset() {
+	*x = GetScrapeJobRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScrapeJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScrapeJobRequest) ProtoMessage() {}
+
+func (x *GetScrapeJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScrapeJobRequest.ProtoReflect.Descriptor instead.
+func (*GetScrapeJobRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetScrapeJobRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListScrapeJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	JobType       string                 `protobuf:"bytes,4,opt,name=job_type,json=jobType,proto3" json:"job_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScrapeJobsRequest) Reset() {
+	*x = ListScrapeJobsRequest{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScrapeJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScrapeJobsRequest) ProtoMessage() {}
+
+func (x *ListScrapeJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScrapeJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListScrapeJobsRequest) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListScrapeJobsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListScrapeJobsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListScrapeJobsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListScrapeJobsRequest) GetJobType() string {
+	if x != nil {
+		return x.JobType
+	}
+	return ""
+}
+
+type ListScrapeJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*ScrapeJob           `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScrapeJobsResponse) Reset() {
+	*x = ListScrapeJobsResponse{}
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScrapeJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScrapeJobsResponse) ProtoMessage() {}
+
+func (x *ListScrapeJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smoothcomp_v1_smoothcomp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScrapeJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListScrapeJobsResponse) Descriptor() ([]byte, []int) {
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListScrapeJobsResponse) GetJobs() []*ScrapeJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListScrapeJobsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListScrapeJobsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListScrapeJobsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_smoothcomp_v1_smoothcomp_proto protoreflect.FileDescriptor
+
+const file_smoothcomp_v1_smoothcomp_proto_rawDesc = "" +
+	"\n" +
+	"\x1esmoothcomp/v1/smoothcomp.proto\x12\rsmoothcomp.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x87\b\n" +
+	"\aAthlete\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x02 \x01(\tR\n" +
+	"externalId\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x1b\n" +
+	"\tfull_name\x18\x05 \x01(\tR\bfullName\x12\x12\n" +
+	"\x04slug\x18\x06 \x01(\tR\x04slug\x12.\n" +
+	"\x13academy_external_id\x18\a \x01(\tR\x11academyExternalId\x12 \n" +
+	"\vnationality\x18\b \x01(\tR\vnationality\x12!\n" +
+	"\fcountry_code\x18\t \x01(\tR\vcountryCode\x12\x1b\n" +
+	"\tbelt_rank\x18\n" +
+	" \x01(\tR\bbeltRank\x12\x10\n" +
+	"\x03age\x18\v \x01(\x05R\x03age\x12\x16\n" +
+	"\x06gender\x18\f \x01(\tR\x06gender\x12\x1f\n" +
+	"\vprofile_url\x18\r \x01(\tR\n" +
+	"profileUrl\x12\x1b\n" +
+	"\timage_url\x18\x0e \x01(\tR\bimageUrl\x12\x1d\n" +
+	"\n" +
+	"birth_year\x18\x0f \x01(\x05R\tbirthYear\x12\x1d\n" +
+	"\n" +
+	"total_wins\x18\x10 \x01(\x05R\ttotalWins\x12,\n" +
+	"\x12wins_by_submission\x18\x11 \x01(\x05R\x10winsBySubmission\x12$\n" +
+	"\x0ewins_by_points\x18\x12 \x01(\x05R\fwinsByPoints\x12(\n" +
+	"\x10wins_by_decision\x18\x13 \x01(\x05R\x0ewinsByDecision\x12\x1c\n" +
+	"\n" +
+	"wins_by_dq\x18\x14 \x01(\x05R\bwinsByDq\x12!\n" +
+	"\ftotal_losses\x18\x15 \x01(\x05R\vtotalLosses\x120\n" +
+	"\x14losses_by_submission\x18\x16 \x01(\x05R\x12lossesBySubmission\x12(\n" +
+	"\x10losses_by_points\x18\x17 \x01(\x05R\x0elossesByPoints\x12,\n" +
+	"\x12losses_by_decision\x18\x18 \x01(\x05R\x10lossesByDecision\x12 \n" +
+	"\flosses_by_dq\x18\x19 \x01(\x05R\n" +
+	"lossesByDq\x129\n" +
+	"\n" +
+	"scraped_at\x18\x1a \x01(\v2\x1a.google.protobuf.TimestampR\tscrapedAt\x12>\n" +
+	"\rfirst_seen_at\x18\x1b \x01(\v2\x1a.google.protobuf.TimestampR\vfirstSeenAt\x12@\n" +
+	"\x0elast_active_at\x18\x1c \x01(\v2\x1a.google.protobuf.TimestampR\flastActiveAt\"\xfc\x03\n" +
+	"\aAcademy\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x02 \x01(\tR\n" +
+	"externalId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\x12\x19\n" +
+	"\bclub_url\x18\x05 \x01(\tR\aclubUrl\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\x12!\n" +
+	"\fcountry_code\x18\a \x01(\tR\vcountryCode\x12\x19\n" +
+	"\blogo_url\x18\b \x01(\tR\alogoUrl\x12\x18\n" +
+	"\awebsite\x18\t \x01(\tR\awebsite\x12\x1d\n" +
+	"\n" +
+	"total_wins\x18\n" +
+	" \x01(\x05R\ttotalWins\x12!\n" +
+	"\ftotal_losses\x18\v \x01(\x05R\vtotalLosses\x12#\n" +
+	"\rathlete_count\x18\f \x01(\x05R\fathleteCount\x12\x1f\n" +
+	"\vgold_medals\x18\r \x01(\x05R\n" +
+	"goldMedals\x12#\n" +
+	"\rsilver_medals\x18\x0e \x01(\x05R\fsilverMedals\x12#\n" +
+	"\rbronze_medals\x18\x0f \x01(\x05R\fbronzeMedals\x129\n" +
+	"\n" +
+	"scraped_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tscrapedAt\"\xd0\x03\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x02 \x01(\tR\n" +
+	"externalId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
+	"\tevent_url\x18\x04 \x01(\tR\beventUrl\x12\x1b\n" +
+	"\timage_url\x18\x05 \x01(\tR\bimageUrl\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\x12!\n" +
+	"\fcountry_code\x18\b \x01(\tR\vcountryCode\x12\x1b\n" +
+	"\tdate_text\x18\t \x01(\tR\bdateText\x129\n" +
+	"\n" +
+	"start_date\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\v \x01(\tR\teventType\x12\x18\n" +
+	"\asection\x18\f \x01(\tR\asection\x12+\n" +
+	"\x11results_published\x18\r \x01(\bR\x10resultsPublished\x129\n" +
+	"\n" +
+	"scraped_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tscrapedAt\"\xf8\x03\n" +
+	"\tScrapeJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\"\n" +
+	"\rparent_job_id\x18\x02 \x01(\x03R\vparentJobId\x12\x19\n" +
+	"\bjob_type\x18\x03 \x01(\tR\ajobType\x12\x14\n" +
+	"\x05depth\x18\x04 \x01(\tR\x05depth\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12#\n" +
+	"\ritems_scraped\x18\b \x01(\x05R\fitemsScraped\x12!\n" +
+	"\fpages_failed\x18\t \x01(\x05R\vpagesFailed\x12#\n" +
+	"\rcurrent_phase\x18\n" +
+	" \x01(\tR\fcurrentPhase\x12\x1f\n" +
+	"\vitems_total\x18\v \x01(\x05R\n" +
+	"itemsTotal\x12'\n" +
+	"\x0fitems_processed\x18\f \x01(\x05R\x0eitemsProcessed\x12#\n" +
+	"\rerror_message\x18\r \x01(\tR\ferrorMessage\x12\x18\n" +
+	"\acountry\x18\x0e \x01(\tR\acountry\"\xa0\x02\n" +
+	"\tQueuedJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bjob_type\x18\x02 \x01(\tR\ajobType\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12!\n" +
+	"\fmax_attempts\x18\x05 \x01(\x05R\vmaxAttempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x127\n" +
+	"\trun_after\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\brunAfter\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x88\x02\n" +
+	"\rScrapeRequest\x12\x19\n" +
+	"\bjob_type\x18\x01 \x01(\tR\ajobType\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12\x14\n" +
+	"\x05depth\x18\x03 \x01(\tR\x05depth\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\x05 \x01(\tR\teventName\x12\x1b\n" +
+	"\tevent_url\x18\x06 \x01(\tR\beventUrl\x12\x1e\n" +
+	"\n" +
+	"federation\x18\a \x01(\tR\n" +
+	"federation\x12\x16\n" +
+	"\x06season\x18\b \x01(\tR\x06season\x12\x1d\n" +
+	"\n" +
+	"academy_id\x18\t \x01(\tR\tacademyId\"\x1c\n" +
+	"\n" +
+	"GetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa8\x01\n" +
+	"\x13ListAthletesRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x1d\n" +
+	"\n" +
+	"academy_id\x18\x04 \x01(\tR\tacademyId\x12\x16\n" +
+	"\x06gender\x18\x05 \x01(\tR\x06gender\x12\x16\n" +
+	"\x06filter\x18\x06 \x01(\tR\x06filter\"\x8a\x01\n" +
+	"\x14ListAthletesResponse\x122\n" +
+	"\bathletes\x18\x01 \x03(\v2\x16.smoothcomp.v1.AthleteR\bathletes\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"Z\n" +
+	"\x14ListAcademiesRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\"\x8d\x01\n" +
+	"\x15ListAcademiesResponse\x124\n" +
+	"\tacademies\x18\x01 \x03(\v2\x16.smoothcomp.v1.AcademyR\tacademies\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"\x83\x01\n" +
+	"\x11ListEventsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x16\n" +
+	"\x06filter\x18\x05 \x01(\tR\x06filter\"\x82\x01\n" +
+	"\x12ListEventsResponse\x12,\n" +
+	"\x06events\x18\x01 \x03(\v2\x14.smoothcomp.v1.EventR\x06events\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"%\n" +
+	"\x13GetScrapeJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"t\n" +
+	"\x15ListScrapeJobsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
+	"\bjob_type\x18\x04 \x01(\tR\ajobType\"\x86\x01\n" +
+	"\x16ListScrapeJobsResponse\x12,\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x18.smoothcomp.v1.ScrapeJobR\x04jobs\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total2\xc9\x05\n" +
+	"\x11SmoothcompService\x12@\n" +
+	"\x06Scrape\x12\x1c.smoothcomp.v1.ScrapeRequest\x1a\x18.smoothcomp.v1.QueuedJob\x12?\n" +
+	"\n" +
+	"GetAthlete\x12\x19.smoothcomp.v1.GetRequest\x1a\x16.smoothcomp.v1.Athlete\x12W\n" +
+	"\fListAthletes\x12\".smoothcomp.v1.ListAthletesRequest\x1a#.smoothcomp.v1.ListAthletesResponse\x12?\n" +
+	"\n" +
+	"GetAcademy\x12\x19.smoothcomp.v1.GetRequest\x1a\x16.smoothcomp.v1.Academy\x12Z\n" +
+	"\rListAcademies\x12#.smoothcomp.v1.ListAcademiesRequest\x1a$.smoothcomp.v1.ListAcademiesResponse\x12;\n" +
+	"\bGetEvent\x12\x19.smoothcomp.v1.GetRequest\x1a\x14.smoothcomp.v1.Event\x12Q\n" +
+	"\n" +
+	"ListEvents\x12 .smoothcomp.v1.ListEventsRequest\x1a!.smoothcomp.v1.ListEventsResponse\x12L\n" +
+	"\fGetScrapeJob\x12\".smoothcomp.v1.GetScrapeJobRequest\x1a\x18.smoothcomp.v1.ScrapeJob\x12]\n" +
+	"\x0eListScrapeJobs\x12$.smoothcomp.v1.ListScrapeJobsRequest\x1a%.smoothcomp.v1.ListScrapeJobsResponseB7Z5github.com/kmicac/smoothcomp-scraper/pkg/smoothcomppbb\x06proto3"
+
+var (
+	file_smoothcomp_v1_smoothcomp_proto_rawDescOnce sync.Once
+	file_smoothcomp_v1_smoothcomp_proto_rawDescData []byte
+)
+
+func file_smoothcomp_v1_smoothcomp_proto_rawDescGZIP() []byte {
+	file_smoothcomp_v1_smoothcomp_proto_rawDescOnce.Do(func() {
+		file_smoothcomp_v1_smoothcomp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_smoothcomp_v1_smoothcomp_proto_rawDesc), len(file_smoothcomp_v1_smoothcomp_proto_rawDesc)))
+	})
+	return file_smoothcomp_v1_smoothcomp_proto_rawDescData
+}
+
+var file_smoothcomp_v1_smoothcomp_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_smoothcomp_v1_smoothcomp_proto_goTypes = []any{
+	(*Athlete)(nil),                // 0: smoothcomp.v1.Athlete
+	(*Academy)(nil),                // 1: smoothcomp.v1.Academy
+	(*Event)(nil),                  // 2: smoothcomp.v1.Event
+	(*ScrapeJob)(nil),              // 3: smoothcomp.v1.ScrapeJob
+	(*QueuedJob)(nil),              // 4: smoothcomp.v1.QueuedJob
+	(*ScrapeRequest)(nil),          // 5: smoothcomp.v1.ScrapeRequest
+	(*GetRequest)(nil),             // 6: smoothcomp.v1.GetRequest
+	(*ListAthletesRequest)(nil),    // 7: smoothcomp.v1.ListAthletesRequest
+	(*ListAthletesResponse)(nil),   // 8: smoothcomp.v1.ListAthletesResponse
+	(*ListAcademiesRequest)(nil),   // 9: smoothcomp.v1.ListAcademiesRequest
+	(*ListAcademiesResponse)(nil),  // 10: smoothcomp.v1.ListAcademiesResponse
+	(*ListEventsRequest)(nil),      // 11: smoothcomp.v1.ListEventsRequest
+	(*ListEventsResponse)(nil),     // 12: smoothcomp.v1.ListEventsResponse
+	(*GetScrapeJobRequest)(nil),    // 13: smoothcomp.v1.GetScrapeJobRequest
+	(*ListScrapeJobsRequest)(nil),  // 14: smoothcomp.v1.ListScrapeJobsRequest
+	(*ListScrapeJobsResponse)(nil), // 15: smoothcomp.v1.ListScrapeJobsResponse
+	(*timestamppb.Timestamp)(nil),  // 16: google.protobuf.Timestamp
+}
+var file_smoothcomp_v1_smoothcomp_proto_depIdxs = []int32{
+	16, // 0: smoothcomp.v1.Athlete.scraped_at:type_name -> google.protobuf.Timestamp
+	16, // 1: smoothcomp.v1.Athlete.first_seen_at:type_name -> google.protobuf.Timestamp
+	16, // 2: smoothcomp.v1.Athlete.last_active_at:type_name -> google.protobuf.Timestamp
+	16, // 3: smoothcomp.v1.Academy.scraped_at:type_name -> google.protobuf.Timestamp
+	16, // 4: smoothcomp.v1.Event.start_date:type_name -> google.protobuf.Timestamp
+	16, // 5: smoothcomp.v1.Event.scraped_at:type_name -> google.protobuf.Timestamp
+	16, // 6: smoothcomp.v1.ScrapeJob.started_at:type_name -> google.protobuf.Timestamp
+	16, // 7: smoothcomp.v1.ScrapeJob.completed_at:type_name -> google.protobuf.Timestamp
+	16, // 8: smoothcomp.v1.QueuedJob.run_after:type_name -> google.protobuf.Timestamp
+	16, // 9: smoothcomp.v1.QueuedJob.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 10: smoothcomp.v1.ListAthletesResponse.athletes:type_name -> smoothcomp.v1.Athlete
+	1,  // 11: smoothcomp.v1.ListAcademiesResponse.academies:type_name -> smoothcomp.v1.Academy
+	2,  // 12: smoothcomp.v1.ListEventsResponse.events:type_name -> smoothcomp.v1.Event
+	3,  // 13: smoothcomp.v1.ListScrapeJobsResponse.jobs:type_name -> smoothcomp.v1.ScrapeJob
+	5,  // 14: smoothcomp.v1.SmoothcompService.Scrape:input_type -> smoothcomp.v1.ScrapeRequest
+	6,  // 15: smoothcomp.v1.SmoothcompService.GetAthlete:input_type -> smoothcomp.v1.GetRequest
+	7,  // 16: smoothcomp.v1.SmoothcompService.ListAthletes:input_type -> smoothcomp.v1.ListAthletesRequest
+	6,  // 17: smoothcomp.v1.SmoothcompService.GetAcademy:input_type -> smoothcomp.v1.GetRequest
+	9,  // 18: smoothcomp.v1.SmoothcompService.ListAcademies:input_type -> smoothcomp.v1.ListAcademiesRequest
+	6,  // 19: smoothcomp.v1.SmoothcompService.GetEvent:input_type -> smoothcomp.v1.GetRequest
+	11, // 20: smoothcomp.v1.SmoothcompService.ListEvents:input_type -> smoothcomp.v1.ListEventsRequest
+	13, // 21: smoothcomp.v1.SmoothcompService.GetScrapeJob:input_type -> smoothcomp.v1.GetScrapeJobRequest
+	14, // 22: smoothcomp.v1.SmoothcompService.ListScrapeJobs:input_type -> smoothcomp.v1.ListScrapeJobsRequest
+	4,  // 23: smoothcomp.v1.SmoothcompService.Scrape:output_type -> smoothcomp.v1.QueuedJob
+	0,  // 24: smoothcomp.v1.SmoothcompService.GetAthlete:output_type -> smoothcomp.v1.Athlete
+	8,  // 25: smoothcomp.v1.SmoothcompService.ListAthletes:output_type -> smoothcomp.v1.ListAthletesResponse
+	1,  // 26: smoothcomp.v1.SmoothcompService.GetAcademy:output_type -> smoothcomp.v1.Academy
+	10, // 27: smoothcomp.v1.SmoothcompService.ListAcademies:output_type -> smoothcomp.v1.ListAcademiesResponse
+	2,  // 28: smoothcomp.v1.SmoothcompService.GetEvent:output_type -> smoothcomp.v1.Event
+	12, // 29: smoothcomp.v1.SmoothcompService.ListEvents:output_type -> smoothcomp.v1.ListEventsResponse
+	3,  // 30: smoothcomp.v1.SmoothcompService.GetScrapeJob:output_type -> smoothcomp.v1.ScrapeJob
+	15, // 31: smoothcomp.v1.SmoothcompService.ListScrapeJobs:output_type -> smoothcomp.v1.ListScrapeJobsResponse
+	23, // [23:32] is the sub-list for method output_type
+	14, // [14:23] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_smoothcomp_v1_smoothcomp_proto_init() }
+func file_smoothcomp_v1_smoothcomp_proto_init() {
+	if File_smoothcomp_v1_smoothcomp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_smoothcomp_v1_smoothcomp_proto_rawDesc), len(file_smoothcomp_v1_smoothcomp_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_smoothcomp_v1_smoothcomp_proto_goTypes,
+		DependencyIndexes: file_smoothcomp_v1_smoothcomp_proto_depIdxs,
+		MessageInfos:      file_smoothcomp_v1_smoothcomp_proto_msgTypes,
+	}.Build()
+	File_smoothcomp_v1_smoothcomp_proto = out.File
+	file_smoothcomp_v1_smoothcomp_proto_goTypes = nil
+	file_smoothcomp_v1_smoothcomp_proto_depIdxs = nil
+}
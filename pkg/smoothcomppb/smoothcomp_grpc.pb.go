@@ -0,0 +1,431 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: smoothcomp/v1/smoothcomp.proto
+
+// gRPC interface of the scraper: the stored athletes, academies and events,
+// and the scrape jobs that fill them. Reads mirror the /api/v1 endpoints,
+// including the masking of minors.
+
+package smoothcomppb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SmoothcompService_Scrape_FullMethodName         = "/smoothcomp.v1.SmoothcompService/Scrape"
+	SmoothcompService_GetAthlete_FullMethodName     = "/smoothcomp.v1.SmoothcompService/GetAthlete"
+	SmoothcompService_ListAthletes_FullMethodName   = "/smoothcomp.v1.SmoothcompService/ListAthletes"
+	SmoothcompService_GetAcademy_FullMethodName     = "/smoothcomp.v1.SmoothcompService/GetAcademy"
+	SmoothcompService_ListAcademies_FullMethodName  = "/smoothcomp.v1.SmoothcompService/ListAcademies"
+	SmoothcompService_GetEvent_FullMethodName       = "/smoothcomp.v1.SmoothcompService/GetEvent"
+	SmoothcompService_ListEvents_FullMethodName     = "/smoothcomp.v1.SmoothcompService/ListEvents"
+	SmoothcompService_GetScrapeJob_FullMethodName   = "/smoothcomp.v1.SmoothcompService/GetScrapeJob"
+	SmoothcompService_ListScrapeJobs_FullMethodName = "/smoothcomp.v1.SmoothcompService/ListScrapeJobs"
+)
+
+// SmoothcompServiceClient is the client API for SmoothcompService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SmoothcompServiceClient interface {
+	// Scrape queues a scrape job, like the POST /api/v1/scrape/* endpoints
+	Scrape(ctx context.Context, in *ScrapeRequest, opts ...grpc.CallOption) (*QueuedJob, error)
+	GetAthlete(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Athlete, error)
+	ListAthletes(ctx context.Context, in *ListAthletesRequest, opts ...grpc.CallOption) (*ListAthletesResponse, error)
+	GetAcademy(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Academy, error)
+	ListAcademies(ctx context.Context, in *ListAcademiesRequest, opts ...grpc.CallOption) (*ListAcademiesResponse, error)
+	GetEvent(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Event, error)
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	GetScrapeJob(ctx context.Context, in *GetScrapeJobRequest, opts ...grpc.CallOption) (*ScrapeJob, error)
+	ListScrapeJobs(ctx context.Context, in *ListScrapeJobsRequest, opts ...grpc.CallOption) (*ListScrapeJobsResponse, error)
+}
+
+type smoothcompServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSmoothcompServiceClient(cc grpc.ClientConnInterface) SmoothcompServiceClient {
+	return &smoothcompServiceClient{cc}
+}
+
+func (c *smoothcompServiceClient) Scrape(ctx context.Context, in *ScrapeRequest, opts ...grpc.CallOption) (*QueuedJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueuedJob)
+	err := c.cc.Invoke(ctx, SmoothcompService_Scrape_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) GetAthlete(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Athlete, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Athlete)
+	err := c.cc.Invoke(ctx, SmoothcompService_GetAthlete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) ListAthletes(ctx context.Context, in *ListAthletesRequest, opts ...grpc.CallOption) (*ListAthletesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAthletesResponse)
+	err := c.cc.Invoke(ctx, SmoothcompService_ListAthletes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) GetAcademy(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Academy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Academy)
+	err := c.cc.Invoke(ctx, SmoothcompService_GetAcademy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) ListAcademies(ctx context.Context, in *ListAcademiesRequest, opts ...grpc.CallOption) (*ListAcademiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAcademiesResponse)
+	err := c.cc.Invoke(ctx, SmoothcompService_ListAcademies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) GetEvent(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, SmoothcompService_GetEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, SmoothcompService_ListEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) GetScrapeJob(ctx context.Context, in *GetScrapeJobRequest, opts ...grpc.CallOption) (*ScrapeJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScrapeJob)
+	err := c.cc.Invoke(ctx, SmoothcompService_GetScrapeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *smoothcompServiceClient) ListScrapeJobs(ctx context.Context, in *ListScrapeJobsRequest, opts ...grpc.CallOption) (*ListScrapeJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScrapeJobsResponse)
+	err := c.cc.Invoke(ctx, SmoothcompService_ListScrapeJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SmoothcompServiceServer is the server API for SmoothcompService service.
+// All implementations must embed UnimplementedSmoothcompServiceServer
+// for forward compatibility.
+type SmoothcompServiceServer interface {
+	// Scrape queues a scrape job, like the POST /api/v1/scrape/* endpoints
+	Scrape(context.Context, *ScrapeRequest) (*QueuedJob, error)
+	GetAthlete(context.Context, *GetRequest) (*Athlete, error)
+	ListAthletes(context.Context, *ListAthletesRequest) (*ListAthletesResponse, error)
+	GetAcademy(context.Context, *GetRequest) (*Academy, error)
+	ListAcademies(context.Context, *ListAcademiesRequest) (*ListAcademiesResponse, error)
+	GetEvent(context.Context, *GetRequest) (*Event, error)
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	GetScrapeJob(context.Context, *GetScrapeJobRequest) (*ScrapeJob, error)
+	ListScrapeJobs(context.Context, *ListScrapeJobsRequest) (*ListScrapeJobsResponse, error)
+	mustEmbedUnimplementedSmoothcompServiceServer()
+}
+
+// UnimplementedSmoothcompServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSmoothcompServiceServer struct{}
+
+func (UnimplementedSmoothcompServiceServer) Scrape(context.Context, *ScrapeRequest) (*QueuedJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scrape not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) GetAthlete(context.Context, *GetRequest) (*Athlete, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAthlete not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) ListAthletes(context.Context, *ListAthletesRequest) (*ListAthletesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAthletes not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) GetAcademy(context.Context, *GetRequest) (*Academy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAcademy not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) ListAcademies(context.Context, *ListAcademiesRequest) (*ListAcademiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAcademies not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) GetEvent(context.Context, *GetRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) GetScrapeJob(context.Context, *GetScrapeJobRequest) (*ScrapeJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScrapeJob not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) ListScrapeJobs(context.Context, *ListScrapeJobsRequest) (*ListScrapeJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScrapeJobs not implemented")
+}
+func (UnimplementedSmoothcompServiceServer) mustEmbedUnimplementedSmoothcompServiceServer() {}
+func (UnimplementedSmoothcompServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeSmoothcompServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SmoothcompServiceServer will
+// result in compilation errors.
+type UnsafeSmoothcompServiceServer interface {
+	mustEmbedUnimplementedSmoothcompServiceServer()
+}
+
+func RegisterSmoothcompServiceServer(s grpc.ServiceRegistrar, srv SmoothcompServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSmoothcompServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SmoothcompService_ServiceDesc, srv)
+}
+
+func _SmoothcompService_Scrape_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScrapeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).Scrape(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_Scrape_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).Scrape(ctx, req.(*ScrapeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_GetAthlete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).GetAthlete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_GetAthlete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).GetAthlete(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_ListAthletes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAthletesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).ListAthletes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_ListAthletes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).ListAthletes(ctx, req.(*ListAthletesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_GetAcademy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).GetAcademy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_GetAcademy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).GetAcademy(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_ListAcademies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAcademiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).ListAcademies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_ListAcademies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).ListAcademies(ctx, req.(*ListAcademiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).GetEvent(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_ListEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_GetScrapeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScrapeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).GetScrapeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_GetScrapeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).GetScrapeJob(ctx, req.(*GetScrapeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SmoothcompService_ListScrapeJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScrapeJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SmoothcompServiceServer).ListScrapeJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SmoothcompService_ListScrapeJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SmoothcompServiceServer).ListScrapeJobs(ctx, req.(*ListScrapeJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SmoothcompService_ServiceDesc is the grpc.ServiceDesc for SmoothcompService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SmoothcompService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smoothcomp.v1.SmoothcompService",
+	HandlerType: (*SmoothcompServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Scrape",
+			Handler:    _SmoothcompService_Scrape_Handler,
+		},
+		{
+			MethodName: "GetAthlete",
+			Handler:    _SmoothcompService_GetAthlete_Handler,
+		},
+		{
+			MethodName: "ListAthletes",
+			Handler:    _SmoothcompService_ListAthletes_Handler,
+		},
+		{
+			MethodName: "GetAcademy",
+			Handler:    _SmoothcompService_GetAcademy_Handler,
+		},
+		{
+			MethodName: "ListAcademies",
+			Handler:    _SmoothcompService_ListAcademies_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _SmoothcompService_GetEvent_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _SmoothcompService_ListEvents_Handler,
+		},
+		{
+			MethodName: "GetScrapeJob",
+			Handler:    _SmoothcompService_GetScrapeJob_Handler,
+		},
+		{
+			MethodName: "ListScrapeJobs",
+			Handler:    _SmoothcompService_ListScrapeJobs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "smoothcomp/v1/smoothcomp.proto",
+}
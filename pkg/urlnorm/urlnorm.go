@@ -0,0 +1,111 @@
+package urlnorm
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const rootHost = "smoothcomp.com"
+
+var (
+	localePrefix = regexp.MustCompile(`^/[a-z]{2}(?:-[a-z]{2})?(/|$)`)
+	profileID    = regexp.MustCompile(`/profile/(\d+)`)
+	clubID       = regexp.MustCompile(`/club/(\d+)`)
+	eventID      = regexp.MustCompile(`/event/(\d+)`)
+)
+
+// ProfileURL returns the canonical athlete profile URL.
+// Profiles are global on Smoothcomp, so federation subdomains and locale
+// prefixes collapse to https://smoothcomp.com/en/profile/{id}. Links to
+// other sites are only trimmed.
+func ProfileURL(raw string) string {
+	if id := match(profileID, raw); id != "" && onSmoothcomp(raw) {
+		return "https://" + rootHost + "/en/profile/" + id
+	}
+	return Clean(raw)
+}
+
+// ClubURL returns the canonical club (academy) URL. Links to other sites are
+// only trimmed.
+func ClubURL(raw string) string {
+	if id := match(clubID, raw); id != "" && onSmoothcomp(raw) {
+		return "https://" + rootHost + "/en/club/" + id
+	}
+	return Clean(raw)
+}
+
+// EventURL returns the canonical event URL. Events are served from the
+// federation subdomain that hosts them, so the host is kept (minus "www.").
+func EventURL(raw string) string {
+	cleaned := Clean(raw)
+	parsed, err := url.Parse(cleaned)
+	if err != nil || parsed.Host == "" {
+		return cleaned
+	}
+
+	if id := match(eventID, parsed.Path); id != "" {
+		return parsed.Scheme + "://" + parsed.Host + "/en/event/" + id
+	}
+	return cleaned
+}
+
+// Clean normalizes scheme, host, locale prefix, query and trailing slash of
+// a smoothcomp.com URL. Non-smoothcomp URLs are only trimmed.
+func Clean(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	} else if !strings.Contains(raw, "://") && strings.Contains(strings.ToLower(raw), rootHost) {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return raw
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if !isSmoothcompHost(host) {
+		return raw
+	}
+	host = strings.TrimPrefix(host, "www.")
+
+	path := parsed.Path
+	if localePrefix.MatchString(path) {
+		path = localePrefix.ReplaceAllString(path, "/en$1")
+	} else {
+		path = "/en" + path
+	}
+	path = strings.TrimRight(path, "/")
+
+	return "https://" + host + path
+}
+
+// onSmoothcomp reports whether raw points to smoothcomp.com or one of its
+// subdomains. Root-relative links count, since they come from its pages.
+func onSmoothcomp(raw string) bool {
+	parsed, err := url.Parse(Clean(raw))
+	if err != nil {
+		return false
+	}
+	if parsed.Host == "" {
+		return parsed.Scheme == "" && strings.HasPrefix(parsed.Path, "/")
+	}
+	return isSmoothcompHost(strings.ToLower(parsed.Hostname()))
+}
+
+func isSmoothcompHost(host string) bool {
+	return host == rootHost || strings.HasSuffix(host, "."+rootHost)
+}
+
+func match(re *regexp.Regexp, raw string) string {
+	m := re.FindStringSubmatch(raw)
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
+}